@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/audit"
+	appconfig "github.com/lewisedginton/general_purpose_chatbot/internal/config"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/server"
+	pkgconfig "github.com/lewisedginton/general_purpose_chatbot/pkg/config"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+const adminUsage = `Usage:
+  chatbot admin delete --app APP [--user USER] [--yes] [--config FILE]
+  chatbot admin export --app APP [--user USER] --out FILE.zip [--include-secrets] [--config FILE]
+
+delete permanently removes stored sessions, archived session events, artifacts, memory
+and documents for a user of an app, or for the whole app when --user is omitted. A user
+is given by their platform ID, e.g. U0123ABCD; their data in every Slack workspace and in
+the threads they took part in is included. Deletions are recorded in the audit log.
+export writes the same data to a zip archive with a manifest.
+`
+
+// adminCommand holds the flags shared by the admin subcommands
+type adminCommand struct {
+	flags      *flag.FlagSet
+	configPath *string
+	appName    *string
+	userID     *string
+}
+
+// newAdminCommand creates the flag set for an admin subcommand
+func newAdminCommand(name string, stderr io.Writer) adminCommand {
+	flags := flag.NewFlagSet("admin "+name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { _, _ = fmt.Fprint(stderr, adminUsage) }
+	return adminCommand{
+		flags:      flags,
+		configPath: flags.String("config", "", "Path to YAML configuration file (optional, env vars override file values)"),
+		appName:    flags.String("app", "", "App whose data is used, e.g. chatbot"),
+		userID:     flags.String("user", "", "User whose data is used; all of the app's users if empty"),
+	}
+}
+
+// parse parses args and loads the configuration, returning a non-zero exit code on failure
+func (c adminCommand) parse(args []string, stderr io.Writer) (*appconfig.AppConfig, logger.Logger, int) {
+	if err := c.flags.Parse(args); err != nil {
+		return nil, nil, 2
+	}
+	if *c.appName == "" {
+		_, _ = fmt.Fprintln(stderr, "--app is required")
+		return nil, nil, 2
+	}
+
+	cfg := &appconfig.AppConfig{}
+	if err := pkgconfig.GetConfig(cfg, *c.configPath, true); err != nil {
+		_, _ = fmt.Fprintf(stderr, "Failed to load configuration: %v\n", err)
+		return nil, nil, 1
+	}
+	log := logger.NewLogger(logger.Config{Level: cfg.GetLogLevel(), Format: cfg.Logging.Format, Service: cfg.ServiceName})
+	return cfg, log, 0
+}
+
+// runAdmin runs an admin subcommand and returns the process exit code
+func runAdmin(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprint(stderr, adminUsage)
+		return 2
+	}
+	switch args[0] {
+	case "delete":
+		return runAdminDelete(args[1:], stdin, stdout, stderr)
+	case "export":
+		return runAdminExport(args[1:], stdout, stderr)
+	default:
+		_, _ = fmt.Fprint(stderr, adminUsage)
+		return 2
+	}
+}
+
+// runAdminDelete deletes a user's or app's stored data after confirmation
+func runAdminDelete(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd := newAdminCommand("delete", stderr)
+	yes := cmd.flags.Bool("yes", false, "Delete without asking for confirmation")
+	cfg, log, code := cmd.parse(args, stderr)
+	if code != 0 {
+		return code
+	}
+
+	// Ask for the user or app to be typed back, so a stray command can't wipe data
+	target := *cmd.userID
+	if target == "" {
+		target = *cmd.appName
+	}
+	if !*yes {
+		scopes, err := server.ResolveUserScopes(context.Background(), cfg, log, *cmd.appName, *cmd.userID)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "Failed to find stored data: %v\n", err)
+			return 1
+		}
+		_, _ = fmt.Fprintf(stdout, "This permanently deletes everything under these prefixes in %s storage:\n", cfg.Storage.Backend)
+		for _, scopeKey := range scopes {
+			for _, prefix := range server.UserDataPrefixes(*cmd.appName, scopeKey) {
+				_, _ = fmt.Fprintf(stdout, "  %s/\n", prefix)
+			}
+		}
+		_, _ = fmt.Fprintf(stdout, "Type %q to confirm: ", target)
+		answer, _ := bufio.NewReader(stdin).ReadString('\n')
+		if strings.TrimSpace(answer) != target {
+			_, _ = fmt.Fprintln(stderr, "Not confirmed, nothing was deleted")
+			return 1
+		}
+	}
+
+	// Deletions are audited like other admin actions, when an audit log is configured
+	var auditLog *audit.Log
+	if cfg.Audit.Path != "" {
+		var err error
+		auditLog, err = audit.Open(cfg.Audit.Path)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "Failed to open audit log: %v\n", err)
+			return 1
+		}
+		defer func() { _ = auditLog.Close() }()
+	}
+
+	deleted, err := server.DeleteUserData(context.Background(), cfg, log, auditLog, cliActor(), *cmd.appName, *cmd.userID)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Deleted %d items before failing: %v\n", deleted, err)
+		return 1
+	}
+	_, _ = fmt.Fprintf(stdout, "Deleted %d items\n", deleted)
+	return 0
+}
+
+// cliActor returns who is running the command, for audit records
+func cliActor() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "cli"
+}
+
+// runAdminExport writes a user's or app's stored data to a zip archive
+func runAdminExport(args []string, stdout, stderr io.Writer) int {
+	cmd := newAdminCommand("export", stderr)
+	out := cmd.flags.String("out", "", "Zip archive to write")
+	includeSecrets := cmd.flags.Bool("include-secrets", false, "Export values that look like credentials or personal data instead of masking them")
+	cfg, log, code := cmd.parse(args, stderr)
+	if code != 0 {
+		return code
+	}
+	if *out == "" {
+		_, _ = fmt.Fprintln(stderr, "--out is required")
+		return 2
+	}
+
+	f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Failed to create archive: %v\n", err)
+		return 1
+	}
+
+	manifest, err := server.ExportUserData(context.Background(), cfg, log, *cmd.appName, *cmd.userID, f, *includeSecrets)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(*out)
+		_, _ = fmt.Fprintf(stderr, "Export failed: %v\n", err)
+		return 1
+	}
+	_, _ = fmt.Fprintf(stdout, "Exported %d files to %s\n", len(manifest.Files), *out)
+	return 0
+}
@@ -2,11 +2,13 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"flag"
 	"fmt"
 	"os"
 
+	"github.com/lewisedginton/general_purpose_chatbot/internal/buildinfo"
 	appconfig "github.com/lewisedginton/general_purpose_chatbot/internal/config"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/server"
 	pkgconfig "github.com/lewisedginton/general_purpose_chatbot/pkg/config"
@@ -14,9 +16,24 @@ import (
 )
 
 func main() {
+	// Admin and preview subcommands run instead of the server
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		os.Exit(runAdmin(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "preview" {
+		os.Exit(runPreview(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "version" {
+		os.Exit(runVersion(os.Stdout))
+	}
+
 	// Parse command line flags
 	configPath := flag.String("config", "", "Path to YAML configuration file (optional, env vars override file values)")
+	showVersion := flag.Bool("version", false, "Print the version and build information and exit")
 	flag.Parse()
+	if *showVersion {
+		os.Exit(runVersion(os.Stdout))
+	}
 
 	// Load configuration from file (if provided) with environment variable overrides
 	cfg := &appconfig.AppConfig{}
@@ -24,18 +41,34 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
+	build := buildinfo.Get()
+	cfg.Version = cmp.Or(cfg.Version, build.Version)
 
 	// Initialize structured logger
 	log := logger.NewLogger(logger.Config{
 		Level:   cfg.GetLogLevel(),
 		Format:  cfg.Logging.Format,
 		Service: cfg.ServiceName,
+		Redaction: logger.RedactionConfig{
+			Disabled:   !cfg.Logging.Redact,
+			Keys:       cfg.Logging.RedactKeys,
+			ScanValues: cfg.Logging.RedactValues,
+		},
+		ErrorDetail: cfg.Logging.ErrorDetail,
 	})
 
+	// Resolve model aliases to canonical IDs, warning about names that look wrong
+	for _, warning := range cfg.ResolveModelAliases() {
+		log.Warn("Suspicious model name", logger.StringField("warning", warning))
+	}
+
 	cfg.LogConfig(log)
 
 	log.Info("Starting Multi-Platform Chatbot",
 		logger.StringField("version", cfg.Version),
+		logger.StringField("commit", build.Commit),
+		logger.StringField("build_date", build.BuildDate),
+		logger.StringField("go_version", build.GoVersion),
 		logger.StringField("llm_provider", cfg.LLM.Provider),
 		logger.StringField("llm_model", cfg.GetLLMModel()))
 
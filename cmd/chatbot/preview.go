@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	appconfig "github.com/lewisedginton/general_purpose_chatbot/internal/config"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/server"
+	pkgconfig "github.com/lewisedginton/general_purpose_chatbot/pkg/config"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+const previewUsage = `Usage:
+  chatbot preview --user USER --session SESSION [--platform slack|telegram] [--channel CHANNEL] [--json] [--config FILE] MESSAGE
+
+preview prints the prompt the agent would send to the model for MESSAGE in a conversation:
+the system prompt, tools and history ending with the message. The model isn't called and
+the conversation isn't changed. USER and SESSION are the IDs the connector stores the
+conversation under.
+`
+
+// runPreview prints the prompt that would be sent to the model for a message and returns
+// the process exit code
+func runPreview(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("preview", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { _, _ = fmt.Fprint(stderr, previewUsage) }
+	configPath := flags.String("config", "", "Path to YAML configuration file (optional, env vars override file values)")
+	platform := flags.String("platform", "", "Connector whose agent is used; the first enabled one if empty")
+	userID := flags.String("user", "", "User the conversation is stored under")
+	sessionID := flags.String("session", "", "Session the conversation is stored under")
+	channelID := flags.String("channel", "", "Channel or chat the message comes from, for routing to named agents")
+	asJSON := flags.Bool("json", false, "Print the preview as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	message := strings.Join(flags.Args(), " ")
+	if *userID == "" || *sessionID == "" || message == "" {
+		_, _ = fmt.Fprint(stderr, previewUsage)
+		return 2
+	}
+
+	cfg := &appconfig.AppConfig{}
+	if err := pkgconfig.GetConfig(cfg, *configPath, true); err != nil {
+		_, _ = fmt.Fprintf(stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	cfg.ResolveModelAliases()
+
+	// Logs go to stderr so stdout holds just the preview
+	log := logger.NewLogger(logger.Config{Level: cfg.GetLogLevel(), Format: cfg.Logging.Format, Service: cfg.ServiceName, Output: stderr})
+	ctx := context.Background()
+	srv, err := server.New(ctx, cfg, log)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Failed to create server: %v\n", err)
+		return 1
+	}
+
+	preview, err := srv.Preview(ctx, *platform, executor.MessageRequest{
+		UserID:    *userID,
+		SessionID: *sessionID,
+		Message:   message,
+		ChannelID: *channelID,
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Preview failed: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(preview); err != nil {
+			_, _ = fmt.Fprintf(stderr, "Failed to write preview: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	_, _ = fmt.Fprint(stdout, preview.Text())
+	return 0
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunPreview_RequiresUserSessionAndMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no message", []string{"--user", "u1", "--session", "s1"}},
+		{"no user", []string{"--session", "s1", "hello"}},
+		{"no session", []string{"--user", "u1", "hello"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := runPreview(tt.args, &stdout, &stderr); code != 2 {
+				t.Errorf("runPreview() = %d, want 2", code)
+			}
+			if !strings.Contains(stderr.String(), "chatbot preview") {
+				t.Errorf("stderr should show the usage, got %q", stderr.String())
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("stdout = %q, want nothing", stdout.String())
+			}
+		})
+	}
+}
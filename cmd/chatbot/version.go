@@ -0,0 +1,14 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/buildinfo"
+)
+
+// runVersion prints the version and build information and returns the process exit code
+func runVersion(stdout io.Writer) int {
+	_, _ = fmt.Fprintf(stdout, "chatbot %s\n", buildinfo.Get())
+	return 0
+}
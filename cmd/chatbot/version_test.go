@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/buildinfo"
+)
+
+// setBuildInfo sets the values injected with -ldflags for the duration of a test
+func setBuildInfo(t *testing.T, version, commit, date string) {
+	t.Helper()
+	oldVersion, oldCommit, oldDate := buildinfo.Version, buildinfo.Commit, buildinfo.Date
+	buildinfo.Version, buildinfo.Commit, buildinfo.Date = version, commit, date
+	t.Cleanup(func() {
+		buildinfo.Version, buildinfo.Commit, buildinfo.Date = oldVersion, oldCommit, oldDate
+	})
+}
+
+func TestRunVersion(t *testing.T) {
+	setBuildInfo(t, "v1.2.0", "3f2c1ab", "2026-01-05T10:00:00Z")
+
+	var stdout bytes.Buffer
+	if code := runVersion(&stdout); code != 0 {
+		t.Fatalf("runVersion() = %d, want 0", code)
+	}
+	if got, want := stdout.String(), "chatbot v1.2.0 (commit 3f2c1ab, built 2026-01-05T10:00:00Z, go"; !strings.HasPrefix(got, want) {
+		t.Errorf("output = %q, want it to start with %q", got, want)
+	}
+}
+
+func TestRunVersion_Unset(t *testing.T) {
+	// Test binaries carry no module version or VCS details to fall back to
+	setBuildInfo(t, "", "", "")
+
+	var stdout bytes.Buffer
+	if code := runVersion(&stdout); code != 0 {
+		t.Fatalf("runVersion() = %d, want 0", code)
+	}
+	if got, want := stdout.String(), "chatbot dev (commit unknown, built unknown, go"; !strings.HasPrefix(got, want) {
+		t.Errorf("output = %q, want it to start with %q", got, want)
+	}
+}
@@ -7,10 +7,13 @@ import (
 	"fmt"
 	"net/http"
 	"os/exec"
+	"strings"
 	"sync"
 
 	"github.com/gorilla/websocket"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/config"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/language"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/httpclient"
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
 	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -18,6 +21,7 @@ import (
 	"google.golang.org/adk/agent/llmagent"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/tool"
+	"google.golang.org/adk/util/instructionutil"
 )
 
 // PromptProvider defines the interface for retrieving prompts.
@@ -26,10 +30,19 @@ type PromptProvider interface {
 	GetSystemPrompt(ctx context.Context) (string, error)
 }
 
-// PlatformSpecificGuidanceProvider defines an interface for platform-specific guidance
+// FormattingProvider defines an interface for platform-specific formatting instructions
+type FormattingProvider interface {
+	// FormattingGuide returns instructions for formatting replies on the platform, added to
+	// the system prompt. A connector without a guide returns "" and the agent's fallback
+	// guide is used instead, so the model isn't left guessing the output format.
+	FormattingGuide() string
+}
+
+// PlatformSpecificGuidanceProvider defines an interface for platform-specific guidance.
+// Connectors implement it so their formatting guide is added to the agent's system prompt.
 type PlatformSpecificGuidanceProvider interface {
-	PlatformName() string    // Name of the platform (e.g., "Slack", "Telegram")
-	FormattingGuide() string // Platform-specific formatting instructions
+	FormattingProvider
+	PlatformName() string // Name of the platform (e.g., "Slack", "Telegram")
 }
 
 // UserInfoProvider defines an interface for providing user context information
@@ -42,8 +55,18 @@ type AgentConfig struct {
 	Name           string         // Agent name (e.g., "slack_assistant", "telegram_assistant")
 	Platform       string         // Platform name for description (e.g., "Slack", "Telegram")
 	Description    string         // Agent description
+	Persona        string         // Optional platform-specific behaviour appended to the system prompt
+	DisplayName    string         // Optional name the agent gives users, e.g. "Support Bot"
+	Intro          string         // Optional introduction the agent gives users
 	Logger         logger.Logger  // Structured logger instance
 	PromptProvider PromptProvider // Provider for system prompts
+	ToolTimeouts   ToolTimeouts   // Per-call tool time limits; zero for no limits
+	AllowedTools   []string       // Optional: names of the only tools the agent may use, MCP tools by prefixed name
+	Model          model.LLM      // Optional: model used instead of the one shared by all agents
+
+	// FormattingGuide is used for connectors without a formatting guide of their own;
+	// DefaultFormattingGuide if empty
+	FormattingGuide string
 }
 
 // UserInfoFunc is a function that returns user information
@@ -53,8 +76,6 @@ type UserInfoFunc func() string
 type AgentFactory func(PlatformSpecificGuidanceProvider, UserInfoFunc) (agent.Agent, error)
 
 // NewChatAgent creates a factory function that returns a new chat agent with model and MCP config.
-//
-//nolint:revive // cognitive-complexity: Factory pattern with platform/user customization requires nested logic
 func NewChatAgent(
 	ctx context.Context,
 	llmModel model.LLM,
@@ -62,11 +83,90 @@ func NewChatAgent(
 	agentConfig AgentConfig,
 	tools []tool.Tool,
 ) (AgentFactory, error) {
-	if agentConfig.Logger == nil {
-		return nil, fmt.Errorf("logger is required in AgentConfig")
+	factories, err := NewChatAgents(ctx, llmModel, mcpConfig, []AgentConfig{agentConfig}, tools)
+	if err != nil {
+		return nil, err
+	}
+	return factories[0], nil
+}
+
+// NewChatAgents creates one agent factory per AgentConfig, e.g. one per connector so each
+// platform gets its own name, description and persona. MCP toolsets are created once and
+// shared by all factories so each MCP server only has a single connection.
+func NewChatAgents(
+	ctx context.Context,
+	llmModel model.LLM,
+	mcpConfig config.MCPConfig,
+	agentConfigs []AgentConfig,
+	tools []tool.Tool,
+) ([]AgentFactory, error) {
+	if err := validateAgentConfigs(agentConfigs); err != nil {
+		return nil, err
+	}
+
+	toolsets := NewMCPToolsets(mcpConfig, nil, agentConfigs[0].Logger)
+	return NewChatAgentsWithToolsets(ctx, llmModel, agentConfigs, tools, toolsets)
+}
+
+// NewChatAgentsWithToolsets creates one agent factory per AgentConfig using already created
+// toolsets, so callers can share MCP toolsets with other components (e.g. agent_info).
+func NewChatAgentsWithToolsets(
+	ctx context.Context,
+	llmModel model.LLM,
+	agentConfigs []AgentConfig,
+	tools []tool.Tool,
+	toolsets []tool.Toolset,
+) ([]AgentFactory, error) {
+	if err := validateAgentConfigs(agentConfigs); err != nil {
+		return nil, err
+	}
+
+	factories := make([]AgentFactory, 0, len(agentConfigs))
+	for _, agentConfig := range agentConfigs {
+		factories = append(factories, newAgentFactory(ctx, llmModel, agentConfig, tools, toolsets))
+	}
+
+	return factories, nil
+}
+
+// validateAgentConfigs checks at least one config is given and each has a logger
+func validateAgentConfigs(agentConfigs []AgentConfig) error {
+	if len(agentConfigs) == 0 {
+		return fmt.Errorf("at least one AgentConfig is required")
+	}
+	for _, agentConfig := range agentConfigs {
+		if agentConfig.Logger == nil {
+			return fmt.Errorf("logger is required in AgentConfig")
+		}
+	}
+	return nil
+}
+
+// NewMCPToolsets creates the MCP toolsets for all enabled servers, or nil if MCP is disabled.
+// Remote servers are reached with client's transport (e.g. one using a proxy); nil uses the default.
+func NewMCPToolsets(mcpConfig config.MCPConfig, client *http.Client, log logger.Logger) []tool.Toolset {
+	if !mcpConfig.Enabled {
+		return nil
 	}
 
-	log := agentConfig.Logger.WithFields(logger.StringField("component", "agent"))
+	log = log.WithFields(logger.StringField("component", "agent"))
+	toolsets := createMCPToolsets(mcpConfig, client, log)
+	log.Info("Successfully created MCP toolsets", logger.IntField("count", len(toolsets)))
+	return toolsets
+}
+
+// newAgentFactory loads the system prompt for agentConfig and returns a factory building agents from it.
+func newAgentFactory(
+	ctx context.Context,
+	llmModel model.LLM,
+	agentConfig AgentConfig,
+	tools []tool.Tool,
+	toolsets []tool.Toolset,
+) AgentFactory {
+	log := agentConfig.Logger.WithFields(
+		logger.StringField("component", "agent"),
+		logger.StringField("agent", agentConfig.Name),
+	)
 
 	// Load agent instructions from prompt provider
 	var instructions string
@@ -85,66 +185,126 @@ func NewChatAgent(
 		instructions = getDefaultInstructions()
 	}
 
-	// Create MCP toolsets if MCP is enabled
-	var toolsets []tool.Toolset
-	if mcpConfig.Enabled {
-		mcpToolsets := createMCPToolsets(mcpConfig, log)
-		log.Info("Successfully created MCP toolsets", logger.IntField("count", len(mcpToolsets)))
-		toolsets = append(toolsets, mcpToolsets...)
+	// Stop a single slow tool from using up the turn
+	tools = withToolTimeouts(tools, agentConfig.ToolTimeouts, log)
+	toolsets = withToolsetTimeouts(toolsets, agentConfig.ToolTimeouts, log)
+	tools, toolsets = withAllowedTools(tools, toolsets, agentConfig.AllowedTools)
+
+	if agentConfig.Model != nil {
+		llmModel = agentConfig.Model
 	}
 
 	// Return a factory function that creates the agent
 	return func(guidanceProvider PlatformSpecificGuidanceProvider, userInfoFunc UserInfoFunc) (agent.Agent, error) {
-		// Start with base instructions
-		agentInstructions := instructions
-
-		// Append platform-specific guidance if provided
-		if guidanceProvider != nil {
-			platformName := guidanceProvider.PlatformName()
-			formattingGuide := guidanceProvider.FormattingGuide()
-
-			if platformName != "" || formattingGuide != "" {
-				platformGuidance := "\n\n## Platform Context\n"
-
-				if platformName != "" {
-					platformGuidance += fmt.Sprintf("This conversation is happening on %s.\n", platformName)
-				}
-
-				if formattingGuide != "" {
-					platformGuidance += "\n" + formattingGuide
-				}
-
-				agentInstructions += platformGuidance
-			}
-		}
-
-		// Append user information if provided
-		if userInfoFunc != nil {
-			userInfo := userInfoFunc()
-			if userInfo != "" {
-				agentInstructions += fmt.Sprintf("\n\n## User Information\n%s", userInfo)
-			}
-		}
+		agentInstructions := buildInstructions(instructions, agentConfig, guidanceProvider, userInfoFunc)
 
 		// Create the LLM agent with tools and MCP toolsets
 		chatAgent, err := llmagent.New(llmagent.Config{
 			Name:        agentConfig.Name,
 			Model:       llmModel,
 			Description: agentConfig.Description,
-			Instruction: agentInstructions,
-			Tools:       tools,
-			Toolsets:    toolsets,
+			InstructionProvider: func(ctx agent.ReadonlyContext) (string, error) {
+				// Keep the {state_key} templating the ADK applies to static instructions
+				inst, err := instructionutil.InjectSessionState(ctx, agentInstructions)
+				if err != nil {
+					return "", err
+				}
+				return inst + languageInstruction(language.FromState(ctx.ReadonlyState())) + channelInstruction(ctx), nil
+			},
+			Tools:    tools,
+			Toolsets: toolsets,
 		})
 		if err != nil {
 			return nil, err
 		}
 
 		return chatAgent, nil
-	}, nil
+	}
+}
+
+// buildInstructions appends the identity, persona, platform guidance and user information to the base instructions.
+func buildInstructions(
+	base string,
+	agentConfig AgentConfig,
+	guidanceProvider PlatformSpecificGuidanceProvider,
+	userInfoFunc UserInfoFunc,
+) string {
+	// Start with base instructions
+	agentInstructions := base
+
+	// Tell the agent the name and introduction users see on this platform
+	var identity []string
+	if agentConfig.DisplayName != "" {
+		identity = append(identity, fmt.Sprintf("Your name is %s; use it when you introduce yourself.", agentConfig.DisplayName))
+	}
+	if agentConfig.Intro != "" {
+		identity = append(identity, fmt.Sprintf("Users are greeted with this introduction of you: %s", agentConfig.Intro))
+	}
+	if len(identity) > 0 {
+		agentInstructions += "\n\n## Identity\n" + strings.Join(identity, "\n")
+	}
+
+	// Append platform-specific persona if configured
+	if agentConfig.Persona != "" {
+		agentInstructions += fmt.Sprintf("\n\n## Persona\n%s", agentConfig.Persona)
+	}
+
+	// Append platform-specific guidance if provided, with the fallback formatting guide for
+	// connectors that have none
+	if guidanceProvider != nil {
+		platformGuidance := "\n\n## Platform Context\n"
+		if platformName := guidanceProvider.PlatformName(); platformName != "" {
+			platformGuidance += fmt.Sprintf("This conversation is happening on %s.\n", platformName)
+		}
+		agentInstructions += platformGuidance + "\n" + formattingGuide(guidanceProvider, agentConfig.FormattingGuide)
+	}
+
+	// Append user information if provided
+	if userInfoFunc != nil {
+		userInfo := userInfoFunc()
+		if userInfo != "" {
+			agentInstructions += fmt.Sprintf("\n\n## User Information\n%s", userInfo)
+		}
+	}
+
+	return agentInstructions
+}
+
+// languageInstruction returns the instruction telling the agent which language to respond in,
+// or "" when the session has no known language
+func languageInstruction(code string) string {
+	name := language.Name(code)
+	if name == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n\n## Language\nRespond in %s unless the user explicitly asks for another language.", name)
+}
+
+// channelContextKey is the context key for the description of the channel a message was sent in
+type channelContextKey struct{}
+
+// WithChannelContext returns a context whose turn gives the agent a description of the
+// channel the message was sent in. It's added to that turn's instructions rather than the
+// message, so it isn't stored in the conversation history.
+func WithChannelContext(ctx context.Context, description string) context.Context {
+	if description == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, channelContextKey{}, description)
+}
+
+// channelInstruction returns the instruction describing the turn's channel, or "" when the
+// context carries none
+func channelInstruction(ctx context.Context) string {
+	description, ok := ctx.Value(channelContextKey{}).(string)
+	if !ok {
+		return ""
+	}
+	return "\n\n## Channel\n" + description
 }
 
 // createMCPToolsets creates MCP toolsets based on configuration
-func createMCPToolsets(mcpConfig config.MCPConfig, log logger.Logger) []tool.Toolset {
+func createMCPToolsets(mcpConfig config.MCPConfig, client *http.Client, log logger.Logger) []tool.Toolset {
 	// Pre-allocate with estimated capacity
 	toolsets := make([]tool.Toolset, 0, len(mcpConfig.Servers))
 
@@ -166,11 +326,11 @@ func createMCPToolsets(mcpConfig config.MCPConfig, log logger.Logger) []tool.Too
 		case "stdio":
 			transport = createStdioTransport(serverConfig)
 		case "sse":
-			transport = createSSETransport(serverConfig, serverName, log)
+			transport = createSSETransport(serverConfig, serverName, client, log)
 		case "http":
-			transport = createHTTPTransport(serverConfig, serverName, log)
+			transport = createHTTPTransport(serverConfig, serverName, client, log)
 		case "websocket":
-			transport = createWebSocketTransport(serverConfig)
+			transport = createWebSocketTransport(serverConfig, client)
 		default:
 			log.Warn("Unsupported transport type",
 				logger.StringField("transport", serverConfig.Transport),
@@ -217,35 +377,41 @@ func createStdioTransport(serverConfig config.MCPServerConfig) mcp.Transport {
 }
 
 // createSSETransport creates SSE transport for MCP servers (2024-11-05 spec)
-func createSSETransport(serverConfig config.MCPServerConfig, serverName string, log logger.Logger) mcp.Transport {
+func createSSETransport(serverConfig config.MCPServerConfig, serverName string, client *http.Client, log logger.Logger) mcp.Transport {
 	return &mcp.SSEClientTransport{
 		Endpoint:   serverConfig.URL,
-		HTTPClient: createHTTPClient(serverConfig, serverName, log),
+		HTTPClient: createHTTPClient(serverConfig, serverName, client, log),
 	}
 }
 
 // createHTTPTransport creates streamable HTTP transport for MCP servers (2025-03-26 spec)
-func createHTTPTransport(serverConfig config.MCPServerConfig, serverName string, log logger.Logger) mcp.Transport {
+func createHTTPTransport(serverConfig config.MCPServerConfig, serverName string, client *http.Client, log logger.Logger) mcp.Transport {
 	return &mcp.StreamableClientTransport{
 		Endpoint:   serverConfig.URL,
-		HTTPClient: createHTTPClient(serverConfig, serverName, log),
+		HTTPClient: createHTTPClient(serverConfig, serverName, client, log),
 	}
 }
 
 // createWebSocketTransport creates WebSocket transport for MCP servers
-func createWebSocketTransport(serverConfig config.MCPServerConfig) mcp.Transport {
+func createWebSocketTransport(serverConfig config.MCPServerConfig, client *http.Client) mcp.Transport {
 	return &webSocketTransport{
 		url:     serverConfig.URL,
 		headers: serverConfig.Headers,
 		auth:    serverConfig.Auth,
+		dialer:  httpclient.WebSocketDialer(client),
 	}
 }
 
-// createHTTPClient creates an HTTP client with authentication and custom headers
-func createHTTPClient(serverConfig config.MCPServerConfig, serverName string, log logger.Logger) *http.Client {
+// createHTTPClient creates an HTTP client with authentication and custom headers, on top of
+// client's transport
+func createHTTPClient(serverConfig config.MCPServerConfig, serverName string, client *http.Client, log logger.Logger) *http.Client {
+	base := http.DefaultTransport
+	if client != nil && client.Transport != nil {
+		base = client.Transport
+	}
 	return &http.Client{
 		Transport: &authTransport{
-			base:       http.DefaultTransport,
+			base:       base,
 			headers:    serverConfig.Headers,
 			auth:       serverConfig.Auth,
 			serverName: serverName,
@@ -307,6 +473,7 @@ type webSocketTransport struct {
 	url     string
 	headers map[string]string
 	auth    *config.MCPAuthConfig
+	dialer  *websocket.Dialer
 }
 
 func (t *webSocketTransport) Connect(ctx context.Context) (mcp.Connection, error) {
@@ -327,8 +494,7 @@ func (t *webSocketTransport) Connect(ctx context.Context) (mcp.Connection, error
 		}
 	}
 
-	dialer := websocket.Dialer{}
-	conn, resp, err := dialer.DialContext(ctx, t.url, headers)
+	conn, resp, err := t.dialer.DialContext(ctx, t.url, headers)
 	if resp != nil && resp.Body != nil {
 		_ = resp.Body.Close()
 	}
@@ -0,0 +1,222 @@
+package agents
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/config"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/httpclient"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// fakeGuidanceProvider is a PlatformSpecificGuidanceProvider with a fixed name and guide
+type fakeGuidanceProvider struct {
+	platform string
+	guide    string
+}
+
+func (f fakeGuidanceProvider) PlatformName() string    { return f.platform }
+func (f fakeGuidanceProvider) FormattingGuide() string { return f.guide }
+
+// fakePromptProvider returns a fixed system prompt
+type fakePromptProvider struct {
+	prompt string
+}
+
+func (f fakePromptProvider) GetSystemPrompt(_ context.Context) (string, error) {
+	return f.prompt, nil
+}
+
+func newTestLogger() logger.Logger {
+	return logger.NewLogger(logger.Config{
+		Level:  logger.DebugLevel,
+		Output: io.Discard,
+	})
+}
+
+var (
+	slackGuidance    = fakeGuidanceProvider{platform: "Slack", guide: "# Slack Formatting Guide\nUse <https://example.com|links>."}
+	telegramGuidance = fakeGuidanceProvider{platform: "Telegram", guide: "# Telegram Formatting Guide\nUse ||spoilers||."}
+)
+
+func TestBuildInstructions_PlatformFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider fakeGuidanceProvider
+		want     string
+		notWant  string
+	}{
+		{name: "slack", provider: slackGuidance, want: "# Slack Formatting Guide", notWant: "Telegram"},
+		{name: "telegram", provider: telegramGuidance, want: "# Telegram Formatting Guide", notWant: "Slack"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildInstructions("base prompt", AgentConfig{Platform: tt.provider.platform}, tt.provider, nil)
+			if !strings.HasPrefix(got, "base prompt") {
+				t.Errorf("instructions should start with base prompt, got %q", got)
+			}
+			if !strings.Contains(got, "This conversation is happening on "+tt.provider.platform+".") {
+				t.Errorf("instructions missing platform context, got %q", got)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("instructions missing %q, got %q", tt.want, got)
+			}
+			if strings.Contains(got, tt.notWant) {
+				t.Errorf("instructions unexpectedly contain %q, got %q", tt.notWant, got)
+			}
+		})
+	}
+}
+
+func TestBuildInstructions_FallbackFormattingGuide(t *testing.T) {
+	newPlatform := fakeGuidanceProvider{platform: "Discord"}
+
+	tests := []struct {
+		name     string
+		config   AgentConfig
+		provider fakeGuidanceProvider
+		want     string
+		notWant  string
+	}{
+		{name: "default guide", provider: newPlatform, want: DefaultFormattingGuide},
+		{name: "configured guide", config: AgentConfig{FormattingGuide: "Reply in plain text."}, provider: newPlatform, want: "Reply in plain text.", notWant: DefaultFormattingGuide},
+		{name: "connector guide wins", config: AgentConfig{FormattingGuide: "Reply in plain text."}, provider: slackGuidance, want: "# Slack Formatting Guide", notWant: "Reply in plain text."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildInstructions("base prompt", tt.config, tt.provider, nil)
+			if !strings.Contains(got, "This conversation is happening on "+tt.provider.platform+".") {
+				t.Errorf("instructions missing platform context, got %q", got)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("instructions missing %q, got %q", tt.want, got)
+			}
+			if tt.notWant != "" && strings.Contains(got, tt.notWant) {
+				t.Errorf("instructions unexpectedly contain %q, got %q", tt.notWant, got)
+			}
+		})
+	}
+}
+
+func TestBuildInstructions_PersonaAndUserInfo(t *testing.T) {
+	agentConfig := AgentConfig{Persona: "Keep answers short and friendly."}
+	userInfo := func() string { return "- Username: alice" }
+
+	got := buildInstructions("base prompt", agentConfig, slackGuidance, userInfo)
+
+	persona := strings.Index(got, "## Persona\nKeep answers short and friendly.")
+	platform := strings.Index(got, "## Platform Context")
+	user := strings.Index(got, "## User Information\n- Username: alice")
+	if persona < 0 || platform < 0 || user < 0 {
+		t.Fatalf("instructions missing a section, got %q", got)
+	}
+	if persona > platform || platform > user {
+		t.Errorf("sections out of order: persona=%d platform=%d user=%d", persona, platform, user)
+	}
+}
+
+func TestBuildInstructions_Identity(t *testing.T) {
+	agentConfig := AgentConfig{DisplayName: "Support Bot", Intro: "I answer billing questions.", Persona: "Be brief."}
+
+	got := buildInstructions("base prompt", agentConfig, nil, nil)
+
+	identity := strings.Index(got, "## Identity\nYour name is Support Bot")
+	persona := strings.Index(got, "## Persona")
+	if identity < 0 || !strings.Contains(got, "I answer billing questions.") {
+		t.Fatalf("instructions missing the identity section, got %q", got)
+	}
+	if identity > persona {
+		t.Errorf("identity should come before the persona, got %q", got)
+	}
+}
+
+func TestBuildInstructions_NoExtras(t *testing.T) {
+	got := buildInstructions("base prompt", AgentConfig{}, nil, func() string { return "" })
+	if got != "base prompt" {
+		t.Errorf("buildInstructions() = %q, want %q", got, "base prompt")
+	}
+}
+
+func TestNewChatAgents_PerPlatformFactories(t *testing.T) {
+	log := newTestLogger()
+	prompts := fakePromptProvider{prompt: "base prompt"}
+
+	factories, err := NewChatAgents(context.Background(), nil, config.MCPConfig{}, []AgentConfig{
+		{Name: "slack_assistant", Platform: "Slack", Description: "Slack bot", Logger: log, PromptProvider: prompts},
+		{Name: "telegram_assistant", Platform: "Telegram", Description: "Telegram bot", Logger: log, PromptProvider: prompts},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewChatAgents() error = %v", err)
+	}
+	if len(factories) != 2 {
+		t.Fatalf("NewChatAgents() returned %d factories, want 2", len(factories))
+	}
+
+	slackAgent, err := factories[0](slackGuidance, nil)
+	if err != nil {
+		t.Fatalf("slack factory error = %v", err)
+	}
+	telegramAgent, err := factories[1](telegramGuidance, nil)
+	if err != nil {
+		t.Fatalf("telegram factory error = %v", err)
+	}
+
+	if slackAgent.Name() != "slack_assistant" || slackAgent.Description() != "Slack bot" {
+		t.Errorf("slack agent = %q (%q), want slack_assistant (Slack bot)", slackAgent.Name(), slackAgent.Description())
+	}
+	if telegramAgent.Name() != "telegram_assistant" || telegramAgent.Description() != "Telegram bot" {
+		t.Errorf("telegram agent = %q (%q), want telegram_assistant (Telegram bot)", telegramAgent.Name(), telegramAgent.Description())
+	}
+}
+
+func TestNewChatAgents_Validation(t *testing.T) {
+	if _, err := NewChatAgents(context.Background(), nil, config.MCPConfig{}, nil, nil); err == nil {
+		t.Error("expected error for empty agent configs")
+	}
+	if _, err := NewChatAgents(context.Background(), nil, config.MCPConfig{}, []AgentConfig{{Name: "a"}}, nil); err == nil {
+		t.Error("expected error for missing logger")
+	}
+}
+
+func TestCreateHTTPClient_UsesSharedTLSConfig(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	// A shared client trusting the server's self-signed certificate, as with OUTBOUND_CA_FILE
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	shared, err := httpclient.New(httpclient.Options{TLSConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}})
+	if err != nil {
+		t.Fatalf("httpclient.New() error = %v", err)
+	}
+
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+	client := createHTTPClient(config.MCPServerConfig{
+		Auth: &config.MCPAuthConfig{Type: "bearer", Token: "secret"},
+	}, "test", shared, log)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v, want the private CA trusted", err)
+	}
+	_ = resp.Body.Close()
+	if gotAuth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want the MCP auth header added", gotAuth)
+	}
+
+	// Without the shared client the certificate isn't trusted
+	if resp, err := createHTTPClient(config.MCPServerConfig{}, "test", nil, log).Get(server.URL); err == nil {
+		_ = resp.Body.Close()
+		t.Error("Get() with the default client succeeded, want a certificate error")
+	}
+}
@@ -0,0 +1,180 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/genai"
+)
+
+// DelegateToolName is the name of the tool agents use to hand tasks to named agents
+const DelegateToolName = "delegate_to_agent"
+
+// delegationAppName is the app name of the throwaway sessions sub-agents run in
+const delegationAppName = "delegation"
+
+// delegationDepthKey is the context key holding how many delegations deep a run is
+type delegationDepthKey struct{}
+
+// Delegation lets an agent hand a self-contained task to a named sub-agent and get its
+// answer back, with the delegate_to_agent tool. Sub-agents run in a throwaway session
+// without the conversation's history; the call and the sub-agent's answer are recorded in
+// the conversation as the tool's call and result. Sub-agents may delegate in turn, up to
+// maxDepth delegations deep.
+type Delegation struct {
+	maxDepth     int
+	descriptions map[string]string // Agent name to description, for the tool description
+	log          logger.Logger
+
+	mutex     sync.RWMutex
+	factories map[string]AgentFactory
+}
+
+// DelegateArgs represents the arguments for the delegate_to_agent tool
+type DelegateArgs struct {
+	Agent string `json:"agent" jsonschema:"Name of the agent to hand the task to."`
+	Task  string `json:"task" jsonschema:"The task for the agent, with all the context it needs: it can't see this conversation."`
+}
+
+// DelegateResult represents the result of the delegate_to_agent tool
+type DelegateResult struct {
+	Success   bool     `json:"success"`
+	Agent     string   `json:"agent,omitempty"`
+	Output    string   `json:"output,omitempty"`
+	ToolCalls []string `json:"tool_calls,omitempty"` // Tools the sub-agent called, in order
+	Message   string   `json:"message,omitempty"`
+}
+
+// NewDelegation creates a Delegation to the agents named in descriptions, whose factories
+// are registered once they're created. maxDepth of 0 or less disables delegation.
+func NewDelegation(maxDepth int, descriptions map[string]string, log logger.Logger) *Delegation {
+	return &Delegation{
+		maxDepth:     maxDepth,
+		descriptions: descriptions,
+		log:          log,
+		factories:    make(map[string]AgentFactory),
+	}
+}
+
+// Register makes an agent available to delegate to. It's separate from NewDelegation because
+// the agents themselves are created with the delegate_to_agent tool.
+func (d *Delegation) Register(name string, factory AgentFactory) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.factories[name] = factory
+}
+
+// Tool returns the delegate_to_agent tool
+func (d *Delegation) Tool() (tool.Tool, error) {
+	names := slices.Sorted(maps.Keys(d.descriptions))
+	agentList := make([]string, 0, len(names))
+	for _, name := range names {
+		if description := d.descriptions[name]; description != "" {
+			agentList = append(agentList, fmt.Sprintf("%s (%s)", name, description))
+		} else {
+			agentList = append(agentList, name)
+		}
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name: DelegateToolName,
+		Description: "Hand a self-contained task to a specialised agent and get its answer back. The agent " +
+			"can't see this conversation, so include everything it needs in the task. Available agents: " +
+			strings.Join(agentList, ", ") + ".",
+	}, func(ctx tool.Context, args DelegateArgs) (DelegateResult, error) {
+		return d.delegate(ctx, ctx.UserID(), args), nil
+	})
+}
+
+// delegate runs the task on the named agent and returns its answer. Problems are reported in
+// the result so the calling agent can carry on without the sub-agent.
+func (d *Delegation) delegate(ctx context.Context, userID string, args DelegateArgs) DelegateResult {
+	depth, _ := ctx.Value(delegationDepthKey{}).(int)
+	if depth >= d.maxDepth {
+		return DelegateResult{Agent: args.Agent, Message: "Delegation limit reached; do the task yourself."}
+	}
+	if strings.TrimSpace(args.Task) == "" {
+		return DelegateResult{Agent: args.Agent, Message: "Give the task to delegate."}
+	}
+
+	d.mutex.RLock()
+	factory, ok := d.factories[args.Agent]
+	d.mutex.RUnlock()
+	if !ok {
+		return DelegateResult{Agent: args.Agent, Message: fmt.Sprintf("Unknown agent %q.", args.Agent)}
+	}
+
+	log := d.log.WithFields(
+		logger.StringField("component", "delegation"),
+		logger.StringField("agent", args.Agent),
+		logger.IntField("depth", depth+1))
+	log.InfoCtx(ctx, "Delegating task to agent")
+
+	output, toolCalls, err := runDelegatedTask(context.WithValue(ctx, delegationDepthKey{}, depth+1), factory, userID, args.Task)
+	if err != nil {
+		log.WarnCtx(ctx, "Delegated task failed", logger.ErrorField(err))
+		return DelegateResult{Agent: args.Agent, ToolCalls: toolCalls, Message: fmt.Sprintf("The agent failed: %v", err)}
+	}
+	return DelegateResult{Success: true, Agent: args.Agent, Output: output, ToolCalls: toolCalls}
+}
+
+// runDelegatedTask runs task on a new agent from factory in a throwaway session, returning
+// the agent's text and the names of the tools it called
+func runDelegatedTask(ctx context.Context, factory AgentFactory, userID, task string) (string, []string, error) {
+	subAgent, err := factory(nil, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create agent: %w", err)
+	}
+
+	sessions := session.InMemoryService()
+	r, err := runner.New(runner.Config{
+		AppName:         delegationAppName,
+		Agent:           subAgent,
+		SessionService:  sessions,
+		ArtifactService: artifact.InMemoryService(),
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create runner: %w", err)
+	}
+	created, err := sessions.Create(ctx, &session.CreateRequest{AppName: delegationAppName, UserID: userID})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	var output strings.Builder
+	var toolCalls []string
+	for event, err := range r.Run(ctx, userID, created.Session.ID(), genai.NewContentFromText(task, genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			return output.String(), toolCalls, err
+		}
+		if event == nil {
+			continue
+		}
+		if event.ErrorMessage != "" {
+			return output.String(), toolCalls, fmt.Errorf("agent error [%s]: %s", event.ErrorCode, event.ErrorMessage)
+		}
+		if event.Content == nil {
+			continue
+		}
+		for _, part := range event.Content.Parts {
+			if part.Text != "" && !part.Thought {
+				output.WriteString(part.Text)
+			}
+			if part.FunctionCall != nil {
+				toolCalls = append(toolCalls, part.FunctionCall.Name)
+			}
+		}
+	}
+	return output.String(), toolCalls, nil
+}
@@ -0,0 +1,198 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+// delegatingModel delegates to target when it has one and answers with answer once it has
+// the delegation's result, or straight away without a target. It counts its calls.
+type delegatingModel struct {
+	target string
+	answer string
+
+	mutex sync.Mutex
+	calls int
+}
+
+func (m *delegatingModel) Name() string { return "fake-model" }
+
+func (m *delegatingModel) callCount() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.calls
+}
+
+func (m *delegatingModel) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	m.mutex.Lock()
+	m.calls++
+	m.mutex.Unlock()
+
+	return func(yield func(*model.LLMResponse, error) bool) {
+		last := req.Contents[len(req.Contents)-1]
+		for _, part := range last.Parts {
+			if part.FunctionResponse != nil {
+				result := part.FunctionResponse.Response
+				text := fmt.Sprintf("%s [%v%v]", m.answer, result["output"], result["message"])
+				yield(&model.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleModel)}, nil)
+				return
+			}
+		}
+		if m.target == "" {
+			yield(&model.LLMResponse{Content: genai.NewContentFromText(m.answer, genai.RoleModel)}, nil)
+			return
+		}
+		yield(&model.LLMResponse{Content: &genai.Content{
+			Role: genai.RoleModel,
+			Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{
+				ID:   "call_1",
+				Name: DelegateToolName,
+				Args: map[string]any{"agent": m.target, "task": "find the answer"},
+			}}},
+		}}, nil)
+	}
+}
+
+// runDelegationTest runs a parent agent that delegates to a "researcher" agent whose model
+// is researcherModel, returning the parent's answer and its session's events
+func runDelegationTest(t *testing.T, maxDepth int, researcherModel *delegatingModel) (string, []*session.Event) {
+	t.Helper()
+	log := newTestLogger()
+	ctx := context.Background()
+
+	delegation := NewDelegation(maxDepth, map[string]string{"researcher": "Looks things up"}, log)
+	delegateTool, err := delegation.Tool()
+	if err != nil {
+		t.Fatalf("Tool() error = %v", err)
+	}
+	tools := []tool.Tool{delegateTool}
+
+	researcher, err := NewChatAgentsWithToolsets(ctx, researcherModel, []AgentConfig{{Name: "researcher", Logger: log}}, tools, nil)
+	if err != nil {
+		t.Fatalf("NewChatAgentsWithToolsets(researcher) error = %v", err)
+	}
+	delegation.Register("researcher", researcher[0])
+
+	parentModel := &delegatingModel{target: "researcher", answer: "parent says"}
+	parent, err := NewChatAgentsWithToolsets(ctx, parentModel, []AgentConfig{{Name: "parent", Logger: log}}, tools, nil)
+	if err != nil {
+		t.Fatalf("NewChatAgentsWithToolsets(parent) error = %v", err)
+	}
+	parentAgent, err := parent[0](nil, nil)
+	if err != nil {
+		t.Fatalf("parent factory error = %v", err)
+	}
+
+	sessions := session.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "test", Agent: parentAgent, SessionService: sessions, ArtifactService: artifact.InMemoryService()})
+	if err != nil {
+		t.Fatalf("runner.New() error = %v", err)
+	}
+	created, err := sessions.Create(ctx, &session.CreateRequest{AppName: "test", UserID: "u1"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	var answer strings.Builder
+	for event, err := range r.Run(ctx, "u1", created.Session.ID(), genai.NewContentFromText("question", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if event.Content != nil {
+			for _, part := range event.Content.Parts {
+				answer.WriteString(part.Text)
+			}
+		}
+	}
+
+	stored, err := sessions.Get(ctx, &session.GetRequest{AppName: "test", UserID: "u1", SessionID: created.Session.ID()})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	var events []*session.Event
+	for event := range stored.Session.Events().All() {
+		events = append(events, event)
+	}
+	return answer.String(), events
+}
+
+func TestDelegation_RunsSubAgentAndReturnsItsOutput(t *testing.T) {
+	researcherModel := &delegatingModel{answer: "the answer is 42"}
+
+	answer, events := runDelegationTest(t, 1, researcherModel)
+
+	if got := researcherModel.callCount(); got != 1 {
+		t.Errorf("researcher model called %d times, want 1", got)
+	}
+	if want := "parent says [the answer is 42"; !strings.Contains(answer, want) {
+		t.Errorf("parent answer = %q, want it to contain %q", answer, want)
+	}
+
+	// The delegation is recorded in the parent's session as the tool's call and result
+	var recordedCall, recordedResult bool
+	for _, event := range events {
+		if event.Content == nil {
+			continue
+		}
+		for _, part := range event.Content.Parts {
+			if part.FunctionCall != nil && part.FunctionCall.Name == DelegateToolName && part.FunctionCall.Args["agent"] == "researcher" {
+				recordedCall = true
+			}
+			if part.FunctionResponse != nil && part.FunctionResponse.Name == DelegateToolName && part.FunctionResponse.Response["output"] == "the answer is 42" {
+				recordedResult = true
+			}
+		}
+	}
+	if !recordedCall || !recordedResult {
+		t.Errorf("session events record call = %v, result = %v; want both", recordedCall, recordedResult)
+	}
+}
+
+func TestDelegation_RespectsDepthLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxDepth  int
+		wantCalls int // Researcher model calls: two per level it runs at
+	}{
+		{name: "depth 1", maxDepth: 1, wantCalls: 2},
+		{name: "depth 2", maxDepth: 2, wantCalls: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The researcher always tries to delegate to itself
+			researcherModel := &delegatingModel{target: "researcher", answer: "researched"}
+
+			answer, _ := runDelegationTest(t, tt.maxDepth, researcherModel)
+
+			if got := researcherModel.callCount(); got != tt.wantCalls {
+				t.Errorf("researcher model called %d times, want %d", got, tt.wantCalls)
+			}
+			if !strings.Contains(answer, "Delegation limit reached") {
+				t.Errorf("parent answer = %q, want the depth limit reported through the sub-agent", answer)
+			}
+		})
+	}
+}
+
+func TestDelegation_UnknownAgent(t *testing.T) {
+	delegation := NewDelegation(1, nil, newTestLogger())
+
+	result := delegation.delegate(context.Background(), "u1", DelegateArgs{Agent: "missing", Task: "anything"})
+
+	if result.Success || !strings.Contains(result.Message, "Unknown agent") {
+		t.Errorf("delegate() = %+v, want an unknown agent failure", result)
+	}
+}
@@ -0,0 +1,4 @@
+package agents
+
+// BuildInstructions exposes buildInstructions to the external agents_test package.
+var BuildInstructions = buildInstructions
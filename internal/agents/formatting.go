@@ -0,0 +1,28 @@
+package agents
+
+// DefaultFormattingGuide is added to the system prompt for connectors whose platform has no
+// formatting guide of its own. Plain Markdown renders acceptably on most chat platforms and
+// stays readable where it isn't rendered at all.
+const DefaultFormattingGuide = `# Formatting Guide
+
+Format replies in standard Markdown, kept simple so they read well whether or not the
+platform renders it:
+- **Bold** for emphasis and *italics* sparingly
+- Bulleted or numbered lists for steps and options, with a blank line before each list
+- ` + "`inline code`" + ` for commands, file names and identifiers
+- Fenced code blocks with a language tag for multi-line code
+- Links as [text](https://example.com)
+
+Avoid tables, HTML and nested lists more than one level deep, which many chat clients
+can't display.`
+
+// formattingGuide returns the connector's formatting guide, or fallback if it has none
+func formattingGuide(provider FormattingProvider, fallback string) string {
+	if guide := provider.FormattingGuide(); guide != "" {
+		return guide
+	}
+	if fallback != "" {
+		return fallback
+	}
+	return DefaultFormattingGuide
+}
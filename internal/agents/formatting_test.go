@@ -0,0 +1,42 @@
+package agents_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/slack"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/telegram"
+)
+
+func TestBuildInstructions_ConnectorFormattingGuides(t *testing.T) {
+	slackConnector := &slack.Connector{}
+	telegramConnector := &telegram.Connector{}
+
+	slackPrompt := agents.BuildInstructions("base prompt", agents.AgentConfig{}, slackConnector, nil)
+	telegramPrompt := agents.BuildInstructions("base prompt", agents.AgentConfig{}, telegramConnector, nil)
+
+	if !strings.Contains(slackPrompt, slackConnector.FormattingGuide()) {
+		t.Error("Slack prompt is missing the Slack formatting guide")
+	}
+	if !strings.Contains(slackPrompt, "This conversation is happening on Slack.") {
+		t.Error("Slack prompt is missing the Slack platform context")
+	}
+	if strings.Contains(slackPrompt, "Telegram Formatting Guide") {
+		t.Error("Slack prompt unexpectedly contains the Telegram formatting guide")
+	}
+
+	if !strings.Contains(telegramPrompt, telegramConnector.FormattingGuide()) {
+		t.Error("Telegram prompt is missing the Telegram formatting guide")
+	}
+	if strings.Contains(telegramPrompt, "Slack Formatting Guide") {
+		t.Error("Telegram prompt unexpectedly contains the Slack formatting guide")
+	}
+}
+
+func TestBuildInstructions_NoGuidanceProvider(t *testing.T) {
+	got := agents.BuildInstructions("base prompt", agents.AgentConfig{}, nil, nil)
+	if strings.Contains(got, "Formatting Guide") || strings.Contains(got, "## Platform Context") {
+		t.Errorf("prompt without a guidance provider should have no formatting guide, got %q", got)
+	}
+}
@@ -146,8 +146,8 @@ func (t *prefixedTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) e
 }
 
 // packTool adds a tool to the LLM request.
-// This is based on toolutils.PackTool from the ADK but works with our prefixed tool.
-func packTool(req *model.LLMRequest, t *prefixedTool) error {
+// This is based on toolutils.PackTool from the ADK but works with our wrapper tools.
+func packTool(req *model.LLMRequest, t functionTool) error {
 	if req.Tools == nil {
 		req.Tools = make(map[string]any)
 	}
@@ -74,13 +74,22 @@ func (l *testLogger) Info(_ string, _ ...logger.LogField)  {}
 func (l *testLogger) Warn(msg string, _ ...logger.LogField) {
 	l.warnMessages = append(l.warnMessages, msg)
 }
-func (l *testLogger) Error(_ string, _ ...logger.LogField) {}
+func (l *testLogger) Error(_ string, _ ...logger.LogField)                       {}
+func (l *testLogger) InfoCtx(_ context.Context, _ string, _ ...logger.LogField)  {}
+func (l *testLogger) ErrorCtx(_ context.Context, _ string, _ ...logger.LogField) {}
+func (l *testLogger) DebugCtx(_ context.Context, _ string, _ ...logger.LogField) {}
+func (l *testLogger) WarnCtx(_ context.Context, msg string, fields ...logger.LogField) {
+	l.Warn(msg, fields...)
+}
 func (l *testLogger) WithFields(_ ...logger.LogField) logger.Logger {
 	return l
 }
 func (l *testLogger) WithCorrelationID(_ string) logger.Logger {
 	return l
 }
+func (l *testLogger) WithError(_ error) logger.Logger {
+	return l
+}
 func (l *testLogger) GrpcRequestsInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	return handler(ctx, req)
 }
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/lewisedginton/general_purpose_chatbot/internal/buildinfo"
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/adk/agent"
@@ -40,7 +41,7 @@ type mcpToolset struct {
 func newMCPToolset(transport mcp.Transport, log logger.Logger) *mcpToolset {
 	return &mcpToolset{
 		transport: transport,
-		client:    mcp.NewClient(&mcp.Implementation{Name: "provo-mcp-client", Version: "1.0.0"}, nil),
+		client:    mcp.NewClient(&mcp.Implementation{Name: "provo-mcp-client", Version: buildinfo.Get().Version}, nil),
 		log:       log,
 	}
 }
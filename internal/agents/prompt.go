@@ -1,5 +1,7 @@
 package agents
 
+import "context"
+
 // getDefaultInstructions returns fallback instructions if system prompt is not available
 func getDefaultInstructions() string {
 	return `You are a helpful AI assistant.
@@ -16,3 +18,11 @@ Ask clarifying questions when you need more context.
 
 Note: No system prompt configured. Configure a prompt provider to customize these instructions.`
 }
+
+// StaticPrompt is a PromptProvider returning a fixed system prompt, e.g. one set in config
+type StaticPrompt string
+
+// GetSystemPrompt returns the prompt
+func (p StaticPrompt) GetSystemPrompt(context.Context) (string, error) {
+	return string(p), nil
+}
@@ -0,0 +1,28 @@
+package agents
+
+import (
+	"slices"
+
+	"google.golang.org/adk/tool"
+)
+
+// withAllowedTools returns the tools, and toolsets limited to the tools, named in allowed.
+// An empty allowed list keeps everything.
+func withAllowedTools(tools []tool.Tool, toolsets []tool.Toolset, allowed []string) ([]tool.Tool, []tool.Toolset) {
+	if len(allowed) == 0 {
+		return tools, toolsets
+	}
+
+	var filteredTools []tool.Tool
+	for _, t := range tools {
+		if slices.Contains(allowed, t.Name()) {
+			filteredTools = append(filteredTools, t)
+		}
+	}
+
+	filteredToolsets := make([]tool.Toolset, len(toolsets))
+	for i, ts := range toolsets {
+		filteredToolsets[i] = tool.FilterToolset(ts, tool.StringPredicate(allowed))
+	}
+	return filteredTools, filteredToolsets
+}
@@ -0,0 +1,286 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+// errToolTimedOut is returned to a timed-out tool that goes on using its tool context
+var errToolTimedOut = errors.New("tool call timed out")
+
+// ToolTimeouts limits how long a single tool call may run, so one slow tool can't use up
+// the whole turn. A call that times out returns an error result and the model carries on.
+type ToolTimeouts struct {
+	Default   time.Duration            // Limit for tools without an override; 0 for no limit
+	Overrides map[string]time.Duration // Limits by tool name (MCP tools by prefixed name); 0 for no limit
+}
+
+// For returns the timeout for the named tool, or 0 if it has no limit
+func (t ToolTimeouts) For(name string) time.Duration {
+	if timeout, ok := t.Overrides[name]; ok {
+		return timeout
+	}
+	return t.Default
+}
+
+// enabled reports whether any tool has a limit
+func (t ToolTimeouts) enabled() bool {
+	if t.Default > 0 {
+		return true
+	}
+	for _, timeout := range t.Overrides {
+		if timeout > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// functionTool is the subset of the ADK's internal function tool interface a tool needs for
+// the model to call it
+type functionTool interface {
+	tool.Tool
+	Declaration() *genai.FunctionDeclaration
+	Run(ctx tool.Context, args any) (map[string]any, error)
+}
+
+// withToolTimeouts wraps each function tool that has a limit so its calls time out
+func withToolTimeouts(tools []tool.Tool, timeouts ToolTimeouts, log logger.Logger) []tool.Tool {
+	if !timeouts.enabled() {
+		return tools
+	}
+
+	wrapped := make([]tool.Tool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = newTimeoutTool(t, timeouts.For(t.Name()), log)
+	}
+	return wrapped
+}
+
+// withToolsetTimeouts wraps each toolset so the tools it lists time out
+func withToolsetTimeouts(toolsets []tool.Toolset, timeouts ToolTimeouts, log logger.Logger) []tool.Toolset {
+	if !timeouts.enabled() {
+		return toolsets
+	}
+
+	wrapped := make([]tool.Toolset, len(toolsets))
+	for i, ts := range toolsets {
+		wrapped[i] = &timeoutToolset{inner: ts, timeouts: timeouts, log: log}
+	}
+	return wrapped
+}
+
+// timeoutToolset wraps the tools of a toolset (e.g. an MCP server) with their timeouts
+type timeoutToolset struct {
+	inner    tool.Toolset
+	timeouts ToolTimeouts
+	log      logger.Logger
+}
+
+// Name returns the name of the wrapped toolset
+func (s *timeoutToolset) Name() string {
+	return s.inner.Name()
+}
+
+// Tools returns the wrapped toolset's tools with their timeouts applied
+func (s *timeoutToolset) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	tools, err := s.inner.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return withToolTimeouts(tools, s.timeouts, s.log), nil
+}
+
+// timeoutTool runs a function tool with a deadline
+type timeoutTool struct {
+	functionTool
+	timeout time.Duration
+	log     logger.Logger
+}
+
+// newTimeoutTool wraps t so its calls time out after timeout. Tools without a limit and
+// tools the model can't call directly are returned unchanged.
+func newTimeoutTool(t tool.Tool, timeout time.Duration, log logger.Logger) tool.Tool {
+	fn, ok := t.(functionTool)
+	if !ok || timeout <= 0 {
+		return t
+	}
+	return &timeoutTool{functionTool: fn, timeout: timeout, log: log}
+}
+
+// Run calls the wrapped tool, returning an error result if it doesn't finish in time. The
+// tool's context is cancelled at the deadline; a tool that ignores it is left to finish in
+// the background, cut off from the turn, and its result discarded.
+func (t *timeoutTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	toolCtx := &timeoutContext{Context: ctx, ctx: timeoutCtx}
+
+	type result struct {
+		response map[string]any
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		response, err := t.functionTool.Run(toolCtx, args)
+		done <- result{response: response, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.response, r.err
+	case <-timeoutCtx.Done():
+		toolCtx.expire()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		t.log.Warn("Tool call timed out",
+			logger.StringField("tool", t.Name()),
+			logger.DurationField("timeout", t.timeout))
+		return nil, fmt.Errorf("tool %s timed out after %s; continue without its result", t.Name(), t.timeout)
+	}
+}
+
+// ProcessRequest adds the tool to the LLM request, registering the wrapper so the ADK
+// calls it rather than the wrapped tool
+func (t *timeoutTool) ProcessRequest(_ tool.Context, req *model.LLMRequest) error {
+	return packTool(req, t)
+}
+
+// timeoutContext is a tool context whose cancellation comes from a derived context with a
+// deadline, so tools that honour their context stop when the timeout fires. Once the call
+// has timed out it stops passing state, artifact and confirmation changes through to the
+// turn's context, which the ADK goes on using, so a tool left running can't change the turn.
+type timeoutContext struct {
+	tool.Context
+	ctx context.Context
+
+	mutex   sync.RWMutex
+	expired bool
+}
+
+func (c *timeoutContext) Deadline() (time.Time, bool) { return c.ctx.Deadline() }
+func (c *timeoutContext) Done() <-chan struct{}       { return c.ctx.Done() }
+func (c *timeoutContext) Err() error                  { return c.ctx.Err() }
+func (c *timeoutContext) Value(key any) any           { return c.ctx.Value(key) }
+
+// expire cuts the context off from the turn, waiting for any call already using it
+func (c *timeoutContext) expire() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.expired = true
+}
+
+// live runs fn against the turn's context, or returns errToolTimedOut once it has expired
+func (c *timeoutContext) live(fn func() error) error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if c.expired {
+		return errToolTimedOut
+	}
+	return fn()
+}
+
+// Actions returns the turn's event actions, or throwaway ones once the call has timed out
+func (c *timeoutContext) Actions() *session.EventActions {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if c.expired {
+		return &session.EventActions{StateDelta: map[string]any{}, ArtifactDelta: map[string]int64{}}
+	}
+	return c.Context.Actions()
+}
+
+func (c *timeoutContext) State() session.State       { return timeoutState{ctx: c} }
+func (c *timeoutContext) Artifacts() agent.Artifacts { return timeoutArtifacts{ctx: c} }
+
+func (c *timeoutContext) RequestConfirmation(hint string, payload any) error {
+	return c.live(func() error { return c.Context.RequestConfirmation(hint, payload) })
+}
+
+// timeoutState is the session state of a timeoutContext
+type timeoutState struct {
+	ctx *timeoutContext
+}
+
+func (s timeoutState) Get(key string) (any, error) {
+	var value any
+	err := s.ctx.live(func() error {
+		var err error
+		value, err = s.ctx.Context.State().Get(key)
+		return err
+	})
+	return value, err
+}
+
+func (s timeoutState) Set(key string, value any) error {
+	return s.ctx.live(func() error { return s.ctx.Context.State().Set(key, value) })
+}
+
+// All copies the state before yielding it, so the loop body can use the state too
+func (s timeoutState) All() iter.Seq2[string, any] {
+	var state map[string]any
+	_ = s.ctx.live(func() error {
+		state = maps.Collect(s.ctx.Context.State().All())
+		return nil
+	})
+	return maps.All(state)
+}
+
+// timeoutArtifacts is the artifact store of a timeoutContext
+type timeoutArtifacts struct {
+	ctx *timeoutContext
+}
+
+func (a timeoutArtifacts) Save(ctx context.Context, name string, data *genai.Part) (*artifact.SaveResponse, error) {
+	var resp *artifact.SaveResponse
+	err := a.ctx.live(func() error {
+		var err error
+		resp, err = a.ctx.Context.Artifacts().Save(ctx, name, data)
+		return err
+	})
+	return resp, err
+}
+
+func (a timeoutArtifacts) List(ctx context.Context) (*artifact.ListResponse, error) {
+	var resp *artifact.ListResponse
+	err := a.ctx.live(func() error {
+		var err error
+		resp, err = a.ctx.Context.Artifacts().List(ctx)
+		return err
+	})
+	return resp, err
+}
+
+func (a timeoutArtifacts) Load(ctx context.Context, name string) (*artifact.LoadResponse, error) {
+	var resp *artifact.LoadResponse
+	err := a.ctx.live(func() error {
+		var err error
+		resp, err = a.ctx.Context.Artifacts().Load(ctx, name)
+		return err
+	})
+	return resp, err
+}
+
+func (a timeoutArtifacts) LoadVersion(ctx context.Context, name string, version int) (*artifact.LoadResponse, error) {
+	var resp *artifact.LoadResponse
+	err := a.ctx.live(func() error {
+		var err error
+		resp, err = a.ctx.Context.Artifacts().LoadVersion(ctx, name, version)
+		return err
+	})
+	return resp, err
+}
@@ -0,0 +1,182 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/genai"
+)
+
+// slowToolCallingModel calls slow_tool once, then answers with the tool's response recorded
+type slowToolCallingModel struct {
+	toolResponse map[string]any
+}
+
+func (m *slowToolCallingModel) Name() string { return "fake-model" }
+
+func (m *slowToolCallingModel) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		last := req.Contents[len(req.Contents)-1]
+		for _, part := range last.Parts {
+			if part.FunctionResponse != nil {
+				m.toolResponse = part.FunctionResponse.Response
+				yield(&model.LLMResponse{Content: genai.NewContentFromText("answered without the tool", genai.RoleModel)}, nil)
+				return
+			}
+		}
+		yield(&model.LLMResponse{Content: &genai.Content{
+			Role:  genai.RoleModel,
+			Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{ID: "call_1", Name: "slow_tool", Args: map[string]any{}}}},
+		}}, nil)
+	}
+}
+
+type slowToolArgs struct{}
+
+// runSlowToolTest runs a turn in which the model calls slowTool with a 50ms timeout,
+// returning the answer, the model and the session the turn ran in
+func runSlowToolTest(t *testing.T, slowTool tool.Tool) (string, *slowToolCallingModel, session.Session) {
+	t.Helper()
+	ctx := context.Background()
+
+	llm := &slowToolCallingModel{}
+	factories, err := NewChatAgentsWithToolsets(ctx, llm, []AgentConfig{{
+		Name:   "test_agent",
+		Logger: newTestLogger(),
+		ToolTimeouts: ToolTimeouts{
+			Default:   time.Minute,
+			Overrides: map[string]time.Duration{"slow_tool": 50 * time.Millisecond},
+		},
+	}}, []tool.Tool{slowTool}, nil)
+	if err != nil {
+		t.Fatalf("NewChatAgentsWithToolsets() error = %v", err)
+	}
+	chatAgent, err := factories[0](nil, nil)
+	if err != nil {
+		t.Fatalf("agent factory error = %v", err)
+	}
+
+	sessions := session.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "test", Agent: chatAgent, SessionService: sessions, ArtifactService: artifact.InMemoryService()})
+	if err != nil {
+		t.Fatalf("runner.New() error = %v", err)
+	}
+	created, err := sessions.Create(ctx, &session.CreateRequest{AppName: "test", UserID: "u1"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	var answer strings.Builder
+	for event, err := range r.Run(ctx, "u1", created.Session.ID(), genai.NewContentFromText("use the slow tool", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if event.Content != nil {
+			for _, part := range event.Content.Parts {
+				answer.WriteString(part.Text)
+			}
+		}
+	}
+
+	stored, err := sessions.Get(ctx, &session.GetRequest{AppName: "test", UserID: "u1", SessionID: created.Session.ID()})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	return answer.String(), llm, stored.Session
+}
+
+func TestToolTimeout_LetsTurnContinue(t *testing.T) {
+	cancelled := make(chan struct{})
+	slowTool, err := functiontool.New(functiontool.Config{Name: "slow_tool", Description: "never finishes in time"},
+		func(ctx tool.Context, _ slowToolArgs) (map[string]any, error) {
+			select {
+			case <-ctx.Done():
+				close(cancelled)
+				return nil, ctx.Err()
+			case <-time.After(5 * time.Second):
+				return map[string]any{"result": "too late"}, nil
+			}
+		})
+	if err != nil {
+		t.Fatalf("functiontool.New() error = %v", err)
+	}
+
+	start := time.Now()
+	answer, llm, _ := runSlowToolTest(t, slowTool)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("turn took %s, want the tool abandoned after its 50ms timeout", elapsed)
+	}
+
+	if answer != "answered without the tool" {
+		t.Errorf("answer = %q, want the model's answer after the timeout", answer)
+	}
+	errText, _ := llm.toolResponse["error"].(string)
+	if !strings.Contains(errText, "slow_tool timed out after 50ms") {
+		t.Errorf("tool response = %v, want a timeout error", llm.toolResponse)
+	}
+	// The abandoned call sees its context cancelled
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("tool context wasn't cancelled at the timeout")
+	}
+}
+
+func TestToolTimeout_CutsAbandonedToolOffFromTurn(t *testing.T) {
+	release := make(chan struct{})
+	setErr := make(chan error, 1)
+	slowTool, err := functiontool.New(functiontool.Config{Name: "slow_tool", Description: "ignores its context"},
+		func(ctx tool.Context, _ slowToolArgs) (map[string]any, error) {
+			<-release
+			setErr <- ctx.State().Set("late", "value")
+			ctx.Actions().StateDelta["late_delta"] = "value"
+			return map[string]any{"result": "too late"}, nil
+		})
+	if err != nil {
+		t.Fatalf("functiontool.New() error = %v", err)
+	}
+
+	_, _, sess := runSlowToolTest(t, slowTool)
+	close(release)
+
+	select {
+	case err := <-setErr:
+		if !errors.Is(err, errToolTimedOut) {
+			t.Errorf("State().Set() after the timeout error = %v, want errToolTimedOut", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("abandoned tool didn't finish")
+	}
+	for key := range sess.State().All() {
+		t.Errorf("session state has %q, want no changes from the abandoned tool", key)
+	}
+}
+
+func TestToolTimeouts_For(t *testing.T) {
+	timeouts := ToolTimeouts{
+		Default:   30 * time.Second,
+		Overrides: map[string]time.Duration{"web_search": 10 * time.Second, "run_command": 0},
+	}
+
+	tests := map[string]time.Duration{
+		"web_search":   10 * time.Second,
+		"run_command":  0,
+		"http_request": 30 * time.Second,
+	}
+	for name, want := range tests {
+		if got := timeouts.For(name); got != want {
+			t.Errorf("For(%q) = %s, want %s", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Package analytics records how users respond to the bot, such as thumbs-up/down feedback on
+// its replies, for evaluating answer quality, and the size of each model call, for tuning
+// history windows and budgets. Events go to their own sink as JSON lines, separate from the
+// application log.
+package analytics
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/jsonl"
+)
+
+// Event types
+const (
+	EventFeedback   = "feedback"    // A user rated a reply
+	EventModelUsage = "model_usage" // A model call completed
+)
+
+// Feedback ratings
+const (
+	RatingPositive = "positive"
+	RatingNegative = "negative"
+)
+
+// Event is one analytics event, written as a line of JSON
+type Event struct {
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	Platform  string    `json:"platform,omitempty"` // slack or telegram
+	UserID    string    `json:"user_id,omitempty"`  // Platform user ID of the user who acted
+	SessionID string    `json:"session_id,omitempty"`
+	// MessageRef identifies the bot message the event is about, e.g. a Slack channel and
+	// timestamp or a Telegram chat and message ID
+	MessageRef string `json:"message_ref,omitempty"`
+	Rating     string `json:"rating,omitempty"` // For feedback: positive or negative
+
+	// For model usage: the model called, the tokens the provider reported and the prompt size
+	// estimated before the call, and whether history or the reply was truncated
+	Provider              string `json:"provider,omitempty"`
+	Model                 string `json:"model,omitempty"`
+	PromptTokens          int    `json:"prompt_tokens,omitempty"`
+	CompletionTokens      int    `json:"completion_tokens,omitempty"`
+	EstimatedPromptTokens int    `json:"estimated_prompt_tokens,omitempty"`
+	TruncatedMessages     int    `json:"truncated_messages,omitempty"`
+	MaxTokensReached      bool   `json:"max_tokens_reached,omitempty"`
+}
+
+// Log appends analytics events to a sink. A nil *Log discards events, so analytics can be
+// left unconfigured without callers checking.
+type Log struct {
+	sink *jsonl.Sink
+	now  func() time.Time
+}
+
+// New creates an analytics log writing JSON lines to w
+func New(w io.Writer) *Log {
+	return &Log{sink: jsonl.New(w), now: time.Now}
+}
+
+// Open creates an analytics log appending to the file at path, creating it if needed
+func Open(path string) (*Log, error) {
+	sink, err := jsonl.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open analytics log: %w", err)
+	}
+	return &Log{sink: sink, now: time.Now}, nil
+}
+
+// Record writes e, stamping it with the current time if Time is unset
+func (l *Log) Record(e Event) error {
+	if l == nil {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = l.now().UTC()
+	}
+	if err := l.sink.Write(e); err != nil {
+		return fmt.Errorf("failed to write analytics event: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying file, if the log owns one
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.sink.Close()
+}
@@ -0,0 +1,57 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpen_AppendsEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analytics.log")
+
+	for _, rating := range []string{RatingPositive, RatingNegative} {
+		l, err := Open(path)
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		if err := l.Record(Event{Type: EventFeedback, Platform: "slack", UserID: "U1", Rating: rating}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+		if err := l.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var events []Event
+	for dec.More() {
+		var e Event
+		if err := dec.Decode(&e); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		events = append(events, e)
+	}
+
+	if len(events) != 2 || events[0].Rating != RatingPositive || events[1].Rating != RatingNegative {
+		t.Fatalf("events = %+v, want both ratings in order", events)
+	}
+	if events[0].Time.IsZero() {
+		t.Error("event has no timestamp")
+	}
+}
+
+func TestRecord_NilLogDiscards(t *testing.T) {
+	var l *Log
+	if err := l.Record(Event{Type: EventFeedback}); err != nil {
+		t.Errorf("Record() on nil log error = %v", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("Close() on nil log error = %v", err)
+	}
+}
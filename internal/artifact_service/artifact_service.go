@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"path"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -27,17 +28,19 @@ type ArtifactService struct {
 
 // ArtifactMetadata stores metadata about an artifact's versions.
 type ArtifactMetadata struct {
-	FileName       string    `json:"file_name"`
-	CurrentVersion int64     `json:"current_version"`
-	Versions       []int64   `json:"versions"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	FileName       string           `json:"file_name"`
+	CurrentVersion int64            `json:"current_version"`
+	Versions       []int64          `json:"versions"`
+	MIMETypes      map[int64]string `json:"mime_types,omitempty"` // Detected content type of each version
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
 }
 
 // VersionedArtifact stores the actual artifact content for a specific version.
 type VersionedArtifact struct {
 	Version   int64       `json:"version"`
 	Part      *genai.Part `json:"part"`
+	MIMEType  string      `json:"mime_type,omitempty"`
 	CreatedAt time.Time   `json:"created_at"`
 }
 
@@ -71,8 +74,9 @@ func (s *ArtifactService) Save(ctx context.Context, req *artifact.SaveRequest) (
 	basePath := s.getArtifactBasePath(req.AppName, req.UserID, req.SessionID, req.FileName)
 	metadataPath := path.Join(basePath, "metadata.json")
 
-	// Load or create metadata
-	metadata, err := s.loadMetadata(ctx, metadataPath)
+	// Load or create metadata (rebuilding it from the version files if it was lost,
+	// so a new save doesn't overwrite existing versions)
+	metadata, err := s.loadOrRebuildMetadata(ctx, basePath)
 	if err != nil {
 		// Create new metadata if it doesn't exist
 		metadata = &ArtifactMetadata{
@@ -91,10 +95,12 @@ func (s *ArtifactService) Save(ctx context.Context, req *artifact.SaveRequest) (
 		version = metadata.CurrentVersion + 1
 	}
 
-	// Create versioned artifact
+	// Create versioned artifact, recording its content type so it can be served correctly later
+	mimeType := detectMIMEType(req.FileName, req.Part)
 	versionedArtifact := &VersionedArtifact{
 		Version:   version,
-		Part:      req.Part,
+		Part:      withMIMEType(req.Part, mimeType),
+		MIMEType:  mimeType,
 		CreatedAt: time.Now(),
 	}
 
@@ -117,6 +123,10 @@ func (s *ArtifactService) Save(ctx context.Context, req *artifact.SaveRequest) (
 	if version > metadata.CurrentVersion {
 		metadata.CurrentVersion = version
 	}
+	if metadata.MIMETypes == nil {
+		metadata.MIMETypes = make(map[int64]string)
+	}
+	metadata.MIMETypes[version] = mimeType
 	metadata.UpdatedAt = time.Now()
 
 	// Save metadata
@@ -129,7 +139,8 @@ func (s *ArtifactService) Save(ctx context.Context, req *artifact.SaveRequest) (
 		logger.StringField("user", req.UserID),
 		logger.StringField("session", req.SessionID),
 		logger.StringField("file", req.FileName),
-		logger.Int64Field("version", version))
+		logger.Int64Field("version", version),
+		logger.StringField("mime_type", mimeType))
 
 	return &artifact.SaveResponse{
 		Version: version,
@@ -147,10 +158,10 @@ func (s *ArtifactService) Load(ctx context.Context, req *artifact.LoadRequest) (
 	defer s.mutex.RUnlock()
 
 	basePath := s.getArtifactBasePath(req.AppName, req.UserID, req.SessionID, req.FileName)
-	metadataPath := path.Join(basePath, "metadata.json")
 
-	// Load metadata to get version info
-	metadata, err := s.loadMetadata(ctx, metadataPath)
+	// Load metadata to get version info; its current version is the pointer to the latest,
+	// so loading the latest version never needs to list the version files
+	metadata, err := s.loadOrRebuildMetadata(ctx, basePath)
 	if err != nil {
 		return nil, fmt.Errorf("artifact not found: %w", err)
 	}
@@ -186,8 +197,14 @@ func (s *ArtifactService) Load(ctx context.Context, req *artifact.LoadRequest) (
 		logger.StringField("file", req.FileName),
 		logger.Int64Field("version", version))
 
+	// Versions saved before MIME types were recorded are detected on load
+	mimeType := versionedArtifact.MIMEType
+	if mimeType == "" {
+		mimeType = detectMIMEType(req.FileName, versionedArtifact.Part)
+	}
+
 	return &artifact.LoadResponse{
-		Part: versionedArtifact.Part,
+		Part: withMIMEType(versionedArtifact.Part, mimeType),
 	}, nil
 }
 
@@ -207,7 +224,7 @@ func (s *ArtifactService) Delete(ctx context.Context, req *artifact.DeleteReques
 
 	if req.Version == 0 {
 		// Delete all versions and metadata
-		metadata, err := s.loadMetadata(ctx, metadataPath)
+		metadata, err := s.loadOrRebuildMetadata(ctx, basePath)
 		if err != nil {
 			// Artifact doesn't exist, consider it deleted
 			return nil
@@ -229,7 +246,7 @@ func (s *ArtifactService) Delete(ctx context.Context, req *artifact.DeleteReques
 			logger.StringField("file", req.FileName))
 	} else {
 		// Delete specific version
-		metadata, err := s.loadMetadata(ctx, metadataPath)
+		metadata, err := s.loadOrRebuildMetadata(ctx, basePath)
 		if err != nil {
 			// Artifact doesn't exist, consider it deleted
 			return nil
@@ -243,6 +260,7 @@ func (s *ArtifactService) Delete(ctx context.Context, req *artifact.DeleteReques
 		metadata.Versions = slices.DeleteFunc(metadata.Versions, func(v int64) bool {
 			return v == req.Version
 		})
+		delete(metadata.MIMETypes, req.Version)
 
 		if len(metadata.Versions) == 0 {
 			// No versions left, delete metadata too
@@ -326,9 +344,8 @@ func (s *ArtifactService) Versions(ctx context.Context, req *artifact.VersionsRe
 	defer s.mutex.RUnlock()
 
 	basePath := s.getArtifactBasePath(req.AppName, req.UserID, req.SessionID, req.FileName)
-	metadataPath := path.Join(basePath, "metadata.json")
 
-	metadata, err := s.loadMetadata(ctx, metadataPath)
+	metadata, err := s.loadOrRebuildMetadata(ctx, basePath)
 	if err != nil {
 		// Artifact doesn't exist, return empty versions
 		return &artifact.VersionsResponse{
@@ -348,6 +365,21 @@ func (s *ArtifactService) Versions(ctx context.Context, req *artifact.VersionsRe
 	}, nil
 }
 
+// MIMEType returns the content type recorded for an artifact version (the latest if Version is 0).
+func (s *ArtifactService) MIMEType(ctx context.Context, req *artifact.LoadRequest) (string, error) {
+	resp, err := s.Load(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if resp.Part.InlineData != nil {
+		return resp.Part.InlineData.MIMEType, nil
+	}
+	if resp.Part.FileData != nil {
+		return resp.Part.FileData.MIMEType, nil
+	}
+	return detectMIMEType(req.FileName, resp.Part), nil
+}
+
 // Helper methods
 
 // getSessionPath returns the path for a session's artifacts.
@@ -380,6 +412,62 @@ func (s *ArtifactService) loadMetadata(ctx context.Context, metadataPath string)
 	return &metadata, nil
 }
 
+// loadOrRebuildMetadata loads an artifact's metadata, which points at its latest version.
+// If the metadata file is missing or unreadable but version files exist, it rebuilds the
+// metadata by listing them and rewrites the file so later loads don't need to scan again.
+func (s *ArtifactService) loadOrRebuildMetadata(ctx context.Context, basePath string) (*ArtifactMetadata, error) {
+	metadataPath := path.Join(basePath, "metadata.json")
+
+	metadata, loadErr := s.loadMetadata(ctx, metadataPath)
+	if loadErr == nil {
+		return metadata, nil
+	}
+
+	files, err := s.fileProvider.List(ctx, path.Join(basePath, "versions")+"/")
+	if err != nil {
+		return nil, loadErr
+	}
+
+	var versions []int64
+	for _, file := range files {
+		name, ok := strings.CutSuffix(path.Base(file), ".json")
+		if !ok {
+			continue
+		}
+		version, err := strconv.ParseInt(name, 10, 64)
+		if err != nil || version <= 0 {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return nil, loadErr
+	}
+	slices.Sort(versions)
+
+	now := time.Now()
+	metadata = &ArtifactMetadata{
+		FileName:       path.Base(basePath),
+		CurrentVersion: versions[len(versions)-1],
+		Versions:       versions,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	// Rewriting is best effort; concurrent readers would write identical metadata
+	if err := s.saveMetadata(ctx, metadataPath, metadata); err != nil {
+		s.log.Warn("Failed to rewrite rebuilt artifact metadata",
+			logger.StringField("path", metadataPath),
+			logger.ErrorField(err))
+	} else {
+		s.log.Info("Rebuilt missing artifact metadata from version files",
+			logger.StringField("path", metadataPath),
+			logger.Int64Field("latest_version", metadata.CurrentVersion))
+	}
+
+	return metadata, nil
+}
+
 // saveMetadata saves artifact metadata to storage.
 func (s *ArtifactService) saveMetadata(ctx context.Context, metadataPath string, metadata *ArtifactMetadata) error {
 	data, err := json.MarshalIndent(metadata, "", "  ")
@@ -535,3 +535,158 @@ func TestNewArtifactService(t *testing.T) {
 		NewArtifactService(provider, nil)
 	})
 }
+
+// listCountingProvider counts List calls so tests can assert loads don't scan
+type listCountingProvider struct {
+	storage_manager.FileProvider
+	lists int
+}
+
+func (p *listCountingProvider) List(ctx context.Context, prefix string) ([]string, error) {
+	p.lists++
+	return p.FileProvider.List(ctx, prefix)
+}
+
+func saveVersions(t *testing.T, service artifact.Service, count int) {
+	t.Helper()
+	for i := 1; i <= count; i++ {
+		_, err := service.Save(context.Background(), &artifact.SaveRequest{
+			AppName:   "test-app",
+			UserID:    "user1",
+			SessionID: "session1",
+			FileName:  "doc.txt",
+			Part:      genai.NewPartFromText("Version " + string(rune('0'+i))),
+		})
+		require.NoError(t, err)
+	}
+}
+
+func loadLatest(t *testing.T, service artifact.Service) string {
+	t.Helper()
+	resp, err := service.Load(context.Background(), &artifact.LoadRequest{
+		AppName:   "test-app",
+		UserID:    "user1",
+		SessionID: "session1",
+		FileName:  "doc.txt",
+	})
+	require.NoError(t, err)
+	return resp.Part.Text
+}
+
+func TestArtifactService_LatestPointerTracksSaves(t *testing.T) {
+	provider := &listCountingProvider{FileProvider: storage_manager.NewLocalFileProvider(t.TempDir())}
+	service := NewArtifactService(provider, testLogger())
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		_, err := service.Save(ctx, &artifact.SaveRequest{
+			AppName: "test-app", UserID: "user1", SessionID: "session1", FileName: "doc.txt",
+			Part: genai.NewPartFromText("Version " + string(rune('0'+i))),
+		})
+		require.NoError(t, err)
+
+		metadata, err := service.loadMetadata(ctx, "test-app/user1/session1/doc.txt/metadata.json")
+		require.NoError(t, err)
+		assert.Equal(t, int64(i), metadata.CurrentVersion)
+	}
+
+	// Only the first save of a new artifact lists (there's no metadata yet)
+	provider.lists = 0
+	assert.Equal(t, "Version 3", loadLatest(t, service))
+	assert.Equal(t, 0, provider.lists, "loading the latest version should not list version files")
+
+	// Deleting the latest version moves the pointer back
+	require.NoError(t, service.Delete(ctx, &artifact.DeleteRequest{
+		AppName: "test-app", UserID: "user1", SessionID: "session1", FileName: "doc.txt", Version: 3,
+	}))
+	metadata, err := service.loadMetadata(ctx, "test-app/user1/session1/doc.txt/metadata.json")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), metadata.CurrentVersion)
+	assert.Equal(t, []int64{1, 2}, metadata.Versions)
+}
+
+func TestArtifactService_RebuildsMissingMetadata(t *testing.T) {
+	provider := &listCountingProvider{FileProvider: storage_manager.NewLocalFileProvider(t.TempDir())}
+	service := NewArtifactService(provider, testLogger())
+	ctx := context.Background()
+	metadataPath := "test-app/user1/session1/doc.txt/metadata.json"
+
+	saveVersions(t, service, 3)
+	require.NoError(t, provider.Delete(ctx, metadataPath))
+	provider.lists = 0
+
+	// Load falls back to scanning the version files and rewrites the metadata
+	assert.Equal(t, "Version 3", loadLatest(t, service))
+	assert.Equal(t, 1, provider.lists)
+
+	exists, err := provider.Exists(ctx, metadataPath)
+	require.NoError(t, err)
+	assert.True(t, exists, "metadata should be rewritten after the scan")
+
+	assert.Equal(t, "Version 3", loadLatest(t, service))
+	assert.Equal(t, 1, provider.lists, "rewritten metadata should avoid another scan")
+
+	// A new save continues from the latest version instead of overwriting version 1
+	require.NoError(t, provider.Delete(ctx, metadataPath))
+	resp, err := service.Save(ctx, &artifact.SaveRequest{
+		AppName: "test-app", UserID: "user1", SessionID: "session1", FileName: "doc.txt",
+		Part: genai.NewPartFromText("Version 4"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), resp.Version)
+}
+
+func TestArtifactService_MIMETypeRoundTrip(t *testing.T) {
+	pngHeader := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	tests := []struct {
+		name     string
+		fileName string
+		part     *genai.Part
+		want     string
+	}{
+		{name: "text", fileName: "notes", part: genai.NewPartFromText("hello"), want: "text/plain; charset=utf-8"},
+		{name: "text with extension", fileName: "data.json", part: genai.NewPartFromText(`{"a":1}`), want: "application/json"},
+		{name: "declared type", fileName: "report", part: genai.NewPartFromBytes([]byte("%PDF-1.7"), "application/pdf"), want: "application/pdf"},
+		{name: "sniffed binary", fileName: "chart", part: genai.NewPartFromBytes(pngHeader, ""), want: "image/png"},
+		{name: "generic declared type sniffed", fileName: "chart", part: genai.NewPartFromBytes(pngHeader, "application/octet-stream"), want: "image/png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewArtifactService(storage_manager.NewLocalFileProvider(t.TempDir()), testLogger())
+			ctx := context.Background()
+
+			_, err := service.Save(ctx, &artifact.SaveRequest{
+				AppName: "test-app", UserID: "user1", SessionID: "session1", FileName: tt.fileName, Part: tt.part,
+			})
+			require.NoError(t, err)
+
+			loadReq := &artifact.LoadRequest{AppName: "test-app", UserID: "user1", SessionID: "session1", FileName: tt.fileName}
+			mimeType, err := service.MIMEType(ctx, loadReq)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, mimeType)
+
+			loadResp, err := service.Load(ctx, loadReq)
+			require.NoError(t, err)
+			if loadResp.Part.InlineData != nil {
+				assert.Equal(t, tt.want, loadResp.Part.InlineData.MIMEType, "loaded part should carry the MIME type")
+			}
+
+			metadata, err := service.loadMetadata(ctx, "test-app/user1/session1/"+tt.fileName+"/metadata.json")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, metadata.MIMETypes[1])
+		})
+	}
+}
+
+func TestArtifactService_SaveDoesNotModifyPart(t *testing.T) {
+	service := emptyArtifactService(t)
+	part := genai.NewPartFromBytes([]byte("plain bytes"), "")
+
+	_, err := service.Save(context.Background(), &artifact.SaveRequest{
+		AppName: "test-app", UserID: "user1", SessionID: "session1", FileName: "blob", Part: part,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, part.InlineData.MIMEType)
+}
@@ -0,0 +1,77 @@
+package artifact_service
+
+import (
+	"mime"
+	"net/http"
+	"path"
+
+	"google.golang.org/genai"
+)
+
+const (
+	// defaultBinaryMIMEType is used for data whose type can't be determined
+	defaultBinaryMIMEType = "application/octet-stream"
+	// defaultTextMIMEType is used for text parts whose file name doesn't suggest a type
+	defaultTextMIMEType = "text/plain; charset=utf-8"
+)
+
+// detectMIMEType determines an artifact's content type: the part's declared type if it has
+// a specific one, otherwise from the file name's extension, otherwise sniffed from the bytes.
+func detectMIMEType(fileName string, part *genai.Part) string {
+	if part == nil {
+		return defaultBinaryMIMEType
+	}
+
+	byExtension := mime.TypeByExtension(path.Ext(fileName))
+
+	switch {
+	case part.InlineData != nil:
+		if declared := part.InlineData.MIMEType; declared != "" && declared != defaultBinaryMIMEType {
+			return declared
+		}
+		if byExtension != "" {
+			return byExtension
+		}
+		return http.DetectContentType(part.InlineData.Data)
+	case part.FileData != nil:
+		if declared := part.FileData.MIMEType; declared != "" {
+			return declared
+		}
+		if byExtension != "" {
+			return byExtension
+		}
+		return defaultBinaryMIMEType
+	case part.Text != "":
+		if byExtension != "" {
+			return byExtension
+		}
+		return defaultTextMIMEType
+	default:
+		return defaultBinaryMIMEType
+	}
+}
+
+// withMIMEType returns part with its inline or file data labelled with mimeType when the
+// part didn't declare a specific type. The caller's part is never modified.
+func withMIMEType(part *genai.Part, mimeType string) *genai.Part {
+	if part == nil {
+		return nil
+	}
+
+	switch {
+	case part.InlineData != nil && (part.InlineData.MIMEType == "" || part.InlineData.MIMEType == defaultBinaryMIMEType):
+		labelled := *part
+		blob := *part.InlineData
+		blob.MIMEType = mimeType
+		labelled.InlineData = &blob
+		return &labelled
+	case part.FileData != nil && part.FileData.MIMEType == "":
+		labelled := *part
+		fileData := *part.FileData
+		fileData.MIMEType = mimeType
+		labelled.FileData = &fileData
+		return &labelled
+	default:
+		return part
+	}
+}
@@ -0,0 +1,90 @@
+// Package audit records administrative actions such as maintenance toggles and session
+// resets. Records go to their own sink, separate from the application log, so they can be
+// retained for longer.
+package audit
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/jsonl"
+)
+
+// Audited actions
+const (
+	ActionMaintenance    = "maintenance" // A maintenance command that didn't change the mode, e.g. denied
+	ActionMaintenanceOn  = "maintenance_on"
+	ActionMaintenanceOff = "maintenance_off"
+	ActionSessionReset   = "session_reset"
+	ActionLogLevel       = "log_level"
+	ActionDataDelete     = "data_delete" // Stored user or app data deleted through the admin CLI
+)
+
+// Outcomes of an audited action
+const (
+	ResultSuccess = "success"
+	ResultDenied  = "denied" // The actor isn't an admin
+	ResultFailed  = "failed"
+)
+
+// Platforms of actions taken outside the chat platforms: on the host, e.g. through a signal,
+// or through the admin HTTP endpoints
+const (
+	PlatformSystem = "system"
+	PlatformHTTP   = "http"
+)
+
+// Record is one audited action, written as a line of JSON
+type Record struct {
+	Time     time.Time `json:"time"`
+	Platform string    `json:"platform"` // slack, telegram, system or http
+	Actor    string    `json:"actor"`    // Platform user ID, the signal for system actions, or the client address for http
+	Action   string    `json:"action"`
+	Target   string    `json:"target,omitempty"` // What the action applied to, e.g. a user ID
+	Result   string    `json:"result"`
+	Detail   string    `json:"detail,omitempty"`
+}
+
+// Log appends audit records to a sink. A nil *Log discards records, so audit logging can
+// be left unconfigured without callers checking.
+type Log struct {
+	sink *jsonl.Sink
+	now  func() time.Time
+}
+
+// New creates an audit log writing JSON lines to w
+func New(w io.Writer) *Log {
+	return &Log{sink: jsonl.New(w), now: time.Now}
+}
+
+// Open creates an audit log appending to the file at path, creating it if needed
+func Open(path string) (*Log, error) {
+	sink, err := jsonl.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &Log{sink: sink, now: time.Now}, nil
+}
+
+// Record writes r, stamping it with the current time if Time is unset
+func (l *Log) Record(r Record) error {
+	if l == nil {
+		return nil
+	}
+	if r.Time.IsZero() {
+		r.Time = l.now().UTC()
+	}
+	if err := l.sink.Write(r); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying file, if the log owns one
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.sink.Close()
+}
@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpen_AppendsRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	for _, action := range []string{ActionMaintenanceOn, ActionMaintenanceOff} {
+		l, err := Open(path)
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		if err := l.Record(Record{Platform: "slack", Actor: "U1", Action: action, Result: ResultSuccess}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+		if err := l.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var records []Record
+	for dec.More() {
+		var r Record
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		records = append(records, r)
+	}
+
+	if len(records) != 2 || records[0].Action != ActionMaintenanceOn || records[1].Action != ActionMaintenanceOff {
+		t.Fatalf("records = %+v, want both actions in order", records)
+	}
+	if records[0].Time.IsZero() {
+		t.Error("record has no timestamp")
+	}
+}
+
+func TestRecord_NilLogDiscards(t *testing.T) {
+	var l *Log
+	if err := l.Record(Record{Action: ActionSessionReset}); err != nil {
+		t.Errorf("Record() on nil log error = %v", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("Close() on nil log error = %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+// Package buildinfo reports the version the binary was built as, so deployments can be
+// matched to their behaviour.
+package buildinfo
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Set at build time with -ldflags, e.g.
+//
+//	go build -ldflags "-X github.com/lewisedginton/general_purpose_chatbot/internal/buildinfo.Version=v1.2.0
+//	  -X github.com/lewisedginton/general_purpose_chatbot/internal/buildinfo.Commit=$(git rev-parse HEAD)
+//	  -X github.com/lewisedginton/general_purpose_chatbot/internal/buildinfo.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// When they're not set, the module version and VCS details Go records in the binary are used.
+var (
+	Version string
+	Commit  string
+	Date    string
+)
+
+// Reported when the value isn't known
+const (
+	DefaultVersion = "dev"
+	unknown        = "unknown"
+)
+
+// Info describes a build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the binary's build information
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildDate: Date, GoVersion: runtime.Version()}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+			info.Version = build.Main.Version
+		}
+		modified := false
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+		if modified && Commit == "" && info.Commit != "" {
+			info.Commit += "-dirty"
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = DefaultVersion
+	}
+	if info.Commit == "" {
+		info.Commit = unknown
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = unknown
+	}
+	return info
+}
+
+// String formats the build information on one line, e.g.
+// "v1.2.0 (commit 3f2c1ab, built 2026-01-05T10:00:00Z, go1.25.0)"
+func (i Info) String() string {
+	return fmt.Sprintf("%s (commit %s, built %s, %s)", i.Version, i.Commit, i.BuildDate, i.GoVersion)
+}
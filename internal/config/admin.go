@@ -0,0 +1,8 @@
+package config
+
+// AdminConfig holds settings for the admin HTTP endpoints served on the health port, such
+// as POST /admin/loglevel. They're only served when Token is set, and requests must send it
+// as a bearer token.
+type AdminConfig struct {
+	Token string `env:"ADMIN_TOKEN" yaml:"-"`
+}
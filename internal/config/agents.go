@@ -0,0 +1,22 @@
+package config
+
+// AgentDefinition is a named agent that messages can be routed to, with its own
+// instructions, tools and model. Each connector gets its own copy, with the connector's
+// display name, intro and persona. Agents are set in YAML.
+type AgentDefinition struct {
+	Name         string   `yaml:"name"`
+	Description  string   `yaml:"description"`
+	Instructions string   `yaml:"instructions"` // System prompt; empty uses prompts/system.md
+	Tools        []string `yaml:"tools"`        // Names of the only tools it may use, MCP tools as mcp__server__tool; empty for all
+	Model        string   `yaml:"model"`        // Model of the configured LLM provider; empty for the default model
+}
+
+// AgentRoute sends messages to a named agent. A message matches a route when it meets every
+// criterion the route sets. Routes are set in YAML and checked in order; the first match
+// wins and messages matching none go to the connector's default agent.
+type AgentRoute struct {
+	Agent    string   `yaml:"agent"`
+	Channels []string `yaml:"channels"` // Slack channel IDs or Telegram chat IDs
+	Commands []string `yaml:"commands"` // First word of the message, e.g. "/ops"
+	Pattern  string   `yaml:"pattern"`  // Go regular expression matched against the message
+}
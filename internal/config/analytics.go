@@ -0,0 +1,10 @@
+package config
+
+// AnalyticsConfig holds analytics configuration. Events such as feedback on replies are
+// recorded as JSON lines in their own file, separate from the application log.
+type AnalyticsConfig struct {
+	Path string `env:"ANALYTICS_LOG_PATH" yaml:"path"` // File events are appended to; empty discards them
+
+	// Feedback adds 👍/👎 buttons to the bot's replies and records the ratings users give
+	Feedback bool `env:"FEEDBACK_ENABLED" yaml:"feedback" default:"false"`
+}
@@ -4,15 +4,33 @@ import "time"
 
 // AnthropicConfig holds Anthropic-specific configuration
 type AnthropicConfig struct {
-	APIKey         string        `env:"ANTHROPIC_API_KEY" yaml:"-"`
-	Model          string        `env:"CLAUDE_MODEL" yaml:"model" default:"claude-sonnet-4-5-20250929"`
-	APIBaseURL     string        `env:"ANTHROPIC_API_URL" yaml:"api_base_url" default:"https://api.anthropic.com"`
+	APIKey     string `env:"ANTHROPIC_API_KEY" yaml:"-"`
+	Model      string `env:"CLAUDE_MODEL" yaml:"model" default:"claude-sonnet-4-5-20250929"`
+	APIBaseURL string `env:"ANTHROPIC_API_URL" yaml:"api_base_url" default:"https://api.anthropic.com"`
+
+	// Retries of failed API calls (rate limits, overload, server errors, timeouts) and the
+	// timeout for each attempt, which must allow for long replies
 	MaxRetries     int           `env:"ANTHROPIC_MAX_RETRIES" yaml:"max_retries" default:"3"`
 	InitialBackoff time.Duration `env:"ANTHROPIC_INITIAL_BACKOFF" yaml:"initial_backoff" default:"1s"`
 	MaxBackoff     time.Duration `env:"ANTHROPIC_MAX_BACKOFF" yaml:"max_backoff" default:"10s"`
-	Timeout        time.Duration `env:"ANTHROPIC_TIMEOUT" yaml:"timeout" default:"30s"`
+	Timeout        time.Duration `env:"ANTHROPIC_TIMEOUT" yaml:"timeout" default:"2m"`
+
+	// Estimated input tokens (system prompt, tools and history) per request; the oldest
+	// messages are dropped to fit. Kept below the context window to allow for estimation error.
+	MaxInputTokens int `env:"ANTHROPIC_MAX_INPUT_TOKENS" yaml:"max_input_tokens" default:"160000"`
+
+	// Prompt caching of the tools, system prompt and history, which cuts the cost and latency
+	// of requests repeating them
+	PromptCaching bool `env:"ANTHROPIC_PROMPT_CACHING" yaml:"prompt_caching" default:"true"`
+
+	// Extended thinking; the budget must be at least 1024 and below the max output tokens
+	ThinkingEnabled bool `env:"ANTHROPIC_THINKING_ENABLED" yaml:"thinking_enabled"`
+	ThinkingBudget  int  `env:"ANTHROPIC_THINKING_BUDGET" yaml:"thinking_budget" default:"2048"`
 }
 
+// MinThinkingBudget is the smallest extended thinking budget the Anthropic API accepts
+const MinThinkingBudget = 1024
+
 // AnthropicRetryConfig represents retry configuration for Anthropic
 type AnthropicRetryConfig struct {
 	MaxRetries     int
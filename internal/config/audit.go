@@ -0,0 +1,7 @@
+package config
+
+// AuditConfig holds audit log configuration. Admin actions (maintenance toggles, session
+// resets) are recorded as JSON lines in their own file, separate from the application log.
+type AuditConfig struct {
+	Path string `env:"AUDIT_LOG_PATH" yaml:"path"` // File admin actions are appended to; empty disables auditing
+}
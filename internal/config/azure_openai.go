@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+// AzureOpenAIConfig holds Azure OpenAI configuration. Azure serves OpenAI models from
+// named deployments on a per-resource endpoint rather than by model name.
+type AzureOpenAIConfig struct {
+	APIKey string `env:"AZURE_OPENAI_API_KEY" yaml:"-"`
+	// Endpoint is the resource endpoint, e.g. https://my-resource.openai.azure.com
+	Endpoint string `env:"AZURE_OPENAI_ENDPOINT" yaml:"endpoint"`
+	// Deployment is the name of the model deployment requests are sent to
+	Deployment string        `env:"AZURE_OPENAI_DEPLOYMENT" yaml:"deployment"`
+	APIVersion string        `env:"AZURE_OPENAI_API_VERSION" yaml:"api_version" default:"2024-10-21"`
+	MaxRetries int           `env:"AZURE_OPENAI_MAX_RETRIES" yaml:"max_retries" default:"3"`
+	Timeout    time.Duration `env:"AZURE_OPENAI_TIMEOUT" yaml:"timeout" default:"2m"` // Per attempt; must allow for long replies
+}
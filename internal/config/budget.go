@@ -0,0 +1,13 @@
+package config
+
+// BudgetConfig caps what one conversation can spend, so a single runaway conversation can't
+// run up the bill. Tokens reported by the model are added up per conversation; once a limit
+// is reached the bot replies with Message instead of calling the model until the user starts
+// a new conversation. Limits of 0 are disabled; a cost limit needs the per-million prices.
+type BudgetConfig struct {
+	MaxTokens            int     `env:"BUDGET_MAX_TOKENS" yaml:"max_tokens" default:"0"`
+	MaxCost              float64 `env:"BUDGET_MAX_COST" yaml:"max_cost" default:"0"`
+	InputCostPerMillion  float64 `env:"BUDGET_INPUT_COST_PER_MILLION" yaml:"input_cost_per_million" default:"0"`
+	OutputCostPerMillion float64 `env:"BUDGET_OUTPUT_COST_PER_MILLION" yaml:"output_cost_per_million" default:"0"`
+	Message              string  `env:"BUDGET_MESSAGE" yaml:"message"` // Reply once the budget is used up (a default is used if empty)
+}
@@ -0,0 +1,10 @@
+package config
+
+// CannedResponseRule answers messages matching Pattern (a Go regular expression, e.g.
+// "(?i)\\bpassword\\b") with Response instead of calling the model. Rules are set in YAML
+// and checked in order; the first match wins.
+type CannedResponseRule struct {
+	Name     string `yaml:"name"`
+	Pattern  string `yaml:"pattern"`
+	Response string `yaml:"response"`
+}
@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+// CommandConfig holds configuration for the allowlisted command execution tool.
+// The tool is disabled by default and also requires a non-empty allowlist.
+type CommandConfig struct {
+	Enabled         bool          `env:"COMMAND_TOOL_ENABLED" yaml:"enabled" default:"false"`
+	AllowedCommands []string      `env:"COMMAND_TOOL_ALLOWLIST" yaml:"allowed_commands"`
+	Timeout         time.Duration `env:"COMMAND_TOOL_TIMEOUT" yaml:"timeout" default:"10s"`
+	MaxOutputBytes  int           `env:"COMMAND_TOOL_MAX_OUTPUT_BYTES" yaml:"max_output_bytes" default:"65536"`
+	WorkingDir      string        `env:"COMMAND_TOOL_WORKING_DIR" yaml:"working_dir"`
+}
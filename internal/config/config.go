@@ -3,10 +3,14 @@ package config
 
 import (
 	"fmt"
+	"math"
+	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/httpclient"
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
 )
 
@@ -14,12 +18,27 @@ import (
 type AppConfig struct {
 	// Service configuration
 	ServiceName string `env:"SERVICE_NAME" yaml:"service_name" default:"general-purpose-chatbot"`
-	Version     string `env:"VERSION" yaml:"version" default:"dev"`
+	Version     string `env:"VERSION" yaml:"version"` // Label for logs; the build version if empty
 	Environment string `env:"ENVIRONMENT" yaml:"environment" default:"development"`
 
 	// Server configuration
 	RequestTimeout time.Duration `env:"REQUEST_TIMEOUT" yaml:"request_timeout" default:"30s"`
 
+	// Longest a single LLM call may take, separate from the whole turn, which can include
+	// several model calls and tool round trips (0 leaves calls to the provider's own timeout)
+	ModelCallTimeout time.Duration `env:"MODEL_CALL_TIMEOUT" yaml:"model_call_timeout" default:"0s"`
+
+	// How long to wait at startup for connectors to connect before reporting the server started (0 to not wait)
+	ConnectorStartupTimeout time.Duration `env:"CONNECTOR_STARTUP_TIMEOUT" yaml:"connector_startup_timeout" default:"30s"`
+
+	// Reply sent when a message can't be processed. A Go template where {{.CorrelationID}} is
+	// the reference logged with the error, for users to quote to support; a default is used if empty.
+	ErrorMessage string `env:"ERROR_MESSAGE" yaml:"error_message"`
+
+	// Formatting instructions for connectors without a formatting guide of their own; a
+	// Markdown guide is used if empty
+	FormattingGuide string `env:"DEFAULT_FORMATTING_GUIDE" yaml:"default_formatting_guide"`
+
 	// LLM Provider configuration
 	LLM LLMConfig `yaml:"llm"`
 
@@ -32,6 +51,9 @@ type AppConfig struct {
 	// OpenAI configuration
 	OpenAI OpenAIConfig `yaml:"openai"`
 
+	// Azure OpenAI configuration
+	AzureOpenAI AzureOpenAIConfig `yaml:"azure_openai"`
+
 	// Logging configuration
 	Logging LoggingConfig `yaml:"logging"`
 
@@ -56,11 +78,67 @@ type AppConfig struct {
 	// Search tool configuration
 	Search SearchConfig `yaml:"search"`
 
+	// Command execution tool configuration
+	Command CommandConfig `yaml:"command"`
+
+	// Settings shared by all tools
+	Tools ToolsConfig `yaml:"tools"`
+
+	// Document ingestion and search configuration
+	Documents DocumentsConfig `yaml:"documents"`
+
+	// Conversation language configuration
+	Language LanguageConfig `yaml:"language"`
+	Display  DisplayConfig  `yaml:"display"`
+
+	// Inbound message length limits
+	Inbound InboundConfig `yaml:"inbound"`
+
+	// Cap on what one conversation can spend
+	Budget BudgetConfig `yaml:"budget"`
+
+	// Pacing of messages the bot sends
+	Throttle ThrottleConfig `yaml:"throttle"`
+
+	// Fixed replies to messages matching a pattern (YAML only)
+	CannedResponses []CannedResponseRule `yaml:"canned_responses"`
+
+	// Named agents and the routes sending messages to them (YAML only)
+	Agents      []AgentDefinition `yaml:"agents"`
+	AgentRoutes []AgentRoute      `yaml:"agent_routes"`
+
+	// Response cache configuration
+	ResponseCache ResponseCacheConfig `yaml:"response_cache"`
+
+	// Maintenance mode configuration
+	Maintenance MaintenanceConfig `yaml:"maintenance"`
+
+	// Audit log configuration
+	Audit AuditConfig `yaml:"audit"`
+
+	// Analytics configuration, e.g. feedback on replies
+	Analytics AnalyticsConfig `yaml:"analytics"`
+
+	// Quiet hours for proactive messages such as reminders
+	QuietHours QuietHoursConfig `yaml:"quiet_hours"`
+
+	// Reminders users schedule through the set_reminder tool
+	Reminders RemindersConfig `yaml:"reminders"`
+
+	// Where secret://name references in other settings are resolved from
+	Secrets SecretsConfig `yaml:"secrets"`
+
+	// Outbound HTTP client configuration (proxy, TLS, timeouts, connection pooling)
+	Outbound OutboundConfig `yaml:"outbound"`
+
 	// Storage configuration (persistence layer)
 	Storage StorageConfig `yaml:"storage"`
 
 	// Health check configuration
 	Health HealthConfig `yaml:"health"`
+
+	// Admin HTTP endpoints
+	Admin AdminConfig `yaml:"admin"`
 }
 
 // Validate validates the configuration and returns an error if invalid
@@ -71,8 +149,8 @@ func (c *AppConfig) Validate() error {
 
 	// Validate LLM provider
 	provider := strings.ToLower(c.LLM.Provider)
-	if provider != ProviderClaude && provider != ProviderGemini && provider != ProviderOpenAI {
-		result = multierror.Append(result, fmt.Errorf("llm_provider must be 'claude', 'gemini', or 'openai', got %q", c.LLM.Provider))
+	if provider != ProviderClaude && provider != ProviderGemini && provider != ProviderOpenAI && provider != ProviderAzureOpenAI {
+		result = multierror.Append(result, fmt.Errorf("llm_provider must be 'claude', 'gemini', 'openai', or 'azure_openai', got %q", c.LLM.Provider))
 	}
 
 	// Validate provider-specific configuration
@@ -85,11 +163,68 @@ func (c *AppConfig) Validate() error {
 		if c.Gemini.APIKey == "" {
 			result = multierror.Append(result, fmt.Errorf("gemini_api_key is required when using gemini provider"))
 		}
+		if c.Gemini.MaxRetries < 0 {
+			result = multierror.Append(result, fmt.Errorf("gemini_max_retries cannot be negative"))
+		}
+		if c.Gemini.MaxRetries > 0 && (c.Gemini.InitialBackoff <= 0 || c.Gemini.MaxBackoff < c.Gemini.InitialBackoff) {
+			result = multierror.Append(result, fmt.Errorf("gemini_initial_backoff must be greater than 0 and no more than gemini_max_backoff when gemini_max_retries is set"))
+		}
 	}
 	if provider == ProviderOpenAI {
 		if c.OpenAI.APIKey == "" {
 			result = multierror.Append(result, fmt.Errorf("openai_api_key is required when using openai provider"))
 		}
+		if err := checkBaseURL(c.OpenAI.APIBaseURL); err != nil {
+			result = multierror.Append(result, fmt.Errorf("openai_api_url: %w", err))
+		}
+		if c.OpenAI.Timeout < 0 {
+			result = multierror.Append(result, fmt.Errorf("openai_timeout cannot be negative"))
+		}
+		if c.OpenAI.MaxRetries < 0 {
+			result = multierror.Append(result, fmt.Errorf("openai_max_retries cannot be negative"))
+		}
+	}
+	if provider == ProviderAzureOpenAI {
+		azure := c.AzureOpenAI
+		if azure.APIKey == "" {
+			result = multierror.Append(result, fmt.Errorf("azure_openai_api_key is required when using azure_openai provider"))
+		}
+		if azure.Endpoint == "" {
+			result = multierror.Append(result, fmt.Errorf("azure_openai_endpoint is required when using azure_openai provider"))
+		} else if err := checkBaseURL(azure.Endpoint); err != nil {
+			result = multierror.Append(result, fmt.Errorf("azure_openai_endpoint: %w", err))
+		}
+		if azure.Deployment == "" {
+			result = multierror.Append(result, fmt.Errorf("azure_openai_deployment is required when using azure_openai provider"))
+		}
+		if azure.APIVersion == "" {
+			result = multierror.Append(result, fmt.Errorf("azure_openai_api_version is required when using azure_openai provider"))
+		}
+		if azure.Timeout < 0 {
+			result = multierror.Append(result, fmt.Errorf("azure_openai_timeout cannot be negative"))
+		}
+		if azure.MaxRetries < 0 {
+			result = multierror.Append(result, fmt.Errorf("azure_openai_max_retries cannot be negative"))
+		}
+	}
+
+	// Validate default model parameters
+	params := c.LLM.Params
+	if params.Temperature != nil {
+		maxTemp := maxTemperature(provider)
+		if *params.Temperature < 0 || *params.Temperature > maxTemp {
+			result = multierror.Append(result, fmt.Errorf("llm_temperature must be between 0 and %g for %s provider, got %g",
+				maxTemp, provider, *params.Temperature))
+		}
+	}
+	if params.TopP != nil && (*params.TopP < 0 || *params.TopP > 1) {
+		result = multierror.Append(result, fmt.Errorf("llm_top_p must be between 0 and 1, got %g", *params.TopP))
+	}
+	if params.MaxTokens < 0 {
+		result = multierror.Append(result, fmt.Errorf("llm_max_tokens cannot be negative"))
+	}
+	if params.Seed != nil && (*params.Seed < math.MinInt32 || *params.Seed > math.MaxInt32) {
+		result = multierror.Append(result, fmt.Errorf("llm_seed must fit in a 32-bit integer, got %d", *params.Seed))
 	}
 
 	// Validate log level
@@ -115,9 +250,111 @@ func (c *AppConfig) Validate() error {
 	if c.RequestTimeout <= 0 {
 		result = multierror.Append(result, fmt.Errorf("request_timeout must be greater than 0"))
 	}
+	if c.ModelCallTimeout < 0 {
+		result = multierror.Append(result, fmt.Errorf("model_call_timeout cannot be negative"))
+	}
+	if c.Storage.DegradedMode && (c.Storage.DegradedRetries <= 0 || c.Storage.DegradedRetryBackoff <= 0) {
+		result = multierror.Append(result, fmt.Errorf("storage_degraded_retries and storage_degraded_retry_backoff must be greater than 0 when storage_degraded_mode is set"))
+	}
+	if c.Storage.RetentionEvents < 0 || c.Storage.RetentionAge < 0 {
+		result = multierror.Append(result, fmt.Errorf("storage_retention_events and storage_retention_age cannot be negative"))
+	}
+	if _, err := time.LoadLocation(c.Display.Timezone); err != nil {
+		result = multierror.Append(result, fmt.Errorf("display_timezone is invalid: %w", err))
+	}
+	if c.ConnectorStartupTimeout < 0 {
+		result = multierror.Append(result, fmt.Errorf("connector_startup_timeout cannot be negative"))
+	}
+	if c.Outbound.ProxyURL != "" {
+		if _, err := httpclient.ParseProxyURL(c.Outbound.ProxyURL); err != nil {
+			result = multierror.Append(result, fmt.Errorf("outbound_proxy_url: %w", err))
+		}
+	}
+	if c.Outbound.CAFile != "" {
+		if _, err := httpclient.LoadCAPool(c.Outbound.CAFile); err != nil {
+			result = multierror.Append(result, fmt.Errorf("outbound_ca_file: %w", err))
+		}
+	}
+	if c.Outbound.Timeout < 0 || c.Outbound.DialTimeout < 0 || c.Outbound.TLSHandshakeTimeout < 0 ||
+		c.Outbound.ResponseHeaderTimeout < 0 || c.Outbound.IdleConnTimeout < 0 {
+		result = multierror.Append(result, fmt.Errorf("outbound timeouts cannot be negative"))
+	}
+	if c.Outbound.MaxIdleConns < 0 || c.Outbound.MaxIdleConnsPerHost < 0 {
+		result = multierror.Append(result, fmt.Errorf("outbound max_idle_conns and max_idle_conns_per_host cannot be negative"))
+	}
+	switch strings.ToLower(c.Tools.History) {
+	case "", "all", "summarize", "omit":
+	default:
+		result = multierror.Append(result, fmt.Errorf("tool_history must be 'all', 'summarize' or 'omit', got %q", c.Tools.History))
+	}
+	if c.Tools.HistoryTurns < 0 {
+		result = multierror.Append(result, fmt.Errorf("tool_history_turns cannot be negative, got %d", c.Tools.HistoryTurns))
+	}
+	if c.ResponseCache.Enabled {
+		switch strings.ToLower(c.ResponseCache.Backend) {
+		case "", ResponseCacheMemory:
+		case ResponseCacheRedis:
+			if c.ResponseCache.RedisAddr == "" {
+				result = multierror.Append(result, fmt.Errorf("response_cache_redis_addr is required for the redis response cache"))
+			}
+		default:
+			result = multierror.Append(result, fmt.Errorf("response_cache_backend must be 'memory' or 'redis', got %q", c.ResponseCache.Backend))
+		}
+		if c.ResponseCache.TTL <= 0 {
+			result = multierror.Append(result, fmt.Errorf("response_cache_ttl must be greater than 0"))
+		}
+		if c.ResponseCache.HistoryWindow < 0 {
+			result = multierror.Append(result, fmt.Errorf("response_cache_history_window cannot be negative"))
+		}
+	}
+	agentNames := make(map[string]bool, len(c.Agents))
+	for i, agent := range c.Agents {
+		if agent.Name == "" {
+			result = multierror.Append(result, fmt.Errorf("agents[%d] needs a name", i))
+		} else if agentNames[agent.Name] {
+			result = multierror.Append(result, fmt.Errorf("agents[%d] (%s) has a duplicate name", i, agent.Name))
+		}
+		agentNames[agent.Name] = true
+	}
+	for i, route := range c.AgentRoutes {
+		if !agentNames[route.Agent] {
+			result = multierror.Append(result, fmt.Errorf("agent_routes[%d] uses unknown agent %q", i, route.Agent))
+		}
+		if len(route.Channels) == 0 && len(route.Commands) == 0 && route.Pattern == "" {
+			result = multierror.Append(result, fmt.Errorf("agent_routes[%d] (%s) needs channels, commands or a pattern", i, route.Agent))
+		}
+		if _, err := regexp.Compile(route.Pattern); err != nil {
+			result = multierror.Append(result, fmt.Errorf("agent_routes[%d] (%s) has an invalid pattern: %q", i, route.Agent, route.Pattern))
+		}
+	}
+	for i, rule := range c.CannedResponses {
+		if _, err := regexp.Compile(rule.Pattern); err != nil || rule.Pattern == "" {
+			result = multierror.Append(result, fmt.Errorf("canned_responses[%d] (%s) needs a valid pattern: %q", i, rule.Name, rule.Pattern))
+		}
+		if rule.Response == "" {
+			result = multierror.Append(result, fmt.Errorf("canned_responses[%d] (%s) needs a response", i, rule.Name))
+		}
+	}
+	if c.Tools.Timeout < 0 {
+		result = multierror.Append(result, fmt.Errorf("tool_timeout cannot be negative"))
+	}
+	if c.Tools.DelegationMaxDepth < 0 {
+		result = multierror.Append(result, fmt.Errorf("tool_delegation_max_depth cannot be negative"))
+	}
+	if c.Tools.DelegationTimeout < 0 {
+		result = multierror.Append(result, fmt.Errorf("tool_delegation_timeout cannot be negative"))
+	}
+	for name, timeout := range c.Tools.TimeoutOverrides {
+		if timeout < 0 {
+			result = multierror.Append(result, fmt.Errorf("tools.timeout_overrides[%s] cannot be negative", name))
+		}
+	}
 
 	// Validate Anthropic-specific config if using Claude
 	if provider == "claude" {
+		if err := checkBaseURL(c.Anthropic.APIBaseURL); err != nil {
+			result = multierror.Append(result, fmt.Errorf("anthropic_api_url: %w", err))
+		}
 		if c.Anthropic.Timeout <= 0 {
 			result = multierror.Append(result, fmt.Errorf("anthropic_timeout must be greater than 0"))
 		}
@@ -134,6 +371,23 @@ func (c *AppConfig) Validate() error {
 		if c.Anthropic.MaxBackoff < c.Anthropic.InitialBackoff {
 			result = multierror.Append(result, fmt.Errorf("anthropic_max_backoff must be greater than or equal to anthropic_initial_backoff"))
 		}
+
+		// Validate extended thinking configuration
+		if c.Anthropic.ThinkingEnabled {
+			maxTokens := c.GetModelParams().MaxTokens
+			if c.Anthropic.ThinkingBudget < MinThinkingBudget {
+				result = multierror.Append(result, fmt.Errorf("anthropic_thinking_budget must be at least %d, got %d", MinThinkingBudget, c.Anthropic.ThinkingBudget))
+			}
+			if c.Anthropic.ThinkingBudget >= maxTokens {
+				result = multierror.Append(result, fmt.Errorf("anthropic_thinking_budget must be less than llm_max_tokens (%d), got %d", maxTokens, c.Anthropic.ThinkingBudget))
+			}
+			if c.LLM.Params.Temperature != nil {
+				result = multierror.Append(result, fmt.Errorf("llm_temperature cannot be set when anthropic extended thinking is enabled"))
+			}
+			if c.LLM.Params.TopP != nil && *c.LLM.Params.TopP < 0.95 {
+				result = multierror.Append(result, fmt.Errorf("llm_top_p must be at least 0.95 when anthropic extended thinking is enabled, got %v", *c.LLM.Params.TopP))
+			}
+		}
 	}
 
 	// Validate security config
@@ -226,6 +480,124 @@ func (c *AppConfig) Validate() error {
 		}
 	}
 
+	// Validate the per-conversation budget
+	if c.Budget.MaxTokens < 0 || c.Budget.MaxCost < 0 || c.Budget.InputCostPerMillion < 0 || c.Budget.OutputCostPerMillion < 0 {
+		result = multierror.Append(result, fmt.Errorf("budget limits and prices cannot be negative"))
+	}
+	if c.Budget.MaxCost > 0 && c.Budget.InputCostPerMillion == 0 && c.Budget.OutputCostPerMillion == 0 {
+		result = multierror.Append(result, fmt.Errorf("budget max_cost needs input_cost_per_million or output_cost_per_million"))
+	}
+
+	// Validate outbound message pacing
+	if c.Throttle.GlobalRate < 0 || c.Throttle.ChannelRate < 0 || c.Throttle.GlobalBurst < 0 || c.Throttle.ChannelBurst < 0 {
+		result = multierror.Append(result, fmt.Errorf("throttle rates and bursts cannot be negative"))
+	}
+
+	// Validate inbound message limits
+	if c.Inbound.MaxChars < 0 || c.Inbound.MaxTokens < 0 {
+		result = multierror.Append(result, fmt.Errorf("inbound max_chars and max_tokens cannot be negative"))
+	}
+	if action := strings.ToLower(c.Inbound.Action); action != "" && action != InboundActionTruncate && action != InboundActionReject {
+		result = multierror.Append(result, fmt.Errorf("inbound oversize_action must be 'truncate' or 'reject', got %q", c.Inbound.Action))
+	}
+	if c.Inbound.TrimQuotes && (c.Inbound.TrimThreshold <= 0 || c.Inbound.TrimKeepLines <= 0) {
+		result = multierror.Append(result, fmt.Errorf("inbound trim_threshold and trim_keep_lines must be greater than 0"))
+	}
+
+	// Validate per-platform agent names
+	if c.Slack.Enabled() && c.Slack.AgentName == "" {
+		result = multierror.Append(result, fmt.Errorf("slack_agent_name cannot be empty"))
+	}
+	if c.Slack.Enabled() {
+		if c.Slack.ReconnectInitialBackoff <= 0 || c.Slack.ReconnectMaxBackoff < c.Slack.ReconnectInitialBackoff {
+			result = multierror.Append(result, fmt.Errorf("slack reconnect_initial_backoff must be greater than 0 and no more than reconnect_max_backoff"))
+		}
+		if c.Slack.ReconnectMaxRetries < 1 {
+			result = multierror.Append(result, fmt.Errorf("slack reconnect_max_retries must be at least 1, got %d", c.Slack.ReconnectMaxRetries))
+		}
+	}
+	if c.Telegram.Enabled() && c.Telegram.AgentName == "" {
+		result = multierror.Append(result, fmt.Errorf("telegram_agent_name cannot be empty"))
+	}
+	if mode := strings.ToLower(c.Telegram.Mode); mode != "" && mode != TelegramModePoll && mode != TelegramModeWebhook {
+		result = multierror.Append(result, fmt.Errorf("telegram mode must be 'poll' or 'webhook', got %q", c.Telegram.Mode))
+	}
+	if c.Telegram.Enabled() && c.Telegram.WebhookEnabled() {
+		if c.Telegram.WebhookURL == "" {
+			result = multierror.Append(result, fmt.Errorf("telegram_webhook_url is required in webhook mode"))
+		}
+		if c.Telegram.WebhookSecret == "" {
+			result = multierror.Append(result, fmt.Errorf("telegram_webhook_secret is required in webhook mode"))
+		}
+		if !strings.HasPrefix(c.Telegram.WebhookPath, "/") {
+			result = multierror.Append(result, fmt.Errorf("telegram_webhook_path must start with '/', got %q", c.Telegram.WebhookPath))
+		}
+		if !c.Health.Enabled {
+			result = multierror.Append(result, fmt.Errorf("telegram webhook mode requires the health server to be enabled"))
+		}
+	}
+
+	// Validate web search config (if enabled)
+	if c.Search.Enabled() {
+		if c.Search.MaxResults < 1 || c.Search.MaxResults > 100 {
+			result = multierror.Append(result, fmt.Errorf("search_max_results must be between 1 and 100, got %d", c.Search.MaxResults))
+		}
+		if c.Search.SnippetChars < 1 || c.Search.SnippetChars > 10000 {
+			result = multierror.Append(result, fmt.Errorf("search_snippet_chars must be between 1 and 10000, got %d", c.Search.SnippetChars))
+		}
+		if c.Search.CacheTTL <= 0 || c.Search.CacheSize <= 0 {
+			result = multierror.Append(result, fmt.Errorf("search_cache_ttl and search_cache_size must be greater than 0"))
+		}
+		if c.Search.MaxRetries < 0 {
+			result = multierror.Append(result, fmt.Errorf("search_max_retries cannot be negative"))
+		}
+	}
+
+	// Validate command tool config (if enabled)
+	if c.Command.Enabled {
+		if len(c.Command.AllowedCommands) == 0 {
+			result = multierror.Append(result, fmt.Errorf("command_tool_allowlist must not be empty when the command tool is enabled"))
+		}
+		if c.Command.Timeout <= 0 {
+			result = multierror.Append(result, fmt.Errorf("command_tool_timeout must be greater than 0"))
+		}
+		if c.Command.MaxOutputBytes <= 0 {
+			result = multierror.Append(result, fmt.Errorf("command_tool_max_output_bytes must be greater than 0"))
+		}
+	}
+
+	// Validate documents config (if enabled)
+	if c.Documents.Enabled {
+		if c.Documents.ChunkSize <= 0 {
+			result = multierror.Append(result, fmt.Errorf("documents_chunk_size must be greater than 0"))
+		}
+		if c.Documents.ChunkOverlap < 0 || c.Documents.ChunkOverlap >= c.Documents.ChunkSize {
+			result = multierror.Append(result, fmt.Errorf("documents_chunk_overlap must be between 0 and documents_chunk_size"))
+		}
+	}
+
+	// Validate quiet hours (if configured)
+	if c.QuietHours.Enabled() {
+		start, startErr := time.Parse("15:04", c.QuietHours.Start)
+		end, endErr := time.Parse("15:04", c.QuietHours.End)
+		if startErr != nil || endErr != nil {
+			result = multierror.Append(result, fmt.Errorf("quiet_hours start and end must both be HH:MM times, got %q and %q", c.QuietHours.Start, c.QuietHours.End))
+		} else if start.Equal(end) {
+			result = multierror.Append(result, fmt.Errorf("quiet_hours start and end must differ"))
+		}
+		if _, err := time.LoadLocation(c.QuietHours.Timezone); err != nil {
+			result = multierror.Append(result, fmt.Errorf("quiet_hours timezone is invalid: %w", err))
+		}
+		if c.QuietHours.Policy != "defer" && c.QuietHours.Policy != "suppress" {
+			result = multierror.Append(result, fmt.Errorf("quiet_hours policy must be 'defer' or 'suppress', got %q", c.QuietHours.Policy))
+		}
+	}
+
+	// Validate reminders (if enabled)
+	if c.Reminders.Enabled && c.Reminders.CheckInterval <= 0 {
+		result = multierror.Append(result, fmt.Errorf("reminders check_interval must be greater than 0"))
+	}
+
 	// Validate health config (if enabled)
 	if c.Health.Enabled {
 		if c.Health.Port < 1 || c.Health.Port > 65535 {
@@ -240,6 +612,10 @@ func (c *AppConfig) Validate() error {
 			result = multierror.Append(result, fmt.Errorf("health_failure_threshold must be greater than 0"))
 		}
 
+		if c.Health.StorageCheck && (c.Health.StorageMaxLatency <= 0 || c.Health.StorageInterval < 0) {
+			result = multierror.Append(result, fmt.Errorf("health_storage_max_latency must be greater than 0 and health_storage_interval cannot be negative"))
+		}
+
 		if c.Health.LivenessPath == "" {
 			result = multierror.Append(result, fmt.Errorf("health_liveness_path cannot be empty"))
 		}
@@ -281,6 +657,44 @@ func (c *AppConfig) IsDevelopment() bool {
 	return env == "development" || env == "dev"
 }
 
+// EnabledConnectors returns the names of the chat connectors the configuration enables,
+// i.e. everything that can receive and answer messages
+func (c *AppConfig) EnabledConnectors() []string {
+	var connectors []string
+	if c.Slack.Enabled() {
+		connectors = append(connectors, "slack")
+	}
+	if c.Telegram.Enabled() {
+		connectors = append(connectors, "telegram")
+	}
+	return connectors
+}
+
+// CheckConnectors returns an error if no connector is enabled, as the bot would have no
+// way to receive messages
+func (c *AppConfig) CheckConnectors() error {
+	if len(c.EnabledConnectors()) == 0 {
+		return fmt.Errorf("no connectors configured: please set environment variables for at least one platform (Slack or Telegram)")
+	}
+	return nil
+}
+
+// checkBaseURL checks an API base URL is empty (for the provider default) or an absolute
+// http(s) URL
+func checkBaseURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http or https URL, got %q", raw)
+	}
+	return nil
+}
+
 // GetAnthropicRetryConfig returns retry configuration for Anthropic client
 func (c *AppConfig) GetAnthropicRetryConfig() AnthropicRetryConfig {
 	return AnthropicRetryConfig{
@@ -290,6 +704,15 @@ func (c *AppConfig) GetAnthropicRetryConfig() AnthropicRetryConfig {
 	}
 }
 
+// GetGeminiRetryConfig returns retry configuration for Gemini client
+func (c *AppConfig) GetGeminiRetryConfig() GeminiRetryConfig {
+	return GeminiRetryConfig{
+		MaxRetries:     c.Gemini.MaxRetries,
+		InitialBackoff: c.Gemini.InitialBackoff,
+		MaxBackoff:     c.Gemini.MaxBackoff,
+	}
+}
+
 // GetLLMModel returns the model name for the configured LLM provider
 func (c *AppConfig) GetLLMModel() string {
 	switch strings.ToLower(c.LLM.Provider) {
@@ -297,11 +720,37 @@ func (c *AppConfig) GetLLMModel() string {
 		return c.Gemini.Model
 	case "openai":
 		return c.OpenAI.Model
+	case "azure_openai":
+		return c.AzureOpenAI.Deployment
 	default:
 		return c.Anthropic.Model
 	}
 }
 
+// SetLLMModel sets the model of the configured provider, the Azure OpenAI deployment for Azure
+func (c *AppConfig) SetLLMModel(name string) {
+	switch strings.ToLower(c.LLM.Provider) {
+	case "gemini":
+		c.Gemini.Model = name
+	case "openai":
+		c.OpenAI.Model = name
+	case "azure_openai":
+		c.AzureOpenAI.Deployment = name
+	default:
+		c.Anthropic.Model = name
+	}
+}
+
+// GetModelParams returns the default model parameters with the provider's
+// max tokens default filled in when none is configured
+func (c *AppConfig) GetModelParams() ModelParamsConfig {
+	params := c.LLM.Params
+	if params.MaxTokens == 0 {
+		params.MaxTokens = defaultMaxTokens(strings.ToLower(c.LLM.Provider))
+	}
+	return params
+}
+
 // LogConfig logs the current configuration (without sensitive data)
 func (c *AppConfig) LogConfig(log logger.Logger) {
 	// Count enabled MCP servers
@@ -320,6 +769,9 @@ func (c *AppConfig) LogConfig(log logger.Logger) {
 		logger.StringField("environment", c.Environment),
 		logger.StringField("llm_provider", c.LLM.Provider),
 		logger.StringField("llm_model", c.GetLLMModel()),
+		logger.IntField("llm_max_tokens", c.GetModelParams().MaxTokens),
+		logger.BoolField("llm_seed_set", c.LLM.Params.Seed != nil),
+		logger.BoolField("anthropic_thinking_enabled", c.Anthropic.ThinkingEnabled),
 		logger.StringField("log_level", c.Logging.Level),
 		logger.StringField("log_format", c.Logging.Format),
 		logger.BoolField("metrics_enabled", c.Monitoring.MetricsEnabled),
@@ -342,7 +794,8 @@ func (c *AppConfig) LogConfig(log logger.Logger) {
 
 	// Log Telegram configuration
 	if c.Telegram.Enabled() {
-		log.Info("Telegram integration enabled")
+		log.Info("Telegram integration enabled",
+			logger.StringField("mode", c.Telegram.Mode))
 	}
 
 	// Log search tool configuration
@@ -350,9 +803,53 @@ func (c *AppConfig) LogConfig(log logger.Logger) {
 		log.Info("Web search tool enabled")
 	}
 
+	// Log command tool configuration
+	if c.Command.Enabled {
+		log.Info("Command tool enabled",
+			logger.StringField("allowed_commands", strings.Join(c.Command.AllowedCommands, ", ")),
+			logger.DurationField("timeout", c.Command.Timeout),
+		)
+	}
+
+	// Log documents configuration
+	if c.Documents.Enabled {
+		log.Info("Document ingestion enabled",
+			logger.IntField("chunk_size", c.Documents.ChunkSize),
+			logger.IntField("chunk_overlap", c.Documents.ChunkOverlap),
+		)
+	}
+
+	// Log language configuration
+	if c.Language.DetectionEnabled {
+		log.Info("Language detection enabled")
+	}
+
+	// Log outbound TLS configuration; skipping verification is never meant for production
+	if c.Outbound.CAFile != "" {
+		log.Info("Trusting extra CA certificates for outbound requests",
+			logger.StringField("ca_file", c.Outbound.CAFile))
+	}
+	if !c.Logging.Redact {
+		log.Warn("Log redaction is disabled (LOG_REDACT); tokens and personal data in log fields are logged as given")
+	}
+	if c.Outbound.InsecureSkipVerify {
+		log.Warn("TLS CERTIFICATE VERIFICATION IS DISABLED for all outbound requests (OUTBOUND_INSECURE_SKIP_VERIFY). " +
+			"Connections can be intercepted; only use this in development.")
+	}
+
+	// Log outbound proxy configuration, without any credentials in the URL
+	if proxy, err := httpclient.ParseProxyURL(c.Outbound.ProxyURL); err == nil {
+		log.Info("Outbound proxy configured",
+			logger.StringField("proxy_url", proxy.Redacted()),
+			logger.StringField("no_proxy", c.Outbound.NoProxy))
+	}
+
 	// Log storage configuration
 	log.Info("Storage configured",
 		logger.StringField("backend", c.Storage.Backend),
+		logger.BoolField("degraded_mode", c.Storage.DegradedMode),
+		logger.IntField("retention_events", c.Storage.RetentionEvents),
+		logger.DurationField("retention_age", c.Storage.RetentionAge),
 	)
 
 	// Log health check configuration
@@ -364,6 +861,7 @@ func (c *AppConfig) LogConfig(log logger.Logger) {
 			logger.StringField("combined_path", c.Health.CombinedPath),
 			logger.DurationField("timeout", c.Health.Timeout),
 			logger.IntField("failure_threshold", c.Health.FailureThreshold),
+			logger.BoolField("storage_check", c.Health.StorageCheck),
 		)
 	}
 }
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConnectors(t *testing.T) {
+	tests := []struct {
+		name     string
+		slack    SlackConfig
+		telegram TelegramConfig
+		want     []string
+	}{
+		{
+			name: "none enabled",
+		},
+		{
+			name:  "slack needs both tokens",
+			slack: SlackConfig{BotToken: "xoxb-test"},
+		},
+		{
+			name:  "slack only",
+			slack: SlackConfig{BotToken: "xoxb-test", AppToken: "xapp-test"},
+			want:  []string{"slack"},
+		},
+		{
+			name:     "telegram only",
+			telegram: TelegramConfig{BotToken: "123:abc"},
+			want:     []string{"telegram"},
+		},
+		{
+			name:     "both",
+			slack:    SlackConfig{BotToken: "xoxb-test", AppToken: "xapp-test"},
+			telegram: TelegramConfig{BotToken: "123:abc"},
+			want:     []string{"slack", "telegram"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validAppConfig(ProviderClaude)
+			cfg.Slack = tt.slack
+			cfg.Telegram = tt.telegram
+
+			assert.Equal(t, tt.want, cfg.EnabledConnectors())
+			if tt.want == nil {
+				require.Error(t, cfg.CheckConnectors())
+			} else {
+				require.NoError(t, cfg.CheckConnectors())
+			}
+		})
+	}
+}
+
+func TestQuietHoursValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		quiet   QuietHoursConfig
+		wantErr string
+	}{
+		{name: "off"},
+		{
+			name:  "overnight window",
+			quiet: QuietHoursConfig{Start: "22:00", End: "07:00", Timezone: "Europe/London", Policy: "defer"},
+		},
+		{
+			name:    "missing end",
+			quiet:   QuietHoursConfig{Start: "22:00", Timezone: "UTC", Policy: "defer"},
+			wantErr: "HH:MM",
+		},
+		{
+			name:    "empty window",
+			quiet:   QuietHoursConfig{Start: "22:00", End: "22:00", Timezone: "UTC", Policy: "defer"},
+			wantErr: "must differ",
+		},
+		{
+			name:    "unknown timezone",
+			quiet:   QuietHoursConfig{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus", Policy: "defer"},
+			wantErr: "timezone",
+		},
+		{
+			name:    "unknown policy",
+			quiet:   QuietHoursConfig{Start: "22:00", End: "07:00", Timezone: "UTC", Policy: "queue"},
+			wantErr: "policy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validAppConfig(ProviderClaude)
+			cfg.QuietHours = tt.quiet
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDisplayTimezone(t *testing.T) {
+	cfg := validAppConfig(ProviderClaude)
+	cfg.Display = DisplayConfig{Timezone: "Europe/Berlin"}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, "Europe/Berlin", cfg.Display.Location().String())
+
+	cfg.Display = DisplayConfig{Timezone: "Not/AZone"}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "display_timezone")
+	assert.Equal(t, time.UTC, cfg.Display.Location(), "an invalid timezone should fall back to UTC")
+}
+
+func TestAgentRoutesValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		agents  []AgentDefinition
+		routes  []AgentRoute
+		wantErr string
+	}{
+		{name: "none"},
+		{
+			name:   "channel route",
+			agents: []AgentDefinition{{Name: "ops", Tools: []string{"run_command"}}},
+			routes: []AgentRoute{{Agent: "ops", Channels: []string{"C123"}}},
+		},
+		{
+			name:    "unnamed agent",
+			agents:  []AgentDefinition{{Description: "ops"}},
+			wantErr: "needs a name",
+		},
+		{
+			name:    "duplicate agent",
+			agents:  []AgentDefinition{{Name: "ops"}, {Name: "ops"}},
+			wantErr: "duplicate name",
+		},
+		{
+			name:    "unknown agent",
+			agents:  []AgentDefinition{{Name: "ops"}},
+			routes:  []AgentRoute{{Agent: "billing", Commands: []string{"/billing"}}},
+			wantErr: "unknown agent",
+		},
+		{
+			name:    "route without criteria",
+			agents:  []AgentDefinition{{Name: "ops"}},
+			routes:  []AgentRoute{{Agent: "ops"}},
+			wantErr: "needs channels, commands or a pattern",
+		},
+		{
+			name:    "invalid pattern",
+			agents:  []AgentDefinition{{Name: "ops"}},
+			routes:  []AgentRoute{{Agent: "ops", Pattern: "(unclosed"}},
+			wantErr: "invalid pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validAppConfig(ProviderClaude)
+			cfg.Agents = tt.agents
+			cfg.AgentRoutes = tt.routes
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+// DisplayConfig holds the defaults for presenting times and language to users
+type DisplayConfig struct {
+	// IANA timezone timestamps are shown in, e.g. "Europe/Berlin". A Slack user's profile
+	// timezone takes precedence.
+	Timezone string `env:"DISPLAY_TIMEZONE" yaml:"timezone" default:"UTC"`
+
+	// Locale that seeds the conversation language when the platform doesn't give the
+	// user's, e.g. "de-DE"; used with language detection
+	Locale string `env:"DISPLAY_LOCALE" yaml:"locale"`
+}
+
+// Location returns the display timezone, or UTC if it isn't a valid IANA name
+func (c DisplayConfig) Location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,10 @@
+package config
+
+// DocumentsConfig holds configuration for document ingestion and the search_documents tool.
+// When enabled, text files uploaded in Slack DMs or to the Telegram bot are chunked,
+// embedded and stored per user in the memory service.
+type DocumentsConfig struct {
+	Enabled      bool `env:"DOCUMENTS_ENABLED" yaml:"enabled" default:"false"`
+	ChunkSize    int  `env:"DOCUMENTS_CHUNK_SIZE" yaml:"chunk_size" default:"1000"`
+	ChunkOverlap int  `env:"DOCUMENTS_CHUNK_OVERLAP" yaml:"chunk_overlap" default:"200"`
+}
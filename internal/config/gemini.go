@@ -1,9 +1,24 @@
 package config
 
+import "time"
+
 // GeminiConfig holds Google Gemini-specific configuration
 type GeminiConfig struct {
 	APIKey  string `env:"GEMINI_API_KEY" yaml:"-"`
 	Model   string `env:"GEMINI_MODEL" yaml:"model" default:"gemini-2.5-flash"`
 	Project string `env:"GOOGLE_CLOUD_PROJECT" yaml:"project"` // Optional: for Vertex AI
 	Region  string `env:"GOOGLE_CLOUD_REGION" yaml:"region"`   // Optional: for Vertex AI
+
+	// Retries of failed API calls (rate limits, server errors, timeouts). A delay the API
+	// asks for with a rate limit error is waited instead of the backoff when it's longer.
+	MaxRetries     int           `env:"GEMINI_MAX_RETRIES" yaml:"max_retries" default:"3"`
+	InitialBackoff time.Duration `env:"GEMINI_INITIAL_BACKOFF" yaml:"initial_backoff" default:"1s"`
+	MaxBackoff     time.Duration `env:"GEMINI_MAX_BACKOFF" yaml:"max_backoff" default:"10s"`
+}
+
+// GeminiRetryConfig represents retry configuration for Gemini
+type GeminiRetryConfig struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
 }
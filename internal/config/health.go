@@ -9,6 +9,13 @@ type HealthConfig struct {
 	LivenessPath     string        `env:"HEALTH_LIVENESS_PATH" yaml:"liveness_path" default:"/health/live"`
 	ReadinessPath    string        `env:"HEALTH_READINESS_PATH" yaml:"readiness_path" default:"/health/ready"`
 	CombinedPath     string        `env:"HEALTH_COMBINED_PATH" yaml:"combined_path" default:"/health"`
+	VersionPath      string        `env:"HEALTH_VERSION_PATH" yaml:"version_path" default:"/version"`
 	Timeout          time.Duration `env:"HEALTH_TIMEOUT" yaml:"timeout" default:"10s"`
 	FailureThreshold int           `env:"HEALTH_FAILURE_THRESHOLD" yaml:"failure_threshold" default:"3"`
+
+	// Session storage readiness check: a write/read/delete round trip slower than
+	// StorageMaxLatency counts as a failure, and results are reused for StorageInterval
+	StorageCheck      bool          `env:"HEALTH_STORAGE_CHECK" yaml:"storage_check" default:"true"`
+	StorageMaxLatency time.Duration `env:"HEALTH_STORAGE_MAX_LATENCY" yaml:"storage_max_latency" default:"2s"`
+	StorageInterval   time.Duration `env:"HEALTH_STORAGE_INTERVAL" yaml:"storage_interval" default:"30s"`
 }
@@ -0,0 +1,23 @@
+package config
+
+// Oversized inbound message actions
+const (
+	InboundActionTruncate = "truncate"
+	InboundActionReject   = "reject"
+)
+
+// InboundConfig caps the length of incoming messages so long pastes don't blow the context
+// window. Messages over the limit are truncated with a note or rejected with RejectMessage.
+// Limits of 0 are disabled; the token limit is estimated from the character count.
+type InboundConfig struct {
+	MaxChars      int    `env:"INBOUND_MAX_CHARS" yaml:"max_chars" default:"0"`
+	MaxTokens     int    `env:"INBOUND_MAX_TOKENS" yaml:"max_tokens" default:"0"`
+	Action        string `env:"INBOUND_OVERSIZE_ACTION" yaml:"oversize_action" default:"truncate"` // truncate or reject
+	RejectMessage string `env:"INBOUND_REJECT_MESSAGE" yaml:"reject_message"`                      // Reply to rejected messages (a default is used if empty)
+
+	// Trim long quoted blocks ("> " quotes and ``` blocks) from messages over TrimThreshold
+	// characters, keeping TrimKeepLines lines of each, the user's own text and every link
+	TrimQuotes    bool `env:"INBOUND_TRIM_QUOTES" yaml:"trim_quotes" default:"false"`
+	TrimThreshold int  `env:"INBOUND_TRIM_THRESHOLD" yaml:"trim_threshold" default:"2000"`
+	TrimKeepLines int  `env:"INBOUND_TRIM_KEEP_LINES" yaml:"trim_keep_lines" default:"5"`
+}
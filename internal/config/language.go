@@ -0,0 +1,8 @@
+package config
+
+// LanguageConfig holds configuration for conversation language handling.
+// When detection is enabled, the language of each message is stored in the session and the
+// agent is asked to respond in it; the Slack user's locale or Telegram language code seeds it.
+type LanguageConfig struct {
+	DetectionEnabled bool `env:"LANGUAGE_DETECTION_ENABLED" yaml:"detection_enabled" default:"false"`
+}
@@ -5,10 +5,58 @@ const (
 	ProviderClaude = "claude"
 	ProviderGemini = "gemini"
 	ProviderOpenAI = "openai"
+	// ProviderAzureOpenAI serves OpenAI models from an Azure OpenAI deployment
+	ProviderAzureOpenAI = "azure_openai"
+)
+
+// Default max output tokens per provider, used when llm.params.max_tokens is unset
+const (
+	DefaultClaudeMaxTokens = 4096
+	DefaultOpenAIMaxTokens = 4096
+	DefaultGeminiMaxTokens = 8192
 )
 
 // LLMConfig holds LLM provider selection configuration
 type LLMConfig struct {
-	// Provider specifies which LLM provider to use: "claude", "gemini", or "openai"
+	// Provider specifies which LLM provider to use: "claude", "gemini", "openai", or "azure_openai"
 	Provider string `env:"LLM_PROVIDER" yaml:"provider" default:"claude"`
+
+	// Params holds default generation parameters applied to every request
+	Params ModelParamsConfig `yaml:"params"`
+
+	// ModelAliases maps friendly model names to canonical model IDs, adding to and
+	// overriding the built-in aliases for the configured provider
+	ModelAliases map[string]string `yaml:"model_aliases"`
+}
+
+// ModelParamsConfig holds default generation parameters for the LLM.
+// Temperature and TopP are pointers so an explicit 0 can be told apart from unset.
+type ModelParamsConfig struct {
+	Temperature   *float64 `env:"LLM_TEMPERATURE" yaml:"temperature"`
+	MaxTokens     int      `env:"LLM_MAX_TOKENS" yaml:"max_tokens"`
+	TopP          *float64 `env:"LLM_TOP_P" yaml:"top_p"`
+	StopSequences []string `env:"LLM_STOP_SEQUENCES" yaml:"stop_sequences"`
+	// Seed requests reproducible sampling from providers that support it (OpenAI, Gemini).
+	// Anthropic has no seed parameter and ignores it.
+	Seed *int `env:"LLM_SEED" yaml:"seed"`
+}
+
+// maxTemperature returns the highest temperature accepted by the given provider
+func maxTemperature(provider string) float64 {
+	if provider == ProviderClaude {
+		return 1.0
+	}
+	return 2.0
+}
+
+// defaultMaxTokens returns the default max output tokens for the given provider
+func defaultMaxTokens(provider string) int {
+	switch provider {
+	case ProviderGemini:
+		return DefaultGeminiMaxTokens
+	case ProviderOpenAI, ProviderAzureOpenAI:
+		return DefaultOpenAIMaxTokens
+	default:
+		return DefaultClaudeMaxTokens
+	}
 }
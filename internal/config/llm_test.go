@@ -0,0 +1,264 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validAppConfig returns an AppConfig that passes validation for the given provider.
+func validAppConfig(provider string) *AppConfig {
+	return &AppConfig{
+		RequestTimeout: 30 * time.Second,
+		LLM:            LLMConfig{Provider: provider},
+		Anthropic: AnthropicConfig{
+			APIKey:         "test-api-key",
+			Timeout:        30 * time.Second,
+			InitialBackoff: 1 * time.Second,
+			MaxBackoff:     10 * time.Second,
+		},
+		Gemini: GeminiConfig{APIKey: "test-api-key"},
+		OpenAI: OpenAIConfig{APIKey: "test-api-key"},
+		AzureOpenAI: AzureOpenAIConfig{
+			APIKey:     "test-api-key",
+			Endpoint:   "https://my-resource.openai.azure.com",
+			Deployment: "gpt-4o",
+			APIVersion: "2024-10-21",
+		},
+		Security: SecurityConfig{MaxRequestSize: 1024, RateLimitRPS: 1},
+		Logging:  LoggingConfig{Level: "info", Format: "json"},
+	}
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestModelParamsValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		provider    string
+		params      ModelParamsConfig
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:     "unset params are valid",
+			provider: ProviderClaude,
+			params:   ModelParamsConfig{},
+		},
+		{
+			name:     "zero temperature is valid",
+			provider: ProviderClaude,
+			params:   ModelParamsConfig{Temperature: floatPtr(0)},
+		},
+		{
+			name:        "claude temperature above 1",
+			provider:    ProviderClaude,
+			params:      ModelParamsConfig{Temperature: floatPtr(1.5)},
+			expectError: true,
+			errorMsg:    "llm_temperature must be between 0 and 1",
+		},
+		{
+			name:     "openai temperature up to 2",
+			provider: ProviderOpenAI,
+			params:   ModelParamsConfig{Temperature: floatPtr(1.5)},
+		},
+		{
+			name:        "gemini temperature above 2",
+			provider:    ProviderGemini,
+			params:      ModelParamsConfig{Temperature: floatPtr(2.5)},
+			expectError: true,
+			errorMsg:    "llm_temperature must be between 0 and 2",
+		},
+		{
+			name:        "negative temperature",
+			provider:    ProviderOpenAI,
+			params:      ModelParamsConfig{Temperature: floatPtr(-0.1)},
+			expectError: true,
+			errorMsg:    "llm_temperature must be between 0 and 2",
+		},
+		{
+			name:        "top_p above 1",
+			provider:    ProviderClaude,
+			params:      ModelParamsConfig{TopP: floatPtr(1.1)},
+			expectError: true,
+			errorMsg:    "llm_top_p must be between 0 and 1",
+		},
+		{
+			name:        "negative max tokens",
+			provider:    ProviderClaude,
+			params:      ModelParamsConfig{MaxTokens: -1},
+			expectError: true,
+			errorMsg:    "llm_max_tokens cannot be negative",
+		},
+		{
+			name:     "seed is valid",
+			provider: ProviderOpenAI,
+			params:   ModelParamsConfig{Seed: intPtr(42)},
+		},
+		{
+			name:        "seed out of 32-bit range",
+			provider:    ProviderOpenAI,
+			params:      ModelParamsConfig{Seed: intPtr(1 << 40)},
+			expectError: true,
+			errorMsg:    "llm_seed must fit in a 32-bit integer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validAppConfig(tt.provider)
+			cfg.LLM.Params = tt.params
+
+			err := cfg.Validate()
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestGetModelParams(t *testing.T) {
+	tests := []struct {
+		name          string
+		provider      string
+		maxTokens     int
+		wantMaxTokens int
+	}{
+		{"claude default", ProviderClaude, 0, DefaultClaudeMaxTokens},
+		{"openai default", ProviderOpenAI, 0, DefaultOpenAIMaxTokens},
+		{"gemini default", ProviderGemini, 0, DefaultGeminiMaxTokens},
+		{"explicit value kept", ProviderGemini, 1000, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validAppConfig(tt.provider)
+			cfg.LLM.Params.MaxTokens = tt.maxTokens
+
+			assert.Equal(t, tt.wantMaxTokens, cfg.GetModelParams().MaxTokens)
+		})
+	}
+}
+
+func TestAnthropicThinkingValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		budget      int
+		params      ModelParamsConfig
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:   "valid budget",
+			budget: 2048,
+		},
+		{
+			name:        "budget below minimum",
+			budget:      512,
+			expectError: true,
+			errorMsg:    "anthropic_thinking_budget must be at least 1024",
+		},
+		{
+			name:        "budget not below max tokens",
+			budget:      DefaultClaudeMaxTokens,
+			expectError: true,
+			errorMsg:    "anthropic_thinking_budget must be less than llm_max_tokens",
+		},
+		{
+			name:        "temperature set",
+			budget:      2048,
+			params:      ModelParamsConfig{Temperature: floatPtr(0.5)},
+			expectError: true,
+			errorMsg:    "llm_temperature cannot be set when anthropic extended thinking is enabled",
+		},
+		{
+			name:        "top_p too low",
+			budget:      2048,
+			params:      ModelParamsConfig{TopP: floatPtr(0.9)},
+			expectError: true,
+			errorMsg:    "llm_top_p must be at least 0.95",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validAppConfig(ProviderClaude)
+			cfg.LLM.Params = tt.params
+			cfg.Anthropic.ThinkingEnabled = true
+			cfg.Anthropic.ThinkingBudget = tt.budget
+
+			err := cfg.Validate()
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAzureOpenAIValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*AzureOpenAIConfig)
+		errorMsg string
+	}{
+		{
+			name:   "valid",
+			modify: func(*AzureOpenAIConfig) {},
+		},
+		{
+			name:     "missing api key",
+			modify:   func(c *AzureOpenAIConfig) { c.APIKey = "" },
+			errorMsg: "azure_openai_api_key is required",
+		},
+		{
+			name:     "missing endpoint",
+			modify:   func(c *AzureOpenAIConfig) { c.Endpoint = "" },
+			errorMsg: "azure_openai_endpoint is required",
+		},
+		{
+			name:     "relative endpoint",
+			modify:   func(c *AzureOpenAIConfig) { c.Endpoint = "my-resource.openai.azure.com" },
+			errorMsg: "azure_openai_endpoint",
+		},
+		{
+			name:     "missing deployment",
+			modify:   func(c *AzureOpenAIConfig) { c.Deployment = "" },
+			errorMsg: "azure_openai_deployment is required",
+		},
+		{
+			name:     "missing api version",
+			modify:   func(c *AzureOpenAIConfig) { c.APIVersion = "" },
+			errorMsg: "azure_openai_api_version is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validAppConfig(ProviderAzureOpenAI)
+			tt.modify(&cfg.AzureOpenAI)
+
+			err := cfg.Validate()
+			if tt.errorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "gpt-4o", cfg.GetLLMModel())
+			}
+		})
+	}
+}
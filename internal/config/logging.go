@@ -4,4 +4,13 @@ package config
 type LoggingConfig struct {
 	Level  string `env:"LOG_LEVEL" yaml:"level" default:"info"`
 	Format string `env:"LOG_FORMAT" yaml:"format" default:"json"`
+
+	// Mask sensitive log field values (tokens, API keys, emails). RedactKeys adds field keys
+	// to the built-in list; RedactValues also masks secret-looking text in any field.
+	Redact       bool     `env:"LOG_REDACT" yaml:"redact" default:"true"`
+	RedactKeys   []string `env:"LOG_REDACT_KEYS" yaml:"redact_keys"`
+	RedactValues bool     `env:"LOG_REDACT_VALUES" yaml:"redact_values" default:"false"`
+
+	// Log the type and full chain of wrapped errors alongside error messages, for debugging
+	ErrorDetail bool `env:"LOG_ERROR_DETAIL" yaml:"error_detail" default:"false"`
 }
@@ -0,0 +1,9 @@
+package config
+
+// MaintenanceConfig holds maintenance mode configuration. While maintenance mode is on the
+// bot stays connected but replies with Message instead of calling the model. It can be
+// toggled at runtime with the /maintenance admin command or by sending the process SIGUSR1.
+type MaintenanceConfig struct {
+	Enabled bool   `env:"MAINTENANCE_MODE" yaml:"enabled" default:"false"`
+	Message string `env:"MAINTENANCE_MESSAGE" yaml:"message"` // Reply while in maintenance (a default is used if empty)
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultOpenAIBaseURL is the OpenAI API endpoint; other endpoints are OpenAI-compatible
+// servers whose model names can't be checked
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// modelAliases maps friendly model names to canonical model IDs for each provider.
+// Aliases are matched case-insensitively; llm.model_aliases adds to and overrides them.
+var modelAliases = map[string]map[string]string{
+	ProviderClaude: {
+		"claude-sonnet-4.5": "claude-sonnet-4-5-20250929",
+		"claude-sonnet-4-5": "claude-sonnet-4-5-20250929",
+		"sonnet-4.5":        "claude-sonnet-4-5-20250929",
+		"sonnet":            "claude-sonnet-4-5-20250929",
+		"claude-sonnet-4":   "claude-sonnet-4-20250514",
+		"claude-opus-4.1":   "claude-opus-4-1-20250805",
+		"claude-opus-4-1":   "claude-opus-4-1-20250805",
+		"opus":              "claude-opus-4-1-20250805",
+		"claude-opus-4":     "claude-opus-4-20250514",
+		"claude-haiku-4.5":  "claude-haiku-4-5-20251001",
+		"claude-haiku-4-5":  "claude-haiku-4-5-20251001",
+		"haiku":             "claude-haiku-4-5-20251001",
+		"claude-3.5-haiku":  "claude-3-5-haiku-20241022",
+	},
+	ProviderOpenAI: {
+		"gpt4":    "gpt-4",
+		"gpt4o":   "gpt-4o",
+		"gpt-4-o": "gpt-4o",
+		"gpt4.1":  "gpt-4.1",
+	},
+	ProviderGemini: {
+		"gemini-flash":     "gemini-2.5-flash",
+		"gemini-pro":       "gemini-2.5-pro",
+		"gemini-2.5":       "gemini-2.5-flash",
+		"gemini-flash-2.5": "gemini-2.5-flash",
+		"gemini-pro-2.5":   "gemini-2.5-pro",
+	},
+}
+
+// modelPrefixes are the prefixes every model ID of a provider starts with
+var modelPrefixes = map[string][]string{
+	ProviderClaude: {"claude-"},
+	ProviderOpenAI: {"gpt-", "o1", "o3", "o4", "chatgpt-"},
+	ProviderGemini: {"gemini-"},
+}
+
+// ResolveModelAlias returns the canonical model ID for model under provider, checking
+// overrides before the built-in aliases. Names that aren't aliases pass through unchanged.
+// The returned warning is non-empty when the name looks wrong for the provider.
+func ResolveModelAlias(provider, model string, overrides map[string]string) (string, string) {
+	provider = strings.ToLower(provider)
+	key := strings.ToLower(strings.TrimSpace(model))
+
+	for alias, canonical := range overrides {
+		if strings.ToLower(strings.TrimSpace(alias)) == key {
+			return canonical, ""
+		}
+	}
+	if canonical, ok := modelAliases[provider][key]; ok {
+		return canonical, ""
+	}
+
+	return model, modelWarning(provider, model)
+}
+
+// modelWarning describes why a model name looks wrong for a provider, or returns ""
+func modelWarning(provider, model string) string {
+	if model != strings.TrimSpace(model) || strings.ContainsAny(model, " \t") {
+		return fmt.Sprintf("model %q contains whitespace", model)
+	}
+
+	prefixes, ok := modelPrefixes[provider]
+	if !ok || model == "" {
+		return ""
+	}
+
+	matched := false
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(strings.ToLower(model), prefix) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return fmt.Sprintf("model %q doesn't look like a %s model ID (expected a name starting with %s)",
+			model, provider, strings.Join(prefixes, ", "))
+	}
+
+	// Claude model IDs use dashes in version numbers (claude-sonnet-4-5), never dots
+	if provider == ProviderClaude && strings.Contains(model, ".") {
+		return fmt.Sprintf("model %q isn't a Claude model ID; versions are written with dashes (e.g. %s)",
+			model, modelAliases[ProviderClaude]["sonnet"])
+	}
+	return ""
+}
+
+// ResolveModelAliases replaces the configured provider's model with its canonical ID
+// and returns warnings for names that look wrong, to be logged at startup.
+func (c *AppConfig) ResolveModelAliases() []string {
+	provider := strings.ToLower(c.LLM.Provider)
+
+	var model *string
+	switch provider {
+	case ProviderClaude:
+		model = &c.Anthropic.Model
+	case ProviderGemini:
+		model = &c.Gemini.Model
+	case ProviderOpenAI:
+		model = &c.OpenAI.Model
+	default:
+		return nil
+	}
+
+	resolved, warning := ResolveModelAlias(provider, *model, c.LLM.ModelAliases)
+	*model = resolved
+
+	// OpenAI-compatible servers use their own model names
+	if provider == ProviderOpenAI && strings.TrimRight(c.OpenAI.APIBaseURL, "/") != defaultOpenAIBaseURL {
+		return nil
+	}
+	if warning == "" {
+		return nil
+	}
+	return []string{warning}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveModelAlias(t *testing.T) {
+	tests := []struct {
+		name        string
+		provider    string
+		model       string
+		overrides   map[string]string
+		want        string
+		wantWarning string
+	}{
+		{"claude dotted alias", ProviderClaude, "claude-sonnet-4.5", nil, "claude-sonnet-4-5-20250929", ""},
+		{"claude short alias is case-insensitive", ProviderClaude, "Opus", nil, "claude-opus-4-1-20250805", ""},
+		{"canonical claude id unchanged", ProviderClaude, "claude-sonnet-4-5-20250929", nil, "claude-sonnet-4-5-20250929", ""},
+		{"openai alias", ProviderOpenAI, "gpt4o", nil, "gpt-4o", ""},
+		{"gemini alias", ProviderGemini, "gemini-pro", nil, "gemini-2.5-pro", ""},
+		{"override wins over built-in", ProviderClaude, "sonnet", map[string]string{"SONNET": "claude-sonnet-4-20250514"}, "claude-sonnet-4-20250514", ""},
+		{"alias for another provider not applied", ProviderOpenAI, "sonnet", nil, "sonnet", "doesn't look like a openai model ID"},
+		{"unknown dotted claude name", ProviderClaude, "claude-opus-4.5-latest", nil, "claude-opus-4.5-latest", "versions are written with dashes"},
+		{"wrong provider's model", ProviderGemini, "gpt-4o", nil, "gpt-4o", "doesn't look like a gemini model ID"},
+		{"whitespace", ProviderOpenAI, "gpt-4 ", nil, "gpt-4 ", "contains whitespace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warning := ResolveModelAlias(tt.provider, tt.model, tt.overrides)
+			assert.Equal(t, tt.want, got)
+			if tt.wantWarning == "" {
+				assert.Empty(t, warning)
+			} else {
+				assert.Contains(t, warning, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestAppConfig_ResolveModelAliases(t *testing.T) {
+	t.Run("resolves the active provider's model", func(t *testing.T) {
+		cfg := validAppConfig(ProviderClaude)
+		cfg.Anthropic.Model = "haiku"
+		cfg.OpenAI.Model = "gpt4o"
+
+		assert.Empty(t, cfg.ResolveModelAliases())
+		assert.Equal(t, "claude-haiku-4-5-20251001", cfg.Anthropic.Model)
+		assert.Equal(t, "gpt4o", cfg.OpenAI.Model, "inactive providers are left alone")
+	})
+
+	t.Run("unknown alias passes through with a warning", func(t *testing.T) {
+		cfg := validAppConfig(ProviderGemini)
+		cfg.Gemini.Model = "flash"
+
+		warnings := cfg.ResolveModelAliases()
+		assert.Equal(t, "flash", cfg.Gemini.Model)
+		assert.Len(t, warnings, 1)
+	})
+
+	t.Run("no warning for OpenAI-compatible servers", func(t *testing.T) {
+		cfg := validAppConfig(ProviderOpenAI)
+		cfg.OpenAI.APIBaseURL = "http://localhost:11434/v1"
+		cfg.OpenAI.Model = "llama3"
+
+		assert.Empty(t, cfg.ResolveModelAliases())
+		assert.Equal(t, "llama3", cfg.OpenAI.Model)
+	})
+}
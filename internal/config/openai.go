@@ -8,5 +8,10 @@ type OpenAIConfig struct {
 	Model      string        `env:"OPENAI_MODEL" yaml:"model" default:"gpt-4"`
 	APIBaseURL string        `env:"OPENAI_API_URL" yaml:"api_base_url" default:"https://api.openai.com/v1"`
 	MaxRetries int           `env:"OPENAI_MAX_RETRIES" yaml:"max_retries" default:"3"`
-	Timeout    time.Duration `env:"OPENAI_TIMEOUT" yaml:"timeout" default:"30s"`
+	Timeout    time.Duration `env:"OPENAI_TIMEOUT" yaml:"timeout" default:"2m"` // Per attempt; must allow for long replies
+
+	// Optional OpenAI-Organization and OpenAI-Project headers, for accounts in several
+	// organizations or projects
+	Organization string `env:"OPENAI_ORGANIZATION" yaml:"organization"`
+	Project      string `env:"OPENAI_PROJECT" yaml:"project"`
 }
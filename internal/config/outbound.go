@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/httpclient"
+)
+
+// OutboundConfig configures the HTTP client shared by outbound requests: LLM APIs, tools,
+// MCP servers and the chat platforms
+type OutboundConfig struct {
+	// Proxy for all outbound requests, e.g. "http://proxy.corp:3128". When empty the
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are honored.
+	ProxyURL string `env:"OUTBOUND_PROXY_URL" yaml:"proxy_url"`
+	NoProxy  string `env:"OUTBOUND_NO_PROXY" yaml:"no_proxy"` // Hosts that bypass ProxyURL, in NO_PROXY syntax
+
+	// Extra CA certificates (PEM) to trust, e.g. for self-hosted LLM or MCP endpoints with a
+	// private CA. InsecureSkipVerify disables certificate checks entirely; development only.
+	CAFile             string `env:"OUTBOUND_CA_FILE" yaml:"ca_file"`
+	InsecureSkipVerify bool   `env:"OUTBOUND_INSECURE_SKIP_VERIFY" yaml:"insecure_skip_verify" default:"false"`
+
+	// Timeouts; Timeout bounds a whole request and must allow for slow LLM replies.
+	// Tools and connectors apply their own shorter limits.
+	Timeout               time.Duration `env:"OUTBOUND_TIMEOUT" yaml:"timeout" default:"10m"`
+	DialTimeout           time.Duration `env:"OUTBOUND_DIAL_TIMEOUT" yaml:"dial_timeout" default:"30s"`
+	TLSHandshakeTimeout   time.Duration `env:"OUTBOUND_TLS_HANDSHAKE_TIMEOUT" yaml:"tls_handshake_timeout" default:"10s"`
+	ResponseHeaderTimeout time.Duration `env:"OUTBOUND_RESPONSE_HEADER_TIMEOUT" yaml:"response_header_timeout" default:"0s"` // 0 for no limit
+
+	// Connection pooling
+	IdleConnTimeout     time.Duration `env:"OUTBOUND_IDLE_CONN_TIMEOUT" yaml:"idle_conn_timeout" default:"90s"`
+	MaxIdleConns        int           `env:"OUTBOUND_MAX_IDLE_CONNS" yaml:"max_idle_conns" default:"100"`
+	MaxIdleConnsPerHost int           `env:"OUTBOUND_MAX_IDLE_CONNS_PER_HOST" yaml:"max_idle_conns_per_host" default:"10"`
+}
+
+// NewHTTPClient builds the shared outbound client these settings describe
+func (c OutboundConfig) NewHTTPClient() (*http.Client, error) {
+	tlsConfig, err := httpclient.NewTLSConfig(httpclient.TLSOptions{
+		CAFile:             c.CAFile,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load outbound TLS config: %w", err)
+	}
+	client, err := httpclient.New(httpclient.Options{
+		TLSConfig:             tlsConfig,
+		ProxyURL:              c.ProxyURL,
+		NoProxy:               c.NoProxy,
+		Timeout:               c.Timeout,
+		DialTimeout:           c.DialTimeout,
+		TLSHandshakeTimeout:   c.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: c.ResponseHeaderTimeout,
+		IdleConnTimeout:       c.IdleConnTimeout,
+		MaxIdleConns:          c.MaxIdleConns,
+		MaxIdleConnsPerHost:   c.MaxIdleConnsPerHost,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+	return client, nil
+}
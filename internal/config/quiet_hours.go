@@ -0,0 +1,15 @@
+package config
+
+// QuietHoursConfig holds the do-not-disturb window for messages the bot sends on its own
+// initiative (reminders, broadcasts). Replies to users are unaffected.
+type QuietHoursConfig struct {
+	Start    string `env:"QUIET_HOURS_START" yaml:"start"`                     // HH:MM; quiet hours are off unless Start and End are set
+	End      string `env:"QUIET_HOURS_END" yaml:"end"`                         // HH:MM; may be earlier than Start to span midnight
+	Timezone string `env:"QUIET_HOURS_TIMEZONE" yaml:"timezone" default:"UTC"` // IANA timezone the window is in
+	Policy   string `env:"QUIET_HOURS_POLICY" yaml:"policy" default:"defer"`   // "defer" to send when the window ends, "suppress" to drop
+}
+
+// Enabled reports whether a quiet-hours window is configured
+func (c QuietHoursConfig) Enabled() bool {
+	return c.Start != "" || c.End != ""
+}
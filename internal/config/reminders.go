@@ -0,0 +1,9 @@
+package config
+
+import "time"
+
+// RemindersConfig holds configuration for reminders users set through the set_reminder tool
+type RemindersConfig struct {
+	Enabled       bool          `env:"REMINDERS_ENABLED" yaml:"enabled" default:"false"`
+	CheckInterval time.Duration `env:"REMINDERS_CHECK_INTERVAL" yaml:"check_interval" default:"30s"` // How often due reminders are sent
+}
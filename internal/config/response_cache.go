@@ -0,0 +1,29 @@
+package config
+
+import "time"
+
+// Response cache backends
+const (
+	ResponseCacheMemory = "memory"
+	ResponseCacheRedis  = "redis"
+)
+
+// ResponseCacheConfig holds response cache configuration. When enabled, a request identical
+// to an earlier one (same system prompt, parameters, recent history and message) is answered
+// from the cache instead of calling the model. Useful for FAQ-style deployments.
+type ResponseCacheConfig struct {
+	Enabled bool          `env:"RESPONSE_CACHE_ENABLED" yaml:"enabled" default:"false"`
+	TTL     time.Duration `env:"RESPONSE_CACHE_TTL" yaml:"ttl" default:"1h"`
+
+	// How many of the most recent messages are part of the cache key (0 for the whole conversation)
+	HistoryWindow int `env:"RESPONSE_CACHE_HISTORY_WINDOW" yaml:"history_window" default:"5"`
+
+	// Backend is "memory" (per process) or "redis" (shared and persistent)
+	Backend    string `env:"RESPONSE_CACHE_BACKEND" yaml:"backend" default:"memory"`
+	MaxEntries int    `env:"RESPONSE_CACHE_MAX_ENTRIES" yaml:"max_entries" default:"1000"` // Memory backend only
+
+	// Redis backend settings
+	RedisAddr     string `env:"RESPONSE_CACHE_REDIS_ADDR" yaml:"redis_addr"`
+	RedisPassword string `env:"RESPONSE_CACHE_REDIS_PASSWORD" yaml:"redis_password"`
+	RedisDB       int    `env:"RESPONSE_CACHE_REDIS_DB" yaml:"redis_db" default:"0"`
+}
@@ -7,6 +7,18 @@ type SearchConfig struct {
 	APIKey  string        `env:"SEARCHAPI_API_KEY" yaml:"-"`
 	BaseURL string        `env:"SEARCH_API_URL" yaml:"base_url" default:"https://www.searchapi.io"`
 	Timeout time.Duration `env:"SEARCH_TIMEOUT" yaml:"timeout" default:"30s"`
+	// MaxResults is the default and maximum number of results per search (1-100)
+	MaxResults int `env:"SEARCH_MAX_RESULTS" yaml:"max_results" default:"10"`
+	// SnippetChars truncates each result snippet so searches don't flood the context window
+	SnippetChars int `env:"SEARCH_SNIPPET_CHARS" yaml:"snippet_chars" default:"500"`
+	// Identical queries within CacheTTL are answered from a bounded in-memory cache
+	CacheTTL  time.Duration `env:"SEARCH_CACHE_TTL" yaml:"cache_ttl" default:"5m"`
+	CacheSize int           `env:"SEARCH_CACHE_SIZE" yaml:"cache_size" default:"100"`
+	// Rate-limited (429) and 5xx responses are retried with exponential backoff
+	MaxRetries   int           `env:"SEARCH_MAX_RETRIES" yaml:"max_retries" default:"2"`
+	RetryBackoff time.Duration `env:"SEARCH_RETRY_BACKOFF" yaml:"retry_backoff" default:"500ms"`
+	// Citations appends a "Sources" footer listing the search results the answer drew on
+	Citations bool `env:"SEARCH_CITATIONS_ENABLED" yaml:"citations_enabled"`
 }
 
 // Enabled returns true if the search API is configured with an API key
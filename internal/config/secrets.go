@@ -0,0 +1,76 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/secrets"
+)
+
+// Secret providers
+const (
+	SecretsProviderEnv   = "env"
+	SecretsProviderAWS   = "aws"
+	SecretsProviderVault = "vault"
+)
+
+// SecretsConfig selects where secret://name references in config values are resolved from.
+// Any string setting (API keys, tokens, URLs) can hold a reference; see package secrets.
+type SecretsConfig struct {
+	Provider string `env:"SECRETS_PROVIDER" yaml:"provider" default:"env"` // "env", "aws" or "vault"
+
+	// AWS Secrets Manager; credentials come from the default AWS credential chain
+	AWSRegion  string `env:"SECRETS_AWS_REGION" yaml:"aws_region"`
+	AWSProfile string `env:"SECRETS_AWS_PROFILE" yaml:"aws_profile"`
+
+	// HashiCorp Vault KV version 2 engine
+	VaultAddress string `env:"SECRETS_VAULT_ADDR" yaml:"vault_address"`
+	VaultToken   string `env:"SECRETS_VAULT_TOKEN" yaml:"vault_token"`
+	VaultMount   string `env:"SECRETS_VAULT_MOUNT" yaml:"vault_mount" default:"secret"`
+}
+
+// SecretProvider returns the provider secret references are resolved with. The config
+// loader calls it after loading the configuration and before validating it. Remote
+// providers use the outbound HTTP client, so its settings (proxy, CA file) can't themselves
+// be secret references.
+func (c *AppConfig) SecretProvider(ctx context.Context) (secrets.Provider, error) {
+	switch strings.ToLower(c.Secrets.Provider) {
+	case "", SecretsProviderEnv:
+		return secrets.EnvProvider{}, nil
+
+	case SecretsProviderAWS:
+		client, err := c.Outbound.NewHTTPClient()
+		if err != nil {
+			return nil, err
+		}
+		var options []func(*awsconfig.LoadOptions) error
+		if c.Secrets.AWSProfile != "" {
+			options = append(options, awsconfig.WithSharedConfigProfile(c.Secrets.AWSProfile))
+		}
+		if c.Secrets.AWSRegion != "" {
+			options = append(options, awsconfig.WithRegion(c.Secrets.AWSRegion))
+		}
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, options...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		return secrets.NewAWSProvider(awsCfg, secrets.WithAWSHTTPClient(client)), nil
+
+	case SecretsProviderVault:
+		client, err := c.Outbound.NewHTTPClient()
+		if err != nil {
+			return nil, err
+		}
+		return secrets.NewVaultProvider(secrets.VaultConfig{
+			Address:    c.Secrets.VaultAddress,
+			Token:      c.Secrets.VaultToken,
+			Mount:      c.Secrets.VaultMount,
+			HTTPClient: client,
+		})
+
+	default:
+		return nil, fmt.Errorf("secrets_provider must be 'env', 'aws' or 'vault', got %q", c.Secrets.Provider)
+	}
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretProvider_VaultUsesOutboundClient(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		_, _ = w.Write([]byte(`{"data": {"data": {"value": "s3cret"}}}`))
+	}))
+	defer proxy.Close()
+
+	cfg := &AppConfig{
+		Secrets: SecretsConfig{
+			Provider:     SecretsProviderVault,
+			VaultAddress: "http://vault.internal:8200",
+			VaultToken:   "token",
+			VaultMount:   "secret",
+		},
+		Outbound: OutboundConfig{ProxyURL: proxy.URL, Timeout: 5 * time.Second},
+	}
+	provider, err := cfg.SecretProvider(context.Background())
+	require.NoError(t, err)
+
+	value, err := provider.GetSecret(context.Background(), "chatbot/slack", "")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+	assert.Equal(t, []string{"http://vault.internal:8200/v1/secret/data/chatbot/slack"}, proxied,
+		"the request should go through the outbound proxy")
+}
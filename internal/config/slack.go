@@ -1,10 +1,78 @@
 package config
 
+import "time"
+
 // SlackConfig holds Slack-specific configuration
 type SlackConfig struct {
 	BotToken string `env:"SLACK_BOT_TOKEN" yaml:"-"`
 	AppToken string `env:"SLACK_APP_TOKEN" yaml:"-"`
 	Debug    bool   `env:"SLACK_DEBUG" yaml:"debug"`
+
+	// Agent persona used for Slack conversations
+	AgentName        string `env:"SLACK_AGENT_NAME" yaml:"agent_name" default:"slack_assistant"`
+	AgentDescription string `env:"SLACK_AGENT_DESCRIPTION" yaml:"agent_description" default:"AI assistant for Slack with MCP capabilities"`
+	AgentPersona     string `env:"SLACK_AGENT_PERSONA" yaml:"agent_persona"` // Optional extra instructions for Slack
+
+	// Name and short introduction the bot gives users on Slack, e.g. "Support Bot", used in
+	// greetings, /help and get_agent_info and added to the system prompt
+	DisplayName string `env:"SLACK_DISPLAY_NAME" yaml:"display_name"`
+	Intro       string `env:"SLACK_INTRO" yaml:"intro"`
+
+	// Prefixes the bot adds to its own messages, stripped when its replies are used as thread context
+	SelfPrefixes []string `env:"SLACK_SELF_PREFIXES" yaml:"self_prefixes"`
+
+	// Optional text added to every response, e.g. an "AI-generated" disclaimer. Both are Go
+	// templates with {{.BotName}} (the agent name) and {{.Platform}} available.
+	ResponsePrefix           string `env:"SLACK_RESPONSE_PREFIX" yaml:"response_prefix"`
+	ResponseSuffix           string `env:"SLACK_RESPONSE_SUFFIX" yaml:"response_suffix"`
+	ResponseSuffixEveryChunk bool   `env:"SLACK_RESPONSE_SUFFIX_EVERY_CHUNK" yaml:"response_suffix_every_chunk" default:"false"` // Suffix every message of a split response
+
+	// User IDs allowed to run admin commands such as /maintenance
+	AdminUsers []string `env:"SLACK_ADMIN_USERS" yaml:"admin_users"`
+
+	// Show slash command replies and error notices only to the user they're for, keeping
+	// channels clean; otherwise they're posted in the channel for everyone
+	EphemeralCommands bool `env:"SLACK_EPHEMERAL_COMMANDS" yaml:"ephemeral_commands" default:"true"`
+	EphemeralErrors   bool `env:"SLACK_EPHEMERAL_ERRORS" yaml:"ephemeral_errors" default:"false"`
+
+	// Set the bot's presence to active when it connects and away when it shuts down, so users
+	// can see whether it's up. Needs the users:write scope.
+	Presence bool `env:"SLACK_PRESENCE" yaml:"presence" default:"false"`
+
+	// Answer in Slack's assistant pane, each thread its own conversation, greeting new threads
+	// with AssistantWelcome (the intro if empty) and offering AssistantPrompts as suggested
+	// prompts. AppHome shows the same on the app's Home tab. Needs the assistant:write scope
+	// and the assistant_thread_started (and app_home_opened) events.
+	Assistant        bool     `env:"SLACK_ASSISTANT" yaml:"assistant" default:"false"`
+	AssistantWelcome string   `env:"SLACK_ASSISTANT_WELCOME" yaml:"assistant_welcome"`
+	AssistantPrompts []string `env:"SLACK_ASSISTANT_PROMPTS" yaml:"assistant_prompts"`
+	AppHome          bool     `env:"SLACK_APP_HOME" yaml:"app_home" default:"false"`
+
+	// Channel IDs where the bot responds to every message, not only @mentions (e.g. a support
+	// channel). The Slack app must subscribe to message.channels / message.groups events.
+	AlwaysRespondChannels []string `env:"SLACK_ALWAYS_RESPOND_CHANNELS" yaml:"always_respond_channels"`
+
+	// Prefixes such as "!ai" that address the bot in any channel without an @mention; the
+	// prefix is removed from the message. Per-channel prefixes replace them in those channels
+	// (YAML only), e.g. {C0123ABCD: ["!ops"]}. Needs the same message events as above.
+	TriggerPrefixes        []string            `env:"SLACK_TRIGGER_PREFIXES" yaml:"trigger_prefixes"`
+	ChannelTriggerPrefixes map[string][]string `yaml:"channel_trigger_prefixes"`
+
+	// Reconnection with exponential backoff when the Socket Mode connection drops. After
+	// ReconnectMaxRetries consecutive failures the server shuts down so it can be restarted.
+	ReconnectInitialBackoff time.Duration `env:"SLACK_RECONNECT_INITIAL_BACKOFF" yaml:"reconnect_initial_backoff" default:"1s"`
+	ReconnectMaxBackoff     time.Duration `env:"SLACK_RECONNECT_MAX_BACKOFF" yaml:"reconnect_max_backoff" default:"2m"`
+	ReconnectMaxRetries     int           `env:"SLACK_RECONNECT_MAX_RETRIES" yaml:"reconnect_max_retries" default:"10"`
+
+	// User display names are cached to avoid repeated API calls; the oldest are evicted past
+	// UserCacheSize and each is looked up again after UserCacheTTL, picking up renames
+	UserCacheSize int           `env:"SLACK_USER_CACHE_SIZE" yaml:"user_cache_size" default:"1000"`
+	UserCacheTTL  time.Duration `env:"SLACK_USER_CACHE_TTL" yaml:"user_cache_ttl" default:"1h"`
+
+	// Give the agent the channel's name, topic, purpose and member count with channel messages,
+	// looked up again after ChannelContextTTL. Needs the channels:read (and groups:read) scope.
+	ChannelContext    bool          `env:"SLACK_CHANNEL_CONTEXT" yaml:"channel_context" default:"false"`
+	ChannelContextTTL time.Duration `env:"SLACK_CHANNEL_CONTEXT_TTL" yaml:"channel_context_ttl" default:"10m"`
 }
 
 // Enabled returns true if Slack is configured with both tokens
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // StorageConfig holds storage/persistence configuration
 type StorageConfig struct {
 	Backend   string `env:"STORAGE_BACKEND" yaml:"backend" default:"local"`      // "local" or "s3"
@@ -8,4 +10,25 @@ type StorageConfig struct {
 	S3Prefix  string `env:"STORAGE_S3_PREFIX" yaml:"s3_prefix"`                  // S3 object key prefix (optional)
 	S3Region  string `env:"STORAGE_S3_REGION" yaml:"s3_region"`                  // AWS region
 	S3Profile string `env:"STORAGE_S3_PROFILE" yaml:"s3_profile"`                // AWS profile name (optional)
+
+	// Write sessions as compact JSON instead of indented JSON to save storage
+	CompactJSON bool `env:"STORAGE_COMPACT_JSON" yaml:"compact_json" default:"false"`
+
+	// How many session files are loaded at once when listing sessions; higher values list
+	// faster against remote backends such as S3
+	ListConcurrency int `env:"STORAGE_LIST_CONCURRENCY" yaml:"list_concurrency" default:"8"`
+
+	// Keep answering while storage is briefly unavailable: a turn whose session can't be
+	// loaded runs without the conversation's history, and turns that can't be saved are
+	// retried in the background. A turn still unsaved after the retries is lost.
+	DegradedMode         bool          `env:"STORAGE_DEGRADED_MODE" yaml:"degraded_mode" default:"false"`
+	DegradedRetries      int           `env:"STORAGE_DEGRADED_RETRIES" yaml:"degraded_retries" default:"5"`
+	DegradedRetryBackoff time.Duration `env:"STORAGE_DEGRADED_RETRY_BACKOFF" yaml:"degraded_retry_backoff" default:"2s"`
+
+	// Limit the events stored per session, pruning the oldest as new ones are appended;
+	// 0 disables each limit. Pruned events are moved to the "sessions_archive" namespace
+	// when RetentionArchive is set, and deleted otherwise.
+	RetentionEvents  int           `env:"STORAGE_RETENTION_EVENTS" yaml:"retention_events" default:"0"`
+	RetentionAge     time.Duration `env:"STORAGE_RETENTION_AGE" yaml:"retention_age" default:"0"`
+	RetentionArchive bool          `env:"STORAGE_RETENTION_ARCHIVE" yaml:"retention_archive" default:"false"`
 }
@@ -1,12 +1,58 @@
 package config
 
+import "strings"
+
+// Telegram update delivery modes
+const (
+	TelegramModePoll    = "poll"    // Long-poll getUpdates
+	TelegramModeWebhook = "webhook" // Telegram POSTs updates to the health server
+)
+
 // TelegramConfig holds Telegram-specific configuration
 type TelegramConfig struct {
 	BotToken string `env:"TELEGRAM_BOT_TOKEN" yaml:"-"`
 	Debug    bool   `env:"TELEGRAM_DEBUG" yaml:"debug"`
+
+	// Agent persona used for Telegram conversations
+	AgentName        string `env:"TELEGRAM_AGENT_NAME" yaml:"agent_name" default:"telegram_assistant"`
+	AgentDescription string `env:"TELEGRAM_AGENT_DESCRIPTION" yaml:"agent_description" default:"AI assistant for Telegram with MCP capabilities"`
+	AgentPersona     string `env:"TELEGRAM_AGENT_PERSONA" yaml:"agent_persona"` // Optional extra instructions for Telegram
+
+	// Name and short introduction the bot gives users on Telegram, e.g. "Support Bot", used in
+	// greetings, /help and get_agent_info and added to the system prompt
+	DisplayName string `env:"TELEGRAM_DISPLAY_NAME" yaml:"display_name"`
+	Intro       string `env:"TELEGRAM_INTRO" yaml:"intro"`
+
+	// How updates are received: "poll" (long polling) or "webhook". Webhook mode registers
+	// WebhookURL with Telegram and serves WebhookPath on the health server; WebhookURL must
+	// route there. Telegram sends WebhookSecret with every update so forged ones are rejected.
+	Mode          string `env:"TELEGRAM_MODE" yaml:"mode" default:"poll"`
+	WebhookURL    string `env:"TELEGRAM_WEBHOOK_URL" yaml:"webhook_url"`
+	WebhookPath   string `env:"TELEGRAM_WEBHOOK_PATH" yaml:"webhook_path" default:"/telegram/webhook"`
+	WebhookSecret string `env:"TELEGRAM_WEBHOOK_SECRET" yaml:"-"`
+
+	// Numeric user IDs allowed to run admin commands such as /maintenance
+	AdminUsers []string `env:"TELEGRAM_ADMIN_USERS" yaml:"admin_users"`
+
+	// Prefixes such as "!ai" that group chat messages must start with for the bot to answer;
+	// the prefix is removed from the message. Per-chat prefixes replace them in those chats
+	// (YAML only), e.g. {"-1001234567890": ["!ops"]}. Without any, every message is answered.
+	TriggerPrefixes     []string            `env:"TELEGRAM_TRIGGER_PREFIXES" yaml:"trigger_prefixes"`
+	ChatTriggerPrefixes map[string][]string `yaml:"chat_trigger_prefixes"`
+
+	// Optional text added to every response, e.g. an "AI-generated" disclaimer. Both are Go
+	// templates with {{.BotName}} (the agent name) and {{.Platform}} available.
+	ResponsePrefix           string `env:"TELEGRAM_RESPONSE_PREFIX" yaml:"response_prefix"`
+	ResponseSuffix           string `env:"TELEGRAM_RESPONSE_SUFFIX" yaml:"response_suffix"`
+	ResponseSuffixEveryChunk bool   `env:"TELEGRAM_RESPONSE_SUFFIX_EVERY_CHUNK" yaml:"response_suffix_every_chunk" default:"false"` // Suffix every message of a split response
 }
 
 // Enabled returns true if Telegram is configured with a bot token
 func (c *TelegramConfig) Enabled() bool {
 	return c.BotToken != ""
 }
+
+// WebhookEnabled returns true if updates are received through a webhook instead of polling
+func (c *TelegramConfig) WebhookEnabled() bool {
+	return strings.EqualFold(c.Mode, TelegramModeWebhook)
+}
@@ -0,0 +1,12 @@
+package config
+
+// ThrottleConfig paces the messages the bot sends so bursts, such as long split replies or
+// many reminders at once, stay within Slack's and Telegram's rate limits. This is separate
+// from limiting what users send. Each connector has its own limits; rates are messages per
+// second, and a rate of 0 disables that limit.
+type ThrottleConfig struct {
+	GlobalRate   float64 `env:"THROTTLE_GLOBAL_RATE" yaml:"global_rate" default:"20"`
+	GlobalBurst  int     `env:"THROTTLE_GLOBAL_BURST" yaml:"global_burst" default:"20"`
+	ChannelRate  float64 `env:"THROTTLE_CHANNEL_RATE" yaml:"channel_rate" default:"1"`
+	ChannelBurst int     `env:"THROTTLE_CHANNEL_BURST" yaml:"channel_burst" default:"5"`
+}
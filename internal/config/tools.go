@@ -0,0 +1,32 @@
+package config
+
+import "time"
+
+// ToolsConfig holds settings shared by all agent tools
+type ToolsConfig struct {
+	// How long a single tool call may run before it's abandoned with an error result (0 for no limit)
+	Timeout time.Duration `env:"TOOL_TIMEOUT" yaml:"timeout" default:"60s"`
+
+	// Per-tool timeouts by tool name, e.g. {web_search: 20s, mcp__github__search_code: 2m} (YAML only)
+	TimeoutOverrides map[string]time.Duration `yaml:"timeout_overrides"`
+
+	// Show users a status line such as "Searching the web…" while the agent uses tools (Slack)
+	Disclosure bool `env:"TOOL_USE_DISCLOSURE" yaml:"disclosure" default:"false"`
+
+	// How many delegations deep agents may hand tasks to the named agents with delegate_to_agent
+	// (0 disables the tool); 1 lets only the agent users talk to delegate
+	DelegationMaxDepth int `env:"TOOL_DELEGATION_MAX_DEPTH" yaml:"delegation_max_depth" default:"1"`
+
+	// How long a delegate_to_agent call may run (0 for no limit). It's kept apart from Timeout
+	// because the delegated agent makes its own model and tool calls, each already limited.
+	// A timeout_overrides entry for delegate_to_agent takes precedence.
+	DelegationTimeout time.Duration `env:"TOOL_DELEGATION_TIMEOUT" yaml:"delegation_timeout" default:"0s"`
+
+	// How tool calls and results from turns before the last HistoryTurns are sent to the model:
+	// "all" in full, "summarize" as a short line of text, or "omit" to leave them out. The
+	// user's and agent's messages are always sent. With "summarize" or "omit" a turn is
+	// rewritten once it falls out of the last HistoryTurns, so the history changes from
+	// request to request and prompt caching only reuses the part before the earliest rewrite.
+	History      string `env:"TOOL_HISTORY" yaml:"history" default:"all"`
+	HistoryTurns int    `env:"TOOL_HISTORY_TURNS" yaml:"history_turns" default:"3"`
+}
@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// DefaultBudgetMessage is the reply sent once a conversation has used up its budget
+const DefaultBudgetMessage = "This conversation has reached its usage limit. Please start a new conversation to continue."
+
+// Session state keys holding what a conversation has spent so far
+const (
+	BudgetTokensStateKey = "budget_tokens"
+	BudgetCostStateKey   = "budget_cost"
+)
+
+// SessionBudget caps the tokens, or the estimated cost, one conversation can use so a
+// runaway conversation can't run up the bill. Usage reported by the model is added up in
+// session state after each turn; once it reaches a limit the conversation is answered with
+// the budget message without calling the model; a new conversation gets a fresh budget.
+type SessionBudget struct {
+	MaxTokens int     // Input and output tokens per conversation; 0 for no token limit
+	MaxCost   float64 // Estimated cost per conversation; 0 for no cost limit
+
+	// Prices per million tokens the cost is estimated with, in the same currency as MaxCost
+	InputCostPerMillion  float64
+	OutputCostPerMillion float64
+
+	Message string // Reply once the budget is used up; DefaultBudgetMessage if empty
+}
+
+// BudgetUsage is what a conversation has spent
+type BudgetUsage struct {
+	Tokens int
+	Cost   float64
+}
+
+// enabled reports whether any limit is set
+func (b SessionBudget) enabled() bool {
+	return b.MaxTokens > 0 || b.MaxCost > 0
+}
+
+// exceeded reports whether usage has reached a limit
+func (b SessionBudget) exceeded(usage BudgetUsage) bool {
+	return (b.MaxTokens > 0 && usage.Tokens >= b.MaxTokens) || (b.MaxCost > 0 && usage.Cost >= b.MaxCost)
+}
+
+// message returns the reply sent once the budget is used up
+func (b SessionBudget) message() string {
+	if b.Message != "" {
+		return b.Message
+	}
+	return DefaultBudgetMessage
+}
+
+// add returns usage with a model response's reported usage added. Thinking tokens are billed
+// as output.
+func (b SessionBudget) add(usage BudgetUsage, metadata *genai.GenerateContentResponseUsageMetadata) BudgetUsage {
+	if metadata == nil {
+		return usage
+	}
+	input := int(metadata.PromptTokenCount)
+	output := int(metadata.CandidatesTokenCount) + int(metadata.ThoughtsTokenCount)
+	usage.Tokens += input + output
+	usage.Cost += (float64(input)*b.InputCostPerMillion + float64(output)*b.OutputCostPerMillion) / 1e6
+	return usage
+}
+
+// budgetUsage reads what a conversation has spent from its session state
+func budgetUsage(state session.ReadonlyState) BudgetUsage {
+	if state == nil {
+		return BudgetUsage{}
+	}
+	return BudgetUsage{
+		Tokens: int(stateNumber(state, BudgetTokensStateKey)),
+		Cost:   stateNumber(state, BudgetCostStateKey),
+	}
+}
+
+// stateNumber returns a number from session state, which is a float64 once the state has
+// been stored as JSON and read back, or 0 if it isn't set
+func stateNumber(state session.ReadonlyState, key string) float64 {
+	value, err := state.Get(key)
+	if err != nil {
+		return 0
+	}
+	switch n := value.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	default:
+		return 0
+	}
+}
+
+// budgetDelta returns the state change recording usage
+func budgetDelta(usage BudgetUsage) map[string]any {
+	return map[string]any{BudgetTokensStateKey: usage.Tokens, BudgetCostStateKey: usage.Cost}
+}
@@ -0,0 +1,168 @@
+package executor_test
+
+import (
+	"context"
+	"io"
+	"iter"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// meteredModel answers "ok", reporting 400 input and 100 output tokens for each call
+type meteredModel struct {
+	calls int
+}
+
+func (m *meteredModel) Name() string { return "fake-model" }
+
+func (m *meteredModel) GenerateContent(context.Context, *model.LLMRequest, bool) iter.Seq2[*model.LLMResponse, error] {
+	m.calls++
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{
+			Content: genai.NewContentFromText("ok", genai.RoleModel),
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+				PromptTokenCount:     400,
+				CandidatesTokenCount: 100,
+				TotalTokenCount:      500,
+			},
+		}, nil)
+	}
+}
+
+func newBudgetExecutor(t *testing.T, sessions session.Service, budget executor.SessionBudget) (*executor.Executor, *meteredModel) {
+	t.Helper()
+	llm := &meteredModel{}
+	return newTestExecutor(t, llm, executor.Config{SessionService: sessions, Budget: budget}), llm
+}
+
+// send executes a message in session s1 and returns the reply
+func send(t *testing.T, exec *executor.Executor) string {
+	t.Helper()
+	return sendTo(t, exec, "s1")
+}
+
+// sendTo executes a message in the given session and returns the reply
+func sendTo(t *testing.T, exec *executor.Executor, sessionID string) string {
+	t.Helper()
+	resp, err := exec.Execute(context.Background(), executor.MessageRequest{UserID: "u1", SessionID: sessionID, Message: "hello"}, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	return resp.Text
+}
+
+// storedUsage returns the budget usage recorded in session s1's state, or nil for a key
+// that isn't set
+func storedUsage(t *testing.T, sessions session.Service) (tokens, cost any) {
+	t.Helper()
+	resp, err := sessions.Get(context.Background(), &session.GetRequest{AppName: "test", UserID: "u1", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	tokens, _ = resp.Session.State().Get(executor.BudgetTokensStateKey)
+	cost, _ = resp.Session.State().Get(executor.BudgetCostStateKey)
+	return tokens, cost
+}
+
+// number converts a stored state value to float64, which it is once read back from JSON
+func number(value any) float64 {
+	switch n := value.(type) {
+	case int:
+		return float64(n)
+	case float64:
+		return n
+	default:
+		return -1
+	}
+}
+
+func TestExecute_BudgetCutoffAndNewConversation(t *testing.T) {
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+	tests := []struct {
+		name     string
+		sessions session.Service
+	}{
+		{"in memory", session.InMemoryService()},
+		// Stored state comes back from JSON with its numbers as float64
+		{"stored", session_manager.NewSessionService(storage_manager.NewLocalFileProvider(t.TempDir()), log)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec, llm := newBudgetExecutor(t, tt.sessions, executor.SessionBudget{MaxTokens: 1000, Message: "Out of budget."})
+
+			// Two turns of 500 tokens each use up the budget
+			for turn := 1; turn <= 2; turn++ {
+				if got := send(t, exec); got != "ok" {
+					t.Fatalf("turn %d reply = %q, want %q", turn, got, "ok")
+				}
+			}
+			if tokens, _ := storedUsage(t, tt.sessions); number(tokens) != 1000 {
+				t.Errorf("recorded tokens = %v, want 1000", tokens)
+			}
+
+			if got := send(t, exec); got != "Out of budget." {
+				t.Errorf("reply over budget = %q, want the budget message", got)
+			}
+			if llm.calls != 2 {
+				t.Errorf("model called %d times, want 2 (none once over budget)", llm.calls)
+			}
+
+			// A new conversation gets a fresh budget
+			if got := sendTo(t, exec, "s2"); got != "ok" {
+				t.Errorf("reply in a new conversation = %q, want %q", got, "ok")
+			}
+			if llm.calls != 3 {
+				t.Errorf("model called %d times in the new conversation, want 3", llm.calls)
+			}
+		})
+	}
+}
+
+func TestExecute_BudgetCost(t *testing.T) {
+	// Each turn costs 400 * $10/M + 100 * $30/M = $0.007
+	sessions := session.InMemoryService()
+	exec, llm := newBudgetExecutor(t, sessions, executor.SessionBudget{
+		MaxCost:              0.01,
+		InputCostPerMillion:  10,
+		OutputCostPerMillion: 30,
+	})
+
+	if got := send(t, exec); got != "ok" {
+		t.Fatalf("first reply = %q, want %q", got, "ok")
+	}
+	if _, cost := storedUsage(t, sessions); number(cost) < 0.0069 || number(cost) > 0.0071 {
+		t.Errorf("recorded cost = %v, want 0.007", cost)
+	}
+
+	// The second turn is allowed, since the budget isn't used up yet, and takes it over
+	if got := send(t, exec); got != "ok" {
+		t.Fatalf("second reply = %q, want %q", got, "ok")
+	}
+	if got := send(t, exec); got != executor.DefaultBudgetMessage {
+		t.Errorf("reply over budget = %q, want the default budget message", got)
+	}
+	if llm.calls != 2 {
+		t.Errorf("model called %d times, want 2", llm.calls)
+	}
+}
+
+func TestExecute_NoBudgetRecordsNothing(t *testing.T) {
+	sessions := session.InMemoryService()
+	exec, _ := newBudgetExecutor(t, sessions, executor.SessionBudget{})
+
+	for turn := 1; turn <= 3; turn++ {
+		if got := send(t, exec); got != "ok" {
+			t.Fatalf("turn %d reply = %q, want %q", turn, got, "ok")
+		}
+	}
+	if tokens, cost := storedUsage(t, sessions); tokens != nil || cost != nil {
+		t.Errorf("recorded tokens = %v, cost = %v, want none without a budget", tokens, cost)
+	}
+}
@@ -0,0 +1,21 @@
+package executor
+
+import "regexp"
+
+// CannedResponse answers messages matching a pattern with a fixed reply instead of calling
+// the model, e.g. to refuse requests for secrets or deflect banned topics cheaply.
+type CannedResponse struct {
+	Name     string         // Identifies the rule in logs
+	Pattern  *regexp.Regexp // Matched against the user's message
+	Response string         // Reply sent when the pattern matches
+}
+
+// matchCannedResponse returns the first rule whose pattern matches message
+func matchCannedResponse(rules []CannedResponse, message string) (CannedResponse, bool) {
+	for _, rule := range rules {
+		if rule.Pattern != nil && rule.Pattern.MatchString(message) {
+			return rule, true
+		}
+	}
+	return CannedResponse{}, false
+}
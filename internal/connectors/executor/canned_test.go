@@ -0,0 +1,53 @@
+package executor_test
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+func TestExecute_CannedResponse(t *testing.T) {
+	exec, llm := newRecordingExecutor(t, executor.Config{CannedResponses: []executor.CannedResponse{
+		{Name: "secrets", Pattern: regexp.MustCompile(`(?i)\b(password|api key)s?\b`), Response: "I can't help with credentials."},
+		{Name: "catch-all-secrets", Pattern: regexp.MustCompile(`(?i)secret`), Response: "Second rule"},
+	}})
+
+	resp := executeMessage(t, exec, "What's the admin PASSWORD for the secret server?")
+	if resp.Text != "I can't help with credentials." {
+		t.Errorf("response = %q, want the first matching rule's reply", resp.Text)
+	}
+	if len(llm.messages) != 0 {
+		t.Errorf("model called %d times, want no call for a matching message", len(llm.messages))
+	}
+
+	// Other messages go to the model as normal
+	resp = executeMessage(t, exec, "What's the weather like?")
+	if resp.Text != "ok" || len(llm.messages) != 1 || llm.messages[0] != "What's the weather like?" {
+		t.Errorf("model received %q and replied %q, want a normal reply", llm.messages, resp.Text)
+	}
+}
+
+func TestExecute_LogsCorrelationIDFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	exec, _ := newRecordingExecutor(t, executor.Config{
+		Logger: logger.NewLogger(logger.Config{Level: logger.InfoLevel, Output: &buf}),
+		CannedResponses: []executor.CannedResponse{
+			{Name: "greeting", Pattern: regexp.MustCompile(`(?i)^hello`), Response: "Hi!"},
+		},
+	})
+
+	// The connector puts the turn's correlation ID in the context; the executor's logs carry it
+	ctx := logger.WithCorrelationIDContext(context.Background(), "abc123")
+	if _, err := exec.Execute(ctx, executor.MessageRequest{UserID: "user1", SessionID: "session1", Message: "hello"}, nil, nil); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), `"correlation_id":"abc123"`) || !strings.Contains(buf.String(), "canned response") {
+		t.Errorf("log output = %s, want the canned response log with the context's correlation ID", buf.String())
+	}
+}
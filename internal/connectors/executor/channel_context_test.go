@@ -0,0 +1,44 @@
+package executor_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"google.golang.org/adk/session"
+)
+
+func TestExecute_ChannelContextIsAnInstruction(t *testing.T) {
+	s := newLanguageExecutor(t, false)
+	ctx := context.Background()
+
+	_, err := s.exec.Execute(ctx, executor.MessageRequest{
+		UserID:         "user1",
+		SessionID:      "session1",
+		Message:        "what's broken?",
+		ChannelContext: "- Channel: #incidents",
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(s.llm.lastInstruction(), "## Channel\n- Channel: #incidents") {
+		t.Errorf("instruction should describe the channel, got %q", s.llm.lastInstruction())
+	}
+
+	resp, err := s.sessions.Get(ctx, &session.GetRequest{AppName: "test", UserID: "user1", SessionID: "session1"})
+	if err != nil {
+		t.Fatalf("failed to get session: %v", err)
+	}
+	for event := range resp.Session.Events().All() {
+		if event.Author == "user" && event.Content != nil && event.Content.Parts[0].Text != "what's broken?" {
+			t.Errorf("stored user message = %q, want only the user's text", event.Content.Parts[0].Text)
+		}
+	}
+
+	// The description only applies to the turn it was given for
+	s.execute(t, "thanks", "")
+	if strings.Contains(s.llm.lastInstruction(), "## Channel") {
+		t.Errorf("instruction without channel context = %q, want no channel section", s.llm.lastInstruction())
+	}
+}
@@ -0,0 +1,152 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+const (
+	// prefixSeparator separates the response prefix from the response text
+	prefixSeparator = "\n"
+	// suffixSeparator separates the response text from the response suffix
+	suffixSeparator = "\n\n"
+)
+
+// DecoratorConfig configures the prefix and suffix (e.g. a disclaimer) added to responses.
+// Prefix and Suffix are Go templates with the variables {{.BotName}} and {{.Platform}}.
+type DecoratorConfig struct {
+	Prefix           string // Added before the first chunk of a response
+	Suffix           string // Added after the last chunk of a response
+	SuffixEveryChunk bool   // Add the suffix after every chunk instead of only the last
+	MaxLength        int    // Platform message length limit in characters; 0 means no limit
+	BotName          string
+	Platform         string
+}
+
+// Decorator adds a configured prefix and suffix to responses and splits them into
+// messages that fit the platform's length limit, decoration included.
+type Decorator struct {
+	prefix           string
+	suffix           string
+	suffixEveryChunk bool
+	maxLength        int
+}
+
+// templateData holds the variables available to prefix and suffix templates
+type templateData struct {
+	BotName  string
+	Platform string
+}
+
+// NewDecorator renders the prefix and suffix templates and returns a Decorator.
+// It returns an error if a template is invalid or the decoration leaves no room for the response.
+func NewDecorator(cfg DecoratorConfig) (*Decorator, error) {
+	data := templateData{BotName: cfg.BotName, Platform: cfg.Platform}
+
+	prefix, err := renderTemplate("prefix", cfg.Prefix, data)
+	if err != nil {
+		return nil, err
+	}
+	suffix, err := renderTemplate("suffix", cfg.Suffix, data)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Decorator{
+		prefix:           prefix,
+		suffix:           suffix,
+		suffixEveryChunk: cfg.SuffixEveryChunk,
+		maxLength:        cfg.MaxLength,
+	}
+	if cfg.MaxLength > 0 && d.reserved() >= cfg.MaxLength {
+		return nil, fmt.Errorf("response prefix and suffix must be shorter than the %d character message limit", cfg.MaxLength)
+	}
+	return d, nil
+}
+
+// renderTemplate executes a prefix or suffix template with data
+func renderTemplate(name, text string, data templateData) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid response %s template: %w", name, err)
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("failed to render response %s template: %w", name, err)
+	}
+	return rendered.String(), nil
+}
+
+// reserved returns how many characters of each message the decoration may take up
+func (d *Decorator) reserved() int {
+	reserved := 0
+	if d.prefix != "" {
+		reserved += utf8.RuneCountInString(d.prefix + prefixSeparator)
+	}
+	if d.suffix != "" {
+		reserved += utf8.RuneCountInString(suffixSeparator + d.suffix)
+	}
+	return reserved
+}
+
+// Apply splits text into messages within the length limit, adding the prefix to the first
+// message and the suffix to the last (or every) message. A nil Decorator only splits with no limit.
+func (d *Decorator) Apply(text string) []string {
+	if d == nil {
+		return []string{text}
+	}
+
+	limit := 0
+	if d.maxLength > 0 {
+		limit = d.maxLength - d.reserved()
+	}
+
+	chunks := SplitMessage(text, limit)
+	for i := range chunks {
+		if i == 0 && d.prefix != "" {
+			chunks[i] = d.prefix + prefixSeparator + chunks[i]
+		}
+		if d.suffix != "" && (d.suffixEveryChunk || i == len(chunks)-1) {
+			chunks[i] += suffixSeparator + d.suffix
+		}
+	}
+	return chunks
+}
+
+// SplitMessage splits text into chunks of at most limit characters, preferring to break
+// between paragraphs, then lines, then words. A limit of 0 or less returns text unsplit.
+func SplitMessage(text string, limit int) []string {
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	var chunks []string
+	remaining := []rune(text)
+	for len(remaining) > limit {
+		window := string(remaining[:limit])
+
+		// Break at the last paragraph, line or word boundary in the second half of the window,
+		// so chunks don't end up tiny; otherwise cut mid-word
+		cut, skip := len(window), 0
+		for _, sep := range []string{"\n\n", "\n", " "} {
+			if i := strings.LastIndex(window, sep); i >= len(window)/2 {
+				cut, skip = i, len(sep)
+				break
+			}
+		}
+
+		chunks = append(chunks, strings.TrimRight(window[:cut], " \n"))
+		remaining = remaining[utf8.RuneCountInString(window[:cut+skip]):]
+	}
+	if rest := strings.TrimSpace(string(remaining)); rest != "" {
+		chunks = append(chunks, rest)
+	}
+	return chunks
+}
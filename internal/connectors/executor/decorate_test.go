@@ -0,0 +1,121 @@
+package executor_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+)
+
+const disclaimer = "_AI-generated by {{.BotName}} on {{.Platform}}, verify important info._"
+
+func newTestDecorator(t *testing.T, cfg executor.DecoratorConfig) *executor.Decorator {
+	t.Helper()
+	cfg.BotName = "helper"
+	cfg.Platform = "Slack"
+	d, err := executor.NewDecorator(cfg)
+	if err != nil {
+		t.Fatalf("NewDecorator() error = %v", err)
+	}
+	return d
+}
+
+func TestDecorator_ShortResponse(t *testing.T) {
+	d := newTestDecorator(t, executor.DecoratorConfig{
+		Prefix:    "🤖 {{.BotName}}:",
+		Suffix:    disclaimer,
+		MaxLength: 100,
+	})
+
+	got := d.Apply("Hello there")
+	want := "🤖 helper:\nHello there\n\n_AI-generated by helper on Slack, verify important info._"
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("Apply() = %q, want [%q]", got, want)
+	}
+}
+
+func TestDecorator_SuffixOnChunks(t *testing.T) {
+	long := strings.Repeat("word ", 60) // 300 characters
+
+	tests := []struct {
+		name       string
+		everyChunk bool
+	}{
+		{name: "last chunk only", everyChunk: false},
+		{name: "every chunk", everyChunk: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDecorator(t, executor.DecoratorConfig{
+				Prefix:           "PREFIX",
+				Suffix:           "SUFFIX",
+				SuffixEveryChunk: tt.everyChunk,
+				MaxLength:        100,
+			})
+
+			chunks := d.Apply(long)
+			if len(chunks) < 2 {
+				t.Fatalf("expected the response to be split, got %d chunk(s)", len(chunks))
+			}
+
+			for i, chunk := range chunks {
+				if n := utf8.RuneCountInString(chunk); n > 100 {
+					t.Errorf("chunk %d has %d characters, over the limit of 100", i, n)
+				}
+				if hasPrefix := strings.HasPrefix(chunk, "PREFIX\n"); hasPrefix != (i == 0) {
+					t.Errorf("chunk %d prefix = %v, want only on the first chunk: %q", i, hasPrefix, chunk)
+				}
+				wantSuffix := tt.everyChunk || i == len(chunks)-1
+				if hasSuffix := strings.HasSuffix(chunk, "\n\nSUFFIX"); hasSuffix != wantSuffix {
+					t.Errorf("chunk %d suffix = %v, want %v: %q", i, hasSuffix, wantSuffix, chunk)
+				}
+			}
+		})
+	}
+}
+
+func TestNewDecorator_Errors(t *testing.T) {
+	if _, err := executor.NewDecorator(executor.DecoratorConfig{Suffix: "{{.Unknown}}"}); err == nil {
+		t.Error("expected error for unknown template variable")
+	}
+	if _, err := executor.NewDecorator(executor.DecoratorConfig{Prefix: "{{.BotName"}); err == nil {
+		t.Error("expected error for invalid template")
+	}
+	if _, err := executor.NewDecorator(executor.DecoratorConfig{Suffix: strings.Repeat("x", 50), MaxLength: 50}); err == nil {
+		t.Error("expected error when the suffix leaves no room for the response")
+	}
+}
+
+func TestDecorator_NilSplitsNothing(t *testing.T) {
+	var d *executor.Decorator
+	if got := d.Apply("hello"); len(got) != 1 || got[0] != "hello" {
+		t.Errorf("nil Apply() = %q, want [\"hello\"]", got)
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []string
+	}{
+		{name: "fits", text: "short", limit: 10, want: []string{"short"}},
+		{name: "no limit", text: "short", limit: 0, want: []string{"short"}},
+		{name: "paragraphs", text: "first paragraph\n\nsecond one", limit: 20, want: []string{"first paragraph", "second one"}},
+		{name: "words", text: "alpha beta gamma delta", limit: 12, want: []string{"alpha beta", "gamma delta"}},
+		{name: "hard cut", text: "abcdefghij", limit: 4, want: []string{"abcd", "efgh", "ij"}},
+		{name: "multibyte", text: "ééééé", limit: 2, want: []string{"éé", "éé", "é"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := executor.SplitMessage(tt.text, tt.limit)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("SplitMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// DuplicateReplyWindow is how long after posting a reply an identical reply to the same
+// conversation is treated as a duplicate
+const DuplicateReplyWindow = time.Minute
+
+// maxTrackedConversations bounds the number of conversations remembered before stale ones are swept
+const maxTrackedConversations = 1000
+
+// ReplyDeduper remembers the last reply posted to each conversation (a channel or thread),
+// so a retried or re-delivered event doesn't post the same reply twice in a row.
+type ReplyDeduper struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	last map[string]postedReply
+}
+
+// postedReply is the hash of the last reply posted to a conversation and when it was posted
+type postedReply struct {
+	hash     [sha256.Size]byte
+	postedAt time.Time
+}
+
+// NewReplyDeduper creates a ReplyDeduper that suppresses identical replies posted within window.
+func NewReplyDeduper(window time.Duration) *ReplyDeduper {
+	return &ReplyDeduper{
+		window: window,
+		now:    time.Now,
+		last:   make(map[string]postedReply),
+	}
+}
+
+// CheckAndRecord reports whether text is identical to the reply last posted to conversation
+// within the window, and otherwise records it as the last reply, in one step so that two
+// deliveries of the same event can't both post it. A caller that then fails to post the reply
+// should Forget it. A nil ReplyDeduper never reports duplicates.
+func (d *ReplyDeduper) CheckAndRecord(conversation, text string) bool {
+	if d == nil {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	hash := sha256.Sum256([]byte(text))
+	if last, ok := d.last[conversation]; ok && last.hash == hash && now.Sub(last.postedAt) < d.window {
+		return true
+	}
+
+	if len(d.last) >= maxTrackedConversations {
+		for key, reply := range d.last {
+			if now.Sub(reply.postedAt) >= d.window {
+				delete(d.last, key)
+			}
+		}
+	}
+	d.last[conversation] = postedReply{hash: hash, postedAt: now}
+	return false
+}
+
+// Forget removes text as the reply last posted to conversation, so a reply that couldn't be
+// posted isn't suppressed when it's retried. A later reply recorded since is kept.
+func (d *ReplyDeduper) Forget(conversation, text string) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if last, ok := d.last[conversation]; ok && last.hash == sha256.Sum256([]byte(text)) {
+		delete(d.last, conversation)
+	}
+}
@@ -0,0 +1,95 @@
+package executor_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+)
+
+func TestReplyDeduper_SuppressesIdenticalReply(t *testing.T) {
+	d := executor.NewReplyDeduper(time.Hour)
+
+	if d.CheckAndRecord("C1:", "Hello!") {
+		t.Fatal("first reply reported as duplicate")
+	}
+	if !d.CheckAndRecord("C1:", "Hello!") {
+		t.Error("identical reply within the window should be a duplicate")
+	}
+	if d.CheckAndRecord("C2:", "Hello!") {
+		t.Error("same reply in another conversation should be posted")
+	}
+	if d.CheckAndRecord("C1:1700000000.000100", "Hello!") {
+		t.Error("same reply in a thread of the channel should be posted")
+	}
+
+	// Only the immediately preceding reply counts
+	if d.CheckAndRecord("C1:", "Hello again!") {
+		t.Error("different reply should be posted")
+	}
+	if d.CheckAndRecord("C1:", "Hello!") {
+		t.Error("reply matching an earlier, not the last, reply should be posted")
+	}
+}
+
+func TestReplyDeduper_ConcurrentDeliveriesPostOnce(t *testing.T) {
+	d := executor.NewReplyDeduper(time.Hour)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	posted := 0
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !d.CheckAndRecord("C1:", "Hello!") {
+				mu.Lock()
+				posted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if posted != 1 {
+		t.Errorf("reply posted %d times, want once", posted)
+	}
+}
+
+func TestReplyDeduper_Forget(t *testing.T) {
+	d := executor.NewReplyDeduper(time.Hour)
+
+	// A reply that failed to post is forgotten, so its retry is posted
+	d.CheckAndRecord("C1:", "Hello!")
+	d.Forget("C1:", "Hello!")
+	if d.CheckAndRecord("C1:", "Hello!") {
+		t.Error("reply forgotten after a failed post should be posted")
+	}
+
+	// Forgetting a reply that has since been replaced keeps the newer one
+	d.CheckAndRecord("C1:", "Newer")
+	d.Forget("C1:", "Hello!")
+	if !d.CheckAndRecord("C1:", "Newer") {
+		t.Error("newer reply should still be remembered")
+	}
+}
+
+func TestReplyDeduper_WindowExpires(t *testing.T) {
+	d := executor.NewReplyDeduper(10 * time.Millisecond)
+	d.CheckAndRecord("chat", "ok")
+
+	time.Sleep(20 * time.Millisecond)
+	if d.CheckAndRecord("chat", "ok") {
+		t.Error("identical reply after the window should be posted")
+	}
+}
+
+func TestReplyDeduper_NilIsDisabled(t *testing.T) {
+	var d *executor.ReplyDeduper
+	d.CheckAndRecord("chat", "ok")
+	d.Forget("chat", "ok")
+	if d.CheckAndRecord("chat", "ok") {
+		t.Error("nil deduper should never report duplicates")
+	}
+}
@@ -0,0 +1,194 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/models"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"google.golang.org/adk/session"
+)
+
+// Defaults for saving a turn in the background after session storage failed
+const (
+	DefaultDegradedRetries      = 5
+	DefaultDegradedRetryBackoff = 2 * time.Second
+
+	maxDegradedRetryBackoff = time.Minute
+)
+
+// DegradedSessions keeps the bot answering while session storage is briefly unavailable.
+// A turn whose session can't be loaded runs on a temporary in-memory session, without the
+// conversation's history, and a turn that can't be saved is retried in the background.
+// A turn still unsaved after every retry, or when a newer turn in the conversation starts,
+// is lost from the history, so turns are never stored out of order.
+type DegradedSessions struct {
+	Enabled      bool
+	Retries      int           // Background attempts to save a turn; DefaultDegradedRetries if 0
+	RetryBackoff time.Duration // Wait before the first attempt, doubling after each; DefaultDegradedRetryBackoff if 0
+}
+
+// retryConfig returns the backoff between background attempts to save a turn. The first
+// attempt waits InitialBackoff after the failed save.
+func (d DegradedSessions) retryConfig() models.RetryConfig {
+	backoff := d.RetryBackoff
+	if backoff <= 0 {
+		backoff = DefaultDegradedRetryBackoff
+	}
+	retries := d.Retries
+	if retries <= 0 {
+		retries = DefaultDegradedRetries
+	}
+	return models.RetryConfig{
+		MaxRetries:     retries - 1,
+		InitialBackoff: min(backoff, maxDegradedRetryBackoff),
+		MaxBackoff:     maxDegradedRetryBackoff,
+	}
+}
+
+// errSaveSuperseded stops a background save once a newer turn in the conversation started
+var errSaveSuperseded = errors.New("a newer turn started before the conversation was saved")
+
+// startPendingSave registers a background save for sessionID, superseding any earlier one,
+// and returns the number identifying it
+func (e *Executor) startPendingSave(sessionID string) uint64 {
+	e.pendingMutex.Lock()
+	defer e.pendingMutex.Unlock()
+
+	if e.pendingSaves == nil {
+		e.pendingSaves = make(map[string]uint64)
+	}
+	e.saveSeq++
+	e.pendingSaves[sessionID] = e.saveSeq
+	return e.saveSeq
+}
+
+// supersedePendingSave stops the background save of an earlier turn in sessionID, if one is
+// still retrying, so it can't append that turn after this one's events
+func (e *Executor) supersedePendingSave(sessionID string) {
+	e.pendingMutex.Lock()
+	defer e.pendingMutex.Unlock()
+	delete(e.pendingSaves, sessionID)
+}
+
+// pendingSaveCurrent reports whether save hasn't been superseded by a newer turn
+func (e *Executor) pendingSaveCurrent(sessionID string, save uint64) bool {
+	e.pendingMutex.Lock()
+	defer e.pendingMutex.Unlock()
+	return e.pendingSaves[sessionID] == save
+}
+
+// finishPendingSave unregisters save, unless a newer turn already superseded it. It reports
+// whether save was still current.
+func (e *Executor) finishPendingSave(sessionID string, save uint64) bool {
+	e.pendingMutex.Lock()
+	defer e.pendingMutex.Unlock()
+
+	if e.pendingSaves[sessionID] != save {
+		return false
+	}
+	delete(e.pendingSaves, sessionID)
+	return true
+}
+
+// ephemeralSession creates an in-memory session for a turn that can't use stored sessions
+func (e *Executor) ephemeralSession(ctx context.Context, userID, sessionID string) (session.Service, session.Session, error) {
+	sessions := session.InMemoryService()
+	created, err := sessions.Create(ctx, &session.CreateRequest{
+		AppName:   e.appName,
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create ephemeral session: %w", err)
+	}
+	return sessions, created.Session, nil
+}
+
+// ephemeralTurnSaver returns a function that copies the events of a turn run on an
+// ephemeral session to the stored session, creating it if needed. Events already saved
+// by an earlier call aren't saved again.
+func (e *Executor) ephemeralTurnSaver(ephemeral session.Service, userID, sessionID string) func(context.Context) error {
+	saved := 0
+	return func(ctx context.Context) error {
+		resp, err := ephemeral.Get(ctx, &session.GetRequest{
+			AppName:   e.appName,
+			UserID:    userID,
+			SessionID: sessionID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get ephemeral session: %w", err)
+		}
+		var events []*session.Event
+		for event := range resp.Session.Events().All() {
+			events = append(events, event)
+		}
+
+		sess, err := e.ensureSession(ctx, userID, sessionID)
+		if err != nil {
+			return err
+		}
+
+		// Save the turn in one write when the session service supports it
+		if batcher, ok := e.sessionService.(turnBatcher); ok {
+			batch := batcher.NewBatch()
+			for _, event := range events {
+				if err := batch.AppendEvent(ctx, sess, event); err != nil {
+					return fmt.Errorf("failed to save event: %w", err)
+				}
+			}
+			return batch.Flush(ctx)
+		}
+		for ; saved < len(events); saved++ {
+			if err := e.sessionService.AppendEvent(ctx, sess, events[saved]); err != nil {
+				return fmt.Errorf("failed to save event: %w", err)
+			}
+		}
+		return nil
+	}
+}
+
+// saveInBackground retries save after the turn was answered, until it succeeds, the retries
+// run out or a newer turn in the session starts. It carries on after ctx is cancelled,
+// keeping its values for logging.
+func (e *Executor) saveInBackground(ctx context.Context, sessionID string, save func(context.Context) error) {
+	ctx = context.WithoutCancel(ctx)
+	cfg := e.degradedSessions.retryConfig()
+	pending := e.startPendingSave(sessionID)
+
+	go func() {
+		time.Sleep(cfg.InitialBackoff)
+
+		attempts := 0
+		err := models.Retry(ctx, cfg, func(err error) bool { return !errors.Is(err, errSaveSuperseded) }, func(ctx context.Context) error {
+			if !e.pendingSaveCurrent(sessionID, pending) {
+				return errSaveSuperseded
+			}
+			attempts++
+			return save(ctx)
+		})
+		current := e.finishPendingSave(sessionID, pending)
+		if e.log == nil {
+			return
+		}
+
+		switch {
+		case err == nil && current:
+			e.log.InfoCtx(ctx, "Saved conversation after session storage recovered",
+				logger.StringField("session_id", sessionID),
+				logger.IntField("attempts", attempts))
+		case err == nil:
+			// Saved just as a newer turn started; that turn ran without it
+			e.log.WarnCtx(ctx, "Saved conversation while a newer turn was running",
+				logger.StringField("session_id", sessionID),
+				logger.IntField("attempts", attempts))
+		default:
+			e.log.ErrorCtx(ctx, "Gave up saving conversation, the turn is lost from its history",
+				logger.StringField("session_id", sessionID),
+				logger.IntField("attempts", attempts),
+				logger.ErrorField(err))
+		}
+	}()
+}
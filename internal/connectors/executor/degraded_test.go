@@ -0,0 +1,190 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"google.golang.org/adk/session"
+)
+
+var errStorageDown = errors.New("storage unavailable")
+
+// outageProvider fails reads, writes or both while the outage lasts, and counts the writes
+// that succeed
+type outageProvider struct {
+	storage_manager.FileProvider
+
+	mutex      sync.Mutex
+	readsDown  bool
+	writesDown bool
+	writes     int
+}
+
+func (p *outageProvider) set(readsDown, writesDown bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.readsDown, p.writesDown = readsDown, writesDown
+}
+
+func (p *outageProvider) down(write bool) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if write {
+		return p.writesDown
+	}
+	return p.readsDown
+}
+
+func (p *outageProvider) Read(ctx context.Context, path string) ([]byte, error) {
+	if p.down(false) {
+		return nil, errStorageDown
+	}
+	return p.FileProvider.Read(ctx, path)
+}
+
+func (p *outageProvider) Exists(ctx context.Context, path string) (bool, error) {
+	if p.down(false) {
+		return false, errStorageDown
+	}
+	return p.FileProvider.Exists(ctx, path)
+}
+
+func (p *outageProvider) Write(ctx context.Context, path string, data []byte) error {
+	if p.down(true) {
+		return errStorageDown
+	}
+	if err := p.FileProvider.Write(ctx, path, data); err != nil {
+		return err
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.writes++
+	return nil
+}
+
+// takeWrites returns how many writes succeeded since the last call
+func (p *outageProvider) takeWrites() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	writes := p.writes
+	p.writes = 0
+	return writes
+}
+
+func newOutageExecutor(t *testing.T, degraded executor.DegradedSessions) (*executor.Executor, *outageProvider, session.Service) {
+	t.Helper()
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+	provider := &outageProvider{FileProvider: storage_manager.NewLocalFileProvider(t.TempDir())}
+	sessions := session_manager.NewSessionService(provider, log)
+
+	exec := newTestExecutor(t, &instructionRecordingModel{}, executor.Config{
+		SessionService:   sessions,
+		DegradedSessions: degraded,
+	})
+	return exec, provider, sessions
+}
+
+// waitForEvents waits until the stored session has want events
+func waitForEvents(t *testing.T, sessions session.Service, want int) {
+	t.Helper()
+	var got int
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		resp, err := sessions.Get(context.Background(), &session.GetRequest{AppName: "test", UserID: "u1", SessionID: "s1"})
+		if err != nil {
+			continue
+		}
+		if got = resp.Session.Events().Len(); got == want {
+			return
+		}
+	}
+	t.Fatalf("stored session has %d events, want %d", got, want)
+}
+
+func TestExecute_DegradedSessions_AnswersWhileStorageIsDown(t *testing.T) {
+	exec, provider, sessions := newOutageExecutor(t, executor.DegradedSessions{Enabled: true, RetryBackoff: 20 * time.Millisecond})
+	provider.set(true, true)
+
+	resp, err := exec.Execute(context.Background(), executor.MessageRequest{UserID: "u1", SessionID: "s1", Message: "hello"}, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want a response while storage is down", err)
+	}
+	if resp.Text != "ok" {
+		t.Errorf("Text = %q, want %q", resp.Text, "ok")
+	}
+
+	// The turn is saved once storage recovers
+	provider.set(false, false)
+	waitForEvents(t, sessions, 2)
+}
+
+func TestExecute_DegradedSessions_RetriesFailedSave(t *testing.T) {
+	exec, provider, sessions := newOutageExecutor(t, executor.DegradedSessions{Enabled: true, RetryBackoff: 20 * time.Millisecond})
+	executeTurn := func() {
+		t.Helper()
+		resp, err := exec.Execute(context.Background(), executor.MessageRequest{UserID: "u1", SessionID: "s1", Message: "hello"}, nil, nil)
+		if err != nil || resp.Text != "ok" {
+			t.Fatalf("Execute() = %q, %v; want ok", resp.Text, err)
+		}
+	}
+	executeTurn()
+
+	// The session loads but the turn can't be written
+	provider.set(false, true)
+	executeTurn()
+
+	provider.set(false, false)
+	waitForEvents(t, sessions, 4)
+}
+
+func TestExecute_DegradedSessionsDisabled_FailsWhileStorageIsDown(t *testing.T) {
+	exec, provider, _ := newOutageExecutor(t, executor.DegradedSessions{})
+	provider.set(true, true)
+
+	if _, err := exec.Execute(context.Background(), executor.MessageRequest{UserID: "u1", SessionID: "s1", Message: "hello"}, nil, nil); err == nil {
+		t.Error("Execute() error = nil, want the storage error without degraded sessions")
+	}
+}
+
+func TestExecute_DegradedSessions_NewerTurnSupersedesPendingSave(t *testing.T) {
+	exec, provider, sessions := newOutageExecutor(t, executor.DegradedSessions{Enabled: true, RetryBackoff: 20 * time.Millisecond})
+	executeTurn := func(message string) {
+		t.Helper()
+		resp, err := exec.Execute(context.Background(), executor.MessageRequest{UserID: "u1", SessionID: "s1", Message: message}, nil, nil)
+		if err != nil || resp.Text != "ok" {
+			t.Fatalf("Execute(%q) = %q, %v; want ok", message, resp.Text, err)
+		}
+	}
+	executeTurn("hello")
+
+	// Both turns fail to save; the second starts while the first is still retrying
+	provider.set(false, true)
+	executeTurn("first")
+	executeTurn("second")
+
+	provider.set(false, false)
+	waitForEvents(t, sessions, 4)
+
+	// The first turn's save was abandoned, so it never lands after the second turn
+	time.Sleep(200 * time.Millisecond)
+	resp, err := sessions.Get(context.Background(), &session.GetRequest{AppName: "test", UserID: "u1", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	var messages []string
+	for event := range resp.Session.Events().All() {
+		if event.Author == "user" {
+			messages = append(messages, event.Content.Parts[0].Text)
+		}
+	}
+	if len(messages) != 2 || messages[0] != "hello" || messages[1] != "second" {
+		t.Errorf("stored user messages = %v, want [hello second]", messages)
+	}
+}
@@ -0,0 +1,62 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxDocumentSize is the largest uploaded document accepted for ingestion, in bytes.
+const MaxDocumentSize = 1 << 20
+
+// textDocumentExtensions lists file extensions treated as text regardless of MIME type.
+var textDocumentExtensions = map[string]bool{
+	".txt": true, ".md": true, ".markdown": true, ".csv": true, ".tsv": true,
+	".json": true, ".yaml": true, ".yml": true, ".xml": true, ".log": true,
+	".html": true, ".htm": true, ".rst": true,
+}
+
+// DocumentIngester stores uploaded documents so the agent can search them later.
+type DocumentIngester interface {
+	IngestDocument(ctx context.Context, appName, userID, name, content string, metadata map[string]string) (int, error)
+}
+
+// DocumentsEnabled reports whether uploaded documents can be ingested.
+func (e *Executor) DocumentsEnabled() bool {
+	return e.documentIngester != nil
+}
+
+// IngestDocument stores an uploaded text document for the user, scoped to this executor's app.
+// Returns the number of chunks stored.
+func (e *Executor) IngestDocument(ctx context.Context, userID, name string, content []byte, metadata map[string]string) (int, error) {
+	if e.documentIngester == nil {
+		return 0, fmt.Errorf("document ingestion is disabled")
+	}
+	if userID == "" {
+		return 0, fmt.Errorf("userID is required")
+	}
+	if len(content) > MaxDocumentSize {
+		return 0, fmt.Errorf("document %q exceeds the %d byte limit", name, MaxDocumentSize)
+	}
+	if !utf8.Valid(content) {
+		return 0, fmt.Errorf("document %q is not valid UTF-8 text", name)
+	}
+
+	return e.documentIngester.IngestDocument(ctx, e.appName, userID, name, string(content), metadata)
+}
+
+// IsTextDocument reports whether a file looks like a text document that can be ingested,
+// based on its MIME type or file extension.
+func IsTextDocument(mimeType, name string) bool {
+	mimeType = strings.ToLower(mimeType)
+	if strings.HasPrefix(mimeType, "text/") {
+		return true
+	}
+	switch mimeType {
+	case "application/json", "application/xml", "application/x-yaml", "application/yaml":
+		return true
+	}
+	return textDocumentExtensions[strings.ToLower(filepath.Ext(name))]
+}
@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"text/template"
+)
+
+// DefaultErrorMessage is the reply sent when a message can't be processed. It's a Go template;
+// {{.CorrelationID}} is the reference logged with the failure so support can find it.
+const DefaultErrorMessage = "Sorry, I encountered an error processing your message. " +
+	"If this keeps happening, please share this reference: {{.CorrelationID}}"
+
+// defaultErrorTemplate renders DefaultErrorMessage, and is the fallback if a custom template fails
+var defaultErrorTemplate = template.Must(template.New("error_message").Parse(DefaultErrorMessage))
+
+// NewCorrelationID returns a short random ID identifying one turn, logged with its errors
+// and shown to the user when it fails
+func NewCorrelationID() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ErrorMessage renders the reply sent to users when their message can't be processed
+type ErrorMessage struct {
+	tmpl *template.Template
+}
+
+// errorMessageData holds the variables available to the error message template
+type errorMessageData struct {
+	CorrelationID string
+}
+
+// NewErrorMessage parses an error message template; DefaultErrorMessage if text is empty.
+func NewErrorMessage(text string) (*ErrorMessage, error) {
+	if text == "" {
+		text = DefaultErrorMessage
+	}
+	tmpl, err := template.New("error_message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid error message template: %w", err)
+	}
+	// Catch references to unknown fields now rather than on the first error
+	if err := tmpl.Execute(&bytes.Buffer{}, errorMessageData{}); err != nil {
+		return nil, fmt.Errorf("invalid error message template: %w", err)
+	}
+	return &ErrorMessage{tmpl: tmpl}, nil
+}
+
+// Render returns the error reply for the turn with the given correlation ID. A nil
+// *ErrorMessage renders DefaultErrorMessage.
+func (m *ErrorMessage) Render(correlationID string) string {
+	data := errorMessageData{CorrelationID: correlationID}
+	var buf bytes.Buffer
+	if m != nil && m.tmpl.Execute(&buf, data) == nil {
+		return buf.String()
+	}
+	buf.Reset()
+	_ = defaultErrorTemplate.Execute(&buf, data)
+	return buf.String()
+}
@@ -0,0 +1,50 @@
+package executor_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+)
+
+func TestNewCorrelationID(t *testing.T) {
+	a, b := executor.NewCorrelationID(), executor.NewCorrelationID()
+	if !regexp.MustCompile(`^[0-9a-f]{12}$`).MatchString(a) {
+		t.Errorf("NewCorrelationID() = %q, want 12 hex characters", a)
+	}
+	if a == b {
+		t.Errorf("NewCorrelationID() returned %q twice", a)
+	}
+}
+
+func TestErrorMessage_Render(t *testing.T) {
+	def, err := executor.NewErrorMessage("")
+	if err != nil {
+		t.Fatalf("NewErrorMessage(\"\") error = %v", err)
+	}
+	if got := def.Render("abc123"); !strings.HasPrefix(got, "Sorry") || !strings.HasSuffix(got, "reference: abc123") {
+		t.Errorf("default Render() = %q, want the default message ending with the reference", got)
+	}
+
+	custom, err := executor.NewErrorMessage("Something broke. Quote {{.CorrelationID}} in #support.")
+	if err != nil {
+		t.Fatalf("NewErrorMessage() error = %v", err)
+	}
+	if got := custom.Render("abc123"); got != "Something broke. Quote abc123 in #support." {
+		t.Errorf("custom Render() = %q", got)
+	}
+
+	var nilMessage *executor.ErrorMessage
+	if got := nilMessage.Render("abc123"); got != def.Render("abc123") {
+		t.Errorf("nil Render() = %q, want the default message", got)
+	}
+}
+
+func TestNewErrorMessage_InvalidTemplate(t *testing.T) {
+	for _, text := range []string{"Error {{.CorrelationID", "Error {{.RequestID}}"} {
+		if _, err := executor.NewErrorMessage(text); err == nil {
+			t.Errorf("NewErrorMessage(%q) error = nil, want an invalid template error", text)
+		}
+	}
+}
@@ -2,11 +2,17 @@
 package executor
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/language"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/memory_service"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
@@ -18,22 +24,71 @@ import (
 
 // Executor handles execution of connector operations
 type Executor struct {
-	sessionService  session.Service
-	artifactService artifact.Service
-	memoryService   memory.Service
-	appName         string
-	agentFactory    agents.AgentFactory
-	log             logger.Logger
+	sessionService   session.Service
+	artifactService  artifact.Service
+	memoryService    memory.Service
+	appName          string
+	agentFactory     agents.AgentFactory
+	documentIngester DocumentIngester
+	citations        bool
+	detectLanguage   bool
+	inboundLimit     InboundLimit
+	quoteTrim        QuoteTrim
+	maintenance      *Maintenance
+	cannedResponses  []CannedResponse
+	degradedSessions DegradedSessions
+	budget           SessionBudget
+	defaultLocale    string
+	agents           map[string]agents.AgentFactory
+	router           Router
+	log              logger.Logger
+
+	pendingMutex sync.Mutex
+	pendingSaves map[string]uint64 // Background saves still retrying, by session ID
+	saveSeq      uint64            // Number of the last background save started
 }
 
 // Config holds configuration for the executor.
 type Config struct {
-	AgentFactory    agents.AgentFactory
-	AppName         string
-	SessionService  session.Service
-	ArtifactService artifact.Service
-	MemoryService   memory.Service // Optional: if nil, memory is disabled
-	Logger          logger.Logger
+	AgentFactory     agents.AgentFactory
+	AppName          string
+	SessionService   session.Service
+	ArtifactService  artifact.Service
+	MemoryService    memory.Service   // Optional: if nil, memory is disabled
+	DocumentIngester DocumentIngester // Optional: if nil, document ingestion is disabled
+	Citations        bool             // Append a "Sources" footer listing web_search results used
+	DetectLanguage   bool             // Detect each message's language and ask the agent to respond in it
+	InboundLimit     InboundLimit     // Optional: cap on the length of incoming messages
+	QuoteTrim        QuoteTrim        // Optional: trim long quoted blocks from long incoming messages
+	Maintenance      *Maintenance     // Optional: runtime switch that pauses LLM calls
+	CannedResponses  []CannedResponse // Optional: fixed replies to messages matching a pattern, checked in order
+	DegradedSessions DegradedSessions // Optional: keep answering while session storage is unavailable
+	Budget           SessionBudget    // Optional: cap on the tokens or cost of each conversation
+	DefaultLocale    string           // Optional: locale used when a request has none, e.g. "de-DE"
+
+	// Optional: named agents, e.g. with their own prompts, tools or models, and the router
+	// picking which one handles each message. Messages it routes nowhere use AgentFactory.
+	Agents map[string]agents.AgentFactory
+	Router Router
+
+	Logger logger.Logger
+}
+
+// turnBatcher is implemented by session services that can persist a whole turn in one write
+type turnBatcher interface {
+	NewBatch() *session_manager.EventBatch
+}
+
+// sessionToucher is implemented by session services that can mark a session active without
+// appending an event
+type sessionToucher interface {
+	Touch(ctx context.Context, appName, userID, sessionID string) error
+}
+
+// stateKeyDeleter is implemented by session services that can remove keys from a session's
+// state
+type stateKeyDeleter interface {
+	DeleteStateKeys(ctx context.Context, sess session.Session, keys ...string) error
 }
 
 // NewExecutor creates a new Executor instance (legacy signature for compatibility).
@@ -58,12 +113,24 @@ func NewExecutorWithConfig(cfg Config) (*Executor, error) {
 	}
 
 	return &Executor{
-		sessionService:  cfg.SessionService,
-		artifactService: cfg.ArtifactService,
-		memoryService:   cfg.MemoryService,
-		appName:         cfg.AppName,
-		agentFactory:    cfg.AgentFactory,
-		log:             cfg.Logger,
+		sessionService:   cfg.SessionService,
+		artifactService:  cfg.ArtifactService,
+		memoryService:    cfg.MemoryService,
+		appName:          cfg.AppName,
+		agentFactory:     cfg.AgentFactory,
+		documentIngester: cfg.DocumentIngester,
+		citations:        cfg.Citations,
+		detectLanguage:   cfg.DetectLanguage,
+		inboundLimit:     cfg.InboundLimit,
+		quoteTrim:        cfg.QuoteTrim,
+		maintenance:      cfg.Maintenance,
+		cannedResponses:  cfg.CannedResponses,
+		degradedSessions: cfg.DegradedSessions,
+		budget:           cfg.Budget,
+		defaultLocale:    cfg.DefaultLocale,
+		agents:           cfg.Agents,
+		router:           cfg.Router,
+		log:              cfg.Logger,
 	}, nil
 }
 
@@ -87,22 +154,81 @@ func (e *Executor) Execute(
 		return MessageResponse{}, fmt.Errorf("message is required")
 	}
 
+	// In maintenance mode reply with the notice without touching the model or the session
+	if enabled, message := e.maintenance.Status(); enabled {
+		return MessageResponse{Text: message}, nil
+	}
+
+	// Messages matching a canned response rule get its reply without a model call
+	if rule, ok := matchCannedResponse(e.cannedResponses, req.Message); ok {
+		if e.log != nil {
+			e.log.InfoCtx(ctx, "Message matched canned response rule",
+				logger.StringField("rule", rule.Name),
+				logger.StringField("user_id", req.UserID))
+		}
+		e.touchSession(ctx, req)
+		return MessageResponse{Text: rule.Response}, nil
+	}
+
+	// Long quoted content (forwarded alerts, pasted threads) is trimmed before the length limit
+	if trimmedMessage, trimmed := e.quoteTrim.apply(req.Message); trimmed {
+		if e.log != nil {
+			e.log.InfoCtx(ctx, "Trimmed quoted content from message",
+				logger.StringField("user_id", req.UserID),
+				logger.IntField("original_chars", utf8.RuneCountInString(req.Message)),
+				logger.IntField("trimmed_chars", utf8.RuneCountInString(trimmedMessage)))
+		}
+		req.Message = trimmedMessage
+	}
+
+	// Oversized messages are rejected before anything is stored, or truncated with a note
+	message, inboundNote, rejected := e.inboundLimit.apply(req.Message)
+	if rejected {
+		e.touchSession(ctx, req)
+		return MessageResponse{Text: inboundNote}, nil
+	}
+	req.Message = message
+
 	// Ensure session exists, create if needed
-	_, err := e.sessionService.Get(ctx, &session.GetRequest{
-		AppName:   e.appName,
-		UserID:    req.UserID,
-		SessionID: req.SessionID,
-	})
+	sessions := e.sessionService
+	ephemeral := false
+	sess, err := e.ensureSession(ctx, req.UserID, req.SessionID)
 	if err != nil {
-		// Session doesn't exist, create it
-		_, err = e.sessionService.Create(ctx, &session.CreateRequest{
-			AppName:   e.appName,
-			UserID:    req.UserID,
-			SessionID: req.SessionID,
-		})
+		if !e.degradedSessions.Enabled || ctx.Err() != nil {
+			return MessageResponse{}, err
+		}
+		// Answer without the conversation's history rather than failing the message
+		if e.log != nil {
+			e.log.WarnCtx(ctx, "Session storage unavailable, answering with an ephemeral session",
+				logger.StringField("session_id", req.SessionID),
+				logger.ErrorField(err))
+		}
+		sessions, sess, err = e.ephemeralSession(ctx, req.UserID, req.SessionID)
 		if err != nil {
-			return MessageResponse{}, fmt.Errorf("failed to create session: %w", err)
+			return MessageResponse{}, err
 		}
+		ephemeral = true
+	}
+
+	// A conversation that has used up its budget gets the notice until it's reset
+	var usage BudgetUsage
+	if e.budget.enabled() {
+		usage = budgetUsage(sess.State())
+		if e.budget.exceeded(usage) {
+			if e.log != nil {
+				e.log.InfoCtx(ctx, "Conversation is over its budget",
+					logger.StringField("session_id", req.SessionID),
+					logger.IntField("tokens", usage.Tokens),
+					logger.Float64Field("cost", usage.Cost))
+			}
+			e.touchSession(ctx, req)
+			return MessageResponse{Text: e.budget.message()}, nil
+		}
+	}
+
+	// Remember the conversation language so the agent's instructions can follow it
+	if e.detectLanguage {
+		e.updateLanguage(ctx, sessions, sess, req)
 	}
 
 	// Create content from user message
@@ -113,15 +239,29 @@ func (e *Executor) Execute(
 		StreamingMode: agent.StreamingModeNone,
 	}
 
-	agentInstance, err := e.agentFactory(guidanceProvider, userInfoFunc)
+	agentName, agentFactory := e.agentFactoryFor(ctx, req)
+	if agentName != "" && e.log != nil {
+		e.log.DebugCtx(ctx, "Routed message to agent",
+			logger.StringField("agent", agentName),
+			logger.StringField("session_id", req.SessionID))
+	}
+	agentInstance, err := agentFactory(guidanceProvider, userInfoFunc)
 	if err != nil {
 		return MessageResponse{}, fmt.Errorf("failed to create agent instance: %w", err)
 	}
 
+	// Persist the turn's events together once it finishes, when the session service supports it
+	sessionService := sessions
+	var batch *session_manager.EventBatch
+	if batcher, ok := sessions.(turnBatcher); ok {
+		batch = batcher.NewBatch()
+		sessionService = batch
+	}
+
 	// Create runner
 	r, err := runner.New(runner.Config{
 		AppName:         e.appName,
-		SessionService:  e.sessionService,
+		SessionService:  sessionService,
 		ArtifactService: e.artifactService,
 		Agent:           agentInstance,
 	})
@@ -129,12 +269,20 @@ func (e *Executor) Execute(
 		return MessageResponse{}, fmt.Errorf("failed to create runner: %w", err)
 	}
 
+	// A turn still being saved in the background would land after this one's events
+	e.supersedePendingSave(req.SessionID)
+
 	// Execute via runner
+	ctx = memory_service.WithDocumentsUser(ctx, req.DocumentsUserID)
+	ctx = agents.WithChannelContext(ctx, req.ChannelContext)
 	eventIterator := r.Run(ctx, req.UserID, req.SessionID, content, runConfig)
 
 	// Iterate and collect response text
 	var responseText strings.Builder
+	var sources []Source
+	seenSources := make(map[string]bool)
 	var lastError error
+	turnUsage := usage
 
 	for event, err := range eventIterator {
 		if err != nil {
@@ -145,6 +293,7 @@ func (e *Executor) Execute(
 		if event == nil {
 			continue
 		}
+		turnUsage = e.budget.add(turnUsage, event.UsageMetadata)
 
 		// Check for error in event
 		if event.ErrorMessage != "" {
@@ -152,12 +301,53 @@ func (e *Executor) Execute(
 			break
 		}
 
-		// Extract text from content parts
+		// Extract text from content parts, skipping model thoughts
 		if event.Content != nil {
 			for _, part := range event.Content.Parts {
-				if part.Text != "" {
+				if part.Text != "" && !part.Thought {
 					responseText.WriteString(part.Text)
 				}
+				if req.OnToolCall != nil && part.FunctionCall != nil {
+					req.OnToolCall(part.FunctionCall.Name)
+				}
+				if e.citations && part.FunctionResponse != nil {
+					sources = append(sources, collectSources(part.FunctionResponse, seenSources)...)
+				}
+			}
+		}
+	}
+
+	// Count what the turn spent, with the turn's events when they're saved together
+	if e.budget.enabled() && turnUsage != usage {
+		if err := appendStateDelta(ctx, sessionService, sess, budgetDelta(turnUsage)); err != nil && e.log != nil {
+			e.log.WarnCtx(ctx, "Failed to record conversation usage",
+				logger.StringField("session_id", req.SessionID),
+				logger.ErrorField(err))
+		}
+	}
+
+	// Save whatever the turn produced, including the user's message when the agent failed
+	if ephemeral {
+		e.saveInBackground(ctx, req.SessionID, e.ephemeralTurnSaver(sessions, req.UserID, req.SessionID))
+	}
+	if batch != nil {
+		if err := batch.Flush(ctx); err != nil {
+			switch {
+			case e.degradedSessions.Enabled && ctx.Err() == nil:
+				if e.log != nil {
+					e.log.WarnCtx(ctx, "Failed to save conversation, retrying in the background",
+						logger.StringField("session_id", req.SessionID),
+						logger.ErrorField(err))
+				}
+				e.saveInBackground(ctx, req.SessionID, batch.Flush)
+			case lastError == nil:
+				return MessageResponse{}, fmt.Errorf("failed to save conversation: %w", err)
+			default:
+				if e.log != nil {
+					e.log.WarnCtx(ctx, "Failed to save conversation after agent error",
+						logger.StringField("session_id", req.SessionID),
+						logger.ErrorField(err))
+				}
 			}
 		}
 	}
@@ -166,16 +356,140 @@ func (e *Executor) Execute(
 		return MessageResponse{}, fmt.Errorf("failed to execute agent: %w", lastError)
 	}
 
-	// Add session to memory after successful execution
-	if e.memoryService != nil {
+	// Add session to memory after successful execution, once it's stored
+	if e.memoryService != nil && !ephemeral {
 		e.addSessionToMemory(ctx, req.UserID, req.SessionID)
 	}
 
+	// Cite the web pages the answer drew on, formatted for the platform
+	if len(sources) > MaxSources {
+		sources = sources[:MaxSources]
+	}
+	if len(sources) > 0 && responseText.Len() > 0 {
+		footer := FormatSourcesPlain(sources)
+		if formatter, ok := guidanceProvider.(SourcesFormatter); ok {
+			footer = formatter.FormatSources(sources)
+		}
+		responseText.WriteString("\n\n" + footer)
+	}
+
+	// Let the user know only part of their message was read
+	if inboundNote != "" && responseText.Len() > 0 {
+		responseText.WriteString("\n\n" + inboundNote)
+	}
+
 	return MessageResponse{
-		Text: responseText.String(),
+		Text:    responseText.String(),
+		Sources: sources,
 	}, nil
 }
 
+// ensureSession returns the session for userID and sessionID, creating it if it doesn't exist.
+func (e *Executor) ensureSession(ctx context.Context, userID, sessionID string) (session.Session, error) {
+	resp, err := e.sessionService.Get(ctx, &session.GetRequest{
+		AppName:   e.appName,
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err == nil {
+		return resp.Session, nil
+	}
+
+	// Session doesn't exist, create it
+	created, err := e.sessionService.Create(ctx, &session.CreateRequest{
+		AppName:   e.appName,
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	return created.Session, nil
+}
+
+// touchSession marks the conversation active for a turn that stores no events (a canned
+// reply, a rejected message or the budget notice), so it isn't treated as idle. A session
+// that doesn't exist yet is left alone.
+func (e *Executor) touchSession(ctx context.Context, req MessageRequest) {
+	toucher, ok := e.sessionService.(sessionToucher)
+	if !ok {
+		return
+	}
+	if err := toucher.Touch(ctx, e.appName, req.UserID, req.SessionID); err != nil && e.log != nil {
+		e.log.DebugCtx(ctx, "Failed to touch session",
+			logger.StringField("session_id", req.SessionID),
+			logger.ErrorField(err))
+	}
+}
+
+// updateLanguage stores the language detected in the message in session state. When the
+// message is too short to tell, the platform's locale, or else the default locale, seeds a
+// session with no language yet.
+// A language set by the user with SetLanguageOverride is never replaced.
+func (e *Executor) updateLanguage(ctx context.Context, sessions session.Service, sess session.Session, req MessageRequest) {
+	state := sess.State()
+	if language.Get(state, language.OverrideStateKey) != "" {
+		return
+	}
+
+	current := language.Get(state, language.StateKey)
+	code := language.Detect(req.Message)
+	if code == "" && current == "" {
+		code = language.Normalize(cmp.Or(req.Locale, e.defaultLocale))
+	}
+	if code == "" || code == current {
+		return
+	}
+
+	if err := appendStateDelta(ctx, sessions, sess, map[string]any{language.StateKey: code}); err != nil {
+		if e.log != nil {
+			e.log.WarnCtx(ctx, "Failed to store conversation language",
+				logger.StringField("session_id", req.SessionID),
+				logger.ErrorField(err))
+		}
+	}
+}
+
+// AppName returns the app name conversations are stored under.
+func (e *Executor) AppName() string {
+	return e.appName
+}
+
+// Maintenance returns the executor's maintenance switch, or nil if it has none.
+func (e *Executor) Maintenance() *Maintenance {
+	return e.maintenance
+}
+
+// LanguageDetectionEnabled reports whether the conversation language is detected and stored.
+func (e *Executor) LanguageDetectionEnabled() bool {
+	return e.detectLanguage
+}
+
+// SetLanguageOverride sets the language the agent responds in for a session regardless of
+// detection. An empty code clears the override so detection is used again.
+func (e *Executor) SetLanguageOverride(ctx context.Context, userID, sessionID, code string) error {
+	sess, err := e.ensureSession(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if deleter, ok := e.sessionService.(stateKeyDeleter); ok && code == "" {
+		return deleter.DeleteStateKeys(ctx, sess, language.OverrideStateKey)
+	}
+	return appendStateDelta(ctx, e.sessionService, sess, map[string]any{language.OverrideStateKey: code})
+}
+
+// appendStateDelta records state changes on a session as a content-less event,
+// which the agent doesn't see as part of the conversation.
+func appendStateDelta(ctx context.Context, sessions session.Service, sess session.Session, delta map[string]any) error {
+	event := session.NewEvent("")
+	event.Author = "user"
+	event.Actions.StateDelta = delta
+	if err := sessions.AppendEvent(ctx, sess, event); err != nil {
+		return fmt.Errorf("failed to update session state: %w", err)
+	}
+	return nil
+}
+
 // addSessionToMemory adds the current session to memory storage.
 func (e *Executor) addSessionToMemory(ctx context.Context, userID, sessionID string) {
 	sess, err := e.sessionService.Get(ctx, &session.GetRequest{
@@ -185,7 +499,7 @@ func (e *Executor) addSessionToMemory(ctx context.Context, userID, sessionID str
 	})
 	if err != nil {
 		if e.log != nil {
-			e.log.Warn("Failed to get session for memory",
+			e.log.WarnCtx(ctx, "Failed to get session for memory",
 				logger.StringField("session_id", sessionID),
 				logger.ErrorField(err))
 		}
@@ -194,7 +508,7 @@ func (e *Executor) addSessionToMemory(ctx context.Context, userID, sessionID str
 
 	if err := e.memoryService.AddSession(ctx, sess.Session); err != nil {
 		if e.log != nil {
-			e.log.Warn("Failed to add session to memory",
+			e.log.WarnCtx(ctx, "Failed to add session to memory",
 				logger.StringField("session_id", sessionID),
 				logger.ErrorField(err))
 		}
@@ -0,0 +1,25 @@
+package executor
+
+import "strings"
+
+// Introduction returns how a bot introduces itself to users in greetings and help, e.g.
+// "Hi, I'm Support Bot. I answer billing questions." Either part may be empty; it returns
+// "" when both are.
+func Introduction(name, intro string) string {
+	var parts []string
+	if name = strings.TrimSpace(name); name != "" {
+		parts = append(parts, "Hi, I'm "+name+".")
+	}
+	if intro = strings.TrimSpace(intro); intro != "" {
+		parts = append(parts, intro)
+	}
+	return strings.Join(parts, " ")
+}
+
+// BotIdentity is the bot's own account on a platform, which connectors look up once and
+// cache
+type BotIdentity struct {
+	UserID string // How the bot appears in mentions
+	BotID  string // Slack's separate bot ID; empty on other platforms
+	Name   string // The bot's username
+}
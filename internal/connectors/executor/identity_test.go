@@ -0,0 +1,26 @@
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+)
+
+func TestIntroduction(t *testing.T) {
+	tests := []struct {
+		name, displayName, intro, want string
+	}{
+		{"name and intro", "Support Bot", "I answer billing questions.", "Hi, I'm Support Bot. I answer billing questions."},
+		{"name only", " Support Bot ", "", "Hi, I'm Support Bot."},
+		{"intro only", "", "I answer billing questions.", "I answer billing questions."},
+		{"neither", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := executor.Introduction(tt.displayName, tt.intro); got != tt.want {
+				t.Errorf("Introduction(%q, %q) = %q, want %q", tt.displayName, tt.intro, got, tt.want)
+			}
+		})
+	}
+}
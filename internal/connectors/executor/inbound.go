@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/tokens"
+)
+
+// DefaultOversizeMessage is sent when a message over the inbound limit is rejected
+const DefaultOversizeMessage = "Sorry, your message is too long for me to process. Please shorten it or split it into smaller parts."
+
+// InboundLimit caps the length of incoming messages so a long paste doesn't blow the
+// context window. Messages over the limit are truncated with a note, or rejected.
+type InboundLimit struct {
+	MaxChars      int    // Longest message in characters; 0 for no character limit
+	MaxTokens     int    // Longest message in estimated tokens; 0 for no token limit
+	Model         string // Model the tokens are estimated for; see tokens.Count
+	Reject        bool   // Reject oversized messages instead of truncating them
+	RejectMessage string // Reply to a rejected message; DefaultOversizeMessage if empty
+}
+
+// rejectMessage returns the reply to a rejected message
+func (l InboundLimit) rejectMessage() string {
+	if l.RejectMessage != "" {
+		return l.RejectMessage
+	}
+	return DefaultOversizeMessage
+}
+
+// apply checks message against the limit. It returns the message to send to the agent,
+// truncated with a note for the agent if needed, and a note for the user when the message
+// was over the limit. rejected is set when the message must not be processed at all.
+func (l InboundLimit) apply(message string) (text, note string, rejected bool) {
+	length := utf8.RuneCountInString(message)
+	overChars := l.MaxChars > 0 && length > l.MaxChars
+	overTokens := l.MaxTokens > 0 && tokens.Count(l.Model, message) > l.MaxTokens
+	if !overChars && !overTokens {
+		return message, "", false
+	}
+	if l.Reject {
+		return "", l.rejectMessage(), true
+	}
+
+	text = message
+	if overChars {
+		text = truncateRunes(text, l.MaxChars)
+	}
+	// Shrink in proportion to the excess until the estimate fits
+	for l.MaxTokens > 0 && text != "" {
+		count := tokens.Count(l.Model, text)
+		if count <= l.MaxTokens {
+			break
+		}
+		runes := utf8.RuneCountInString(text)
+		text = truncateRunes(text, min(runes*l.MaxTokens/count, runes-1))
+	}
+
+	kept := utf8.RuneCountInString(text)
+	text = fmt.Sprintf("%s\n\n[The user's message was truncated to its first %d of %d characters.]", text, kept, length)
+	note = fmt.Sprintf("(Your message was %d characters long, so I only read the first %d.)", length, kept)
+	return text, note, false
+}
+
+// truncateRunes returns the first n runes of s, cutting at a rune boundary so
+// multi-byte characters aren't split
+func truncateRunes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	runes := 0
+	for i := range s {
+		if runes == n {
+			return s[:i]
+		}
+		runes++
+	}
+	return s
+}
@@ -0,0 +1,182 @@
+package executor_test
+
+import (
+	"context"
+	"io"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// messageRecordingModel records the text of the last user message of each request and replies "ok"
+type messageRecordingModel struct {
+	messages []string
+}
+
+func (m *messageRecordingModel) Name() string { return "fake-model" }
+
+func (m *messageRecordingModel) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	var text strings.Builder
+	if len(req.Contents) > 0 {
+		for _, part := range req.Contents[len(req.Contents)-1].Parts {
+			text.WriteString(part.Text)
+		}
+	}
+	m.messages = append(m.messages, text.String())
+
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{Content: genai.NewContentFromText("ok", genai.RoleModel)}, nil)
+	}
+}
+
+func newInboundExecutor(t *testing.T, limit executor.InboundLimit) (*executor.Executor, *messageRecordingModel) {
+	t.Helper()
+	return newRecordingExecutor(t, executor.Config{InboundLimit: limit})
+}
+
+// newRecordingExecutor creates an executor from cfg backed by a messageRecordingModel and
+// in-memory services
+func newRecordingExecutor(t *testing.T, cfg executor.Config) (*executor.Executor, *messageRecordingModel) {
+	t.Helper()
+	llm := &messageRecordingModel{}
+	return newTestExecutor(t, llm, cfg), llm
+}
+
+// newTestExecutor creates an executor for app "test" from cfg. An agent backed by llm is
+// used unless cfg has an agent factory, and in-memory services and a discarding logger
+// fill in what cfg leaves unset.
+func newTestExecutor(t *testing.T, llm model.LLM, cfg executor.Config) *executor.Executor {
+	t.Helper()
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+
+	if cfg.AgentFactory == nil {
+		factories, err := agents.NewChatAgentsWithToolsets(context.Background(), llm, []agents.AgentConfig{{
+			Name:   "test_agent",
+			Logger: log,
+		}}, nil, nil)
+		if err != nil {
+			t.Fatalf("NewChatAgentsWithToolsets() error = %v", err)
+		}
+		cfg.AgentFactory = factories[0]
+	}
+	cfg.AppName = "test"
+	if cfg.SessionService == nil {
+		cfg.SessionService = session.InMemoryService()
+	}
+	if cfg.ArtifactService == nil {
+		cfg.ArtifactService = artifact.InMemoryService()
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log
+	}
+	exec, err := executor.NewExecutorWithConfig(cfg)
+	if err != nil {
+		t.Fatalf("NewExecutorWithConfig() error = %v", err)
+	}
+	return exec
+}
+
+func executeMessage(t *testing.T, exec *executor.Executor, message string) executor.MessageResponse {
+	t.Helper()
+	resp, err := exec.Execute(context.Background(), executor.MessageRequest{
+		UserID:    "user1",
+		SessionID: "session1",
+		Message:   message,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	return resp
+}
+
+func TestExecute_TruncatesOversizedMessage(t *testing.T) {
+	exec, llm := newInboundExecutor(t, executor.InboundLimit{MaxChars: 10})
+
+	resp := executeMessage(t, exec, "héllo wörld, this part is cut off")
+
+	if len(llm.messages) != 1 {
+		t.Fatalf("model called %d times, want 1", len(llm.messages))
+	}
+	sent := llm.messages[0]
+	if !strings.HasPrefix(sent, "héllo wörl\n\n[") || strings.Contains(sent, "cut off") {
+		t.Errorf("model received %q, want the first 10 characters and a note", sent)
+	}
+	if !strings.Contains(sent, "truncated to its first 10 of 33 characters") {
+		t.Errorf("model received %q, want a truncation note", sent)
+	}
+	if !strings.HasPrefix(resp.Text, "ok") || !strings.Contains(resp.Text, "only read the first 10") {
+		t.Errorf("response = %q, want the reply followed by a note for the user", resp.Text)
+	}
+}
+
+func TestExecute_TokenLimitTruncates(t *testing.T) {
+	exec, llm := newInboundExecutor(t, executor.InboundLimit{MaxChars: 1000, MaxTokens: 5})
+
+	executeMessage(t, exec, strings.Repeat("a", 100))
+
+	if len(llm.messages) != 1 || !strings.HasPrefix(llm.messages[0], strings.Repeat("a", 20)+"\n\n[") {
+		t.Errorf("model received %q, want the message cut to about 5 tokens", llm.messages)
+	}
+}
+
+func TestExecute_RejectsOversizedMessage(t *testing.T) {
+	exec, llm := newInboundExecutor(t, executor.InboundLimit{MaxChars: 10, Reject: true, RejectMessage: "Too long, please shorten it."})
+
+	resp := executeMessage(t, exec, "this message is far too long")
+
+	if len(llm.messages) != 0 {
+		t.Errorf("model called %d times, want no call for a rejected message", len(llm.messages))
+	}
+	if resp.Text != "Too long, please shorten it." {
+		t.Errorf("response = %q, want the configured rejection message", resp.Text)
+	}
+
+	// Messages within the limit are processed unchanged
+	resp = executeMessage(t, exec, "short")
+	if len(llm.messages) != 1 || llm.messages[0] != "short" || resp.Text != "ok" {
+		t.Errorf("model received %q and replied %q, want the message unchanged", llm.messages, resp.Text)
+	}
+}
+
+func TestExecute_RejectUsesDefaultMessage(t *testing.T) {
+	exec, _ := newInboundExecutor(t, executor.InboundLimit{MaxTokens: 2, Reject: true})
+
+	resp := executeMessage(t, exec, "this message is far too long")
+
+	if resp.Text != executor.DefaultOversizeMessage {
+		t.Errorf("response = %q, want the default rejection message", resp.Text)
+	}
+}
+
+// touchRecordingService is an in-memory session service that records the sessions touched
+type touchRecordingService struct {
+	session.Service
+	touched []string
+}
+
+func (s *touchRecordingService) Touch(_ context.Context, _, _, sessionID string) error {
+	s.touched = append(s.touched, sessionID)
+	return nil
+}
+
+func TestExecute_RejectedMessageTouchesSession(t *testing.T) {
+	sessions := &touchRecordingService{Service: session.InMemoryService()}
+	exec, _ := newRecordingExecutor(t, executor.Config{
+		SessionService: sessions,
+		InboundLimit:   executor.InboundLimit{MaxChars: 10, Reject: true},
+	})
+
+	executeMessage(t, exec, "this message is far too long")
+
+	if len(sessions.touched) != 1 || sessions.touched[0] != "session1" {
+		t.Errorf("touched sessions = %q, want the rejected message's session marked active", sessions.touched)
+	}
+}
@@ -0,0 +1,151 @@
+package executor_test
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/telegram"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/language"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// instructionRecordingModel records the system instruction of each request and replies "ok"
+type instructionRecordingModel struct {
+	instructions []string
+}
+
+func (m *instructionRecordingModel) Name() string { return "fake-model" }
+
+func (m *instructionRecordingModel) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	var instruction strings.Builder
+	if req.Config != nil && req.Config.SystemInstruction != nil {
+		for _, part := range req.Config.SystemInstruction.Parts {
+			instruction.WriteString(part.Text)
+		}
+	}
+	m.instructions = append(m.instructions, instruction.String())
+
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{Content: genai.NewContentFromText("ok", genai.RoleModel)}, nil)
+	}
+}
+
+func (m *instructionRecordingModel) lastInstruction() string {
+	if len(m.instructions) == 0 {
+		return ""
+	}
+	return m.instructions[len(m.instructions)-1]
+}
+
+type languageTestSetup struct {
+	exec     *executor.Executor
+	sessions session.Service
+	llm      *instructionRecordingModel
+}
+
+func newLanguageExecutor(t *testing.T, detect bool) languageTestSetup {
+	t.Helper()
+	llm := &instructionRecordingModel{}
+	sessions := session.InMemoryService()
+	exec := newTestExecutor(t, llm, executor.Config{SessionService: sessions, DetectLanguage: detect})
+	return languageTestSetup{exec: exec, sessions: sessions, llm: llm}
+}
+
+func (s languageTestSetup) execute(t *testing.T, message, locale string) {
+	t.Helper()
+	_, err := s.exec.Execute(context.Background(), executor.MessageRequest{
+		UserID:    "user1",
+		SessionID: "session1",
+		Message:   message,
+		Locale:    locale,
+	}, &telegram.Connector{}, nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+}
+
+func (s languageTestSetup) stateLanguage(t *testing.T, key string) string {
+	t.Helper()
+	resp, err := s.sessions.Get(context.Background(), &session.GetRequest{
+		AppName: "test", UserID: "user1", SessionID: "session1",
+	})
+	if err != nil {
+		t.Fatalf("failed to get session: %v", err)
+	}
+	return language.Get(resp.Session.State(), key)
+}
+
+func TestExecute_DetectsLanguage(t *testing.T) {
+	s := newLanguageExecutor(t, true)
+
+	s.execute(t, "Hola, ¿cómo puedo cambiar la contraseña de mi cuenta?", "en-US")
+
+	if got := s.stateLanguage(t, language.StateKey); got != "es" {
+		t.Errorf("stored language = %q, want %q", got, "es")
+	}
+	if !strings.Contains(s.llm.lastInstruction(), "Respond in Spanish") {
+		t.Errorf("instruction should ask for Spanish, got %q", s.llm.lastInstruction())
+	}
+
+	// A message too short to detect keeps the conversation's language
+	s.execute(t, "ok", "en-US")
+	if got := s.stateLanguage(t, language.StateKey); got != "es" {
+		t.Errorf("stored language after short message = %q, want %q", got, "es")
+	}
+}
+
+func TestExecute_LocaleSeedsLanguage(t *testing.T) {
+	s := newLanguageExecutor(t, true)
+
+	s.execute(t, "ok", "de-DE")
+
+	if got := s.stateLanguage(t, language.StateKey); got != "de" {
+		t.Errorf("stored language = %q, want %q", got, "de")
+	}
+	if !strings.Contains(s.llm.lastInstruction(), "Respond in German") {
+		t.Errorf("instruction should ask for German, got %q", s.llm.lastInstruction())
+	}
+}
+
+func TestExecute_LanguageOverride(t *testing.T) {
+	s := newLanguageExecutor(t, true)
+
+	if err := s.exec.SetLanguageOverride(context.Background(), "user1", "session1", "fr"); err != nil {
+		t.Fatalf("SetLanguageOverride() error = %v", err)
+	}
+	s.execute(t, "How do I reset my password for this account?", "")
+
+	if got := s.stateLanguage(t, language.OverrideStateKey); got != "fr" {
+		t.Errorf("stored override = %q, want %q", got, "fr")
+	}
+	if !strings.Contains(s.llm.lastInstruction(), "Respond in French") {
+		t.Errorf("override should ask for French, got %q", s.llm.lastInstruction())
+	}
+
+	// Clearing the override goes back to detection
+	if err := s.exec.SetLanguageOverride(context.Background(), "user1", "session1", ""); err != nil {
+		t.Fatalf("SetLanguageOverride() error = %v", err)
+	}
+	s.execute(t, "How do I reset my password for this account?", "")
+	if !strings.Contains(s.llm.lastInstruction(), "Respond in English") {
+		t.Errorf("instruction should follow detection again, got %q", s.llm.lastInstruction())
+	}
+}
+
+func TestExecute_LanguageDetectionDisabled(t *testing.T) {
+	s := newLanguageExecutor(t, false)
+
+	s.execute(t, "Hola, ¿cómo puedo cambiar la contraseña de mi cuenta?", "en-US")
+
+	if got := s.stateLanguage(t, language.StateKey); got != "" {
+		t.Errorf("stored language = %q, want none", got)
+	}
+	if strings.Contains(s.llm.lastInstruction(), "## Language") {
+		t.Errorf("instruction should not mention a language, got %q", s.llm.lastInstruction())
+	}
+}
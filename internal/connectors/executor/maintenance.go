@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"strings"
+	"sync"
+)
+
+// DefaultMaintenanceMessage is the reply sent while maintenance mode is on
+const DefaultMaintenanceMessage = "I'm down for maintenance right now and can't answer. Please try again later."
+
+// Maintenance is a runtime switch that pauses LLM calls, e.g. during an incident. While it's
+// on, Execute replies with the maintenance message without calling the model or tools and
+// without recording the turn. One Maintenance is shared by every executor so a toggle from
+// any platform (or a signal) applies everywhere. A nil *Maintenance is always off.
+type Maintenance struct {
+	mu             sync.RWMutex
+	enabled        bool
+	message        string
+	defaultMessage string
+}
+
+// NewMaintenance creates a maintenance switch. defaultMessage is the reply used when the
+// mode is enabled without a message; DefaultMaintenanceMessage if empty.
+func NewMaintenance(enabled bool, defaultMessage string) *Maintenance {
+	if defaultMessage == "" {
+		defaultMessage = DefaultMaintenanceMessage
+	}
+	return &Maintenance{enabled: enabled, message: defaultMessage, defaultMessage: defaultMessage}
+}
+
+// Enable turns maintenance mode on, replying with message (or the default message if empty).
+func (m *Maintenance) Enable(message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if message == "" {
+		message = m.defaultMessage
+	}
+	m.enabled = true
+	m.message = message
+}
+
+// Disable turns maintenance mode off.
+func (m *Maintenance) Disable() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enabled = false
+}
+
+// Toggle flips maintenance mode, using the default message when turning it on,
+// and returns whether it's now on.
+func (m *Maintenance) Toggle() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.enabled = !m.enabled
+	if m.enabled {
+		m.message = m.defaultMessage
+	}
+	return m.enabled
+}
+
+// Status reports whether maintenance mode is on and the message sent while it is.
+func (m *Maintenance) Status() (bool, string) {
+	if m == nil {
+		return false, ""
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabled, m.message
+}
+
+// MaintenanceCommand applies a maintenance admin command ("on [message]", "off" or "status")
+// and returns the reply for the admin, and whether the command turned the mode on or off
+// (for auditing). Connectors check the caller is an admin first.
+func MaintenanceCommand(m *Maintenance, args string) (reply string, changed bool) {
+	if m == nil {
+		return "Maintenance mode isn't available.", false
+	}
+
+	action, message, _ := strings.Cut(strings.TrimSpace(args), " ")
+	switch strings.ToLower(action) {
+	case "on":
+		m.Enable(strings.TrimSpace(message))
+		_, message = m.Status()
+		return "Maintenance mode is on. Replying with: " + message, true
+	case "off":
+		m.Disable()
+		return "Maintenance mode is off.", true
+	case "", "status":
+		if enabled, message := m.Status(); enabled {
+			return "Maintenance mode is on. Replying with: " + message, false
+		}
+		return "Maintenance mode is off.", false
+	default:
+		return "Usage: /maintenance on [message] | off | status", false
+	}
+}
@@ -0,0 +1,86 @@
+package executor_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/session"
+)
+
+func TestExecute_MaintenanceModeSkipsModel(t *testing.T) {
+	llm := &messageRecordingModel{}
+	factories, err := agents.NewChatAgentsWithToolsets(context.Background(), llm, []agents.AgentConfig{{
+		Name:   "test_agent",
+		Logger: logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard}),
+	}}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewChatAgentsWithToolsets() error = %v", err)
+	}
+
+	sessions := session.InMemoryService()
+	maintenance := executor.NewMaintenance(true, "Back soon.")
+	exec, err := executor.NewExecutorWithConfig(executor.Config{
+		AgentFactory:    factories[0],
+		AppName:         "test",
+		SessionService:  sessions,
+		ArtifactService: artifact.InMemoryService(),
+		Maintenance:     maintenance,
+	})
+	if err != nil {
+		t.Fatalf("NewExecutorWithConfig() error = %v", err)
+	}
+
+	resp := executeMessage(t, exec, "hello")
+	if resp.Text != "Back soon." {
+		t.Errorf("response = %q, want the maintenance message", resp.Text)
+	}
+	if len(llm.messages) != 0 {
+		t.Errorf("model called %d times, want no call in maintenance mode", len(llm.messages))
+	}
+
+	// The maintenance reply isn't recorded as a turn
+	if _, err := sessions.Get(context.Background(), &session.GetRequest{AppName: "test", UserID: "user1", SessionID: "session1"}); err == nil {
+		t.Error("session was created in maintenance mode, want none")
+	}
+
+	// Normal operation resumes once maintenance mode is cleared
+	maintenance.Disable()
+	resp = executeMessage(t, exec, "hello")
+	if resp.Text != "ok" || len(llm.messages) != 1 || llm.messages[0] != "hello" {
+		t.Errorf("model received %q and replied %q, want a normal reply", llm.messages, resp.Text)
+	}
+}
+
+func TestMaintenanceCommand(t *testing.T) {
+	m := executor.NewMaintenance(false, "")
+
+	if got, changed := executor.MaintenanceCommand(m, "status"); got != "Maintenance mode is off." || changed {
+		t.Errorf("status = %q, %v, want off and unchanged", got, changed)
+	}
+
+	if _, changed := executor.MaintenanceCommand(m, "on Upgrading, back at 3pm"); !changed {
+		t.Error("on: changed = false, want true")
+	}
+	if enabled, message := m.Status(); !enabled || message != "Upgrading, back at 3pm" {
+		t.Errorf("after on: Status() = %v, %q, want enabled with the given message", enabled, message)
+	}
+
+	executor.MaintenanceCommand(m, "off")
+	if enabled, _ := m.Status(); enabled {
+		t.Error("after off: maintenance mode still enabled")
+	}
+
+	executor.MaintenanceCommand(m, "ON")
+	if enabled, message := m.Status(); !enabled || message != executor.DefaultMaintenanceMessage {
+		t.Errorf("after ON: Status() = %v, %q, want enabled with the default message", enabled, message)
+	}
+
+	if got, _ := executor.MaintenanceCommand(m, "later"); got != "Usage: /maintenance on [message] | off | status" {
+		t.Errorf("unknown action = %q, want usage", got)
+	}
+}
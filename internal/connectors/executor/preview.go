@@ -0,0 +1,200 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// Preview is the request the agent would send to the model for a message
+type Preview struct {
+	Agent        string                       // Name of the agent that would handle the message
+	SystemPrompt string                       // The agent's instructions
+	Contents     []*genai.Content             // The conversation history, ending with the user's message
+	Tools        []*genai.FunctionDeclaration // Tools the model would be offered
+}
+
+// Text renders the preview for reading, e.g. in a terminal
+func (p Preview) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "=== Agent: %s ===\n\n", p.Agent)
+	fmt.Fprintf(&b, "=== System prompt ===\n%s\n\n", p.SystemPrompt)
+
+	fmt.Fprintf(&b, "=== Tools (%d) ===\n", len(p.Tools))
+	for _, declaration := range p.Tools {
+		fmt.Fprintf(&b, "- %s: %s\n", declaration.Name, declaration.Description)
+	}
+
+	fmt.Fprintf(&b, "\n=== Contents (%d) ===\n", len(p.Contents))
+	for _, content := range p.Contents {
+		for _, part := range content.Parts {
+			switch {
+			case part.Text != "":
+				fmt.Fprintf(&b, "[%s] %s\n", content.Role, part.Text)
+			case part.FunctionCall != nil:
+				args, _ := json.Marshal(part.FunctionCall.Args)
+				fmt.Fprintf(&b, "[%s] call %s %s\n", content.Role, part.FunctionCall.Name, args)
+			case part.FunctionResponse != nil:
+				response, _ := json.Marshal(part.FunctionResponse.Response)
+				fmt.Fprintf(&b, "[%s] result of %s %s\n", content.Role, part.FunctionResponse.Name, response)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Preview assembles the request the agent would send to the model for req, with the
+// session's history, without calling the model or changing the stored session. Maintenance
+// mode, canned responses and budgets are ignored, so the prompt can be inspected even when
+// Execute wouldn't reach the model.
+func (e *Executor) Preview(
+	ctx context.Context,
+	req MessageRequest,
+	guidanceProvider agents.PlatformSpecificGuidanceProvider,
+	userInfoFunc agents.UserInfoFunc,
+) (Preview, error) {
+	if req.UserID == "" {
+		return Preview{}, fmt.Errorf("userID is required")
+	}
+	if req.SessionID == "" {
+		return Preview{}, fmt.Errorf("sessionID is required")
+	}
+	if req.Message == "" {
+		return Preview{}, fmt.Errorf("message is required")
+	}
+
+	// The message is trimmed and limited as Execute would before the model sees it
+	if trimmedMessage, trimmed := e.quoteTrim.apply(req.Message); trimmed {
+		req.Message = trimmedMessage
+	}
+	message, inboundNote, rejected := e.inboundLimit.apply(req.Message)
+	if rejected {
+		return Preview{}, fmt.Errorf("message would be rejected: %s", inboundNote)
+	}
+	req.Message = message
+
+	// Run on a copy of the conversation so the preview leaves nothing behind
+	sessions, sess, err := e.previewSession(ctx, req.UserID, req.SessionID)
+	if err != nil {
+		return Preview{}, err
+	}
+	if e.detectLanguage {
+		e.updateLanguage(ctx, sessions, sess, req)
+	}
+
+	agentName, agentFactory := e.agentFactoryFor(ctx, req)
+	agentInstance, err := agentFactory(guidanceProvider, userInfoFunc)
+	if err != nil {
+		return Preview{}, fmt.Errorf("failed to create agent instance: %w", err)
+	}
+
+	// Capture the model request and answer it with an empty response instead
+	var captured *model.LLMRequest
+	capture, err := plugin.New(plugin.Config{
+		Name: "preview",
+		BeforeModelCallback: func(_ agent.CallbackContext, llmReq *model.LLMRequest) (*model.LLMResponse, error) {
+			if captured == nil {
+				captured = llmReq
+			}
+			return &model.LLMResponse{}, nil
+		},
+	})
+	if err != nil {
+		return Preview{}, fmt.Errorf("failed to create preview plugin: %w", err)
+	}
+
+	r, err := runner.New(runner.Config{
+		AppName:         e.appName,
+		SessionService:  sessions,
+		ArtifactService: e.artifactService,
+		Agent:           agentInstance,
+		PluginConfig:    runner.PluginConfig{Plugins: []*plugin.Plugin{capture}},
+	})
+	if err != nil {
+		return Preview{}, fmt.Errorf("failed to create runner: %w", err)
+	}
+
+	content := genai.NewContentFromText(req.Message, "user")
+	for event, err := range r.Run(ctx, req.UserID, req.SessionID, content, agent.RunConfig{StreamingMode: agent.StreamingModeNone}) {
+		if err != nil {
+			return Preview{}, fmt.Errorf("failed to assemble prompt: %w", err)
+		}
+		if event != nil && event.ErrorMessage != "" {
+			return Preview{}, fmt.Errorf("agent error [%s]: %s", event.ErrorCode, event.ErrorMessage)
+		}
+	}
+	if captured == nil {
+		return Preview{}, fmt.Errorf("agent %q made no model call", agentInstance.Name())
+	}
+
+	preview := Preview{
+		Agent:    agentInstance.Name(),
+		Contents: captured.Contents,
+	}
+	if agentName != "" {
+		preview.Agent = agentName
+	}
+	if captured.Config != nil {
+		if instruction := captured.Config.SystemInstruction; instruction != nil {
+			var texts []string
+			for _, part := range instruction.Parts {
+				if part.Text != "" {
+					texts = append(texts, part.Text)
+				}
+			}
+			preview.SystemPrompt = strings.Join(texts, "\n\n")
+		}
+		for _, t := range captured.Config.Tools {
+			if t != nil {
+				preview.Tools = append(preview.Tools, t.FunctionDeclarations...)
+			}
+		}
+	}
+	return preview, nil
+}
+
+// previewSession copies the stored session, if there is one, to an in-memory session
+func (e *Executor) previewSession(ctx context.Context, userID, sessionID string) (session.Service, session.Session, error) {
+	sessions := session.InMemoryService()
+	createReq := &session.CreateRequest{
+		AppName:   e.appName,
+		UserID:    userID,
+		SessionID: sessionID,
+	}
+
+	var events []*session.Event
+	stored, err := e.sessionService.Get(ctx, &session.GetRequest{
+		AppName:   e.appName,
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err == nil {
+		createReq.State = make(map[string]any)
+		for key, value := range stored.Session.State().All() {
+			createReq.State[key] = value
+		}
+		for event := range stored.Session.Events().All() {
+			events = append(events, event)
+		}
+	}
+
+	created, err := sessions.Create(ctx, createReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create preview session: %w", err)
+	}
+	for _, event := range events {
+		if err := sessions.AppendEvent(ctx, created.Session, event); err != nil {
+			return nil, nil, fmt.Errorf("failed to copy session history: %w", err)
+		}
+	}
+	return sessions, created.Session, nil
+}
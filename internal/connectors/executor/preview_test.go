@@ -0,0 +1,123 @@
+package executor_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/session"
+)
+
+func TestPreview_AssemblesPromptWithoutCallingModel(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+	llm := &instructionRecordingModel{}
+	sessions := session.InMemoryService()
+
+	factories, err := agents.NewChatAgentsWithToolsets(ctx, llm, []agents.AgentConfig{{
+		Name:           "test_agent",
+		Logger:         log,
+		PromptProvider: agents.StaticPrompt("You are the preview test assistant."),
+	}}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewChatAgentsWithToolsets() error = %v", err)
+	}
+	exec, err := executor.NewExecutorWithConfig(executor.Config{
+		AgentFactory:    factories[0],
+		AppName:         "test",
+		SessionService:  sessions,
+		ArtifactService: artifact.InMemoryService(),
+	})
+	if err != nil {
+		t.Fatalf("NewExecutorWithConfig() error = %v", err)
+	}
+
+	// An earlier turn gives the conversation some history
+	if _, err := exec.Execute(ctx, executor.MessageRequest{UserID: "u1", SessionID: "s1", Message: "first question"}, nil, nil); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	calls := len(llm.instructions)
+	stored, err := sessions.Get(ctx, &session.GetRequest{AppName: "test", UserID: "u1", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	events := stored.Session.Events().Len()
+
+	preview, err := exec.Preview(ctx, executor.MessageRequest{UserID: "u1", SessionID: "s1", Message: "second question"}, nil, nil)
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+
+	if len(llm.instructions) != calls {
+		t.Errorf("model was called %d times by the preview, want 0", len(llm.instructions)-calls)
+	}
+	if preview.Agent != "test_agent" {
+		t.Errorf("Agent = %q, want %q", preview.Agent, "test_agent")
+	}
+	if !strings.Contains(preview.SystemPrompt, "You are the preview test assistant.") {
+		t.Errorf("SystemPrompt should contain the agent's prompt, got %q", preview.SystemPrompt)
+	}
+	if len(preview.Contents) == 0 {
+		t.Fatal("preview has no contents")
+	}
+	last := preview.Contents[len(preview.Contents)-1]
+	if last.Role != "user" || len(last.Parts) == 0 || last.Parts[0].Text != "second question" {
+		t.Errorf("last content = %+v, want the user's message", last)
+	}
+	text := preview.Text()
+	for _, want := range []string{"You are the preview test assistant.", "first question", "second question"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text() should contain %q, got:\n%s", want, text)
+		}
+	}
+
+	// The stored conversation is left as it was
+	stored, err = sessions.Get(ctx, &session.GetRequest{AppName: "test", UserID: "u1", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := stored.Session.Events().Len(); got != events {
+		t.Errorf("session has %d events after the preview, want %d", got, events)
+	}
+}
+
+func TestPreview_NewSessionIsNotCreated(t *testing.T) {
+	ctx := context.Background()
+	llm := &meteredModel{}
+	sessions := session.InMemoryService()
+	exec, err := executor.NewExecutorWithConfig(executor.Config{
+		AgentFactory: func(agents.PlatformSpecificGuidanceProvider, agents.UserInfoFunc) (agent.Agent, error) {
+			return llmagent.New(llmagent.Config{Name: "test_agent", Model: llm, Instruction: "Be brief."})
+		},
+		AppName:         "test",
+		SessionService:  sessions,
+		ArtifactService: artifact.InMemoryService(),
+	})
+	if err != nil {
+		t.Fatalf("NewExecutorWithConfig() error = %v", err)
+	}
+
+	preview, err := exec.Preview(ctx, executor.MessageRequest{UserID: "u1", SessionID: "new", Message: "hello"}, nil, nil)
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if llm.calls != 0 {
+		t.Errorf("model was called %d times, want 0", llm.calls)
+	}
+	if preview.SystemPrompt != "Be brief." {
+		t.Errorf("SystemPrompt = %q, want %q", preview.SystemPrompt, "Be brief.")
+	}
+	if len(preview.Contents) != 1 || preview.Contents[0].Parts[0].Text != "hello" {
+		t.Errorf("Contents = %+v, want just the user's message", preview.Contents)
+	}
+	if _, err := sessions.Get(ctx, &session.GetRequest{AppName: "test", UserID: "u1", SessionID: "new"}); err == nil {
+		t.Error("preview should not create the session")
+	}
+}
@@ -0,0 +1,117 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Quote trimming defaults
+const (
+	DefaultQuoteTrimThreshold = 2000
+	DefaultQuoteKeepLines     = 5
+)
+
+// linkPattern matches URLs, including those inside Slack's <url|label> markup
+var linkPattern = regexp.MustCompile(`https?://[^\s<>|]+`)
+
+// QuoteTrim shortens the quoted content of long messages, such as forwarded alerts or pasted
+// email threads, so it doesn't bloat the prompt. Messages longer than Threshold characters
+// have each quoted block ("> " lines, including Slack's "&gt; ", and ``` fenced blocks) cut to
+// its first KeepLines lines. The user's own text is never trimmed, and links in trimmed lines
+// are listed at the end so none are lost. The zero value is disabled.
+type QuoteTrim struct {
+	Enabled   bool
+	Threshold int // Message length in characters above which quotes are trimmed; DefaultQuoteTrimThreshold if 0
+	KeepLines int // Lines kept from each quoted block; DefaultQuoteKeepLines if 0
+}
+
+// apply returns message with its long quoted blocks trimmed, and whether anything was cut
+func (q QuoteTrim) apply(message string) (string, bool) {
+	if !q.Enabled {
+		return message, false
+	}
+	threshold := q.Threshold
+	if threshold <= 0 {
+		threshold = DefaultQuoteTrimThreshold
+	}
+	if utf8.RuneCountInString(message) <= threshold {
+		return message, false
+	}
+	keep := q.KeepLines
+	if keep <= 0 {
+		keep = DefaultQuoteKeepLines
+	}
+
+	lines := strings.Split(message, "\n")
+	out := make([]string, 0, len(lines))
+	var links []string
+	seen := make(map[string]bool)
+	trimmed := false
+
+	// trimBlock keeps the first lines of a quoted block and records the links of the rest
+	trimBlock := func(block []string, marker string) {
+		if len(block) <= keep {
+			out = append(out, block...)
+			return
+		}
+		out = append(out, block[:keep]...)
+		for _, line := range block[keep:] {
+			for _, link := range linkPattern.FindAllString(line, -1) {
+				if !seen[link] {
+					seen[link] = true
+					links = append(links, link)
+				}
+			}
+		}
+		out = append(out, fmt.Sprintf(marker, len(block)-keep))
+		trimmed = true
+	}
+
+	for i := 0; i < len(lines); {
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(lines[i]), "```"):
+			// A fenced block runs to its closing fence (or the end of the message); the
+			// fences themselves are kept so the block stays well-formed
+			end := i + 1
+			for end < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[end]), "```") {
+				end++
+			}
+			out = append(out, lines[i])
+			trimBlock(lines[i+1:end], "[… %d more lines trimmed]")
+			if end < len(lines) {
+				out = append(out, lines[end])
+				end++
+			}
+			i = end
+
+		case isQuoteLine(lines[i]):
+			end := i
+			for end < len(lines) && isQuoteLine(lines[end]) {
+				end++
+			}
+			trimBlock(lines[i:end], "> [… %d more quoted lines trimmed]")
+			i = end
+
+		default:
+			out = append(out, lines[i])
+			i++
+		}
+	}
+
+	if !trimmed {
+		return message, false
+	}
+	result := strings.Join(out, "\n")
+	if len(links) > 0 {
+		result += "\n\n[Links from trimmed content: " + strings.Join(links, " ") + "]"
+	}
+	return result, true
+}
+
+// isQuoteLine reports whether line is part of a "> " quote; Slack escapes the marker as &gt;
+func isQuoteLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, ">") || strings.HasPrefix(line, "&gt;")
+}
@@ -0,0 +1,93 @@
+package executor_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+)
+
+// forwardedAlert builds a message with a question, a long Slack-escaped quote and a long
+// code block, with a link on a line that gets trimmed from each
+func forwardedAlert() string {
+	var b strings.Builder
+	b.WriteString("Can you tell me why this alert keeps firing? See https://runbooks.example.com/disk\n")
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&b, "&gt; [FIRING] disk usage at %d%% on node-%d\n", 80+i%20, i)
+	}
+	b.WriteString("&gt; Dashboard: <https://grafana.example.com/d/disk|Disk dashboard>\n")
+	b.WriteString("Logs below:\n```\n")
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&b, "2026-01-01T00:00:%02dZ kubelet eviction threshold met\n", i)
+	}
+	b.WriteString("see https://logs.example.com/q/123\n```\nThanks!")
+	return b.String()
+}
+
+func TestExecute_TrimsLongQuotes(t *testing.T) {
+	exec, llm := newRecordingExecutor(t, executor.Config{
+		QuoteTrim: executor.QuoteTrim{Enabled: true, Threshold: 500, KeepLines: 3},
+	})
+
+	message := forwardedAlert()
+	executeMessage(t, exec, message)
+
+	if len(llm.messages) != 1 {
+		t.Fatalf("model called %d times, want 1", len(llm.messages))
+	}
+	sent := llm.messages[0]
+	if len(sent) >= len(message)/3 {
+		t.Errorf("model received %d characters, want the %d character message trimmed", len(sent), len(message))
+	}
+
+	// The user's own text survives
+	for _, want := range []string{"Can you tell me why this alert keeps firing?", "Logs below:", "Thanks!"} {
+		if !strings.Contains(sent, want) {
+			t.Errorf("model received %q, want it to keep %q", sent, want)
+		}
+	}
+
+	// Each block keeps its first lines, with a marker for the rest
+	if !strings.Contains(sent, "node-2\n> [… 38 more quoted lines trimmed]") {
+		t.Errorf("model received %q, want the quote cut after 3 lines", sent)
+	}
+	if !strings.Contains(sent, "00:00:02Z kubelet eviction threshold met\n[… 38 more lines trimmed]\n```") {
+		t.Errorf("model received %q, want the code block cut after 3 lines and closed", sent)
+	}
+	if strings.Contains(sent, "node-10") {
+		t.Errorf("model received %q, want later quoted lines dropped", sent)
+	}
+
+	// Every link survives
+	for _, link := range []string{"https://runbooks.example.com/disk", "https://grafana.example.com/d/disk", "https://logs.example.com/q/123"} {
+		if !strings.Contains(sent, link) {
+			t.Errorf("model received %q, want it to keep %s", sent, link)
+		}
+	}
+}
+
+func TestExecute_QuoteTrimLeavesShortMessages(t *testing.T) {
+	exec, llm := newRecordingExecutor(t, executor.Config{
+		QuoteTrim: executor.QuoteTrim{Enabled: true, KeepLines: 1},
+	})
+
+	// Under the default threshold nothing is trimmed, however many quoted lines there are
+	message := "what does this mean?\n> one\n> two\n> three"
+	executeMessage(t, exec, message)
+
+	if len(llm.messages) != 1 || llm.messages[0] != message {
+		t.Errorf("model received %q, want the message unchanged", llm.messages)
+	}
+}
+
+func TestExecute_QuoteTrimDisabledByDefault(t *testing.T) {
+	exec, llm := newRecordingExecutor(t, executor.Config{})
+
+	message := forwardedAlert()
+	executeMessage(t, exec, message)
+
+	if len(llm.messages) != 1 || llm.messages[0] != message {
+		t.Error("model received a changed message, want it untouched when trimming is off")
+	}
+}
@@ -0,0 +1,88 @@
+package executor
+
+import (
+	"cmp"
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// Router picks which named agent handles a message. It returns "" to use the executor's
+// default agent. Implementations can route however they like, e.g. by asking a model to
+// classify the message.
+type Router interface {
+	Route(ctx context.Context, req MessageRequest) string
+}
+
+// RouterFunc adapts a function to the Router interface
+type RouterFunc func(ctx context.Context, req MessageRequest) string
+
+// Route calls f
+func (f RouterFunc) Route(ctx context.Context, req MessageRequest) string {
+	return f(ctx, req)
+}
+
+// Route sends messages matching every criterion it sets to Agent. A route with no criteria
+// matches nothing.
+type Route struct {
+	Agent    string
+	Channels []string       // Channel or chat IDs the message must come from
+	Commands []string       // Commands the user's text must start with, e.g. "/ops" (case-insensitive)
+	Pattern  *regexp.Regexp // Matched against the user's text, e.g. "(?i)\\b(invoice|refund)\\b"
+}
+
+// matches reports whether req meets all of the route's criteria
+func (r Route) matches(req MessageRequest) bool {
+	if len(r.Channels) == 0 && len(r.Commands) == 0 && r.Pattern == nil {
+		return false
+	}
+	if len(r.Channels) > 0 && !slices.Contains(r.Channels, req.ChannelID) {
+		return false
+	}
+	text := cmp.Or(req.UserText, req.Message)
+	if len(r.Commands) > 0 {
+		command, _, _ := strings.Cut(strings.TrimSpace(text), " ")
+		if !slices.ContainsFunc(r.Commands, func(c string) bool { return strings.EqualFold(c, command) }) {
+			return false
+		}
+	}
+	return r.Pattern == nil || r.Pattern.MatchString(text)
+}
+
+// RulesRouter routes a message to the agent of the first route it matches, checked in order
+type RulesRouter []Route
+
+// Route returns the agent of the first matching route, or "" if none match
+func (routes RulesRouter) Route(_ context.Context, req MessageRequest) string {
+	for _, route := range routes {
+		if route.matches(req) {
+			return route.Agent
+		}
+	}
+	return ""
+}
+
+// agentFactoryFor returns the factory of the agent the router picks for req, or the default
+// agent's when there's no router, it picks none or it picks one that isn't configured
+func (e *Executor) agentFactoryFor(ctx context.Context, req MessageRequest) (string, agents.AgentFactory) {
+	if e.router == nil {
+		return "", e.agentFactory
+	}
+	name := e.router.Route(ctx, req)
+	if name == "" {
+		return "", e.agentFactory
+	}
+	factory, ok := e.agents[name]
+	if !ok {
+		if e.log != nil {
+			e.log.WarnCtx(ctx, "Router picked an unknown agent, using the default agent",
+				logger.StringField("agent", name))
+		}
+		return "", e.agentFactory
+	}
+	return name, factory
+}
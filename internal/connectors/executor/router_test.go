@@ -0,0 +1,175 @@
+package executor_test
+
+import (
+	"context"
+	"io"
+	"iter"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/genai"
+)
+
+// routedCall is what the model was given for one call: the agent's instructions and tools
+type routedCall struct {
+	instruction string
+	tools       []string
+}
+
+// toolRecordingModel records the instructions and tool names of each request
+type toolRecordingModel struct {
+	calls []routedCall
+}
+
+func (m *toolRecordingModel) Name() string { return "fake-model" }
+
+func (m *toolRecordingModel) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	var call routedCall
+	if req.Config != nil && req.Config.SystemInstruction != nil {
+		for _, part := range req.Config.SystemInstruction.Parts {
+			call.instruction += part.Text
+		}
+	}
+	for name := range req.Tools {
+		call.tools = append(call.tools, name)
+	}
+	slices.Sort(call.tools)
+	m.calls = append(m.calls, call)
+
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{Content: genai.NewContentFromText("ok", genai.RoleModel)}, nil)
+	}
+}
+
+type noArgs struct{}
+
+func newNamedTestTool(t *testing.T, name string) tool.Tool {
+	t.Helper()
+	namedTool, err := functiontool.New(functiontool.Config{Name: name, Description: "test tool"},
+		func(tool.Context, noArgs) (map[string]any, error) { return nil, nil })
+	if err != nil {
+		t.Fatalf("functiontool.New(%s) error = %v", name, err)
+	}
+	return namedTool
+}
+
+// newRoutingExecutor creates an executor whose default agent has every tool and whose "ops"
+// agent only has run_command, with messages from channel COPS routed to "ops"
+func newRoutingExecutor(t *testing.T) (*executor.Executor, *toolRecordingModel) {
+	t.Helper()
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+	llm := &toolRecordingModel{}
+	tools := []tool.Tool{newNamedTestTool(t, "web_search"), newNamedTestTool(t, "run_command")}
+
+	factories, err := agents.NewChatAgentsWithToolsets(context.Background(), llm, []agents.AgentConfig{
+		{Name: "default_agent", Logger: log, PromptProvider: agents.StaticPrompt("You are the general assistant.")},
+		{Name: "ops", Logger: log, PromptProvider: agents.StaticPrompt("You are the operations assistant."), AllowedTools: []string{"run_command"}},
+	}, tools, nil)
+	if err != nil {
+		t.Fatalf("NewChatAgentsWithToolsets() error = %v", err)
+	}
+
+	exec := newTestExecutor(t, llm, executor.Config{
+		AgentFactory: factories[0],
+		Agents:       map[string]agents.AgentFactory{"ops": factories[1]},
+		Router:       executor.RulesRouter{{Agent: "ops", Channels: []string{"COPS"}}},
+	})
+	return exec, llm
+}
+
+func TestExecute_RoutesByChannel(t *testing.T) {
+	tests := []struct {
+		name            string
+		channel         string
+		wantInstruction string
+		wantTools       []string
+	}{
+		{
+			name:            "routed channel",
+			channel:         "COPS",
+			wantInstruction: "You are the operations assistant.",
+			wantTools:       []string{"run_command"},
+		},
+		{
+			name:            "other channel",
+			channel:         "CGENERAL",
+			wantInstruction: "You are the general assistant.",
+			wantTools:       []string{"run_command", "web_search"},
+		},
+		{
+			name:            "no channel",
+			wantInstruction: "You are the general assistant.",
+			wantTools:       []string{"run_command", "web_search"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec, llm := newRoutingExecutor(t)
+
+			_, err := exec.Execute(context.Background(), executor.MessageRequest{
+				UserID:    "user1",
+				SessionID: "session1",
+				Message:   "restart the api",
+				ChannelID: tt.channel,
+			}, nil, nil)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if len(llm.calls) != 1 {
+				t.Fatalf("model called %d times, want 1", len(llm.calls))
+			}
+			if !strings.HasPrefix(llm.calls[0].instruction, tt.wantInstruction) {
+				t.Errorf("instruction = %q, want it to start with %q", llm.calls[0].instruction, tt.wantInstruction)
+			}
+			if !slices.Equal(llm.calls[0].tools, tt.wantTools) {
+				t.Errorf("tools = %v, want %v", llm.calls[0].tools, tt.wantTools)
+			}
+		})
+	}
+}
+
+func TestRulesRouter(t *testing.T) {
+	router := executor.RulesRouter{
+		{Agent: "ops_incidents", Channels: []string{"COPS"}, Pattern: regexp.MustCompile(`(?i)\bincident\b`)},
+		{Agent: "ops", Channels: []string{"COPS"}},
+		{Agent: "billing", Commands: []string{"/billing"}},
+		{Agent: "billing", Pattern: regexp.MustCompile(`(?i)\b(invoice|refund)\b`)},
+		{Agent: "never"},
+	}
+
+	tests := []struct {
+		name string
+		req  executor.MessageRequest
+		want string
+	}{
+		{name: "channel and pattern", req: executor.MessageRequest{ChannelID: "COPS", Message: "open an incident"}, want: "ops_incidents"},
+		{name: "channel only", req: executor.MessageRequest{ChannelID: "COPS", Message: "restart the api"}, want: "ops"},
+		{name: "command", req: executor.MessageRequest{Message: "/Billing why was I charged twice?"}, want: "billing"},
+		{name: "command must be the first word", req: executor.MessageRequest{Message: "what does /billing do?"}, want: ""},
+		{name: "pattern", req: executor.MessageRequest{Message: "Where's my refund?"}, want: "billing"},
+		{
+			name: "user text rather than added context",
+			req:  executor.MessageRequest{Message: "Earlier: my invoice is wrong\n\nalice's message to you: thanks!", UserText: "thanks!"},
+			want: "",
+		},
+		{name: "no match", req: executor.MessageRequest{ChannelID: "CGENERAL", Message: "hello"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := router.Route(context.Background(), tt.req); got != tt.want {
+				t.Errorf("Route() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// citationToolName is the tool whose results are collected as sources for citation
+const citationToolName = "web_search"
+
+// MaxSources caps how many sources are listed in a response's footer
+const MaxSources = 5
+
+// Source is a web page the agent drew on, collected from tool results for citation
+type Source struct {
+	Title string
+	URL   string
+}
+
+// SourcesFormatter lets a connector render the sources footer in its platform's format.
+// Connectors that don't implement it get a plain-text footer.
+type SourcesFormatter interface {
+	FormatSources(sources []Source) string
+}
+
+// collectSources extracts titled links from a web_search function response,
+// skipping URLs already in seen. It reads the tool's JSON result shape
+// ({"results": [{"title": ..., "url": ...}]}) since responses arrive as maps.
+func collectSources(resp *genai.FunctionResponse, seen map[string]bool) []Source {
+	if resp == nil || resp.Name != citationToolName {
+		return nil
+	}
+
+	results, ok := resp.Response["results"].([]any)
+	if !ok {
+		return nil
+	}
+
+	var sources []Source
+	for _, r := range results {
+		result, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		url, _ := result["url"].(string)
+		if url == "" || seen[url] {
+			continue
+		}
+		title, _ := result["title"].(string)
+		if title == "" {
+			title = url
+		}
+		seen[url] = true
+		sources = append(sources, Source{Title: title, URL: url})
+	}
+	return sources
+}
+
+// FormatSourcesPlain renders sources as a plain-text numbered list.
+func FormatSourcesPlain(sources []Source) string {
+	var b strings.Builder
+	b.WriteString("Sources:")
+	for i, source := range sources {
+		fmt.Fprintf(&b, "\n%d. %s - %s", i+1, source.Title, source.URL)
+	}
+	return b.String()
+}
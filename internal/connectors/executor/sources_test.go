@@ -0,0 +1,160 @@
+package executor_test
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/slack"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/telegram"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/genai"
+)
+
+// searchCallingModel calls web_search once, then answers once it has the tool's result
+type searchCallingModel struct{}
+
+func (searchCallingModel) Name() string { return "fake-model" }
+
+func (searchCallingModel) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		last := req.Contents[len(req.Contents)-1]
+		for _, part := range last.Parts {
+			if part.FunctionResponse != nil {
+				yield(&model.LLMResponse{Content: genai.NewContentFromText("Go 1.24 is the latest release.", genai.RoleModel)}, nil)
+				return
+			}
+		}
+		yield(&model.LLMResponse{Content: &genai.Content{
+			Role:  genai.RoleModel,
+			Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{ID: "call_1", Name: "web_search", Args: map[string]any{"query": "latest go"}}}},
+		}}, nil)
+	}
+}
+
+type fakeSearchArgs struct {
+	Query string `json:"query"`
+}
+
+type fakeSearchResult struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type fakeSearchResults struct {
+	Query   string             `json:"query"`
+	Results []fakeSearchResult `json:"results"`
+}
+
+func newFakeWebSearchTool(t *testing.T) tool.Tool {
+	t.Helper()
+	searchTool, err := functiontool.New(functiontool.Config{Name: "web_search", Description: "fake search"},
+		func(_ tool.Context, args fakeSearchArgs) (fakeSearchResults, error) {
+			return fakeSearchResults{Query: args.Query, Results: []fakeSearchResult{
+				{Title: "Go 1.24 Release Notes", URL: "https://go.dev/doc/go1.24"},
+				{Title: "The Go Blog", URL: "https://go.dev/blog"},
+				{Title: "Duplicate", URL: "https://go.dev/blog"},
+			}}, nil
+		})
+	if err != nil {
+		t.Fatalf("failed to create fake web_search tool: %v", err)
+	}
+	return searchTool
+}
+
+func newCitationExecutor(t *testing.T, citations bool) *executor.Executor {
+	t.Helper()
+	searchTool := newFakeWebSearchTool(t)
+
+	return newTestExecutor(t, nil, executor.Config{
+		AgentFactory: func(agents.PlatformSpecificGuidanceProvider, agents.UserInfoFunc) (agent.Agent, error) {
+			return llmagent.New(llmagent.Config{
+				Name:  "test_agent",
+				Model: searchCallingModel{},
+				Tools: []tool.Tool{searchTool},
+			})
+		},
+		Citations: citations,
+	})
+}
+
+func TestExecute_CitesSearchSources(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider agents.PlatformSpecificGuidanceProvider
+		want     []string
+	}{
+		{
+			name:     "slack",
+			provider: &slack.Connector{},
+			want: []string{
+				"*Sources*",
+				"• <https://go.dev/doc/go1.24|Go 1.24 Release Notes>",
+				"• <https://go.dev/blog|The Go Blog>",
+			},
+		},
+		{
+			name:     "telegram",
+			provider: &telegram.Connector{},
+			want: []string{
+				"Sources:",
+				"1. Go 1.24 Release Notes - https://go.dev/doc/go1.24",
+				"2. The Go Blog - https://go.dev/blog",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := newCitationExecutor(t, true)
+
+			resp, err := exec.Execute(context.Background(), executor.MessageRequest{
+				UserID:    "user1",
+				SessionID: "session1",
+				Message:   "what's the latest Go?",
+			}, tt.provider, nil)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if !strings.HasPrefix(resp.Text, "Go 1.24 is the latest release.\n\n") {
+				t.Errorf("response should start with the answer, got %q", resp.Text)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(resp.Text, want) {
+					t.Errorf("response %q missing %q", resp.Text, want)
+				}
+			}
+			if len(resp.Sources) != 2 {
+				t.Errorf("got %d sources, want 2 (duplicates removed): %v", len(resp.Sources), resp.Sources)
+			}
+		})
+	}
+}
+
+func TestExecute_CitationsDisabled(t *testing.T) {
+	exec := newCitationExecutor(t, false)
+
+	resp, err := exec.Execute(context.Background(), executor.MessageRequest{
+		UserID:    "user1",
+		SessionID: "session1",
+		Message:   "what's the latest Go?",
+	}, &slack.Connector{}, nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if resp.Text != "Go 1.24 is the latest release." {
+		t.Errorf("expected answer without sources footer, got %q", resp.Text)
+	}
+	if len(resp.Sources) != 0 {
+		t.Errorf("expected no sources, got %v", resp.Sources)
+	}
+}
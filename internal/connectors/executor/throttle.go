@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ThrottleConfig sets how fast a connector may send messages. Rates are messages per
+// second, and bursts how many can be sent at once before sends are paced; a rate of 0 means
+// no limit at that level.
+type ThrottleConfig struct {
+	GlobalRate   float64 // Across all channels
+	GlobalBurst  int
+	ChannelRate  float64 // To any one channel or chat
+	ChannelBurst int
+}
+
+// maxTrackedChannels bounds the number of channel buckets kept before idle ones are swept
+const maxTrackedChannels = 1000
+
+// Throttle paces the messages a connector sends so it stays within the platform's rate
+// limits, using token buckets across all channels and per channel. Sends wait for a token
+// rather than failing, so bursts are smoothed out instead of being rejected with 429s.
+type Throttle struct {
+	config ThrottleConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	global   *tokenBucket
+	channels map[string]*tokenBucket
+}
+
+// NewThrottle creates a Throttle, or returns nil when neither rate is limited
+func NewThrottle(config ThrottleConfig) *Throttle {
+	if config.GlobalRate <= 0 && config.ChannelRate <= 0 {
+		return nil
+	}
+	t := &Throttle{
+		config:   config,
+		now:      time.Now,
+		channels: make(map[string]*tokenBucket),
+	}
+	if config.GlobalRate > 0 {
+		t.global = newTokenBucket(config.GlobalRate, config.GlobalBurst, t.now())
+	}
+	return t
+}
+
+// Wait blocks until a message may be sent to channel, returning early with the context's
+// error if it's done first. A nil Throttle never waits.
+func (t *Throttle) Wait(ctx context.Context, channel string) error {
+	delay := t.reserve(channel)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token from the global and channel buckets and returns how long to wait
+// until both tokens are available
+func (t *Throttle) reserve(channel string) time.Duration {
+	if t == nil {
+		return 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	var delay time.Duration
+	if t.global != nil {
+		delay = t.global.reserve(now)
+	}
+	if t.config.ChannelRate > 0 {
+		bucket, ok := t.channels[channel]
+		if !ok {
+			if len(t.channels) >= maxTrackedChannels {
+				t.sweep(now)
+			}
+			bucket = newTokenBucket(t.config.ChannelRate, t.config.ChannelBurst, now)
+			t.channels[channel] = bucket
+		}
+		delay = max(delay, bucket.reserve(now))
+	}
+	return delay
+}
+
+// sweep forgets channel buckets that have refilled, which behave the same as new ones
+func (t *Throttle) sweep(now time.Time) {
+	for channel, bucket := range t.channels {
+		if bucket.full(now) {
+			delete(t.channels, channel)
+		}
+	}
+}
+
+// tokenBucket holds up to burst tokens, refilled at rate per second. Tokens can be
+// reserved ahead of time, taking the count negative, so concurrent senders queue up.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	b := float64(max(burst, 1))
+	return &tokenBucket{rate: rate, burst: b, tokens: b, last: now}
+}
+
+// refill adds the tokens earned since the bucket was last used
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// reserve takes a token and returns how long until it's available
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.refill(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// full reports whether the bucket has refilled completely
+func (b *tokenBucket) full(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= b.burst
+}
@@ -0,0 +1,87 @@
+package executor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+)
+
+// sendTimes waits to send to each channel in turn and returns how long after the start each send was allowed
+func sendTimes(t *testing.T, throttle *executor.Throttle, channels ...string) []time.Duration {
+	t.Helper()
+	start := time.Now()
+	times := make([]time.Duration, len(channels))
+	for i, channel := range channels {
+		if err := throttle.Wait(context.Background(), channel); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+		times[i] = time.Since(start)
+	}
+	return times
+}
+
+func TestThrottle_PacesSendsToChannelRate(t *testing.T) {
+	throttle := executor.NewThrottle(executor.ThrottleConfig{ChannelRate: 20, ChannelBurst: 1})
+
+	times := sendTimes(t, throttle, "C1", "C1", "C1", "C1")
+	for i := 1; i < len(times); i++ {
+		if gap := times[i] - times[i-1]; gap < 40*time.Millisecond {
+			t.Errorf("send %d came %v after the previous one, want about 50ms at 20 per second", i, gap)
+		}
+	}
+}
+
+func TestThrottle_SmoothsBursts(t *testing.T) {
+	throttle := executor.NewThrottle(executor.ThrottleConfig{ChannelRate: 10, ChannelBurst: 3})
+
+	times := sendTimes(t, throttle, "C1", "C1", "C1", "C1")
+	if times[2] > 50*time.Millisecond {
+		t.Errorf("third send waited %v, want the burst of 3 sent at once", times[2])
+	}
+	if times[3] < 80*time.Millisecond {
+		t.Errorf("fourth send waited %v, want it paced to about 100ms once the burst is used", times[3])
+	}
+}
+
+func TestThrottle_ChannelsArePacedSeparately(t *testing.T) {
+	throttle := executor.NewThrottle(executor.ThrottleConfig{ChannelRate: 1, ChannelBurst: 1})
+
+	times := sendTimes(t, throttle, "C1", "C2", "C3")
+	if times[2] > 50*time.Millisecond {
+		t.Errorf("sends to different channels took %v, want no wait", times[2])
+	}
+}
+
+func TestThrottle_GlobalRateAppliesAcrossChannels(t *testing.T) {
+	throttle := executor.NewThrottle(executor.ThrottleConfig{GlobalRate: 20, GlobalBurst: 1, ChannelRate: 100, ChannelBurst: 10})
+
+	times := sendTimes(t, throttle, "C1", "C2", "C3")
+	if times[2] < 80*time.Millisecond {
+		t.Errorf("third send waited %v, want about 100ms at 20 per second overall", times[2])
+	}
+}
+
+func TestThrottle_WaitStopsWhenContextIsDone(t *testing.T) {
+	throttle := executor.NewThrottle(executor.ThrottleConfig{ChannelRate: 0.1, ChannelBurst: 1})
+	if err := throttle.Wait(context.Background(), "C1"); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := throttle.Wait(ctx, "C1"); err == nil {
+		t.Error("Wait() error = nil, want the context's error instead of waiting 10s")
+	}
+}
+
+func TestThrottle_NilWhenUnlimited(t *testing.T) {
+	throttle := executor.NewThrottle(executor.ThrottleConfig{})
+	if throttle != nil {
+		t.Fatal("NewThrottle() = non-nil, want nil without any rate")
+	}
+	if err := throttle.Wait(context.Background(), "C1"); err != nil {
+		t.Errorf("Wait() error = %v, want a nil Throttle to never wait", err)
+	}
+}
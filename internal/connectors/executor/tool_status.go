@@ -0,0 +1,33 @@
+package executor
+
+import (
+	"strings"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+)
+
+// toolStatusTexts describes what the built-in tools are doing, for status lines shown to users
+var toolStatusTexts = map[string]string{
+	"web_search":     "Searching the web…",
+	"http_request":   "Fetching a web page…",
+	"run_command":    "Running a command…",
+	"run_lua_script": "Running a script…",
+	"set_reminder":   "Setting a reminder…",
+	"get_agent_info": "Checking my configuration…",
+
+	agents.DelegateToolName: "Asking another agent…",
+}
+
+// ToolStatusText returns a short status line telling users which tool the agent is using,
+// e.g. "Searching the web…". MCP tools ("mcp__server__tool") are named with their server.
+func ToolStatusText(toolName string) string {
+	if text, ok := toolStatusTexts[toolName]; ok {
+		return text
+	}
+	if rest, ok := strings.CutPrefix(toolName, agents.MCPToolPrefix); ok {
+		if server, tool, ok := strings.Cut(rest, "__"); ok {
+			return "Using " + tool + " on " + server + "…"
+		}
+	}
+	return "Using " + toolName + "…"
+}
@@ -0,0 +1,46 @@
+package executor_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+)
+
+func TestToolStatusText(t *testing.T) {
+	tests := []struct {
+		toolName string
+		want     string
+	}{
+		{toolName: "web_search", want: "Searching the web…"},
+		{toolName: "run_command", want: "Running a command…"},
+		{toolName: "mcp__github__search_code", want: "Using search_code on github…"},
+		{toolName: "lookup_order", want: "Using lookup_order…"},
+	}
+
+	for _, tt := range tests {
+		if got := executor.ToolStatusText(tt.toolName); got != tt.want {
+			t.Errorf("ToolStatusText(%q) = %q, want %q", tt.toolName, got, tt.want)
+		}
+	}
+}
+
+func TestExecute_ReportsToolCalls(t *testing.T) {
+	exec := newCitationExecutor(t, false)
+
+	var calls []string
+	_, err := exec.Execute(context.Background(), executor.MessageRequest{
+		UserID:     "user1",
+		SessionID:  "session1",
+		Message:    "what's the latest Go?",
+		OnToolCall: func(toolName string) { calls = append(calls, toolName) },
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if want := []string{"web_search"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("OnToolCall calls = %q, want %q", calls, want)
+	}
+}
@@ -0,0 +1,50 @@
+package executor
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TriggerPrefixes let users address the bot in a channel without mentioning it, by starting
+// a message with a prefix such as "!ai". Prefixes are matched case-insensitively and must be
+// followed by a space, punctuation or the end of the message, so "!ai" doesn't match "!aim".
+type TriggerPrefixes struct {
+	Default  []string            // Prefixes that work in every channel
+	Channels map[string][]string // Prefixes for particular channels, replacing Default there
+}
+
+// Enabled reports whether any prefixes work in channelID
+func (t TriggerPrefixes) Enabled(channelID string) bool {
+	return len(t.prefixes(channelID)) > 0
+}
+
+// Match reports whether text starts with one of channelID's prefixes, returning the text
+// with the prefix removed. A message of just the prefix doesn't match.
+func (t TriggerPrefixes) Match(channelID, text string) (string, bool) {
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	for _, prefix := range t.prefixes(channelID) {
+		prefix = strings.TrimSpace(prefix)
+		if prefix == "" || len(trimmed) < len(prefix) || !strings.EqualFold(trimmed[:len(prefix)], prefix) {
+			continue
+		}
+		rest := trimmed[len(prefix):]
+		if next, _ := utf8.DecodeRuneInString(rest); rest != "" && (unicode.IsLetter(next) || unicode.IsDigit(next)) {
+			continue
+		}
+		rest = strings.TrimSpace(strings.TrimLeft(rest, ":,"))
+		if rest == "" {
+			return text, false
+		}
+		return rest, true
+	}
+	return text, false
+}
+
+// prefixes returns the prefixes that work in channelID
+func (t TriggerPrefixes) prefixes(channelID string) []string {
+	if prefixes, ok := t.Channels[channelID]; ok {
+		return prefixes
+	}
+	return t.Default
+}
@@ -0,0 +1,55 @@
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+)
+
+func TestTriggerPrefixes_Match(t *testing.T) {
+	triggers := executor.TriggerPrefixes{
+		Default:  []string{"!ai", "hey bot"},
+		Channels: map[string][]string{"C-ops": {"!ops"}, "C-off": nil},
+	}
+
+	tests := []struct {
+		name      string
+		channel   string
+		text      string
+		wantText  string
+		wantMatch bool
+	}{
+		{"prefix", "C1", "!ai what's the status?", "what's the status?", true},
+		{"case-insensitive", "C1", "!AI what's the status?", "what's the status?", true},
+		{"leading space", "C1", "  !ai hello", "hello", true},
+		{"punctuation after prefix", "C1", "hey bot, what's up", "what's up", true},
+		{"colon after prefix", "C1", "!ai: summarize", "summarize", true},
+		{"multi-line", "C1", "!ai\nsummarize this", "summarize this", true},
+		{"no prefix", "C1", "what's the status?", "what's the status?", false},
+		{"prefix mid-message", "C1", "ask !ai later", "ask !ai later", false},
+		{"longer word", "C1", "!aim high", "!aim high", false},
+		{"prefix alone", "C1", "!ai  ", "!ai  ", false},
+		{"channel prefix", "C-ops", "!ops restart the worker", "restart the worker", true},
+		{"default not used where channel has its own", "C-ops", "!ai hello", "!ai hello", false},
+		{"channel with prefixes turned off", "C-off", "!ai hello", "!ai hello", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotText, gotMatch := triggers.Match(tt.channel, tt.text)
+			if gotText != tt.wantText || gotMatch != tt.wantMatch {
+				t.Errorf("Match(%q, %q) = (%q, %v), want (%q, %v)", tt.channel, tt.text, gotText, gotMatch, tt.wantText, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestTriggerPrefixes_Enabled(t *testing.T) {
+	triggers := executor.TriggerPrefixes{Channels: map[string][]string{"C-ops": {"!ops"}}}
+	if !triggers.Enabled("C-ops") {
+		t.Error("Enabled(C-ops) = false, want true")
+	}
+	if triggers.Enabled("C1") {
+		t.Error("Enabled(C1) = true, want false without default prefixes")
+	}
+}
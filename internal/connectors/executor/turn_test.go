@@ -0,0 +1,46 @@
+package executor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"google.golang.org/adk/session"
+)
+
+func TestExecute_PersistsTurnInOneWrite(t *testing.T) {
+	ctx := context.Background()
+	exec, provider, sessions := newOutageExecutor(t, executor.DegradedSessions{})
+
+	for turn := 1; turn <= 2; turn++ {
+		provider.takeWrites()
+		resp, err := exec.Execute(ctx, executor.MessageRequest{UserID: "u1", SessionID: "s1", Message: "hello"}, nil, nil)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if resp.Text != "ok" {
+			t.Errorf("Text = %q, want %q", resp.Text, "ok")
+		}
+
+		// The first turn also creates the session
+		wantWrites := 1
+		if turn == 1 {
+			wantWrites = 2
+		}
+		if got := provider.takeWrites(); got != wantWrites {
+			t.Errorf("turn %d: got %d writes, want %d", turn, got, wantWrites)
+		}
+	}
+
+	got, err := sessions.Get(ctx, &session.GetRequest{AppName: "test", UserID: "u1", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	var authors []string
+	for event := range got.Session.Events().All() {
+		authors = append(authors, event.Author)
+	}
+	if len(authors) != 4 || authors[0] != "user" || authors[1] != "test_agent" || authors[2] != "user" || authors[3] != "test_agent" {
+		t.Errorf("persisted event authors = %v, want user and agent events for both turns", authors)
+	}
+}
@@ -5,9 +5,28 @@ type MessageRequest struct {
 	UserID    string // Unique identifier for the user
 	SessionID string // Unique identifier for the conversation session
 	Message   string // The user's message text
+	Locale    string // Optional: the user's platform locale (e.g. "en-US"), used as the default language
+	ChannelID string // Optional: the platform channel or chat the message came from, used for routing
+
+	// UserText is the user's own words when Message adds context before them, e.g. earlier
+	// messages in a thread. Routing matches commands and patterns against it.
+	UserText string
+
+	// ChannelContext describes the channel the message was sent in (name, topic and so on).
+	// The agent is given it as an instruction for this turn; it isn't stored with the message.
+	ChannelContext string
+
+	// DocumentsUserID is the key the sender's documents are stored under, when it differs
+	// from UserID because the conversation is shared, e.g. a channel thread
+	DocumentsUserID string
+
+	// OnToolCall is called with each tool's name as the agent calls it, e.g. to show the user
+	// a status; nil to not be told
+	OnToolCall func(toolName string)
 }
 
 // MessageResponse represents the agent's response
 type MessageResponse struct {
-	Text string // The agent's response text
+	Text    string   // The agent's response text
+	Sources []Source // Web sources cited in the response (when citations are enabled)
 }
@@ -0,0 +1,72 @@
+// Package sessionscope builds the scope keys connectors store conversations under. A scope
+// key identifies whose conversation a message belongs to: a user's direct messages, a
+// channel, or a thread shared by everyone in it.
+//
+// Sessions are indexed per platform, so keys only need to be unique within one platform.
+// The workspace (e.g. a Slack team) is optional; when given, the same user or channel ID in
+// two workspaces never shares a session. IDs containing ':' or '%' are escaped, so keys built
+// from different IDs or for different kinds of scope never collide.
+package sessionscope
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Prefixes of the channel and thread keys; user keys have none
+const (
+	channelPrefix = "channel"
+	threadPrefix  = "thread"
+)
+
+// escaper escapes the separator in IDs, and the escape character itself
+var escaper = strings.NewReplacer("%", "%25", ":", "%3A")
+
+// User returns the scope key for a user's direct messages and personal settings
+func User(workspace, userID string) string {
+	return join("", workspace, userID)
+}
+
+// Channel returns the scope key for a channel or group chat, shared by everyone in it
+func Channel(workspace, channelID string) string {
+	return join(channelPrefix, workspace, channelID)
+}
+
+// Thread returns the scope key for a thread in a channel, shared by everyone in the thread
+func Thread(workspace, channelID, threadID string) string {
+	return join(threadPrefix, workspace, channelID, threadID)
+}
+
+// UserID returns the user ID a user key was built from, and false for channel and thread
+// keys or keys that weren't built by User
+func UserID(key string) (string, bool) {
+	parts := strings.Split(key, ":")
+	if parts[0] == channelPrefix || parts[0] == threadPrefix || len(parts) > 2 {
+		return "", false
+	}
+	userID, err := url.PathUnescape(parts[len(parts)-1])
+	if err != nil {
+		return "", false
+	}
+	return userID, true
+}
+
+// join joins the prefix, the workspace if there is one, and the escaped IDs with ':'
+func join(prefix, workspace string, ids ...string) string {
+	parts := make([]string, 0, len(ids)+2)
+	if prefix != "" {
+		parts = append(parts, prefix)
+	}
+	if workspace != "" {
+		parts = append(parts, escaper.Replace(workspace))
+	}
+	for _, id := range ids {
+		parts = append(parts, escaper.Replace(id))
+	}
+	// A user key mustn't look like a channel or thread key
+	if prefix == "" && (parts[0] == channelPrefix || parts[0] == threadPrefix) {
+		parts[0] = fmt.Sprintf("%%%02X", parts[0][0]) + parts[0][1:]
+	}
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,121 @@
+package sessionscope
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+func TestKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		// Keys Slack has always stored conversations under are unchanged
+		{name: "user with workspace", got: User("T1", "U123"), want: "T1:U123"},
+		{name: "user without workspace", got: User("", "U123"), want: "U123"},
+		{name: "thread with workspace", got: Thread("T1", "C456", "1700000000.000100"), want: "thread:T1:C456:1700000000.000100"},
+		{name: "thread without workspace", got: Thread("", "C456", "1700000000.000100"), want: "thread:C456:1700000000.000100"},
+		{name: "channel with workspace", got: Channel("T1", "C456"), want: "channel:T1:C456"},
+		{name: "channel without workspace", got: Channel("", "-100123"), want: "channel:-100123"},
+		{name: "id with separator", got: User("", "a:b"), want: "a%3Ab"},
+		{name: "id with escape", got: User("", "50%"), want: "50%25"},
+		{name: "workspace named like a prefix", got: User("thread", "U123"), want: "%74hread:U123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("scope key = %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserID(t *testing.T) {
+	tests := []struct {
+		key    string
+		want   string
+		wantOK bool
+	}{
+		{key: User("T1", "U123"), want: "U123", wantOK: true},
+		{key: User("", "U123"), want: "U123", wantOK: true},
+		{key: User("", "a:b"), want: "a:b", wantOK: true},
+		{key: User("T:1", "50%"), want: "50%", wantOK: true},
+		{key: User("", "thread"), want: "thread", wantOK: true},
+		{key: User("thread", "U123"), want: "U123", wantOK: true},
+		{key: Thread("T1", "C456", "1700000000.000100")},
+		{key: Channel("", "C456")},
+		{key: "a:b:c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, ok := UserID(tt.key)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("UserID(%q) = (%q, %v), want (%q, %v)", tt.key, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestKeys_DontCollide(t *testing.T) {
+	keys := []string{
+		User("", "U1"),
+		User("", "T1:U1"),
+		User("T1", "U1"),
+		User("T1:U1", ""),
+		User("channel", "C1"),
+		User("thread", "C1:1.0"),
+		User("", "channel:C1"),
+		User("", "%3A"),
+		User("", ":"),
+		Channel("", "C1"),
+		Channel("T1", "C1"),
+		Channel("", "T1:C1"),
+		Thread("", "C1", "1.0"),
+		Thread("T1", "C1", "1.0"),
+		Thread("", "T1:C1", "1.0"),
+		Thread("", "C1:1.0", ""),
+	}
+
+	seen := make(map[string]int, len(keys))
+	for i, key := range keys {
+		if j, ok := seen[key]; ok {
+			t.Errorf("keys %d and %d are both %q", j, i, key)
+		}
+		seen[key] = i
+	}
+}
+
+func TestKeys_SeparateAcrossPlatforms(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+	manager, err := session_manager.New(session_manager.Config{
+		MetadataFile: "metadata.json",
+		FileProvider: storage_manager.NewLocalFileProvider(t.TempDir()),
+		Logger:       log,
+	})
+	if err != nil {
+		t.Fatalf("session_manager.New() error = %v", err)
+	}
+
+	// The same ID on two platforms gives the same key, but sessions are indexed per platform
+	key := User("", "12345")
+	slackSession, err := manager.GetOrCreateSession(ctx, "slack", key, "C1")
+	if err != nil {
+		t.Fatalf("GetOrCreateSession() error = %v", err)
+	}
+	telegramSession, err := manager.GetOrCreateSession(ctx, "telegram", key, "12345")
+	if err != nil {
+		t.Fatalf("GetOrCreateSession() error = %v", err)
+	}
+	if slackSession == telegramSession {
+		t.Errorf("slack and telegram share session %s, want separate sessions", slackSession)
+	}
+}
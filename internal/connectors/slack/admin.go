@@ -0,0 +1,113 @@
+package slack
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/audit"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+)
+
+// handleMaintenanceCommand handles the admin-only /maintenance command, which pauses or
+// resumes LLM calls on every platform
+func (c *Connector) handleMaintenanceCommand(_ context.Context, cmd slack.SlashCommand, args CommandArgs) (interface{}, error) {
+	if !c.admins[cmd.UserID] {
+		c.recordAudit(cmd.UserID, audit.ActionMaintenance, "", audit.ResultDenied, strings.TrimSpace(cmd.Text))
+		return map[string]interface{}{
+			"text": "Sorry, only admins can use /maintenance.",
+		}, nil
+	}
+
+	maintenance := c.executor.Maintenance()
+	reply, changed := executor.MaintenanceCommand(maintenance, args.Arg("action")+" "+args.Arg("message"))
+	if changed {
+		action, message := audit.ActionMaintenanceOff, ""
+		if enabled, msg := maintenance.Status(); enabled {
+			action, message = audit.ActionMaintenanceOn, msg
+		}
+		c.recordAudit(cmd.UserID, action, "", audit.ResultSuccess, message)
+	}
+
+	return map[string]interface{}{
+		"text": reply,
+	}, nil
+}
+
+// handleResetCommand handles the admin-only /reset command, which starts a new conversation
+// for another user in the channel the command is run in
+func (c *Connector) handleResetCommand(ctx context.Context, cmd slack.SlashCommand, args CommandArgs) (interface{}, error) {
+	target := parseUserReference(args.Arg("user"))
+	if !c.admins[cmd.UserID] {
+		c.recordAudit(cmd.UserID, audit.ActionSessionReset, target, audit.ResultDenied, "")
+		return map[string]interface{}{
+			"text": "Sorry, only admins can use /reset.",
+		}, nil
+	}
+	if target == "" {
+		return map[string]interface{}{
+			"text": resetCommand.Usage(),
+		}, nil
+	}
+
+	scopeKey := c.userScope(ctx, cmd.TeamID, target)
+	c.clearReplacedState(ctx, scopeKey)
+	sessionID, err := c.sessionMgr.CreateNewSession(ctx, "slack", scopeKey, cmd.ChannelID)
+	if err != nil {
+		c.recordAudit(cmd.UserID, audit.ActionSessionReset, target, audit.ResultFailed, err.Error())
+		return map[string]interface{}{
+			"text": "Failed to reset the conversation.",
+		}, err
+	}
+	c.recordAudit(cmd.UserID, audit.ActionSessionReset, target, audit.ResultSuccess, "channel "+cmd.ChannelID)
+
+	return map[string]interface{}{
+		"text": "Started a new conversation for <@" + target + "> in this channel. (Session: " + sessionID + ")",
+	}, nil
+}
+
+// clearReplacedState clears the state of the conversation a reset replaces, such as its
+// language override and budget usage, so none of it is left behind if it's picked up again
+func (c *Connector) clearReplacedState(ctx context.Context, scopeKey string) {
+	previous, err := c.sessionMgr.GetLatestSession(ctx, "slack", scopeKey)
+	if err != nil || previous == "" {
+		return
+	}
+	if err := c.sessionMgr.ClearState(ctx, c.executor.AppName(), scopeKey, previous); err != nil {
+		c.logger.Warn("Failed to clear the replaced conversation's state",
+			logger.StringField("session_id", previous),
+			logger.ErrorField(err))
+	}
+}
+
+// parseUserReference extracts a user ID from a command argument, which Slack sends as an
+// escaped mention (<@U123|name>) when the command escapes user names, or a bare user ID
+func parseUserReference(text string) string {
+	ref := strings.TrimSpace(text)
+	if strings.HasPrefix(ref, "<@") && strings.HasSuffix(ref, ">") {
+		ref = strings.TrimSuffix(strings.TrimPrefix(ref, "<@"), ">")
+		ref, _, _ = strings.Cut(ref, "|")
+	}
+	if strings.ContainsAny(ref, " <>@") {
+		return ""
+	}
+	return ref
+}
+
+// recordAudit writes an audit record for an admin command run by userID
+func (c *Connector) recordAudit(userID, action, target, result, detail string) {
+	err := c.audit.Record(audit.Record{
+		Platform: "slack",
+		Actor:    userID,
+		Action:   action,
+		Target:   target,
+		Result:   result,
+		Detail:   detail,
+	})
+	if err != nil {
+		c.logger.Error("Failed to write audit record",
+			logger.StringField("action", action),
+			logger.ErrorField(err))
+	}
+}
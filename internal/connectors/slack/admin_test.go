@@ -0,0 +1,138 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/audit"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/language"
+	"github.com/slack-go/slack"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/session"
+)
+
+func decodeAuditRecords(t *testing.T, buf *bytes.Buffer) []audit.Record {
+	t.Helper()
+	var records []audit.Record
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var r audit.Record
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestHandleResetCommand_AuditsReset(t *testing.T) {
+	c, _ := newScopeTestConnector(t)
+	var buf bytes.Buffer
+	c.audit = audit.New(&buf)
+	c.admins = map[string]bool{"UADMIN": true}
+
+	_, err := c.handleResetCommand(context.Background(), slack.SlashCommand{
+		Command: "/reset", Text: "<@U123|alice>", UserID: "UADMIN", TeamID: "T1", ChannelID: "C456",
+	}, CommandArgs{Args: map[string]string{"user": "<@U123|alice>"}})
+	if err != nil {
+		t.Fatalf("handleResetCommand() error = %v", err)
+	}
+
+	records := decodeAuditRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("got %d audit records, want 1", len(records))
+	}
+	r := records[0]
+	if r.Platform != "slack" || r.Actor != "UADMIN" || r.Action != audit.ActionSessionReset ||
+		r.Target != "U123" || r.Result != audit.ResultSuccess || r.Detail != "channel C456" {
+		t.Errorf("audit record = %+v, want a successful reset of U123 by UADMIN in C456", r)
+	}
+	if r.Time.IsZero() {
+		t.Error("audit record has no timestamp")
+	}
+}
+
+func TestHandleResetCommand_ClearsReplacedState(t *testing.T) {
+	c, sessions := newScopeTestConnector(t)
+	c.audit = audit.New(&bytes.Buffer{})
+	c.admins = map[string]bool{"UADMIN": true}
+	ctx := context.Background()
+
+	exec, err := executor.NewExecutorWithConfig(executor.Config{
+		AgentFactory: func(agents.PlatformSpecificGuidanceProvider, agents.UserInfoFunc) (agent.Agent, error) {
+			return llmagent.New(llmagent.Config{Name: "test_agent", Model: &messageRecordingModel{}})
+		},
+		AppName:         "test",
+		SessionService:  sessions.GetADKSessionService(),
+		ArtifactService: artifact.InMemoryService(),
+	})
+	if err != nil {
+		t.Fatalf("NewExecutorWithConfig() error = %v", err)
+	}
+	c.executor = exec
+
+	scopeKey := c.userScope(ctx, "T1", "U123")
+	previous, err := sessions.Manager.GetOrCreateSession(ctx, "slack", scopeKey, "C456")
+	if err != nil {
+		t.Fatalf("GetOrCreateSession() error = %v", err)
+	}
+	if err := exec.SetLanguageOverride(ctx, scopeKey, previous, "fr"); err != nil {
+		t.Fatalf("SetLanguageOverride() error = %v", err)
+	}
+
+	if _, err := c.handleResetCommand(ctx, slack.SlashCommand{
+		Command: "/reset", Text: "U123", UserID: "UADMIN", TeamID: "T1", ChannelID: "C456",
+	}, CommandArgs{Args: map[string]string{"user": "U123"}}); err != nil {
+		t.Fatalf("handleResetCommand() error = %v", err)
+	}
+
+	stored, err := sessions.GetADKSessionService().Get(ctx, &session.GetRequest{AppName: "test", UserID: scopeKey, SessionID: previous})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if code := language.Get(stored.Session.State(), language.OverrideStateKey); code != "" {
+		t.Errorf("replaced conversation's language override = %q, want it cleared", code)
+	}
+}
+
+func TestHandleResetCommand_NonAdminDenied(t *testing.T) {
+	c, _ := newScopeTestConnector(t)
+	var buf bytes.Buffer
+	c.audit = audit.New(&buf)
+
+	resp, err := c.handleResetCommand(context.Background(), slack.SlashCommand{
+		Command: "/reset", Text: "U123", UserID: "U999", TeamID: "T1", ChannelID: "C456",
+	}, CommandArgs{Args: map[string]string{"user": "U123"}})
+	if err != nil {
+		t.Fatalf("handleResetCommand() error = %v", err)
+	}
+	if text := resp.(map[string]interface{})["text"]; text != "Sorry, only admins can use /reset." {
+		t.Errorf("response = %q, want a refusal", text)
+	}
+
+	records := decodeAuditRecords(t, &buf)
+	if len(records) != 1 || records[0].Result != audit.ResultDenied || records[0].Actor != "U999" {
+		t.Errorf("audit records = %+v, want one denied attempt by U999", records)
+	}
+}
+
+func TestParseUserReference(t *testing.T) {
+	tests := map[string]string{
+		"<@U123|alice>": "U123",
+		"<@U123>":       "U123",
+		" U123 ":        "U123",
+		"@alice":        "",
+		"":              "",
+	}
+	for text, want := range tests {
+		if got := parseUserReference(text); got != want {
+			t.Errorf("parseUserReference(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// recordingSessionManager records the sessions requested for processed messages. It fails
+// the lookup so processing stops before the executor is reached.
+type recordingSessionManager struct {
+	session_manager.Manager
+	scopes []string
+}
+
+func (m *recordingSessionManager) GetLatestSession(context.Context, string, string) (string, error) {
+	return "", nil
+}
+
+func (m *recordingSessionManager) GetOrCreateSession(_ context.Context, _, userID, _ string) (string, error) {
+	m.scopes = append(m.scopes, userID)
+	return "", errors.New("session lookup stopped by test")
+}
+
+func TestHandleMessageEvent_AlwaysRespondChannels(t *testing.T) {
+	tests := []struct {
+		name      string
+		event     slackevents.MessageEvent
+		wantScope string
+	}{
+		{
+			name:      "plain message in always-respond channel",
+			event:     slackevents.MessageEvent{User: "U123", Channel: "CSUPPORT", TimeStamp: "1700000000.000100", Text: "how do I reset my password?"},
+			wantScope: "thread:CSUPPORT:1700000000.000100",
+		},
+		{
+			name:      "thread reply in always-respond channel",
+			event:     slackevents.MessageEvent{User: "U123", Channel: "CSUPPORT", TimeStamp: "1700000000.000300", ThreadTimeStamp: "1700000000.000100", Text: "still broken"},
+			wantScope: "thread:CSUPPORT:1700000000.000100",
+		},
+		{
+			name:  "plain message in normal channel",
+			event: slackevents.MessageEvent{User: "U123", Channel: "CGENERAL", TimeStamp: "1700000000.000100", Text: "how do I reset my password?"},
+		},
+		{
+			name:  "mention in always-respond channel is left to the app_mention event",
+			event: slackevents.MessageEvent{User: "U123", Channel: "CSUPPORT", TimeStamp: "1700000000.000100", Text: "<@UBOT> how do I reset my password?"},
+		},
+		{
+			name:  "bot message in always-respond channel",
+			event: slackevents.MessageEvent{BotID: "BOTHER", Channel: "CSUPPORT", TimeStamp: "1700000000.000100", Text: "deploy finished"},
+		},
+		{
+			name:  "own message in always-respond channel",
+			event: slackevents.MessageEvent{User: "UBOT", Channel: "CSUPPORT", TimeStamp: "1700000000.000100", Text: "here's how"},
+		},
+		{
+			name:  "system message in always-respond channel",
+			event: slackevents.MessageEvent{User: "U123", SubType: "channel_join", Channel: "CSUPPORT", TimeStamp: "1700000000.000100", Text: "<@U123> has joined the channel"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newMentionTestConnector(t)
+			sessions := &recordingSessionManager{}
+			c.sessionMgr = sessions
+			c.alwaysRespond = map[string]bool{"CSUPPORT": true}
+
+			_ = c.handleMessageEvent(context.Background(), "", &tt.event)
+
+			if tt.wantScope == "" {
+				if len(sessions.scopes) != 0 {
+					t.Errorf("message was processed (sessions %v), want it ignored", sessions.scopes)
+				}
+				return
+			}
+			if len(sessions.scopes) != 1 || sessions.scopes[0] != tt.wantScope {
+				t.Errorf("sessions = %v, want [%s]", sessions.scopes, tt.wantScope)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package slack
+
+import (
+	"cmp"
+	"context"
+	"strings"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// DefaultAssistantWelcome is posted when an assistant thread starts if no welcome or
+// introduction is configured
+const DefaultAssistantWelcome = "Hi! How can I help?"
+
+// assistantThinkingStatus is shown in an assistant thread while a reply is being generated;
+// Slack clears it when the reply is posted
+const assistantThinkingStatus = "is thinking…"
+
+// AssistantConfig configures Slack's AI assistant surfaces: the assistant pane, where each
+// thread is its own conversation, and the app's Home tab
+type AssistantConfig struct {
+	// Enabled answers messages in assistant threads, in the thread, and greets each new
+	// thread. The Slack app needs the Agents & AI Apps feature and the
+	// assistant_thread_started event.
+	Enabled bool
+
+	// Welcome is posted when an assistant thread starts and shown on the Home tab; empty
+	// uses the bot's introduction, or DefaultAssistantWelcome
+	Welcome string
+
+	// Prompts are suggested prompts offered in each new assistant thread
+	Prompts []string
+
+	// AppHome publishes the welcome and suggested prompts to the Home tab when it's opened.
+	// Needs the app_home_opened event.
+	AppHome bool
+}
+
+// welcome returns the message greeting a user in a new assistant thread or on the Home tab
+func (c *Connector) welcome() string {
+	return cmp.Or(strings.TrimSpace(c.assistant.Welcome), c.intro, DefaultAssistantWelcome)
+}
+
+// handleAssistantThreadStarted greets the user in a new assistant thread and offers the
+// suggested prompts
+func (c *Connector) handleAssistantThreadStarted(ctx context.Context, event *slackevents.AssistantThreadStartedEvent) error {
+	if !c.assistant.Enabled {
+		return nil
+	}
+	thread := event.AssistantThread
+	log := c.logger.WithFields(
+		logger.StringField("user_id", thread.UserID),
+		logger.StringField("channel", thread.ChannelID),
+		logger.StringField("thread_ts", thread.ThreadTimeStamp))
+	log.Info("Assistant thread started")
+
+	if err := c.throttle.Wait(ctx, thread.ChannelID); err != nil {
+		return err
+	}
+	if _, _, err := c.client.PostMessageContext(ctx, thread.ChannelID,
+		slack.MsgOptionText(c.welcome(), false),
+		slack.MsgOptionTS(thread.ThreadTimeStamp)); err != nil {
+		log.Error("Failed to post assistant welcome", logger.ErrorField(err))
+		return err
+	}
+
+	if len(c.assistant.Prompts) == 0 {
+		return nil
+	}
+	params := slack.AssistantThreadsSetSuggestedPromptsParameters{
+		ChannelID: thread.ChannelID,
+		ThreadTS:  thread.ThreadTimeStamp,
+	}
+	for _, prompt := range c.assistant.Prompts {
+		params.AddPrompt(prompt, prompt)
+	}
+	if err := c.client.SetAssistantThreadsSuggestedPromptsContext(ctx, params); err != nil {
+		log.Warn("Failed to set suggested prompts", logger.ErrorField(err))
+	}
+	return nil
+}
+
+// handleAssistantMessage answers a message in an assistant thread. Each thread is its own
+// conversation, scoped like a channel thread, and the reply goes in the thread.
+func (c *Connector) handleAssistantMessage(ctx context.Context, teamID string, event *slackevents.MessageEvent) error {
+	err := c.client.SetAssistantThreadsStatusContext(ctx, slack.AssistantThreadsSetStatusParameters{
+		ChannelID: event.Channel,
+		ThreadTS:  event.ThreadTimeStamp,
+		Status:    assistantThinkingStatus,
+	})
+	if err != nil {
+		c.logger.Debug("Failed to set assistant thread status", logger.ErrorField(err))
+	}
+
+	return c.handleChannelMessage(ctx, teamID, event.User, event.Channel, event.TimeStamp, event.ThreadTimeStamp, event.Text, false)
+}
+
+// handleAppHomeOpened publishes the welcome and suggested prompts to the user's Home tab
+func (c *Connector) handleAppHomeOpened(ctx context.Context, event *slackevents.AppHomeOpenedEvent) error {
+	if !c.assistant.AppHome || event.Tab != "home" {
+		return nil
+	}
+
+	view := slack.HomeTabViewRequest{
+		Type:   slack.VTHomeTab,
+		Blocks: slack.Blocks{BlockSet: c.homeBlocks()},
+	}
+	if _, err := c.client.PublishViewContext(ctx, slack.PublishViewContextRequest{UserID: event.User, View: view}); err != nil {
+		c.logger.Error("Failed to publish Home tab",
+			logger.StringField("user_id", event.User),
+			logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// homeBlocks builds the Home tab: the welcome, then the suggested prompts as a list
+func (c *Connector) homeBlocks() []slack.Block {
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, c.welcome(), false, false), nil, nil),
+	}
+	if len(c.assistant.Prompts) > 0 {
+		prompts := "*Try asking:*"
+		for _, prompt := range c.assistant.Prompts {
+			prompts += "\n• " + prompt
+		}
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, prompts, false, false), nil, nil))
+	}
+	return blocks
+}
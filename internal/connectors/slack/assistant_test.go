@@ -0,0 +1,220 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/sessionscope"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/cache"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/session"
+)
+
+// assistantAPICall is a request made to the fake Slack API
+type assistantAPICall struct {
+	path string
+	form map[string]string
+	body string
+}
+
+// newAssistantTestConnector returns a connector with assistant threads enabled, backed by a
+// fake Slack API recording the calls made to it
+func newAssistantTestConnector(t *testing.T, config AssistantConfig) (*Connector, func() []assistantAPICall) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var calls []assistantAPICall
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		_ = r.ParseForm()
+		call := assistantAPICall{path: r.URL.Path, form: make(map[string]string), body: string(body)}
+		for key := range r.Form {
+			call.form[key] = r.Form.Get(key)
+		}
+		mu.Lock()
+		calls = append(calls, call)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users.info":
+			_, _ = w.Write([]byte(`{"ok":true,"user":{"id":"U123","name":"alice"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":true,"channel":"D123","ts":"1700000000.000900","messages":[]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c := &Connector{
+		client:           slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/")),
+		logger:           logger.NewLogger(logger.Config{Level: logger.DebugLevel, Output: io.Discard}),
+		userNameCache:    cache.New[string, string](DefaultUserCacheSize, DefaultUserCacheTTL),
+		channelNameCache: make(map[string]string),
+		replies:          executor.NewReplyDeduper(executor.DuplicateReplyWindow),
+		assistant:        config,
+	}
+	return c, func() []assistantAPICall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]assistantAPICall(nil), calls...)
+	}
+}
+
+// callsTo returns the calls made to a Slack API method
+func callsTo(calls []assistantAPICall, method string) []assistantAPICall {
+	var matched []assistantAPICall
+	for _, call := range calls {
+		if call.path == "/"+method {
+			matched = append(matched, call)
+		}
+	}
+	return matched
+}
+
+func TestHandleMessageEvent_AssistantThread(t *testing.T) {
+	c, calls := newAssistantTestConnector(t, AssistantConfig{Enabled: true})
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+
+	llm := &messageRecordingModel{}
+	exec, err := executor.NewExecutorWithConfig(executor.Config{
+		AgentFactory: func(agents.PlatformSpecificGuidanceProvider, agents.UserInfoFunc) (agent.Agent, error) {
+			return llmagent.New(llmagent.Config{Name: "test_agent", Model: llm})
+		},
+		AppName:         "test",
+		SessionService:  session.InMemoryService(),
+		ArtifactService: artifact.InMemoryService(),
+	})
+	if err != nil {
+		t.Fatalf("NewExecutorWithConfig() error = %v", err)
+	}
+	sessions, err := session_manager.New(session_manager.Config{
+		MetadataFile: "metadata.json",
+		FileProvider: storage_manager.NewLocalFileProvider(t.TempDir()),
+		Logger:       log,
+	})
+	if err != nil {
+		t.Fatalf("session_manager.New() error = %v", err)
+	}
+	c.executor = exec
+	c.sessionMgr = sessions
+
+	event := &slackevents.MessageEvent{
+		Type:            "message",
+		User:            "U123",
+		Channel:         "D123",
+		ChannelType:     "im",
+		Text:            "summarise the incident",
+		TimeStamp:       "1700000000.000200",
+		ThreadTimeStamp: "1700000000.000100",
+	}
+	if err := c.handleMessageEvent(context.Background(), "T1", event); err != nil {
+		t.Fatalf("handleMessageEvent() error = %v", err)
+	}
+
+	if len(llm.messages) != 1 || !strings.Contains(llm.messages[0], "summarise the incident") {
+		t.Fatalf("messages to the model = %q, want the assistant thread message", llm.messages)
+	}
+
+	status := callsTo(calls(), "assistant.threads.setStatus")
+	if len(status) != 1 || status[0].form["thread_ts"] != event.ThreadTimeStamp {
+		t.Errorf("assistant.threads.setStatus calls = %+v, want one for the thread", status)
+	}
+	posts := callsTo(calls(), "chat.postMessage")
+	if len(posts) != 1 {
+		t.Fatalf("chat.postMessage called %d times, want 1", len(posts))
+	}
+	if posts[0].form["thread_ts"] != event.ThreadTimeStamp {
+		t.Errorf("reply thread_ts = %q, want %q", posts[0].form["thread_ts"], event.ThreadTimeStamp)
+	}
+
+	// The thread is its own conversation, not the user's DM session
+	threadSessions, err := sessions.ListUserSessions(context.Background(), "slack", sessionscope.Thread("T1", "D123", event.ThreadTimeStamp))
+	if err != nil || len(threadSessions) != 1 {
+		t.Errorf("sessions for the assistant thread = %v (error %v), want 1", threadSessions, err)
+	}
+}
+
+func TestHandleAssistantThreadStarted(t *testing.T) {
+	c, calls := newAssistantTestConnector(t, AssistantConfig{
+		Enabled: true,
+		Welcome: "Ask me about incidents.",
+		Prompts: []string{"What's on fire?", "Summarise today's alerts"},
+	})
+
+	event := &slackevents.AssistantThreadStartedEvent{AssistantThread: slackevents.AssistantThread{
+		UserID:          "U123",
+		ChannelID:       "D123",
+		ThreadTimeStamp: "1700000000.000100",
+	}}
+	if err := c.handleAssistantThreadStarted(context.Background(), event); err != nil {
+		t.Fatalf("handleAssistantThreadStarted() error = %v", err)
+	}
+
+	posts := callsTo(calls(), "chat.postMessage")
+	if len(posts) != 1 || posts[0].form["text"] != "Ask me about incidents." || posts[0].form["thread_ts"] != "1700000000.000100" {
+		t.Errorf("chat.postMessage calls = %+v, want the welcome in the thread", posts)
+	}
+	prompts := callsTo(calls(), "assistant.threads.setSuggestedPrompts")
+	if len(prompts) != 1 {
+		t.Fatalf("assistant.threads.setSuggestedPrompts called %d times, want 1", len(prompts))
+	}
+	for _, want := range []string{"What's on fire?", "Summarise today's alerts"} {
+		if !strings.Contains(prompts[0].form["prompts"], want) {
+			t.Errorf("suggested prompts = %s, want them to contain %q", prompts[0].form["prompts"], want)
+		}
+	}
+}
+
+func TestHandleAssistantThreadStarted_Disabled(t *testing.T) {
+	c, calls := newAssistantTestConnector(t, AssistantConfig{})
+
+	event := &slackevents.AssistantThreadStartedEvent{AssistantThread: slackevents.AssistantThread{
+		UserID: "U123", ChannelID: "D123", ThreadTimeStamp: "1700000000.000100",
+	}}
+	if err := c.handleAssistantThreadStarted(context.Background(), event); err != nil {
+		t.Fatalf("handleAssistantThreadStarted() error = %v", err)
+	}
+	if got := calls(); len(got) != 0 {
+		t.Errorf("Slack API calls = %+v, want none", got)
+	}
+}
+
+func TestHandleAppHomeOpened(t *testing.T) {
+	c, calls := newAssistantTestConnector(t, AssistantConfig{AppHome: true, Prompts: []string{"What's on fire?"}})
+	c.intro = "Hi, I'm Support Bot."
+
+	if err := c.handleAppHomeOpened(context.Background(), &slackevents.AppHomeOpenedEvent{User: "U123", Tab: "messages"}); err != nil {
+		t.Fatalf("handleAppHomeOpened() error = %v", err)
+	}
+	if got := callsTo(calls(), "views.publish"); len(got) != 0 {
+		t.Fatalf("views.publish called for the messages tab")
+	}
+
+	if err := c.handleAppHomeOpened(context.Background(), &slackevents.AppHomeOpenedEvent{User: "U123", Tab: "home"}); err != nil {
+		t.Fatalf("handleAppHomeOpened() error = %v", err)
+	}
+	published := callsTo(calls(), "views.publish")
+	if len(published) != 1 {
+		t.Fatalf("views.publish called %d times, want 1", len(published))
+	}
+	for _, want := range []string{`"user_id":"U123"`, `"type":"home"`, "Hi, I'm Support Bot.", "What's on fire?"} {
+		if !strings.Contains(published[0].body, want) {
+			t.Errorf("published view = %s, want it to contain %q", published[0].body, want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// BotIdentity returns the bot's own user ID, bot ID and name, looked up with auth.test the
+// first time they're needed and cached from then on
+func (c *Connector) BotIdentity(ctx context.Context) (executor.BotIdentity, error) {
+	c.identityMu.Lock()
+	defer c.identityMu.Unlock()
+
+	if c.botUserID == "" {
+		auth, err := c.client.AuthTestContext(ctx)
+		if err != nil {
+			return executor.BotIdentity{}, fmt.Errorf("failed to look up bot identity: %w", err)
+		}
+		c.cacheBotIdentity(executor.BotIdentity{UserID: auth.UserID, BotID: auth.BotID, Name: auth.User})
+	}
+	return executor.BotIdentity{UserID: c.botUserID, BotID: c.botBotID, Name: c.botName}, nil
+}
+
+// cacheBotIdentity stores the bot's identity and compiles the pattern for its mentions;
+// must be called with identityMu held
+func (c *Connector) cacheBotIdentity(identity executor.BotIdentity) {
+	c.botUserID = identity.UserID
+	c.botBotID = identity.BotID
+	c.botName = identity.Name
+	c.botMention = regexp.MustCompile(`[ \t]*<@` + regexp.QuoteMeta(identity.UserID) + `(\|[^>]*)?>[ \t]*`)
+}
+
+// botMentionPattern returns the pattern matching the bot's own mentions, or nil if its
+// identity couldn't be looked up
+func (c *Connector) botMentionPattern() *regexp.Regexp {
+	if c.ensureBotIdentity().UserID == "" {
+		return nil
+	}
+	c.identityMu.Lock()
+	defer c.identityMu.Unlock()
+	return c.botMention
+}
+
+// ensureBotIdentity returns the bot's identity, caching it if it isn't already. A failed
+// lookup is logged and tried again next time; until then the identity is empty and the
+// bot's own messages and mentions aren't recognized. Callers use the returned value rather
+// than the cached fields, which a retried lookup may be writing.
+func (c *Connector) ensureBotIdentity() executor.BotIdentity {
+	identity, err := c.BotIdentity(context.Background())
+	if err != nil {
+		c.logger.Warn("Failed to cache bot identity", logger.ErrorField(err))
+	}
+	return identity
+}
@@ -0,0 +1,100 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+)
+
+// newIdentityTestConnector returns a Connector backed by a fake Slack API whose auth.test
+// fails the first failures times. It counts auth.test calls.
+func newIdentityTestConnector(t *testing.T, failures int) (*Connector, func() int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		mu.Lock()
+		calls++
+		failed := calls <= failures
+		mu.Unlock()
+		if failed {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"ratelimited"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"user_id":"UBOT","bot_id":"BBOT","user":"helper"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &Connector{
+		client: slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/")),
+		logger: logger.NewLogger(logger.Config{Level: logger.DebugLevel, Output: io.Discard}),
+	}
+	return c, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls
+	}
+}
+
+func TestBotIdentity_LooksUpOnce(t *testing.T) {
+	c, calls := newIdentityTestConnector(t, 0)
+
+	for range 3 {
+		identity, err := c.BotIdentity(context.Background())
+		if err != nil {
+			t.Fatalf("BotIdentity() error = %v", err)
+		}
+		if want := (executor.BotIdentity{UserID: "UBOT", BotID: "BBOT", Name: "helper"}); identity != want {
+			t.Errorf("BotIdentity() = %+v, want %+v", identity, want)
+		}
+	}
+	c.mentionsBot("<@UBOT> hi")
+	c.isOwnMessage(slack.Message{Msg: slack.Msg{User: "U123"}})
+
+	if calls() != 1 {
+		t.Errorf("auth.test called %d times, want once", calls())
+	}
+}
+
+func TestBotIdentity_RetriesAfterFailure(t *testing.T) {
+	c, calls := newIdentityTestConnector(t, 1)
+
+	if _, err := c.BotIdentity(context.Background()); err == nil {
+		t.Fatal("BotIdentity() error = nil, want the failed lookup")
+	}
+	if identity, err := c.BotIdentity(context.Background()); err != nil || identity.UserID != "UBOT" {
+		t.Errorf("BotIdentity() = %+v, %v; want it looked up again", identity, err)
+	}
+	if calls() != 2 {
+		t.Errorf("auth.test called %d times, want 2", calls())
+	}
+}
+
+func TestRemoveBotMention(t *testing.T) {
+	c, _ := newIdentityTestConnector(t, 0)
+
+	tests := map[string]string{
+		"<@U_OTHER> and <@UBOT> please help":           "<@U_OTHER> and please help",
+		"<@UBOT> ask <@U_OTHER> about it":              "ask <@U_OTHER> about it",
+		"can <@U_OTHER> check this? <@UBOT>":           "can <@U_OTHER> check this?",
+		"<@UBOT> ping <@U_OTHER>, thanks <@UBOT>":      "ping <@U_OTHER>, thanks",
+		"<@UBOT|helper> summarise\n<@UBOT> the thread": "summarise\nthe thread",
+		"thanks <@UBOT> <@UBOT> again":                 "thanks again",
+		"<@U_OTHER> <@U_THIRD> hello":                  "<@U_OTHER> <@U_THIRD> hello",
+		"<@UBOTX> is a different user":                 "<@UBOTX> is a different user",
+	}
+	for text, want := range tests {
+		if got := c.removeBotMention(text); got != want {
+			t.Errorf("removeBotMention(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+)
+
+// DefaultChannelContextTTL is how long channel metadata is cached when no TTL is configured
+const DefaultChannelContextTTL = 10 * time.Minute
+
+// channelContextCacheSize bounds how many channels' metadata is kept in memory
+const channelContextCacheSize = 1000
+
+// getChannelContext returns a short description of a channel (its name, topic, purpose and
+// member count) to give the agent with a message, or "" when channel context is off or the
+// channel can't be read, e.g. when the bot lacks the scope for it. Results, including
+// failures, are cached so an unreadable channel isn't looked up on every message. DMs,
+// including assistant threads, have no channel context.
+func (c *Connector) getChannelContext(ctx context.Context, channelID string) string {
+	if c.channelContextCache == nil || channelID == "" || strings.HasPrefix(channelID, "D") {
+		return ""
+	}
+	if description, ok := c.channelContextCache.Get(channelID); ok {
+		return description
+	}
+
+	channel, err := c.client.GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{
+		ChannelID:         channelID,
+		IncludeNumMembers: true,
+	})
+	description := ""
+	if err != nil {
+		c.logger.Debug("Failed to fetch channel info for context",
+			logger.StringField("channel", channelID),
+			logger.ErrorField(err))
+	} else {
+		description = describeChannel(channel)
+	}
+
+	c.channelContextCache.Set(channelID, description)
+	return description
+}
+
+// describeChannel formats a channel's metadata as a list for the agent's instructions
+func describeChannel(channel *slack.Channel) string {
+	if channel == nil || channel.Name == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "- Channel: #%s\n", channel.Name)
+	if topic := strings.TrimSpace(channel.Topic.Value); topic != "" {
+		fmt.Fprintf(&b, "- Topic: %s\n", topic)
+	}
+	if purpose := strings.TrimSpace(channel.Purpose.Value); purpose != "" {
+		fmt.Fprintf(&b, "- Purpose: %s\n", purpose)
+	}
+	if channel.NumMembers > 0 {
+		fmt.Fprintf(&b, "- Members: %d\n", channel.NumMembers)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
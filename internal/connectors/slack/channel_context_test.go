@@ -0,0 +1,199 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/cache"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// newChannelContextTestAPI returns a fake Slack API whose conversations.info knows channel
+// C456 (incidents) and reports a missing scope for any other channel. It counts lookups.
+func newChannelContextTestAPI(t *testing.T) (*httptest.Server, func() int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	lookups := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/conversations.info":
+			mu.Lock()
+			lookups++
+			mu.Unlock()
+			if r.Form.Get("channel") == "C456" {
+				_, _ = w.Write([]byte(`{"ok":true,"channel":{"id":"C456","name":"incidents","num_members":12,` +
+					`"topic":{"value":"SEV1: checkout latency"},"purpose":{"value":"Coordinating production incidents"}}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok":false,"error":"missing_scope"}`))
+		case "/users.info":
+			_, _ = w.Write([]byte(`{"ok":true,"user":{"id":"U123","name":"alice"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":true,"channel":"C456","ts":"1700000000.000900","messages":[]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return lookups
+	}
+}
+
+func newChannelContextTestConnector(t *testing.T, serverURL string, enabled bool) *Connector {
+	t.Helper()
+	c := &Connector{
+		client:           slack.New("xoxb-test", slack.OptionAPIURL(serverURL+"/")),
+		logger:           logger.NewLogger(logger.Config{Level: logger.DebugLevel, Output: io.Discard}),
+		userNameCache:    cache.New[string, string](DefaultUserCacheSize, DefaultUserCacheTTL),
+		channelNameCache: make(map[string]string),
+		replies:          executor.NewReplyDeduper(executor.DuplicateReplyWindow),
+	}
+	if enabled {
+		c.channelContextCache = cache.New[string, string](channelContextCacheSize, DefaultChannelContextTTL)
+	}
+	return c
+}
+
+func TestGetChannelContext(t *testing.T) {
+	server, lookups := newChannelContextTestAPI(t)
+	c := newChannelContextTestConnector(t, server.URL, true)
+	ctx := context.Background()
+
+	want := "- Channel: #incidents\n" +
+		"- Topic: SEV1: checkout latency\n" +
+		"- Purpose: Coordinating production incidents\n" +
+		"- Members: 12"
+	for range 2 {
+		if got := c.getChannelContext(ctx, "C456"); got != want {
+			t.Errorf("getChannelContext() = %q, want %q", got, want)
+		}
+	}
+	if got := lookups(); got != 1 {
+		t.Errorf("conversations.info called %d times, want 1 (cached)", got)
+	}
+
+	// A channel the bot can't read gives no context, and isn't looked up again
+	for range 2 {
+		if got := c.getChannelContext(ctx, "C999"); got != "" {
+			t.Errorf("getChannelContext() without scope = %q, want empty", got)
+		}
+	}
+	if got := lookups(); got != 2 {
+		t.Errorf("conversations.info called %d times, want 2", got)
+	}
+}
+
+func TestGetChannelContext_Disabled(t *testing.T) {
+	server, lookups := newChannelContextTestAPI(t)
+	c := newChannelContextTestConnector(t, server.URL, false)
+
+	if got := c.getChannelContext(context.Background(), "C456"); got != "" {
+		t.Errorf("getChannelContext() = %q, want empty when channel context is off", got)
+	}
+	if got := lookups(); got != 0 {
+		t.Errorf("conversations.info called %d times, want 0", got)
+	}
+}
+
+// messageRecordingModel records the system instruction and last user message of each
+// request and answers "ok"
+type messageRecordingModel struct {
+	mu           sync.Mutex
+	instructions []string
+	messages     []string
+}
+
+func (m *messageRecordingModel) Name() string { return "fake-model" }
+
+func (m *messageRecordingModel) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	var text strings.Builder
+	if len(req.Contents) > 0 {
+		for _, part := range req.Contents[len(req.Contents)-1].Parts {
+			text.WriteString(part.Text)
+		}
+	}
+	var instruction strings.Builder
+	if req.Config != nil && req.Config.SystemInstruction != nil {
+		for _, part := range req.Config.SystemInstruction.Parts {
+			instruction.WriteString(part.Text)
+		}
+	}
+	m.mu.Lock()
+	m.instructions = append(m.instructions, instruction.String())
+	m.messages = append(m.messages, text.String())
+	m.mu.Unlock()
+
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{Content: genai.NewContentFromText("ok", genai.RoleModel)}, nil)
+	}
+}
+
+func TestHandleChannelMessage_GivesChannelContextAsInstruction(t *testing.T) {
+	server, _ := newChannelContextTestAPI(t)
+	c := newChannelContextTestConnector(t, server.URL, true)
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+
+	llm := &messageRecordingModel{}
+	factories, err := agents.NewChatAgentsWithToolsets(context.Background(), llm, []agents.AgentConfig{{
+		Name:   "test_agent",
+		Logger: log,
+	}}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewChatAgentsWithToolsets() error = %v", err)
+	}
+	exec, err := executor.NewExecutorWithConfig(executor.Config{
+		AgentFactory:    factories[0],
+		AppName:         "test",
+		SessionService:  session.InMemoryService(),
+		ArtifactService: artifact.InMemoryService(),
+	})
+	if err != nil {
+		t.Fatalf("NewExecutorWithConfig() error = %v", err)
+	}
+	sessions, err := session_manager.New(session_manager.Config{
+		MetadataFile: "metadata.json",
+		FileProvider: storage_manager.NewLocalFileProvider(t.TempDir()),
+		Logger:       log,
+	})
+	if err != nil {
+		t.Fatalf("session_manager.New() error = %v", err)
+	}
+	c.executor = exec
+	c.sessionMgr = sessions
+
+	if err := c.handleChannelMessage(context.Background(), "T1", "U123", "C456", "1700000000.000100", "", "what's going on?", false); err != nil {
+		t.Fatalf("handleChannelMessage() error = %v", err)
+	}
+
+	if len(llm.messages) != 1 {
+		t.Fatalf("model called %d times, want 1", len(llm.messages))
+	}
+	if llm.messages[0] != "what's going on?" {
+		t.Errorf("message to the model = %q, want only the user's text", llm.messages[0])
+	}
+	for _, want := range []string{"- Channel: #incidents", "- Topic: SEV1: checkout latency", "- Purpose: Coordinating production incidents"} {
+		if !strings.Contains(llm.instructions[0], want) {
+			t.Errorf("instruction = %q, want it to contain %q", llm.instructions[0], want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package slack
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// CommandSpec describes a slash command and its arguments. The registry parses a command's
+// text against its spec before the handler runs, and builds usage and help text from it.
+type CommandSpec struct {
+	Name        string // Command including the slash, e.g. /reset
+	Description string // Shown in /help
+	Args        []ArgSpec
+	Flags       []FlagSpec
+}
+
+// ArgSpec describes a positional argument
+type ArgSpec struct {
+	Name     string // Key the value is stored under in CommandArgs
+	Value    string // Placeholder shown in usage; the name if empty
+	Required bool
+	Rest     bool // Takes the remaining words, joined by spaces; only for the last argument
+}
+
+// FlagSpec describes a --flag. Flags are given as --name value or --name=value, or as
+// --name alone for a boolean flag.
+type FlagSpec struct {
+	Name        string // Flag name without the dashes
+	Value       string // Placeholder shown in usage; empty for a boolean flag
+	Required    bool
+	Description string
+}
+
+// CommandArgs holds a command's parsed arguments
+type CommandArgs struct {
+	Args  map[string]string
+	Flags map[string]string // Boolean flags that were given are set to "true"
+}
+
+// Arg returns a positional argument, or "" if it wasn't given
+func (a CommandArgs) Arg(name string) string {
+	return a.Args[name]
+}
+
+// Flag returns a flag's value, or "" if it wasn't given
+func (a CommandArgs) Flag(name string) string {
+	return a.Flags[name]
+}
+
+// Bool reports whether a boolean flag was given
+func (a CommandArgs) Bool(name string) bool {
+	return a.Flags[name] == "true"
+}
+
+// errHelpRequested is returned by Parse when the command is run with --help
+var errHelpRequested = errors.New("help requested")
+
+// Synopsis returns the command with its arguments, e.g. /reset <@user>
+func (s CommandSpec) Synopsis() string {
+	parts := []string{s.Name}
+	for _, arg := range s.Args {
+		value := cmp.Or(arg.Value, arg.Name)
+		if arg.Rest {
+			value += "..."
+		}
+		if arg.Required {
+			parts = append(parts, "<"+value+">")
+		} else {
+			parts = append(parts, "["+value+"]")
+		}
+	}
+	for _, flag := range s.Flags {
+		usage := flag.usage()
+		if !flag.Required {
+			usage = "[" + usage + "]"
+		}
+		parts = append(parts, usage)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Usage returns the synopsis followed by a line per flag that has a description
+func (s CommandSpec) Usage() string {
+	var b strings.Builder
+	b.WriteString("Usage: " + s.Synopsis())
+	for _, flag := range s.Flags {
+		if flag.Description != "" {
+			fmt.Fprintf(&b, "\n  %s - %s", flag.usage(), flag.Description)
+		}
+	}
+	return b.String()
+}
+
+// usage returns how a flag is written, e.g. --name <name>
+func (f FlagSpec) usage() string {
+	if f.Value == "" {
+		return "--" + f.Name
+	}
+	return "--" + f.Name + " <" + f.Value + ">"
+}
+
+// flag returns the spec of a flag, or false if the command has no such flag
+func (s CommandSpec) flag(name string) (FlagSpec, bool) {
+	for _, flag := range s.Flags {
+		if flag.Name == name {
+			return flag, true
+		}
+	}
+	return FlagSpec{}, false
+}
+
+// Parse parses a command's text against its spec. Words can be quoted to include spaces,
+// and -- ends the flags so later words are positional even if they start with dashes.
+func (s CommandSpec) Parse(text string) (CommandArgs, error) {
+	words, err := splitCommandText(text)
+	if err != nil {
+		return CommandArgs{}, err
+	}
+
+	args := CommandArgs{Args: make(map[string]string), Flags: make(map[string]string)}
+	var positional []string
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if word == "--" {
+			positional = append(positional, words[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(word, "--") {
+			positional = append(positional, word)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(word, "--"), "=")
+		if name == "help" {
+			return CommandArgs{}, errHelpRequested
+		}
+		flag, ok := s.flag(name)
+		if !ok {
+			return CommandArgs{}, fmt.Errorf("unknown flag --%s", name)
+		}
+		if _, seen := args.Flags[name]; seen {
+			return CommandArgs{}, fmt.Errorf("flag --%s given more than once", name)
+		}
+		switch {
+		case flag.Value == "" && hasValue:
+			return CommandArgs{}, fmt.Errorf("flag --%s doesn't take a value", name)
+		case flag.Value == "":
+			value = "true"
+		case !hasValue:
+			if i+1 == len(words) || strings.HasPrefix(words[i+1], "--") {
+				return CommandArgs{}, fmt.Errorf("flag --%s needs a value", name)
+			}
+			i++
+			value = words[i]
+		}
+		args.Flags[name] = value
+	}
+
+	for i, arg := range s.Args {
+		if i >= len(positional) {
+			if arg.Required {
+				return CommandArgs{}, fmt.Errorf("missing argument <%s>", cmp.Or(arg.Value, arg.Name))
+			}
+			break
+		}
+		if arg.Rest {
+			args.Args[arg.Name] = strings.Join(positional[i:], " ")
+			positional = positional[:i+1]
+			break
+		}
+		args.Args[arg.Name] = positional[i]
+	}
+	if len(positional) > len(s.Args) {
+		return CommandArgs{}, fmt.Errorf("unexpected argument %q", positional[len(s.Args)])
+	}
+
+	for _, flag := range s.Flags {
+		if _, ok := args.Flags[flag.Name]; flag.Required && !ok {
+			return CommandArgs{}, fmt.Errorf("missing flag --%s", flag.Name)
+		}
+	}
+	return args, nil
+}
+
+// closingQuotes maps the quotes that can open a quoted word to the quote that closes it.
+// Slack clients may turn straight quotes into curly ones as they're typed.
+var closingQuotes = map[rune]rune{'"': '"', '\'': '\'', '“': '”', '‘': '’'}
+
+// splitCommandText splits a command's text into words. A quote only opens a quoted word at
+// the start of a word, so apostrophes inside words are kept as written.
+func splitCommandText(text string) ([]string, error) {
+	var words []string
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		if closing, ok := closingQuotes[runes[i]]; ok {
+			end := i + 1
+			for end < len(runes) && runes[end] != closing {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated quote in %q", string(runes[i:]))
+			}
+			words = append(words, string(runes[i+1:end]))
+			i = end + 1
+			continue
+		}
+
+		start := i
+		for i < len(runes) && !unicode.IsSpace(runes[i]) {
+			i++
+		}
+		words = append(words, string(runes[start:i]))
+	}
+	return words, nil
+}
@@ -0,0 +1,123 @@
+package slack
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+var skillAddCommand = CommandSpec{
+	Name:        "/skill",
+	Description: "Manage skills",
+	Args:        []ArgSpec{{Name: "action", Required: true}, {Name: "notes", Rest: true}},
+	Flags: []FlagSpec{
+		{Name: "name", Value: "name", Required: true, Description: "Name of the skill"},
+		{Name: "desc", Value: "text", Description: "What the skill does"},
+		{Name: "force"},
+	},
+}
+
+func TestCommandSpecParse(t *testing.T) {
+	tests := []struct {
+		text      string
+		wantArgs  map[string]string
+		wantFlags map[string]string
+	}{
+		{
+			text:      "add --name x --desc y",
+			wantArgs:  map[string]string{"action": "add"},
+			wantFlags: map[string]string{"name": "x", "desc": "y"},
+		},
+		{
+			text:      `add --name=x --desc "looks things up" --force`,
+			wantArgs:  map[string]string{"action": "add"},
+			wantFlags: map[string]string{"name": "x", "desc": "looks things up", "force": "true"},
+		},
+		{
+			text:      "add “smart quotes” don't split --name x",
+			wantArgs:  map[string]string{"action": "add", "notes": "smart quotes don't split"},
+			wantFlags: map[string]string{"name": "x"},
+		},
+		{
+			text:      "--name x add -- --not-a-flag",
+			wantArgs:  map[string]string{"action": "add", "notes": "--not-a-flag"},
+			wantFlags: map[string]string{"name": "x"},
+		},
+	}
+	for _, tt := range tests {
+		args, err := skillAddCommand.Parse(tt.text)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.text, err)
+			continue
+		}
+		if !reflect.DeepEqual(args.Args, tt.wantArgs) || !reflect.DeepEqual(args.Flags, tt.wantFlags) {
+			t.Errorf("Parse(%q) = %+v, want args %v and flags %v", tt.text, args, tt.wantArgs, tt.wantFlags)
+		}
+	}
+}
+
+func TestCommandSpecParse_Errors(t *testing.T) {
+	tests := map[string]string{
+		"--name x":                  "missing argument <action>",
+		"add":                       "missing flag --name",
+		"add --name":                "flag --name needs a value",
+		"add --name --force":        "flag --name needs a value",
+		"add --name x --name y":     "flag --name given more than once",
+		"add --name x --force=yes":  "flag --force doesn't take a value",
+		"add --name x --colour red": "unknown flag --colour",
+		`add --name "x`:             `unterminated quote in "\"x"`,
+	}
+	for text, want := range tests {
+		_, err := skillAddCommand.Parse(text)
+		if err == nil || err.Error() != want {
+			t.Errorf("Parse(%q) error = %v, want %q", text, err, want)
+		}
+	}
+
+	if _, err := resetCommand.Parse("<@U1> <@U2>"); err == nil || err.Error() != `unexpected argument "<@U2>"` {
+		t.Errorf("Parse() error = %v, want the extra argument reported", err)
+	}
+}
+
+func TestCommandSpecUsage(t *testing.T) {
+	want := "Usage: /skill <action> [notes...] --name <name> [--desc <text>] [--force]\n" +
+		"  --name <name> - Name of the skill\n" +
+		"  --desc <text> - What the skill does"
+	if got := skillAddCommand.Usage(); got != want {
+		t.Errorf("Usage() = %q, want %q", got, want)
+	}
+}
+
+func TestCommandRegistry_ReportsInvalidArgumentsEphemerally(t *testing.T) {
+	registry := NewCommandRegistry()
+	called := false
+	registry.Register(skillAddCommand, func(context.Context, slack.SlashCommand, CommandArgs) (interface{}, error) {
+		called = true
+		return nil, nil
+	})
+
+	for _, text := range []string{"add --desc y", "--help"} {
+		resp, err := registry.Handle(context.Background(), slack.SlashCommand{Command: "/skill", Text: text})
+		if err != nil {
+			t.Fatalf("Handle(%q) error = %v", text, err)
+		}
+		fields := resp.(map[string]interface{})
+		if fields["response_type"] != slack.ResponseTypeEphemeral {
+			t.Errorf("Handle(%q) response_type = %v, want ephemeral", text, fields["response_type"])
+		}
+		if got, _ := fields["text"].(string); !strings.Contains(got, "Usage: /skill") {
+			t.Errorf("Handle(%q) text = %q, want the usage", text, got)
+		}
+	}
+	if called {
+		t.Error("handler ran for an invalid invocation")
+	}
+
+	resp, _ := registry.Handle(context.Background(), slack.SlashCommand{Command: "/skill", Text: "add --desc y"})
+	if text := resp.(map[string]interface{})["text"]; !strings.HasPrefix(text.(string), "Invalid arguments: missing flag --name\n") {
+		t.Errorf("text = %q, want the parse error first", text)
+	}
+}
@@ -3,48 +3,101 @@ package slack
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/lewisedginton/general_purpose_chatbot/internal/language"
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/socketmode"
 )
 
-// CommandHandler handles a specific slash command
-type CommandHandler func(ctx context.Context, cmd slack.SlashCommand) (interface{}, error)
+// CommandHandler handles a specific slash command, given its parsed arguments
+type CommandHandler func(ctx context.Context, cmd slack.SlashCommand, args CommandArgs) (interface{}, error)
+
+// registeredCommand is a command's spec and handler
+type registeredCommand struct {
+	spec    CommandSpec
+	handler CommandHandler
+}
 
 // CommandRegistry manages slash command handlers
 type CommandRegistry struct {
-	handlers map[string]CommandHandler
+	handlers map[string]registeredCommand
 }
 
 // NewCommandRegistry creates a new command registry
 func NewCommandRegistry() *CommandRegistry {
 	return &CommandRegistry{
-		handlers: make(map[string]CommandHandler),
+		handlers: make(map[string]registeredCommand),
 	}
 }
 
-// Register adds a command handler to the registry
-func (r *CommandRegistry) Register(command string, handler CommandHandler) {
-	r.handlers[command] = handler
+// Register adds a command handler to the registry. The command's text is parsed against
+// spec before the handler runs.
+func (r *CommandRegistry) Register(spec CommandSpec, handler CommandHandler) {
+	r.handlers[spec.Name] = registeredCommand{spec: spec, handler: handler}
 }
 
-// Handle processes a slash command event
+// Handle processes a slash command event. Invalid arguments are reported to the user with
+// the command's usage rather than reaching the handler.
 func (r *CommandRegistry) Handle(ctx context.Context, cmd slack.SlashCommand) (interface{}, error) {
-	handler, exists := r.handlers[cmd.Command]
+	command, exists := r.handlers[cmd.Command]
 	if !exists {
 		return map[string]interface{}{
 			"text": fmt.Sprintf("Unknown command: %s", cmd.Command),
 		}, nil
 	}
 
-	return handler(ctx, cmd)
+	args, err := command.spec.Parse(cmd.Text)
+	if errors.Is(err, errHelpRequested) {
+		return map[string]interface{}{
+			"response_type": slack.ResponseTypeEphemeral,
+			"text":          command.spec.Usage(),
+		}, nil
+	}
+	if err != nil {
+		return map[string]interface{}{
+			"response_type": slack.ResponseTypeEphemeral,
+			"text":          fmt.Sprintf("Invalid arguments: %v\n%s", err, command.spec.Usage()),
+		}, nil
+	}
+
+	return command.handler(ctx, cmd, args)
 }
 
+// Slash commands, in the order /help lists them
+var (
+	newCommand = CommandSpec{
+		Name:        "/new",
+		Description: "Start a new conversation",
+	}
+	languageCommand = CommandSpec{
+		Name:        "/language",
+		Description: "Set the language I reply in",
+		Args:        []ArgSpec{{Name: "code", Value: "code|auto"}},
+	}
+	maintenanceCommand = CommandSpec{
+		Name:        "/maintenance",
+		Description: "Pause or resume replies (admins only)",
+		Args:        []ArgSpec{{Name: "action", Value: "on|off|status"}, {Name: "message", Rest: true}},
+	}
+	resetCommand = CommandSpec{
+		Name:        "/reset",
+		Description: "Start a new conversation for a user in this channel (admins only)",
+		Args:        []ArgSpec{{Name: "user", Value: "@user", Required: true}},
+	}
+	helpCommand = CommandSpec{
+		Name:        "/help",
+		Description: "Show this help message",
+	}
+	slashCommands = []CommandSpec{newCommand, languageCommand, maintenanceCommand, resetCommand, helpCommand}
+)
+
 // handleNewCommand handles the /new command
 func (c *Connector) handleNewCommand(ctx context.Context, cmd slack.SlashCommand) (interface{}, error) {
-	sessionID, err := c.sessionMgr.CreateNewSession(ctx, "slack", cmd.UserID, cmd.ChannelID)
+	sessionID, err := c.sessionMgr.CreateNewSession(ctx, "slack", c.userScope(ctx, cmd.TeamID, cmd.UserID), cmd.ChannelID)
 	if err != nil {
 		return map[string]interface{}{
 			"text": "Failed to create new session.",
@@ -55,12 +108,60 @@ func (c *Connector) handleNewCommand(ctx context.Context, cmd slack.SlashCommand
 	}, nil
 }
 
+// handleLanguageCommand handles the /language command, which sets the language the bot replies in
+func (c *Connector) handleLanguageCommand(ctx context.Context, cmd slack.SlashCommand, args CommandArgs) (interface{}, error) {
+	arg := args.Arg("code")
+	if arg == "" {
+		return map[string]interface{}{
+			"text": fmt.Sprintf("%s (supported: %s)", languageCommand.Usage(), strings.Join(language.Supported(), ", ")),
+		}, nil
+	}
+
+	code := ""
+	if !strings.EqualFold(arg, "auto") {
+		code = language.Parse(arg)
+		if code == "" {
+			return map[string]interface{}{
+				"text": fmt.Sprintf("Unsupported language: %s (supported: %s)", arg, strings.Join(language.Supported(), ", ")),
+			}, nil
+		}
+	}
+
+	scopeKey := c.userScope(ctx, cmd.TeamID, cmd.UserID)
+	sessionID, err := c.sessionMgr.GetOrCreateSession(ctx, "slack", scopeKey, cmd.ChannelID)
+	if err != nil {
+		return map[string]interface{}{
+			"text": "Failed to set language.",
+		}, err
+	}
+	if err := c.executor.SetLanguageOverride(ctx, scopeKey, sessionID, code); err != nil {
+		return map[string]interface{}{
+			"text": "Failed to set language.",
+		}, err
+	}
+
+	if code == "" {
+		return map[string]interface{}{
+			"text": "I'll reply in the language you write in.",
+		}, nil
+	}
+	return map[string]interface{}{
+		"text": fmt.Sprintf("I'll reply in %s in this conversation.", language.Name(code)),
+	}, nil
+}
+
 // handleHelpCommand handles the /help command
 func (c *Connector) handleHelpCommand(_ context.Context, _ slack.SlashCommand) (interface{}, error) {
-	helpText := `*Available Commands:*
+	var b strings.Builder
+	b.WriteString("*Available Commands:*\n")
+	for _, spec := range slashCommands {
+		fmt.Fprintf(&b, "\n• *%s* - %s", spec.Synopsis(), spec.Description)
+	}
+	helpText := b.String()
 
-• */new* - Start a new conversation
-• */help* - Show this help message`
+	if c.intro != "" {
+		helpText = c.intro + "\n\n" + helpText
+	}
 
 	return map[string]interface{}{
 		"text": helpText,
@@ -70,10 +171,13 @@ func (c *Connector) handleHelpCommand(_ context.Context, _ slack.SlashCommand) (
 // setupCommands initializes the command registry with all available commands
 func (c *Connector) setupCommands() {
 	c.commands = NewCommandRegistry()
-	c.commands.Register("/new", func(ctx context.Context, cmd slack.SlashCommand) (interface{}, error) {
+	c.commands.Register(newCommand, func(ctx context.Context, cmd slack.SlashCommand, _ CommandArgs) (interface{}, error) {
 		return c.handleNewCommand(ctx, cmd)
 	})
-	c.commands.Register("/help", func(ctx context.Context, cmd slack.SlashCommand) (interface{}, error) {
+	c.commands.Register(languageCommand, c.handleLanguageCommand)
+	c.commands.Register(maintenanceCommand, c.handleMaintenanceCommand)
+	c.commands.Register(resetCommand, c.handleResetCommand)
+	c.commands.Register(helpCommand, func(ctx context.Context, cmd slack.SlashCommand, _ CommandArgs) (interface{}, error) {
 		return c.handleHelpCommand(ctx, cmd)
 	})
 }
@@ -103,6 +207,10 @@ func (c *Connector) handleSlashCommand(ctx context.Context, envelope socketmode.
 		}
 	}
 
-	// Acknowledge the command with the response
+	// Acknowledge the command with the response, unless it was posted ephemerally
+	if response = c.commandResponse(ctx, cmd, response); response == nil {
+		c.socketMode.Ack(*envelope.Request)
+		return
+	}
 	c.socketMode.Ack(*envelope.Request, response)
 }
@@ -0,0 +1,29 @@
+package slack
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/slack-go/slack"
+)
+
+func TestHandleHelpCommand_IntroducesBot(t *testing.T) {
+	c := &Connector{intro: executor.Introduction("Support Bot", "I answer billing questions.")}
+
+	resp, err := c.handleHelpCommand(context.Background(), slack.SlashCommand{Command: "/help"})
+	if err != nil {
+		t.Fatalf("handleHelpCommand() error = %v", err)
+	}
+	text, _ := resp.(map[string]interface{})["text"].(string)
+	if !strings.HasPrefix(text, "Hi, I'm Support Bot. I answer billing questions.\n\n") || !strings.Contains(text, "*/help*") {
+		t.Errorf("help = %q, want the introduction followed by the commands", text)
+	}
+
+	c = &Connector{}
+	resp, _ = c.handleHelpCommand(context.Background(), slack.SlashCommand{Command: "/help"})
+	if text, _ := resp.(map[string]interface{})["text"].(string); !strings.HasPrefix(text, "*Available Commands:*") {
+		t.Errorf("help = %q, want only the commands without a configured name", text)
+	}
+}
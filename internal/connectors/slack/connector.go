@@ -1,15 +1,23 @@
 package slack
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/analytics"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/audit"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/cache"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/httpclient"
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
@@ -27,14 +35,72 @@ type Connector struct {
 	connected  bool
 	mu         sync.RWMutex
 
-	// Cached bot identity (lazy-initialized via ensureBotIdentity)
-	botUserID string
-	botBotID  string
-	initOnce  sync.Once
+	// Cached bot identity, guarded by identityMu (lazy-initialized via ensureBotIdentity)
+	botUserID  string
+	botBotID   string
+	botName    string
+	botMention *regexp.Regexp // Matches the bot's own mentions, compiled with botUserID
+	identityMu sync.Mutex
 
-	// User display name cache to avoid repeated API calls
-	userNameCache map[string]string
-	cacheMu       sync.RWMutex
+	// Prefixes the bot adds to its own messages, stripped when they're used as thread context
+	selfPrefixes []string
+
+	// Adds the configured prefix/suffix to responses and splits them to fit Slack's limit
+	decorator *executor.Decorator
+
+	// How the bot introduces itself in /help
+	intro string
+
+	// Reply sent with the turn's correlation ID when a message can't be processed
+	errorReply *executor.ErrorMessage
+
+	// Whether replies get 👍/👎 buttons, and where the ratings are recorded
+	feedback  bool
+	analytics *analytics.Log
+
+	// Whether a status line shows which tool the agent is using while it works
+	toolStatus bool
+
+	// Whether command replies and error notices are shown only to the user they're for
+	ephemeralCommands bool
+	ephemeralErrors   bool
+
+	// Whether the bot's presence is set to active on connect and away on shutdown
+	presence bool
+
+	// Slack's assistant pane and Home tab
+	assistant AssistantConfig
+
+	// Paces the messages the bot posts to stay within Slack's rate limits; nil for no pacing
+	throttle *executor.Throttle
+
+	// Suppresses a reply identical to the one just posted to the same channel or thread
+	replies *executor.ReplyDeduper
+
+	// Reconnects with backoff when the Socket Mode connection fails, giving up after too many attempts
+	reconnect *reconnector
+
+	// Channels where every message gets a response, not just @mentions
+	alwaysRespond map[string]bool
+
+	// Prefixes such as "!ai" that address the bot in a channel without a mention
+	triggers executor.TriggerPrefixes
+
+	// User IDs allowed to run admin commands, and where those commands are audited
+	admins map[string]bool
+	audit  *audit.Log
+
+	// User display name, locale and channel name caches to avoid repeated API calls
+	userNameCache    *cache.TTLCache[string, string]
+	userLocaleCache  map[string]string
+	userTZCache      *cache.TTLCache[string, *time.Location]
+	timezone         *time.Location
+	channelNameCache map[string]string
+	cacheMu          sync.RWMutex
+
+	// Channel name, topic and purpose given to the agent with channel messages; nil when
+	// channel context is off
+	channelContextCache *cache.TTLCache[string, string]
 }
 
 // Config holds configuration for the Slack connector
@@ -43,8 +109,97 @@ type Config struct {
 	AppToken string        // xapp-*
 	Debug    bool          // Enable debug logging for Slack API and Socket Mode
 	Logger   logger.Logger // Structured logger instance
+
+	// SelfPrefixes are prefixes the bot adds to its own messages (e.g. a persona tag).
+	// They're stripped from the bot's earlier replies when building thread context.
+	SelfPrefixes []string
+
+	// Optional response prefix and suffix (e.g. a disclaimer) templates; see executor.DecoratorConfig
+	ResponsePrefix   string
+	ResponseSuffix   string
+	SuffixEveryChunk bool   // Add the suffix to every message of a split response, not just the last
+	BotName          string // Value of the {{.BotName}} template variable
+
+	// DisplayName and Intro are how the bot introduces itself to users; see executor.Introduction
+	DisplayName string
+	Intro       string
+
+	// ErrorMessage is the reply template used when a message can't be processed; see
+	// executor.NewErrorMessage. Empty uses executor.DefaultErrorMessage.
+	ErrorMessage string
+
+	// Reconnect controls reconnection when the Socket Mode connection drops; unset fields use defaults
+	Reconnect ReconnectPolicy
+
+	// AlwaysRespondChannels are channel IDs (e.g. a dedicated support channel) where the bot
+	// responds to every message, not only @mentions. Elsewhere a mention is still required.
+	AlwaysRespondChannels []string
+
+	// TriggerPrefixes address the bot in any channel without an @mention: a message starting
+	// with one, e.g. "!ai", gets a response with the prefix removed
+	TriggerPrefixes executor.TriggerPrefixes
+
+	// AdminUsers are user IDs allowed to run admin commands such as /maintenance
+	AdminUsers []string
+
+	// Audit records admin commands; nil to not audit them
+	Audit *audit.Log
+
+	// Feedback adds 👍/👎 buttons to replies; ratings are recorded in Analytics
+	Feedback  bool
+	Analytics *analytics.Log
+
+	// ToolStatus posts a status line such as "Searching the web…" while the agent uses tools,
+	// updated as it moves between them and deleted once it replies
+	ToolStatus bool
+
+	// EphemeralCommands shows slash command replies only to the user who ran the command,
+	// instead of in the channel. EphemeralErrors does the same for the notice sent when a
+	// message can't be processed.
+	EphemeralCommands bool
+	EphemeralErrors   bool
+
+	// Presence sets the bot's presence to active when it connects and away when it shuts
+	// down, so users can see whether it's up. Needs the users:write scope.
+	Presence bool
+
+	// Assistant answers in Slack's assistant threads and publishes the Home tab
+	Assistant AssistantConfig
+
+	// Throttle paces the messages the bot posts, overall and per channel, so bursts don't
+	// hit Slack's rate limits
+	Throttle executor.ThrottleConfig
+
+	// HTTPClient is used for Slack API calls and its proxy for the Socket Mode connection;
+	// nil uses the defaults
+	HTTPClient *http.Client
+
+	// UserCacheSize and UserCacheTTL bound the user display name cache; zero uses
+	// DefaultUserCacheSize and DefaultUserCacheTTL
+	UserCacheSize int
+	UserCacheTTL  time.Duration
+
+	// Timezone timestamps are shown in, such as in thread context, unless the user's Slack
+	// profile has one; nil is UTC
+	Timezone *time.Location
+
+	// ChannelContext gives the agent the channel's name, topic, purpose and member count with
+	// each channel message, cached for ChannelContextTTL (zero uses DefaultChannelContextTTL).
+	// Needs the channels:read scope (groups:read for private channels); without it messages
+	// are sent as they are.
+	ChannelContext    bool
+	ChannelContextTTL time.Duration
 }
 
+// User name cache defaults
+const (
+	DefaultUserCacheSize = 1000
+	DefaultUserCacheTTL  = time.Hour
+)
+
+// maxMessageLength is the longest message Slack accepts before truncating it
+const maxMessageLength = 40000
+
 // NewConnector creates a new Slack connector with in-process executor
 func NewConnector(config Config, exec *executor.Executor, sessionMgr session_manager.Manager) (*Connector, error) {
 	if !strings.HasPrefix(config.BotToken, "xoxb-") {
@@ -63,24 +218,78 @@ func NewConnector(config Config, exec *executor.Executor, sessionMgr session_man
 		return nil, fmt.Errorf("logger is required")
 	}
 
+	decorator, err := executor.NewDecorator(executor.DecoratorConfig{
+		Prefix:           config.ResponsePrefix,
+		Suffix:           config.ResponseSuffix,
+		SuffixEveryChunk: config.SuffixEveryChunk,
+		MaxLength:        maxMessageLength,
+		BotName:          config.BotName,
+		Platform:         "Slack",
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize Slack clients
-	client := slack.New(
-		config.BotToken,
+	clientOpts := []slack.Option{
 		slack.OptionAppLevelToken(config.AppToken),
 		slack.OptionDebug(config.Debug),
-	)
-	socketMode := socketmode.New(client, socketmode.OptionDebug(config.Debug))
+	}
+	socketOpts := []socketmode.Option{socketmode.OptionDebug(config.Debug)}
+	if config.HTTPClient != nil {
+		clientOpts = append(clientOpts, slack.OptionHTTPClient(config.HTTPClient))
+		socketOpts = append(socketOpts, socketmode.OptionDialer(httpclient.WebSocketDialer(config.HTTPClient)))
+	}
+	client := slack.New(config.BotToken, clientOpts...)
+	socketMode := socketmode.New(client, socketOpts...)
+
+	errorReply, err := executor.NewErrorMessage(config.ErrorMessage)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create a logger with Slack-specific context
 	slackLogger := config.Logger.WithFields(logger.StringField("connector", "slack"))
 
 	connector := &Connector{
-		client:        client,
-		socketMode:    socketMode,
-		executor:      exec,
-		logger:        slackLogger,
-		sessionMgr:    sessionMgr,
-		userNameCache: make(map[string]string),
+		client:            client,
+		socketMode:        socketMode,
+		executor:          exec,
+		logger:            slackLogger,
+		sessionMgr:        sessionMgr,
+		selfPrefixes:      config.SelfPrefixes,
+		decorator:         decorator,
+		intro:             executor.Introduction(config.DisplayName, config.Intro),
+		errorReply:        errorReply,
+		feedback:          config.Feedback,
+		analytics:         config.Analytics,
+		toolStatus:        config.ToolStatus,
+		ephemeralCommands: config.EphemeralCommands,
+		ephemeralErrors:   config.EphemeralErrors,
+		presence:          config.Presence,
+		assistant:         config.Assistant,
+		replies:           executor.NewReplyDeduper(executor.DuplicateReplyWindow),
+		reconnect:         newReconnector(config.Reconnect, slackLogger),
+		alwaysRespond:     make(map[string]bool, len(config.AlwaysRespondChannels)),
+		triggers:          config.TriggerPrefixes,
+		admins:            make(map[string]bool, len(config.AdminUsers)),
+		audit:             config.Audit,
+		userNameCache:     cache.New[string, string](cmp.Or(config.UserCacheSize, DefaultUserCacheSize), cmp.Or(config.UserCacheTTL, DefaultUserCacheTTL)),
+		userLocaleCache:   make(map[string]string),
+		userTZCache:       cache.New[string, *time.Location](cmp.Or(config.UserCacheSize, DefaultUserCacheSize), cmp.Or(config.UserCacheTTL, DefaultUserCacheTTL)),
+		timezone:          config.Timezone,
+		channelNameCache:  make(map[string]string),
+	}
+
+	if config.ChannelContext {
+		connector.channelContextCache = cache.New[string, string](channelContextCacheSize, cmp.Or(config.ChannelContextTTL, DefaultChannelContextTTL))
+	}
+
+	for _, channel := range config.AlwaysRespondChannels {
+		connector.alwaysRespond[strings.TrimSpace(channel)] = true
+	}
+	for _, user := range config.AdminUsers {
+		connector.admins[strings.TrimSpace(user)] = true
 	}
 
 	// Setup slash command handlers
@@ -104,16 +313,27 @@ func (c *Connector) Start(ctx context.Context) error {
 				c.mu.Unlock()
 
 			case socketmode.EventTypeConnectionError:
-				c.logger.Error("Connection failed", logger.StringField("data", fmt.Sprintf("%v", envelope.Data)))
 				c.mu.Lock()
 				c.connected = false
 				c.mu.Unlock()
 
+				var err error = fmt.Errorf("%v", envelope.Data)
+				if connErr, ok := envelope.Data.(*slack.ConnectionErrorEvent); ok {
+					err = connErr.ErrorObj
+				}
+				attempt, _ := c.reconnect.recordFailure(err)
+				c.logger.Error("Connection failed",
+					logger.IntField("attempt", attempt),
+					logger.IntField("max_retries", c.reconnect.policy.MaxRetries),
+					logger.ErrorField(err))
+
 			case socketmode.EventTypeConnected:
 				c.logger.Info("Connected to Slack with Socket Mode")
 				c.mu.Lock()
 				c.connected = true
 				c.mu.Unlock()
+				c.reconnect.connected()
+				c.setPresence(ctx, presenceActive)
 
 			case socketmode.EventTypeHello:
 				// Hello event confirms WebSocket connection - no action needed
@@ -136,7 +356,9 @@ func (c *Connector) Start(ctx context.Context) error {
 			case socketmode.EventTypeInteractive:
 				c.logger.Debug("Interactive event received")
 				c.socketMode.Ack(*envelope.Request)
-				// Handle interactive events if needed
+				if callback, ok := envelope.Data.(slack.InteractionCallback); ok {
+					c.handleInteraction(ctx, callback)
+				}
 
 			case socketmode.EventTypeSlashCommand:
 				c.handleSlashCommand(ctx, envelope)
@@ -166,7 +388,8 @@ func (c *Connector) Start(ctx context.Context) error {
 				c.logger.Error("Invalid authentication for Slack Socket Mode")
 
 			case socketmode.EventTypeDisconnect:
-				c.logger.Warn("Disconnected from Slack Socket Mode")
+				// The Slack library reconnects on its own; failed attempts are reported as connection errors
+				c.logger.Warn("Disconnected from Slack Socket Mode, reconnecting")
 				c.mu.Lock()
 				c.connected = false
 				c.mu.Unlock()
@@ -180,8 +403,9 @@ func (c *Connector) Start(ctx context.Context) error {
 		}
 	}()
 
-	// Start the connection
-	return c.socketMode.RunContext(ctx)
+	// Start the connection, reconnecting when it fails, and show the bot as away once it stops
+	defer c.setAway() //nolint:contextcheck // ctx is done by the time the bot is marked away
+	return c.reconnect.run(ctx, c.socketMode.RunContext)
 }
 
 // handleEvent processes Slack events and routes them to the agent
@@ -190,16 +414,21 @@ func (c *Connector) handleEvent(ctx context.Context, event slackevents.EventsAPI
 		innerEvent := event.InnerEvent
 		switch ev := innerEvent.Data.(type) {
 		case *slackevents.MessageEvent:
-			return c.handleMessageEvent(ctx, ev)
+			return c.handleMessageEvent(ctx, event.TeamID, ev)
 		case *slackevents.AppMentionEvent:
-			return c.handleAppMentionEvent(ctx, ev)
+			return c.handleAppMentionEvent(ctx, event.TeamID, ev)
+		case *slackevents.AssistantThreadStartedEvent:
+			return c.handleAssistantThreadStarted(ctx, ev)
+		case *slackevents.AppHomeOpenedEvent:
+			return c.handleAppHomeOpened(ctx, ev)
 		}
 	}
 	return nil
 }
 
-// handleMessageEvent processes direct messages to the bot
-func (c *Connector) handleMessageEvent(ctx context.Context, event *slackevents.MessageEvent) error {
+// handleMessageEvent processes direct messages to the bot, and channel messages in
+// always-respond channels or starting with a trigger prefix
+func (c *Connector) handleMessageEvent(ctx context.Context, teamID string, event *slackevents.MessageEvent) error {
 	// Skip messages from bots to avoid loops
 	if event.BotID != "" || event.SubType == "bot_message" {
 		c.logger.Debug("Skipping bot message",
@@ -227,7 +456,18 @@ func (c *Connector) handleMessageEvent(ctx context.Context, event *slackevents.M
 		"pinned_item": true, "unpinned_item": true, "reminder_add": true,
 		"ekm_access_denied": true, "assistant_app_thread": true,
 	}
-	if systemSubtypes[event.SubType] {
+	// Files shared in a DM are ingested as searchable documents when enabled;
+	// any accompanying text is then processed like a normal message
+	fileShare := event.SubType == "file_share" && strings.HasPrefix(event.Channel, "D") &&
+		event.User != "" && c.executor.DocumentsEnabled()
+	if fileShare {
+		c.ingestSharedFiles(ctx, teamID, event)
+		if strings.TrimSpace(event.Text) == "" {
+			return nil
+		}
+	}
+
+	if systemSubtypes[event.SubType] && !fileShare {
 		c.logger.Debug("Skipping system message", logger.StringField("sub_type", event.SubType))
 		return nil
 	}
@@ -238,43 +478,64 @@ func (c *Connector) handleMessageEvent(ctx context.Context, event *slackevents.M
 		return nil
 	}
 
-	// Only process direct messages (DMs have channel type starting with D)
+	// Channel messages are only processed in always-respond channels or when they start with
+	// a trigger prefix; otherwise the bot responds to @mentions, which arrive as app_mention events
 	if !strings.HasPrefix(event.Channel, "D") {
-		return nil
+		if c.isOwnMessage(slack.Message{Msg: slack.Msg{User: event.User, BotID: event.BotID}}) || c.mentionsBot(event.Text) {
+			return nil
+		}
+		if _, prefixed := c.triggers.Match(event.Channel, event.Text); !prefixed && !c.alwaysRespond[event.Channel] {
+			return nil
+		}
+		return c.handleChannelMessage(ctx, teamID, event.User, event.Channel, event.TimeStamp, event.ThreadTimeStamp, event.Text, false)
+	}
+
+	// Messages in assistant threads are answered in the thread, each thread its own conversation
+	if c.assistant.Enabled && event.ThreadTimeStamp != "" {
+		return c.handleAssistantMessage(ctx, teamID, event)
 	}
 
-	c.logger.Info("Processing DM",
+	// Everything logged for this turn carries its correlation ID, which users are given on failure
+	correlationID := executor.NewCorrelationID()
+	ctx = logger.WithCorrelationIDContext(ctx, correlationID)
+	log := c.logger.WithFields(
+		logger.CorrelationIDField(correlationID),
 		logger.StringField("user_id", event.User),
 		logger.StringField("channel", event.Channel))
+	log.Info("Processing DM")
 
 	// Send message to agent via executor
 	// Get or create session for this user
-	sessionID, err := c.sessionMgr.GetOrCreateSession(ctx, "slack", event.User, event.Channel)
+	scopeKey := c.userScope(ctx, teamID, event.User)
+	sessionID, err := c.sessionMgr.GetOrCreateSession(ctx, "slack", scopeKey, event.Channel)
 	if err != nil {
-		c.logger.Error("Error getting session", logger.ErrorField(err))
+		log.Error("Error getting session", logger.ErrorField(err))
+		_ = c.postError(ctx, event.Channel, "", event.User, correlationID)
 		return fmt.Errorf("failed to get session: %w", err)
 	}
 
+	status := c.newToolStatus(event.Channel, "")
+	defer status.clear(ctx)
+
 	response, err := c.executor.Execute(ctx, executor.MessageRequest{
-		UserID:    event.User,
-		SessionID: sessionID,
-		Message:   event.Text,
+		UserID:     scopeKey,
+		SessionID:  sessionID,
+		Message:    c.resolveMentions(ctx, event.Text),
+		Locale:     c.resolveUserLocale(ctx, event.User),
+		ChannelID:  event.Channel,
+		OnToolCall: status.onToolCall(ctx),
 	}, c, func() string {
 		return c.GetUserInfo(ctx, event.User)
 	})
 	if err != nil {
-		c.logger.Error("Error from executor", logger.ErrorField(err))
-		_, _, err = c.client.PostMessage(event.Channel,
-			slack.MsgOptionText("Sorry, I encountered an error processing your message.", false))
-		return err
+		log.Error("Error from executor", logger.StringField("session_id", sessionID), logger.ErrorField(err))
+		return c.postError(ctx, event.Channel, "", event.User, correlationID)
 	}
 
 	// Send response back to Slack
 	if response.Text != "" {
-		_, _, err = c.client.PostMessage(event.Channel,
-			slack.MsgOptionText(response.Text, false))
-		if err != nil {
-			c.logger.Error("Error sending message to Slack", logger.ErrorField(err))
+		if err := c.postResponse(ctx, event.Channel, "", sessionID, response.Text); err != nil {
+			log.Error("Error sending message to Slack", logger.ErrorField(err))
 			return err
 		}
 	}
@@ -283,63 +544,91 @@ func (c *Connector) handleMessageEvent(ctx context.Context, event *slackevents.M
 }
 
 // handleAppMentionEvent processes @bot mentions in channels
-func (c *Connector) handleAppMentionEvent(ctx context.Context, event *slackevents.AppMentionEvent) error {
+func (c *Connector) handleAppMentionEvent(ctx context.Context, teamID string, event *slackevents.AppMentionEvent) error {
+	return c.handleChannelMessage(ctx, teamID, event.User, event.Channel, event.TimeStamp, event.ThreadTimeStamp, event.Text, true)
+}
+
+// handleChannelMessage responds to a channel message in its thread. mentioned is set when
+// the message @mentions the bot, so the mention is removed before it's sent to the agent,
+// as is a trigger prefix the message starts with.
+func (c *Connector) handleChannelMessage(ctx context.Context, teamID, userID, channel, ts, threadTS, text string, mentioned bool) error {
 	// Determine thread root: if already in a thread use that TS, otherwise this message starts the thread
-	threadTS := event.ThreadTimeStamp
 	if threadTS == "" {
-		threadTS = event.TimeStamp
+		threadTS = ts
 	}
 
-	c.logger.Info("Processing mention",
-		logger.StringField("user_id", event.User),
-		logger.StringField("channel", event.Channel),
+	// Everything logged for this turn carries its correlation ID, which users are given on failure
+	correlationID := executor.NewCorrelationID()
+	ctx = logger.WithCorrelationIDContext(ctx, correlationID)
+	log := c.logger.WithFields(
+		logger.CorrelationIDField(correlationID),
+		logger.StringField("user_id", userID),
+		logger.StringField("channel", channel),
 		logger.StringField("thread_ts", threadTS))
+	log.Info("Processing channel message")
 
 	// Fetch the full message from the API so we get attachments, blocks, and files
-	// (the AppMentionEvent only carries the plain Text field).
-	cleanText := c.removeBotMention(c.fetchFullMessageText(ctx, event.Channel, event.TimeStamp, event.Text))
+	// (message events only carry the plain Text field).
+	cleanText := c.fetchFullMessageText(ctx, channel, ts, text)
+	if mentioned {
+		cleanText = c.removeBotMention(cleanText)
+	}
+	if text, prefixed := c.triggers.Match(channel, cleanText); prefixed {
+		cleanText = text
+	}
+	cleanText = c.resolveMentions(ctx, cleanText)
 
 	// Fetch thread context if this is a reply in an existing thread
-	threadContext := c.getThreadContext(ctx, event.Channel, threadTS, event.TimeStamp)
+	threadContext := c.getThreadContext(ctx, userID, channel, threadTS, ts)
 
 	// Compose the full message with thread context if available
 	fullMessage := cleanText
 	if threadContext != "" {
-		userName := c.resolveUserName(ctx, event.User, "")
+		userName := c.resolveUserName(ctx, userID, "")
 		fullMessage = fmt.Sprintf("%s\n\n%s's message to you: %s", threadContext, userName, cleanText)
 	}
 
 	// Thread-scoped session: all users in the same thread share one session
-	scopeKey := fmt.Sprintf("thread:%s:%s", event.Channel, threadTS)
+	scopeKey := c.threadScope(ctx, teamID, channel, threadTS)
 
-	sessionID, err := c.sessionMgr.GetOrCreateSession(ctx, "slack", scopeKey, event.Channel)
+	sessionID, err := c.sessionMgr.GetOrCreateSession(ctx, "slack", scopeKey, channel)
 	if err != nil {
-		c.logger.Error("Error getting session", logger.ErrorField(err))
+		log.Error("Error getting session", logger.ErrorField(err))
+		_ = c.postError(ctx, channel, threadTS, userID, correlationID)
 		return fmt.Errorf("failed to get session: %w", err)
 	}
+	// Remember who spoke in the thread, so their data can be exported or deleted
+	if err := c.sessionMgr.AddParticipant(ctx, sessionID, userID); err != nil {
+		log.Warn("Failed to record thread participant", logger.StringField("session_id", sessionID), logger.ErrorField(err))
+	}
+
+	status := c.newToolStatus(channel, threadTS)
+	defer status.clear(ctx)
 
 	response, err := c.executor.Execute(ctx, executor.MessageRequest{
 		UserID:    scopeKey,
 		SessionID: sessionID,
 		Message:   fullMessage,
+		UserText:  cleanText,
+		// Documents are ingested in DMs, so search the sender's rather than the thread's
+		DocumentsUserID: c.userScope(ctx, teamID, userID),
+		Locale:          c.resolveUserLocale(ctx, userID),
+		ChannelID:       channel,
+		// Tell the agent which channel it's in, when channel context is on
+		ChannelContext: c.getChannelContext(ctx, channel),
+		OnToolCall:     status.onToolCall(ctx),
 	}, c, func() string {
-		return c.GetUserInfo(ctx, event.User)
+		return c.GetUserInfo(ctx, userID)
 	})
 	if err != nil {
-		c.logger.Error("Error from executor", logger.ErrorField(err))
-		_, _, err = c.client.PostMessage(event.Channel,
-			slack.MsgOptionText("Sorry, I encountered an error processing your message.", false),
-			slack.MsgOptionTS(threadTS))
-		return err
+		log.Error("Error from executor", logger.StringField("session_id", sessionID), logger.ErrorField(err))
+		return c.postError(ctx, channel, threadTS, userID, correlationID)
 	}
 
 	// Send response back in the thread
 	if response.Text != "" {
-		_, _, err = c.client.PostMessage(event.Channel,
-			slack.MsgOptionText(response.Text, false),
-			slack.MsgOptionTS(threadTS))
-		if err != nil {
-			c.logger.Error("Error sending message to Slack", logger.ErrorField(err))
+		if err := c.postResponse(ctx, channel, threadTS, sessionID, response.Text); err != nil {
+			log.Error("Error sending message to Slack", logger.ErrorField(err))
 			return err
 		}
 	}
@@ -347,41 +636,106 @@ func (c *Connector) handleAppMentionEvent(ctx context.Context, event *slackevent
 	return nil
 }
 
-// removeBotMention removes @bot mentions from message text
-func (c *Connector) removeBotMention(text string) string {
-	// Remove <@UBOT_ID> mentions - this is a simplified approach
-	// In production, you'd want to get the actual bot user ID and remove it properly
-	cleaned := text
-	// Simple regex-like removal for mentions
-	if strings.Contains(text, "<@") {
-		// Find and remove the mention part
-		start := strings.Index(text, "<@")
-		end := strings.Index(text[start:], ">")
-		if end != -1 {
-			cleaned = strings.TrimSpace(text[:start] + text[start+end+1:])
+// postResponse sends an agent response to a channel, or a thread when threadTS is set, with the
+// configured prefix and suffix, split into several messages if it's longer than Slack allows.
+// A response identical to the one just posted to the same conversation is skipped. When
+// feedback is enabled the last message carries 👍/👎 buttons for the session.
+func (c *Connector) postResponse(ctx context.Context, channel, threadTS, sessionID, text string) error {
+	conversation := channel + ":" + threadTS
+	if c.replies.CheckAndRecord(conversation, text) {
+		c.logger.Info("Skipping duplicate reply",
+			logger.StringField("channel", channel),
+			logger.StringField("thread_ts", threadTS))
+		return nil
+	}
+
+	var options []slack.MsgOption
+	if threadTS != "" {
+		options = append(options, slack.MsgOptionTS(threadTS))
+	}
+
+	chunks := c.decorator.Apply(text)
+	for i, chunk := range chunks {
+		msgOptions := append([]slack.MsgOption{slack.MsgOptionText(chunk, false)}, options...)
+		if c.feedback && i == len(chunks)-1 {
+			msgOptions = append(msgOptions, slack.MsgOptionAttachments(feedbackAttachment(sessionID)))
+		}
+		if err := c.throttle.Wait(ctx, channel); err != nil {
+			c.replies.Forget(conversation, text)
+			return err
+		}
+		if _, _, err := c.client.PostMessageContext(ctx, channel, msgOptions...); err != nil {
+			c.replies.Forget(conversation, text)
+			return err
 		}
 	}
-	return cleaned
+	return nil
+}
+
+// resolveUserLocale returns the Slack locale of a user (e.g. "en-US"), used as the default
+// conversation language. It returns "" if language detection is off or the user can't be fetched.
+func (c *Connector) resolveUserLocale(ctx context.Context, userID string) string {
+	if userID == "" || !c.executor.LanguageDetectionEnabled() {
+		return ""
+	}
+
+	c.cacheMu.RLock()
+	if locale, ok := c.userLocaleCache[userID]; ok {
+		c.cacheMu.RUnlock()
+		return locale
+	}
+	c.cacheMu.RUnlock()
+
+	user, err := c.client.GetUserInfoContext(ctx, userID)
+	if err != nil {
+		return ""
+	}
+
+	c.cacheMu.Lock()
+	c.userLocaleCache[userID] = user.Locale
+	c.cacheMu.Unlock()
+
+	return user.Locale
 }
 
-// ensureBotIdentity lazily fetches and caches the bot's own user ID and bot ID.
-func (c *Connector) ensureBotIdentity() {
-	c.initOnce.Do(func() {
-		auth, err := c.client.AuthTest()
-		if err != nil {
-			c.logger.Warn("Failed to cache bot identity", logger.ErrorField(err))
-			return
+// removeBotMention removes every @mention of the bot from message text, including the
+// <@U123|name> form, leaving mentions of other users to be resolved to their names. The
+// spaces around a removed mention are collapsed to one, or dropped at the start or end of
+// a line.
+func (c *Connector) removeBotMention(text string) string {
+	mention := c.botMentionPattern()
+	if mention == nil {
+		return text
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range mention.FindAllStringIndex(text, -1) {
+		b.WriteString(text[last:loc[0]])
+		// Keep one space between the words either side, unless the mention starts or ends a
+		// line or directly follows another mention, which already left one
+		atLineEdge := loc[0] == 0 || text[loc[0]-1] == '\n' || loc[1] == len(text) || text[loc[1]] == '\n'
+		if !atLineEdge && loc[0] != last {
+			b.WriteByte(' ')
 		}
-		c.botUserID = auth.UserID
-		c.botBotID = auth.BotID
-	})
+		last = loc[1]
+	}
+	b.WriteString(text[last:])
+	return strings.TrimSpace(b.String())
+}
+
+// mentionsBot reports whether text @mentions the bot. Such messages also arrive as
+// app_mention events, so they're answered there rather than twice.
+func (c *Connector) mentionsBot(text string) bool {
+	identity := c.ensureBotIdentity()
+	return identity.UserID != "" && (strings.Contains(text, "<@"+identity.UserID+">") || strings.Contains(text, "<@"+identity.UserID+"|"))
 }
 
 // resolveUserName resolves a Slack user ID or bot ID to a display name.
 func (c *Connector) resolveUserName(ctx context.Context, userID, botID string) string {
-	c.ensureBotIdentity()
+	identity := c.ensureBotIdentity()
 
-	if botID != "" && botID == c.botBotID {
+	if botID != "" && botID == identity.BotID {
 		return "You (assistant)"
 	}
 	if botID != "" {
@@ -392,12 +746,9 @@ func (c *Connector) resolveUserName(ctx context.Context, userID, botID string) s
 	}
 
 	// Check cache
-	c.cacheMu.RLock()
-	if name, ok := c.userNameCache[userID]; ok {
-		c.cacheMu.RUnlock()
+	if name, ok := c.userNameCache.Get(userID); ok {
 		return name
 	}
-	c.cacheMu.RUnlock()
 
 	// Fetch from API
 	user, err := c.client.GetUserInfoContext(ctx, userID)
@@ -412,9 +763,7 @@ func (c *Connector) resolveUserName(ctx context.Context, userID, botID string) s
 		name = user.RealName
 	}
 
-	c.cacheMu.Lock()
-	c.userNameCache[userID] = name
-	c.cacheMu.Unlock()
+	c.userNameCache.Set(userID, name)
 
 	return name
 }
@@ -479,6 +828,14 @@ func extractMessageText(msg slack.Message) string {
 			}
 		case *slack.RichTextBlock:
 			parts = append(parts, extractRichTextBlock(b)...)
+		case *slack.ImageBlock:
+			if text := extractImageBlock(b); text != "" {
+				parts = append(parts, text)
+			}
+		case *slack.ContextBlock:
+			if text := extractContextBlock(b); text != "" {
+				parts = append(parts, text)
+			}
 		}
 	}
 	if len(parts) > 0 {
@@ -508,82 +865,114 @@ func extractRichTextBlock(block *slack.RichTextBlock) []string {
 	for _, elem := range block.Elements {
 		switch el := elem.(type) {
 		case *slack.RichTextSection:
-			var sectionText strings.Builder
-			for _, se := range el.Elements {
-				switch ste := se.(type) {
-				case *slack.RichTextSectionTextElement:
-					sectionText.WriteString(ste.Text)
-				case *slack.RichTextSectionLinkElement:
-					if ste.Text != "" {
-						sectionText.WriteString(ste.Text)
-					} else {
-						sectionText.WriteString(ste.URL)
-					}
-				}
-			}
-			if sectionText.Len() > 0 {
-				parts = append(parts, sectionText.String())
+			if text := renderRichTextElements(el.Elements); text != "" {
+				parts = append(parts, text)
 			}
 		case *slack.RichTextList:
 			for _, item := range el.Elements {
 				if section, ok := item.(*slack.RichTextSection); ok {
-					var itemText strings.Builder
-					for _, se := range section.Elements {
-						switch ste := se.(type) {
-						case *slack.RichTextSectionTextElement:
-							itemText.WriteString(ste.Text)
-						case *slack.RichTextSectionLinkElement:
-							if ste.Text != "" {
-								itemText.WriteString(ste.Text)
-							} else {
-								itemText.WriteString(ste.URL)
-							}
-						}
-					}
-					if itemText.Len() > 0 {
-						parts = append(parts, "- "+itemText.String())
+					if text := renderRichTextElements(section.Elements); text != "" {
+						parts = append(parts, "- "+text)
 					}
 				}
 			}
 		case *slack.RichTextQuote:
-			var quoteText strings.Builder
-			for _, se := range el.Elements {
-				switch ste := se.(type) {
-				case *slack.RichTextSectionTextElement:
-					quoteText.WriteString(ste.Text)
-				case *slack.RichTextSectionLinkElement:
-					if ste.Text != "" {
-						quoteText.WriteString(ste.Text)
-					} else {
-						quoteText.WriteString(ste.URL)
-					}
-				}
-			}
-			if quoteText.Len() > 0 {
-				parts = append(parts, "> "+quoteText.String())
+			if text := renderRichTextElements(el.Elements); text != "" {
+				parts = append(parts, "> "+text)
 			}
 		case *slack.RichTextPreformatted:
-			var codeText strings.Builder
-			for _, se := range el.Elements {
-				switch ste := se.(type) {
-				case *slack.RichTextSectionTextElement:
-					codeText.WriteString(ste.Text)
-				case *slack.RichTextSectionLinkElement:
-					if ste.Text != "" {
-						codeText.WriteString(ste.Text)
-					} else {
-						codeText.WriteString(ste.URL)
-					}
-				}
-			}
-			if codeText.Len() > 0 {
-				parts = append(parts, "```\n"+codeText.String()+"\n```")
+			if text := renderRichTextElements(el.Elements); text != "" {
+				parts = append(parts, "```\n"+text+"\n```")
 			}
 		}
 	}
 	return parts
 }
 
+// renderRichTextElements renders rich text section elements as plain text. Mentions are
+// rendered in Slack's <@U123>/<#C123> markup so they read the same as a message's text field.
+func renderRichTextElements(elements []slack.RichTextSectionElement) string {
+	var text strings.Builder
+	for _, se := range elements {
+		switch ste := se.(type) {
+		case *slack.RichTextSectionTextElement:
+			text.WriteString(ste.Text)
+		case *slack.RichTextSectionLinkElement:
+			if ste.Text != "" {
+				text.WriteString(ste.Text)
+			} else {
+				text.WriteString(ste.URL)
+			}
+		case *slack.RichTextSectionUserElement:
+			text.WriteString("<@" + ste.UserID + ">")
+		case *slack.RichTextSectionChannelElement:
+			text.WriteString("<#" + ste.ChannelID + ">")
+		case *slack.RichTextSectionUserGroupElement:
+			text.WriteString("<!subteam^" + ste.UsergroupID + ">")
+		case *slack.RichTextSectionBroadcastElement:
+			text.WriteString("@" + ste.Range)
+		case *slack.RichTextSectionEmojiElement:
+			text.WriteString(renderEmoji(ste))
+		}
+	}
+	return text.String()
+}
+
+// renderEmoji renders an emoji element as its Unicode character when known, or as :name: otherwise.
+func renderEmoji(emoji *slack.RichTextSectionEmojiElement) string {
+	if emoji.Unicode != "" {
+		var decoded strings.Builder
+		for _, codePoint := range strings.Split(emoji.Unicode, "-") {
+			r, err := strconv.ParseInt(codePoint, 16, 32)
+			if err != nil {
+				decoded.Reset()
+				break
+			}
+			decoded.WriteRune(rune(r))
+		}
+		if decoded.Len() > 0 {
+			return decoded.String()
+		}
+	}
+	return ":" + emoji.Name + ":"
+}
+
+// extractImageBlock describes an image block using its title and alt text.
+func extractImageBlock(block *slack.ImageBlock) string {
+	var label string
+	if block.Title != nil {
+		label = block.Title.Text
+	}
+	if block.AltText != "" && block.AltText != label {
+		if label != "" {
+			label += ": "
+		}
+		label += block.AltText
+	}
+	if label == "" {
+		return ""
+	}
+	return fmt.Sprintf("[Image: %s]", label)
+}
+
+// extractContextBlock joins the text and image alt text of a context block's elements.
+func extractContextBlock(block *slack.ContextBlock) string {
+	var texts []string
+	for _, elem := range block.ContextElements.Elements {
+		switch el := elem.(type) {
+		case *slack.TextBlockObject:
+			if el.Text != "" {
+				texts = append(texts, el.Text)
+			}
+		case *slack.ImageBlockElement:
+			if el.AltText != "" {
+				texts = append(texts, fmt.Sprintf("[Image: %s]", el.AltText))
+			}
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
 // fetchFullMessageText retrieves the complete Slack message (with attachments, blocks, files)
 // for a given channel and timestamp, and extracts readable text from it.
 // Falls back to fallbackText if the API call fails or no richer content is found.
@@ -614,9 +1003,9 @@ func (c *Connector) fetchFullMessageText(ctx context.Context, channelID, timesta
 	return fallbackText
 }
 
-// formatSlackTimestamp converts a Slack timestamp (e.g. "1234567890.123456") to
-// a human-readable format like "[2026-02-16 09:12 UTC]".
-func formatSlackTimestamp(ts string) string {
+// formatSlackTimestamp converts a Slack timestamp (e.g. "1234567890.123456") to a
+// human-readable time in loc like "[2026-02-16 09:12 UTC]". A nil loc is UTC.
+func formatSlackTimestamp(ts string, loc *time.Location) string {
 	parts := strings.SplitN(ts, ".", 2)
 	if len(parts) == 0 {
 		return ""
@@ -625,12 +1014,41 @@ func formatSlackTimestamp(ts string) string {
 	if err != nil {
 		return ""
 	}
-	return time.Unix(sec, 0).UTC().Format("[2006-01-02 15:04 UTC]")
+	if loc == nil {
+		loc = time.UTC
+	}
+	return time.Unix(sec, 0).In(loc).Format("[2006-01-02 15:04 MST]")
+}
+
+// resolveUserTimezone returns the timezone times are shown to a user in: the timezone in
+// their Slack profile, or the configured default when it's unset or can't be fetched.
+func (c *Connector) resolveUserTimezone(ctx context.Context, userID string) *time.Location {
+	if userID == "" || c.userTZCache == nil {
+		return c.timezone
+	}
+	if loc, ok := c.userTZCache.Get(userID); ok {
+		return loc
+	}
+
+	user, err := c.client.GetUserInfoContext(ctx, userID)
+	if err != nil {
+		return c.timezone
+	}
+
+	loc := c.timezone
+	if user.TZ != "" {
+		if userLoc, err := time.LoadLocation(user.TZ); err == nil {
+			loc = userLoc
+		}
+	}
+	c.userTZCache.Set(userID, loc)
+	return loc
 }
 
-// getThreadContext fetches thread history and formats it as context for the LLM.
-// Returns empty string if this is a new thread (no prior messages) or on error.
-func (c *Connector) getThreadContext(ctx context.Context, channelID, threadTS, currentMsgTS string) string {
+// getThreadContext fetches thread history and formats it as context for the LLM, with times
+// in the timezone of userID, the user being answered. Returns empty string if this is a new
+// thread (no prior messages) or on error.
+func (c *Connector) getThreadContext(ctx context.Context, userID, channelID, threadTS, currentMsgTS string) string {
 	// If this message starts the thread, there's no prior context
 	if threadTS == currentMsgTS {
 		return ""
@@ -660,6 +1078,7 @@ func (c *Connector) getThreadContext(ctx context.Context, channelID, threadTS, c
 		threadContext.WriteString("[...earlier messages omitted, showing most recent messages]\n")
 	}
 
+	loc := c.resolveUserTimezone(ctx, userID)
 	hasContent := false
 	for _, msg := range msgs {
 		if msg.Timestamp == currentMsgTS {
@@ -667,12 +1086,18 @@ func (c *Connector) getThreadContext(ctx context.Context, channelID, threadTS, c
 		}
 
 		displayName := c.resolveUserName(ctx, msg.User, msg.BotID)
-		text := c.removeBotMention(extractMessageText(msg))
+		var text string
+		if c.isOwnMessage(msg) {
+			displayName = "You (assistant)"
+			text = c.resolveMentions(ctx, c.normalizeOwnMessage(extractMessageText(msg)))
+		} else {
+			text = c.resolveMentions(ctx, c.removeBotMention(extractMessageText(msg)))
+		}
 		if text == "" {
 			continue
 		}
 
-		if ts := formatSlackTimestamp(msg.Timestamp); ts != "" {
+		if ts := formatSlackTimestamp(msg.Timestamp, loc); ts != "" {
 			threadContext.WriteString(fmt.Sprintf("%s %s: %s\n", ts, displayName, text))
 		} else {
 			threadContext.WriteString(fmt.Sprintf("%s: %s\n", displayName, text))
@@ -698,14 +1123,17 @@ func (c *Connector) Stop() error {
 
 // GetBotInfo returns information about the bot
 func (c *Connector) GetBotInfo() (*slack.Bot, error) {
-	auth, err := c.client.AuthTest()
+	identity, err := c.BotIdentity(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	return c.client.GetBotInfo(slack.GetBotInfoParameters{Bot: auth.BotID})
+	return c.client.GetBotInfo(slack.GetBotInfoParameters{Bot: identity.BotID})
 }
 
+// Ensure the connector supplies platform guidance to the agent
+var _ agents.PlatformSpecificGuidanceProvider = (*Connector)(nil)
+
 // PlatformName returns the platform name
 func (c *Connector) PlatformName() string {
 	return "Slack"
@@ -812,3 +1240,25 @@ func (c *Connector) Ready() error {
 
 	return nil
 }
+
+// FormatSources renders cited sources as Slack links
+func (c *Connector) FormatSources(sources []executor.Source) string {
+	var b strings.Builder
+	b.WriteString("*Sources*")
+	for _, source := range sources {
+		title := strings.NewReplacer("<", "", ">", "", "|", "-").Replace(source.Title)
+		fmt.Fprintf(&b, "\n• <%s|%s>", source.URL, title)
+	}
+	return b.String()
+}
+
+// SendReminder posts a reminder to a channel; see reminders.Sender
+func (c *Connector) SendReminder(ctx context.Context, channelID, text string) error {
+	if err := c.throttle.Wait(ctx, channelID); err != nil {
+		return fmt.Errorf("failed to send reminder: %w", err)
+	}
+	if _, _, err := c.client.PostMessageContext(ctx, channelID, slack.MsgOptionText(text, false)); err != nil {
+		return fmt.Errorf("failed to send reminder: %w", err)
+	}
+	return nil
+}
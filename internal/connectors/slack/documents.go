@@ -0,0 +1,61 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// ingestSharedFiles stores text files shared in a DM as documents for the sender
+// and replies with the outcome for each file.
+func (c *Connector) ingestSharedFiles(ctx context.Context, teamID string, event *slackevents.MessageEvent) {
+	if event.Message == nil {
+		return
+	}
+
+	for _, file := range event.Message.Files {
+		reply := c.ingestFile(ctx, c.userScope(ctx, teamID, event.User), file)
+		if err := c.throttle.Wait(ctx, event.Channel); err != nil {
+			return
+		}
+		if _, _, err := c.client.PostMessageContext(ctx, event.Channel, slack.MsgOptionText(reply, false)); err != nil {
+			c.logger.Error("Error sending message to Slack", logger.ErrorField(err))
+		}
+	}
+}
+
+// ingestFile downloads a single shared file and ingests it, returning a message for the user.
+func (c *Connector) ingestFile(ctx context.Context, userID string, file slack.File) string {
+	if file.Size > executor.MaxDocumentSize {
+		return fmt.Sprintf("%s is too large to add to your documents (limit is %d KB).", file.Name, executor.MaxDocumentSize/1024)
+	}
+	if !executor.IsTextDocument(file.Mimetype, file.Name) {
+		return fmt.Sprintf("I can only add text documents to your documents, so I skipped %s.", file.Name)
+	}
+
+	var buf bytes.Buffer
+	if err := c.client.GetFileContext(ctx, file.URLPrivateDownload, &buf); err != nil {
+		c.logger.Error("Error downloading file from Slack",
+			logger.StringField("file_id", file.ID),
+			logger.ErrorField(err))
+		return fmt.Sprintf("Sorry, I couldn't download %s.", file.Name)
+	}
+
+	chunks, err := c.executor.IngestDocument(ctx, userID, file.Name, buf.Bytes(), map[string]string{
+		"source":  "slack",
+		"file_id": file.ID,
+	})
+	if err != nil {
+		c.logger.Error("Error ingesting document",
+			logger.StringField("file_id", file.ID),
+			logger.ErrorField(err))
+		return fmt.Sprintf("Sorry, I couldn't add %s to your documents.", file.Name)
+	}
+
+	return fmt.Sprintf("Added %s to your documents (%d sections). Ask me about it any time.", file.Name, chunks)
+}
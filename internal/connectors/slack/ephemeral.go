@@ -0,0 +1,60 @@
+package slack
+
+import (
+	"context"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+)
+
+// commandResponse returns the payload a slash command is acknowledged with. When command
+// replies are ephemeral the reply is posted for the invoking user and the command is
+// acknowledged without a payload, so nil is returned. If that post fails, e.g. in a channel
+// the bot isn't a member of, the reply is sent with the acknowledgement as an ephemeral
+// response instead. Responses that already set a response type, such as argument errors,
+// are left as they are.
+func (c *Connector) commandResponse(ctx context.Context, cmd slack.SlashCommand, response interface{}) interface{} {
+	fields, ok := response.(map[string]interface{})
+	if !ok {
+		return response
+	}
+	if _, set := fields["response_type"]; set {
+		return fields
+	}
+
+	if !c.ephemeralCommands {
+		fields["response_type"] = slack.ResponseTypeInChannel
+		return fields
+	}
+
+	text, _ := fields["text"].(string)
+	if _, err := c.client.PostEphemeralContext(ctx, cmd.ChannelID, cmd.UserID, slack.MsgOptionText(text, false)); err != nil {
+		c.logger.Warn("Failed to post ephemeral command reply, replying with the acknowledgement",
+			logger.StringField("command", cmd.Command),
+			logger.StringField("channel", cmd.ChannelID),
+			logger.ErrorField(err))
+		fields["response_type"] = slack.ResponseTypeEphemeral
+		return fields
+	}
+	return nil
+}
+
+// postError tells userID that their message couldn't be processed, in a thread when
+// threadTS is set. The notice carries the turn's correlation ID, and is only visible to the
+// user when error notices are ephemeral.
+func (c *Connector) postError(ctx context.Context, channel, threadTS, userID, correlationID string) error {
+	options := []slack.MsgOption{slack.MsgOptionText(c.errorReply.Render(correlationID), false)}
+	if threadTS != "" {
+		options = append(options, slack.MsgOptionTS(threadTS))
+	}
+
+	if err := c.throttle.Wait(ctx, channel); err != nil {
+		return err
+	}
+	if c.ephemeralErrors {
+		_, err := c.client.PostEphemeralContext(ctx, channel, userID, options...)
+		return err
+	}
+	_, _, err := c.client.PostMessageContext(ctx, channel, options...)
+	return err
+}
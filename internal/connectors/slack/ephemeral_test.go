@@ -0,0 +1,118 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+)
+
+// newEphemeralTestConnector returns a Connector backed by a fake Slack API that records the
+// calls it receives as "method channel user thread_ts text". Calls fail when ok is false.
+func newEphemeralTestConnector(t *testing.T, ok bool) (*Connector, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		mu.Lock()
+		calls = append(calls, r.URL.Path+" "+r.Form.Get("channel")+" "+r.Form.Get("user")+" "+r.Form.Get("thread_ts")+" "+r.Form.Get("text"))
+		mu.Unlock()
+		if !ok {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"C1","ts":"1700000000.000900","message_ts":"1700000000.000900"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	errorReply, err := executor.NewErrorMessage("Something went wrong ({{.CorrelationID}})")
+	if err != nil {
+		t.Fatalf("NewErrorMessage() error = %v", err)
+	}
+	c := &Connector{
+		client:     slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/")),
+		logger:     logger.NewLogger(logger.Config{Level: logger.DebugLevel, Output: io.Discard}),
+		errorReply: errorReply,
+	}
+	return c, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), calls...)
+	}
+}
+
+var helpSlashCommand = slack.SlashCommand{Command: "/help", ChannelID: "C1", UserID: "U1"}
+
+func TestCommandResponse_PostsEphemerallyToInvokingUser(t *testing.T) {
+	c, calls := newEphemeralTestConnector(t, true)
+	c.ephemeralCommands = true
+
+	resp := c.commandResponse(context.Background(), helpSlashCommand, map[string]interface{}{"text": "Hello"})
+	if resp != nil {
+		t.Errorf("commandResponse() = %v, want nil so the command is acknowledged without a payload", resp)
+	}
+	if want := []string{"/chat.postEphemeral C1 U1  Hello"}; !reflect.DeepEqual(calls(), want) {
+		t.Errorf("calls = %q, want %q", calls(), want)
+	}
+}
+
+func TestCommandResponse_FallsBackToEphemeralAcknowledgement(t *testing.T) {
+	c, _ := newEphemeralTestConnector(t, false)
+	c.ephemeralCommands = true
+
+	resp := c.commandResponse(context.Background(), helpSlashCommand, map[string]interface{}{"text": "Hello"})
+	want := map[string]interface{}{"text": "Hello", "response_type": slack.ResponseTypeEphemeral}
+	if !reflect.DeepEqual(resp, want) {
+		t.Errorf("commandResponse() = %v, want %v", resp, want)
+	}
+}
+
+func TestCommandResponse_InChannelWhenNotEphemeral(t *testing.T) {
+	c, calls := newEphemeralTestConnector(t, true)
+
+	resp := c.commandResponse(context.Background(), helpSlashCommand, map[string]interface{}{"text": "Hello"})
+	want := map[string]interface{}{"text": "Hello", "response_type": slack.ResponseTypeInChannel}
+	if !reflect.DeepEqual(resp, want) {
+		t.Errorf("commandResponse() = %v, want %v", resp, want)
+	}
+
+	// Argument errors stay ephemeral either way
+	usage := map[string]interface{}{"text": "Usage: /help", "response_type": slack.ResponseTypeEphemeral}
+	if resp := c.commandResponse(context.Background(), helpSlashCommand, usage); !reflect.DeepEqual(resp, usage) {
+		t.Errorf("commandResponse() = %v, want %v", resp, usage)
+	}
+	if len(calls()) != 0 {
+		t.Errorf("calls = %q, want none", calls())
+	}
+}
+
+func TestPostError(t *testing.T) {
+	c, calls := newEphemeralTestConnector(t, true)
+	ctx := context.Background()
+
+	if err := c.postError(ctx, "C1", "1700000000.000100", "U1", "abc123"); err != nil {
+		t.Fatalf("postError() error = %v", err)
+	}
+	c.ephemeralErrors = true
+	if err := c.postError(ctx, "C1", "1700000000.000100", "U1", "abc123"); err != nil {
+		t.Fatalf("postError() error = %v", err)
+	}
+
+	want := []string{
+		"/chat.postMessage C1  1700000000.000100 Something went wrong (abc123)",
+		"/chat.postEphemeral C1 U1 1700000000.000100 Something went wrong (abc123)",
+	}
+	if !reflect.DeepEqual(calls(), want) {
+		t.Errorf("calls = %q, want %q", calls(), want)
+	}
+}
@@ -1,6 +1,7 @@
 package slack
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/slack-go/slack"
@@ -490,3 +491,98 @@ func TestExtractMessageText_RichTextBlock(t *testing.T) {
 		t.Errorf("expected %q, got %q", expected, got)
 	}
 }
+
+func TestExtractMessageText_ImageBlockOnly(t *testing.T) {
+	msg := slack.Message{}
+	msg.Blocks = slack.Blocks{
+		BlockSet: []slack.Block{
+			&slack.ImageBlock{
+				Type:     slack.MBTImage,
+				ImageURL: "https://example.com/graph.png",
+				AltText:  "CPU usage graph",
+				Title:    &slack.TextBlockObject{Type: "plain_text", Text: "CPU over 90%"},
+			},
+		},
+	}
+
+	got := extractMessageText(msg)
+	want := "[Image: CPU over 90%: CPU usage graph]"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestExtractMessageText_ImageBlockAltTextOnly(t *testing.T) {
+	msg := slack.Message{}
+	msg.Blocks = slack.Blocks{
+		BlockSet: []slack.Block{
+			&slack.ImageBlock{Type: slack.MBTImage, AltText: "error rate dashboard"},
+		},
+	}
+
+	got := extractMessageText(msg)
+	if got != "[Image: error rate dashboard]" {
+		t.Errorf("expected %q, got %q", "[Image: error rate dashboard]", got)
+	}
+}
+
+func TestExtractMessageText_ContextBlockOnly(t *testing.T) {
+	msg := slack.Message{}
+	msg.Blocks = slack.Blocks{
+		BlockSet: []slack.Block{
+			slack.NewContextBlock("",
+				&slack.ImageBlockElement{Type: slack.METImage, ImageURL: "https://example.com/icon.png", AltText: "Grafana"},
+				&slack.TextBlockObject{Type: "mrkdwn", Text: "*Firing* | prod-cluster"},
+			),
+		},
+	}
+
+	got := extractMessageText(msg)
+	want := "[Image: Grafana] *Firing* | prod-cluster"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestExtractMessageText_ImageAndContextBlocksFromJSON(t *testing.T) {
+	var msg slack.Message
+	payload := `{"type":"message","blocks":[` +
+		`{"type":"image","image_url":"https://example.com/a.png","alt_text":"latency spike"},` +
+		`{"type":"context","elements":[{"type":"mrkdwn","text":"Alert fired at 10:00"}]}]}`
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		t.Fatalf("failed to unmarshal message: %v", err)
+	}
+
+	got := extractMessageText(msg)
+	want := "[Image: latency spike]\nAlert fired at 10:00"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestExtractRichTextBlock_MentionsAndEmoji(t *testing.T) {
+	block := &slack.RichTextBlock{
+		Type: slack.MBTRichText,
+		Elements: []slack.RichTextElement{
+			&slack.RichTextSection{
+				Type: slack.RTESection,
+				Elements: []slack.RichTextSectionElement{
+					&slack.RichTextSectionUserElement{Type: slack.RTSEUser, UserID: "U123"},
+					&slack.RichTextSectionTextElement{Type: slack.RTSEText, Text: " see "},
+					&slack.RichTextSectionChannelElement{Type: slack.RTSEChannel, ChannelID: "C456"},
+					&slack.RichTextSectionTextElement{Type: slack.RTSEText, Text: " "},
+					&slack.RichTextSectionEmojiElement{Type: slack.RTSEEmoji, Name: "thumbsup", Unicode: "1f44d"},
+					&slack.RichTextSectionEmojiElement{Type: slack.RTSEEmoji, Name: "custom_party"},
+					&slack.RichTextSectionTextElement{Type: slack.RTSEText, Text: " "},
+					&slack.RichTextSectionBroadcastElement{Type: slack.RTSEBroadcast, Range: "here"},
+				},
+			},
+		},
+	}
+
+	parts := extractRichTextBlock(block)
+	want := "<@U123> see <#C456> 👍:custom_party: @here"
+	if len(parts) != 1 || parts[0] != want {
+		t.Errorf("expected [%q], got %v", want, parts)
+	}
+}
@@ -0,0 +1,73 @@
+package slack
+
+import (
+	"context"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/analytics"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+)
+
+// Action IDs of the feedback buttons; each button's value is the reply's session ID
+const (
+	feedbackBlockID    = "feedback"
+	feedbackUpAction   = "feedback_up"
+	feedbackDownAction = "feedback_down"
+)
+
+// feedbackAttachment returns the 👍/👎 buttons shown under a reply in the given session.
+// They're sent as an attachment so the reply keeps its plain text rendering.
+func feedbackAttachment(sessionID string) slack.Attachment {
+	return slack.Attachment{
+		Blocks: slack.Blocks{BlockSet: []slack.Block{
+			slack.NewActionBlock(feedbackBlockID,
+				slack.NewButtonBlockElement(feedbackUpAction, sessionID, slack.NewTextBlockObject(slack.PlainTextType, "👍", true, false)),
+				slack.NewButtonBlockElement(feedbackDownAction, sessionID, slack.NewTextBlockObject(slack.PlainTextType, "👎", true, false)),
+			),
+		}},
+	}
+}
+
+// handleInteraction processes Block Kit interactions, i.e. presses of a reply's feedback buttons
+func (c *Connector) handleInteraction(ctx context.Context, callback slack.InteractionCallback) {
+	if callback.Type != slack.InteractionTypeBlockActions {
+		return
+	}
+
+	for _, action := range callback.ActionCallback.BlockActions {
+		var rating string
+		switch action.ActionID {
+		case feedbackUpAction:
+			rating = analytics.RatingPositive
+		case feedbackDownAction:
+			rating = analytics.RatingNegative
+		default:
+			continue
+		}
+
+		channel := callback.Container.ChannelID
+		if channel == "" {
+			channel = callback.Channel.ID
+		}
+		err := c.analytics.Record(analytics.Event{
+			Type:       analytics.EventFeedback,
+			Platform:   "slack",
+			UserID:     callback.User.ID,
+			SessionID:  action.Value,
+			MessageRef: channel + ":" + callback.Container.MessageTs,
+			Rating:     rating,
+		})
+		if err != nil {
+			c.logger.Error("Failed to record feedback", logger.ErrorField(err))
+		}
+
+		// Let the user know their rating was received, visible only to them
+		options := []slack.MsgOption{slack.MsgOptionText("Thanks for your feedback!", false)}
+		if callback.Container.ThreadTs != "" {
+			options = append(options, slack.MsgOptionTS(callback.Container.ThreadTs))
+		}
+		if _, err := c.client.PostEphemeralContext(ctx, channel, callback.User.ID, options...); err != nil {
+			c.logger.Warn("Failed to acknowledge feedback", logger.ErrorField(err))
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/analytics"
+	"github.com/slack-go/slack"
+)
+
+func TestHandleInteraction_RecordsFeedback(t *testing.T) {
+	c, _ := newMentionTestConnector(t)
+	var buf bytes.Buffer
+	c.analytics = analytics.New(&buf)
+
+	callback := slack.InteractionCallback{
+		Type:      slack.InteractionTypeBlockActions,
+		User:      slack.User{ID: "U123"},
+		Container: slack.Container{ChannelID: "C456", MessageTs: "1700000000.000200", ThreadTs: "1700000000.000100"},
+		ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{
+			{ActionID: feedbackDownAction, BlockID: feedbackBlockID, Value: "session-1"},
+		}},
+	}
+	c.handleInteraction(context.Background(), callback)
+
+	var event analytics.Event
+	if err := json.NewDecoder(&buf).Decode(&event); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if event.Type != analytics.EventFeedback || event.Platform != "slack" || event.UserID != "U123" ||
+		event.SessionID != "session-1" || event.MessageRef != "C456:1700000000.000200" || event.Rating != analytics.RatingNegative {
+		t.Errorf("event = %+v, want negative feedback from U123 on C456:1700000000.000200 in session-1", event)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("recorded more than one event: %s", buf.String())
+	}
+}
+
+func TestHandleInteraction_IgnoresOtherActions(t *testing.T) {
+	c, _ := newMentionTestConnector(t)
+	var buf bytes.Buffer
+	c.analytics = analytics.New(&buf)
+
+	c.handleInteraction(context.Background(), slack.InteractionCallback{
+		Type:           slack.InteractionTypeBlockActions,
+		ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{{ActionID: "something_else"}}},
+	})
+
+	if buf.Len() != 0 {
+		t.Errorf("recorded %s, want no event for an unrelated action", buf.String())
+	}
+}
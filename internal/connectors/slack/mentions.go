@@ -0,0 +1,74 @@
+package slack
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+)
+
+var (
+	// userMentionPattern matches <@U123> and <@U123|label> user mentions
+	userMentionPattern = regexp.MustCompile(`<@([UW][A-Z0-9]+)(?:\|([^>]*))?>`)
+	// channelMentionPattern matches <#C123> and <#C123|label> channel mentions
+	channelMentionPattern = regexp.MustCompile(`<#([CGD][A-Z0-9]+)(?:\|([^>]*))?>`)
+)
+
+// resolveMentions replaces user and channel mention tokens with readable @name and #name
+// references. Tokens that can't be resolved are left unchanged.
+func (c *Connector) resolveMentions(ctx context.Context, text string) string {
+	if !strings.Contains(text, "<@") && !strings.Contains(text, "<#") {
+		return text
+	}
+
+	text = userMentionPattern.ReplaceAllStringFunc(text, func(token string) string {
+		match := userMentionPattern.FindStringSubmatch(token)
+		if match[2] != "" {
+			return "@" + match[2]
+		}
+		name := c.resolveUserName(ctx, match[1], "")
+		if strings.HasPrefix(name, "<@") {
+			return token
+		}
+		return "@" + name
+	})
+
+	return channelMentionPattern.ReplaceAllStringFunc(text, func(token string) string {
+		match := channelMentionPattern.FindStringSubmatch(token)
+		if match[2] != "" {
+			return "#" + match[2]
+		}
+		if name := c.resolveChannelName(ctx, match[1]); name != "" {
+			return "#" + name
+		}
+		return token
+	})
+}
+
+// resolveChannelName resolves a Slack channel ID to its name, returning "" if it can't be resolved.
+func (c *Connector) resolveChannelName(ctx context.Context, channelID string) string {
+	// Check cache
+	c.cacheMu.RLock()
+	if name, ok := c.channelNameCache[channelID]; ok {
+		c.cacheMu.RUnlock()
+		return name
+	}
+	c.cacheMu.RUnlock()
+
+	// Fetch from API
+	channel, err := c.client.GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{ChannelID: channelID})
+	if err != nil {
+		c.logger.Debug("Failed to resolve channel name",
+			logger.StringField("channel", channelID),
+			logger.ErrorField(err))
+		return ""
+	}
+
+	c.cacheMu.Lock()
+	c.channelNameCache[channelID] = channel.Name
+	c.cacheMu.Unlock()
+
+	return channel.Name
+}
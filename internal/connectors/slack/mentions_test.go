@@ -0,0 +1,112 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/cache"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+)
+
+// newMentionTestConnector returns a Connector backed by a fake Slack API that knows
+// user U123 (alice) and channel C456 (incidents). It counts conversations.info calls.
+func newMentionTestConnector(t *testing.T) (*Connector, *int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	channelLookups := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth.test":
+			_, _ = w.Write([]byte(`{"ok":true,"user_id":"UBOT","bot_id":"BBOT"}`))
+		case "/users.info":
+			if r.Form.Get("user") == "U123" {
+				_, _ = w.Write([]byte(`{"ok":true,"user":{"id":"U123","name":"alice","profile":{"display_name":"Alice"}}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok":false,"error":"user_not_found"}`))
+		case "/conversations.replies":
+			_, _ = w.Write([]byte(`{"ok":true,"has_more":false,"messages":[` +
+				`{"type":"message","user":"U123","ts":"1700000000.000100","text":"<@UBOT> can <@U123> look at this? cc <#C456>"},` +
+				`{"type":"message","user":"U123","ts":"1700000000.000300","text":"current message"}]}`))
+		case "/conversations.info":
+			mu.Lock()
+			channelLookups++
+			mu.Unlock()
+			if r.Form.Get("channel") == "C456" {
+				_, _ = w.Write([]byte(`{"ok":true,"channel":{"id":"C456","name":"incidents"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":false,"error":"unknown_method"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return &Connector{
+		client:           slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/")),
+		logger:           logger.NewLogger(logger.Config{Level: logger.DebugLevel, Output: io.Discard}),
+		userNameCache:    cache.New[string, string](DefaultUserCacheSize, DefaultUserCacheTTL),
+		channelNameCache: make(map[string]string),
+	}, &channelLookups
+}
+
+func TestResolveMentions(t *testing.T) {
+	c, _ := newMentionTestConnector(t)
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "no mentions", text: "hello world", want: "hello world"},
+		{name: "user mention", text: "<@U123> can you check?", want: "@Alice can you check?"},
+		{name: "channel mention", text: "see <#C456> for details", want: "see #incidents for details"},
+		{name: "labelled channel mention", text: "see <#C999|general>", want: "see #general"},
+		{name: "both", text: "<@U123> posted in <#C456>", want: "@Alice posted in #incidents"},
+		{name: "unknown user kept", text: "ping <@U999>", want: "ping <@U999>"},
+		{name: "unknown channel kept", text: "in <#C999>", want: "in <#C999>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.resolveMentions(context.Background(), tt.text)
+			if got != tt.want {
+				t.Errorf("resolveMentions(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveChannelName_Cached(t *testing.T) {
+	c, lookups := newMentionTestConnector(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if got := c.resolveChannelName(ctx, "C456"); got != "incidents" {
+			t.Fatalf("resolveChannelName() = %q, want %q", got, "incidents")
+		}
+	}
+	if *lookups != 1 {
+		t.Errorf("expected 1 conversations.info call, got %d", *lookups)
+	}
+}
+
+func TestGetThreadContext_ResolvesMentions(t *testing.T) {
+	c, _ := newMentionTestConnector(t)
+
+	got := c.getThreadContext(context.Background(), "", "C456", "1700000000.000100", "1700000000.000300")
+	want := "Alice: can @Alice look at this? cc #incidents"
+	if !strings.Contains(got, want) {
+		t.Errorf("thread context %q does not contain %q", got, want)
+	}
+}
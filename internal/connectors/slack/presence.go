@@ -0,0 +1,41 @@
+package slack
+
+import (
+	"context"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// Presence values accepted by users.setPresence. "auto" lets Slack show the bot as active
+// while it's connected.
+const (
+	presenceActive = "auto"
+	presenceAway   = "away"
+)
+
+// presenceTimeout bounds the presence update made on shutdown, after the connector's context
+// has been cancelled
+const presenceTimeout = 5 * time.Second
+
+// setPresence sets the bot's presence when presence updates are enabled. Failures, most
+// likely a missing users:write scope, are logged and otherwise ignored.
+func (c *Connector) setPresence(ctx context.Context, presence string) {
+	if !c.presence {
+		return
+	}
+	if err := c.client.SetUserPresenceContext(ctx, presence); err != nil {
+		c.logger.Warn("Failed to set Slack presence (needs the users:write scope)",
+			logger.StringField("presence", presence),
+			logger.ErrorField(err))
+		return
+	}
+	c.logger.Debug("Set Slack presence", logger.StringField("presence", presence))
+}
+
+// setAway marks the bot away as the connector shuts down
+func (c *Connector) setAway() {
+	ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+	defer cancel()
+	c.setPresence(ctx, presenceAway)
+}
@@ -0,0 +1,94 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+)
+
+// newPresenceTestConnector returns a Connector backed by a fake Slack API that records the
+// presence it's set to. Socket Mode connections are refused.
+func newPresenceTestConnector(t *testing.T, enabled bool) (*Connector, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var presences []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.HasSuffix(r.URL.Path, "/users.setPresence") {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"invalid_auth"}`))
+			return
+		}
+		mu.Lock()
+		presences = append(presences, r.Form.Get("presence"))
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+	client := slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/"), slack.OptionAppLevelToken("xapp-test"))
+	c := &Connector{
+		client:     client,
+		socketMode: socketmode.New(client),
+		logger:     log,
+		reconnect:  newReconnector(ReconnectPolicy{InitialBackoff: time.Hour}, log),
+		presence:   enabled,
+	}
+	return c, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), presences...)
+	}
+}
+
+func TestPresence_ActiveOnConnectAwayOnShutdown(t *testing.T) {
+	c, presences := newPresenceTestConnector(t, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- c.Start(ctx) }()
+	c.socketMode.Events <- socketmode.Event{Type: socketmode.EventTypeConnected}
+
+	for deadline := time.Now().Add(5 * time.Second); len(presences()) == 0; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("presence wasn't set on connect")
+		}
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start() didn't return after shutdown")
+	}
+
+	if want := []string{presenceActive, presenceAway}; !reflect.DeepEqual(presences(), want) {
+		t.Errorf("presences = %q, want %q", presences(), want)
+	}
+}
+
+func TestPresence_Disabled(t *testing.T) {
+	c, presences := newPresenceTestConnector(t, false)
+
+	c.setPresence(context.Background(), presenceActive)
+	c.setAway()
+
+	if len(presences()) != 0 {
+		t.Errorf("presences = %q, want none when presence updates are off", presences())
+	}
+}
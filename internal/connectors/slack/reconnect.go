@@ -0,0 +1,170 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// Reconnect defaults, used when the policy leaves a field unset
+const (
+	DefaultReconnectInitialBackoff = time.Second
+	DefaultReconnectMaxBackoff     = 2 * time.Minute
+	DefaultReconnectMaxRetries     = 10
+)
+
+// ReconnectPolicy controls how the connector reconnects when its Socket Mode connection drops.
+// Delays double from InitialBackoff up to MaxBackoff; after MaxRetries consecutive failed
+// attempts the connector gives up and Start returns an error, so the server shuts down and
+// the orchestrator can restart it.
+type ReconnectPolicy struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	MaxRetries     int
+}
+
+// withDefaults fills in unset fields with the defaults
+func (p ReconnectPolicy) withDefaults() ReconnectPolicy {
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = DefaultReconnectInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultReconnectMaxBackoff
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		p.MaxBackoff = p.InitialBackoff
+	}
+	if p.MaxRetries <= 0 {
+		p.MaxRetries = DefaultReconnectMaxRetries
+	}
+	return p
+}
+
+// Backoff returns the delay before reconnection attempt n (starting at 1)
+func (p ReconnectPolicy) Backoff(attempt int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return min(delay, p.MaxBackoff)
+}
+
+// reconnector runs the Socket Mode connection, reconnecting with backoff when it fails.
+// Failures are counted both when the connection returns and when the Slack library reports
+// a failed connection attempt; a successful connection resets the count.
+type reconnector struct {
+	policy ReconnectPolicy
+	logger logger.Logger
+	sleep  func(ctx context.Context, d time.Duration) error
+
+	mu       sync.Mutex
+	attempts int
+	gaveUp   bool
+	lastErr  error
+	cancel   context.CancelFunc
+}
+
+func newReconnector(policy ReconnectPolicy, log logger.Logger) *reconnector {
+	return &reconnector{
+		policy: policy.withDefaults(),
+		logger: log,
+		sleep:  sleepContext,
+	}
+}
+
+// run calls connect until ctx is done. It returns nil on shutdown and an error once it gives up.
+func (r *reconnector) run(ctx context.Context, connect func(context.Context) error) error {
+	for {
+		runCtx, cancel := context.WithCancel(ctx)
+		r.mu.Lock()
+		r.cancel = cancel
+		r.mu.Unlock()
+
+		err := connect(runCtx)
+		cancel()
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == nil {
+			err = fmt.Errorf("connection closed")
+		}
+
+		attempt, giveUp := r.recordFailure(err)
+		if giveUp {
+			return r.giveUpError()
+		}
+
+		delay := r.policy.Backoff(attempt)
+		r.logger.Warn("Reconnecting to Slack",
+			logger.IntField("attempt", attempt),
+			logger.IntField("max_retries", r.policy.MaxRetries),
+			logger.DurationField("backoff", delay),
+			logger.ErrorField(err))
+		if err := r.sleep(ctx, delay); err != nil {
+			return nil
+		}
+	}
+}
+
+// connected resets the failure count after a successful connection
+func (r *reconnector) connected() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.attempts > 0 {
+		r.logger.Info("Reconnected to Slack", logger.IntField("attempts", r.attempts))
+	}
+	r.attempts = 0
+}
+
+// recordFailure counts a failed connection attempt and reports whether to give up.
+// Giving up cancels the running connection so run returns.
+func (r *reconnector) recordFailure(err error) (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.gaveUp {
+		return r.attempts, true
+	}
+
+	r.attempts++
+	r.lastErr = err
+	if r.attempts <= r.policy.MaxRetries {
+		return r.attempts, false
+	}
+
+	r.gaveUp = true
+	r.logger.Error("Giving up reconnecting to Slack",
+		logger.IntField("retries", r.policy.MaxRetries),
+		logger.ErrorField(err))
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return r.attempts, true
+}
+
+func (r *reconnector) giveUpError() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return fmt.Errorf("gave up reconnecting to Slack after %d attempts: %w", r.policy.MaxRetries, r.lastErr)
+}
+
+// sleepContext waits for d, returning early with the context's error if it's done first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,113 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// newTestReconnector returns a reconnector that records its backoff delays instead of sleeping
+func newTestReconnector(policy ReconnectPolicy) (*reconnector, *[]time.Duration) {
+	r := newReconnector(policy, logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard}))
+	var delays []time.Duration
+	r.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return r, &delays
+}
+
+func TestReconnectPolicy_Backoff(t *testing.T) {
+	policy := ReconnectPolicy{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	var got []time.Duration
+	for attempt := 1; attempt <= 6; attempt++ {
+		got = append(got, policy.Backoff(attempt))
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Backoff() schedule = %v, want %v", got, want)
+	}
+}
+
+func TestReconnector_GivesUpAfterRepeatedDisconnects(t *testing.T) {
+	r, delays := newTestReconnector(ReconnectPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, MaxRetries: 4})
+
+	dropped := errors.New("websocket closed")
+	calls := 0
+	err := r.run(context.Background(), func(context.Context) error {
+		calls++
+		return dropped
+	})
+
+	if err == nil || !errors.Is(err, dropped) {
+		t.Fatalf("run() error = %v, want a give-up error wrapping the last failure", err)
+	}
+	if !strings.Contains(err.Error(), "after 4 attempts") {
+		t.Errorf("run() error = %q, want the retry count", err)
+	}
+	if calls != 5 {
+		t.Errorf("connect called %d times, want the first attempt plus 4 retries", calls)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	if !reflect.DeepEqual(*delays, want) {
+		t.Errorf("backoff delays = %v, want %v", *delays, want)
+	}
+}
+
+func TestReconnector_SuccessfulConnectionResetsAttempts(t *testing.T) {
+	r, delays := newTestReconnector(ReconnectPolicy{InitialBackoff: time.Second, MaxBackoff: time.Minute, MaxRetries: 3})
+
+	// Every third attempt connects before dropping again, so the connector never runs out of retries
+	calls := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := r.run(ctx, func(context.Context) error {
+		calls++
+		if calls%3 == 0 {
+			r.connected()
+		}
+		if calls == 9 {
+			cancel()
+			return context.Canceled
+		}
+		return errors.New("websocket closed")
+	})
+
+	if err != nil {
+		t.Fatalf("run() error = %v, want nil on shutdown", err)
+	}
+	want := []time.Duration{
+		time.Second, 2 * time.Second,
+		time.Second, 2 * time.Second, 4 * time.Second,
+		time.Second, 2 * time.Second, 4 * time.Second,
+	}
+	if !reflect.DeepEqual(*delays, want) {
+		t.Errorf("backoff delays = %v, want %v", *delays, want)
+	}
+}
+
+func TestReconnector_GivesUpOnFailedConnectionAttempts(t *testing.T) {
+	r, _ := newTestReconnector(ReconnectPolicy{MaxRetries: 3})
+
+	// The Slack library retries failed connections itself and reports each failure as an event
+	err := r.run(context.Background(), func(ctx context.Context) error {
+		for i := 0; i < 10; i++ {
+			if _, giveUp := r.recordFailure(errors.New("dial failed")); giveUp {
+				break
+			}
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "dial failed") {
+		t.Errorf("run() error = %v, want a give-up error wrapping the connection failure", err)
+	}
+}
@@ -0,0 +1,37 @@
+package slack
+
+import (
+	"context"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/sessionscope"
+)
+
+// Scope keys include the Slack team (workspace) ID so the same user or channel ID in two
+// workspaces of an Enterprise Grid org never shares a session. Installs from before that
+// stored conversations under keys without the team; a scope keeps using its legacy key
+// while it has a session there and none under the new key, so history, language overrides
+// and documents carry over.
+
+// userScope returns the scope key for a user's direct messages and personal settings
+func (c *Connector) userScope(ctx context.Context, teamID, userID string) string {
+	return c.resolveScope(ctx, sessionscope.User(teamID, userID), sessionscope.User("", userID))
+}
+
+// threadScope returns the scope key for a channel thread, shared by everyone in the thread
+func (c *Connector) threadScope(ctx context.Context, teamID, channelID, threadTS string) string {
+	return c.resolveScope(ctx, sessionscope.Thread(teamID, channelID, threadTS), sessionscope.Thread("", channelID, threadTS))
+}
+
+// resolveScope returns key, or legacyKey if the scope only has sessions under that
+func (c *Connector) resolveScope(ctx context.Context, key, legacyKey string) string {
+	if key == legacyKey {
+		return key
+	}
+	if sessionID, err := c.sessionMgr.GetLatestSession(ctx, "slack", key); err != nil || sessionID != "" {
+		return key
+	}
+	if sessionID, err := c.sessionMgr.GetLatestSession(ctx, "slack", legacyKey); err == nil && sessionID != "" {
+		return legacyKey
+	}
+	return key
+}
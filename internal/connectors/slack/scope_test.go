@@ -0,0 +1,134 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// sessionIDRecorder records the sessions a real session manager returns for processed
+// messages, then fails the lookup so processing stops before the executor is reached.
+type sessionIDRecorder struct {
+	session_manager.Manager
+	sessionIDs []string
+}
+
+func (m *sessionIDRecorder) GetOrCreateSession(ctx context.Context, connector, userID, channelID string) (string, error) {
+	sessionID, err := m.Manager.GetOrCreateSession(ctx, connector, userID, channelID)
+	if err != nil {
+		return "", err
+	}
+	m.sessionIDs = append(m.sessionIDs, sessionID)
+	return "", errors.New("session lookup stopped by test")
+}
+
+func newScopeTestConnector(t *testing.T) (*Connector, *sessionIDRecorder) {
+	t.Helper()
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+	manager, err := session_manager.New(session_manager.Config{
+		MetadataFile: "metadata.json",
+		FileProvider: storage_manager.NewLocalFileProvider(t.TempDir()),
+		Logger:       log,
+	})
+	if err != nil {
+		t.Fatalf("session_manager.New() error = %v", err)
+	}
+
+	c, _ := newMentionTestConnector(t)
+	sessions := &sessionIDRecorder{Manager: manager}
+	c.sessionMgr = sessions
+	return c, sessions
+}
+
+func callbackEvent(teamID string, inner any) slackevents.EventsAPIEvent {
+	return slackevents.EventsAPIEvent{
+		Type:       slackevents.CallbackEvent,
+		TeamID:     teamID,
+		InnerEvent: slackevents.EventsAPIInnerEvent{Data: inner},
+	}
+}
+
+func TestHandleEvent_DMSessionsIsolatedByTeam(t *testing.T) {
+	c, sessions := newScopeTestConnector(t)
+	ctx := context.Background()
+
+	// The same user and DM channel IDs arrive from two workspaces of a Grid org
+	for _, teamID := range []string{"T1", "T2", "T1"} {
+		_ = c.handleEvent(ctx, callbackEvent(teamID, &slackevents.MessageEvent{
+			User: "U123", Channel: "D456", TimeStamp: "1700000000.000100", Text: "hello",
+		}))
+	}
+
+	if len(sessions.sessionIDs) != 3 {
+		t.Fatalf("got %d session lookups, want 3", len(sessions.sessionIDs))
+	}
+	if sessions.sessionIDs[0] == sessions.sessionIDs[1] {
+		t.Errorf("teams T1 and T2 share session %s, want separate sessions", sessions.sessionIDs[0])
+	}
+	if sessions.sessionIDs[0] != sessions.sessionIDs[2] {
+		t.Errorf("team T1 got sessions %s and %s, want the same session", sessions.sessionIDs[0], sessions.sessionIDs[2])
+	}
+}
+
+func TestHandleEvent_ThreadSessionsIsolatedByTeam(t *testing.T) {
+	c, sessions := newScopeTestConnector(t)
+	ctx := context.Background()
+
+	for _, teamID := range []string{"T1", "T2"} {
+		_ = c.handleEvent(ctx, callbackEvent(teamID, &slackevents.AppMentionEvent{
+			User: "U123", Channel: "C456", TimeStamp: "1700000000.000100", Text: "<@UBOT> hello",
+		}))
+	}
+
+	if len(sessions.sessionIDs) != 2 {
+		t.Fatalf("got %d session lookups, want 2", len(sessions.sessionIDs))
+	}
+	if sessions.sessionIDs[0] == sessions.sessionIDs[1] {
+		t.Errorf("teams T1 and T2 share thread session %s, want separate sessions", sessions.sessionIDs[0])
+	}
+}
+
+func TestHandleEvent_LegacyScopesKeepTheirSessions(t *testing.T) {
+	c, sessions := newScopeTestConnector(t)
+	ctx := context.Background()
+
+	// Sessions stored before scope keys included the team
+	legacyDM, err := sessions.Manager.CreateNewSession(ctx, "slack", "U123", "D456")
+	if err != nil {
+		t.Fatalf("CreateNewSession() error = %v", err)
+	}
+	legacyThread, err := sessions.Manager.CreateNewSession(ctx, "slack", "thread:C456:1700000000.000100", "C456")
+	if err != nil {
+		t.Fatalf("CreateNewSession() error = %v", err)
+	}
+
+	_ = c.handleEvent(ctx, callbackEvent("T1", &slackevents.MessageEvent{
+		User: "U123", Channel: "D456", TimeStamp: "1700000000.000200", Text: "hello again",
+	}))
+	_ = c.handleEvent(ctx, callbackEvent("T1", &slackevents.AppMentionEvent{
+		User: "U123", Channel: "C456", TimeStamp: "1700000000.000300", ThreadTimeStamp: "1700000000.000100", Text: "<@UBOT> and now?",
+	}))
+	// A user without a legacy session gets a team-scoped one
+	_ = c.handleEvent(ctx, callbackEvent("T1", &slackevents.MessageEvent{
+		User: "U999", Channel: "D999", TimeStamp: "1700000000.000400", Text: "hi",
+	}))
+
+	if len(sessions.sessionIDs) != 3 {
+		t.Fatalf("got %d session lookups, want 3", len(sessions.sessionIDs))
+	}
+	if sessions.sessionIDs[0] != legacyDM {
+		t.Errorf("DM session = %s, want the legacy session %s", sessions.sessionIDs[0], legacyDM)
+	}
+	if sessions.sessionIDs[1] != legacyThread {
+		t.Errorf("thread session = %s, want the legacy session %s", sessions.sessionIDs[1], legacyThread)
+	}
+	if latest, _ := sessions.Manager.GetLatestSession(ctx, "slack", "T1:U999"); latest != sessions.sessionIDs[2] {
+		t.Errorf("new user's session isn't under the team-scoped key")
+	}
+}
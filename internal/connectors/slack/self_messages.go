@@ -0,0 +1,52 @@
+package slack
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+var (
+	// slackLinkPattern matches <url|label> links so they can be collapsed to their label
+	slackLinkPattern = regexp.MustCompile(`<(https?://[^|>]+)\|([^>]+)>`)
+	// bareLinkPattern matches <url> links so the angle brackets can be dropped
+	bareLinkPattern = regexp.MustCompile(`<(https?://[^|>]+)>`)
+	// emphasisPattern matches *bold*, _italic_ and ~strike~ markers around text
+	emphasisPattern = regexp.MustCompile(`(^|[\s(])([*_~])([^*_~\n]+)([*_~])`)
+	// blankLinesPattern matches runs of blank lines
+	blankLinesPattern = regexp.MustCompile(`\n{2,}`)
+)
+
+// isOwnMessage reports whether a message was posted by this bot
+func (c *Connector) isOwnMessage(msg slack.Message) bool {
+	identity := c.ensureBotIdentity()
+	return (msg.BotID != "" && msg.BotID == identity.BotID) || (msg.User != "" && msg.User == identity.UserID)
+}
+
+// normalizeOwnMessage cleans one of the bot's earlier replies for use as thread context:
+// configured prefixes and self-mentions are removed and Slack formatting is collapsed
+// so the model sees its earlier turn as plain text.
+func (c *Connector) normalizeOwnMessage(text string) string {
+	text = strings.TrimSpace(text)
+	for _, prefix := range c.selfPrefixes {
+		if prefix != "" && strings.HasPrefix(text, prefix) {
+			text = strings.TrimSpace(strings.TrimPrefix(text, prefix))
+		}
+	}
+
+	text = c.removeBotMention(text)
+
+	text = slackLinkPattern.ReplaceAllString(text, "$2 ($1)")
+	text = bareLinkPattern.ReplaceAllString(text, "$1")
+	text = emphasisPattern.ReplaceAllString(text, "$1$3")
+	text = strings.ReplaceAll(text, "```", "")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	text = blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n")
+
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,83 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/cache"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+)
+
+func TestNormalizeOwnMessage(t *testing.T) {
+	c := &Connector{selfPrefixes: []string{"[assistant]"}}
+	c.cacheBotIdentity(executor.BotIdentity{UserID: "UBOT"})
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "plain text unchanged", text: "Deploys run at 10am.", want: "Deploys run at 10am."},
+		{name: "configured prefix stripped", text: "[assistant] Deploys run at 10am.", want: "Deploys run at 10am."},
+		{name: "self mention stripped", text: "<@UBOT> here's the summary", want: "here's the summary"},
+		{name: "labelled self mention stripped", text: "Ask <@UBOT|helper> again", want: "Ask again"},
+		{name: "other mentions kept", text: "<@U_OTHER> and <@UBOT> agreed", want: "<@U_OTHER> and agreed"},
+		{name: "emphasis collapsed", text: "This is *important* and _urgent_ but ~wrong~", want: "This is important and urgent but wrong"},
+		{name: "links collapsed", text: "See <https://go.dev|the docs> or <https://example.com>", want: "See the docs (https://go.dev) or https://example.com"},
+		{name: "code fences and blank lines collapsed", text: "Run:\n\n```\nmake   test\n```\n\n\nDone", want: "Run:\nmake test\nDone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.normalizeOwnMessage(tt.text); got != tt.want {
+				t.Errorf("normalizeOwnMessage(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetThreadContext_LabelsAndCleansOwnMessages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth.test":
+			_, _ = w.Write([]byte(`{"ok":true,"user_id":"UBOT","bot_id":"BBOT"}`))
+		case "/users.info":
+			_, _ = w.Write([]byte(`{"ok":true,"user":{"id":"U123","name":"alice","profile":{"display_name":"Alice"}}}`))
+		case "/conversations.replies":
+			_, _ = w.Write([]byte(`{"ok":true,"has_more":false,"messages":[` +
+				`{"type":"message","user":"U123","ts":"1700000000.000100","text":"<@UBOT> when do deploys run?"},` +
+				`{"type":"message","user":"UBOT","bot_id":"BBOT","ts":"1700000000.000200","text":"[assistant] Deploys run at *10am* <@UBOT>"},` +
+				`{"type":"message","user":"U123","ts":"1700000000.000300","text":"thanks"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":false,"error":"unknown_method"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c := &Connector{
+		client:           slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/")),
+		logger:           logger.NewLogger(logger.Config{Level: logger.DebugLevel, Output: io.Discard}),
+		selfPrefixes:     []string{"[assistant]"},
+		userNameCache:    cache.New[string, string](DefaultUserCacheSize, DefaultUserCacheTTL),
+		channelNameCache: make(map[string]string),
+	}
+
+	got := c.getThreadContext(context.Background(), "", "C1", "1700000000.000100", "1700000000.000300")
+
+	if !strings.Contains(got, "You (assistant): Deploys run at 10am\n") {
+		t.Errorf("thread context should contain the cleaned assistant turn, got %q", got)
+	}
+	if !strings.Contains(got, "Alice: when do deploys run?") {
+		t.Errorf("thread context should contain the user's turn, got %q", got)
+	}
+	if strings.Contains(got, "[assistant]") || strings.Contains(got, "<@UBOT>") || strings.Contains(got, "*10am*") {
+		t.Errorf("thread context should not contain prefixes, self-mentions or formatting, got %q", got)
+	}
+}
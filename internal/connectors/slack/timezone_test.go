@@ -0,0 +1,126 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/cache"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone data for %s unavailable: %v", name, err)
+	}
+	return loc
+}
+
+func TestFormatSlackTimestamp(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+
+	tests := []struct {
+		name string
+		ts   string
+		loc  *time.Location
+		want string
+	}{
+		{name: "nil is UTC", ts: "1700000000.000100", want: "[2023-11-14 22:13 UTC]"},
+		{name: "UTC", ts: "1700000000.000100", loc: time.UTC, want: "[2023-11-14 22:13 UTC]"},
+		{name: "configured zone", ts: "1700000000.000100", loc: berlin, want: "[2023-11-14 23:13 CET]"},
+		{name: "invalid timestamp", ts: "not-a-ts", loc: berlin, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatSlackTimestamp(tt.ts, tt.loc); got != tt.want {
+				t.Errorf("formatSlackTimestamp(%q) = %q, want %q", tt.ts, got, tt.want)
+			}
+		})
+	}
+}
+
+// newTimezoneTestConnector returns a Connector whose fake Slack API knows user U123 in
+// America/New_York, user U456 with no timezone and user U789 with an unknown one
+func newTimezoneTestConnector(t *testing.T, defaultLoc *time.Location) *Connector {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users.info":
+			switch r.Form.Get("user") {
+			case "U123":
+				_, _ = w.Write([]byte(`{"ok":true,"user":{"id":"U123","name":"alice","tz":"America/New_York"}}`))
+			case "U456":
+				_, _ = w.Write([]byte(`{"ok":true,"user":{"id":"U456","name":"bob"}}`))
+			case "U789":
+				_, _ = w.Write([]byte(`{"ok":true,"user":{"id":"U789","name":"carol","tz":"Not/AZone"}}`))
+			default:
+				_, _ = w.Write([]byte(`{"ok":false,"error":"user_not_found"}`))
+			}
+		case "/conversations.replies":
+			_, _ = w.Write([]byte(`{"ok":true,"has_more":false,"messages":[` +
+				`{"type":"message","user":"U456","ts":"1700000000.000100","text":"earlier message"},` +
+				`{"type":"message","user":"U123","ts":"1700000000.000300","text":"current message"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":false,"error":"unknown_method"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return &Connector{
+		client:           slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/")),
+		logger:           logger.NewLogger(logger.Config{Level: logger.DebugLevel, Output: io.Discard}),
+		userNameCache:    cache.New[string, string](DefaultUserCacheSize, DefaultUserCacheTTL),
+		userTZCache:      cache.New[string, *time.Location](DefaultUserCacheSize, DefaultUserCacheTTL),
+		timezone:         defaultLoc,
+		channelNameCache: make(map[string]string),
+	}
+}
+
+func TestResolveUserTimezone(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	c := newTimezoneTestConnector(t, berlin)
+	ctx := context.Background()
+
+	tests := []struct {
+		userID string
+		want   string
+	}{
+		{userID: "U123", want: "America/New_York"},
+		{userID: "U456", want: "Europe/Berlin"},
+		{userID: "U789", want: "Europe/Berlin"},
+		{userID: "U000", want: "Europe/Berlin"},
+		{userID: "", want: "Europe/Berlin"},
+	}
+
+	for _, tt := range tests {
+		if got := c.resolveUserTimezone(ctx, tt.userID); got.String() != tt.want {
+			t.Errorf("resolveUserTimezone(%q) = %s, want %s", tt.userID, got, tt.want)
+		}
+	}
+}
+
+func TestGetThreadContext_UsesUserTimezone(t *testing.T) {
+	mustLoadLocation(t, "America/New_York")
+	c := newTimezoneTestConnector(t, nil)
+
+	got := c.getThreadContext(context.Background(), "U123", "C1", "1700000000.000100", "1700000000.000300")
+	if !strings.Contains(got, "[2023-11-14 17:13 EST] bob: earlier message") {
+		t.Errorf("thread context = %q, want the earlier message timestamped in the user's timezone", got)
+	}
+
+	got = c.getThreadContext(context.Background(), "U456", "C1", "1700000000.000100", "1700000000.000300")
+	if !strings.Contains(got, "[2023-11-14 22:13 UTC] bob: earlier message") {
+		t.Errorf("thread context = %q, want UTC when neither the user nor the config has a timezone", got)
+	}
+}
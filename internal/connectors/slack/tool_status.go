@@ -0,0 +1,80 @@
+package slack
+
+import (
+	"context"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+)
+
+// toolStatus is the status line telling users which tool the agent is using during a turn.
+// The first tool call posts it and later calls update it in place; it's deleted once the
+// turn is over. A nil toolStatus does nothing.
+type toolStatus struct {
+	connector *Connector
+	channel   string
+	threadTS  string
+	ts        string // Timestamp of the posted status message, "" until it's posted
+	text      string
+}
+
+// newToolStatus returns the status line for a turn in channel, or a thread when threadTS is
+// set. It returns nil when tool use disclosure is off.
+func (c *Connector) newToolStatus(channel, threadTS string) *toolStatus {
+	if !c.toolStatus {
+		return nil
+	}
+	return &toolStatus{connector: c, channel: channel, threadTS: threadTS}
+}
+
+// onToolCall returns the executor callback that shows each tool call, or nil when s is nil
+func (s *toolStatus) onToolCall(ctx context.Context) func(string) {
+	if s == nil {
+		return nil
+	}
+	return func(toolName string) { s.update(ctx, executor.ToolStatusText(toolName)) }
+}
+
+// update shows text as the status, posting the status message if it isn't posted yet.
+// Failures are logged and otherwise ignored; the status is only a courtesy.
+func (s *toolStatus) update(ctx context.Context, text string) {
+	if s == nil || text == s.text {
+		return
+	}
+	log := s.connector.logger.WithFields(logger.StringField("channel", s.channel))
+
+	if s.ts == "" {
+		options := []slack.MsgOption{slack.MsgOptionText("_"+text+"_", false)}
+		if s.threadTS != "" {
+			options = append(options, slack.MsgOptionTS(s.threadTS))
+		}
+		if err := s.connector.throttle.Wait(ctx, s.channel); err != nil {
+			return
+		}
+		_, ts, err := s.connector.client.PostMessageContext(ctx, s.channel, options...)
+		if err != nil {
+			log.Warn("Failed to post tool status", logger.ErrorField(err))
+			return
+		}
+		s.ts = ts
+	} else if _, _, _, err := s.connector.client.UpdateMessageContext(ctx, s.channel, s.ts, slack.MsgOptionText("_"+text+"_", false)); err != nil {
+		log.Warn("Failed to update tool status", logger.ErrorField(err))
+		return
+	}
+	s.text = text
+}
+
+// clear deletes the status message, if one was posted
+func (s *toolStatus) clear(ctx context.Context) {
+	if s == nil || s.ts == "" {
+		return
+	}
+	if _, _, err := s.connector.client.DeleteMessageContext(ctx, s.channel, s.ts); err != nil {
+		s.connector.logger.Warn("Failed to delete tool status",
+			logger.StringField("channel", s.channel),
+			logger.ErrorField(err))
+	}
+	s.ts = ""
+	s.text = ""
+}
@@ -0,0 +1,93 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack"
+)
+
+// newToolStatusTestConnector returns a Connector backed by a fake Slack API that records the
+// chat.* calls it receives as "method text"
+func newToolStatusTestConnector(t *testing.T, enabled bool) (*Connector, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		mu.Lock()
+		calls = append(calls, r.URL.Path+" "+r.Form.Get("text"))
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"C1","ts":"1700000000.000900"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &Connector{
+		client:     slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/")),
+		logger:     logger.NewLogger(logger.Config{Level: logger.DebugLevel, Output: io.Discard}),
+		toolStatus: enabled,
+	}
+	return c, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), calls...)
+	}
+}
+
+func TestToolStatus_PostsAndUpdatesStatusLine(t *testing.T) {
+	c, calls := newToolStatusTestConnector(t, true)
+	ctx := context.Background()
+
+	status := c.newToolStatus("C1", "1700000000.000100")
+	onToolCall := status.onToolCall(ctx)
+	if onToolCall == nil {
+		t.Fatal("onToolCall() = nil, want a callback when tool use disclosure is on")
+	}
+	onToolCall("web_search")
+	onToolCall("web_search")
+	onToolCall("http_request")
+	status.clear(ctx)
+
+	want := []string{
+		"/chat.postMessage _Searching the web…_",
+		"/chat.update _Fetching a web page…_",
+		"/chat.delete ",
+	}
+	if got := calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Slack calls = %q, want %q", got, want)
+	}
+}
+
+func TestToolStatus_SilentWhenDisabled(t *testing.T) {
+	c, calls := newToolStatusTestConnector(t, false)
+	ctx := context.Background()
+
+	status := c.newToolStatus("C1", "1700000000.000100")
+	if onToolCall := status.onToolCall(ctx); onToolCall != nil {
+		t.Error("onToolCall() != nil, want no callback when tool use disclosure is off")
+	}
+	status.update(ctx, "Searching the web…")
+	status.clear(ctx)
+
+	if got := calls(); len(got) != 0 {
+		t.Errorf("Slack calls = %q, want none", got)
+	}
+}
+
+func TestToolStatus_ClearWithoutToolCalls(t *testing.T) {
+	c, calls := newToolStatusTestConnector(t, true)
+
+	c.newToolStatus("C1", "").clear(context.Background())
+
+	if got := calls(); len(got) != 0 {
+		t.Errorf("Slack calls = %q, want none when no tool was called", got)
+	}
+}
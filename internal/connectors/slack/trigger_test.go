@@ -0,0 +1,124 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/slack-go/slack/slackevents"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/session"
+)
+
+func TestHandleMessageEvent_TriggerPrefixes(t *testing.T) {
+	tests := []struct {
+		name      string
+		event     slackevents.MessageEvent
+		wantScope string
+	}{
+		{
+			name:      "prefixed message in normal channel",
+			event:     slackevents.MessageEvent{User: "U123", Channel: "CGENERAL", TimeStamp: "1700000000.000100", Text: "!ai how do I reset my password?"},
+			wantScope: "thread:CGENERAL:1700000000.000100",
+		},
+		{
+			name:      "prefixed thread reply",
+			event:     slackevents.MessageEvent{User: "U123", Channel: "CGENERAL", TimeStamp: "1700000000.000300", ThreadTimeStamp: "1700000000.000100", Text: "!AI and after that?"},
+			wantScope: "thread:CGENERAL:1700000000.000100",
+		},
+		{
+			name:  "message without prefix in normal channel",
+			event: slackevents.MessageEvent{User: "U123", Channel: "CGENERAL", TimeStamp: "1700000000.000100", Text: "how do I reset my password?"},
+		},
+		{
+			name:  "default prefix in channel with its own",
+			event: slackevents.MessageEvent{User: "U123", Channel: "COPS", TimeStamp: "1700000000.000100", Text: "!ai restart the worker"},
+		},
+		{
+			name:      "channel's own prefix",
+			event:     slackevents.MessageEvent{User: "U123", Channel: "COPS", TimeStamp: "1700000000.000100", Text: "!ops restart the worker"},
+			wantScope: "thread:COPS:1700000000.000100",
+		},
+		{
+			name:  "prefixed mention is left to the app_mention event",
+			event: slackevents.MessageEvent{User: "U123", Channel: "CGENERAL", TimeStamp: "1700000000.000100", Text: "!ai <@UBOT> hello"},
+		},
+		{
+			name:  "own prefixed message",
+			event: slackevents.MessageEvent{User: "UBOT", Channel: "CGENERAL", TimeStamp: "1700000000.000100", Text: "!ai run this"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newMentionTestConnector(t)
+			sessions := &recordingSessionManager{}
+			c.sessionMgr = sessions
+			c.triggers = executor.TriggerPrefixes{
+				Default:  []string{"!ai"},
+				Channels: map[string][]string{"COPS": {"!ops"}},
+			}
+
+			_ = c.handleMessageEvent(context.Background(), "", &tt.event)
+
+			if tt.wantScope == "" {
+				if len(sessions.scopes) != 0 {
+					t.Errorf("message was processed (sessions %v), want it ignored", sessions.scopes)
+				}
+				return
+			}
+			if len(sessions.scopes) != 1 || sessions.scopes[0] != tt.wantScope {
+				t.Errorf("sessions = %v, want [%s]", sessions.scopes, tt.wantScope)
+			}
+		})
+	}
+}
+
+func TestHandleMessageEvent_TriggerPrefixRemoved(t *testing.T) {
+	server, _ := newChannelContextTestAPI(t)
+	c := newChannelContextTestConnector(t, server.URL, false)
+	c.triggers = executor.TriggerPrefixes{Default: []string{"!ai"}}
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+
+	llm := &messageRecordingModel{}
+	exec, err := executor.NewExecutorWithConfig(executor.Config{
+		AgentFactory: func(agents.PlatformSpecificGuidanceProvider, agents.UserInfoFunc) (agent.Agent, error) {
+			return llmagent.New(llmagent.Config{Name: "test_agent", Model: llm})
+		},
+		AppName:         "test",
+		SessionService:  session.InMemoryService(),
+		ArtifactService: artifact.InMemoryService(),
+	})
+	if err != nil {
+		t.Fatalf("NewExecutorWithConfig() error = %v", err)
+	}
+	sessions, err := session_manager.New(session_manager.Config{
+		MetadataFile: "metadata.json",
+		FileProvider: storage_manager.NewLocalFileProvider(t.TempDir()),
+		Logger:       log,
+	})
+	if err != nil {
+		t.Fatalf("session_manager.New() error = %v", err)
+	}
+	c.executor = exec
+	c.sessionMgr = sessions
+
+	event := &slackevents.MessageEvent{User: "U123", Channel: "C456", TimeStamp: "1700000000.000100", Text: "!ai what's going on?"}
+	if err := c.handleMessageEvent(context.Background(), "T1", event); err != nil {
+		t.Fatalf("handleMessageEvent() error = %v", err)
+	}
+
+	if len(llm.messages) != 1 {
+		t.Fatalf("model called %d times, want 1", len(llm.messages))
+	}
+	if llm.messages[0] != "what's going on?" {
+		t.Errorf("message to the model = %q, want it without the prefix", llm.messages[0])
+	}
+}
@@ -0,0 +1,96 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/audit"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/sessionscope"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// handleMaintenanceCommand handles the admin-only /maintenance command, which pauses or
+// resumes LLM calls on every platform
+func (c *Connector) handleMaintenanceCommand(_ context.Context, _ *bot.Bot, update *models.Update) (string, error) {
+	userID := fmt.Sprintf("%d", update.Message.From.ID)
+	_, args, _ := strings.Cut(update.Message.Text, " ")
+	if !c.admins[userID] {
+		c.recordAudit(userID, audit.ActionMaintenance, "", audit.ResultDenied, strings.TrimSpace(args))
+		return "Sorry, only admins can use /maintenance.", nil
+	}
+
+	maintenance := c.executor.Maintenance()
+	reply, changed := executor.MaintenanceCommand(maintenance, args)
+	if changed {
+		action, message := audit.ActionMaintenanceOff, ""
+		if enabled, msg := maintenance.Status(); enabled {
+			action, message = audit.ActionMaintenanceOn, msg
+		}
+		c.recordAudit(userID, action, "", audit.ResultSuccess, message)
+	}
+	return reply, nil
+}
+
+// handleResetCommand handles the admin-only /reset command, which starts a new conversation
+// for another user in the chat the command is run in
+func (c *Connector) handleResetCommand(ctx context.Context, _ *bot.Bot, update *models.Update) (string, error) {
+	userID := fmt.Sprintf("%d", update.Message.From.ID)
+	chatID := fmt.Sprintf("%d", update.Message.Chat.ID)
+	_, target, _ := strings.Cut(update.Message.Text, " ")
+	target = strings.TrimSpace(target)
+
+	if !c.admins[userID] {
+		c.recordAudit(userID, audit.ActionSessionReset, target, audit.ResultDenied, "")
+		return "Sorry, only admins can use /reset.", nil
+	}
+	if _, err := strconv.ParseInt(target, 10, 64); err != nil {
+		return "Usage: /reset <user id>", nil
+	}
+
+	scopeKey := sessionscope.User("", target)
+	c.clearReplacedState(ctx, scopeKey)
+	sessionID, err := c.sessionMgr.CreateNewSession(ctx, "telegram", scopeKey, chatID)
+	if err != nil {
+		c.recordAudit(userID, audit.ActionSessionReset, target, audit.ResultFailed, err.Error())
+		return "Failed to reset the conversation.", err
+	}
+	c.recordAudit(userID, audit.ActionSessionReset, target, audit.ResultSuccess, "chat "+chatID)
+
+	return fmt.Sprintf("Started a new conversation for user %s in this chat. (Session: %s)", target, sessionID), nil
+}
+
+// clearReplacedState clears the state of the conversation a reset replaces, such as its
+// language override and budget usage, so none of it is left behind if it's picked up again
+func (c *Connector) clearReplacedState(ctx context.Context, scopeKey string) {
+	previous, err := c.sessionMgr.GetLatestSession(ctx, "telegram", scopeKey)
+	if err != nil || previous == "" {
+		return
+	}
+	if err := c.sessionMgr.ClearState(ctx, c.executor.AppName(), scopeKey, previous); err != nil {
+		c.logger.Warn("Failed to clear the replaced conversation's state",
+			logger.StringField("session_id", previous),
+			logger.ErrorField(err))
+	}
+}
+
+// recordAudit writes an audit record for an admin command run by userID
+func (c *Connector) recordAudit(userID, action, target, result, detail string) {
+	err := c.audit.Record(audit.Record{
+		Platform: "telegram",
+		Actor:    userID,
+		Action:   action,
+		Target:   target,
+		Result:   result,
+		Detail:   detail,
+	})
+	if err != nil {
+		c.logger.Error("Failed to write audit record",
+			logger.StringField("action", action),
+			logger.ErrorField(err))
+	}
+}
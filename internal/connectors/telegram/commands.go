@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/language"
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
 )
 
@@ -63,32 +64,93 @@ func (r *CommandRegistry) IsCommand(text string) bool {
 
 // handleNewCommand handles the /new command
 func (c *Connector) handleNewCommand(ctx context.Context, _ *bot.Bot, update *models.Update) (string, error) {
-	userID := fmt.Sprintf("%d", update.Message.From.ID)
 	chatID := fmt.Sprintf("%d", update.Message.Chat.ID)
 
-	sessionID, err := c.sessionMgr.CreateNewSession(ctx, "telegram", userID, chatID)
+	sessionID, err := c.sessionMgr.CreateNewSession(ctx, "telegram", chatScope(update.Message), chatID)
 	if err != nil {
 		return "Failed to create new session.", err
 	}
 	return fmt.Sprintf("Started new conversation! (Session: %s)", sessionID), nil
 }
 
+// handleLanguageCommand handles the /language command, which sets the language the bot replies in
+func (c *Connector) handleLanguageCommand(ctx context.Context, _ *bot.Bot, update *models.Update) (string, error) {
+	supported := strings.Join(language.Supported(), ", ")
+
+	parts := strings.SplitN(update.Message.Text, " ", 2)
+	if len(parts) < 2 || strings.TrimSpace(parts[1]) == "" {
+		return fmt.Sprintf("Usage: /language <code|auto> (supported: %s)", supported), nil
+	}
+	arg := strings.TrimSpace(parts[1])
+
+	code := ""
+	if !strings.EqualFold(arg, "auto") {
+		code = language.Parse(arg)
+		if code == "" {
+			return fmt.Sprintf("Unsupported language: %s (supported: %s)", arg, supported), nil
+		}
+	}
+
+	chatID := fmt.Sprintf("%d", update.Message.Chat.ID)
+
+	scopeKey := chatScope(update.Message)
+	sessionID, err := c.sessionMgr.GetOrCreateSession(ctx, "telegram", scopeKey, chatID)
+	if err != nil {
+		return "Failed to set language.", err
+	}
+	if err := c.executor.SetLanguageOverride(ctx, scopeKey, sessionID, code); err != nil {
+		return "Failed to set language.", err
+	}
+
+	if code == "" {
+		return "I'll reply in the language you write in.", nil
+	}
+	return fmt.Sprintf("I'll reply in %s in this conversation.", language.Name(code)), nil
+}
+
+// handleStartCommand handles /start, sent when a user first opens a chat with the bot
+func (c *Connector) handleStartCommand(_ context.Context, _ *bot.Bot, _ *models.Update) (string, error) {
+	greeting := c.intro
+	if greeting == "" {
+		greeting = "Hi!"
+	}
+	return greeting + "\n\nSend me a message to start chatting, or use /help to see what else I can do.", nil
+}
+
 // handleHelpCommand handles the /help command
 func (c *Connector) handleHelpCommand(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
 	helpText := `Available Commands:
 
 /new - Start a new conversation
+/language <code|auto> - Set the language I reply in
+/maintenance on [message]|off|status - Pause or resume replies (admins only)
+/reset <user id> - Start a new conversation for a user in this chat (admins only)
 /help - Show this help message`
 
+	if c.intro != "" {
+		helpText = c.intro + "\n\n" + helpText
+	}
 	return helpText, nil
 }
 
 // setupCommands initializes the command registry with all available commands
 func (c *Connector) setupCommands() {
 	c.commands = NewCommandRegistry()
+	c.commands.Register("/start", func(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+		return c.handleStartCommand(ctx, b, update)
+	})
 	c.commands.Register("/new", func(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
 		return c.handleNewCommand(ctx, b, update)
 	})
+	c.commands.Register("/language", func(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+		return c.handleLanguageCommand(ctx, b, update)
+	})
+	c.commands.Register("/maintenance", func(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+		return c.handleMaintenanceCommand(ctx, b, update)
+	})
+	c.commands.Register("/reset", func(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+		return c.handleResetCommand(ctx, b, update)
+	})
 	c.commands.Register("/help", func(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
 		return c.handleHelpCommand(ctx, b, update)
 	})
@@ -112,7 +174,7 @@ func (c *Connector) handleCommand(ctx context.Context, b *bot.Bot, update *model
 
 	// Send response if we have one
 	if response != "" {
-		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		_, err = c.sendMessage(ctx, b, &bot.SendMessageParams{
 			ChatID: update.Message.Chat.ID,
 			Text:   response,
 		})
@@ -3,9 +3,16 @@ package telegram
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/analytics"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/audit"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
@@ -18,15 +25,76 @@ type Connector struct {
 	logger     logger.Logger
 	commands   *CommandRegistry
 	sessionMgr session_manager.Manager
+	decorator  *executor.Decorator      // Adds the configured prefix/suffix and splits long responses
+	replies    *executor.ReplyDeduper   // Suppresses a reply identical to the one just posted to the chat
+	throttle   *executor.Throttle       // Paces sent messages to stay within Telegram's rate limits
+	admins     map[string]bool          // User IDs allowed to run admin commands
+	audit      *audit.Log               // Where admin commands are audited
+	webhook    WebhookConfig            // Receive updates through a webhook when URL is set
+	intro      string                   // How the bot introduces itself in /start and /help
+	errorReply *executor.ErrorMessage   // Reply sent with the correlation ID when a message fails
+	feedback   bool                     // Add 👍/👎 buttons to replies
+	analytics  *analytics.Log           // Where feedback is recorded
+	httpClient *http.Client             // Used for file downloads
+	triggers   executor.TriggerPrefixes // Prefixes group chat messages must start with, when set
+	connected  bool                     // Set while the bot is receiving updates
+	identity   executor.BotIdentity     // The bot's own account, cached by BotIdentity
+	cancel     context.CancelFunc       // Stops the running Start
+	stopped    chan struct{}            // Closed when the running Start returns
+	mu         sync.RWMutex
 }
 
 // Config holds configuration for the Telegram connector
 type Config struct {
-	BotToken string        // Bot token from @BotFather
-	Debug    bool          // Enable debug logging
-	Logger   logger.Logger // Structured logger instance
+	BotToken  string        // Bot token from @BotFather
+	ServerURL string        // Optional Bot API server URL, e.g. a self-hosted Bot API server
+	Debug     bool          // Enable debug logging
+	Logger    logger.Logger // Structured logger instance
+
+	// Optional response prefix and suffix (e.g. a disclaimer) templates; see executor.DecoratorConfig
+	ResponsePrefix   string
+	ResponseSuffix   string
+	SuffixEveryChunk bool   // Add the suffix to every message of a split response, not just the last
+	BotName          string // Value of the {{.BotName}} template variable
+
+	// DisplayName and Intro are how the bot introduces itself to users; see executor.Introduction
+	DisplayName string
+	Intro       string
+
+	// ErrorMessage is the reply template used when a message can't be processed; see
+	// executor.NewErrorMessage. Empty uses executor.DefaultErrorMessage.
+	ErrorMessage string
+
+	// AdminUsers are numeric user IDs allowed to run admin commands such as /maintenance
+	AdminUsers []string
+
+	// Audit records admin commands; nil to not audit them
+	Audit *audit.Log
+
+	// Feedback adds 👍/👎 buttons to replies; ratings are recorded in Analytics
+	Feedback  bool
+	Analytics *analytics.Log
+
+	// Throttle paces the messages the bot sends, overall and per chat, so bursts don't hit
+	// Telegram's rate limits
+	Throttle executor.ThrottleConfig
+
+	// HTTPClient is the client Bot API requests and file downloads are sent with, e.g. one
+	// using a proxy; nil uses the defaults
+	HTTPClient *http.Client
+
+	// Webhook receives updates through a webhook instead of long polling when its URL is set.
+	// WebhookHandler must then be served at that URL.
+	Webhook WebhookConfig
+
+	// TriggerPrefixes, when set for a group chat, limit the messages the bot answers there to
+	// those starting with one, e.g. "!ai", which is removed. Private chats are always answered.
+	TriggerPrefixes executor.TriggerPrefixes
 }
 
+// maxMessageLength is the longest message the Telegram Bot API accepts
+const maxMessageLength = 4096
+
 // NewConnector creates a new Telegram connector with in-process executor
 func NewConnector(config Config, exec *executor.Executor, sessionMgr session_manager.Manager) (*Connector, error) {
 	if config.BotToken == "" {
@@ -42,6 +110,23 @@ func NewConnector(config Config, exec *executor.Executor, sessionMgr session_man
 		return nil, fmt.Errorf("logger is required")
 	}
 
+	decorator, err := executor.NewDecorator(executor.DecoratorConfig{
+		Prefix:           config.ResponsePrefix,
+		Suffix:           config.ResponseSuffix,
+		SuffixEveryChunk: config.SuffixEveryChunk,
+		MaxLength:        maxMessageLength,
+		BotName:          config.BotName,
+		Platform:         "Telegram",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	errorReply, err := executor.NewErrorMessage(config.ErrorMessage)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create a logger with Telegram-specific context
 	telegramLogger := config.Logger.WithFields(logger.StringField("connector", "telegram"))
 
@@ -50,11 +135,48 @@ func NewConnector(config Config, exec *executor.Executor, sessionMgr session_man
 		executor:   exec,
 		logger:     telegramLogger,
 		sessionMgr: sessionMgr,
+		decorator:  decorator,
+		replies:    executor.NewReplyDeduper(executor.DuplicateReplyWindow),
+		throttle:   executor.NewThrottle(config.Throttle),
+		admins:     make(map[string]bool, len(config.AdminUsers)),
+		audit:      config.Audit,
+		webhook:    config.Webhook,
+		intro:      executor.Introduction(config.DisplayName, config.Intro),
+		errorReply: errorReply,
+		feedback:   config.Feedback,
+		analytics:  config.Analytics,
+		httpClient: &http.Client{},
+		triggers:   config.TriggerPrefixes,
+	}
+	transport := http.DefaultTransport
+	if config.HTTPClient != nil {
+		connector.httpClient = config.HTTPClient
+		if config.HTTPClient.Transport != nil {
+			transport = config.HTTPClient.Transport
+		}
+	}
+	for _, user := range config.AdminUsers {
+		connector.admins[strings.TrimSpace(user)] = true
 	}
 
 	// Initialize Telegram bot with default handler
 	opts := []bot.Option{
 		bot.WithDefaultHandler(connector.handleUpdate),
+		bot.WithErrorsHandler(func(err error) {
+			telegramLogger.Warn("Telegram bot error", logger.ErrorField(err))
+		}),
+		// Track whether long polls succeed so readiness reflects connectivity
+		bot.WithHTTPClient(pollTimeout, &http.Client{
+			Timeout:   pollTimeout,
+			Transport: &pollMonitor{base: transport, connector: connector},
+		}),
+	}
+	if config.Webhook.URL != "" {
+		// WebhookHandler rejects a bad token with 401 first; the library just drops the update
+		opts = append(opts, bot.WithWebhookSecretToken(config.Webhook.Secret))
+	}
+	if config.ServerURL != "" {
+		opts = append(opts, bot.WithServerURL(config.ServerURL))
 	}
 
 	if config.Debug {
@@ -75,18 +197,63 @@ func NewConnector(config Config, exec *executor.Executor, sessionMgr session_man
 	return connector, nil
 }
 
-// Start begins polling for updates
+// Start begins polling for updates, or registers the webhook in webhook mode. It blocks
+// until ctx is cancelled or Stop is called; the in-flight long poll is cancelled with it.
 func (c *Connector) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	stopped := make(chan struct{})
+	c.mu.Lock()
+	c.cancel, c.stopped = cancel, stopped
+	c.mu.Unlock()
+	defer close(stopped)
+	defer cancel()
+	defer c.setConnected(false)
+
+	if c.webhook.URL != "" {
+		c.logger.Info("Starting Telegram bot in webhook mode")
+		return c.runWebhook(ctx)
+	}
 	c.logger.Info("Starting Telegram bot polling")
 
+	// Get and log bot info. Until this succeeds the connector reports ready
+	// once the first poll does.
+	identity, err := c.BotIdentity(ctx)
+	if err != nil {
+		c.logger.Warn("Failed to get Telegram bot info", logger.ErrorField(err))
+	} else {
+		c.logger.Info("Telegram bot connected",
+			logger.StringField("bot_username", identity.Name),
+			logger.StringField("bot_user_id", identity.UserID))
+		c.setConnected(true)
+	}
+
 	// Start polling - this blocks until context is canceled
 	c.bot.Start(ctx)
 
 	return nil
 }
 
+func (c *Connector) setConnected(connected bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connected = connected
+}
+
 // handleUpdate processes all incoming Telegram updates
 func (c *Connector) handleUpdate(ctx context.Context, b *bot.Bot, update *models.Update) {
+	// Button presses, e.g. feedback on a reply
+	if update.CallbackQuery != nil {
+		c.handleCallbackQuery(ctx, b, update.CallbackQuery)
+		return
+	}
+
+	// Uploaded documents are ingested for search when enabled
+	if update.Message != nil && update.Message.Document != nil && update.Message.From != nil &&
+		!update.Message.From.IsBot && c.executor.DocumentsEnabled() {
+		c.handleDocument(ctx, b, update.Message)
+		return
+	}
+
 	// Only process text messages for now
 	if update.Message == nil || update.Message.Text == "" {
 		c.logger.Debug("Skipping non-text message or empty update")
@@ -108,63 +275,116 @@ func (c *Connector) handleUpdate(ctx context.Context, b *bot.Bot, update *models
 		return
 	}
 
-	c.logger.Info("Processing message",
-		logger.Int64Field("user_id", update.Message.From.ID),
-		logger.StringField("username", update.Message.From.Username))
-
 	// Create user info function that captures the user ID
 	userID := fmt.Sprintf("%d", update.Message.From.ID)
 	chatID := fmt.Sprintf("%d", update.Message.Chat.ID)
 
-	// Get or create session for this user
-	sessionID, err := c.sessionMgr.GetOrCreateSession(ctx, "telegram", userID, chatID)
+	// In group chats with trigger prefixes, only messages starting with one are for the bot
+	text := update.Message.Text
+	if update.Message.Chat.Type != models.ChatTypePrivate && c.triggers.Enabled(chatID) {
+		stripped, prefixed := c.triggers.Match(chatID, text)
+		if !prefixed {
+			c.logger.Debug("Skipping group message without a trigger prefix", logger.StringField("chat_id", chatID))
+			return
+		}
+		text = stripped
+	}
+
+	// Everything logged for this turn carries its correlation ID, which users are given on failure
+	correlationID := executor.NewCorrelationID()
+	ctx = logger.WithCorrelationIDContext(ctx, correlationID)
+	log := c.logger.WithFields(
+		logger.CorrelationIDField(correlationID),
+		logger.StringField("user_id", userID),
+		logger.StringField("chat_id", chatID))
+	log.Info("Processing message", logger.StringField("username", update.Message.From.Username))
+
+	// Get or create the session for this chat; a group chat's is shared by everyone in it
+	scopeKey := chatScope(update.Message)
+	sessionID, err := c.sessionMgr.GetOrCreateSession(ctx, "telegram", scopeKey, chatID)
 	if err != nil {
-		c.logger.Error("Error getting session", logger.ErrorField(err))
-		_, _ = b.SendMessage(ctx, &bot.SendMessageParams{
+		log.Error("Error getting session", logger.ErrorField(err))
+		_, _ = c.sendMessage(ctx, b, &bot.SendMessageParams{
 			ChatID: update.Message.Chat.ID,
-			Text:   "Sorry, I encountered an error creating your session.",
+			Text:   c.errorReply.Render(correlationID),
 		})
 		return
 	}
+	var documentsUserID string
+	if senderScope := userScope(update.Message); senderScope != scopeKey {
+		documentsUserID = senderScope
+		// Remember who spoke in the group, so their data can be exported or deleted
+		if err := c.sessionMgr.AddParticipant(ctx, sessionID, userID); err != nil {
+			log.Warn("Failed to record group participant", logger.StringField("session_id", sessionID), logger.ErrorField(err))
+		}
+	}
 
 	// Send message to agent via executor
 	response, err := c.executor.Execute(ctx, executor.MessageRequest{
-		UserID:    userID,
+		UserID:    scopeKey,
 		SessionID: sessionID,
-		Message:   update.Message.Text,
+		Message:   text,
+		Locale:    update.Message.From.LanguageCode,
+		ChannelID: strconv.FormatInt(update.Message.Chat.ID, 10),
+
+		DocumentsUserID: documentsUserID,
 	}, c, func() string {
 		return c.GetUserInfo(ctx, userID)
 	})
 	if err != nil {
-		c.logger.Error("Error from executor", logger.ErrorField(err))
-		// Send error message to user
-		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		log.Error("Error from executor", logger.StringField("session_id", sessionID), logger.ErrorField(err))
+		// Send error message to user with the reference to quote to support
+		_, err = c.sendMessage(ctx, b, &bot.SendMessageParams{
 			ChatID: update.Message.Chat.ID,
-			Text:   "Sorry, I encountered an error processing your message.",
+			Text:   c.errorReply.Render(correlationID),
 		})
 		if err != nil {
-			c.logger.Error("Error sending error message", logger.ErrorField(err))
+			log.Error("Error sending error message", logger.ErrorField(err))
 		}
 		return
 	}
 
-	// Send response back to Telegram
-	if response.Text != "" {
-		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+	// Send response back to Telegram, split into several messages if it's too long
+	if response.Text == "" {
+		return
+	}
+	if c.replies.CheckAndRecord(chatID, response.Text) {
+		log.Info("Skipping duplicate reply")
+		return
+	}
+	chunks := c.decorator.Apply(response.Text)
+	for i, chunk := range chunks {
+		params := &bot.SendMessageParams{
 			ChatID: update.Message.Chat.ID,
-			Text:   response.Text,
-		})
+			Text:   chunk,
+		}
+		// Feedback buttons go under the end of the reply
+		if c.feedback && i == len(chunks)-1 {
+			params.ReplyMarkup = feedbackKeyboard(sessionID)
+		}
+		_, err = c.sendMessage(ctx, b, params)
 		if err != nil {
-			c.logger.Error("Error sending message to Telegram", logger.ErrorField(err))
+			log.Error("Error sending message to Telegram", logger.ErrorField(err))
+			c.replies.Forget(chatID, response.Text)
 			return
 		}
 	}
 }
 
-// Stop gracefully stops the connector
+// Stop stops polling (or deregisters the webhook) and waits for Start to return
 func (c *Connector) Stop() error {
 	c.logger.Info("Stopping Telegram connector")
-	// Stopping is handled by context cancellation in Start
+
+	c.mu.RLock()
+	cancel, stopped := c.cancel, c.stopped
+	c.mu.RUnlock()
+	if cancel == nil {
+		return nil
+	}
+
+	// Cancel the long poll and wait for in-flight updates to be handled
+	cancel()
+	<-stopped
 	return nil
 }
 
@@ -173,6 +393,31 @@ func (c *Connector) GetBotInfo(ctx context.Context) (*models.User, error) {
 	return c.bot.GetMe(ctx)
 }
 
+// BotIdentity returns the bot's own user ID and username, looked up with getMe the first
+// time they're needed and cached from then on
+func (c *Connector) BotIdentity(ctx context.Context) (executor.BotIdentity, error) {
+	c.mu.RLock()
+	identity := c.identity
+	c.mu.RUnlock()
+	if identity.UserID != "" {
+		return identity, nil
+	}
+
+	me, err := c.bot.GetMe(ctx)
+	if err != nil {
+		return executor.BotIdentity{}, fmt.Errorf("failed to look up bot identity: %w", err)
+	}
+	identity = executor.BotIdentity{UserID: strconv.FormatInt(me.ID, 10), Name: me.Username}
+
+	c.mu.Lock()
+	c.identity = identity
+	c.mu.Unlock()
+	return identity, nil
+}
+
+// Ensure the connector supplies platform guidance to the agent
+var _ agents.PlatformSpecificGuidanceProvider = (*Connector)(nil)
+
 // PlatformName returns the platform name
 func (c *Connector) PlatformName() string {
 	return "Telegram"
@@ -277,11 +522,43 @@ Telegram also supports HTML formatting:
 - Maximum message length is 4096 characters`
 }
 
-// Ready returns nil if the Telegram connector is initialized and ready to receive requests,
+// Ready returns nil if the Telegram connector is connected and ready to receive requests,
 // or an error if it's not ready.
 func (c *Connector) Ready() error {
 	if c.bot == nil {
 		return fmt.Errorf("telegram bot not initialized")
 	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.connected {
+		return fmt.Errorf("telegram connector not connected")
+	}
+	return nil
+}
+
+// FormatSources renders cited sources as a plain-text list (responses are sent without a parse mode)
+func (c *Connector) FormatSources(sources []executor.Source) string {
+	return executor.FormatSourcesPlain(sources)
+}
+
+// SendReminder posts a reminder to a chat; see reminders.Sender
+func (c *Connector) SendReminder(ctx context.Context, chatID, text string) error {
+	id, err := strconv.ParseInt(chatID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chat ID %q: %w", chatID, err)
+	}
+	if _, err := c.sendMessage(ctx, c.bot, &bot.SendMessageParams{ChatID: id, Text: text}); err != nil {
+		return fmt.Errorf("failed to send reminder: %w", err)
+	}
 	return nil
 }
+
+// sendMessage sends a message once the throttle allows another to the chat
+func (c *Connector) sendMessage(ctx context.Context, b *bot.Bot, params *bot.SendMessageParams) (*models.Message, error) {
+	if err := c.throttle.Wait(ctx, fmt.Sprint(params.ChatID)); err != nil {
+		return nil, err
+	}
+	return b.SendMessage(ctx, params)
+}
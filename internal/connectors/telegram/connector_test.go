@@ -0,0 +1,352 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/analytics"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+)
+
+// fakeBotAPI is a Telegram Bot API server whose getUpdates can be made to fail. It records
+// the other methods called and the text of sent messages.
+type fakeBotAPI struct {
+	*httptest.Server
+	failing atomic.Bool
+	polls   atomic.Int64
+
+	mu      sync.Mutex
+	methods []string
+	sent    []string
+}
+
+// called reports whether method has been called
+func (api *fakeBotAPI) called(method string) bool {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return slices.Contains(api.methods, method)
+}
+
+// sentMessages returns the text of the messages sent so far
+func (api *fakeBotAPI) sentMessages() []string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return slices.Clone(api.sent)
+}
+
+func newFakeBotAPI(t *testing.T) *fakeBotAPI {
+	t.Helper()
+	api := &fakeBotAPI{}
+	api.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getMe"):
+			_, _ = io.WriteString(w, `{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"test_bot"}}`)
+		case strings.HasSuffix(r.URL.Path, "/getUpdates"):
+			api.polls.Add(1)
+			if api.failing.Load() {
+				w.WriteHeader(http.StatusBadGateway)
+				_, _ = io.WriteString(w, `{"ok":false,"error_code":502,"description":"Bad Gateway"}`)
+				return
+			}
+			// Hold the long poll briefly, or until it's cancelled
+			select {
+			case <-time.After(20 * time.Millisecond):
+			case <-r.Context().Done():
+				return
+			}
+			_, _ = io.WriteString(w, `{"ok":true,"result":[]}`)
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			_ = r.ParseMultipartForm(1 << 20)
+			api.mu.Lock()
+			api.methods = append(api.methods, "sendMessage")
+			api.sent = append(api.sent, r.FormValue("text"))
+			api.mu.Unlock()
+			_, _ = io.WriteString(w, `{"ok":true,"result":{"message_id":1,"date":0,"chat":{"id":42,"type":"private"}}}`)
+		default:
+			api.mu.Lock()
+			api.methods = append(api.methods, path.Base(r.URL.Path))
+			api.mu.Unlock()
+			_, _ = io.WriteString(w, `{"ok":true,"result":true}`)
+		}
+	}))
+	t.Cleanup(api.Close)
+	return api
+}
+
+func newTestConnector(t *testing.T, serverURL string) *Connector {
+	t.Helper()
+	return newTestConnectorWithWebhook(t, serverURL, WebhookConfig{})
+}
+
+func newTestConnectorWithWebhook(t *testing.T, serverURL string, webhook WebhookConfig) *Connector {
+	t.Helper()
+	return newTestConnectorWithConfig(t, Config{ServerURL: serverURL, Webhook: webhook})
+}
+
+// newTestConnectorWithConfig creates a connector from config with a test token, logger,
+// executor and session manager filled in
+func newTestConnectorWithConfig(t *testing.T, config Config) *Connector {
+	t.Helper()
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+
+	exec, err := executor.NewExecutorWithConfig(executor.Config{
+		AgentFactory: func(agents.PlatformSpecificGuidanceProvider, agents.UserInfoFunc) (agent.Agent, error) {
+			return nil, nil
+		},
+		AppName:        "test",
+		SessionService: session.InMemoryService(),
+	})
+	if err != nil {
+		t.Fatalf("NewExecutorWithConfig() error = %v", err)
+	}
+	sessions, err := session_manager.New(session_manager.Config{
+		MetadataFile: "metadata.json",
+		FileProvider: storage_manager.NewLocalFileProvider(t.TempDir()),
+		Logger:       log,
+	})
+	if err != nil {
+		t.Fatalf("session_manager.New() error = %v", err)
+	}
+
+	config.BotToken = "123:test"
+	if config.Logger == nil {
+		config.Logger = log
+	}
+	c, err := NewConnector(config, exec, sessions)
+	if err != nil {
+		t.Fatalf("NewConnector() error = %v", err)
+	}
+	return c
+}
+
+// waitFor polls cond until it's true or the timeout passes
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestConnector_ReadyReflectsPolling(t *testing.T) {
+	api := newFakeBotAPI(t)
+	c := newTestConnector(t, api.URL)
+
+	if err := c.Ready(); err == nil {
+		t.Error("Ready() = nil before Start, want an error")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = c.Start(context.Background())
+	}()
+	waitFor(t, "ready after starting", func() bool { return c.Ready() == nil })
+
+	api.failing.Store(true)
+	waitFor(t, "not ready while polls fail", func() bool { return c.Ready() != nil })
+
+	api.failing.Store(false)
+	waitFor(t, "ready once polls recover", func() bool { return c.Ready() == nil })
+
+	if err := c.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Start didn't return after Stop")
+	}
+	if err := c.Ready(); err == nil {
+		t.Error("Ready() = nil after Stop, want an error")
+	}
+
+	// No more polls are made once stopped
+	polls := api.polls.Load()
+	time.Sleep(100 * time.Millisecond)
+	if got := api.polls.Load(); got != polls {
+		t.Errorf("made %d polls after Stop, want none", got-polls)
+	}
+}
+
+func TestConnector_StopBeforeStart(t *testing.T) {
+	c := newTestConnector(t, newFakeBotAPI(t).URL)
+	if err := c.Stop(); err != nil {
+		t.Errorf("Stop() before Start error = %v", err)
+	}
+}
+
+// helpUpdate is a synthetic update carrying a /help command from a user
+const helpUpdate = `{"update_id":1,"message":{"message_id":7,"date":0,` +
+	`"from":{"id":42,"is_bot":false,"first_name":"Ada"},` +
+	`"chat":{"id":42,"type":"private"},"text":"/help"}}`
+
+func postUpdate(handler http.Handler, secret string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(helpUpdate))
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(secretTokenHeader, secret)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestConnector_Webhook(t *testing.T) {
+	api := newFakeBotAPI(t)
+	c := newTestConnectorWithWebhook(t, api.URL, WebhookConfig{URL: "https://bot.example.com/telegram/webhook", Secret: "s3cret"})
+	handler := c.WebhookHandler()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := c.Start(context.Background()); err != nil {
+			t.Errorf("Start() error = %v", err)
+		}
+	}()
+	waitFor(t, "ready after registering the webhook", func() bool { return c.Ready() == nil })
+	if !api.called("setWebhook") {
+		t.Error("setWebhook wasn't called on Start")
+	}
+
+	// Updates without the right secret are rejected and not processed
+	if rec := postUpdate(handler, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("update without a secret: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := postUpdate(handler, "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("update with the wrong secret: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/telegram/webhook", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+
+	// A valid update is handled like a polled one
+	if rec := postUpdate(handler, "s3cret"); rec.Code != http.StatusOK {
+		t.Errorf("valid update: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	waitFor(t, "a reply to /help", func() bool { return len(api.sentMessages()) > 0 })
+	if sent := api.sentMessages(); len(sent) != 1 || !strings.Contains(sent[0], "/help") {
+		t.Errorf("sent %q, want a single help message", sent)
+	}
+	if api.polls.Load() != 0 {
+		t.Errorf("made %d polls in webhook mode, want none", api.polls.Load())
+	}
+
+	if err := c.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Start didn't return after Stop")
+	}
+	if !api.called("deleteWebhook") {
+		t.Error("deleteWebhook wasn't called on shutdown")
+	}
+	if err := c.Ready(); err == nil {
+		t.Error("Ready() = nil after Stop, want an error")
+	}
+}
+
+func TestConnector_IntroducesBot(t *testing.T) {
+	c := newTestConnectorWithConfig(t, Config{ServerURL: newFakeBotAPI(t).URL, DisplayName: "Support Bot", Intro: "I answer billing questions."})
+
+	help, err := c.handleHelpCommand(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("handleHelpCommand() error = %v", err)
+	}
+	if !strings.HasPrefix(help, "Hi, I'm Support Bot. I answer billing questions.\n\n") || !strings.Contains(help, "/help") {
+		t.Errorf("help = %q, want the introduction followed by the commands", help)
+	}
+
+	greeting, err := c.handleStartCommand(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("handleStartCommand() error = %v", err)
+	}
+	if !strings.HasPrefix(greeting, "Hi, I'm Support Bot.") {
+		t.Errorf("greeting = %q, want it to introduce the bot by name", greeting)
+	}
+}
+
+func TestConnector_ErrorReplyIncludesCorrelationID(t *testing.T) {
+	api := newFakeBotAPI(t)
+	var logs bytes.Buffer
+	c := newTestConnectorWithConfig(t, Config{
+		ServerURL:    api.URL,
+		Logger:       logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Format: "json", Output: &logs}),
+		ErrorMessage: "Something went wrong, reference {{.CorrelationID}}",
+	})
+
+	// The test executor's agent factory returns no agent, so every message fails
+	var update models.Update
+	if err := json.Unmarshal([]byte(strings.Replace(helpUpdate, `"/help"`, `"hello"`, 1)), &update); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	c.handleUpdate(context.Background(), c.bot, &update)
+
+	sent := api.sentMessages()
+	if len(sent) != 1 || !strings.HasPrefix(sent[0], "Something went wrong, reference ") {
+		t.Fatalf("sent %q, want a single error reply", sent)
+	}
+	correlationID := strings.TrimPrefix(sent[0], "Something went wrong, reference ")
+
+	var entry map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to decode log line %q: %v", logs.String(), err)
+	}
+	if entry["level"] != "error" || entry["correlation_id"] != correlationID || entry["user_id"] != "42" {
+		t.Errorf("log entry = %v, want an error with correlation_id %q and the user", entry, correlationID)
+	}
+}
+
+func TestConnector_RecordsFeedback(t *testing.T) {
+	api := newFakeBotAPI(t)
+	var events bytes.Buffer
+	c := newTestConnectorWithConfig(t, Config{ServerURL: api.URL, Feedback: true, Analytics: analytics.New(&events)})
+
+	var update models.Update
+	err := json.Unmarshal([]byte(`{"update_id":2,"callback_query":{"id":"cb1","from":{"id":42,"is_bot":false,"first_name":"Ada"},`+
+		`"message":{"message_id":9,"date":1700000000,"chat":{"id":42,"type":"private"},"text":"an answer"},`+
+		`"data":"`+feedbackKeyboard("session_abc").InlineKeyboard[0][0].CallbackData+`"}}`), &update)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	c.handleUpdate(context.Background(), c.bot, &update)
+
+	var event analytics.Event
+	if err := json.NewDecoder(&events).Decode(&event); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if event.Type != analytics.EventFeedback || event.Platform != "telegram" || event.UserID != "42" ||
+		event.SessionID != "session_abc" || event.MessageRef != "42:9" || event.Rating != analytics.RatingPositive {
+		t.Errorf("event = %+v, want positive feedback from 42 on message 42:9 in session_abc", event)
+	}
+	if !api.called("answerCallbackQuery") {
+		t.Error("the callback query wasn't answered")
+	}
+}
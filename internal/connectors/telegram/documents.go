@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// handleDocument stores an uploaded text document for the sender and replies with the outcome.
+func (c *Connector) handleDocument(ctx context.Context, b *bot.Bot, msg *models.Message) {
+	reply := c.ingestDocument(ctx, b, userScope(msg), msg.Document)
+
+	if _, err := c.sendMessage(ctx, b, &bot.SendMessageParams{
+		ChatID: msg.Chat.ID,
+		Text:   reply,
+	}); err != nil {
+		c.logger.Error("Error sending message to Telegram", logger.ErrorField(err))
+	}
+}
+
+// ingestDocument downloads a document and ingests it, returning a message for the user.
+func (c *Connector) ingestDocument(ctx context.Context, b *bot.Bot, userID string, doc *models.Document) string {
+	if doc.FileSize > executor.MaxDocumentSize {
+		return fmt.Sprintf("%s is too large to add to your documents (limit is %d KB).", doc.FileName, executor.MaxDocumentSize/1024)
+	}
+	if !executor.IsTextDocument(doc.MimeType, doc.FileName) {
+		return fmt.Sprintf("I can only add text documents to your documents, so I skipped %s.", doc.FileName)
+	}
+
+	file, err := b.GetFile(ctx, &bot.GetFileParams{FileID: doc.FileID})
+	if err != nil {
+		c.logger.Error("Error fetching file from Telegram",
+			logger.StringField("file_id", doc.FileID),
+			logger.ErrorField(err))
+		return fmt.Sprintf("Sorry, I couldn't download %s.", doc.FileName)
+	}
+
+	content, err := c.downloadFile(ctx, b.FileDownloadLink(file))
+	if err != nil {
+		c.logger.Error("Error downloading file from Telegram",
+			logger.StringField("file_id", doc.FileID),
+			logger.ErrorField(err))
+		return fmt.Sprintf("Sorry, I couldn't download %s.", doc.FileName)
+	}
+
+	chunks, err := c.executor.IngestDocument(ctx, userID, doc.FileName, content, map[string]string{
+		"source":  "telegram",
+		"file_id": doc.FileID,
+	})
+	if err != nil {
+		c.logger.Error("Error ingesting document",
+			logger.StringField("file_id", doc.FileID),
+			logger.ErrorField(err))
+		return fmt.Sprintf("Sorry, I couldn't add %s to your documents.", doc.FileName)
+	}
+
+	return fmt.Sprintf("Added %s to your documents (%d sections). Ask me about it any time.", doc.FileName, chunks)
+}
+
+// downloadFile fetches a file, reading at most one byte past the document size limit
+// so oversized files are rejected by the executor.
+func (c *Connector) downloadFile(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := *c.httpClient
+	client.Timeout = 30 * time.Second
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, executor.MaxDocumentSize+1))
+}
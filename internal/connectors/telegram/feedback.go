@@ -0,0 +1,75 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/analytics"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// feedbackPrefix starts the callback data of the feedback buttons, followed by the rating
+// and the session ID ("feedback:up:<session>"). Telegram allows 64 bytes of callback data.
+const feedbackPrefix = "feedback:"
+
+// feedbackKeyboard returns the 👍/👎 buttons added under a reply in the given session
+func feedbackKeyboard(sessionID string) *models.InlineKeyboardMarkup {
+	return &models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{{
+		{Text: "👍", CallbackData: feedbackPrefix + "up:" + sessionID},
+		{Text: "👎", CallbackData: feedbackPrefix + "down:" + sessionID},
+	}}}
+}
+
+// parseFeedback returns the rating and session ID of feedback button callback data
+func parseFeedback(data string) (rating, sessionID string, ok bool) {
+	rest, ok := strings.CutPrefix(data, feedbackPrefix)
+	if !ok {
+		return "", "", false
+	}
+	vote, sessionID, _ := strings.Cut(rest, ":")
+	switch vote {
+	case "up":
+		return analytics.RatingPositive, sessionID, true
+	case "down":
+		return analytics.RatingNegative, sessionID, true
+	default:
+		return "", "", false
+	}
+}
+
+// handleCallbackQuery records a press of a reply's feedback buttons
+func (c *Connector) handleCallbackQuery(ctx context.Context, b *bot.Bot, query *models.CallbackQuery) {
+	rating, sessionID, ok := parseFeedback(query.Data)
+	if !ok {
+		c.logger.Debug("Ignoring unknown callback query", logger.StringField("data", query.Data))
+		return
+	}
+
+	event := analytics.Event{
+		Type:      analytics.EventFeedback,
+		Platform:  "telegram",
+		UserID:    fmt.Sprintf("%d", query.From.ID),
+		SessionID: sessionID,
+		Rating:    rating,
+	}
+	// Messages older than 48 hours are delivered as inaccessible but still carry their ID
+	if msg := query.Message.Message; msg != nil {
+		event.MessageRef = fmt.Sprintf("%d:%d", msg.Chat.ID, msg.ID)
+	} else if msg := query.Message.InaccessibleMessage; msg != nil {
+		event.MessageRef = fmt.Sprintf("%d:%d", msg.Chat.ID, msg.MessageID)
+	}
+	if err := c.analytics.Record(event); err != nil {
+		c.logger.Error("Failed to record feedback", logger.ErrorField(err))
+	}
+
+	// Stop the client's loading indicator and thank the user
+	if _, err := b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: query.ID,
+		Text:            "Thanks for your feedback!",
+	}); err != nil {
+		c.logger.Warn("Failed to answer feedback callback", logger.ErrorField(err))
+	}
+}
@@ -0,0 +1,37 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// pollTimeout is how long a getUpdates long poll waits for updates; the HTTP client
+// timeout matches it, as in the bot library's default client
+const pollTimeout = time.Minute
+
+// pollMonitor is an http.RoundTripper that tracks whether getUpdates requests are
+// succeeding, so Ready reflects whether the bot is actually receiving updates
+type pollMonitor struct {
+	base      http.RoundTripper
+	connector *Connector
+}
+
+func (m *pollMonitor) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := m.base.RoundTrip(req)
+	if !strings.HasSuffix(req.URL.Path, "/getUpdates") {
+		return resp, err
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		// Shutting down; Start clears the connected flag
+	case err != nil || resp.StatusCode >= http.StatusBadRequest:
+		m.connector.setConnected(false)
+	default:
+		m.connector.setConnected(true)
+	}
+	return resp, err
+}
@@ -0,0 +1,22 @@
+package telegram
+
+import (
+	"strconv"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/sessionscope"
+)
+
+// chatScope returns the scope key for the conversation a message belongs to: the sender's own
+// in a private chat, or one shared by everyone in a group chat
+func chatScope(msg *models.Message) string {
+	if msg.Chat.Type == models.ChatTypePrivate {
+		return userScope(msg)
+	}
+	return sessionscope.Channel("", strconv.FormatInt(msg.Chat.ID, 10))
+}
+
+// userScope returns the scope key for the sender's direct messages and personal settings
+func userScope(msg *models.Message) string {
+	return sessionscope.User("", strconv.FormatInt(msg.From.ID, 10))
+}
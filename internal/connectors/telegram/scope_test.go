@@ -0,0 +1,62 @@
+package telegram
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/sessionscope"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/session"
+)
+
+func TestConnector_GroupChatsShareOneSession(t *testing.T) {
+	api := newFakeBotAPI(t)
+	c := newTestConnector(t, api.URL)
+	exec, err := executor.NewExecutorWithConfig(executor.Config{
+		AgentFactory: func(agents.PlatformSpecificGuidanceProvider, agents.UserInfoFunc) (agent.Agent, error) {
+			return llmagent.New(llmagent.Config{Name: "test_agent", Model: &messageRecordingModel{}})
+		},
+		AppName:         "test",
+		SessionService:  session.InMemoryService(),
+		ArtifactService: artifact.InMemoryService(),
+	})
+	if err != nil {
+		t.Fatalf("NewExecutorWithConfig() error = %v", err)
+	}
+	c.executor = exec
+
+	fromOther := textUpdate(-100, models.ChatTypeGroup, "me too")
+	fromOther.Message.From = &models.User{ID: 43, FirstName: "Grace"}
+	for _, update := range []*models.Update{
+		textUpdate(-100, models.ChatTypeGroup, "hello group"),
+		fromOther,
+		textUpdate(42, models.ChatTypePrivate, "hello in private"),
+	} {
+		c.handleUpdate(context.Background(), c.bot, update)
+	}
+
+	sessions := c.sessionMgr.ListSessions(context.Background())
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want one for the group and one for the private chat", len(sessions))
+	}
+	for _, info := range sessions {
+		switch info.UserID {
+		case sessionscope.Channel("", "-100"):
+			if !slices.Equal(info.Participants, []string{"42", "43"}) {
+				t.Errorf("group participants = %v, want both senders", info.Participants)
+			}
+		case sessionscope.User("", "42"):
+			if info.ChannelID != "42" {
+				t.Errorf("private session channel = %q, want 42", info.ChannelID)
+			}
+		default:
+			t.Errorf("unexpected session scope %q", info.UserID)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// messageRecordingModel records the last user message of each request and answers "ok"
+type messageRecordingModel struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (m *messageRecordingModel) Name() string { return "fake-model" }
+
+func (m *messageRecordingModel) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	var text strings.Builder
+	if len(req.Contents) > 0 {
+		for _, part := range req.Contents[len(req.Contents)-1].Parts {
+			text.WriteString(part.Text)
+		}
+	}
+	m.mu.Lock()
+	m.messages = append(m.messages, text.String())
+	m.mu.Unlock()
+
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{Content: genai.NewContentFromText("ok", genai.RoleModel)}, nil)
+	}
+}
+
+// textUpdate returns an update with a text message from user 42 in a chat of chatType
+func textUpdate(chatID int64, chatType models.ChatType, text string) *models.Update {
+	return &models.Update{Message: &models.Message{
+		ID:   7,
+		From: &models.User{ID: 42, FirstName: "Ada"},
+		Chat: models.Chat{ID: chatID, Type: chatType},
+		Text: text,
+	}}
+}
+
+func TestConnector_TriggerPrefixes(t *testing.T) {
+	api := newFakeBotAPI(t)
+	c := newTestConnectorWithConfig(t, Config{
+		ServerURL: api.URL,
+		TriggerPrefixes: executor.TriggerPrefixes{
+			Default:  []string{"!ai"},
+			Channels: map[string][]string{"-200": {"!ops"}},
+		},
+	})
+	llm := &messageRecordingModel{}
+	exec, err := executor.NewExecutorWithConfig(executor.Config{
+		AgentFactory: func(agents.PlatformSpecificGuidanceProvider, agents.UserInfoFunc) (agent.Agent, error) {
+			return llmagent.New(llmagent.Config{Name: "test_agent", Model: llm})
+		},
+		AppName:         "test",
+		SessionService:  session.InMemoryService(),
+		ArtifactService: artifact.InMemoryService(),
+	})
+	if err != nil {
+		t.Fatalf("NewExecutorWithConfig() error = %v", err)
+	}
+	c.executor = exec
+
+	updates := []*models.Update{
+		textUpdate(-100, models.ChatTypeSupergroup, "!ai what's the status?"),
+		textUpdate(-100, models.ChatTypeSupergroup, "just chatting"),
+		textUpdate(-200, models.ChatTypeGroup, "!ai restart it"),
+		textUpdate(-200, models.ChatTypeGroup, "!ops restart the worker"),
+		textUpdate(42, models.ChatTypePrivate, "no prefix needed here"),
+	}
+	for _, update := range updates {
+		c.handleUpdate(context.Background(), c.bot, update)
+	}
+
+	want := []string{"what's the status?", "restart the worker", "no prefix needed here"}
+	if fmt.Sprint(llm.messages) != fmt.Sprint(want) {
+		t.Errorf("messages sent to the model = %q, want %q", llm.messages, want)
+	}
+	if sent := api.sentMessages(); len(sent) != len(want) {
+		t.Errorf("sent %d replies, want %d", len(sent), len(want))
+	}
+}
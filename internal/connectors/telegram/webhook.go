@@ -0,0 +1,76 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// secretTokenHeader is the header Telegram sends the webhook secret token in
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// deleteWebhookTimeout bounds deregistering the webhook on shutdown
+const deleteWebhookTimeout = 10 * time.Second
+
+// WebhookConfig configures receiving updates through a webhook instead of long polling
+type WebhookConfig struct {
+	URL    string // Public HTTPS URL Telegram POSTs updates to; empty to long-poll
+	Secret string // Secret token Telegram sends with each update
+}
+
+// runWebhook registers the webhook and handles updates delivered to WebhookHandler until
+// ctx is cancelled, then deregisters it
+func (c *Connector) runWebhook(ctx context.Context) error {
+	ok, err := c.bot.SetWebhook(ctx, &bot.SetWebhookParams{
+		URL:         c.webhook.URL,
+		SecretToken: c.webhook.Secret,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register Telegram webhook: %w", err)
+	}
+	if !ok {
+		return errors.New("failed to register Telegram webhook: Telegram didn't accept it")
+	}
+	c.logger.Info("Telegram webhook registered", logger.StringField("url", c.webhook.URL))
+	c.setConnected(true)
+
+	// Handle updates - this blocks until context is canceled
+	c.bot.StartWebhook(ctx)
+
+	// ctx is cancelled by now, so deregister with a fresh one
+	deleteCtx, cancel := context.WithTimeout(context.Background(), deleteWebhookTimeout)
+	defer cancel()
+	if _, err := c.bot.DeleteWebhook(deleteCtx, &bot.DeleteWebhookParams{}); err != nil {
+		c.logger.Warn("Failed to delete Telegram webhook", logger.ErrorField(err))
+	} else {
+		c.logger.Info("Telegram webhook deleted")
+	}
+	return nil
+}
+
+// WebhookHandler returns the handler for the webhook endpoint. It rejects requests without
+// the configured secret token before passing updates to the bot.
+func (c *Connector) WebhookHandler() http.Handler {
+	updates := c.bot.WebhookHandler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		token := r.Header.Get(secretTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(c.webhook.Secret)) != 1 {
+			c.logger.Warn("Rejected Telegram webhook request with an invalid secret token",
+				logger.StringField("remote_addr", r.RemoteAddr))
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		updates(w, r)
+	})
+}
@@ -0,0 +1,57 @@
+// Package jsonl writes JSON records to a sink, one per line. It backs the logs kept apart
+// from the application log, such as the audit and analytics logs.
+package jsonl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Sink appends JSON lines to a writer, one record per Write. It's safe for concurrent use,
+// and a nil *Sink discards records.
+type Sink struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer
+}
+
+// New creates a sink writing JSON lines to w
+func New(w io.Writer) *Sink {
+	return &Sink{enc: json.NewEncoder(w)}
+}
+
+// Open creates a sink appending to the file at path, creating it if needed
+func Open(path string) (*Sink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // G304: Path comes from configuration
+	if err != nil {
+		return nil, err
+	}
+	s := New(f)
+	s.closer = f
+	return s, nil
+}
+
+// Write writes v as one line of JSON
+func (s *Sink) Write(v any) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode record: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying file, if the sink owns one
+func (s *Sink) Close() error {
+	if s == nil || s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
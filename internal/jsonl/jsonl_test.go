@@ -0,0 +1,52 @@
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+func TestSink_ConcurrentWritesStayOnTheirOwnLines(t *testing.T) {
+	var buf bytes.Buffer
+	s := New(&buf)
+
+	const writers, perWriter = 8, 50
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(writer int) {
+			defer wg.Done()
+			for j := 0; j < perWriter; j++ {
+				if err := s.Write(map[string]int{"writer": writer, "n": j}); err != nil {
+					t.Errorf("Write() error = %v", err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	lines := 0
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var record map[string]int
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("line %d = %q, not a JSON record: %v", lines, scanner.Text(), err)
+		}
+		lines++
+	}
+	if lines != writers*perWriter {
+		t.Errorf("got %d lines, want %d", lines, writers*perWriter)
+	}
+}
+
+func TestSink_NilDiscards(t *testing.T) {
+	var s *Sink
+	if err := s.Write("ignored"); err != nil {
+		t.Errorf("Write() on nil sink error = %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("Close() on nil sink error = %v", err)
+	}
+}
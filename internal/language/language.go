@@ -0,0 +1,240 @@
+// Package language provides lightweight language detection and the session state
+// keys used to remember which language the agent should respond in.
+package language
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"google.golang.org/adk/session"
+)
+
+const (
+	// StateKey is the session state key holding the detected (or platform default) language code
+	StateKey = "language"
+	// OverrideStateKey is the session state key holding a language set explicitly by the user.
+	// It takes precedence over detection; an empty value means detection is used.
+	OverrideStateKey = "language_override"
+)
+
+// minLatinWords is the number of words a Latin-script message needs before stopwords are scored,
+// so short replies like "ok thanks" don't flip the conversation language
+const minLatinWords = 3
+
+// minScriptLetters is the number of letters a non-Latin message needs to be detected by script
+const minScriptLetters = 2
+
+// names maps the supported ISO 639-1 codes to their English names
+var names = map[string]string{
+	"ar": "Arabic",
+	"de": "German",
+	"el": "Greek",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"he": "Hebrew",
+	"hi": "Hindi",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"nl": "Dutch",
+	"pt": "Portuguese",
+	"ru": "Russian",
+	"th": "Thai",
+	"uk": "Ukrainian",
+	"zh": "Chinese",
+}
+
+// stopwords are frequent words used to tell Latin-script languages apart
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "you", "what", "how", "this", "that", "with", "for", "can", "have", "it", "of", "to", "i", "my", "do", "please"},
+	"es": {"el", "la", "los", "las", "que", "es", "y", "en", "por", "para", "con", "una", "cómo", "qué", "está", "puedes", "gracias", "hola", "mi", "pero"},
+	"fr": {"le", "la", "les", "et", "est", "je", "tu", "vous", "que", "une", "des", "pour", "avec", "dans", "pas", "comment", "merci", "bonjour", "ce", "qui"},
+	"de": {"der", "die", "das", "und", "ist", "ich", "du", "sie", "nicht", "mit", "ein", "eine", "wie", "was", "für", "auf", "danke", "bitte", "kannst", "mir"},
+	"it": {"il", "lo", "gli", "che", "è", "di", "e", "un", "una", "per", "con", "non", "sono", "come", "cosa", "grazie", "ciao", "puoi", "mi", "della"},
+	"pt": {"o", "os", "que", "é", "e", "de", "um", "uma", "para", "com", "não", "você", "como", "obrigado", "obrigada", "olá", "pode", "meu", "isso", "do"},
+	"nl": {"de", "het", "een", "en", "is", "ik", "je", "jij", "niet", "met", "van", "voor", "wat", "hoe", "dat", "dank", "alsjeblieft", "kun", "mijn", "op"},
+}
+
+// stopwordSets indexes stopwords for lookup
+var stopwordSets = func() map[string]map[string]bool {
+	sets := make(map[string]map[string]bool, len(stopwords))
+	for code, words := range stopwords {
+		set := make(map[string]bool, len(words))
+		for _, word := range words {
+			set[word] = true
+		}
+		sets[code] = set
+	}
+	return sets
+}()
+
+// Detect returns the ISO 639-1 code of the language text is written in,
+// or "" when the text is too short or the language can't be told confidently.
+func Detect(text string) string {
+	if code := detectScript(text); code != "" {
+		return code
+	}
+	return detectLatin(text)
+}
+
+// detectScript identifies languages written in a distinctive (non-Latin) script
+func detectScript(text string) string {
+	counts := make(map[string]int)
+	latin := 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Hangul, r):
+			counts["ko"]++
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			counts["kana"]++
+		case unicode.Is(unicode.Han, r):
+			counts["han"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			counts["cyrillic"]++
+			if strings.ContainsRune("іїєґІЇЄҐ", r) {
+				counts["uk"]++
+			}
+		case unicode.Is(unicode.Arabic, r):
+			counts["ar"]++
+		case unicode.Is(unicode.Hebrew, r):
+			counts["he"]++
+		case unicode.Is(unicode.Greek, r):
+			counts["el"]++
+		case unicode.Is(unicode.Devanagari, r):
+			counts["hi"]++
+		case unicode.Is(unicode.Thai, r):
+			counts["th"]++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+
+	// Japanese mixes kana with Han characters; Han alone is taken as Chinese
+	if counts["kana"] > 0 {
+		counts["ja"] = counts["kana"] + counts["han"]
+	} else {
+		counts["zh"] = counts["han"]
+	}
+	if counts["cyrillic"] > 0 {
+		if counts["uk"] > 0 {
+			counts["uk"] = counts["cyrillic"]
+		} else {
+			counts["ru"] = counts["cyrillic"]
+		}
+	}
+
+	best, bestCount := "", 0
+	for code, count := range counts {
+		if _, ok := names[code]; !ok {
+			continue
+		}
+		if count > bestCount || (count == bestCount && code < best) {
+			best, bestCount = code, count
+		}
+	}
+	if bestCount < minScriptLetters || bestCount < latin {
+		return ""
+	}
+	return best
+}
+
+// detectLatin scores the words of text against each language's stopwords
+func detectLatin(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	if len(words) < minLatinWords {
+		return ""
+	}
+
+	scores := make(map[string]int)
+	for _, word := range words {
+		for code, set := range stopwordSets {
+			if set[word] {
+				scores[code]++
+			}
+		}
+	}
+
+	best, bestScore, tied := "", 0, false
+	for code, score := range scores {
+		switch {
+		case score > bestScore:
+			best, bestScore, tied = code, score, false
+		case score == bestScore:
+			tied = true
+		}
+	}
+
+	// Require a couple of hits and a clear winner
+	if bestScore < 2 || tied {
+		return ""
+	}
+	return best
+}
+
+// Name returns the English name of a language code, or "" if it isn't supported
+func Name(code string) string {
+	return names[code]
+}
+
+// Normalize converts a locale or language tag (e.g. "en-US", "pt_BR") to a supported
+// ISO 639-1 code, or "" if the language isn't supported.
+func Normalize(locale string) string {
+	code := strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	if _, ok := names[code]; !ok {
+		return ""
+	}
+	return code
+}
+
+// Parse accepts a language code, locale or English name (e.g. "es", "es-MX", "Spanish")
+// and returns its ISO 639-1 code, or "" if it isn't recognised.
+func Parse(input string) string {
+	if code := Normalize(input); code != "" {
+		return code
+	}
+	for code, name := range names {
+		if strings.EqualFold(strings.TrimSpace(input), name) {
+			return code
+		}
+	}
+	return ""
+}
+
+// Supported returns the supported language codes in alphabetical order
+func Supported() []string {
+	codes := make([]string, 0, len(names))
+	for code := range names {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// FromState returns the language to respond in for a session: the user's override if set,
+// otherwise the detected language, or "" when neither is known.
+func FromState(state session.ReadonlyState) string {
+	if code := Get(state, OverrideStateKey); code != "" {
+		return code
+	}
+	return Get(state, StateKey)
+}
+
+// Get reads a language code from session state, returning "" if it's missing or not a string
+func Get(state session.ReadonlyState, key string) string {
+	if state == nil {
+		return ""
+	}
+	value, err := state.Get(key)
+	if err != nil {
+		return ""
+	}
+	code, _ := value.(string)
+	return code
+}
@@ -0,0 +1,108 @@
+package language
+
+import (
+	"iter"
+	"testing"
+
+	"google.golang.org/adk/session"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "english", text: "How do I reset my password for this account?", want: "en"},
+		{name: "spanish", text: "Hola, ¿cómo puedo cambiar la contraseña de mi cuenta?", want: "es"},
+		{name: "french", text: "Bonjour, comment je peux changer le mot de passe pour mon compte ?", want: "fr"},
+		{name: "german", text: "Kannst du mir bitte sagen, wie ich das Passwort ändere?", want: "de"},
+		{name: "italian", text: "Ciao, come posso cambiare la password del mio account? Grazie", want: "it"},
+		{name: "portuguese", text: "Olá, você pode me ajudar a mudar a senha? Obrigado", want: "pt"},
+		{name: "dutch", text: "Hoe kan ik het wachtwoord van mijn account wijzigen?", want: "nl"},
+		{name: "russian", text: "Как мне сменить пароль?", want: "ru"},
+		{name: "ukrainian", text: "Як мені змінити пароль від акаунта? Дякую, це її", want: "uk"},
+		{name: "japanese", text: "パスワードを変更するにはどうすればいいですか", want: "ja"},
+		{name: "chinese", text: "我怎么修改密码？", want: "zh"},
+		{name: "korean", text: "비밀번호를 어떻게 변경하나요?", want: "ko"},
+		{name: "arabic", text: "كيف أغير كلمة المرور؟", want: "ar"},
+		{name: "too short", text: "ok thanks", want: ""},
+		{name: "no words", text: "👍 123", want: ""},
+		{name: "code is not a language", text: "func main() { fmt.Println(x) }", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text); got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeAndParse(t *testing.T) {
+	tests := []struct {
+		input         string
+		wantNormalize string
+		wantParse     string
+	}{
+		{input: "en-US", wantNormalize: "en", wantParse: "en"},
+		{input: "pt_BR", wantNormalize: "pt", wantParse: "pt"},
+		{input: "ES", wantNormalize: "es", wantParse: "es"},
+		{input: "Spanish", wantNormalize: "", wantParse: "es"},
+		{input: "klingon", wantNormalize: "", wantParse: ""},
+		{input: "", wantNormalize: "", wantParse: ""},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.input); got != tt.wantNormalize {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.wantNormalize)
+		}
+		if got := Parse(tt.input); got != tt.wantParse {
+			t.Errorf("Parse(%q) = %q, want %q", tt.input, got, tt.wantParse)
+		}
+	}
+}
+
+// mapState is a session.ReadonlyState backed by a map
+type mapState map[string]any
+
+func (s mapState) Get(key string) (any, error) {
+	value, ok := s[key]
+	if !ok {
+		return nil, session.ErrStateKeyNotExist
+	}
+	return value, nil
+}
+
+func (s mapState) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for key, value := range s {
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+func TestFromState(t *testing.T) {
+	tests := []struct {
+		name  string
+		state mapState
+		want  string
+	}{
+		{name: "empty", state: mapState{}, want: ""},
+		{name: "detected", state: mapState{StateKey: "es"}, want: "es"},
+		{name: "override wins", state: mapState{StateKey: "es", OverrideStateKey: "fr"}, want: "fr"},
+		{name: "cleared override", state: mapState{StateKey: "es", OverrideStateKey: ""}, want: "es"},
+		{name: "wrong type", state: mapState{StateKey: 42}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromState(tt.state); got != tt.want {
+				t.Errorf("FromState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package memory_service //nolint:revive // var-naming: using underscores for domain clarity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// Document chunking and search defaults
+const (
+	DefaultChunkSize    = 1000 // characters per chunk
+	DefaultChunkOverlap = 200  // characters shared between consecutive chunks
+	DefaultSearchLimit  = 5
+	MaxSearchLimit      = 20
+)
+
+// IngestDocument chunks and embeds a document and stores it for the given app and user.
+// Ingesting a document with the same name again replaces the previous version.
+// Returns the number of chunks stored.
+func (s *Service) IngestDocument(
+	ctx context.Context,
+	appName, userID, name, content string,
+	metadata map[string]string,
+) (int, error) {
+	if appName == "" || userID == "" {
+		return 0, fmt.Errorf("app name and user ID are required")
+	}
+	if name == "" {
+		return 0, fmt.Errorf("document name is required")
+	}
+
+	chunks := chunkText(content, s.chunkSize, s.chunkOverlap)
+	if len(chunks) == 0 {
+		return 0, fmt.Errorf("document %q has no text content", name)
+	}
+
+	embeddings, err := s.embedder.Embed(ctx, chunks)
+	if err != nil {
+		return 0, fmt.Errorf("failed to embed document: %w", err)
+	}
+	if len(embeddings) != len(chunks) {
+		return 0, fmt.Errorf("embedder returned %d vectors for %d chunks", len(embeddings), len(chunks))
+	}
+
+	doc := DocumentData{
+		ID:        documentID(name),
+		Name:      name,
+		AppName:   appName,
+		UserID:    userID,
+		Metadata:  metadata,
+		CreatedAt: time.Now(),
+		Chunks:    make([]DocumentChunk, len(chunks)),
+	}
+	for i, chunk := range chunks {
+		doc.Chunks[i] = DocumentChunk{Index: i, Text: chunk, Embedding: embeddings[i]}
+	}
+
+	userLock := s.getUserLock(appName, userID)
+	userLock.Lock()
+	defer userLock.Unlock()
+
+	if err := s.writeJSON(ctx, s.documentPath(appName, userID, doc.ID), doc); err != nil {
+		return 0, fmt.Errorf("failed to write document: %w", err)
+	}
+
+	s.log.Info("Ingested document",
+		logger.StringField("document", name),
+		logger.StringField("user_id", userID),
+		logger.IntField("chunks", len(chunks)))
+
+	return len(chunks), nil
+}
+
+// SearchDocuments returns the document chunks most similar to the query.
+// Only documents ingested for the same app and user are searched.
+func (s *Service) SearchDocuments(ctx context.Context, appName, userID, query string, limit int) ([]DocumentMatch, error) {
+	if strings.TrimSpace(query) == "" {
+		return []DocumentMatch{}, nil
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	embeddings, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(embeddings) != 1 {
+		return nil, fmt.Errorf("embedder returned %d vectors for 1 query", len(embeddings))
+	}
+	queryVec := embeddings[0]
+
+	paths, err := s.fileProvider.List(ctx, s.documentPrefix(appName, userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	matches := make([]DocumentMatch, 0)
+	for _, path := range paths {
+		if !strings.HasSuffix(path, ".json") {
+			continue
+		}
+		doc, err := s.loadDocument(ctx, path)
+		if err != nil {
+			s.log.Debug("Failed to load document",
+				logger.StringField("path", path),
+				logger.ErrorField(err))
+			continue
+		}
+		for _, chunk := range doc.Chunks {
+			score := cosineSimilarity(queryVec, chunk.Embedding)
+			if score <= 0 {
+				continue
+			}
+			matches = append(matches, DocumentMatch{
+				DocumentID:   doc.ID,
+				DocumentName: doc.Name,
+				ChunkIndex:   chunk.Index,
+				Text:         chunk.Text,
+				Score:        score,
+				Metadata:     doc.Metadata,
+			})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches, nil
+}
+
+// loadDocument reads and decodes a stored document.
+func (s *Service) loadDocument(ctx context.Context, path string) (*DocumentData, error) {
+	data, err := s.fileProvider.Read(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc DocumentData
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal document: %w", err)
+	}
+
+	return &doc, nil
+}
+
+// documentPrefix returns the storage prefix for a user's documents.
+func (s *Service) documentPrefix(appName, userID string) string {
+	return fmt.Sprintf("documents/%s/%s/", appName, userID)
+}
+
+// documentPath returns the storage path for a document.
+func (s *Service) documentPath(appName, userID, docID string) string {
+	return s.documentPrefix(appName, userID) + docID + ".json"
+}
+
+// documentID derives a storage-safe identifier from a document name.
+func documentID(name string) string {
+	id := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return unicode.ToLower(r)
+		default:
+			return '-'
+		}
+	}, name)
+	id = strings.Trim(id, "-.")
+	if id == "" {
+		return "document"
+	}
+	return id
+}
+
+// chunkText splits text into chunks of roughly size characters with the given overlap,
+// preferring to break on whitespace near the end of each chunk.
+func chunkText(text string, size, overlap int) []string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) == 0 {
+		return nil
+	}
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+
+	var chunks []string
+	for start := 0; start < len(runes); {
+		end := start + size
+		if end >= len(runes) {
+			chunks = append(chunks, strings.TrimSpace(string(runes[start:])))
+			break
+		}
+
+		// Prefer to break at whitespace in the last quarter of the chunk
+		for i := end; i > start+size*3/4; i-- {
+			if unicode.IsSpace(runes[i]) {
+				end = i
+				break
+			}
+		}
+		chunks = append(chunks, strings.TrimSpace(string(runes[start:end])))
+
+		next := end - overlap
+		if next <= start {
+			next = end
+		}
+		start = next
+	}
+
+	return chunks
+}
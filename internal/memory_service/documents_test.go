@@ -0,0 +1,212 @@
+package memory_service //nolint:revive // var-naming: using underscores for domain clarity
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/tool"
+)
+
+// fakeEmbedder maps each text onto a fixed set of keyword dimensions.
+type fakeEmbedder struct {
+	keywords []string
+}
+
+func (e *fakeEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vec := make([]float32, len(e.keywords))
+		lower := strings.ToLower(text)
+		for j, keyword := range e.keywords {
+			if strings.Contains(lower, keyword) {
+				vec[j] = 1
+			}
+		}
+		vectors[i] = vec
+	}
+	return vectors, nil
+}
+
+// fakeToolContext provides the app and user scope the search_documents tool reads.
+type fakeToolContext struct {
+	tool.Context
+	ctx     context.Context
+	appName string
+	userID  string
+}
+
+func (c *fakeToolContext) AppName() string             { return c.appName }
+func (c *fakeToolContext) UserID() string              { return c.userID }
+func (c *fakeToolContext) Deadline() (time.Time, bool) { return c.ctx.Deadline() }
+func (c *fakeToolContext) Done() <-chan struct{}       { return c.ctx.Done() }
+func (c *fakeToolContext) Err() error                  { return c.ctx.Err() }
+func (c *fakeToolContext) Value(key any) any           { return c.ctx.Value(key) }
+
+func newDocumentTestService(t *testing.T) *Service {
+	t.Helper()
+	overlap := 10
+	return New(Config{
+		FileProvider: storage_manager.NewLocalFileProvider(t.TempDir()),
+		Logger:       newTestLogger(),
+		Embedder:     &fakeEmbedder{keywords: []string{"database", "deploy", "holiday"}},
+		ChunkSize:    60,
+		ChunkOverlap: &overlap,
+	})
+}
+
+const testDocument = "Our database runs on Postgres and is backed up nightly. " +
+	"To deploy the service, merge to main and wait for the pipeline. " +
+	"Holiday requests go through the HR portal."
+
+func TestIngestDocumentAndSearch(t *testing.T) {
+	svc := newDocumentTestService(t)
+	ctx := context.Background()
+
+	count, err := svc.IngestDocument(ctx, "testapp", "user1", "Handbook.md", testDocument, map[string]string{"source": "slack"})
+	require.NoError(t, err)
+	assert.Greater(t, count, 1, "expected document to be split into multiple chunks")
+
+	matches, err := svc.SearchDocuments(ctx, "testapp", "user1", "how do I deploy?", 1)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Contains(t, matches[0].Text, "deploy")
+	assert.Equal(t, "Handbook.md", matches[0].DocumentName)
+	assert.Equal(t, "handbook.md", matches[0].DocumentID)
+	assert.Equal(t, "slack", matches[0].Metadata["source"])
+}
+
+func TestSearchDocuments_ScopedPerUser(t *testing.T) {
+	svc := newDocumentTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.IngestDocument(ctx, "testapp", "user1", "handbook.md", testDocument, nil)
+	require.NoError(t, err)
+
+	matches, err := svc.SearchDocuments(ctx, "testapp", "user2", "database", 5)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	matches, err = svc.SearchDocuments(ctx, "otherapp", "user1", "database", 5)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestIngestDocument_ReplacesSameName(t *testing.T) {
+	svc := newDocumentTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.IngestDocument(ctx, "testapp", "user1", "notes.txt", "The database is MySQL.", nil)
+	require.NoError(t, err)
+	_, err = svc.IngestDocument(ctx, "testapp", "user1", "notes.txt", "The database is Postgres.", nil)
+	require.NoError(t, err)
+
+	matches, err := svc.SearchDocuments(ctx, "testapp", "user1", "database", 5)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Contains(t, matches[0].Text, "Postgres")
+}
+
+func TestIngestDocument_Validation(t *testing.T) {
+	svc := newDocumentTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.IngestDocument(ctx, "", "user1", "doc.txt", "content", nil)
+	assert.Error(t, err)
+
+	_, err = svc.IngestDocument(ctx, "testapp", "user1", "", "content", nil)
+	assert.Error(t, err)
+
+	_, err = svc.IngestDocument(ctx, "testapp", "user1", "doc.txt", "   ", nil)
+	assert.Error(t, err)
+}
+
+func TestSearchDocumentsTool(t *testing.T) {
+	svc := newDocumentTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.IngestDocument(ctx, "testapp", "user1", "handbook.md", testDocument, nil)
+	require.NoError(t, err)
+
+	tools, err := svc.DocumentTools()
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "search_documents", tools[0].Name())
+
+	runner, ok := tools[0].(interface {
+		Run(tool.Context, any) (map[string]any, error)
+	})
+	require.True(t, ok, "expected tool to be runnable")
+
+	toolCtx := &fakeToolContext{ctx: ctx, appName: "testapp", userID: "user1"}
+	result, err := runner.Run(toolCtx, map[string]any{"query": "holiday", "limit": 1})
+	require.NoError(t, err)
+
+	results, ok := result["results"].([]any)
+	require.True(t, ok, "expected results list, got %T", result["results"])
+	require.Len(t, results, 1)
+	chunk, ok := results[0].(map[string]any)
+	require.True(t, ok)
+	assert.Contains(t, chunk["text"], "Holiday")
+}
+
+func TestSearchDocumentsTool_DocumentsUser(t *testing.T) {
+	svc := newDocumentTestService(t)
+	ctx := context.Background()
+
+	// Documents are ingested for the user; the conversation is a thread shared with others
+	_, err := svc.IngestDocument(ctx, "testapp", "T1:U1", "handbook.md", testDocument, nil)
+	require.NoError(t, err)
+
+	tools, err := svc.DocumentTools()
+	require.NoError(t, err)
+	runner := tools[0].(interface {
+		Run(tool.Context, any) (map[string]any, error)
+	})
+
+	search := func(ctx context.Context) []any {
+		t.Helper()
+		toolCtx := &fakeToolContext{ctx: ctx, appName: "testapp", userID: "thread:T1:C1:1700000000.000100"}
+		result, err := runner.Run(toolCtx, map[string]any{"query": "holiday"})
+		require.NoError(t, err)
+		results, _ := result["results"].([]any)
+		return results
+	}
+
+	assert.Empty(t, search(ctx), "the thread has no documents of its own")
+	assert.NotEmpty(t, search(WithDocumentsUser(ctx, "T1:U1")), "the sender's documents should be searched")
+}
+
+func TestNew_ChunkOverlap(t *testing.T) {
+	newService := func(overlap *int) *Service {
+		return New(Config{FileProvider: storage_manager.NewLocalFileProvider(t.TempDir()), Logger: newTestLogger(), ChunkOverlap: overlap})
+	}
+	off := 0
+
+	assert.Equal(t, DefaultChunkOverlap, newService(nil).chunkOverlap, "unset overlap should use the default")
+	assert.Equal(t, 0, newService(&off).chunkOverlap, "an overlap of 0 should turn it off")
+}
+
+func TestChunkText(t *testing.T) {
+	assert.Nil(t, chunkText("   ", 10, 2))
+	assert.Equal(t, []string{"short"}, chunkText("short", 10, 2))
+
+	chunks := chunkText(strings.Repeat("word ", 50), 40, 10)
+	assert.Greater(t, len(chunks), 1)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk), 40)
+	}
+}
+
+func TestHashEmbedder(t *testing.T) {
+	embedder := NewHashEmbedder(64)
+	vectors, err := embedder.Embed(context.Background(), []string{"deploy the service", "deploy service", "holiday"})
+	require.NoError(t, err)
+	require.Len(t, vectors, 3)
+
+	assert.Greater(t, cosineSimilarity(vectors[0], vectors[1]), cosineSimilarity(vectors[0], vectors[2]))
+}
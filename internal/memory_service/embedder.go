@@ -0,0 +1,80 @@
+package memory_service //nolint:revive // var-naming: using underscores for domain clarity
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+)
+
+// DefaultEmbeddingDimensions is the vector size produced by HashEmbedder.
+const DefaultEmbeddingDimensions = 512
+
+// Embedder converts text into vectors used for document similarity search.
+// Implementations must return one vector per input text, all of the same length.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// HashEmbedder is a dependency-free Embedder that hashes words into a fixed-size,
+// L2-normalised bag-of-words vector. It needs no external API, at the cost of
+// only capturing lexical overlap rather than meaning.
+type HashEmbedder struct {
+	dimensions int
+}
+
+// NewHashEmbedder creates a HashEmbedder producing vectors of the given size.
+func NewHashEmbedder(dimensions int) *HashEmbedder {
+	if dimensions <= 0 {
+		dimensions = DefaultEmbeddingDimensions
+	}
+	return &HashEmbedder{dimensions: dimensions}
+}
+
+// Embed returns a hashed bag-of-words vector for each text.
+func (e *HashEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vec := make([]float32, e.dimensions)
+		for word := range extractWords(text) {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(word))
+			vec[h.Sum32()%uint32(e.dimensions)]++ //nolint:gosec // G115: dimensions is always positive
+		}
+		normalise(vec)
+		vectors[i] = vec
+	}
+	return vectors, nil
+}
+
+// normalise scales vec to unit length in place.
+func normalise(vec []float32) {
+	var sum float64
+	for _, v := range vec {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range vec {
+		vec[i] /= norm
+	}
+}
+
+// cosineSimilarity returns the cosine similarity of two vectors, or 0 if their
+// lengths differ or either is zero.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
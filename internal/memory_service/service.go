@@ -19,12 +19,18 @@ type Service struct {
 	userLocks    map[string]*sync.Mutex // Per-user locks
 	userLockMux  sync.Mutex
 	log          logger.Logger
+	embedder     Embedder
+	chunkSize    int
+	chunkOverlap int
 }
 
 // Config holds configuration for the memory service.
 type Config struct {
 	FileProvider storage_manager.FileProvider
 	Logger       logger.Logger
+	Embedder     Embedder // Optional: defaults to a HashEmbedder
+	ChunkSize    int      // Optional: document chunk size in characters
+	ChunkOverlap *int     // Optional: overlap between document chunks in characters; 0 turns it off
 }
 
 // New creates a new memory service with the given configuration.
@@ -36,10 +42,24 @@ func New(cfg Config) *Service {
 		panic("logger cannot be nil")
 	}
 
+	if cfg.Embedder == nil {
+		cfg.Embedder = NewHashEmbedder(DefaultEmbeddingDimensions)
+	}
+	if cfg.ChunkSize <= 0 {
+		cfg.ChunkSize = DefaultChunkSize
+	}
+	chunkOverlap := DefaultChunkOverlap
+	if cfg.ChunkOverlap != nil {
+		chunkOverlap = max(*cfg.ChunkOverlap, 0)
+	}
+
 	return &Service{
 		fileProvider: cfg.FileProvider,
 		userLocks:    make(map[string]*sync.Mutex),
 		log:          cfg.Logger,
+		embedder:     cfg.Embedder,
+		chunkSize:    cfg.ChunkSize,
+		chunkOverlap: chunkOverlap,
 	}
 }
 
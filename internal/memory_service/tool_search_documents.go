@@ -0,0 +1,65 @@
+package memory_service //nolint:revive // var-naming: using underscores for domain clarity
+
+import (
+	"context"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// SearchDocumentsArgs represents the arguments for the search_documents tool.
+type SearchDocumentsArgs struct {
+	Query string `json:"query" jsonschema:"What to look for in the user's uploaded documents"`
+	Limit int    `json:"limit,omitempty" jsonschema:"Maximum number of passages to return (default: 5, max: 20)"`
+}
+
+// SearchDocumentsResult represents the result of the search_documents tool.
+type SearchDocumentsResult struct {
+	Query   string          `json:"query"`
+	Results []DocumentMatch `json:"results"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// documentsUserKey is the context key for the user whose documents are searched
+type documentsUserKey struct{}
+
+// WithDocumentsUser returns a context whose search_documents calls search userID's
+// documents instead of the session user's. Conversations shared by several users, such as
+// channel threads, are stored under a key of their own, while documents are ingested for
+// the user who uploaded them.
+func WithDocumentsUser(ctx context.Context, userID string) context.Context {
+	if userID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, documentsUserKey{}, userID)
+}
+
+// documentsUser returns the user whose documents a tool call searches
+func documentsUser(ctx tool.Context) string {
+	if userID, ok := ctx.Value(documentsUserKey{}).(string); ok {
+		return userID
+	}
+	return ctx.UserID()
+}
+
+func (s *Service) createSearchDocumentsTool() (tool.Tool, error) {
+	return functiontool.New(functiontool.Config{
+		Name:        "search_documents",
+		Description: "Search documents the user has uploaded and return the most relevant passages to answer from.",
+	}, func(ctx tool.Context, args SearchDocumentsArgs) (SearchDocumentsResult, error) {
+		matches, err := s.SearchDocuments(ctx, ctx.AppName(), documentsUser(ctx), args.Query, args.Limit)
+		if err != nil {
+			return SearchDocumentsResult{Query: args.Query, Results: []DocumentMatch{}, Error: err.Error()}, nil
+		}
+		return SearchDocumentsResult{Query: args.Query, Results: matches}, nil
+	})
+}
+
+// DocumentTools returns the ADK tools for searching ingested documents.
+func (s *Service) DocumentTools() ([]tool.Tool, error) {
+	searchTool, err := s.createSearchDocumentsTool()
+	if err != nil {
+		return nil, err
+	}
+	return []tool.Tool{searchTool}, nil
+}
@@ -40,3 +40,31 @@ type WordIndex struct {
 	UpdatedAt time.Time           `json:"updated_at"`
 	Words     map[string][]string `json:"words"` // word -> []sessionID
 }
+
+// DocumentData represents an ingested document split into embedded chunks.
+type DocumentData struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	AppName   string            `json:"app_name"`
+	UserID    string            `json:"user_id"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	Chunks    []DocumentChunk   `json:"chunks"`
+}
+
+// DocumentChunk is a contiguous slice of a document with its embedding.
+type DocumentChunk struct {
+	Index     int       `json:"index"`
+	Text      string    `json:"text"`
+	Embedding []float32 `json:"embedding"`
+}
+
+// DocumentMatch is a document chunk returned from a similarity search.
+type DocumentMatch struct {
+	DocumentID   string            `json:"document_id"`
+	DocumentName string            `json:"document_name"`
+	ChunkIndex   int               `json:"chunk_index"`
+	Text         string            `json:"text"`
+	Score        float64           `json:"score"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+}
@@ -0,0 +1,57 @@
+package anthropic
+
+import (
+	"log/slog"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// applyCacheControl marks the stable prefix of a request for prompt caching: the tool
+// definitions, the system prompt and the conversation up to the latest user message.
+// Anthropic caches the prompt up to each marker (tools, then system, then messages), so a
+// system prompt repeated across requests is read from the cache at a fraction of the input
+// price instead of being processed again.
+func applyCacheControl(params *anthropic.MessageNewParams) {
+	if n := len(params.Tools); n > 0 && params.Tools[n-1].OfTool != nil {
+		params.Tools[n-1].OfTool.CacheControl = anthropic.NewCacheControlEphemeralParam()
+	}
+
+	if n := len(params.System); n > 0 {
+		params.System[n-1].CacheControl = anthropic.NewCacheControlEphemeralParam()
+	}
+
+	for i := len(params.Messages) - 1; i >= 0; i-- {
+		msg := params.Messages[i]
+		if msg.Role != anthropic.MessageParamRoleUser {
+			continue
+		}
+		if len(msg.Content) > 0 {
+			last := msg.Content[len(msg.Content)-1]
+			cacheControl := anthropic.NewCacheControlEphemeralParam()
+			switch {
+			case last.OfText != nil:
+				last.OfText.CacheControl = cacheControl
+			case last.OfImage != nil:
+				last.OfImage.CacheControl = cacheControl
+			case last.OfToolResult != nil:
+				last.OfToolResult.CacheControl = cacheControl
+			}
+		}
+		break
+	}
+}
+
+// logCacheUsage logs whether a request's prompt was read from the prompt cache or written
+// to it
+func (c *ClaudeModel) logCacheUsage(usage anthropic.Usage) {
+	result := "miss"
+	if usage.CacheReadInputTokens > 0 {
+		result = "hit"
+	}
+	c.logger.Debug("prompt cache "+result,
+		slog.String("model", c.modelName),
+		slog.Int64("cache_read_input_tokens", usage.CacheReadInputTokens),
+		slog.Int64("cache_creation_input_tokens", usage.CacheCreationInputTokens),
+		slog.Int64("input_tokens", usage.InputTokens),
+	)
+}
@@ -2,12 +2,16 @@ package anthropic
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"iter"
 	"log/slog"
+	"net/http"
+	"time"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/models"
 	"google.golang.org/adk/model"
 )
 
@@ -16,10 +20,102 @@ type ClaudeModel struct {
 	client    *anthropic.Client
 	modelName string
 	logger    *slog.Logger
+	params    models.Params
+	// thinkingBudget enables extended thinking with this many tokens when > 0
+	thinkingBudget int64
+	// maxInputTokens is the estimated input size history is truncated to
+	maxInputTokens int
+	// clientOptions configure the API client, e.g. its HTTP client
+	clientOptions []option.RequestOption
+	// timeout bounds each API call attempt when > 0
+	timeout time.Duration
+	// retry replaces the client's built-in retries when set
+	retry *models.RetryConfig
+	// promptCaching marks the tools, system prompt and history for prompt caching
+	promptCaching bool
+}
+
+// Extended thinking limits
+const (
+	// MinThinkingBudget is the smallest thinking budget the Anthropic API accepts
+	MinThinkingBudget = 1024
+	// minThinkingTopP is the lowest top_p the Anthropic API accepts alongside extended thinking
+	minThinkingTopP = 0.95
+)
+
+// Option configures optional ClaudeModel settings.
+type Option func(*ClaudeModel)
+
+// WithParams sets default generation parameters applied to every request.
+// Params.Seed is ignored because the Anthropic API has no seed parameter.
+func WithParams(params models.Params) Option {
+	return func(c *ClaudeModel) {
+		c.params = params
+	}
+}
+
+// WithThinking enables extended thinking with the given token budget.
+// Thinking is returned as thought parts (never as answer text) so it can be passed
+// back to the API during tool use; temperature and top_k are not sent while it is enabled.
+func WithThinking(budgetTokens int) Option {
+	return func(c *ClaudeModel) {
+		c.thinkingBudget = int64(budgetTokens)
+	}
+}
+
+// WithMaxInputTokens sets the estimated token budget for the request input (system prompt,
+// tools and history); the oldest messages are dropped to fit. Values of 0 or less use
+// DefaultMaxInputTokens.
+func WithMaxInputTokens(maxTokens int) Option {
+	return func(c *ClaudeModel) {
+		if maxTokens > 0 {
+			c.maxInputTokens = maxTokens
+		}
+	}
+}
+
+// WithHTTPClient sets the HTTP client API requests are sent with, e.g. one using a proxy.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *ClaudeModel) {
+		c.clientOptions = append(c.clientOptions, option.WithHTTPClient(client))
+	}
+}
+
+// WithBaseURL sends API requests to baseURL instead of https://api.anthropic.com, e.g. an
+// Anthropic-compatible gateway such as LiteLLM. Empty keeps the default.
+func WithBaseURL(baseURL string) Option {
+	return func(c *ClaudeModel) {
+		if baseURL != "" {
+			c.clientOptions = append(c.clientOptions, option.WithBaseURL(baseURL))
+		}
+	}
+}
+
+// WithTimeout bounds each API call attempt; a timed-out attempt is retried like a failed one.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *ClaudeModel) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetry retries failed API calls (rate limits, overload, server and network errors and
+// timeouts) per cfg instead of with the client's built-in retry policy.
+func WithRetry(cfg models.RetryConfig) Option {
+	return func(c *ClaudeModel) {
+		c.retry = &cfg
+	}
+}
+
+// WithPromptCaching turns prompt caching of the tools, system prompt and conversation
+// history on or off. It's on by default.
+func WithPromptCaching(enabled bool) Option {
+	return func(c *ClaudeModel) {
+		c.promptCaching = enabled
+	}
 }
 
 // NewClaudeModel creates a new Claude model instance.
-func NewClaudeModel(apiKey, modelName string) (*ClaudeModel, error) {
+func NewClaudeModel(apiKey, modelName string, opts ...Option) (*ClaudeModel, error) {
 	if apiKey == "" {
 		return nil, fmt.Errorf("API key is required")
 	}
@@ -27,13 +123,28 @@ func NewClaudeModel(apiKey, modelName string) (*ClaudeModel, error) {
 		return nil, fmt.Errorf("model name is required")
 	}
 
-	client := anthropic.NewClient(option.WithAPIKey(apiKey))
+	m := &ClaudeModel{
+		modelName:      modelName,
+		logger:         slog.Default(),
+		maxInputTokens: DefaultMaxInputTokens,
+		promptCaching:  true,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.retry != nil {
+		m.clientOptions = append(m.clientOptions, option.WithMaxRetries(0))
+	}
+	client := anthropic.NewClient(append([]option.RequestOption{option.WithAPIKey(apiKey)}, m.clientOptions...)...)
+	m.client = &client
+	if m.params.Seed != nil {
+		m.logger.Warn("seed is not supported by the Anthropic API and will be ignored")
+	}
+	if m.thinkingBudget > 0 && m.thinkingBudget < MinThinkingBudget {
+		return nil, fmt.Errorf("thinking budget must be at least %d tokens", MinThinkingBudget)
+	}
 
-	return &ClaudeModel{
-		client:    &client,
-		modelName: modelName,
-		logger:    slog.Default(),
-	}, nil
+	return m, nil
 }
 
 // Name returns the model name.
@@ -59,6 +170,9 @@ func (c *ClaudeModel) GenerateContent(ctx context.Context, req *model.LLMRequest
 //
 //nolint:gocyclo,revive // API integration requires handling many response conditions
 func (c *ClaudeModel) generateContentNonStreaming(ctx context.Context, req *model.LLMRequest) (*model.LLMResponse, error) {
+	// Fill in configured defaults for anything the request leaves unset
+	c.params.Apply(req)
+
 	// Transform ADK request to Anthropic format
 	messages, systemBlocks, err := transformADKToAnthropic(req.Contents)
 	if err != nil {
@@ -77,13 +191,6 @@ func (c *ClaudeModel) generateContentNonStreaming(ctx context.Context, req *mode
 		}
 	}
 
-	// Add cache control to the last system block if we have any
-	if len(systemBlocks) > 0 {
-		lastIdx := len(systemBlocks) - 1
-		cacheControl := anthropic.NewCacheControlEphemeralParam()
-		systemBlocks[lastIdx].CacheControl = cacheControl
-	}
-
 	// Determine max tokens - default to 4096 if not specified
 	maxTokens := int64(4096)
 	if req.Config != nil && req.Config.MaxOutputTokens > 0 {
@@ -102,18 +209,27 @@ func (c *ClaudeModel) generateContentNonStreaming(ctx context.Context, req *mode
 		params.System = systemBlocks
 	}
 
-	// Add temperature if specified
-	if req.Config != nil && req.Config.Temperature != nil {
+	// Enable extended thinking if configured; max_tokens must exceed the budget
+	thinking := c.thinkingBudget > 0
+	if thinking {
+		params.Thinking = anthropic.ThinkingConfigParamOfEnabled(c.thinkingBudget)
+		if params.MaxTokens <= c.thinkingBudget {
+			params.MaxTokens = c.thinkingBudget + maxTokens
+		}
+	}
+
+	// Add temperature if specified (not supported with extended thinking)
+	if req.Config != nil && req.Config.Temperature != nil && !thinking {
 		params.Temperature = anthropic.Float(float64(*req.Config.Temperature))
 	}
 
-	// Add top_p if specified
-	if req.Config != nil && req.Config.TopP != nil {
+	// Add top_p if specified (extended thinking only accepts values >= 0.95)
+	if req.Config != nil && req.Config.TopP != nil && (!thinking || *req.Config.TopP >= minThinkingTopP) {
 		params.TopP = anthropic.Float(float64(*req.Config.TopP))
 	}
 
-	// Add top_k if specified
-	if req.Config != nil && req.Config.TopK != nil {
+	// Add top_k if specified (not supported with extended thinking)
+	if req.Config != nil && req.Config.TopK != nil && !thinking {
 		params.TopK = anthropic.Int(int64(*req.Config.TopK))
 	}
 
@@ -140,7 +256,7 @@ func (c *ClaudeModel) generateContentNonStreaming(ctx context.Context, req *mode
 	}
 
 	// Truncate oldest messages if the conversation would exceed the context window
-	truncatedMessages, removedCount := truncateMessages(params.Messages, fixedOverhead)
+	truncatedMessages, removedCount := truncateMessagesToBudget(params.Messages, fixedOverhead, c.maxInputTokens)
 	if removedCount > 0 {
 		c.logger.Info("truncated conversation history to fit context window",
 			slog.Int("original_messages", len(params.Messages)),
@@ -150,17 +266,74 @@ func (c *ClaudeModel) generateContentNonStreaming(ctx context.Context, req *mode
 		params.Messages = truncatedMessages
 	}
 
+	if c.promptCaching {
+		applyCacheControl(&params)
+	}
+
 	// Make the API call
-	msg, err := c.client.Messages.New(ctx, params)
+	msg, err := c.sendMessage(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("anthropic API error: %w", err)
 	}
+	if c.promptCaching {
+		c.logCacheUsage(msg.Usage)
+	}
+
+	// Thinking is kept out of the answer text, but is useful when debugging
+	for _, block := range msg.Content {
+		if block.Type == "thinking" {
+			c.logger.Debug("model thinking", slog.String("thinking", block.Thinking))
+		}
+	}
 
 	// Transform the response
 	response, err := transformAnthropicToADK(msg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to transform response: %w", err)
 	}
+	if removedCount > 0 {
+		if response.CustomMetadata == nil {
+			response.CustomMetadata = make(map[string]any)
+		}
+		response.CustomMetadata[models.TruncatedMessagesKey] = removedCount
+	}
 
 	return response, nil
 }
+
+// sendMessage makes the API call, retrying and timing out attempts as configured
+func (c *ClaudeModel) sendMessage(ctx context.Context, params anthropic.MessageNewParams) (*anthropic.Message, error) {
+	var msg *anthropic.Message
+	attempt := func(ctx context.Context) error {
+		if c.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+		}
+		var err error
+		msg, err = c.client.Messages.New(ctx, params)
+		return err
+	}
+
+	var err error
+	if c.retry == nil {
+		err = attempt(ctx)
+	} else {
+		err = models.Retry(ctx, *c.retry, isRetryable, attempt)
+	}
+	return msg, err
+}
+
+// isRetryable reports whether a failed API call may succeed if retried: request timeouts,
+// conflicts, rate limits and server errors, or failures to get a response at all
+func isRetryable(err error) bool {
+	var apiErr *anthropic.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+			return true
+		}
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
@@ -2,9 +2,20 @@ package anthropic
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/models"
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
 )
@@ -179,35 +190,6 @@ func TestTransformADKToAnthropic(t *testing.T) {
 			if len(sysBlocks) != tt.wantSysBlocks {
 				t.Errorf("transformADKToAnthropic() system blocks count = %v, want %v", len(sysBlocks), tt.wantSysBlocks)
 			}
-
-			// Verify cache control on last system block if present
-			if len(sysBlocks) > 0 {
-				lastBlock := sysBlocks[len(sysBlocks)-1]
-				// Check that cache control has been set (non-zero value)
-				emptyCC := anthropic.CacheControlEphemeralParam{}
-				if lastBlock.CacheControl == emptyCC {
-					t.Error("transformADKToAnthropic() last system block should have cache control")
-				}
-			}
-
-			// Verify cache control on last user message if present
-			hasUserMsg := false
-			for i := len(msgs) - 1; i >= 0; i-- {
-				if msgs[i].Role == anthropic.MessageParamRoleUser && len(msgs[i].Content) > 0 {
-					hasUserMsg = true
-					lastBlock := msgs[i].Content[len(msgs[i].Content)-1]
-					emptyCC := anthropic.CacheControlEphemeralParam{}
-					// Check if any block type has cache control set (non-zero value)
-					hasCacheControl := (lastBlock.OfText != nil && lastBlock.OfText.CacheControl != emptyCC) ||
-						(lastBlock.OfImage != nil && lastBlock.OfImage.CacheControl != emptyCC) ||
-						(lastBlock.OfToolResult != nil && lastBlock.OfToolResult.CacheControl != emptyCC)
-					if !hasCacheControl {
-						t.Error("transformADKToAnthropic() last user message should have cache control on last block")
-					}
-					break
-				}
-			}
-			_ = hasUserMsg // Used in check above
 		})
 	}
 }
@@ -469,13 +451,405 @@ func TestTransformToolsToAnthropic(t *testing.T) {
 				t.Errorf("transformToolsToAnthropic() count = %v, want %v", len(tools), tt.wantCount)
 			}
 
-			// Verify cache control on last tool if present
-			if len(tools) > 0 {
-				lastTool := tools[len(tools)-1]
-				emptyCC := anthropic.CacheControlEphemeralParam{}
-				if lastTool.OfTool != nil && lastTool.OfTool.CacheControl == emptyCC {
-					t.Error("transformToolsToAnthropic() last tool should have cache control")
+			// Tools are sorted by name so the definitions can be cached
+			for i := 1; i < len(tools); i++ {
+				if tools[i-1].OfTool.Name > tools[i].OfTool.Name {
+					t.Errorf("transformToolsToAnthropic() tools not sorted by name: %q before %q", tools[i-1].OfTool.Name, tools[i].OfTool.Name)
+				}
+			}
+		})
+	}
+}
+
+// newTestClaudeModel returns a ClaudeModel whose client talks to a test server that
+// records the request body and replies with a minimal text message.
+func newTestClaudeModel(t *testing.T, captured *map[string]any, opts ...Option) *ClaudeModel {
+	t.Helper()
+	return newTestClaudeModelWithResponse(t, captured, `{"id":"msg_1","type":"message","role":"assistant","model":"claude-test",`+
+		`"content":[{"type":"text","text":"hi"}],"stop_reason":"end_turn","usage":{"input_tokens":1,"output_tokens":1}}`, opts...)
+}
+
+// newTestClaudeModelWithResponse returns a ClaudeModel whose API replies with responseBody
+func newTestClaudeModelWithResponse(t *testing.T, captured *map[string]any, responseBody string, opts ...Option) *ClaudeModel {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(captured); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(responseBody))
+	}))
+	t.Cleanup(server.Close)
+
+	client := anthropic.NewClient(
+		option.WithAPIKey("test-api-key"),
+		option.WithBaseURL(server.URL),
+		option.WithMaxRetries(0),
+	)
+	m := &ClaudeModel{
+		client:    &client,
+		modelName: "claude-test",
+		logger:    slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func TestClaudeModel_GenerateContent_AppliesParams(t *testing.T) {
+	temperature := 0.0
+	topP := 0.9
+
+	tests := []struct {
+		name            string
+		config          *genai.GenerateContentConfig
+		wantTemperature float64
+		wantMaxTokens   float64
+		wantTopP        float64
+	}{
+		{
+			name:            "defaults applied to request without config",
+			config:          nil,
+			wantTemperature: 0,
+			wantMaxTokens:   1024,
+			wantTopP:        0.9,
+		},
+		{
+			name: "request values take precedence",
+			config: &genai.GenerateContentConfig{
+				Temperature:     genai.Ptr(float32(0.5)),
+				MaxOutputTokens: 256,
+			},
+			wantTemperature: 0.5,
+			wantMaxTokens:   256,
+			wantTopP:        0.9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]any
+			m := newTestClaudeModel(t, &body, WithParams(models.Params{
+				Temperature:   &temperature,
+				MaxTokens:     1024,
+				TopP:          &topP,
+				StopSequences: []string{"END"},
+			}))
+
+			req := &model.LLMRequest{
+				Contents: []*genai.Content{genai.NewContentFromText("hello", genai.RoleUser)},
+				Config:   tt.config,
+			}
+			for _, err := range m.GenerateContent(context.Background(), req, false) {
+				if err != nil {
+					t.Fatalf("GenerateContent() error = %v", err)
+				}
+			}
+
+			if body["temperature"] != tt.wantTemperature {
+				t.Errorf("temperature = %v, want %v", body["temperature"], tt.wantTemperature)
+			}
+			if body["max_tokens"] != tt.wantMaxTokens {
+				t.Errorf("max_tokens = %v, want %v", body["max_tokens"], tt.wantMaxTokens)
+			}
+			if got, ok := body["top_p"].(float64); !ok || math.Abs(got-tt.wantTopP) > 1e-6 {
+				t.Errorf("top_p = %v, want %v", body["top_p"], tt.wantTopP)
+			}
+			if stops, ok := body["stop_sequences"].([]any); !ok || len(stops) != 1 || stops[0] != "END" {
+				t.Errorf("stop_sequences = %v, want [END]", body["stop_sequences"])
+			}
+		})
+	}
+}
+
+func TestClaudeModel_GenerateContent_Thinking(t *testing.T) {
+	temperature := 0.5
+	response := `{"id":"msg_1","type":"message","role":"assistant","model":"claude-test","content":[` +
+		`{"type":"thinking","thinking":"let me think","signature":"sig_1"},` +
+		`{"type":"text","text":"the answer"}],"stop_reason":"end_turn","usage":{"input_tokens":1,"output_tokens":1}}`
+
+	var body map[string]any
+	m := newTestClaudeModelWithResponse(t, &body, response,
+		WithParams(models.Params{Temperature: &temperature, MaxTokens: 1024}),
+		WithThinking(2048),
+	)
+
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{genai.NewContentFromText("hello", genai.RoleUser)},
+	}
+	var resp *model.LLMResponse
+	for r, err := range m.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		resp = r
+	}
+
+	thinking, ok := body["thinking"].(map[string]any)
+	if !ok {
+		t.Fatalf("thinking not set in request: %v", body)
+	}
+	if thinking["type"] != "enabled" || thinking["budget_tokens"] != float64(2048) {
+		t.Errorf("thinking = %v, want enabled with budget 2048", thinking)
+	}
+	if got := body["max_tokens"].(float64); got <= 2048 {
+		t.Errorf("max_tokens = %v, want greater than the thinking budget", got)
+	}
+	if _, ok := body["temperature"]; ok {
+		t.Errorf("temperature should not be sent with thinking enabled, got %v", body["temperature"])
+	}
+
+	if len(resp.Content.Parts) != 2 {
+		t.Fatalf("got %d parts, want 2", len(resp.Content.Parts))
+	}
+	thought := resp.Content.Parts[0]
+	if !thought.Thought || thought.Text != "let me think" || string(thought.ThoughtSignature) != "sig_1" {
+		t.Errorf("thought part = %+v, want signed thought", thought)
+	}
+	if answer := resp.Content.Parts[1]; answer.Thought || answer.Text != "the answer" {
+		t.Errorf("answer part = %+v, want plain text", answer)
+	}
+}
+
+func TestClaudeModel_GenerateContent_ThinkingDisabled(t *testing.T) {
+	var body map[string]any
+	m := newTestClaudeModel(t, &body)
+
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{genai.NewContentFromText("hello", genai.RoleUser)},
+	}
+	for _, err := range m.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+	}
+
+	if _, ok := body["thinking"]; ok {
+		t.Errorf("thinking should not be sent when disabled, got %v", body["thinking"])
+	}
+}
+
+func TestClaudeModel_GenerateContent_PromptCaching(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("enabled=%v", enabled), func(t *testing.T) {
+			var body map[string]any
+			m := newTestClaudeModel(t, &body, WithPromptCaching(enabled))
+
+			req := &model.LLMRequest{
+				Contents: []*genai.Content{
+					genai.NewContentFromText("hello", genai.RoleUser),
+					genai.NewContentFromText("hi", genai.RoleModel),
+					genai.NewContentFromText("what can you do?", genai.RoleUser),
+				},
+				Config: &genai.GenerateContentConfig{
+					SystemInstruction: genai.NewContentFromText("You are a helpful assistant.", genai.RoleUser),
+				},
+				Tools: map[string]any{
+					"search":      &mockTool{decl: &genai.FunctionDeclaration{Name: "search"}},
+					"get_weather": &mockTool{decl: &genai.FunctionDeclaration{Name: "get_weather"}},
+				},
+			}
+			for _, err := range m.GenerateContent(context.Background(), req, false) {
+				if err != nil {
+					t.Fatalf("GenerateContent() error = %v", err)
+				}
+			}
+
+			marked := func(block any) bool {
+				fields, _ := block.(map[string]any)
+				cacheControl, _ := fields["cache_control"].(map[string]any)
+				return cacheControl["type"] == "ephemeral"
+			}
+			last := func(key string) any {
+				list, _ := body[key].([]any)
+				if len(list) == 0 {
+					t.Fatalf("request has no %s", key)
 				}
+				return list[len(list)-1]
+			}
+
+			if got := marked(last("system")); got != enabled {
+				t.Errorf("system prompt cache_control set = %v, want %v", got, enabled)
+			}
+			if tool := last("tools"); marked(tool) != enabled || tool.(map[string]any)["name"] != "search" {
+				t.Errorf("last tool = %v, want search with cache_control set = %v", tool, enabled)
+			}
+			content, _ := last("messages").(map[string]any)["content"].([]any)
+			if len(content) == 0 || marked(content[len(content)-1]) != enabled {
+				t.Errorf("latest user message = %v, want cache_control set = %v", content, enabled)
+			}
+		})
+	}
+}
+
+func TestClaudeModel_GenerateContent_ReportsTruncation(t *testing.T) {
+	var body map[string]any
+	m := newTestClaudeModel(t, &body, WithMaxInputTokens(200))
+
+	long := strings.Repeat("an old message that no longer fits ", 20)
+	req := &model.LLMRequest{Contents: []*genai.Content{
+		genai.NewContentFromText(long, genai.RoleUser),
+		genai.NewContentFromText(long, genai.RoleModel),
+		genai.NewContentFromText("hello", genai.RoleUser),
+	}}
+	var resp *model.LLMResponse
+	for r, err := range m.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		resp = r
+	}
+
+	if got := resp.CustomMetadata[models.TruncatedMessagesKey]; got != 2 {
+		t.Errorf("CustomMetadata[%q] = %v, want 2", models.TruncatedMessagesKey, got)
+	}
+	if messages, ok := body["messages"].([]any); !ok || len(messages) != 1 {
+		t.Errorf("messages sent = %v, want only the latest", body["messages"])
+	}
+}
+
+func TestNewClaudeModel_ThinkingBudgetTooSmall(t *testing.T) {
+	if _, err := NewClaudeModel("test-key", "claude-test", WithThinking(MinThinkingBudget-1)); err == nil {
+		t.Error("expected error for thinking budget below minimum")
+	}
+}
+
+func TestConvertPartToContentBlock_Thinking(t *testing.T) {
+	signed, err := convertPartToContentBlock(&genai.Part{Text: "reasoning", Thought: true, ThoughtSignature: []byte("sig")})
+	if err != nil {
+		t.Fatalf("convertPartToContentBlock() error = %v", err)
+	}
+	if signed.OfThinking == nil || signed.OfThinking.Thinking != "reasoning" || signed.OfThinking.Signature != "sig" {
+		t.Errorf("signed thought = %+v, want thinking block", signed)
+	}
+
+	redacted, err := convertPartToContentBlock(&genai.Part{Thought: true, ThoughtSignature: []byte("encrypted")})
+	if err != nil {
+		t.Fatalf("convertPartToContentBlock() error = %v", err)
+	}
+	if redacted.OfRedactedThinking == nil || redacted.OfRedactedThinking.Data != "encrypted" {
+		t.Errorf("redacted thought = %+v, want redacted thinking block", redacted)
+	}
+
+	unsigned, err := convertPartToContentBlock(&genai.Part{Text: "reasoning", Thought: true})
+	if err != nil {
+		t.Fatalf("convertPartToContentBlock() error = %v", err)
+	}
+	if unsigned != (anthropic.ContentBlockParamUnion{}) {
+		t.Errorf("unsigned thought should be dropped, got %+v", unsigned)
+	}
+}
+
+func TestNewClaudeModel_BaseURL(t *testing.T) {
+	var gotPath, gotKey string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotKey = r.URL.Path, r.Header.Get("X-Api-Key")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"msg_1","type":"message","role":"assistant","model":"claude-test",` +
+			`"content":[{"type":"text","text":"via gateway"}],"stop_reason":"end_turn","usage":{"input_tokens":1,"output_tokens":1}}`))
+	}))
+	defer gateway.Close()
+
+	m, err := NewClaudeModel("test-api-key", "claude-test", WithBaseURL(gateway.URL+"/anthropic"))
+	if err != nil {
+		t.Fatalf("NewClaudeModel() error = %v", err)
+	}
+
+	req := &model.LLMRequest{Contents: []*genai.Content{genai.NewContentFromText("hello", genai.RoleUser)}}
+	for resp, err := range m.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		if resp.Content.Parts[0].Text != "via gateway" {
+			t.Errorf("response = %q, want the gateway's reply", resp.Content.Parts[0].Text)
+		}
+	}
+
+	if gotPath != "/anthropic/v1/messages" {
+		t.Errorf("request path = %q, want /anthropic/v1/messages on the configured base URL", gotPath)
+	}
+	if gotKey != "test-api-key" {
+		t.Errorf("x-api-key = %q, want the API key", gotKey)
+	}
+}
+
+// generateOnce sends a single message and returns the error, if any
+func generateOnce(m *ClaudeModel) error {
+	req := &model.LLMRequest{Contents: []*genai.Content{genai.NewContentFromText("hello", genai.RoleUser)}}
+	for _, err := range m.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestClaudeModel_TimeoutCancelsSlowRequest(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	m, err := NewClaudeModel("test-api-key", "claude-test", WithBaseURL(server.URL),
+		WithTimeout(50*time.Millisecond), WithRetry(models.RetryConfig{}))
+	if err != nil {
+		t.Fatalf("NewClaudeModel() error = %v", err)
+	}
+
+	start := time.Now()
+	if err := generateOnce(m); err == nil {
+		t.Fatal("GenerateContent() error = nil, want a timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("request took %v, want it cancelled after the 50ms timeout", elapsed)
+	}
+}
+
+func TestClaudeModel_RetriesPerConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		retries   int
+		wantCalls int64
+	}{
+		{"overloaded is retried", 529, 2, 3},
+		{"rate limit is retried", http.StatusTooManyRequests, 1, 2},
+		{"no retries configured", http.StatusInternalServerError, 0, 1},
+		{"bad request isn't retried", http.StatusBadRequest, 3, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"type":"error","error":{"type":"api_error","message":"failed"}}`))
+			}))
+			defer server.Close()
+
+			m, err := NewClaudeModel("test-api-key", "claude-test", WithBaseURL(server.URL), WithRetry(models.RetryConfig{
+				MaxRetries:     tt.retries,
+				InitialBackoff: time.Millisecond,
+				MaxBackoff:     2 * time.Millisecond,
+			}))
+			if err != nil {
+				t.Fatalf("NewClaudeModel() error = %v", err)
+			}
+
+			if err := generateOnce(m); err == nil {
+				t.Fatal("GenerateContent() error = nil, want the API error")
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("made %d requests, want %d", got, tt.wantCalls)
 			}
 		})
 	}
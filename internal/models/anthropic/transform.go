@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"slices"
+	"strings"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"google.golang.org/adk/model"
@@ -35,10 +37,6 @@ const (
 // It extracts system messages separately since Anthropic requires them as a top-level parameter.
 // Returns the messages, system prompt blocks, and any error.
 //
-// Cache Control:
-// Automatically enables prompt caching on the last system block and last user message.
-// This follows Anthropic's best practices for effective caching of context.
-//
 //nolint:gocyclo,revive // Protocol transformation requires handling many content types
 func transformADKToAnthropic(contents []*genai.Content) ([]anthropic.MessageParam, []anthropic.TextBlockParam, error) {
 	var messages []anthropic.MessageParam
@@ -72,35 +70,6 @@ func transformADKToAnthropic(contents []*genai.Content) ([]anthropic.MessagePara
 		}
 	}
 
-	// Enable cache control on the last system block (best practice for caching system context)
-	if len(systemBlocks) > 0 {
-		lastIdx := len(systemBlocks) - 1
-		cacheControl := anthropic.NewCacheControlEphemeralParam()
-		systemBlocks[lastIdx].CacheControl = cacheControl
-	}
-
-	// Enable cache control on the last user message (best practice for caching conversation history)
-	for i := len(messages) - 1; i >= 0; i-- {
-		if messages[i].Role == anthropic.MessageParamRoleUser {
-			// Add cache control to the last content block of the last user message
-			if len(messages[i].Content) > 0 {
-				lastBlockIdx := len(messages[i].Content) - 1
-				cacheControl := anthropic.NewCacheControlEphemeralParam()
-
-				// Add cache control to the last block based on its type
-				switch {
-				case messages[i].Content[lastBlockIdx].OfText != nil:
-					messages[i].Content[lastBlockIdx].OfText.CacheControl = cacheControl
-				case messages[i].Content[lastBlockIdx].OfImage != nil:
-					messages[i].Content[lastBlockIdx].OfImage.CacheControl = cacheControl
-				case messages[i].Content[lastBlockIdx].OfToolResult != nil:
-					messages[i].Content[lastBlockIdx].OfToolResult.CacheControl = cacheControl
-				}
-			}
-			break
-		}
-	}
-
 	return messages, systemBlocks, nil
 }
 
@@ -151,6 +120,18 @@ func convertPartToContentBlock(part *genai.Part) (anthropic.ContentBlockParamUni
 		return anthropic.ContentBlockParamUnion{}, nil
 	}
 
+	// Handle thinking content. It must be passed back unmodified with its signature
+	// during tool use; unsigned thoughts can't be verified by the API and are dropped.
+	if part.Thought {
+		if len(part.ThoughtSignature) == 0 {
+			return anthropic.ContentBlockParamUnion{}, nil
+		}
+		if part.Text == "" {
+			return anthropic.NewRedactedThinkingBlock(string(part.ThoughtSignature)), nil
+		}
+		return anthropic.NewThinkingBlock(string(part.ThoughtSignature), part.Text), nil
+	}
+
 	// Handle text content
 	if part.Text != "" {
 		return anthropic.NewTextBlock(part.Text), nil
@@ -284,10 +265,18 @@ func convertContentBlockToPart(block anthropic.ContentBlockUnion) (*genai.Part,
 		}, nil
 
 	case "thinking":
-		// Return thinking content as text with a marker
+		// Return thinking content as a thought part, keeping the signature for tool use
 		return &genai.Part{
-			Text:    block.Thinking,
-			Thought: true,
+			Text:             block.Thinking,
+			Thought:          true,
+			ThoughtSignature: []byte(block.Signature),
+		}, nil
+
+	case "redacted_thinking":
+		// Redacted thinking has no readable text; keep the encrypted data to pass back
+		return &genai.Part{
+			Thought:          true,
+			ThoughtSignature: []byte(block.Data),
 		}, nil
 
 	default:
@@ -316,11 +305,8 @@ func mapStopReason(stopReason anthropic.StopReason) genai.FinishReason {
 
 // transformToolsToAnthropic converts ADK tool definitions to Anthropic ToolUnionParam.
 // ADK tools are stored as tool.Tool interface objects with a Declaration() method that
-// returns *genai.FunctionDeclaration containing the tool's schema.
-//
-// Cache Control:
-// Automatically enables prompt caching on the last tool definition.
-// This follows Anthropic's best practices for effective caching when using tools.
+// returns *genai.FunctionDeclaration containing the tool's schema. Tools are sorted by name
+// so the definitions are the same from one request to the next and can be cached.
 //
 //nolint:gocognit,revive,unparam // Tool transformation requires handling many schema types
 func transformToolsToAnthropic(tools map[string]any) ([]anthropic.ToolUnionParam, error) {
@@ -393,14 +379,9 @@ func transformToolsToAnthropic(tools map[string]any) ([]anthropic.ToolUnionParam
 		anthropicTools = append(anthropicTools, toolParam)
 	}
 
-	// Enable cache control on the last tool (best practice for caching tool definitions)
-	if len(anthropicTools) > 0 {
-		lastIdx := len(anthropicTools) - 1
-		cacheControl := anthropic.NewCacheControlEphemeralParam()
-		if anthropicTools[lastIdx].OfTool != nil {
-			anthropicTools[lastIdx].OfTool.CacheControl = cacheControl
-		}
-	}
+	slices.SortFunc(anthropicTools, func(a, b anthropic.ToolUnionParam) int {
+		return strings.Compare(a.OfTool.Name, b.OfTool.Name)
+	})
 
 	return anthropicTools, nil
 }
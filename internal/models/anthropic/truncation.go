@@ -4,23 +4,25 @@ import (
 	"encoding/json"
 
 	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/tokens"
 )
 
 const (
-	// maxInputTokenBudget is a conservative token budget for input content.
+	// DefaultMaxInputTokens is a conservative token budget for input content.
 	// Set well below the 200K API limit to account for token estimation
 	// inaccuracy and output token reservation.
-	maxInputTokenBudget = 160000
+	DefaultMaxInputTokens = 160000
 
 	// charsPerToken is the approximate character-to-token ratio used for
 	// token estimation. A lower value produces higher (more conservative)
 	// estimates, reducing the chance of hitting the API limit.
-	charsPerToken = 4
+	charsPerToken = tokens.CharsPerToken
 )
 
-// estimateStringTokens estimates the token count for a string.
+// estimateStringTokens estimates the token count for a string. Claude has no public
+// tokenizer, so this uses the characters-per-token heuristic.
 func estimateStringTokens(s string) int {
-	return (len(s) + charsPerToken - 1) / charsPerToken
+	return tokens.Heuristic(s)
 }
 
 // estimateBlockTokens estimates the token count for a single content block.
@@ -118,18 +120,27 @@ func isToolResultMessage(msg anthropic.MessageParam) bool {
 	return false
 }
 
-// truncateMessages removes the oldest messages to fit within the token budget.
+// truncateMessages removes the oldest messages to fit within the default token budget.
+func truncateMessages(
+	messages []anthropic.MessageParam,
+	fixedTokenOverhead int,
+) ([]anthropic.MessageParam, int) {
+	return truncateMessagesToBudget(messages, fixedTokenOverhead, DefaultMaxInputTokens)
+}
+
+// truncateMessagesToBudget removes the oldest messages to fit within maxInputTokens.
 // It preserves message validity by ensuring:
 //   - The result starts with a user message (Anthropic API requirement)
 //   - Tool use/result pairs are not orphaned (only truncates at clean boundaries)
 //
 // fixedTokenOverhead accounts for system prompt and tool definition tokens.
 // Returns the (possibly truncated) messages and the number of messages removed.
-func truncateMessages(
+func truncateMessagesToBudget(
 	messages []anthropic.MessageParam,
 	fixedTokenOverhead int,
+	maxInputTokens int,
 ) ([]anthropic.MessageParam, int) {
-	budget := maxInputTokenBudget - fixedTokenOverhead
+	budget := maxInputTokens - fixedTokenOverhead
 	if budget <= 0 {
 		budget = 1 // ensure we don't reject everything due to overhead alone
 	}
@@ -227,7 +227,7 @@ func TestTruncateMessages_NoTruncationNeeded(t *testing.T) {
 }
 
 func TestTruncateMessages_TruncatesOldest(t *testing.T) {
-	// Create messages that total well over maxInputTokenBudget
+	// Create messages that total well over DefaultMaxInputTokens
 	// Each message has ~50000 tokens worth of text
 	messages := []anthropic.MessageParam{
 		makeTextMsg("user", 50000),     // old - should be removed
@@ -445,14 +445,14 @@ func TestTruncateMessages_AllToolResults(t *testing.T) {
 }
 
 func TestTruncateMessages_OverheadExceedsBudget(t *testing.T) {
-	// Fixed overhead larger than maxInputTokenBudget: budget clamps to 1.
+	// Fixed overhead larger than DefaultMaxInputTokens: budget clamps to 1.
 	// Should still return something reasonable.
 	messages := []anthropic.MessageParam{
 		makeTextMsg("user", 100),
 		makeTextMsg("assistant", 100),
 	}
 
-	result, removed := truncateMessages(messages, maxInputTokenBudget+10000)
+	result, removed := truncateMessages(messages, DefaultMaxInputTokens+10000)
 	// Messages exceed budget of 1, so truncation happens.
 	// Should fall back to last valid user message.
 	if len(result) == 0 {
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"iter"
+	"log/slog"
+	"time"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// ResponseCache stores serialised model responses by key. Implementations must be safe
+// for concurrent use.
+type ResponseCache interface {
+	// Get returns the value stored under key, and false if there is none or it has expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// CacheConfig configures response caching.
+type CacheConfig struct {
+	Cache ResponseCache // Where responses are stored
+	TTL   time.Duration // How long a response is reused
+
+	// HistoryWindow is how many of the most recent contents (the new message and the turns
+	// before it) are part of the key; 0 for the whole conversation
+	HistoryWindow int
+
+	// Params are the default parameters the wrapped model applies, which affect its
+	// responses without being on the request
+	Params Params
+}
+
+// cachingModel wraps a model.LLM and reuses its response to an identical request.
+type cachingModel struct {
+	model.LLM
+	config CacheConfig
+}
+
+// WrapWithCache returns an LLM that answers a request identical to an earlier one (same
+// system prompt, tools, parameters, recent history and message) from the cache instead of
+// calling llm. Turns that use tools are never cached: responses that call a tool aren't
+// stored, and requests carrying a tool result always go to the model.
+func WrapWithCache(llm model.LLM, config CacheConfig) model.LLM {
+	return &cachingModel{LLM: llm, config: config}
+}
+
+// GenerateContent returns the cached response for req if there is one, and otherwise
+// calls the wrapped model and caches its response.
+func (m *cachingModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	key, ok := m.key(req)
+	if !ok {
+		return m.LLM.GenerateContent(ctx, req, stream)
+	}
+
+	return func(yield func(*model.LLMResponse, error) bool) {
+		if resp, ok := m.lookup(ctx, key); ok {
+			yield(resp, nil)
+			return
+		}
+
+		// Only a single complete response is cached; partial streamed chunks are skipped
+		var final []*model.LLMResponse
+		failed := false
+		for resp, err := range m.LLM.GenerateContent(ctx, req, stream) {
+			if err != nil {
+				failed = true
+			} else if resp != nil && !resp.Partial {
+				final = append(final, resp)
+			}
+			if !yield(resp, err) {
+				return
+			}
+		}
+		if !failed && len(final) == 1 && cacheable(final[0]) {
+			m.store(ctx, key, final[0])
+		}
+	}
+}
+
+// lookup returns the cached response under key
+func (m *cachingModel) lookup(ctx context.Context, key string) (*model.LLMResponse, bool) {
+	data, found, err := m.config.Cache.Get(ctx, key)
+	if err != nil {
+		slog.Default().Warn("response cache lookup failed", slog.String("error", err.Error()))
+		return nil, false
+	}
+	if !found {
+		return nil, false
+	}
+
+	var resp model.LLMResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		slog.Default().Warn("discarding unreadable cached response", slog.String("error", err.Error()))
+		return nil, false
+	}
+	// No tokens were spent on a cached response
+	resp.UsageMetadata = nil
+
+	slog.Default().Debug("answered from response cache", slog.String("model", m.Name()))
+	return &resp, true
+}
+
+// store caches resp under key
+func (m *cachingModel) store(ctx context.Context, key string, resp *model.LLMResponse) {
+	data, err := json.Marshal(resp)
+	if err == nil {
+		err = m.config.Cache.Set(ctx, key, data, m.config.TTL)
+	}
+	if err != nil {
+		slog.Default().Warn("failed to cache response", slog.String("error", err.Error()))
+	}
+}
+
+// key hashes everything that determines the model's response to req. It returns false
+// when the request mustn't be cached.
+func (m *cachingModel) key(req *model.LLMRequest) (string, bool) {
+	if len(req.Contents) == 0 || hasFunctionResponse(req.Contents[len(req.Contents)-1]) {
+		return "", false
+	}
+
+	contents := req.Contents
+	if m.config.HistoryWindow > 0 && len(contents) > m.config.HistoryWindow {
+		contents = contents[len(contents)-m.config.HistoryWindow:]
+	}
+
+	data, err := json.Marshal(struct {
+		Model    string                       `json:"model"`
+		Params   Params                       `json:"params"`
+		Config   *genai.GenerateContentConfig `json:"config"`
+		Contents []*genai.Content             `json:"contents"`
+	}{
+		Model:    m.Name(),
+		Params:   m.config.Params,
+		Config:   req.Config,
+		Contents: contents,
+	})
+	if err != nil {
+		return "", false
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), true
+}
+
+// cacheable reports whether resp is a complete text answer that can be reused
+func cacheable(resp *model.LLMResponse) bool {
+	if resp.Content == nil || resp.ErrorCode != "" || resp.Interrupted {
+		return false
+	}
+	for _, part := range resp.Content.Parts {
+		if part.FunctionCall != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func hasFunctionResponse(content *genai.Content) bool {
+	if content == nil {
+		return false
+	}
+	for _, part := range content.Parts {
+		if part.FunctionResponse != nil {
+			return true
+		}
+	}
+	return false
+}
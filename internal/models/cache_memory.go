@@ -0,0 +1,42 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/cache"
+)
+
+// DefaultCacheMaxEntries is how many responses the in-memory cache holds by default
+const DefaultCacheMaxEntries = 1000
+
+// MemoryResponseCache is a bounded in-memory ResponseCache that evicts the least recently
+// used response when full. Its contents are lost on restart and not shared between replicas.
+type MemoryResponseCache struct {
+	entries *cache.TTLCache[string, []byte]
+}
+
+// NewMemoryResponseCache creates an in-memory cache holding up to maxEntries responses
+// (DefaultCacheMaxEntries if not positive).
+func NewMemoryResponseCache(maxEntries int) *MemoryResponseCache {
+	return newMemoryResponseCache(maxEntries)
+}
+
+func newMemoryResponseCache(maxEntries int, opts ...cache.Option) *MemoryResponseCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheMaxEntries
+	}
+	return &MemoryResponseCache{entries: cache.New[string, []byte](maxEntries, 0, opts...)}
+}
+
+// Get returns the value stored under key if present and not expired
+func (c *MemoryResponseCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	value, ok := c.entries.Get(key)
+	return value, ok, nil
+}
+
+// Set stores value under key for ttl, evicting the least recently used entry when full
+func (c *MemoryResponseCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.entries.SetWithTTL(key, value, ttl)
+	return nil
+}
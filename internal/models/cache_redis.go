@@ -0,0 +1,191 @@
+package models
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// redisKeyPrefix namespaces response cache keys in a shared Redis
+const redisKeyPrefix = "chatbot:response:"
+
+// redisTimeout bounds each Redis command when the context has no earlier deadline
+const redisTimeout = 2 * time.Second
+
+// RedisConfig configures the Redis response cache.
+type RedisConfig struct {
+	Addr     string // host:port
+	Password string // Optional AUTH password
+	DB       int    // Database number selected after connecting
+}
+
+// RedisResponseCache is a ResponseCache backed by Redis, so cached responses survive
+// restarts and are shared between replicas. It speaks the RESP protocol directly over a
+// single connection, which is re-established after any error.
+type RedisResponseCache struct {
+	config RedisConfig
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// NewRedisResponseCache creates a Redis response cache. The connection is made on first use.
+func NewRedisResponseCache(config RedisConfig) *RedisResponseCache {
+	return &RedisResponseCache{config: config}
+}
+
+// Get returns the value stored under key
+func (c *RedisResponseCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.do(ctx, "GET", redisKeyPrefix+key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("unexpected redis GET reply %v", reply)
+	}
+	return value, true, nil
+}
+
+// Set stores value under key, expiring it after ttl
+func (c *RedisResponseCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := c.do(ctx, "SET", redisKeyPrefix+key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+// Close closes the connection to Redis
+func (c *RedisResponseCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeConn()
+}
+
+// do sends a command and reads its reply, reconnecting first if needed
+func (c *RedisResponseCache) do(ctx context.Context, args ...string) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := c.roundTrip(ctx, args...)
+	if err != nil {
+		var redisErr redisError
+		if !errors.As(err, &redisErr) {
+			// The connection is in an unknown state; start afresh next time
+			_ = c.closeConn()
+		}
+		return nil, err
+	}
+	return reply, nil
+}
+
+// connect dials Redis, authenticating and selecting the database if configured
+func (c *RedisResponseCache) connect(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.config.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	c.conn = conn
+	c.rd = bufio.NewReader(conn)
+
+	if c.config.Password != "" {
+		if _, err := c.roundTrip(ctx, "AUTH", c.config.Password); err != nil {
+			_ = c.closeConn()
+			return fmt.Errorf("redis AUTH failed: %w", err)
+		}
+	}
+	if c.config.DB != 0 {
+		if _, err := c.roundTrip(ctx, "SELECT", strconv.Itoa(c.config.DB)); err != nil {
+			_ = c.closeConn()
+			return fmt.Errorf("redis SELECT failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *RedisResponseCache) closeConn() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn, c.rd = nil, nil
+	return err
+}
+
+// roundTrip writes a command as a RESP array of bulk strings and reads the reply
+func (c *RedisResponseCache) roundTrip(ctx context.Context, args ...string) (any, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > redisTimeout {
+		deadline = time.Now().Add(redisTimeout)
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	if _, err := c.conn.Write(buf); err != nil {
+		return nil, fmt.Errorf("redis write failed: %w", err)
+	}
+	return readRESP(c.rd)
+}
+
+// redisError is an error reply from the server; the connection is still usable after one
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// readRESP reads one reply: a simple string, error, integer or bulk string (nil when absent)
+func readRESP(rd *bufio.Reader) (any, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("redis read failed: %w", err)
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed redis reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed redis bulk length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, data); err != nil {
+			return nil, fmt.Errorf("redis read failed: %w", err)
+		}
+		return data[:n], nil
+	default:
+		return nil, fmt.Errorf("unsupported redis reply type %q", kind)
+	}
+}
@@ -0,0 +1,220 @@
+package models
+
+import (
+	"bufio"
+	"context"
+	"iter"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/cache"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// countingLLM answers every request with a fixed response and counts the calls
+type countingLLM struct {
+	calls int
+	reply *genai.Content
+}
+
+func (c *countingLLM) Name() string { return "counting" }
+
+func (c *countingLLM) GenerateContent(_ context.Context, _ *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	c.calls++
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{
+			Content:       c.reply,
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{TotalTokenCount: 42},
+		}, nil)
+	}
+}
+
+func newCacheRequest(history ...string) *model.LLMRequest {
+	req := &model.LLMRequest{Config: &genai.GenerateContentConfig{
+		SystemInstruction: genai.NewContentFromText("You answer FAQs.", genai.RoleUser),
+	}}
+	for _, text := range history {
+		req.Contents = append(req.Contents, genai.NewContentFromText(text, genai.RoleUser))
+	}
+	return req
+}
+
+func generate(t *testing.T, llm model.LLM, req *model.LLMRequest) *model.LLMResponse {
+	t.Helper()
+	var last *model.LLMResponse
+	for resp, err := range llm.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		last = resp
+	}
+	return last
+}
+
+func TestWrapWithCache_ReusesResponse(t *testing.T) {
+	inner := &countingLLM{reply: genai.NewContentFromText("We open at 9am.", genai.RoleModel)}
+	llm := WrapWithCache(inner, CacheConfig{Cache: NewMemoryResponseCache(10), TTL: time.Hour})
+
+	first := generate(t, llm, newCacheRequest("When do you open?"))
+	second := generate(t, llm, newCacheRequest("When do you open?"))
+
+	if inner.calls != 1 {
+		t.Errorf("model called %d times, want 1", inner.calls)
+	}
+	if got := second.Content.Parts[0].Text; got != "We open at 9am." || got != first.Content.Parts[0].Text {
+		t.Errorf("cached response = %q, want the original response", got)
+	}
+	if second.UsageMetadata != nil {
+		t.Errorf("cached response usage = %+v, want none", second.UsageMetadata)
+	}
+
+	// A different message, system prompt or parameters isn't a hit
+	generate(t, llm, newCacheRequest("When do you close?"))
+	req := newCacheRequest("When do you open?")
+	req.Config.SystemInstruction = genai.NewContentFromText("You are terse.", genai.RoleUser)
+	generate(t, llm, req)
+	req = newCacheRequest("When do you open?")
+	req.Config.Temperature = genai.Ptr[float32](0.9)
+	generate(t, llm, req)
+	if inner.calls != 4 {
+		t.Errorf("model called %d times, want 4", inner.calls)
+	}
+}
+
+func TestWrapWithCache_HistoryWindow(t *testing.T) {
+	inner := &countingLLM{reply: genai.NewContentFromText("We open at 9am.", genai.RoleModel)}
+	llm := WrapWithCache(inner, CacheConfig{Cache: NewMemoryResponseCache(10), TTL: time.Hour, HistoryWindow: 1})
+
+	generate(t, llm, newCacheRequest("hello", "When do you open?"))
+	generate(t, llm, newCacheRequest("something else entirely", "When do you open?"))
+
+	if inner.calls != 1 {
+		t.Errorf("model called %d times, want history outside the window ignored", inner.calls)
+	}
+}
+
+func TestWrapWithCache_SkipsToolTurns(t *testing.T) {
+	call := &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{
+		FunctionCall: &genai.FunctionCall{Name: "web_search", Args: map[string]any{"query": "opening hours"}},
+	}}}
+	inner := &countingLLM{reply: call}
+	llm := WrapWithCache(inner, CacheConfig{Cache: NewMemoryResponseCache(10), TTL: time.Hour})
+
+	// A response calling a tool isn't cached
+	generate(t, llm, newCacheRequest("When do you open?"))
+	generate(t, llm, newCacheRequest("When do you open?"))
+	if inner.calls != 2 {
+		t.Errorf("model called %d times, want tool calls never cached", inner.calls)
+	}
+
+	// Nor is the answer to a tool result
+	inner.reply = genai.NewContentFromText("We open at 9am.", genai.RoleModel)
+	req := newCacheRequest("When do you open?")
+	req.Contents = append(req.Contents, call, &genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{{
+		FunctionResponse: &genai.FunctionResponse{Name: "web_search", Response: map[string]any{"hours": "9-5"}},
+	}}})
+	generate(t, llm, req)
+	generate(t, llm, req)
+	if inner.calls != 4 {
+		t.Errorf("model called %d times, want answers to tool results never cached", inner.calls)
+	}
+}
+
+func TestMemoryResponseCache_Expiry(t *testing.T) {
+	now := time.Now()
+	responses := newMemoryResponseCache(1, cache.WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+
+	_ = responses.Set(ctx, "a", []byte("1"), time.Minute)
+	if _, ok, _ := responses.Get(ctx, "a"); !ok {
+		t.Fatal("Get(a) missed, want a hit")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok, _ := responses.Get(ctx, "a"); ok {
+		t.Error("Get(a) hit after the TTL, want a miss")
+	}
+
+	_ = responses.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = responses.Set(ctx, "b", []byte("2"), time.Minute)
+	if _, ok, _ := responses.Get(ctx, "a"); ok {
+		t.Error("Get(a) hit after eviction, want a miss")
+	}
+}
+
+// fakeRedis is a minimal RESP server supporting GET and SET
+func fakeRedis(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	var mu sync.Mutex
+	data := map[string]string{}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				rd := bufio.NewReader(conn)
+				for {
+					line, err := rd.ReadString('\n')
+					if err != nil {
+						return
+					}
+					n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+					args := make([]string, n)
+					for i := range args {
+						_, _ = rd.ReadString('\n')
+						arg, _ := rd.ReadString('\n')
+						args[i] = strings.TrimSuffix(arg, "\r\n")
+					}
+
+					mu.Lock()
+					switch args[0] {
+					case "SET":
+						data[args[1]] = args[2]
+						_, _ = conn.Write([]byte("+OK\r\n"))
+					case "GET":
+						if v, ok := data[args[1]]; ok {
+							_, _ = conn.Write([]byte("$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"))
+						} else {
+							_, _ = conn.Write([]byte("$-1\r\n"))
+						}
+					default:
+						_, _ = conn.Write([]byte("-ERR unknown command\r\n"))
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestRedisResponseCache_RoundTrip(t *testing.T) {
+	cache := NewRedisResponseCache(RedisConfig{Addr: fakeRedis(t)})
+	t.Cleanup(func() { _ = cache.Close() })
+	ctx := context.Background()
+
+	if _, ok, err := cache.Get(ctx, "missing"); err != nil || ok {
+		t.Errorf("Get(missing) = %v, %v, want a miss", ok, err)
+	}
+	if err := cache.Set(ctx, "key", []byte(`{"text":"hi"}`), time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	value, ok, err := cache.Get(ctx, "key")
+	if err != nil || !ok || string(value) != `{"text":"hi"}` {
+		t.Errorf("Get(key) = %q, %v, %v, want the stored value", value, ok, err)
+	}
+}
@@ -0,0 +1,137 @@
+// Package gemini creates Gemini models, on the Gemini API or Vertex AI. It adapts tool
+// declarations to what Gemini accepts, and retries transient API errors like the other
+// providers do.
+package gemini
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/models"
+	"google.golang.org/adk/model"
+	adkgemini "google.golang.org/adk/model/gemini"
+	"google.golang.org/genai"
+)
+
+// retryInfoType is the type of the error detail in which Google APIs say how long to wait
+// before retrying
+const retryInfoType = "type.googleapis.com/google.rpc.RetryInfo"
+
+// Option configures a Gemini model
+type Option func(*options)
+
+type options struct {
+	retry *models.RetryConfig
+}
+
+// WithRetry retries calls that fail with rate limits, server errors or without a response,
+// per cfg, waiting as long as the API asks when it says
+func WithRetry(cfg models.RetryConfig) Option {
+	return func(o *options) {
+		if cfg.MaxRetries > 0 {
+			o.retry = &cfg
+		}
+	}
+}
+
+// NewModel creates a Gemini model for modelName using clientConfig
+func NewModel(ctx context.Context, modelName string, clientConfig *genai.ClientConfig, opts ...Option) (model.LLM, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	llm, err := adkgemini.NewModel(ctx, modelName, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	m := &geminiModel{LLM: llm}
+	if o.retry != nil {
+		m.retry = *o.retry
+	}
+	return m, nil
+}
+
+// geminiModel wraps the ADK's Gemini model, preparing tool declarations for Gemini and
+// retrying calls that fail before producing any output
+type geminiModel struct {
+	model.LLM
+	retry models.RetryConfig
+}
+
+// GenerateContent calls the wrapped model, retrying an attempt that fails before it has
+// produced anything. Errors once a streamed response has started are passed through, to be
+// handled by the stream retry wrapper.
+func (m *geminiModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	prepared, names := prepareRequest(req)
+	return func(yield func(*model.LLMResponse, error) bool) {
+		stopped := false
+		err := models.RetryAfter(ctx, m.retry, isRetryable, retryAfter, func(ctx context.Context) error {
+			started := false
+			for resp, err := range m.LLM.GenerateContent(ctx, prepared, stream) {
+				if err != nil && !started {
+					return err
+				}
+				started = true
+				restoreFunctionCalls(resp, names)
+				if !yield(resp, err) {
+					stopped = true
+					return nil
+				}
+			}
+			return nil
+		})
+		if err != nil && !stopped {
+			yield(nil, err)
+		}
+	}
+}
+
+// isRetryable reports whether a failed call may succeed if retried: request timeouts, rate
+// limits and server errors, or failures to get a response at all
+func isRetryable(err error) bool {
+	if apiErr, ok := asAPIError(err); ok {
+		switch apiErr.Code {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests:
+			return true
+		}
+		return apiErr.Code >= http.StatusInternalServerError
+	}
+	return !errors.Is(err, context.Canceled)
+}
+
+// retryAfter returns the delay a failed call's RetryInfo detail asks for, e.g. on a quota
+// error, or 0 if there isn't one
+func retryAfter(err error) time.Duration {
+	apiErr, ok := asAPIError(err)
+	if !ok {
+		return 0
+	}
+	for _, detail := range apiErr.Details {
+		if detail["@type"] != retryInfoType {
+			continue
+		}
+		if value, ok := detail["retryDelay"].(string); ok {
+			if delay, err := time.ParseDuration(value); err == nil && delay > 0 {
+				return delay
+			}
+		}
+	}
+	return 0
+}
+
+// asAPIError returns the API error in err's chain, which genai returns by value
+func asAPIError(err error) (genai.APIError, bool) {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	var apiErrPtr *genai.APIError
+	if errors.As(err, &apiErrPtr) && apiErrPtr != nil {
+		return *apiErrPtr, true
+	}
+	return genai.APIError{}, false
+}
@@ -0,0 +1,138 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/models"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+const okResponse = `{"candidates":[{"content":{"role":"model","parts":[{"text":"ok"}]},"finishReason":"STOP"}]}`
+
+// newTestModel returns a model calling a fake Gemini API that answers each call with the
+// next of responses, and the number of calls made
+func newTestModel(t *testing.T, retry models.RetryConfig, responses ...func(http.ResponseWriter)) (model.LLM, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1)) - 1
+		w.Header().Set("Content-Type", "application/json")
+		responses[min(n, len(responses)-1)](w)
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := NewModel(context.Background(), "gemini-2.5-flash", &genai.ClientConfig{
+		APIKey:      "test-key",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPClient:  server.Client(),
+		HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+	}, WithRetry(retry))
+	if err != nil {
+		t.Fatalf("NewModel() error = %v", err)
+	}
+	return llm, &calls
+}
+
+func status(code int, body string) func(http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func generate(llm model.LLM) (string, error) {
+	req := &model.LLMRequest{Contents: []*genai.Content{genai.NewContentFromText("hi", genai.RoleUser)}}
+	var text string
+	for resp, err := range llm.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			return "", err
+		}
+		if resp.Content != nil {
+			for _, part := range resp.Content.Parts {
+				text += part.Text
+			}
+		}
+	}
+	return text, nil
+}
+
+func TestGenerateContent_RetriesTransientErrors(t *testing.T) {
+	retry := models.RetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	llm, calls := newTestModel(t, retry,
+		status(http.StatusServiceUnavailable, `{"error":{"code":503,"message":"overloaded","status":"UNAVAILABLE"}}`),
+		status(http.StatusTooManyRequests, `{"error":{"code":429,"message":"quota","status":"RESOURCE_EXHAUSTED","details":[{"@type":"type.googleapis.com/google.rpc.RetryInfo","retryDelay":"0.05s"}]}}`),
+		status(http.StatusOK, okResponse))
+
+	start := time.Now()
+	text, err := generate(llm)
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if text != "ok" {
+		t.Errorf("text = %q, want %q", text, "ok")
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("API called %d times, want 3", got)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("retries took %v, want the 50ms retry delay the API asked for honoured", elapsed)
+	}
+}
+
+func TestGenerateContent_DoesNotRetryClientErrors(t *testing.T) {
+	retry := models.RetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	llm, calls := newTestModel(t, retry,
+		status(http.StatusBadRequest, `{"error":{"code":400,"message":"bad request","status":"INVALID_ARGUMENT"}}`))
+
+	_, err := generate(llm)
+	var apiErr genai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest {
+		t.Fatalf("GenerateContent() error = %v, want the 400 API error", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("API called %d times, want 1", got)
+	}
+}
+
+func TestGenerateContent_GivesUpAfterMaxRetries(t *testing.T) {
+	retry := models.RetryConfig{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	llm, calls := newTestModel(t, retry,
+		status(http.StatusInternalServerError, `{"error":{"code":500,"message":"internal","status":"INTERNAL"}}`))
+
+	if _, err := generate(llm); err == nil {
+		t.Fatal("GenerateContent() error = nil, want the 500 API error")
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("API called %d times, want 3", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want time.Duration
+	}{
+		{"retry info", genai.APIError{Code: 429, Details: []map[string]any{
+			{"@type": "type.googleapis.com/google.rpc.QuotaFailure"},
+			{"@type": retryInfoType, "retryDelay": "37s"},
+		}}, 37 * time.Second},
+		{"no retry info", genai.APIError{Code: 429}, 0},
+		{"unparseable delay", genai.APIError{Code: 429, Details: []map[string]any{{"@type": retryInfoType, "retryDelay": "soon"}}}, 0},
+		{"not an API error", errors.New("connection reset"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.err); got != tt.want {
+				t.Errorf("retryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package gemini
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"slices"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// maxFunctionNameLength is the longest function name Gemini accepts
+const maxFunctionNameLength = 64
+
+// invalidNameChars matches characters Gemini doesn't allow in function names
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.:-]`)
+
+// unsupportedSchemaKeys are JSON Schema keywords MCP servers commonly send that Gemini
+// rejects in a function's parameters
+var unsupportedSchemaKeys = []string{"$schema", "$comment"}
+
+// functionName returns name as a valid Gemini function name: letters, digits, underscores,
+// dots, colons and dashes, starting with a letter or underscore, and at most 64 characters.
+// Names that are too long, such as those of prefixed MCP tools, are shortened with a hash of
+// the full name so they stay unique.
+func functionName(name string) string {
+	valid := invalidNameChars.ReplaceAllString(name, "_")
+	if valid == "" || !isNameStart(valid[0]) {
+		valid = "_" + valid
+	}
+	if len(valid) > maxFunctionNameLength {
+		sum := sha256.Sum256([]byte(name))
+		suffix := hex.EncodeToString(sum[:4])
+		valid = valid[:maxFunctionNameLength-len(suffix)-1] + "_" + suffix
+	}
+	return valid
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// parametersSchema returns a function's JSON Schema parameters as Gemini accepts them: an
+// object schema without the keywords it rejects. Schemas that can't be read are passed on
+// as they are.
+func parametersSchema(schema any) any {
+	if schema == nil {
+		return nil
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return schema
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return schema
+	}
+	for _, key := range unsupportedSchemaKeys {
+		delete(fields, key)
+	}
+	if _, ok := fields["type"]; !ok {
+		fields["type"] = "object"
+	}
+	return fields
+}
+
+// prepareRequest returns req with its function declarations in the form Gemini accepts, and
+// the tool names they were given, mapped back to the originals. Function calls and responses
+// in the history are renamed to match. req itself isn't changed, since the ADK keeps it for
+// the rest of the turn.
+func prepareRequest(req *model.LLMRequest) (*model.LLMRequest, map[string]string) {
+	if req.Config == nil || len(req.Config.Tools) == 0 {
+		return req, nil
+	}
+
+	names := make(map[string]string)
+	config := *req.Config
+	config.Tools = make([]*genai.Tool, len(req.Config.Tools))
+	for i, t := range req.Config.Tools {
+		if t == nil || len(t.FunctionDeclarations) == 0 {
+			config.Tools[i] = t
+			continue
+		}
+		prepared := *t
+		prepared.FunctionDeclarations = make([]*genai.FunctionDeclaration, len(t.FunctionDeclarations))
+		for j, decl := range t.FunctionDeclarations {
+			if decl == nil {
+				continue
+			}
+			d := *decl
+			d.Name = functionName(decl.Name)
+			if d.Name != decl.Name {
+				names[d.Name] = decl.Name
+			}
+			d.ParametersJsonSchema = parametersSchema(decl.ParametersJsonSchema)
+			prepared.FunctionDeclarations[j] = &d
+		}
+		config.Tools[i] = &prepared
+	}
+
+	prepared := *req
+	prepared.Config = &config
+	if len(names) > 0 {
+		prepared.Contents = renameFunctionParts(req.Contents)
+	}
+	return &prepared, names
+}
+
+// renameFunctionParts returns contents with the names of function calls and responses made
+// valid for Gemini, copying only the contents that change
+func renameFunctionParts(contents []*genai.Content) []*genai.Content {
+	renamed := make([]*genai.Content, len(contents))
+	for i, content := range contents {
+		renamed[i] = content
+		if content == nil {
+			continue
+		}
+		for j, part := range content.Parts {
+			if part == nil {
+				continue
+			}
+			call, response := part.FunctionCall, part.FunctionResponse
+			if call != nil && functionName(call.Name) != call.Name {
+				c := *call
+				c.Name = functionName(call.Name)
+				call = &c
+			}
+			if response != nil && functionName(response.Name) != response.Name {
+				r := *response
+				r.Name = functionName(response.Name)
+				response = &r
+			}
+			if call == part.FunctionCall && response == part.FunctionResponse {
+				continue
+			}
+
+			if renamed[i] == content {
+				c := *content
+				c.Parts = slices.Clone(content.Parts)
+				renamed[i] = &c
+			}
+			p := *part
+			p.FunctionCall = call
+			p.FunctionResponse = response
+			renamed[i].Parts[j] = &p
+		}
+	}
+	return renamed
+}
+
+// restoreFunctionCalls gives the function calls in a response the tool names they were
+// declared with, so the ADK finds the tools to run. Gemini leaves out the arguments of calls
+// to functions without parameters, which are set to an empty object for tools that expect
+// one.
+func restoreFunctionCalls(resp *model.LLMResponse, names map[string]string) {
+	if resp == nil || resp.Content == nil {
+		return
+	}
+	for _, part := range resp.Content.Parts {
+		if part == nil || part.FunctionCall == nil {
+			continue
+		}
+		if original, ok := names[part.FunctionCall.Name]; ok {
+			part.FunctionCall.Name = original
+		}
+		if part.FunctionCall.Args == nil {
+			part.FunctionCall.Args = map[string]any{}
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/genai"
+)
+
+// forecastToolName is longer than Gemini allows, like the names of some prefixed MCP tools
+const forecastToolName = "mcp__weather_forecasts_for_cities_around_the_world__get_daily_forecast"
+
+type forecastArgs struct {
+	City string `json:"city"`
+}
+
+type forecastResult struct {
+	Forecast string `json:"forecast"`
+}
+
+// geminiRequest is the part of a generateContent request body the tests look at
+type geminiRequest struct {
+	Contents []*genai.Content `json:"contents"`
+	Tools    []struct {
+		FunctionDeclarations []struct {
+			Name                 string         `json:"name"`
+			ParametersJsonSchema map[string]any `json:"parametersJsonSchema"`
+		} `json:"functionDeclarations"`
+	} `json:"tools"`
+}
+
+func TestGenerateContent_FunctionCalling(t *testing.T) {
+	ctx := context.Background()
+	geminiName := functionName(forecastToolName)
+
+	var mu sync.Mutex
+	var requests []geminiRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req geminiRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("request body %s: %v", body, err)
+		}
+		mu.Lock()
+		requests = append(requests, req)
+		n := len(requests)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if n == 1 {
+			// Gemini calls functions by their declared name, without a call ID
+			_, _ = w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"` + geminiName + `","args":{"city":"Paris"}}}]},"finishReason":"STOP"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"Sunny in Paris."}]},"finishReason":"STOP"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	llm, err := NewModel(ctx, "gemini-2.5-flash", &genai.ClientConfig{
+		APIKey:      "test-key",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPClient:  server.Client(),
+		HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+	})
+	if err != nil {
+		t.Fatalf("NewModel() error = %v", err)
+	}
+
+	var calls []forecastArgs
+	forecast, err := functiontool.New(functiontool.Config{
+		Name:        forecastToolName,
+		Description: "Gets the forecast for a city",
+		InputSchema: &jsonschema.Schema{
+			Schema:     "https://json-schema.org/draft/2020-12/schema",
+			Type:       "object",
+			Properties: map[string]*jsonschema.Schema{"city": {Type: "string"}},
+			Required:   []string{"city"},
+		},
+	}, func(_ tool.Context, args forecastArgs) (forecastResult, error) {
+		calls = append(calls, args)
+		return forecastResult{Forecast: "sunny"}, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New() error = %v", err)
+	}
+
+	a, err := llmagent.New(llmagent.Config{Name: "test_agent", Model: llm, Tools: []tool.Tool{forecast}})
+	if err != nil {
+		t.Fatalf("llmagent.New() error = %v", err)
+	}
+	sessions := session.InMemoryService()
+	if _, err := sessions.Create(ctx, &session.CreateRequest{AppName: "test", UserID: "user1", SessionID: "session1"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	r, err := runner.New(runner.Config{AppName: "test", Agent: a, SessionService: sessions})
+	if err != nil {
+		t.Fatalf("runner.New() error = %v", err)
+	}
+
+	var reply strings.Builder
+	for event, err := range r.Run(ctx, "user1", "session1", genai.NewContentFromText("Weather in Paris?", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if event.Content != nil && event.Author == "test_agent" {
+			for _, part := range event.Content.Parts {
+				reply.WriteString(part.Text)
+			}
+		}
+	}
+
+	if len(calls) != 1 || calls[0].City != "Paris" {
+		t.Errorf("tool calls = %+v, want one for Paris", calls)
+	}
+	if reply.String() != "Sunny in Paris." {
+		t.Errorf("reply = %q, want %q", reply.String(), "Sunny in Paris.")
+	}
+	if len(requests) != 2 {
+		t.Fatalf("API called %d times, want 2", len(requests))
+	}
+
+	// The tool is declared with a name and schema Gemini accepts
+	decls := requests[0].Tools[0].FunctionDeclarations
+	if len(decls) != 1 || decls[0].Name != geminiName {
+		t.Fatalf("function declarations = %+v, want one named %q", decls, geminiName)
+	}
+	if _, ok := decls[0].ParametersJsonSchema["$schema"]; ok {
+		t.Errorf("parameters schema = %v, want $schema removed", decls[0].ParametersJsonSchema)
+	}
+
+	// The tool's result is sent back under the name the call was made with
+	var response *genai.FunctionResponse
+	for _, content := range requests[1].Contents {
+		for _, part := range content.Parts {
+			if part.FunctionResponse != nil {
+				response = part.FunctionResponse
+			}
+		}
+	}
+	if response == nil {
+		t.Fatalf("second request contents = %+v, want the function response", requests[1].Contents)
+	}
+	if response.Name != geminiName || response.Response["forecast"] != "sunny" {
+		t.Errorf("function response = %+v, want %q with the forecast", response, geminiName)
+	}
+}
+
+func TestFunctionName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"get_forecast", "get_forecast"},
+		{"mcp__github__search.code", "mcp__github__search.code"},
+		{"search code", "search_code"},
+		{"1password_lookup", "_1password_lookup"},
+	}
+	for _, tt := range tests {
+		if got := functionName(tt.name); got != tt.want {
+			t.Errorf("functionName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	long := functionName(forecastToolName)
+	if len(long) > maxFunctionNameLength {
+		t.Errorf("functionName() = %q, want at most %d characters", long, maxFunctionNameLength)
+	}
+	if other := functionName(forecastToolName + "s"); other == long {
+		t.Errorf("long names %q and %q shortened to the same name", forecastToolName, forecastToolName+"s")
+	}
+}
+
+func TestParametersSchema(t *testing.T) {
+	got, ok := parametersSchema(map[string]any{
+		"$schema":    "http://json-schema.org/draft-07/schema#",
+		"properties": map[string]any{"query": map[string]any{"type": "string"}},
+	}).(map[string]any)
+	if !ok {
+		t.Fatalf("parametersSchema() = %T, want a map", got)
+	}
+	if _, ok := got["$schema"]; ok {
+		t.Errorf("schema = %v, want $schema removed", got)
+	}
+	if got["type"] != "object" {
+		t.Errorf("schema type = %v, want object", got["type"])
+	}
+	if parametersSchema(nil) != nil {
+		t.Error("parametersSchema(nil) != nil")
+	}
+}
+
+func TestRestoreFunctionCalls(t *testing.T) {
+	resp := &model.LLMResponse{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+		{FunctionCall: &genai.FunctionCall{Name: "short_name"}},
+		{FunctionCall: &genai.FunctionCall{Name: "list_repos", Args: map[string]any{"org": "acme"}}},
+	}}}
+
+	restoreFunctionCalls(resp, map[string]string{"short_name": "the original name"})
+
+	first, second := resp.Content.Parts[0].FunctionCall, resp.Content.Parts[1].FunctionCall
+	if first.Name != "the original name" {
+		t.Errorf("name = %q, want the declared tool name", first.Name)
+	}
+	if first.Args == nil {
+		t.Error("args = nil, want an empty object for a call without arguments")
+	}
+	if second.Name != "list_repos" || second.Args["org"] != "acme" {
+		t.Errorf("second call = %+v, want it unchanged", second)
+	}
+}
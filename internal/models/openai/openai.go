@@ -5,7 +5,12 @@ import (
 	"fmt"
 	"iter"
 	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
+	"github.com/lewisedginton/general_purpose_chatbot/internal/models"
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 	"google.golang.org/adk/model"
@@ -16,10 +21,86 @@ type Model struct {
 	client    *openai.Client
 	modelName string
 	logger    *slog.Logger
+	params    models.Params
+	// clientOptions configure the API client, e.g. its HTTP client
+	clientOptions []option.RequestOption
+	// azure sends the API key in Azure's api-key header instead of as a bearer token
+	azure bool
+}
+
+// Option configures optional Model settings.
+type Option func(*Model)
+
+// WithParams sets default generation parameters applied to every request.
+func WithParams(params models.Params) Option {
+	return func(o *Model) {
+		o.params = params
+	}
+}
+
+// WithHTTPClient sets the HTTP client API requests are sent with, e.g. one using a proxy.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *Model) {
+		o.clientOptions = append(o.clientOptions, option.WithHTTPClient(client))
+	}
+}
+
+// WithBaseURL sends API requests to baseURL instead of https://api.openai.com/v1, e.g. an
+// OpenAI-compatible gateway such as LiteLLM. Empty keeps the default.
+func WithBaseURL(baseURL string) Option {
+	return func(o *Model) {
+		if baseURL != "" {
+			o.clientOptions = append(o.clientOptions, option.WithBaseURL(baseURL))
+		}
+	}
+}
+
+// WithOrganization sends requests on behalf of an organization (the OpenAI-Organization
+// header) and, if set, a project (OpenAI-Project). Empty values are not sent.
+func WithOrganization(organization, project string) Option {
+	return func(o *Model) {
+		if organization != "" {
+			o.clientOptions = append(o.clientOptions, option.WithOrganization(organization))
+		}
+		if project != "" {
+			o.clientOptions = append(o.clientOptions, option.WithProject(project))
+		}
+	}
+}
+
+// WithAzure sends requests to an Azure OpenAI deployment instead of the OpenAI API.
+// Azure routes by deployment rather than model name, e.g.
+// https://my-resource.openai.azure.com/openai/deployments/my-gpt4o/chat/completions,
+// requires an api-version query parameter on every request and takes the API key in an
+// api-key header.
+func WithAzure(endpoint, deployment, apiVersion string) Option {
+	return func(o *Model) {
+		baseURL := strings.TrimRight(endpoint, "/") + "/openai/deployments/" + url.PathEscape(deployment) + "/"
+		o.clientOptions = append(o.clientOptions,
+			option.WithBaseURL(baseURL),
+			option.WithQuery("api-version", apiVersion))
+		o.azure = true
+	}
+}
+
+// WithTimeout bounds each API call attempt; 0 for no limit.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *Model) {
+		if timeout > 0 {
+			o.clientOptions = append(o.clientOptions, option.WithRequestTimeout(timeout))
+		}
+	}
+}
+
+// WithMaxRetries sets how many times the client retries a failed API call.
+func WithMaxRetries(retries int) Option {
+	return func(o *Model) {
+		o.clientOptions = append(o.clientOptions, option.WithMaxRetries(retries))
+	}
 }
 
 // New creates a new OpenAI model instance.
-func New(apiKey, modelName string) (*Model, error) {
+func New(apiKey, modelName string, opts ...Option) (*Model, error) {
 	if apiKey == "" {
 		return nil, fmt.Errorf("API key is required")
 	}
@@ -27,13 +108,22 @@ func New(apiKey, modelName string) (*Model, error) {
 		return nil, fmt.Errorf("model name is required")
 	}
 
-	client := openai.NewClient(option.WithAPIKey(apiKey))
-
-	return &Model{
-		client:    &client,
+	m := &Model{
 		modelName: modelName,
 		logger:    slog.Default(),
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	auth := []option.RequestOption{option.WithAPIKey(apiKey)}
+	if m.azure {
+		// Drop the bearer token the client adds from the key or OPENAI_API_KEY
+		auth = []option.RequestOption{option.WithHeaderDel("authorization"), option.WithHeader("api-key", apiKey)}
+	}
+	client := openai.NewClient(append(auth, m.clientOptions...)...)
+	m.client = &client
+
+	return m, nil
 }
 
 // Name returns the model name.
@@ -59,6 +149,9 @@ func (o *Model) GenerateContent(ctx context.Context, req *model.LLMRequest, stre
 //
 //nolint:gocyclo,revive // API integration requires handling many response conditions
 func (o *Model) generateContentNonStreaming(ctx context.Context, req *model.LLMRequest) (*model.LLMResponse, error) {
+	// Fill in configured defaults for anything the request leaves unset
+	o.params.Apply(req)
+
 	// Transform ADK request to OpenAI format
 	messages, err := transformADKToOpenAI(req.Contents)
 	if err != nil {
@@ -114,6 +207,11 @@ func (o *Model) generateContentNonStreaming(ctx context.Context, req *model.LLMR
 		}
 	}
 
+	// Add seed if specified, for reproducible sampling
+	if req.Config != nil && req.Config.Seed != nil {
+		params.Seed = openai.Int(int64(*req.Config.Seed))
+	}
+
 	// Transform and add tools if present
 	if req.Tools != nil {
 		tools := transformToolsToOpenAI(req.Tools)
@@ -134,5 +232,13 @@ func (o *Model) generateContentNonStreaming(ctx context.Context, req *model.LLMR
 		return nil, fmt.Errorf("failed to transform response: %w", err)
 	}
 
+	if params.Seed.Valid() {
+		o.logger.Info("generated response with seed",
+			slog.String("model", o.modelName),
+			slog.Int64("seed", params.Seed.Value),
+			slog.String("system_fingerprint", completion.SystemFingerprint),
+		)
+	}
+
 	return response, nil
 }
@@ -2,9 +2,17 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/lewisedginton/general_purpose_chatbot/internal/models"
 	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
 )
@@ -489,3 +497,235 @@ func TestCreateToolResultMessage(t *testing.T) {
 		t.Error("CreateToolResultMessage() did not create a tool message")
 	}
 }
+
+// newTestModel returns a Model whose client talks to a test server that records
+// the request body and replies with a minimal chat completion.
+func newTestModel(t *testing.T, captured *map[string]any, opts ...Option) *Model {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(captured); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1","object":"chat.completion","created":0,"model":"gpt-test",` +
+			`"choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],` +
+			`"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := openai.NewClient(
+		option.WithAPIKey("test-api-key"),
+		option.WithBaseURL(server.URL),
+		option.WithMaxRetries(0),
+	)
+	m := &Model{
+		client:    &client,
+		modelName: "gpt-test",
+		logger:    slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func TestModel_GenerateContent_AppliesParams(t *testing.T) {
+	temperature := 1.2
+	topP := 0.5
+
+	var body map[string]any
+	m := newTestModel(t, &body, WithParams(models.Params{
+		Temperature:   &temperature,
+		MaxTokens:     2048,
+		TopP:          &topP,
+		StopSequences: []string{"STOP"},
+	}))
+
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{genai.NewContentFromText("hello", genai.RoleUser)},
+	}
+	for _, err := range m.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+	}
+
+	if got, ok := body["temperature"].(float64); !ok || math.Abs(got-temperature) > 1e-6 {
+		t.Errorf("temperature = %v, want %v", body["temperature"], temperature)
+	}
+	if body["max_tokens"] != float64(2048) {
+		t.Errorf("max_tokens = %v, want 2048", body["max_tokens"])
+	}
+	if got, ok := body["top_p"].(float64); !ok || math.Abs(got-topP) > 1e-6 {
+		t.Errorf("top_p = %v, want %v", body["top_p"], topP)
+	}
+	if stops, ok := body["stop"].([]any); !ok || len(stops) != 1 || stops[0] != "STOP" {
+		t.Errorf("stop = %v, want [STOP]", body["stop"])
+	}
+}
+
+func TestModel_GenerateContent_ForwardsSeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   models.Params
+		config   *genai.GenerateContentConfig
+		wantSeed any
+	}{
+		{
+			name:     "seed from params",
+			params:   models.Params{Seed: intPtr(42)},
+			wantSeed: float64(42),
+		},
+		{
+			name:     "seed from request takes precedence",
+			params:   models.Params{Seed: intPtr(42)},
+			config:   &genai.GenerateContentConfig{Seed: genai.Ptr(int32(7))},
+			wantSeed: float64(7),
+		},
+		{
+			name:     "no seed omitted from payload",
+			params:   models.Params{},
+			wantSeed: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]any
+			m := newTestModel(t, &body, WithParams(tt.params))
+
+			req := &model.LLMRequest{
+				Contents: []*genai.Content{genai.NewContentFromText("hello", genai.RoleUser)},
+				Config:   tt.config,
+			}
+			for _, err := range m.GenerateContent(context.Background(), req, false) {
+				if err != nil {
+					t.Fatalf("GenerateContent() error = %v", err)
+				}
+			}
+
+			if body["seed"] != tt.wantSeed {
+				t.Errorf("seed = %v, want %v", body["seed"], tt.wantSeed)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestNew_BaseURLAndOrganization(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []Option
+		wantOrg     string
+		wantProject string
+	}{
+		{"organization and project", []Option{WithOrganization("org-123", "proj_456")}, "org-123", "proj_456"},
+		{"organization only", []Option{WithOrganization("org-123", "")}, "org-123", ""},
+		{"neither", nil, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":"chatcmpl-1","object":"chat.completion","created":0,"model":"gpt-test",` +
+					`"choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+			}))
+			defer gateway.Close()
+
+			opts := append([]Option{WithBaseURL(gateway.URL + "/openai/v1"), WithTimeout(time.Minute)}, tt.opts...)
+			m, err := New("test-api-key", "gpt-test", opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			req := &model.LLMRequest{Contents: []*genai.Content{genai.NewContentFromText("hello", genai.RoleUser)}}
+			for _, err := range m.GenerateContent(context.Background(), req, false) {
+				if err != nil {
+					t.Fatalf("GenerateContent() error = %v", err)
+				}
+			}
+
+			if got == nil || got.URL.Path != "/openai/v1/chat/completions" {
+				t.Fatalf("request = %v, want /openai/v1/chat/completions on the configured base URL", got)
+			}
+			if org := got.Header.Get("OpenAI-Organization"); org != tt.wantOrg {
+				t.Errorf("OpenAI-Organization = %q, want %q", org, tt.wantOrg)
+			}
+			if project := got.Header.Get("OpenAI-Project"); project != tt.wantProject {
+				t.Errorf("OpenAI-Project = %q, want %q", project, tt.wantProject)
+			}
+		})
+	}
+}
+
+func TestNew_Azure(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1","object":"chat.completion","created":0,"model":"gpt-4o",` +
+			`"choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	m, err := New("azure-key", "my-gpt4o", WithAzure(server.URL+"/", "my-gpt4o", "2024-10-21"), WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	req := &model.LLMRequest{Contents: []*genai.Content{genai.NewContentFromText("hello", genai.RoleUser)}}
+	for _, err := range m.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+	}
+
+	if got == nil {
+		t.Fatal("no request reached the server")
+	}
+	if got.URL.Path != "/openai/deployments/my-gpt4o/chat/completions" {
+		t.Errorf("path = %q, want the deployment's chat completions route", got.URL.Path)
+	}
+	if version := got.URL.Query().Get("api-version"); version != "2024-10-21" {
+		t.Errorf("api-version = %q, want 2024-10-21", version)
+	}
+	if key := got.Header.Get("api-key"); key != "azure-key" {
+		t.Errorf("api-key header = %q, want azure-key", key)
+	}
+	if auth := got.Header.Get("Authorization"); auth != "" {
+		t.Errorf("Authorization = %q, want no bearer token", auth)
+	}
+}
+
+func TestNew_TimeoutCancelsSlowRequest(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	m, err := New("test-api-key", "gpt-test", WithBaseURL(server.URL), WithTimeout(50*time.Millisecond), WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	start := time.Now()
+	req := &model.LLMRequest{Contents: []*genai.Content{genai.NewContentFromText("hello", genai.RoleUser)}}
+	for _, err := range m.GenerateContent(context.Background(), req, false) {
+		if err == nil {
+			t.Fatal("GenerateContent() error = nil, want a timeout")
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("request took %v, want it cancelled after the 50ms timeout", elapsed)
+	}
+}
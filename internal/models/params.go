@@ -0,0 +1,83 @@
+// Package models provides functionality shared by the LLM provider implementations.
+package models
+
+import (
+	"context"
+	"iter"
+	"log/slog"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// Params holds default generation parameters applied to every request.
+// Values already set on a request take precedence over these defaults.
+type Params struct {
+	Temperature   *float64
+	MaxTokens     int
+	TopP          *float64
+	StopSequences []string
+	// Seed requests reproducible sampling. It is forwarded to OpenAI and Gemini;
+	// Anthropic has no equivalent parameter and ignores it.
+	Seed *int
+}
+
+// Apply fills any generation parameters the request leaves unset with the defaults.
+func (p Params) Apply(req *model.LLMRequest) {
+	if req.Config == nil {
+		req.Config = &genai.GenerateContentConfig{}
+	}
+	cfg := req.Config
+
+	if cfg.Temperature == nil && p.Temperature != nil {
+		cfg.Temperature = genai.Ptr(float32(*p.Temperature))
+	}
+	if cfg.MaxOutputTokens == 0 && p.MaxTokens > 0 {
+		cfg.MaxOutputTokens = int32(p.MaxTokens) //nolint:gosec // G115: max tokens is validated in config
+	}
+	if cfg.TopP == nil && p.TopP != nil {
+		cfg.TopP = genai.Ptr(float32(*p.TopP))
+	}
+	if len(cfg.StopSequences) == 0 && len(p.StopSequences) > 0 {
+		cfg.StopSequences = p.StopSequences
+	}
+	if cfg.Seed == nil && p.Seed != nil {
+		cfg.Seed = genai.Ptr(int32(*p.Seed)) //nolint:gosec // G115: seed range is validated in config
+	}
+}
+
+// paramsModel wraps a model.LLM and applies default params to each request.
+type paramsModel struct {
+	model.LLM
+	params Params
+}
+
+// WrapWithParams returns an LLM that applies params to each request before
+// delegating to llm. It is used for providers whose constructors we don't own.
+func WrapWithParams(llm model.LLM, params Params) model.LLM {
+	return &paramsModel{LLM: llm, params: params}
+}
+
+// GenerateContent applies the default params and delegates to the wrapped model.
+// When a seed is in effect it is logged alongside each response.
+func (m *paramsModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	m.params.Apply(req)
+	if req.Config.Seed == nil {
+		return m.LLM.GenerateContent(ctx, req, stream)
+	}
+
+	seed := *req.Config.Seed
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for resp, err := range m.LLM.GenerateContent(ctx, req, stream) {
+			if err == nil {
+				slog.Default().Info("generated response with seed",
+					slog.String("model", m.Name()),
+					slog.Int("seed", int(seed)),
+				)
+			}
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"context"
+	"iter"
+	"testing"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// recordingLLM captures the last request it receives.
+type recordingLLM struct {
+	lastReq *model.LLMRequest
+}
+
+func (r *recordingLLM) Name() string { return "recording" }
+
+func (r *recordingLLM) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	r.lastReq = req
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{}, nil)
+	}
+}
+
+func TestParams_Apply(t *testing.T) {
+	temperature := 0.0
+	topP := 0.8
+	seed := 42
+	params := Params{
+		Temperature:   &temperature,
+		MaxTokens:     512,
+		TopP:          &topP,
+		StopSequences: []string{"END"},
+		Seed:          &seed,
+	}
+
+	t.Run("fills unset fields", func(t *testing.T) {
+		req := &model.LLMRequest{}
+		params.Apply(req)
+
+		if req.Config == nil {
+			t.Fatal("expected config to be created")
+		}
+		if req.Config.Temperature == nil || *req.Config.Temperature != 0 {
+			t.Errorf("Temperature = %v, want 0", req.Config.Temperature)
+		}
+		if req.Config.MaxOutputTokens != 512 {
+			t.Errorf("MaxOutputTokens = %d, want 512", req.Config.MaxOutputTokens)
+		}
+		if req.Config.TopP == nil || *req.Config.TopP != float32(0.8) {
+			t.Errorf("TopP = %v, want 0.8", req.Config.TopP)
+		}
+		if len(req.Config.StopSequences) != 1 || req.Config.StopSequences[0] != "END" {
+			t.Errorf("StopSequences = %v, want [END]", req.Config.StopSequences)
+		}
+		if req.Config.Seed == nil || *req.Config.Seed != 42 {
+			t.Errorf("Seed = %v, want 42", req.Config.Seed)
+		}
+	})
+
+	t.Run("keeps request values", func(t *testing.T) {
+		req := &model.LLMRequest{Config: &genai.GenerateContentConfig{
+			Temperature:     genai.Ptr(float32(0.7)),
+			MaxOutputTokens: 100,
+			StopSequences:   []string{"STOP"},
+		}}
+		params.Apply(req)
+
+		if *req.Config.Temperature != float32(0.7) {
+			t.Errorf("Temperature = %v, want 0.7", *req.Config.Temperature)
+		}
+		if req.Config.MaxOutputTokens != 100 {
+			t.Errorf("MaxOutputTokens = %d, want 100", req.Config.MaxOutputTokens)
+		}
+		if req.Config.StopSequences[0] != "STOP" {
+			t.Errorf("StopSequences = %v, want [STOP]", req.Config.StopSequences)
+		}
+	})
+
+	t.Run("zero params leave request untouched", func(t *testing.T) {
+		req := &model.LLMRequest{}
+		Params{}.Apply(req)
+
+		if req.Config.Temperature != nil || req.Config.TopP != nil || req.Config.MaxOutputTokens != 0 || req.Config.Seed != nil {
+			t.Errorf("expected empty config, got %+v", req.Config)
+		}
+	})
+}
+
+func TestWrapWithParams(t *testing.T) {
+	inner := &recordingLLM{}
+	llm := WrapWithParams(inner, Params{MaxTokens: 8192})
+
+	if llm.Name() != "recording" {
+		t.Errorf("Name() = %q, want %q", llm.Name(), "recording")
+	}
+
+	for _, err := range llm.GenerateContent(context.Background(), &model.LLMRequest{}, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+	}
+
+	if inner.lastReq == nil || inner.lastReq.Config == nil {
+		t.Fatal("expected wrapped model to receive a request with config")
+	}
+	if inner.lastReq.Config.MaxOutputTokens != 8192 {
+		t.Errorf("MaxOutputTokens = %d, want 8192", inner.lastReq.Config.MaxOutputTokens)
+	}
+}
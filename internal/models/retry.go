@@ -0,0 +1,68 @@
+package models
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// RetryConfig controls retrying failed calls, such as to a model API. Retries back off
+// exponentially from InitialBackoff, capped at MaxBackoff.
+type RetryConfig struct {
+	MaxRetries     int           // Retries after the first attempt; 0 to not retry
+	InitialBackoff time.Duration // Delay before the first retry
+	MaxBackoff     time.Duration // Longest delay between retries
+}
+
+// backoff returns the delay before retry number attempt (counting from 0)
+func (c RetryConfig) backoff(attempt int) time.Duration {
+	delay := c.InitialBackoff
+	for range attempt {
+		delay *= 2
+		if c.MaxBackoff > 0 && delay >= c.MaxBackoff {
+			return c.MaxBackoff
+		}
+	}
+	if c.MaxBackoff > 0 && delay > c.MaxBackoff {
+		return c.MaxBackoff
+	}
+	return delay
+}
+
+// MaxRetryAfter caps how long a retry waits when the server asks for a longer delay
+const MaxRetryAfter = time.Minute
+
+// Retry calls fn until it succeeds, fails with an error retryable rejects, or MaxRetries
+// retries have failed, returning the last error. It gives up waiting if ctx is done.
+func Retry(ctx context.Context, cfg RetryConfig, retryable func(error) bool, fn func(context.Context) error) error {
+	return RetryAfter(ctx, cfg, retryable, nil, fn)
+}
+
+// RetryAfter is Retry for APIs that say how long to wait before retrying. retryAfter returns
+// the delay the server asked for with an error, or 0 if none; a retry waits that long, up to
+// MaxRetryAfter, when it's longer than the backoff.
+func RetryAfter(ctx context.Context, cfg RetryConfig, retryable func(error) bool, retryAfter func(error) time.Duration, fn func(context.Context) error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || attempt >= cfg.MaxRetries || ctx.Err() != nil || !retryable(err) {
+			return err
+		}
+
+		delay := cfg.backoff(attempt)
+		if retryAfter != nil {
+			delay = max(delay, min(retryAfter(err), MaxRetryAfter))
+		}
+		slog.Default().Warn("call failed, retrying",
+			slog.Int("attempt", attempt+1),
+			slog.Duration("delay", delay),
+			slog.String("error", err.Error()))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
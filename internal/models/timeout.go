@@ -0,0 +1,49 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+
+	"google.golang.org/adk/model"
+)
+
+// ErrCallTimeout is returned when a single model call takes longer than its timeout
+var ErrCallTimeout = errors.New("model call timed out")
+
+// timeoutModel wraps a model.LLM and bounds how long each call to it may take
+type timeoutModel struct {
+	model.LLM
+	timeout time.Duration
+}
+
+// WrapWithTimeout returns an LLM whose calls fail with ErrCallTimeout once they've run for
+// timeout. It bounds each model invocation on its own, so a stuck provider call fails fast
+// while the rest of the turn (tool calls and further model calls) keeps its own budget.
+// A timeout of 0 or less returns llm unchanged.
+func WrapWithTimeout(llm model.LLM, timeout time.Duration) model.LLM {
+	if timeout <= 0 {
+		return llm
+	}
+	return &timeoutModel{LLM: llm, timeout: timeout}
+}
+
+// GenerateContent calls the wrapped model with a context that expires after the timeout
+func (m *timeoutModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		callCtx, cancel := context.WithTimeoutCause(ctx, m.timeout, ErrCallTimeout)
+		defer cancel()
+
+		for resp, err := range m.LLM.GenerateContent(callCtx, req, stream) {
+			// Report our own deadline distinctly from the caller cancelling the turn
+			if err != nil && ctx.Err() == nil && errors.Is(context.Cause(callCtx), ErrCallTimeout) {
+				err = fmt.Errorf("%w after %s: %w", ErrCallTimeout, m.timeout, err)
+			}
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
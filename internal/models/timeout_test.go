@@ -0,0 +1,83 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// slowLLM replies after delay, or fails when its context ends first
+type slowLLM struct {
+	delay time.Duration
+}
+
+func (s *slowLLM) Name() string { return "slow" }
+
+func (s *slowLLM) GenerateContent(ctx context.Context, _ *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		select {
+		case <-time.After(s.delay):
+			yield(&model.LLMResponse{Content: genai.NewContentFromText("done", genai.RoleModel)}, nil)
+		case <-ctx.Done():
+			yield(nil, ctx.Err())
+		}
+	}
+}
+
+func TestWrapWithTimeout_AbortsSlowCall(t *testing.T) {
+	// The turn has plenty of time left; only the model call is bounded
+	turnCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	llm := WrapWithTimeout(&slowLLM{delay: 5 * time.Second}, 50*time.Millisecond)
+
+	start := time.Now()
+	var gotErr error
+	for _, err := range llm.GenerateContent(turnCtx, &model.LLMRequest{}, false) {
+		gotErr = err
+	}
+	elapsed := time.Since(start)
+
+	if !errors.Is(gotErr, ErrCallTimeout) {
+		t.Errorf("error = %v, want ErrCallTimeout", gotErr)
+	}
+	if elapsed > time.Second {
+		t.Errorf("call took %s, want it aborted at the 50ms model timeout", elapsed)
+	}
+	if turnCtx.Err() != nil {
+		t.Error("turn context ended, want only the model call to time out")
+	}
+
+	// The turn carries on: a later call within the timeout succeeds
+	llm = WrapWithTimeout(&slowLLM{delay: time.Millisecond}, 50*time.Millisecond)
+	for resp, err := range llm.GenerateContent(turnCtx, &model.LLMRequest{}, false) {
+		if err != nil || resp.Content.Parts[0].Text != "done" {
+			t.Errorf("GenerateContent() = %v, %v; want the reply", resp, err)
+		}
+	}
+}
+
+func TestWrapWithTimeout_CallerCancellation(t *testing.T) {
+	// Cancelling the turn isn't reported as a model timeout
+	turnCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	llm := WrapWithTimeout(&slowLLM{delay: 5 * time.Second}, time.Minute)
+	for _, err := range llm.GenerateContent(turnCtx, &model.LLMRequest{}, false) {
+		if errors.Is(err, ErrCallTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("error = %v, want the turn's deadline", err)
+		}
+	}
+}
+
+func TestWrapWithTimeout_Disabled(t *testing.T) {
+	inner := &slowLLM{}
+	if WrapWithTimeout(inner, 0) != model.LLM(inner) {
+		t.Error("WrapWithTimeout with no timeout should return the model unchanged")
+	}
+}
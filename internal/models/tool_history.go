@@ -0,0 +1,152 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// How tool calls and results from older turns are sent to the model
+const (
+	ToolHistoryAll       = "all"       // Send them in full
+	ToolHistorySummarize = "summarize" // Replace them with a short text summary
+	ToolHistoryOmit      = "omit"      // Leave them out
+)
+
+// maxToolSummaryChars caps the arguments and result quoted in a tool call's summary
+const maxToolSummaryChars = 200
+
+// ToolHistoryConfig controls how tool calls and results in the conversation history are sent
+type ToolHistoryConfig struct {
+	// Policy is ToolHistoryAll, ToolHistorySummarize or ToolHistoryOmit
+	Policy string
+	// KeepTurns is how many of the most recent user turns keep their tool calls in full. The
+	// current turn always does, so the agent can carry on with the tools it's using.
+	KeepTurns int
+}
+
+// toolHistoryModel wraps a model.LLM and summarizes or omits tool calls from older turns
+type toolHistoryModel struct {
+	model.LLM
+	config ToolHistoryConfig
+}
+
+// WrapWithToolHistory returns an LLM that sends the tool calls and results of turns older
+// than cfg.KeepTurns as cfg.Policy says, keeping the user's and model's text as it is.
+// With ToolHistoryAll, or an unknown policy, llm is returned unchanged.
+//
+// The window moves with each new turn, so a turn that was sent in full is sent rewritten
+// once it's older than KeepTurns. That changes the request's prefix from that turn on, and a
+// provider's prompt cache can't reuse anything after it.
+func WrapWithToolHistory(llm model.LLM, cfg ToolHistoryConfig) model.LLM {
+	if cfg.Policy != ToolHistorySummarize && cfg.Policy != ToolHistoryOmit {
+		return llm
+	}
+	return &toolHistoryModel{LLM: llm, config: cfg}
+}
+
+// GenerateContent rewrites the request's older tool calls and delegates to the wrapped model
+func (m *toolHistoryModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	trimmed := *req
+	trimmed.Contents = applyToolHistory(req.Contents, m.config)
+	return m.LLM.GenerateContent(ctx, &trimmed, stream)
+}
+
+// applyToolHistory returns contents with the tool calls and results of turns older than
+// cfg.KeepTurns summarized or omitted as cfg.Policy says. A turn starts with a message from
+// the user; tool results, which are sent with the user's role, don't start one. contents
+// isn't modified; rewritten contents are copies.
+func applyToolHistory(contents []*genai.Content, cfg ToolHistoryConfig) []*genai.Content {
+	if cfg.Policy != ToolHistorySummarize && cfg.Policy != ToolHistoryOmit {
+		return contents
+	}
+
+	// Find where the turns kept in full start
+	keep := max(cfg.KeepTurns, 1)
+	start := len(contents)
+	for i := len(contents) - 1; i >= 0 && keep > 0; i-- {
+		if startsTurn(contents[i]) {
+			keep--
+			start = i
+		}
+	}
+	if keep > 0 {
+		return contents
+	}
+
+	result := make([]*genai.Content, 0, len(contents))
+	for _, content := range contents[:start] {
+		if content == nil || !hasToolParts(content) {
+			result = append(result, content)
+			continue
+		}
+		rewritten := &genai.Content{Role: content.Role}
+		for _, part := range content.Parts {
+			switch {
+			case part == nil:
+			case part.FunctionCall != nil:
+				if cfg.Policy == ToolHistorySummarize {
+					rewritten.Parts = append(rewritten.Parts, genai.NewPartFromText(summarizeCall(part.FunctionCall)))
+				}
+			case part.FunctionResponse != nil:
+				if cfg.Policy == ToolHistorySummarize {
+					rewritten.Parts = append(rewritten.Parts, genai.NewPartFromText(summarizeResponse(part.FunctionResponse)))
+				}
+			default:
+				rewritten.Parts = append(rewritten.Parts, part)
+			}
+		}
+		if len(rewritten.Parts) > 0 {
+			result = append(result, rewritten)
+		}
+	}
+	return append(result, contents[start:]...)
+}
+
+// startsTurn reports whether content is a message from the user rather than a tool result
+func startsTurn(content *genai.Content) bool {
+	if content == nil || content.Role != genai.RoleUser {
+		return false
+	}
+	for _, part := range content.Parts {
+		if part != nil && part.FunctionResponse != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// hasToolParts reports whether content has any tool calls or results
+func hasToolParts(content *genai.Content) bool {
+	for _, part := range content.Parts {
+		if part != nil && (part.FunctionCall != nil || part.FunctionResponse != nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// summarizeCall describes a tool call in a line of text
+func summarizeCall(call *genai.FunctionCall) string {
+	args, _ := json.Marshal(call.Args)
+	return fmt.Sprintf("[Called tool %s with %s]", call.Name, truncateSummary(string(args)))
+}
+
+// summarizeResponse describes a tool result in a line of text
+func summarizeResponse(response *genai.FunctionResponse) string {
+	output, _ := json.Marshal(response.Response)
+	return fmt.Sprintf("[Tool %s returned %s]", response.Name, truncateSummary(string(output)))
+}
+
+// truncateSummary shortens s to maxToolSummaryChars runes
+func truncateSummary(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxToolSummaryChars {
+		return s
+	}
+	return string(runes[:maxToolSummaryChars]) + "…"
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// toolTurn returns a turn in which the user asks question, the model calls a search tool
+// and answers with answer
+func toolTurn(question, query, answer string) []*genai.Content {
+	return []*genai.Content{
+		genai.NewContentFromText(question, genai.RoleUser),
+		{Role: genai.RoleModel, Parts: []*genai.Part{genai.NewPartFromFunctionCall("web_search", map[string]any{"query": query})}},
+		{Role: genai.RoleUser, Parts: []*genai.Part{genai.NewPartFromFunctionResponse("web_search", map[string]any{"result": "results for " + query})}},
+		genai.NewContentFromText(answer, genai.RoleModel),
+	}
+}
+
+// history returns three tool-using turns followed by the current message
+func history() []*genai.Content {
+	var contents []*genai.Content
+	contents = append(contents, toolTurn("weather in Paris?", "paris weather", "It's sunny in Paris.")...)
+	contents = append(contents, toolTurn("and in Rome?", "rome weather", "It's raining in Rome.")...)
+	contents = append(contents, toolTurn("and in Oslo?", "oslo weather", "It's snowing in Oslo.")...)
+	return append(contents, genai.NewContentFromText("thanks!", genai.RoleUser))
+}
+
+// toolParts counts the tool calls and results in contents
+func toolParts(contents []*genai.Content) int {
+	n := 0
+	for _, content := range contents {
+		for _, part := range content.Parts {
+			if part.FunctionCall != nil || part.FunctionResponse != nil {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// joinedText returns the text of contents, one content per line
+func joinedText(contents []*genai.Content) string {
+	var b strings.Builder
+	for _, content := range contents {
+		for _, part := range content.Parts {
+			b.WriteString(part.Text)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func TestApplyToolHistory_Omit(t *testing.T) {
+	contents := history()
+	got := applyToolHistory(contents, ToolHistoryConfig{Policy: ToolHistoryOmit, KeepTurns: 2})
+
+	// The Paris and Rome turns lose their tool calls; the Oslo turn and the current one are kept
+	if want := 2; toolParts(got) != want {
+		t.Errorf("got %d tool parts, want %d (the Oslo turn's)", toolParts(got), want)
+	}
+	if len(got) != len(contents)-4 {
+		t.Errorf("got %d contents, want %d", len(got), len(contents)-4)
+	}
+	text := joinedText(got)
+	for _, want := range []string{"weather in Paris?", "It's sunny in Paris.", "and in Rome?", "It's raining in Rome.", "It's snowing in Oslo.", "thanks!"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("history should keep %q, got:\n%s", want, text)
+		}
+	}
+	for i, content := range got[len(got)-5:] {
+		if content != contents[len(contents)-5+i] {
+			t.Errorf("recent content %d was rewritten", i)
+		}
+	}
+
+	// The request's contents are left as they were
+	if toolParts(contents) != 6 {
+		t.Errorf("original contents were modified, got %d tool parts", toolParts(contents))
+	}
+}
+
+func TestApplyToolHistory_Summarize(t *testing.T) {
+	contents := history()
+	got := applyToolHistory(contents, ToolHistoryConfig{Policy: ToolHistorySummarize, KeepTurns: 1})
+
+	if len(got) != len(contents) {
+		t.Errorf("got %d contents, want %d", len(got), len(contents))
+	}
+	if toolParts(got) != 0 {
+		t.Errorf("got %d tool parts, want none before the current turn", toolParts(got))
+	}
+	text := joinedText(got)
+	for _, want := range []string{
+		`[Called tool web_search with {"query":"paris weather"}]`,
+		`[Tool web_search returned {"result":"results for oslo weather"}]`,
+		"It's snowing in Oslo.",
+		"thanks!",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("history should contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestApplyToolHistory_KeepsCurrentTurn(t *testing.T) {
+	// The agent is mid-turn: the user's message, a tool call and its result
+	contents := toolTurn("weather in Paris?", "paris weather", "")[:3]
+	got := applyToolHistory(contents, ToolHistoryConfig{Policy: ToolHistoryOmit, KeepTurns: 0})
+	if toolParts(got) != 2 {
+		t.Errorf("got %d tool parts, want the current turn's 2", toolParts(got))
+	}
+}
+
+func TestApplyToolHistory_SummarizeTruncatesLongResults(t *testing.T) {
+	contents := []*genai.Content{
+		genai.NewContentFromText("read the file", genai.RoleUser),
+		{Role: genai.RoleModel, Parts: []*genai.Part{genai.NewPartFromFunctionCall("read_file", map[string]any{"path": "a.txt"})}},
+		{Role: genai.RoleUser, Parts: []*genai.Part{genai.NewPartFromFunctionResponse("read_file", map[string]any{"content": strings.Repeat("x", 5000)})}},
+		genai.NewContentFromText("done", genai.RoleModel),
+		genai.NewContentFromText("next", genai.RoleUser),
+	}
+	got := applyToolHistory(contents, ToolHistoryConfig{Policy: ToolHistorySummarize, KeepTurns: 1})
+	summary := got[2].Parts[0].Text
+	if len([]rune(summary)) > maxToolSummaryChars+50 || !strings.Contains(summary, "…") {
+		t.Errorf("summary should be truncated, got %d chars: %q", len(summary), summary)
+	}
+}
+
+func TestWrapWithToolHistory(t *testing.T) {
+	inner := &recordingLLM{}
+	if got := WrapWithToolHistory(inner, ToolHistoryConfig{Policy: ToolHistoryAll}); got != model.LLM(inner) {
+		t.Error("the all policy should return the model unchanged")
+	}
+
+	llm := WrapWithToolHistory(inner, ToolHistoryConfig{Policy: ToolHistoryOmit, KeepTurns: 1})
+	req := &model.LLMRequest{Contents: history()}
+	for _, err := range llm.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+	}
+	if toolParts(inner.lastReq.Contents) != 0 {
+		t.Errorf("model got %d tool parts, want none", toolParts(inner.lastReq.Contents))
+	}
+	if toolParts(req.Contents) != 6 {
+		t.Errorf("caller's request was modified, got %d tool parts", toolParts(req.Contents))
+	}
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/tokens"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// TruncatedMessagesKey is the response CustomMetadata key a provider sets to the number of
+// history messages it dropped to fit the request into the context window
+const TruncatedMessagesKey = "truncated_messages"
+
+// Usage describes the size of one model call, for right-sizing history windows and budgets
+type Usage struct {
+	Provider string
+	Model    string
+
+	// Tokens reported by the provider; 0 when it reports none
+	PromptTokens     int
+	CompletionTokens int
+
+	// EstimatedPromptTokens is the request's size as estimated by the tokens package, before
+	// any truncation by the provider
+	EstimatedPromptTokens int
+
+	TruncatedMessages int  // History messages dropped to fit the context window
+	MaxTokensReached  bool // The reply was cut off at the output token limit
+}
+
+// UsageRecorder is called with the usage of each model call
+type UsageRecorder func(ctx context.Context, usage Usage)
+
+// usageModel wraps a model.LLM and records the usage of each call
+type usageModel struct {
+	model.LLM
+	provider string
+	record   UsageRecorder
+}
+
+// WrapWithUsage returns an LLM that reports the usage of each call made to llm to record,
+// tagged with provider and the model's name. Calls that fail aren't recorded.
+func WrapWithUsage(llm model.LLM, provider string, record UsageRecorder) model.LLM {
+	return &usageModel{LLM: llm, provider: provider, record: record}
+}
+
+// GenerateContent delegates to the wrapped model and records the call's usage once it
+// completes, from the last usage the provider reported
+func (m *usageModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		usage := Usage{
+			Provider:              m.provider,
+			Model:                 m.Name(),
+			EstimatedPromptTokens: estimateRequestTokens(m.Name(), req),
+		}
+		failed := false
+		defer func() {
+			if !failed {
+				m.record(ctx, usage)
+			}
+		}()
+
+		for resp, err := range m.LLM.GenerateContent(ctx, req, stream) {
+			if err != nil {
+				failed = true
+			} else {
+				usage.add(resp)
+			}
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
+
+// add takes the counts a response reports. Streamed responses report the running totals,
+// so the last one seen wins.
+func (u *Usage) add(resp *model.LLMResponse) {
+	if resp == nil {
+		return
+	}
+	if metadata := resp.UsageMetadata; metadata != nil {
+		u.PromptTokens = int(metadata.PromptTokenCount)
+		u.CompletionTokens = int(metadata.CandidatesTokenCount) + int(metadata.ThoughtsTokenCount)
+	}
+	if n, ok := resp.CustomMetadata[TruncatedMessagesKey].(int); ok {
+		u.TruncatedMessages = n
+	}
+	if resp.FinishReason == genai.FinishReasonMaxTokens {
+		u.MaxTokensReached = true
+	}
+}
+
+// estimateRequestTokens estimates the tokens in a request's system instruction and contents
+func estimateRequestTokens(modelName string, req *model.LLMRequest) int {
+	if req == nil {
+		return 0
+	}
+	total := 0
+	if req.Config != nil {
+		total += estimateContentTokens(modelName, req.Config.SystemInstruction)
+	}
+	for _, content := range req.Contents {
+		total += estimateContentTokens(modelName, content)
+	}
+	return total
+}
+
+// estimateContentTokens estimates the tokens in a content's text, function calls and
+// function responses
+func estimateContentTokens(modelName string, content *genai.Content) int {
+	if content == nil {
+		return 0
+	}
+	total := 0
+	for _, part := range content.Parts {
+		if part == nil {
+			continue
+		}
+		total += tokens.Count(modelName, part.Text)
+		if part.FunctionCall != nil {
+			total += estimateJSONTokens(modelName, part.FunctionCall.Args)
+		}
+		if part.FunctionResponse != nil {
+			total += estimateJSONTokens(modelName, part.FunctionResponse.Response)
+		}
+	}
+	return total
+}
+
+func estimateJSONTokens(modelName string, value any) int {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return 0
+	}
+	return tokens.Count(modelName, string(data))
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// meteredLLM answers "ok", reporting 400 prompt and 100 completion tokens and that it
+// dropped two history messages
+type meteredLLM struct{}
+
+func (meteredLLM) Name() string { return "metered-model" }
+
+func (meteredLLM) GenerateContent(context.Context, *model.LLMRequest, bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{
+			Content:        genai.NewContentFromText("ok", genai.RoleModel),
+			CustomMetadata: map[string]any{TruncatedMessagesKey: 2},
+			FinishReason:   genai.FinishReasonStop,
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+				PromptTokenCount:     400,
+				CandidatesTokenCount: 100,
+			},
+		}, nil)
+	}
+}
+
+var errConnectionReset = errors.New("connection reset by peer")
+
+// failingLLM fails every call before producing a response
+type failingLLM struct{}
+
+func (failingLLM) Name() string { return "failing-model" }
+
+func (failingLLM) GenerateContent(context.Context, *model.LLMRequest, bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(nil, errConnectionReset)
+	}
+}
+
+func TestWrapWithUsage_RecordsTurn(t *testing.T) {
+	ctx := context.Background()
+	var recorded []Usage
+	var sessionIDs []string
+	llm := WrapWithUsage(meteredLLM{}, "claude", func(ctx context.Context, usage Usage) {
+		recorded = append(recorded, usage)
+		if invocation, ok := ctx.(agent.InvocationContext); ok {
+			sessionIDs = append(sessionIDs, invocation.Session().ID())
+		}
+	})
+
+	a, err := llmagent.New(llmagent.Config{Name: "test_agent", Model: llm, Instruction: "You are a helpful assistant."})
+	if err != nil {
+		t.Fatalf("llmagent.New() error = %v", err)
+	}
+	sessions := session.InMemoryService()
+	if _, err := sessions.Create(ctx, &session.CreateRequest{AppName: "test", UserID: "user1", SessionID: "session1"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	r, err := runner.New(runner.Config{AppName: "test", Agent: a, SessionService: sessions})
+	if err != nil {
+		t.Fatalf("runner.New() error = %v", err)
+	}
+	message := strings.Repeat("How long should the history window be? ", 10)
+	for _, err := range r.Run(ctx, "user1", "session1", genai.NewContentFromText(message, genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+
+	if len(recorded) != 1 {
+		t.Fatalf("recorded %d calls, want 1", len(recorded))
+	}
+	got := recorded[0]
+	if got.Provider != "claude" || got.Model != "metered-model" {
+		t.Errorf("usage tagged %q/%q, want claude/metered-model", got.Provider, got.Model)
+	}
+	if got.PromptTokens != 400 || got.CompletionTokens != 100 {
+		t.Errorf("tokens = %d prompt, %d completion, want 400 and 100", got.PromptTokens, got.CompletionTokens)
+	}
+	// The message alone is about 100 tokens; the instruction adds a few more
+	if got.EstimatedPromptTokens < len(message)/4 || got.EstimatedPromptTokens > len(message)/2 {
+		t.Errorf("EstimatedPromptTokens = %d, want about %d", got.EstimatedPromptTokens, len(message)/4)
+	}
+	if got.TruncatedMessages != 2 {
+		t.Errorf("TruncatedMessages = %d, want 2", got.TruncatedMessages)
+	}
+	if got.MaxTokensReached {
+		t.Error("MaxTokensReached = true for a reply that finished normally")
+	}
+	if len(sessionIDs) != 1 || sessionIDs[0] != "session1" {
+		t.Errorf("recorded for sessions %v, want the invocation's session", sessionIDs)
+	}
+}
+
+func TestWrapWithUsage_SkipsFailedCalls(t *testing.T) {
+	recorded := 0
+	llm := WrapWithUsage(failingLLM{}, "gemini", func(context.Context, Usage) { recorded++ })
+
+	req := &model.LLMRequest{Contents: []*genai.Content{genai.NewContentFromText("hi", genai.RoleUser)}}
+	for _, err := range llm.GenerateContent(context.Background(), req, true) {
+		if err != nil && !errors.Is(err, errConnectionReset) {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+	}
+	if recorded != 0 {
+		t.Errorf("recorded %d calls, want none for a failed call", recorded)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/lewisedginton/general_purpose_chatbot/internal/buildinfo"
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/health"
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/health/checkers"
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
@@ -40,6 +41,10 @@ type Config struct {
 	DatabaseURL       string               // Optional: Database connection string for health check
 	SlackConnector    ConnectorHealthCheck // Optional: Slack connector for health checks
 	TelegramConnector ConnectorHealthCheck // Optional: Telegram connector for health checks
+	Startup           *StartupBarrier      // Optional: readiness fails until every connector has connected
+	Storage           StorageBackend       // Optional: session storage, probed with a write/read/delete round trip
+	StorageMaxLatency time.Duration        // Slowest healthy storage round trip; DefaultStorageMaxLatency if zero
+	StorageInterval   time.Duration        // How long a storage probe result is reused; DefaultStorageInterval if zero
 	Timeout           time.Duration        // Health check timeout
 	FailureThreshold  int                  // Number of consecutive failures before reporting unhealthy
 }
@@ -100,6 +105,24 @@ func NewHealthMonitor(cfg Config) *HealthMonitor {
 		}))
 	}
 
+	// Session storage check; cached between probes so health requests don't each hit the backend
+	if cfg.Storage != nil {
+		interval := cfg.StorageInterval
+		if interval == 0 {
+			interval = DefaultStorageInterval
+		}
+		checker.AddCachedReadinessCheck(
+			health.NewCheckFunc("session_storage", StorageCheck(cfg.Storage, cfg.StorageMaxLatency)),
+			interval,
+		)
+	}
+
+	// Startup check; not smoothed by the failure threshold, so the service isn't
+	// reported ready before its connectors have connected
+	if cfg.Startup != nil {
+		checker.AddImmediateReadinessCheck(health.NewCheckFunc("startup", cfg.Startup.Check))
+	}
+
 	return &HealthMonitor{
 		checker:   checker,
 		logger:    cfg.Logger,
@@ -171,6 +194,7 @@ func (hm *HealthMonitor) ReadinessHandler() http.HandlerFunc {
 func (hm *HealthMonitor) HealthHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		build := buildinfo.Get()
 
 		livenessStatus, livenessErr := hm.checker.CheckLiveness(ctx)
 		readinessStatus, readinessErr := hm.checker.CheckReadiness(ctx)
@@ -179,7 +203,8 @@ func (hm *HealthMonitor) HealthHandler() http.HandlerFunc {
 			"status":    statusHealthy,
 			"timestamp": time.Now().UTC().Format(time.RFC3339),
 			"uptime":    time.Since(hm.startTime).String(),
-			"version":   getVersion(),
+			"version":   build.Version,
+			"commit":    build.Commit,
 			"liveness": map[string]interface{}{
 				"status": statusHealthy,
 				"checks": livenessStatus.Checks,
@@ -218,11 +243,14 @@ func (hm *HealthMonitor) HealthHandler() http.HandlerFunc {
 	}
 }
 
-// getVersion returns the application version
-// In a real deployment, this would come from build flags or environment variables
-func getVersion() string {
-	// TODO: Implement version tracking via build flags or environment
-	return "dev" // placeholder
+// VersionHandler returns the build information of the running binary
+// GET /version - Returns the version, commit, build date and Go version
+func (hm *HealthMonitor) VersionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(buildinfo.Get())
+	}
 }
 
 // RegisterHandlers registers all health check endpoints on the provided mux
@@ -230,6 +258,7 @@ func (hm *HealthMonitor) RegisterHandlers(mux *http.ServeMux) {
 	mux.HandleFunc("/health", hm.HealthHandler())
 	mux.HandleFunc("/health/live", hm.LivenessHandler())
 	mux.HandleFunc("/health/ready", hm.ReadinessHandler())
+	mux.HandleFunc("/version", hm.VersionHandler())
 }
 
 // ShutdownCheck adds a shutdown check to mark the service as not ready during shutdown
@@ -0,0 +1,84 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// startupPollInterval is how often connectors are polled while waiting for them to connect
+const startupPollInterval = 100 * time.Millisecond
+
+// StartupBarrier tracks whether each connector has connected since the server started.
+// Connectors start in the background, so the server waits on the barrier before reporting
+// itself started, and the readiness probe fails until every connector has connected once.
+type StartupBarrier struct {
+	connectors map[string]ConnectorHealthCheck
+
+	mu        sync.Mutex
+	connected map[string]bool
+}
+
+// NewStartupBarrier creates a barrier for the given connectors, keyed by name.
+func NewStartupBarrier(connectors map[string]ConnectorHealthCheck) *StartupBarrier {
+	return &StartupBarrier{
+		connectors: connectors,
+		connected:  make(map[string]bool, len(connectors)),
+	}
+}
+
+// Pending returns the names of the connectors that haven't been ready yet, sorted.
+// Once a connector has been ready it stays connected as far as the barrier is concerned;
+// later disconnects are reported by the connector's own readiness check.
+func (b *StartupBarrier) Pending() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var pending []string
+	for name, connector := range b.connectors {
+		if b.connected[name] {
+			continue
+		}
+		if connector.Ready() == nil {
+			b.connected[name] = true
+			continue
+		}
+		pending = append(pending, name)
+	}
+	sort.Strings(pending)
+	return pending
+}
+
+// Wait blocks until every connector has been ready, timeout elapses or ctx is done.
+// It returns the connectors that still weren't ready.
+func (b *StartupBarrier) Wait(ctx context.Context, timeout time.Duration) []string {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(startupPollInterval)
+	defer ticker.Stop()
+
+	for {
+		pending := b.Pending()
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return pending
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check is a readiness check that fails until every connector has connected.
+func (b *StartupBarrier) Check(context.Context) error {
+	if pending := b.Pending(); len(pending) > 0 {
+		return fmt.Errorf("waiting for connectors to connect: %s", strings.Join(pending, ", "))
+	}
+	return nil
+}
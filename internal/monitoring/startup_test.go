@@ -0,0 +1,96 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// fakeConnector becomes ready once its delay has passed
+type fakeConnector struct {
+	readyAt time.Time
+}
+
+func newFakeConnector(delay time.Duration) *fakeConnector {
+	return &fakeConnector{readyAt: time.Now().Add(delay)}
+}
+
+func (c *fakeConnector) Ready() error {
+	if time.Now().Before(c.readyAt) {
+		return errors.New("not connected")
+	}
+	return nil
+}
+
+func TestStartupBarrier_WaitForConnectors(t *testing.T) {
+	barrier := NewStartupBarrier(map[string]ConnectorHealthCheck{
+		"slack":    newFakeConnector(200 * time.Millisecond),
+		"telegram": newFakeConnector(0),
+	})
+
+	if got := barrier.Pending(); !reflect.DeepEqual(got, []string{"slack"}) {
+		t.Errorf("Pending() = %v, want [slack]", got)
+	}
+
+	if pending := barrier.Wait(context.Background(), 5*time.Second); len(pending) != 0 {
+		t.Errorf("Wait() pending = %v, want none", pending)
+	}
+	if err := barrier.Check(context.Background()); err != nil {
+		t.Errorf("Check() error = %v, want nil", err)
+	}
+}
+
+func TestStartupBarrier_WaitTimesOut(t *testing.T) {
+	barrier := NewStartupBarrier(map[string]ConnectorHealthCheck{
+		"slack":    newFakeConnector(time.Hour),
+		"telegram": newFakeConnector(0),
+	})
+
+	start := time.Now()
+	pending := barrier.Wait(context.Background(), 150*time.Millisecond)
+	if !reflect.DeepEqual(pending, []string{"slack"}) {
+		t.Errorf("Wait() pending = %v, want [slack]", pending)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Wait() took %v, want it to stop at the timeout", elapsed)
+	}
+
+	err := barrier.Check(context.Background())
+	if err == nil || err.Error() != "waiting for connectors to connect: slack" {
+		t.Errorf("Check() error = %v, want the pending connector named", err)
+	}
+}
+
+func TestReadinessHandler_WaitsForConnectorStartup(t *testing.T) {
+	slack := newFakeConnector(300 * time.Millisecond)
+	monitor := NewHealthMonitor(Config{
+		Logger:         logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard}),
+		SlackConnector: slack,
+		Startup:        NewStartupBarrier(map[string]ConnectorHealthCheck{"slack": slack}),
+	})
+	handler := monitor.ReadinessHandler()
+
+	ready := func() int {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+		return rec.Code
+	}
+
+	// Not ready on the first probe, even though connector checks tolerate a few failures
+	if code := ready(); code != http.StatusServiceUnavailable {
+		t.Errorf("before connecting: status = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+
+	time.Sleep(400 * time.Millisecond)
+
+	if code := ready(); code != http.StatusOK {
+		t.Errorf("after connecting: status = %d, want %d", code, http.StatusOK)
+	}
+}
@@ -0,0 +1,63 @@
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Storage check defaults
+const (
+	DefaultStorageMaxLatency = 2 * time.Second
+	DefaultStorageInterval   = 30 * time.Second
+)
+
+// storageProbePrefix is where probe files are written, away from session data
+const storageProbePrefix = "_health/"
+
+// StorageBackend is the part of a storage provider the storage check exercises;
+// storage_manager.FileProvider satisfies it
+type StorageBackend interface {
+	Read(ctx context.Context, path string) ([]byte, error)
+	Write(ctx context.Context, path string, data []byte) error
+	Delete(ctx context.Context, path string) error
+}
+
+// StorageCheck returns a check that writes, reads back and deletes a small probe file,
+// failing if any step errors or the round trip takes longer than maxLatency.
+func StorageCheck(backend StorageBackend, maxLatency time.Duration) func(context.Context) error {
+	if maxLatency <= 0 {
+		maxLatency = DefaultStorageMaxLatency
+	}
+
+	return func(ctx context.Context) error {
+		id := make([]byte, 8)
+		_, _ = rand.Read(id)
+		path := storageProbePrefix + hex.EncodeToString(id)
+		want := []byte(time.Now().UTC().Format(time.RFC3339Nano))
+
+		start := time.Now()
+		if err := backend.Write(ctx, path, want); err != nil {
+			return fmt.Errorf("storage write failed: %w", err)
+		}
+		got, err := backend.Read(ctx, path)
+		if err != nil {
+			_ = backend.Delete(ctx, path)
+			return fmt.Errorf("storage read failed: %w", err)
+		}
+		if err := backend.Delete(ctx, path); err != nil {
+			return fmt.Errorf("storage delete failed: %w", err)
+		}
+		if !bytes.Equal(got, want) {
+			return fmt.Errorf("storage read back %d bytes that don't match what was written", len(got))
+		}
+
+		if latency := time.Since(start); latency > maxLatency {
+			return fmt.Errorf("storage round trip took %s, over the %s threshold", latency.Round(time.Millisecond), maxLatency)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,127 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// fakeStorage is an in-memory backend whose writes can be slowed down or made to fail
+type fakeStorage struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	delay time.Duration
+	err   error
+}
+
+func newFakeStorage() *fakeStorage {
+	return &fakeStorage{files: make(map[string][]byte)}
+}
+
+func (s *fakeStorage) set(delay time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay, s.err = delay, err
+}
+
+func (s *fakeStorage) Write(_ context.Context, path string, data []byte) error {
+	s.mu.Lock()
+	delay, err := s.delay, s.err
+	s.mu.Unlock()
+
+	time.Sleep(delay)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[path] = data
+	return nil
+}
+
+func (s *fakeStorage) Read(_ context.Context, path string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.files[path]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+func (s *fakeStorage) Delete(_ context.Context, path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, path)
+	return nil
+}
+
+func TestStorageCheck(t *testing.T) {
+	storage := newFakeStorage()
+	check := StorageCheck(storage, 20*time.Millisecond)
+
+	if err := check(context.Background()); err != nil {
+		t.Fatalf("check() error = %v, want healthy", err)
+	}
+	if len(storage.files) != 0 {
+		t.Errorf("probe left %d files behind", len(storage.files))
+	}
+
+	storage.set(30*time.Millisecond, nil)
+	if err := check(context.Background()); err == nil || !strings.Contains(err.Error(), "threshold") {
+		t.Errorf("check() error = %v, want a latency error", err)
+	}
+
+	storage.set(0, errors.New("bucket unavailable"))
+	if err := check(context.Background()); err == nil || !strings.Contains(err.Error(), "bucket unavailable") {
+		t.Errorf("check() error = %v, want the write error", err)
+	}
+}
+
+func TestHealthMonitor_StorageDegradesAndRecovers(t *testing.T) {
+	storage := newFakeStorage()
+	monitor := NewHealthMonitor(Config{
+		Logger:            logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard}),
+		Storage:           storage,
+		StorageMaxLatency: 20 * time.Millisecond,
+		StorageInterval:   time.Nanosecond,
+		FailureThreshold:  2,
+	})
+
+	ready := func() int {
+		rec := httptest.NewRecorder()
+		monitor.ReadinessHandler()(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+		return rec.Code
+	}
+
+	if code := ready(); code != http.StatusOK {
+		t.Fatalf("readiness = %d with healthy storage, want 200", code)
+	}
+
+	// Slow storage is reported once the failure threshold is reached
+	storage.set(30*time.Millisecond, nil)
+	if code := ready(); code != http.StatusOK {
+		t.Errorf("readiness = %d after one slow probe, want 200 below the threshold", code)
+	}
+	if code := ready(); code != http.StatusServiceUnavailable {
+		t.Errorf("readiness = %d after two slow probes, want 503", code)
+	}
+
+	storage.set(0, errors.New("bucket unavailable"))
+	if code := ready(); code != http.StatusServiceUnavailable {
+		t.Errorf("readiness = %d with failing storage, want 503", code)
+	}
+
+	storage.set(0, nil)
+	if code := ready(); code != http.StatusOK {
+		t.Errorf("readiness = %d after storage recovered, want 200", code)
+	}
+}
@@ -0,0 +1,156 @@
+package prompt_manager //nolint:revive // var-naming: using underscores for domain clarity
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+)
+
+const (
+	// libraryPrefix is the directory holding the prompt library, one markdown file per prompt
+	libraryPrefix = "library"
+	promptExt     = ".md"
+
+	// DefaultLibraryCacheTTL is how long the prompt library is cached before it's re-read,
+	// so prompts edited by operators are picked up without a restart
+	DefaultLibraryCacheTTL = 5 * time.Minute
+)
+
+// Prompt is a reusable prompt snippet from the prompt library.
+// Prompts are stored as library/<name>.md, optionally starting with front matter
+// holding a description:
+//
+//	---
+//	description: Review a pull request for security issues
+//	---
+//	You are reviewing...
+type Prompt struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Text        string `json:"text"`
+}
+
+// Option configures a PromptManager.
+type Option func(*PromptManager)
+
+// WithLibraryCacheTTL sets how long the prompt library is cached. A TTL of 0 or less
+// caches it until the process restarts.
+func WithLibraryCacheTTL(ttl time.Duration) Option {
+	return func(m *PromptManager) {
+		m.libraryTTL = ttl
+	}
+}
+
+// SearchPrompts returns the library prompts whose name or description contains query
+// (case-insensitive), sorted by name. Use "*" to return all prompts.
+func (m *PromptManager) SearchPrompts(ctx context.Context, query string) ([]Prompt, error) {
+	prompts, err := m.loadLibrary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if query == "*" {
+		return slices.Clone(prompts), nil
+	}
+
+	queryLower := strings.ToLower(query)
+	var results []Prompt
+	for _, p := range prompts {
+		if strings.Contains(strings.ToLower(p.Name), queryLower) ||
+			strings.Contains(strings.ToLower(p.Description), queryLower) {
+			results = append(results, p)
+		}
+	}
+	return results, nil
+}
+
+// GetPrompt retrieves a library prompt by exact name. It returns nil if there's no such prompt.
+func (m *PromptManager) GetPrompt(ctx context.Context, name string) (*Prompt, error) {
+	prompts, err := m.loadLibrary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range prompts {
+		if p.Name == name {
+			return &p, nil
+		}
+	}
+	return nil, nil
+}
+
+// loadLibrary returns the cached prompt library, reading it from the provider when the cache expires
+func (m *PromptManager) loadLibrary(ctx context.Context) ([]Prompt, error) {
+	m.libraryMutex.Lock()
+	defer m.libraryMutex.Unlock()
+
+	if m.library != nil && (m.libraryTTL <= 0 || m.now().Sub(m.libraryLoadedAt) < m.libraryTTL) {
+		return m.library, nil
+	}
+
+	prompts, err := readLibrary(ctx, m.provider)
+	if err != nil {
+		return nil, err
+	}
+
+	m.library = prompts
+	m.libraryLoadedAt = m.now()
+	return prompts, nil
+}
+
+// readLibrary reads every prompt in the library directory, sorted by name
+func readLibrary(ctx context.Context, provider storage_manager.FileProvider) ([]Prompt, error) {
+	files, err := provider.List(ctx, libraryPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt library: %w", err)
+	}
+
+	prompts := make([]Prompt, 0, len(files))
+	for _, file := range files {
+		if !strings.HasSuffix(file, promptExt) {
+			continue
+		}
+
+		data, err := provider.Read(ctx, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt %s: %w", file, err)
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(file, libraryPrefix+"/"), promptExt)
+		description, text := parseFrontMatter(string(data))
+		prompts = append(prompts, Prompt{Name: name, Description: description, Text: text})
+	}
+
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+	return prompts, nil
+}
+
+// parseFrontMatter splits a prompt file into the description from its front matter and its body.
+// Files without front matter have no description and are returned whole.
+func parseFrontMatter(content string) (description, body string) {
+	content = strings.TrimPrefix(content, "\ufeff")
+	if !strings.HasPrefix(content, "---\n") {
+		return "", strings.TrimSpace(content)
+	}
+
+	rest := content[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return "", strings.TrimSpace(content)
+	}
+
+	for _, line := range strings.Split(rest[:end], "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok && strings.TrimSpace(key) == "description" {
+			description = strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+
+	body = rest[end+len("\n---"):]
+	return description, strings.TrimSpace(body)
+}
@@ -0,0 +1,143 @@
+package prompt_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func expectLibrary(mockProvider *mocks.FileProvider) {
+	mockProvider.EXPECT().
+		List(mock.Anything, "library").
+		Return([]string{"library/code-review.md", "library/sql/optimise.md", "library/notes.txt"}, nil).
+		Once()
+	mockProvider.EXPECT().
+		Read(mock.Anything, "library/code-review.md").
+		Return([]byte("---\ndescription: Review a pull request for security issues\n---\nYou are reviewing a PR.\n"), nil).
+		Once()
+	mockProvider.EXPECT().
+		Read(mock.Anything, "library/sql/optimise.md").
+		Return([]byte("Suggest indexes for the query."), nil).
+		Once()
+}
+
+func TestPromptManager_SearchPrompts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("lists all prompts sorted by name", func(t *testing.T) {
+		mockProvider := mocks.NewFileProvider(t)
+		expectLibrary(mockProvider)
+
+		prompts, err := New(mockProvider).SearchPrompts(ctx, "*")
+
+		require.NoError(t, err)
+		assert.Equal(t, []Prompt{
+			{Name: "code-review", Description: "Review a pull request for security issues", Text: "You are reviewing a PR."},
+			{Name: "sql/optimise", Text: "Suggest indexes for the query."},
+		}, prompts)
+	})
+
+	t.Run("matches name and description case-insensitively", func(t *testing.T) {
+		mockProvider := mocks.NewFileProvider(t)
+		expectLibrary(mockProvider)
+		manager := New(mockProvider)
+
+		byDescription, err := manager.SearchPrompts(ctx, "SECURITY")
+		require.NoError(t, err)
+		require.Len(t, byDescription, 1)
+		assert.Equal(t, "code-review", byDescription[0].Name)
+
+		byName, err := manager.SearchPrompts(ctx, "sql")
+		require.NoError(t, err)
+		require.Len(t, byName, 1)
+		assert.Equal(t, "sql/optimise", byName[0].Name)
+
+		none, err := manager.SearchPrompts(ctx, "deploy")
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
+	t.Run("returns error when listing fails", func(t *testing.T) {
+		mockProvider := mocks.NewFileProvider(t)
+		mockProvider.EXPECT().
+			List(mock.Anything, "library").
+			Return(nil, errors.New("access denied"))
+
+		_, err := New(mockProvider).SearchPrompts(ctx, "*")
+
+		assert.ErrorContains(t, err, "failed to list prompt library")
+	})
+}
+
+func TestPromptManager_GetPrompt(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retrieves prompt by exact name", func(t *testing.T) {
+		mockProvider := mocks.NewFileProvider(t)
+		expectLibrary(mockProvider)
+
+		prompt, err := New(mockProvider).GetPrompt(ctx, "sql/optimise")
+
+		require.NoError(t, err)
+		require.NotNil(t, prompt)
+		assert.Equal(t, "Suggest indexes for the query.", prompt.Text)
+	})
+
+	t.Run("returns nil for unknown prompt", func(t *testing.T) {
+		mockProvider := mocks.NewFileProvider(t)
+		expectLibrary(mockProvider)
+
+		prompt, err := New(mockProvider).GetPrompt(ctx, "code")
+
+		require.NoError(t, err)
+		assert.Nil(t, prompt)
+	})
+}
+
+func TestPromptManager_LibraryCache(t *testing.T) {
+	ctx := context.Background()
+	mockProvider := mocks.NewFileProvider(t)
+	now := time.Now()
+	manager := New(mockProvider, WithLibraryCacheTTL(time.Minute))
+	manager.now = func() time.Time { return now }
+
+	// The provider is read once while the cache is fresh
+	expectLibrary(mockProvider)
+	_, err := manager.SearchPrompts(ctx, "*")
+	require.NoError(t, err)
+	_, err = manager.GetPrompt(ctx, "code-review")
+	require.NoError(t, err)
+
+	// and again once it expires
+	now = now.Add(2 * time.Minute)
+	expectLibrary(mockProvider)
+	_, err = manager.SearchPrompts(ctx, "*")
+	require.NoError(t, err)
+}
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		name            string
+		content         string
+		wantDescription string
+		wantBody        string
+	}{
+		{"no front matter", "Just a prompt.\n", "", "Just a prompt."},
+		{"quoted description", "---\ndescription: \"Summarise a thread\"\nauthor: ops\n---\n\nBody\n", "Summarise a thread", "Body"},
+		{"unterminated front matter", "---\ndescription: x\nBody", "", "---\ndescription: x\nBody"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			description, body := parseFrontMatter(tt.content)
+			assert.Equal(t, tt.wantDescription, description)
+			assert.Equal(t, tt.wantBody, body)
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"sync"
+	"time"
 
 	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
 )
@@ -15,19 +17,31 @@ const (
 	docsPrefix       = "docs"
 )
 
-// PromptManager provides methods to retrieve system prompts and documents.
+// PromptManager provides methods to retrieve system prompts, documents and library prompts.
 type PromptManager struct {
 	provider storage_manager.FileProvider
+
+	libraryMutex    sync.Mutex
+	library         []Prompt // Cached prompt library, nil until first loaded
+	libraryLoadedAt time.Time
+	libraryTTL      time.Duration
+	now             func() time.Time
 }
 
 // New creates a new PromptManager with the given file provider.
-func New(provider storage_manager.FileProvider) *PromptManager {
+func New(provider storage_manager.FileProvider, opts ...Option) *PromptManager {
 	if provider == nil {
 		panic("file provider cannot be nil")
 	}
-	return &PromptManager{
-		provider: provider,
+	m := &PromptManager{
+		provider:   provider,
+		libraryTTL: DefaultLibraryCacheTTL,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
 }
 
 // GetSystemPrompt retrieves the system prompt from system.md.
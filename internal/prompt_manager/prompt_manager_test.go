@@ -120,14 +120,16 @@ func TestPromptManager_GetDocument(t *testing.T) {
 }
 
 func TestPromptManager_Tools(t *testing.T) {
-	t.Run("returns get_document tool", func(t *testing.T) {
+	t.Run("returns document and prompt library tools", func(t *testing.T) {
 		mockProvider := mocks.NewFileProvider(t)
 		manager := New(mockProvider)
 
 		tools, err := manager.Tools()
 
 		assert.NoError(t, err)
-		assert.Len(t, tools, 1)
+		assert.Len(t, tools, 3)
 		assert.Equal(t, "get_document", tools[0].Name())
+		assert.Equal(t, "search_prompts", tools[1].Name())
+		assert.Equal(t, "get_prompt", tools[2].Name())
 	})
 }
@@ -41,7 +41,7 @@ func (m *PromptManager) createGetDocumentTool() (tool.Tool, error) {
 
 // Tools returns all ADK tools for the prompt manager.
 func (m *PromptManager) Tools() ([]tool.Tool, error) {
-	tools := make([]tool.Tool, 0, 1)
+	tools := make([]tool.Tool, 0, 3)
 
 	getDocTool, err := m.createGetDocumentTool()
 	if err != nil {
@@ -49,5 +49,17 @@ func (m *PromptManager) Tools() ([]tool.Tool, error) {
 	}
 	tools = append(tools, getDocTool)
 
+	searchTool, err := m.createSearchPromptsTool()
+	if err != nil {
+		return nil, err
+	}
+	tools = append(tools, searchTool)
+
+	getPromptTool, err := m.createGetPromptTool()
+	if err != nil {
+		return nil, err
+	}
+	tools = append(tools, getPromptTool)
+
 	return tools, nil
 }
@@ -0,0 +1,44 @@
+package prompt_manager //nolint:revive // var-naming: using underscores for domain clarity
+
+import (
+	"fmt"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetPromptArgs represents the arguments for the get_prompt tool.
+type GetPromptArgs struct {
+	Name string `json:"name" jsonschema:"The exact name of the prompt to retrieve."`
+}
+
+// GetPromptResult represents the result of the get_prompt tool.
+type GetPromptResult struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Text        string `json:"text"`
+	Found       bool   `json:"found"`
+}
+
+func (m *PromptManager) createGetPromptTool() (tool.Tool, error) {
+	return functiontool.New(functiontool.Config{
+		Name:        "get_prompt",
+		Description: "Retrieve a prompt from the prompt library by its exact name to get the full prompt text.",
+	}, func(ctx tool.Context, args GetPromptArgs) (GetPromptResult, error) {
+		prompt, err := m.GetPrompt(ctx, args.Name)
+		if err != nil {
+			return GetPromptResult{}, err
+		}
+
+		if prompt == nil {
+			return GetPromptResult{Found: false}, fmt.Errorf("prompt not found: %s", args.Name)
+		}
+
+		return GetPromptResult{
+			Name:        prompt.Name,
+			Description: prompt.Description,
+			Text:        prompt.Text,
+			Found:       true,
+		}, nil
+	})
+}
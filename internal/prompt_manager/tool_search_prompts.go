@@ -0,0 +1,43 @@
+package prompt_manager //nolint:revive // var-naming: using underscores for domain clarity
+
+import (
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// SearchPromptsArgs represents the arguments for the search_prompts tool.
+type SearchPromptsArgs struct {
+	// Query to match against prompt names and descriptions. Use '*' to return all prompts.
+	Query string `json:"query" jsonschema:"Search query for prompt names and descriptions. Use '*' for all."`
+}
+
+// PromptSummary represents a prompt in search results (without the full text).
+type PromptSummary struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// SearchPromptsResult represents the result of the search_prompts tool.
+type SearchPromptsResult struct {
+	Prompts []PromptSummary `json:"prompts"`
+	Count   int             `json:"count"`
+}
+
+func (m *PromptManager) createSearchPromptsTool() (tool.Tool, error) {
+	return functiontool.New(functiontool.Config{
+		Name:        "search_prompts",
+		Description: "Search the prompt library for reusable prompts by name or description. Use '*' to list all prompts.",
+	}, func(ctx tool.Context, args SearchPromptsArgs) (SearchPromptsResult, error) {
+		prompts, err := m.SearchPrompts(ctx, args.Query)
+		if err != nil {
+			return SearchPromptsResult{}, err
+		}
+
+		summaries := make([]PromptSummary, len(prompts))
+		for i, p := range prompts {
+			summaries[i] = PromptSummary{Name: p.Name, Description: p.Description}
+		}
+
+		return SearchPromptsResult{Prompts: summaries, Count: len(summaries)}, nil
+	})
+}
@@ -0,0 +1,152 @@
+// Package quiethours implements a do-not-disturb window for messages the bot sends on its own
+// initiative, such as broadcasts and reminders, so users aren't pinged overnight. Replies to
+// users are never held back. This is separate from rate limiting: it depends only on the time
+// of day in the configured timezone.
+package quiethours
+
+import (
+	"fmt"
+	"time"
+)
+
+// Policies for messages due during quiet hours
+const (
+	PolicyDefer    = "defer"    // Send once quiet hours end
+	PolicySuppress = "suppress" // Drop the message
+)
+
+// Window is a daily quiet-hours window. It may span midnight (e.g. 22:00 to 07:00).
+// A nil *Window never holds anything back.
+type Window struct {
+	start, end time.Duration // Offsets from local midnight
+	loc        *time.Location
+	policy     string
+	now        func() time.Time
+}
+
+// Option configures optional Window behaviour
+type Option func(*Window)
+
+// WithClock sets the clock Allow uses; time.Now by default
+func WithClock(now func() time.Time) Option {
+	return func(w *Window) {
+		w.now = now
+	}
+}
+
+// New creates a window from start and end clock times ("22:00", "07:00") in timezone (an IANA
+// name such as "Europe/London"; UTC if empty). policy is PolicyDefer or PolicySuppress.
+func New(start, end, timezone, policy string, opts ...Option) (*Window, error) {
+	startOffset, err := ParseClock(start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours start: %w", err)
+	}
+	endOffset, err := ParseClock(end)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours end: %w", err)
+	}
+	if startOffset == endOffset {
+		return nil, fmt.Errorf("quiet hours start and end must differ")
+	}
+
+	loc := time.UTC
+	if timezone != "" {
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("invalid quiet hours timezone: %w", err)
+		}
+	}
+
+	switch policy {
+	case "":
+		policy = PolicyDefer
+	case PolicyDefer, PolicySuppress:
+	default:
+		return nil, fmt.Errorf("quiet hours policy must be %q or %q, got %q", PolicyDefer, PolicySuppress, policy)
+	}
+
+	w := &Window{start: startOffset, end: endOffset, loc: loc, policy: policy, now: time.Now}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w, nil
+}
+
+// ParseClock parses a 24-hour "HH:MM" clock time into its offset from midnight
+func ParseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an HH:MM time", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Active reports whether t falls within quiet hours
+func (w *Window) Active(t time.Time) bool {
+	if w == nil {
+		return false
+	}
+	offset := sinceMidnight(t.In(w.loc))
+	if w.start < w.end {
+		return offset >= w.start && offset < w.end
+	}
+	return offset >= w.start || offset < w.end
+}
+
+// Ends returns when the quiet hours containing t end; t itself if t is outside them
+func (w *Window) Ends(t time.Time) time.Time {
+	if !w.Active(t) {
+		return t
+	}
+	local := t.In(w.loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, w.loc)
+	end := addClock(midnight, w.end)
+	if !end.After(local) {
+		end = addClock(midnight.AddDate(0, 0, 1), w.end)
+	}
+	return end
+}
+
+// Allow reports whether a proactive message can be sent now. When it can't, retryAt is when
+// quiet hours end under the defer policy, or zero when the message should be dropped.
+func (w *Window) Allow() (ok bool, retryAt time.Time) {
+	if w == nil {
+		return true, time.Time{}
+	}
+	return w.AllowAt(w.now())
+}
+
+// AllowAt is Allow for a message sent at now, for callers with their own clock
+func (w *Window) AllowAt(now time.Time) (ok bool, retryAt time.Time) {
+	if !w.Active(now) {
+		return true, time.Time{}
+	}
+	if w.policy == PolicySuppress {
+		return false, time.Time{}
+	}
+	return false, w.Ends(now)
+}
+
+// String describes the window, e.g. "22:00-07:00 Europe/London (defer)"
+func (w *Window) String() string {
+	if w == nil {
+		return "off"
+	}
+	return fmt.Sprintf("%s-%s %s (%s)", formatClock(w.start), formatClock(w.end), w.loc, w.policy)
+}
+
+// sinceMidnight returns how far t is into its day
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+}
+
+// addClock returns the wall-clock time offset into the day starting at midnight, so days
+// with a DST change still end quiet hours at the configured local time
+func addClock(midnight time.Time, offset time.Duration) time.Time {
+	return time.Date(midnight.Year(), midnight.Month(), midnight.Day(),
+		int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, midnight.Location())
+}
+
+func formatClock(offset time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(offset/time.Hour), int(offset%time.Hour/time.Minute))
+}
@@ -0,0 +1,117 @@
+package quiethours
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable clock for tests
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+// broadcast sends text to every recipient that Allow lets through, returning who was sent
+// to and when held-back messages should be retried
+func broadcast(w *Window, recipients []string) (sent []string, retryAt time.Time) {
+	for _, r := range recipients {
+		ok, retry := w.Allow()
+		if !ok {
+			retryAt = retry
+			continue
+		}
+		sent = append(sent, r)
+	}
+	return sent, retryAt
+}
+
+func TestWindow_Active(t *testing.T) {
+	overnight, err := New("22:00", "07:00", "Europe/London", PolicyDefer)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	daytime, err := New("12:00", "13:30", "", PolicyDefer)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	london, _ := time.LoadLocation("Europe/London")
+	tests := []struct {
+		window *Window
+		at     time.Time
+		want   bool
+	}{
+		{overnight, time.Date(2026, 1, 10, 21, 59, 0, 0, london), false},
+		{overnight, time.Date(2026, 1, 10, 22, 0, 0, 0, london), true},
+		{overnight, time.Date(2026, 1, 11, 3, 0, 0, 0, london), true},
+		{overnight, time.Date(2026, 1, 11, 7, 0, 0, 0, london), false},
+		// 05:00 UTC is 06:00 in London during summer time
+		{overnight, time.Date(2026, 7, 1, 5, 0, 0, 0, time.UTC), true},
+		{overnight, time.Date(2026, 7, 1, 6, 30, 0, 0, time.UTC), false},
+		{daytime, time.Date(2026, 1, 10, 12, 45, 0, 0, time.UTC), true},
+		{daytime, time.Date(2026, 1, 10, 13, 30, 0, 0, time.UTC), false},
+		{nil, time.Date(2026, 1, 10, 3, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := tt.window.Active(tt.at); got != tt.want {
+			t.Errorf("%v.Active(%v) = %v, want %v", tt.window, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestWindow_BroadcastDeferred(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 1, 10, 23, 15, 0, 0, time.UTC)}
+	w, err := New("22:00", "07:00", "UTC", PolicyDefer, WithClock(clock.Now))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	sent, retryAt := broadcast(w, []string{"alice", "bob"})
+	if len(sent) != 0 {
+		t.Errorf("sent to %v during quiet hours, want nobody", sent)
+	}
+	if want := time.Date(2026, 1, 11, 7, 0, 0, 0, time.UTC); !retryAt.Equal(want) {
+		t.Errorf("retryAt = %v, want %v", retryAt, want)
+	}
+
+	clock.now = retryAt
+	if sent, _ := broadcast(w, []string{"alice", "bob"}); len(sent) != 2 {
+		t.Errorf("sent to %v after quiet hours, want everyone", sent)
+	}
+}
+
+func TestWindow_BroadcastSuppressed(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 1, 11, 6, 0, 0, 0, time.UTC)}
+	w, err := New("22:00", "07:00", "", PolicySuppress, WithClock(clock.Now))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	sent, retryAt := broadcast(w, []string{"alice"})
+	if len(sent) != 0 || !retryAt.IsZero() {
+		t.Errorf("broadcast = %v, retry at %v; want it dropped", sent, retryAt)
+	}
+
+	clock.now = time.Date(2026, 1, 11, 12, 0, 0, 0, time.UTC)
+	if sent, _ := broadcast(w, []string{"alice"}); len(sent) != 1 {
+		t.Errorf("sent to %v outside quiet hours, want alice", sent)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		start, end, timezone, policy string
+	}{
+		{"25:00", "07:00", "", ""},
+		{"22:00", "7am", "", ""},
+		{"22:00", "22:00", "", ""},
+		{"22:00", "07:00", "Mars/Olympus", ""},
+		{"22:00", "07:00", "", "queue"},
+	}
+	for _, tt := range tests {
+		if _, err := New(tt.start, tt.end, tt.timezone, tt.policy); err == nil {
+			t.Errorf("New(%q, %q, %q, %q) succeeded, want an error", tt.start, tt.end, tt.timezone, tt.policy)
+		}
+	}
+}
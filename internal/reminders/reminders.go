@@ -0,0 +1,303 @@
+// Package reminders schedules messages the bot sends later on a user's behalf ("remind me in
+// an hour"). Reminders are stored through the storage manager, one file each, so pending ones
+// survive restarts, and a background loop posts each through its platform's connector when
+// it's due, holding it back during quiet hours.
+package reminders
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/quiethours"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/prefixed_uuid"
+)
+
+// DefaultInterval is how often the scheduler checks for due reminders by default
+const DefaultInterval = 30 * time.Second
+
+// maxAttempts is how many times a reminder is tried before it's dropped
+const maxAttempts = 3
+
+// Reminder is a message to post to a conversation at a given time
+type Reminder struct {
+	ID        string    `json:"id"`
+	Platform  string    `json:"platform"`   // Connector that posts it: slack or telegram
+	ChannelID string    `json:"channel_id"` // Slack channel or Telegram chat it's posted to
+	UserID    string    `json:"user_id"`    // User who asked for it
+	SessionID string    `json:"session_id"` // Conversation it was set in
+	Message   string    `json:"message"`
+	DueAt     time.Time `json:"due_at"`
+	CreatedAt time.Time `json:"created_at"`
+	Attempts  int       `json:"attempts,omitempty"` // Failed sends so far
+}
+
+// Text is what's posted when the reminder fires
+func (r Reminder) Text() string {
+	return "⏰ Reminder: " + r.Message
+}
+
+// Sender posts reminders for one platform; implemented by the connectors
+type Sender interface {
+	SendReminder(ctx context.Context, channelID, text string) error
+}
+
+// Config holds configuration for the scheduler
+type Config struct {
+	FileProvider storage_manager.FileProvider // Where reminders are stored
+	QuietHours   *quiethours.Window           // Optional: reminders aren't sent during quiet hours
+	Interval     time.Duration                // How often due reminders are checked for; DefaultInterval if zero
+	Logger       logger.Logger
+	Now          func() time.Time // Clock; time.Now if nil
+}
+
+// Scheduler stores reminders and sends them when they're due
+type Scheduler struct {
+	provider storage_manager.FileProvider
+	quiet    *quiethours.Window
+	interval time.Duration
+	log      logger.Logger
+	now      func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]Reminder
+	senders map[string]Sender
+
+	firing sync.Mutex // Held while due reminders are sent, so each is sent once
+}
+
+// New creates a scheduler and loads the pending reminders from storage
+func New(ctx context.Context, cfg Config) (*Scheduler, error) {
+	if cfg.FileProvider == nil {
+		return nil, fmt.Errorf("file provider is required")
+	}
+	if cfg.Logger == nil {
+		return nil, fmt.Errorf("logger is required")
+	}
+
+	s := &Scheduler{
+		provider: cfg.FileProvider,
+		quiet:    cfg.QuietHours,
+		interval: cfg.Interval,
+		log:      cfg.Logger,
+		now:      cfg.Now,
+		pending:  make(map[string]Reminder),
+		senders:  make(map[string]Sender),
+	}
+	if s.interval <= 0 {
+		s.interval = DefaultInterval
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load reads the stored reminders into memory
+func (s *Scheduler) load(ctx context.Context) error {
+	files, err := s.provider.List(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list reminders: %w", err)
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file, ".json") {
+			continue
+		}
+		data, err := s.provider.Read(ctx, file)
+		if err != nil {
+			return fmt.Errorf("failed to read reminder %s: %w", file, err)
+		}
+		var r Reminder
+		if err := json.Unmarshal(data, &r); err != nil {
+			s.log.Warn("Skipping unreadable reminder", logger.StringField("file", file), logger.ErrorField(err))
+			continue
+		}
+		s.pending[r.ID] = r
+	}
+
+	if len(s.pending) > 0 {
+		s.log.Info("Loaded pending reminders", logger.IntField("count", len(s.pending)))
+	}
+	return nil
+}
+
+// RegisterSender sets the sender used for reminders on platform
+func (s *Scheduler) RegisterSender(platform string, sender Sender) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.senders[platform] = sender
+}
+
+// Schedule stores r and returns it with its ID set
+func (s *Scheduler) Schedule(ctx context.Context, r Reminder) (Reminder, error) {
+	if r.Platform == "" || r.ChannelID == "" {
+		return Reminder{}, fmt.Errorf("reminder needs a platform and channel")
+	}
+	if strings.TrimSpace(r.Message) == "" {
+		return Reminder{}, fmt.Errorf("reminder message cannot be empty")
+	}
+
+	r.ID = prefixed_uuid.New("reminder").String()
+	r.CreatedAt = s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.save(ctx, r); err != nil {
+		return Reminder{}, err
+	}
+	s.pending[r.ID] = r
+
+	s.log.Info("Reminder scheduled",
+		logger.StringField("reminder_id", r.ID),
+		logger.StringField("platform", r.Platform),
+		logger.StringField("user_id", r.UserID),
+		logger.StringField("due_at", r.DueAt.Format(time.RFC3339)))
+	return r, nil
+}
+
+// Pending returns the reminders not yet sent, soonest first
+func (s *Scheduler) Pending() []Reminder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := make([]Reminder, 0, len(s.pending))
+	for _, r := range s.pending {
+		pending = append(pending, r)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].DueAt.Before(pending[j].DueAt) })
+	return pending
+}
+
+// Run sends due reminders every interval until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.FireDue(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.FireDue(ctx)
+		}
+	}
+}
+
+// FireDue sends every reminder that's due. During quiet hours reminders are deferred until
+// they end or dropped, according to the quiet hours policy. A failed send is retried on the
+// next check, up to maxAttempts times. Reminders are sent without holding the scheduler's
+// lock, so a slow platform doesn't hold up scheduling.
+func (s *Scheduler) FireDue(ctx context.Context) {
+	s.firing.Lock()
+	defer s.firing.Unlock()
+
+	now := s.now()
+	for _, r := range s.due(now) {
+		log := s.log.WithFields(
+			logger.StringField("reminder_id", r.ID),
+			logger.StringField("platform", r.Platform),
+			logger.StringField("channel_id", r.ChannelID))
+
+		if s.quiet != nil {
+			if ok, retryAt := s.quiet.AllowAt(now); !ok {
+				if retryAt.IsZero() {
+					log.Info("Dropping reminder due during quiet hours")
+					s.remove(ctx, r)
+					continue
+				}
+				r.DueAt = retryAt
+				s.update(ctx, r)
+				log.Info("Deferring reminder until quiet hours end", logger.StringField("due_at", retryAt.Format(time.RFC3339)))
+				continue
+			}
+		}
+
+		s.mu.Lock()
+		sender, ok := s.senders[r.Platform]
+		s.mu.Unlock()
+		if !ok {
+			log.Warn("No connector to send reminder; dropping it")
+			s.remove(ctx, r)
+			continue
+		}
+
+		if err := sender.SendReminder(ctx, r.ChannelID, r.Text()); err != nil {
+			r.Attempts++
+			if r.Attempts >= maxAttempts {
+				log.Error("Failed to send reminder; giving up", logger.IntField("attempts", r.Attempts), logger.ErrorField(err))
+				s.remove(ctx, r)
+				continue
+			}
+			log.Warn("Failed to send reminder; will retry", logger.IntField("attempts", r.Attempts), logger.ErrorField(err))
+			s.update(ctx, r)
+			continue
+		}
+
+		log.Info("Reminder sent")
+		s.remove(ctx, r)
+	}
+}
+
+// due returns the pending reminders due at now
+func (s *Scheduler) due(now time.Time) []Reminder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []Reminder
+	for _, r := range s.pending {
+		if !r.DueAt.After(now) {
+			due = append(due, r)
+		}
+	}
+	return due
+}
+
+// update stores a changed reminder
+func (s *Scheduler) update(ctx context.Context, r Reminder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending[r.ID] = r
+	if err := s.save(ctx, r); err != nil {
+		s.log.Error("Failed to update reminder", logger.StringField("reminder_id", r.ID), logger.ErrorField(err))
+	}
+}
+
+// remove deletes a sent or dropped reminder
+func (s *Scheduler) remove(ctx context.Context, r Reminder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.pending, r.ID)
+	if err := s.provider.Delete(ctx, fileName(r.ID)); err != nil {
+		s.log.Error("Failed to delete reminder", logger.StringField("reminder_id", r.ID), logger.ErrorField(err))
+	}
+}
+
+// save writes r to storage
+func (s *Scheduler) save(ctx context.Context, r Reminder) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode reminder: %w", err)
+	}
+	if err := s.provider.Write(ctx, fileName(r.ID), data); err != nil {
+		return fmt.Errorf("failed to store reminder: %w", err)
+	}
+	return nil
+}
+
+func fileName(id string) string {
+	return id + ".json"
+}
@@ -0,0 +1,313 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/quiethours"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// fakeClock is a settable clock for tests
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// sent is a message posted by a recordingSender
+type sent struct {
+	channelID, text string
+}
+
+// recordingSender records the reminders it's asked to send, failing while err is set
+type recordingSender struct {
+	sent []sent
+	err  error
+}
+
+func (s *recordingSender) SendReminder(_ context.Context, channelID, text string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, sent{channelID, text})
+	return nil
+}
+
+// fakeSessions maps session IDs to their conversations
+type fakeSessions map[string]session_manager.SessionInfo
+
+func (f fakeSessions) GetSessionInfo(_ context.Context, sessionID string) (session_manager.SessionInfo, bool) {
+	info, ok := f[sessionID]
+	return info, ok
+}
+
+func testLogger() logger.Logger {
+	return logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+}
+
+func newTestScheduler(t *testing.T, provider storage_manager.FileProvider, clock *fakeClock, quiet *quiethours.Window) *Scheduler {
+	t.Helper()
+	s, err := New(context.Background(), Config{
+		FileProvider: provider,
+		QuietHours:   quiet,
+		Logger:       testLogger(),
+		Now:          clock.Now,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return s
+}
+
+func TestScheduler_FiresAtDueTime(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)}
+	s := newTestScheduler(t, storage_manager.NewLocalFileProvider(t.TempDir()), clock, nil)
+	slack, telegram := &recordingSender{}, &recordingSender{}
+	s.RegisterSender("slack", slack)
+	s.RegisterSender("telegram", telegram)
+
+	sessions := fakeSessions{"session_1": {Connector: "telegram", UserID: "42", ChannelID: "1001"}}
+	result := s.setReminder(ctx, sessions, "session_1", SetReminderArgs{Message: "stretch", In: "1h"})
+	if !result.Success {
+		t.Fatalf("setReminder() = %+v, want success", result)
+	}
+	if result.DueAt != "2026-03-02T11:00:00Z" {
+		t.Errorf("DueAt = %s, want an hour from now", result.DueAt)
+	}
+
+	clock.Advance(59 * time.Minute)
+	s.FireDue(ctx)
+	if len(telegram.sent) != 0 {
+		t.Fatalf("reminder sent %v before it was due", telegram.sent)
+	}
+
+	clock.Advance(time.Minute)
+	s.FireDue(ctx)
+	want := []sent{{"1001", "⏰ Reminder: stretch"}}
+	if len(telegram.sent) != 1 || telegram.sent[0] != want[0] {
+		t.Errorf("telegram sent %v, want %v", telegram.sent, want)
+	}
+	if len(slack.sent) != 0 {
+		t.Errorf("slack sent %v, want nothing", slack.sent)
+	}
+
+	// Each reminder fires once
+	s.FireDue(ctx)
+	if len(telegram.sent) != 1 || len(s.Pending()) != 0 {
+		t.Errorf("reminder sent %d times with %d pending, want once and none pending", len(telegram.sent), len(s.Pending()))
+	}
+}
+
+func TestScheduler_SurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	provider := storage_manager.NewLocalFileProvider(t.TempDir())
+	clock := &fakeClock{now: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)}
+
+	first := newTestScheduler(t, provider, clock, nil)
+	_, err := first.Schedule(ctx, Reminder{Platform: "slack", ChannelID: "C1", Message: "standup", DueAt: clock.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	// A new scheduler picks up the stored reminder and sends it when due
+	clock.Advance(2 * time.Hour)
+	second := newTestScheduler(t, provider, clock, nil)
+	sender := &recordingSender{}
+	second.RegisterSender("slack", sender)
+	if len(second.Pending()) != 1 {
+		t.Fatalf("Pending() = %v after restart, want the stored reminder", second.Pending())
+	}
+	second.FireDue(ctx)
+	if len(sender.sent) != 1 || sender.sent[0].channelID != "C1" {
+		t.Errorf("sent %v, want the reminder in C1", sender.sent)
+	}
+
+	// Sent reminders are removed from storage
+	third := newTestScheduler(t, provider, clock, nil)
+	if len(third.Pending()) != 0 {
+		t.Errorf("Pending() = %v, want none after the reminder was sent", third.Pending())
+	}
+}
+
+func TestScheduler_QuietHours(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 2, 21, 30, 0, 0, time.UTC)
+
+	t.Run("defer", func(t *testing.T) {
+		quiet, err := quiethours.New("22:00", "07:00", "UTC", quiethours.PolicyDefer)
+		if err != nil {
+			t.Fatalf("quiethours.New() error = %v", err)
+		}
+		clock := &fakeClock{now: start}
+		s := newTestScheduler(t, storage_manager.NewLocalFileProvider(t.TempDir()), clock, quiet)
+		sender := &recordingSender{}
+		s.RegisterSender("slack", sender)
+
+		if _, err := s.Schedule(ctx, Reminder{Platform: "slack", ChannelID: "C1", Message: "bins", DueAt: clock.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("Schedule() error = %v", err)
+		}
+		clock.Advance(90 * time.Minute)
+		s.FireDue(ctx)
+		if len(sender.sent) != 0 {
+			t.Fatalf("sent %v during quiet hours", sender.sent)
+		}
+		if due := s.Pending()[0].DueAt; !due.Equal(time.Date(2026, 3, 3, 7, 0, 0, 0, time.UTC)) {
+			t.Errorf("deferred to %v, want the end of quiet hours", due)
+		}
+
+		clock.Advance(8 * time.Hour)
+		s.FireDue(ctx)
+		if len(sender.sent) != 1 {
+			t.Errorf("sent %v after quiet hours, want the reminder", sender.sent)
+		}
+	})
+
+	t.Run("suppress", func(t *testing.T) {
+		quiet, err := quiethours.New("22:00", "07:00", "UTC", quiethours.PolicySuppress)
+		if err != nil {
+			t.Fatalf("quiethours.New() error = %v", err)
+		}
+		clock := &fakeClock{now: start}
+		s := newTestScheduler(t, storage_manager.NewLocalFileProvider(t.TempDir()), clock, quiet)
+		sender := &recordingSender{}
+		s.RegisterSender("slack", sender)
+
+		if _, err := s.Schedule(ctx, Reminder{Platform: "slack", ChannelID: "C1", Message: "bins", DueAt: clock.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("Schedule() error = %v", err)
+		}
+		clock.Advance(90 * time.Minute)
+		s.FireDue(ctx)
+		if len(sender.sent) != 0 || len(s.Pending()) != 0 {
+			t.Errorf("sent %v with %d pending, want the reminder dropped", sender.sent, len(s.Pending()))
+		}
+	})
+}
+
+func TestScheduler_RetriesFailedSends(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)}
+	s := newTestScheduler(t, storage_manager.NewLocalFileProvider(t.TempDir()), clock, nil)
+	sender := &recordingSender{err: errors.New("rate limited")}
+	s.RegisterSender("slack", sender)
+
+	if _, err := s.Schedule(ctx, Reminder{Platform: "slack", ChannelID: "C1", Message: "ping", DueAt: clock.Now()}); err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	s.FireDue(ctx)
+	if len(s.Pending()) != 1 {
+		t.Fatalf("Pending() = %v after one failure, want the reminder kept for a retry", s.Pending())
+	}
+	sender.err = nil
+	s.FireDue(ctx)
+	if len(sender.sent) != 1 || len(s.Pending()) != 0 {
+		t.Errorf("sent %v with %d pending, want the retry to succeed", sender.sent, len(s.Pending()))
+	}
+
+	// A reminder that keeps failing is eventually dropped
+	sender.err = errors.New("channel archived")
+	if _, err := s.Schedule(ctx, Reminder{Platform: "slack", ChannelID: "C2", Message: "ping", DueAt: clock.Now()}); err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	for i := 0; i < maxAttempts; i++ {
+		s.FireDue(ctx)
+	}
+	if len(s.Pending()) != 0 {
+		t.Errorf("Pending() = %v, want the reminder dropped after %d attempts", s.Pending(), maxAttempts)
+	}
+}
+
+// schedulingSender schedules a follow-up reminder from inside SendReminder, as a connector
+// handling a message while a reminder is being posted might
+type schedulingSender struct {
+	scheduler *Scheduler
+	err       error
+}
+
+func (s *schedulingSender) SendReminder(ctx context.Context, channelID, _ string) error {
+	_, s.err = s.scheduler.Schedule(ctx, Reminder{Platform: "slack", ChannelID: channelID, Message: "follow up", DueAt: time.Now().Add(time.Hour)})
+	return nil
+}
+
+func TestScheduler_SendsWithoutHoldingLock(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)}
+	s := newTestScheduler(t, storage_manager.NewLocalFileProvider(t.TempDir()), clock, nil)
+	sender := &schedulingSender{scheduler: s}
+	s.RegisterSender("slack", sender)
+
+	if _, err := s.Schedule(ctx, Reminder{Platform: "slack", ChannelID: "C1", Message: "ping", DueAt: clock.Now()}); err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.FireDue(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("FireDue() deadlocked scheduling from inside a send")
+	}
+	if sender.err != nil {
+		t.Errorf("Schedule() during a send error = %v", sender.err)
+	}
+	if pending := s.Pending(); len(pending) != 1 || pending[0].Message != "follow up" {
+		t.Errorf("Pending() = %v, want only the follow-up", pending)
+	}
+}
+
+func TestSetReminder_Arguments(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)}
+	s := newTestScheduler(t, storage_manager.NewLocalFileProvider(t.TempDir()), clock, nil)
+	sessions := fakeSessions{"session_1": {Connector: "slack", UserID: "U1", ChannelID: "C1"}}
+
+	tests := []struct {
+		name      string
+		sessionID string
+		args      SetReminderArgs
+		wantDue   string
+	}{
+		{name: "at", sessionID: "session_1", args: SetReminderArgs{Message: "call", At: "2026-03-02T12:30:00+01:00"}, wantDue: "2026-03-02T12:30:00+01:00"},
+		{name: "in and at", sessionID: "session_1", args: SetReminderArgs{Message: "call", In: "1h", At: "2026-03-02T12:30:00Z"}},
+		{name: "neither", sessionID: "session_1", args: SetReminderArgs{Message: "call"}},
+		{name: "bad duration", sessionID: "session_1", args: SetReminderArgs{Message: "call", In: "an hour"}},
+		{name: "in the past", sessionID: "session_1", args: SetReminderArgs{Message: "call", At: "2026-03-01T12:00:00Z"}},
+		{name: "too far ahead", sessionID: "session_1", args: SetReminderArgs{Message: "call", In: "9000h"}},
+		{name: "empty message", sessionID: "session_1", args: SetReminderArgs{In: "1h"}},
+		{name: "unknown session", sessionID: "session_2", args: SetReminderArgs{Message: "call", In: "1h"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := s.setReminder(ctx, sessions, tt.sessionID, tt.args)
+			if result.Success != (tt.wantDue != "") {
+				t.Fatalf("setReminder() = %+v, want success %v", result, tt.wantDue != "")
+			}
+			if tt.wantDue != "" && result.DueAt != tt.wantDue {
+				t.Errorf("DueAt = %s, want %s", result.DueAt, tt.wantDue)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package reminders
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// maxDelay is the furthest ahead a reminder can be set
+const maxDelay = 365 * 24 * time.Hour
+
+// SessionLookup finds the conversation a session belongs to; session_manager.Manager
+// satisfies it
+type SessionLookup interface {
+	GetSessionInfo(ctx context.Context, sessionID string) (session_manager.SessionInfo, bool)
+}
+
+// SetReminderArgs represents the arguments for the set_reminder tool
+type SetReminderArgs struct {
+	Message string `json:"message" jsonschema:"What to remind the user about, written as the reminder they'll receive."`
+	In      string `json:"in,omitempty" jsonschema:"How long from now to send the reminder, as a duration such as 45m, 2h or 1h30m."`
+	At      string `json:"at,omitempty" jsonschema:"When to send the reminder, as an RFC 3339 timestamp such as 2025-06-01T09:00:00+01:00. Use instead of in."`
+}
+
+// SetReminderResult represents the result of the set_reminder tool
+type SetReminderResult struct {
+	Success bool   `json:"success"`
+	ID      string `json:"id,omitempty"`
+	DueAt   string `json:"due_at,omitempty"`
+	Message string `json:"message"`
+}
+
+// Tool returns the set_reminder tool, which schedules a reminder in the conversation it's
+// called from
+func (s *Scheduler) Tool(sessions SessionLookup) (tool.Tool, error) {
+	return functiontool.New(functiontool.Config{
+		Name: "set_reminder",
+		Description: "Schedule a reminder that will be posted to this conversation later, e.g. when the user " +
+			"says \"remind me in an hour to ...\". Give either in (a duration from now) or at (a timestamp).",
+	}, func(ctx tool.Context, args SetReminderArgs) (SetReminderResult, error) {
+		return s.setReminder(ctx, sessions, ctx.SessionID(), args), nil
+	})
+}
+
+// setReminder schedules a reminder for the conversation of sessionID. Problems are reported
+// in the result so the agent can tell the user.
+func (s *Scheduler) setReminder(ctx context.Context, sessions SessionLookup, sessionID string, args SetReminderArgs) SetReminderResult {
+	info, ok := sessions.GetSessionInfo(ctx, sessionID)
+	if !ok {
+		return SetReminderResult{Message: "Reminders can't be set from this conversation."}
+	}
+
+	dueAt, err := s.dueTime(args)
+	if err != nil {
+		return SetReminderResult{Message: err.Error()}
+	}
+
+	r, err := s.Schedule(ctx, Reminder{
+		Platform:  info.Connector,
+		ChannelID: info.ChannelID,
+		UserID:    info.UserID,
+		SessionID: sessionID,
+		Message:   args.Message,
+		DueAt:     dueAt,
+	})
+	if err != nil {
+		return SetReminderResult{Message: fmt.Sprintf("Failed to set the reminder: %v", err)}
+	}
+
+	return SetReminderResult{
+		Success: true,
+		ID:      r.ID,
+		DueAt:   r.DueAt.Format(time.RFC3339),
+		Message: "Reminder set",
+	}
+}
+
+// dueTime works out when a reminder is due from the tool arguments
+func (s *Scheduler) dueTime(args SetReminderArgs) (time.Time, error) {
+	now := s.now()
+
+	var due time.Time
+	switch {
+	case args.In != "" && args.At != "":
+		return time.Time{}, fmt.Errorf("give either in or at, not both")
+	case args.In != "":
+		delay, err := time.ParseDuration(args.In)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("in must be a duration such as 45m or 2h, got %q", args.In)
+		}
+		due = now.Add(delay)
+	case args.At != "":
+		at, err := time.Parse(time.RFC3339, args.At)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("at must be an RFC 3339 timestamp, got %q", args.At)
+		}
+		due = at
+	default:
+		return time.Time{}, fmt.Errorf("give in or at to say when the reminder is due")
+	}
+
+	if !due.After(now) {
+		return time.Time{}, fmt.Errorf("the reminder time must be in the future")
+	}
+	if due.Sub(now) > maxDelay {
+		return time.Time{}, fmt.Errorf("reminders can be set at most a year ahead")
+	}
+	return due, nil
+}
@@ -0,0 +1,337 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/audit"
+	appconfig "github.com/lewisedginton/general_purpose_chatbot/internal/config"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/sessionscope"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// userDataRoots returns the storage prefixes, relative to the storage root, under which an
+// app keeps a directory per scope key
+func userDataRoots(appName string) []string {
+	return []string{
+		path.Join("sessions", appName),
+		path.Join("sessions_archive", appName),
+		path.Join("artifacts", appName),
+		path.Join("memory", "memories", appName),
+		path.Join("memory", "index", appName),
+		path.Join("memory", "documents", appName),
+	}
+}
+
+// UserDataPrefixes returns the storage prefixes, relative to the storage root, that hold an
+// app's data, or only one scope key's when scopeKey is set. ResolveUserScopes finds the scope
+// keys holding a platform user's data.
+func UserDataPrefixes(appName, scopeKey string) []string {
+	roots := userDataRoots(appName)
+	prefixes := make([]string, 0, len(roots))
+	for _, root := range roots {
+		prefixes = append(prefixes, path.Join(root, scopeKey))
+	}
+	return prefixes
+}
+
+// adminStores opens the storage and session index used by the admin commands
+func adminStores(ctx context.Context, cfg *appconfig.AppConfig, log logger.Logger) (*storage_manager.StorageManager, session_manager.Manager, error) {
+	s := &Server{cfg: cfg, log: log}
+	storageManager, err := s.createStorageManager(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create storage manager: %w", err)
+	}
+	s.storageManager = storageManager
+	sessionManager, err := s.createSessionManager()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create session manager: %w", err)
+	}
+	return storageManager, sessionManager, nil
+}
+
+// ResolveUserScopes returns the scope keys an app keeps a platform user's data under. Data
+// is stored by scope key rather than user ID: Slack keys include the workspace, and threads
+// are shared by everyone in them. The keys are the user's own on every connector and
+// workspace, found in the session index and in storage, and the shared sessions the user took
+// part in. The user ID itself is always included; an empty user ID resolves to the whole app.
+func ResolveUserScopes(ctx context.Context, cfg *appconfig.AppConfig, log logger.Logger, appName, userID string) ([]string, error) {
+	if appName == "" {
+		return nil, fmt.Errorf("app name is required")
+	}
+	storageManager, sessionManager, err := adminStores(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+	return userScopes(ctx, storageManager.GetRootProvider(), sessionManager, appName, userID)
+}
+
+// userScopes implements ResolveUserScopes over opened stores
+func userScopes(ctx context.Context, root storage_manager.FileProvider, sessionManager session_manager.Manager, appName, userID string) ([]string, error) {
+	if userID == "" {
+		return []string{""}, nil
+	}
+
+	scopes := []string{userID}
+	add := func(scopeKey string) {
+		if !slices.Contains(scopes, scopeKey) {
+			scopes = append(scopes, scopeKey)
+		}
+	}
+	owns := func(scopeKey string) bool {
+		owner, ok := sessionscope.UserID(scopeKey)
+		return ok && owner == userID
+	}
+
+	for _, info := range sessionManager.ListSessions(ctx) {
+		if owns(info.UserID) || slices.Contains(info.Participants, userID) {
+			add(info.UserID)
+		}
+	}
+
+	// Stored data can outlive the index, e.g. documents ingested before any conversation
+	for _, dataRoot := range userDataRoots(appName) {
+		files, err := storage_manager.ListPrefix(ctx, root, dataRoot)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			scopeKey, _, _ := strings.Cut(strings.TrimPrefix(file, dataRoot+"/"), "/")
+			if owns(scopeKey) {
+				add(scopeKey)
+			}
+		}
+	}
+	return scopes, nil
+}
+
+// DeleteUserData permanently deletes an app's data, or only one platform user's when userID
+// is set, across sessions, archived session events, artifacts, memory and documents. A user's
+// data is found with ResolveUserScopes, and their scope keys are also removed from the
+// session index. Each scope's deletion is audited as done by actor. It returns how many files
+// and index entries were deleted.
+func DeleteUserData(ctx context.Context, cfg *appconfig.AppConfig, log logger.Logger, auditLog *audit.Log, actor, appName, userID string) (int, error) {
+	if appName == "" {
+		return 0, fmt.Errorf("app name is required")
+	}
+
+	storageManager, sessionManager, err := adminStores(ctx, cfg, log)
+	if err != nil {
+		return 0, err
+	}
+	scopes, err := userScopes(ctx, storageManager.GetRootProvider(), sessionManager, appName, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, scopeKey := range scopes {
+		n, err := deleteScope(ctx, storageManager, sessionManager, log, appName, scopeKey)
+		deleted += n
+
+		record := audit.Record{
+			Platform: audit.PlatformSystem,
+			Actor:    actor,
+			Action:   audit.ActionDataDelete,
+			Target:   path.Join(appName, scopeKey),
+			Result:   audit.ResultSuccess,
+			Detail:   fmt.Sprintf("%d items deleted", n),
+		}
+		if err != nil {
+			record.Result = audit.ResultFailed
+			record.Detail = fmt.Sprintf("%d items deleted before failing: %v", n, err)
+		}
+		if auditErr := auditLog.Record(record); auditErr != nil {
+			log.Error("Failed to record audit entry", logger.ErrorField(auditErr))
+		}
+
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// deleteScope deletes the app's data stored under one scope key, or all of it when the key is
+// empty, and removes the key from the session index. It returns how many files and index
+// entries were deleted.
+func deleteScope(ctx context.Context, storageManager *storage_manager.StorageManager, sessionManager session_manager.Manager, log logger.Logger, appName, scopeKey string) (int, error) {
+	deleted := 0
+	for _, prefix := range UserDataPrefixes(appName, scopeKey) {
+		n, err := storageManager.DeletePrefix(ctx, prefix)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+		if n > 0 {
+			log.Info("Deleted stored data",
+				logger.StringField("prefix", prefix),
+				logger.IntField("files", n))
+		}
+	}
+
+	// The index maps connectors' scope keys to sessions; it isn't scoped by app
+	if scopeKey != "" {
+		n, err := sessionManager.ForgetUser(ctx, scopeKey)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// ExportManifest describes the contents of a data export. It's written to the archive as
+// manifest.json, after the files it lists.
+type ExportManifest struct {
+	AppName    string       `json:"app_name"`
+	UserID     string       `json:"user_id,omitempty"`
+	Scopes     []string     `json:"scopes,omitempty"` // Scope keys the user's data was found under
+	ExportedAt time.Time    `json:"exported_at"`
+	Redacted   bool         `json:"redacted"` // Secrets and personal data in JSON files were masked
+	Files      []ExportFile `json:"files"`
+}
+
+// ExportFile is one file in a data export
+type ExportFile struct {
+	Path   string `json:"path"` // Path in the archive, which is its path in storage
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ExportUserData writes an app's data, or only one platform user's when userID is set, to w
+// as a zip archive of the files under UserDataPrefixes plus a manifest. A user's data is
+// found with ResolveUserScopes, as for DeleteUserData. Files are read and written
+// one at a time rather than gathered in memory. Unless includeSecrets is set, values in JSON
+// files that look like credentials or personal data are masked as they are in logs.
+func ExportUserData(ctx context.Context, cfg *appconfig.AppConfig, log logger.Logger, appName, userID string, w io.Writer, includeSecrets bool) (ExportManifest, error) {
+	manifest := ExportManifest{
+		AppName:    appName,
+		UserID:     userID,
+		ExportedAt: time.Now().UTC(),
+		Redacted:   !includeSecrets,
+		Files:      []ExportFile{},
+	}
+	if appName == "" {
+		return manifest, fmt.Errorf("app name is required")
+	}
+
+	storageManager, sessionManager, err := adminStores(ctx, cfg, log)
+	if err != nil {
+		return manifest, err
+	}
+	root := storageManager.GetRootProvider()
+	scopes, err := userScopes(ctx, root, sessionManager, appName, userID)
+	if err != nil {
+		return manifest, err
+	}
+	if userID != "" {
+		manifest.Scopes = scopes
+	}
+
+	var prefixes []string
+	for _, scopeKey := range scopes {
+		prefixes = append(prefixes, UserDataPrefixes(appName, scopeKey)...)
+	}
+
+	archive := zip.NewWriter(w)
+	for _, prefix := range prefixes {
+		files, err := storage_manager.ListPrefix(ctx, root, prefix)
+		if err != nil {
+			return manifest, err
+		}
+		for _, file := range files {
+			data, err := root.Read(ctx, file)
+			if err != nil {
+				return manifest, fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			if !includeSecrets {
+				data = redactJSON(data)
+			}
+			if err := writeArchiveFile(archive, file, data); err != nil {
+				return manifest, err
+			}
+			sum := sha256.Sum256(data)
+			manifest.Files = append(manifest.Files, ExportFile{Path: file, Size: len(data), SHA256: hex.EncodeToString(sum[:])})
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return manifest, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := writeArchiveFile(archive, "manifest.json", data); err != nil {
+		return manifest, err
+	}
+	if err := archive.Close(); err != nil {
+		return manifest, fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	log.Info("Exported stored data",
+		logger.StringField("app", appName),
+		logger.StringField("user_id", userID),
+		logger.IntField("files", len(manifest.Files)),
+		logger.BoolField("redacted", manifest.Redacted))
+	return manifest, nil
+}
+
+// writeArchiveFile adds a file to a zip archive
+func writeArchiveFile(archive *zip.Writer, name string, data []byte) error {
+	f, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	return nil
+}
+
+// redactJSON masks values in a JSON document the way log fields are masked, scanning every
+// string for secrets. Data that isn't JSON is returned unchanged.
+func redactJSON(data []byte) []byte {
+	// Keep numbers as written so large integers survive the round trip
+	var doc any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return data
+	}
+	redaction := logger.RedactionConfig{ScanValues: true}
+
+	var walk func(key string, value any) any
+	walk = func(key string, value any) any {
+		switch v := value.(type) {
+		case map[string]any:
+			for k, child := range v {
+				v[k] = walk(k, child)
+			}
+			return v
+		case []any:
+			for i, child := range v {
+				v[i] = walk(key, child)
+			}
+			return v
+		default:
+			return redaction.Redact(key, v)
+		}
+	}
+
+	redacted, err := json.MarshalIndent(walk("", doc), "", "  ")
+	if err != nil {
+		return data
+	}
+	return redacted
+}
@@ -0,0 +1,336 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/audit"
+	appconfig "github.com/lewisedginton/general_purpose_chatbot/internal/config"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/sessionscope"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+func TestDeleteUserData(t *testing.T) {
+	ctx := context.Background()
+	cfg := &appconfig.AppConfig{Storage: appconfig.StorageConfig{Backend: "local", LocalDir: t.TempDir()}}
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+	root := storage_manager.NewLocalFileProvider(cfg.Storage.LocalDir)
+
+	files := map[string]bool{ // path -> kept after deleting U1's data
+		"sessions/chatbot/U1/session-1.json":                      false,
+		"sessions_archive/chatbot/U1/session-1/20260101.json":     false,
+		"artifacts/chatbot/U1/session-1/report.txt/metadata.json": false,
+		"memory/memories/chatbot/U1/session-1.json":               false,
+		"memory/index/chatbot/U1/words.json":                      false,
+		"sessions/chatbot/U10/session-2.json":                     true,
+		"sessions/chatbot/U2/session-3.json":                      true,
+		"artifacts/chatbot/U2/session-3/report.txt/metadata.json": true,
+		"memory/index/chatbot/U2/words.json":                      true,
+		"sessions/other_app/U1/session-4.json":                    true,
+	}
+	for file := range files {
+		if err := root.Write(ctx, file, []byte("{}")); err != nil {
+			t.Fatalf("Write(%s) error = %v", file, err)
+		}
+	}
+
+	// Index sessions for both users
+	s := &Server{cfg: cfg, log: log, storageManager: storage_manager.NewWithProvider(root)}
+	sessions, err := s.createSessionManager()
+	if err != nil {
+		t.Fatalf("createSessionManager() error = %v", err)
+	}
+	for _, user := range []string{"U1", "U2"} {
+		if _, err := sessions.CreateNewSession(ctx, "slack", user, "C1"); err != nil {
+			t.Fatalf("CreateNewSession() error = %v", err)
+		}
+	}
+
+	deleted, err := DeleteUserData(ctx, cfg, log, nil, "tester", "chatbot", "U1")
+	if err != nil {
+		t.Fatalf("DeleteUserData() error = %v", err)
+	}
+	if deleted != 6 {
+		t.Errorf("DeleteUserData() = %d, want 5 files and 1 index entry", deleted)
+	}
+
+	for file, kept := range files {
+		exists, err := root.Exists(ctx, file)
+		if err != nil {
+			t.Fatalf("Exists(%s) error = %v", file, err)
+		}
+		if exists != kept {
+			t.Errorf("%s exists = %v, want %v", file, exists, kept)
+		}
+	}
+
+	// Reload the index to see what was saved
+	reloaded, err := session_manager.New(session_manager.Config{
+		MetadataFile: "sessions.json",
+		FileProvider: storage_manager.NewPrefixedFileProvider(root, "sessions"),
+		Logger:       log,
+	})
+	if err != nil {
+		t.Fatalf("session_manager.New() error = %v", err)
+	}
+	if latest, _ := reloaded.GetLatestSession(ctx, "slack", "U1"); latest != "" {
+		t.Errorf("U1 still has indexed session %s", latest)
+	}
+	if latest, _ := reloaded.GetLatestSession(ctx, "slack", "U2"); latest == "" {
+		t.Error("U2's indexed session was removed")
+	}
+}
+
+// seedSlackUser stores data for Slack user U1, who has DMs in workspaces T1 and T2 and took
+// part in one thread, alongside data that isn't theirs. It returns each file and whether it
+// holds U1's data.
+func seedSlackUser(ctx context.Context, t *testing.T, s *Server) map[string]bool {
+	t.Helper()
+	root := s.storageManager.GetRootProvider()
+	sessions, err := s.createSessionManager()
+	if err != nil {
+		t.Fatalf("createSessionManager() error = %v", err)
+	}
+
+	dmT1 := sessionscope.User("T1", "U1")
+	dmT2 := sessionscope.User("T2", "U1")
+	joined := sessionscope.Thread("T1", "C1", "1700000000.000100")
+	other := sessionscope.Thread("T1", "C1", "1700000000.000200")
+	for _, scope := range []struct{ key, participant string }{
+		{dmT1, ""}, {dmT2, ""}, {joined, "U1"}, {other, "U2"}, {sessionscope.User("T1", "U2"), ""},
+	} {
+		sessionID, err := sessions.CreateNewSession(ctx, "slack", scope.key, "C1")
+		if err != nil {
+			t.Fatalf("CreateNewSession() error = %v", err)
+		}
+		if scope.participant != "" {
+			if err := sessions.AddParticipant(ctx, sessionID, scope.participant); err != nil {
+				t.Fatalf("AddParticipant() error = %v", err)
+			}
+		}
+	}
+
+	files := map[string]bool{
+		"sessions/chatbot/" + dmT1 + "/session-1.json":                 true,
+		"sessions/chatbot/" + dmT2 + "/session-2.json":                 true,
+		"sessions/chatbot/" + joined + "/session-3.json":               true,
+		"sessions_archive/chatbot/" + dmT1 + "/session-1/2026.json":    true,
+		"artifacts/chatbot/" + joined + "/session-3/chart.png/v1.json": true,
+		"memory/memories/chatbot/" + dmT1 + "/session-1.json":          true,
+		"memory/index/chatbot/" + dmT2 + "/words.json":                 true,
+		"memory/documents/chatbot/" + dmT1 + "/doc-1.json":             true,
+		// Documents of a workspace the user has no conversation in yet
+		"memory/documents/chatbot/T3:U1/doc-2.json":      true,
+		"sessions/chatbot/" + other + "/session-4.json":  false,
+		"sessions/chatbot/T1:U2/session-5.json":          false,
+		"memory/documents/chatbot/T1:U2/doc-3.json":      false,
+		"memory/documents/chatbot/T1:U10/doc-4.json":     false,
+		"sessions/other_app/" + dmT1 + "/session-6.json": false,
+	}
+	for file := range files {
+		if err := root.Write(ctx, file, []byte("{}")); err != nil {
+			t.Fatalf("Write(%s) error = %v", file, err)
+		}
+	}
+	return files
+}
+
+func TestDeleteUserData_SlackUser(t *testing.T) {
+	ctx := context.Background()
+	cfg := &appconfig.AppConfig{Storage: appconfig.StorageConfig{Backend: "local", LocalDir: t.TempDir()}}
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+	root := storage_manager.NewLocalFileProvider(cfg.Storage.LocalDir)
+	s := &Server{cfg: cfg, log: log, storageManager: storage_manager.NewWithProvider(root)}
+	files := seedSlackUser(ctx, t, s)
+
+	var records bytes.Buffer
+	if _, err := DeleteUserData(ctx, cfg, log, audit.New(&records), "tester", "chatbot", "U1"); err != nil {
+		t.Fatalf("DeleteUserData() error = %v", err)
+	}
+
+	for file, owned := range files {
+		exists, err := root.Exists(ctx, file)
+		if err != nil {
+			t.Fatalf("Exists(%s) error = %v", file, err)
+		}
+		if exists == owned {
+			t.Errorf("%s exists = %v, want %v", file, exists, !owned)
+		}
+	}
+
+	sessions, err := s.createSessionManager()
+	if err != nil {
+		t.Fatalf("createSessionManager() error = %v", err)
+	}
+	var indexed []string
+	for _, info := range sessions.ListSessions(ctx) {
+		indexed = append(indexed, info.UserID)
+	}
+	sort.Strings(indexed)
+	if want := []string{"T1:U2", "thread:T1:C1:1700000000.000200"}; strings.Join(indexed, ",") != strings.Join(want, ",") {
+		t.Errorf("indexed scopes after delete = %v, want %v", indexed, want)
+	}
+
+	// One audit record per scope deleted: U1 itself, two DMs, the thread and T3's documents
+	var targets []string
+	for _, line := range strings.Split(strings.TrimSpace(records.String()), "\n") {
+		var record audit.Record
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("audit record %q error = %v", line, err)
+		}
+		if record.Action != audit.ActionDataDelete || record.Actor != "tester" || record.Result != audit.ResultSuccess {
+			t.Errorf("audit record = %+v, want a successful data_delete by tester", record)
+		}
+		targets = append(targets, record.Target)
+	}
+	sort.Strings(targets)
+	want := []string{"chatbot/T1:U1", "chatbot/T2:U1", "chatbot/T3:U1", "chatbot/U1", "chatbot/thread:T1:C1:1700000000.000100"}
+	if strings.Join(targets, ",") != strings.Join(want, ",") {
+		t.Errorf("audited targets = %v, want %v", targets, want)
+	}
+}
+
+func TestDeleteUserData_RequiresApp(t *testing.T) {
+	cfg := &appconfig.AppConfig{Storage: appconfig.StorageConfig{Backend: "local", LocalDir: t.TempDir()}}
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+
+	if _, err := DeleteUserData(context.Background(), cfg, log, nil, "tester", "", "U1"); err == nil {
+		t.Error("DeleteUserData() without an app should fail")
+	}
+}
+
+func TestExportUserData(t *testing.T) {
+	ctx := context.Background()
+	cfg := &appconfig.AppConfig{Storage: appconfig.StorageConfig{Backend: "local", LocalDir: t.TempDir()}}
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+	root := storage_manager.NewLocalFileProvider(cfg.Storage.LocalDir)
+
+	session := `{"session_id":"session-1","updated_at_ns":1767225600123456789,"state":{"github_token":"ghp_secret"},"events":[{"text":"mail me at alice@example.com"}]}`
+	for file, data := range map[string]string{
+		"sessions/chatbot/U1/session-1.json":        session,
+		"memory/index/chatbot/U1/words.json":        `{"words":{"deploy":["session-1"]}}`,
+		"artifacts/chatbot/U1/session-1/report.txt": "plain text, not JSON",
+		"sessions/chatbot/U2/session-2.json":        `{"session_id":"session-2"}`,
+	} {
+		if err := root.Write(ctx, file, []byte(data)); err != nil {
+			t.Fatalf("Write(%s) error = %v", file, err)
+		}
+	}
+
+	export := func(includeSecrets bool) map[string]string {
+		t.Helper()
+		var buf bytes.Buffer
+		manifest, err := ExportUserData(ctx, cfg, log, "chatbot", "U1", &buf, includeSecrets)
+		if err != nil {
+			t.Fatalf("ExportUserData() error = %v", err)
+		}
+		if len(manifest.Files) != 3 || manifest.Redacted == includeSecrets {
+			t.Errorf("manifest = %+v, want 3 files and Redacted %v", manifest, !includeSecrets)
+		}
+
+		archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatalf("zip.NewReader() error = %v", err)
+		}
+		contents := make(map[string]string)
+		for _, f := range archive.File {
+			r, err := f.Open()
+			if err != nil {
+				t.Fatalf("Open(%s) error = %v", f.Name, err)
+			}
+			data, _ := io.ReadAll(r)
+			_ = r.Close()
+			contents[f.Name] = string(data)
+		}
+		return contents
+	}
+
+	contents := export(false)
+	var names []string
+	for name := range contents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := []string{
+		"artifacts/chatbot/U1/session-1/report.txt",
+		"manifest.json",
+		"memory/index/chatbot/U1/words.json",
+		"sessions/chatbot/U1/session-1.json",
+	}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("archive files = %v, want %v", names, want)
+	}
+
+	var manifest ExportManifest
+	if err := json.Unmarshal([]byte(contents["manifest.json"]), &manifest); err != nil {
+		t.Fatalf("manifest.json error = %v", err)
+	}
+	if manifest.AppName != "chatbot" || manifest.UserID != "U1" || !manifest.Redacted {
+		t.Errorf("manifest = %+v, want app chatbot, user U1, redacted", manifest)
+	}
+	for _, f := range manifest.Files {
+		if sum := sha256.Sum256([]byte(contents[f.Path])); hex.EncodeToString(sum[:]) != f.SHA256 {
+			t.Errorf("manifest checksum for %s doesn't match the archived file", f.Path)
+		}
+	}
+
+	redacted := contents["sessions/chatbot/U1/session-1.json"]
+	for _, secret := range []string{"ghp_secret", "alice@example.com"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("redacted session contains %q:\n%s", secret, redacted)
+		}
+	}
+	if !strings.Contains(redacted, "1767225600123456789") {
+		t.Errorf("redacted session lost number precision:\n%s", redacted)
+	}
+	if contents["artifacts/chatbot/U1/session-1/report.txt"] != "plain text, not JSON" {
+		t.Error("non-JSON file was changed")
+	}
+
+	if got := export(true)["sessions/chatbot/U1/session-1.json"]; got != session {
+		t.Errorf("session exported with secrets = %s, want it unchanged", got)
+	}
+}
+
+func TestExportUserData_SlackUser(t *testing.T) {
+	ctx := context.Background()
+	cfg := &appconfig.AppConfig{Storage: appconfig.StorageConfig{Backend: "local", LocalDir: t.TempDir()}}
+	log := logger.NewLogger(logger.Config{Level: logger.ErrorLevel, Output: io.Discard})
+	s := &Server{cfg: cfg, log: log, storageManager: storage_manager.NewWithProvider(storage_manager.NewLocalFileProvider(cfg.Storage.LocalDir))}
+	files := seedSlackUser(ctx, t, s)
+
+	var buf bytes.Buffer
+	manifest, err := ExportUserData(ctx, cfg, log, "chatbot", "U1", &buf, false)
+	if err != nil {
+		t.Fatalf("ExportUserData() error = %v", err)
+	}
+
+	var exported []string
+	for _, f := range manifest.Files {
+		exported = append(exported, f.Path)
+	}
+	var want []string
+	for file, owned := range files {
+		if owned {
+			want = append(want, file)
+		}
+	}
+	sort.Strings(exported)
+	sort.Strings(want)
+	if strings.Join(exported, ",") != strings.Join(want, ",") {
+		t.Errorf("exported files = %v, want %v", exported, want)
+	}
+	if len(manifest.Scopes) != 5 {
+		t.Errorf("manifest scopes = %v, want U1, its three workspace keys and the thread", manifest.Scopes)
+	}
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/audit"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+// LogLevelPath is where the log level is read and changed at runtime
+const LogLevelPath = "/admin/loglevel"
+
+// maxLogLevelRequestSize bounds the body of a log level change
+const maxLogLevelRequestSize = 1 << 10
+
+// logLevelResponse is the body returned by the log level endpoint
+type logLevelResponse struct {
+	Level    string `json:"level"`
+	Previous string `json:"previous,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// logLevelHandler serves the log level so it can be changed without a restart, e.g. to turn
+// on debug logging while investigating a production issue. GET returns the level and POST
+// sets it, given as {"level": "debug"} or a level form value. Requests must send token as a
+// bearer token; changes are logged and audited.
+func logLevelHandler(log logger.Logger, token string, auditLog *audit.Log) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r, token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			writeLogLevelResponse(w, http.StatusUnauthorized, logLevelResponse{Error: "unauthorized"})
+			return
+		}
+		controller, ok := log.(logger.LevelController)
+		if !ok {
+			writeLogLevelResponse(w, http.StatusNotImplemented, logLevelResponse{Error: "the logger's level can't be changed"})
+			return
+		}
+
+		current := controller.Level()
+		switch r.Method {
+		case http.MethodGet:
+			writeLogLevelResponse(w, http.StatusOK, logLevelResponse{Level: current.String()})
+			return
+		case http.MethodPost:
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeLogLevelResponse(w, http.StatusMethodNotAllowed, logLevelResponse{Error: "use GET or POST"})
+			return
+		}
+
+		name := requestedLogLevel(w, r)
+		level, ok := logger.LookupLevel(name)
+		if !ok {
+			writeLogLevelResponse(w, http.StatusBadRequest, logLevelResponse{
+				Level: current.String(),
+				Error: "level must be debug, info, warn or error",
+			})
+			return
+		}
+
+		controller.SetLevel(level)
+		actor := clientAddress(r)
+		log.Warn("Log level changed",
+			logger.StringField("from", current.String()),
+			logger.StringField("to", level.String()),
+			logger.StringField("client", actor))
+		if err := auditLog.Record(audit.Record{
+			Platform: audit.PlatformHTTP,
+			Actor:    actor,
+			Action:   audit.ActionLogLevel,
+			Target:   level.String(),
+			Result:   audit.ResultSuccess,
+			Detail:   "was " + current.String(),
+		}); err != nil {
+			log.Error("Failed to write audit record", logger.ErrorField(err))
+		}
+		writeLogLevelResponse(w, http.StatusOK, logLevelResponse{Level: level.String(), Previous: current.String()})
+	}
+}
+
+// authorized reports whether r carries token as a bearer token. An empty token authorizes
+// nothing.
+func authorized(r *http.Request, token string) bool {
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && token != "" && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// requestedLogLevel returns the level a POST asks for, from a JSON body or a form value
+func requestedLogLevel(w http.ResponseWriter, r *http.Request) string {
+	r.Body = http.MaxBytesReader(w, r.Body, maxLogLevelRequestSize)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var body struct {
+			Level string `json:"level"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return ""
+		}
+		return body.Level
+	}
+	return r.FormValue("level")
+}
+
+// clientAddress returns the host a request came from
+func clientAddress(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+func writeLogLevelResponse(w http.ResponseWriter, status int, response logLevelResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
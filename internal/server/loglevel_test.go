@@ -0,0 +1,109 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/audit"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+)
+
+func serveLogLevel(handler http.Handler, method, token, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, LogLevelPath, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLogLevelHandler(t *testing.T) {
+	var logs, audits bytes.Buffer
+	log := logger.NewLogger(logger.Config{Level: logger.InfoLevel, Output: &logs})
+	handler := logLevelHandler(log, "s3cret", audit.New(&audits))
+
+	derived := log.WithFields(logger.StringField("component", "test"))
+	derived.Debug("hidden before")
+	if strings.Contains(logs.String(), "hidden before") {
+		t.Fatalf("debug line logged at info level: %s", logs.String())
+	}
+
+	rec := serveLogLevel(handler, http.MethodPost, "s3cret", "application/json", `{"level":"debug"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if got, want := strings.TrimSpace(rec.Body.String()), `{"level":"debug","previous":"info"}`; got != want {
+		t.Errorf("POST body = %s, want %s", got, want)
+	}
+
+	// Loggers derived before the change follow it too
+	derived.Debug("shown after")
+	if !strings.Contains(logs.String(), "shown after") {
+		t.Errorf("debug line not logged after lowering the level: %s", logs.String())
+	}
+	for _, want := range []string{`"action":"log_level"`, `"target":"debug"`, `"detail":"was info"`} {
+		if !strings.Contains(audits.String(), want) {
+			t.Errorf("audit log = %s, want it to contain %s", audits.String(), want)
+		}
+	}
+
+	rec = serveLogLevel(handler, http.MethodPost, "s3cret", "application/x-www-form-urlencoded", "level=warn")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("form POST status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	rec = serveLogLevel(handler, http.MethodGet, "s3cret", "", "")
+	if got, want := strings.TrimSpace(rec.Body.String()), `{"level":"warn"}`; rec.Code != http.StatusOK || got != want {
+		t.Errorf("GET = %d %s, want 200 %s", rec.Code, got, want)
+	}
+}
+
+func TestLogLevelHandler_Rejects(t *testing.T) {
+	var logs bytes.Buffer
+	log := logger.NewLogger(logger.Config{Level: logger.InfoLevel, Output: &logs})
+	handler := logLevelHandler(log, "s3cret", nil)
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		body   string
+		status int
+	}{
+		{"no token", http.MethodPost, "", `{"level":"debug"}`, http.StatusUnauthorized},
+		{"wrong token", http.MethodPost, "guess", `{"level":"debug"}`, http.StatusUnauthorized},
+		{"unknown level", http.MethodPost, "s3cret", `{"level":"verbose"}`, http.StatusBadRequest},
+		{"malformed body", http.MethodPost, "s3cret", `{"level":`, http.StatusBadRequest},
+		{"wrong method", http.MethodDelete, "s3cret", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveLogLevel(handler, tt.method, tt.token, "application/json", tt.body)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if got := log.(logger.LevelController).Level(); got != logger.InfoLevel {
+				t.Errorf("level = %v, want it left at info", got)
+			}
+		})
+	}
+}
+
+func TestLogLevelHandler_EmptyTokenAuthorizesNothing(t *testing.T) {
+	log := logger.NewLogger(logger.Config{Level: logger.InfoLevel, Output: &bytes.Buffer{}})
+	handler := logLevelHandler(log, "", nil)
+
+	req := httptest.NewRequest(http.MethodPost, LogLevelPath, strings.NewReader(`{"level":"debug"}`))
+	req.Header.Set("Authorization", "Bearer ")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", rec.Code)
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
+)
+
+// Preview assembles the prompt a connector's agent would send to the model for req, without
+// calling the model or changing the conversation. platform is "slack" or "telegram"; an
+// empty platform uses the first enabled connector.
+func (s *Server) Preview(ctx context.Context, platform string, req executor.MessageRequest) (executor.Preview, error) {
+	var guidance agents.PlatformSpecificGuidanceProvider
+	switch platform {
+	case "":
+		if s.slackConnector != nil {
+			platform, guidance = slackPlatform, s.slackConnector
+		} else if s.telegramConnector != nil {
+			platform, guidance = telegramPlatform, s.telegramConnector
+		}
+	case slackPlatform:
+		if s.slackConnector != nil {
+			guidance = s.slackConnector
+		}
+	case telegramPlatform:
+		if s.telegramConnector != nil {
+			guidance = s.telegramConnector
+		}
+	default:
+		return executor.Preview{}, fmt.Errorf("unknown platform %q, want %q or %q", platform, slackPlatform, telegramPlatform)
+	}
+
+	exec, ok := s.executors[platform]
+	if !ok || guidance == nil {
+		if platform == "" {
+			return executor.Preview{}, fmt.Errorf("no connector is enabled")
+		}
+		return executor.Preview{}, fmt.Errorf("the %s connector is not enabled", platform)
+	}
+	return exec.Preview(ctx, req, guidance, nil)
+}
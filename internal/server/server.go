@@ -2,12 +2,16 @@
 package server
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	_ "net/http/pprof" //nolint:gosec // G108: pprof is intentionally enabled for debugging
 	"os"
 	"os/signal"
+	"regexp"
 	"strings"
 	"sync"
 	"syscall"
@@ -16,27 +20,33 @@ import (
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/agents"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/analytics"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/artifact_service"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/audit"
 	appconfig "github.com/lewisedginton/general_purpose_chatbot/internal/config"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/executor"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/slack"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/connectors/telegram"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/memory_service"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/models"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/models/anthropic"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/models/gemini"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/models/openai"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/monitoring"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/prompt_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/quiethours"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/reminders"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/session_manager"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/skills_manager"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/tools/agent_info"
+	"github.com/lewisedginton/general_purpose_chatbot/internal/tools/command"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/tools/http_request"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/tools/web_search"
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
-	"google.golang.org/adk/memory"
 	"google.golang.org/adk/model"
-	"google.golang.org/adk/model/gemini"
 	"google.golang.org/adk/tool"
 	"google.golang.org/genai"
 )
@@ -50,15 +60,26 @@ type Connector interface {
 type Server struct {
 	cfg               *appconfig.AppConfig
 	log               logger.Logger
-	executor          *executor.Executor
 	slackConnector    *slack.Connector
 	telegramConnector *telegram.Connector
 	storageManager    *storage_manager.StorageManager
 	sessionManager    session_manager.Manager
-	memoryService     memory.Service
+	memoryService     *memory_service.Service
 	artifactService   artifact.Service
 	skillsManager     skills_manager.Manager
 	promptManager     *prompt_manager.PromptManager
+	mcpToolsets       []tool.Toolset
+	executors         map[string]*executor.Executor // Each connector's executor, keyed by platform
+	startup           *monitoring.StartupBarrier    // Tracks whether the started connectors have connected
+	maintenance       *executor.Maintenance         // Shared by every executor; toggled by admins or SIGUSR1
+	cannedResponses   []executor.CannedResponse     // Compiled canned response rules
+	agentRoutes       executor.RulesRouter          // Compiled routes to named agents
+	delegation        *agents.Delegation            // Runs tasks handed to named agents; nil when disabled
+	audit             *audit.Log                    // Records admin actions; nil when auditing is disabled
+	analytics         *analytics.Log                // Records feedback on replies; nil when no path is set
+	reminders         *reminders.Scheduler          // Sends reminders set with set_reminder; nil when disabled
+	httpClient        *http.Client                  // Shared client (and connection pool) for outbound requests
+	startTime         time.Time
 	cancel            context.CancelFunc
 }
 
@@ -67,12 +88,69 @@ type Server struct {
 //nolint:revive // cognitive-complexity: Server initialization requires sequential component setup
 func New(ctx context.Context, cfg *appconfig.AppConfig, log logger.Logger) (*Server, error) {
 	s := &Server{
-		cfg: cfg,
-		log: log,
+		cfg:         cfg,
+		log:         log,
+		startTime:   time.Now(),
+		maintenance: executor.NewMaintenance(cfg.Maintenance.Enabled, cfg.Maintenance.Message),
+		executors:   make(map[string]*executor.Executor),
 	}
 
-	// Create storage manager (handles persistence for sessions and metadata)
+	// Open the audit log for admin actions
 	var err error
+	if cfg.Audit.Path != "" {
+		s.audit, err = audit.Open(cfg.Audit.Path)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("Auditing admin actions", logger.StringField("path", cfg.Audit.Path))
+	}
+
+	// Open the analytics log for feedback on replies
+	if cfg.Analytics.Path != "" {
+		s.analytics, err = analytics.Open(cfg.Analytics.Path)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("Recording analytics events", logger.StringField("path", cfg.Analytics.Path))
+	}
+
+	// Create the shared client for outbound HTTP requests
+	s.httpClient, err = cfg.Outbound.NewHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// Compile the routes sending messages to named agents
+	for _, route := range cfg.AgentRoutes {
+		var pattern *regexp.Regexp
+		if route.Pattern != "" {
+			pattern, err = regexp.Compile(route.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid agent route pattern for %q: %w", route.Agent, err)
+			}
+		}
+		s.agentRoutes = append(s.agentRoutes, executor.Route{
+			Agent:    route.Agent,
+			Channels: route.Channels,
+			Commands: route.Commands,
+			Pattern:  pattern,
+		})
+	}
+
+	// Compile the canned response rules
+	for _, rule := range cfg.CannedResponses {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid canned response pattern %q: %w", rule.Name, err)
+		}
+		s.cannedResponses = append(s.cannedResponses, executor.CannedResponse{
+			Name:     rule.Name,
+			Pattern:  pattern,
+			Response: rule.Response,
+		})
+	}
+
+	// Create storage manager (handles persistence for sessions and metadata)
 	s.storageManager, err = s.createStorageManager(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create storage manager: %w", err)
@@ -84,6 +162,14 @@ func New(ctx context.Context, cfg *appconfig.AppConfig, log logger.Logger) (*Ser
 		return nil, fmt.Errorf("failed to create session manager: %w", err)
 	}
 
+	// Create the reminder scheduler, loading pending reminders from storage
+	if cfg.Reminders.Enabled {
+		s.reminders, err = s.createReminderScheduler(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create reminder scheduler: %w", err)
+		}
+	}
+
 	// Create memory service (uses storage manager with "memory" namespace)
 	s.memoryService = s.createMemoryService()
 
@@ -105,6 +191,10 @@ func New(ctx context.Context, cfg *appconfig.AppConfig, log logger.Logger) (*Ser
 	if err != nil {
 		return nil, fmt.Errorf("failed to create LLM model: %w", err)
 	}
+	llmModel = s.wrapLLMModel(llmModel)
+
+	// Create MCP toolsets once; they're shared by every agent and reported by agent_info
+	s.mcpToolsets = agents.NewMCPToolsets(cfg.MCP, s.httpClient, log)
 
 	// Create tools for the agent
 	tools, err := s.createTools(llmModel) //nolint:contextcheck // Tool creation doesn't need request context
@@ -112,55 +202,126 @@ func New(ctx context.Context, cfg *appconfig.AppConfig, log logger.Logger) (*Ser
 		return nil, fmt.Errorf("failed to create tools: %w", err)
 	}
 
-	// Create generic chat agent factory (shared across all platforms)
-	chatAgentFactory, err := agents.NewChatAgent(ctx, llmModel, cfg.MCP, agents.AgentConfig{
-		Name:           "chat_assistant",
-		Platform:       "Multi-Platform",
-		Description:    "AI assistant with MCP capabilities",
-		Logger:         log,
-		PromptProvider: s.promptManager,
-	}, tools)
+	// Create agent factories per enabled connector so each platform gets its own
+	// name, description and persona (MCP toolsets are shared between them)
+	agentFactories, err := s.createAgentFactories(ctx, llmModel, tools)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create chat agent factory: %w", err)
+		return nil, fmt.Errorf("failed to create chat agent factories: %w", err)
 	}
-
-	// Create executor with agent factory (shared across all platforms)
-	s.executor, err = executor.NewExecutorWithConfig(executor.Config{
-		AgentFactory:    chatAgentFactory,
-		AppName:         "chatbot",
-		SessionService:  s.sessionManager.GetADKSessionService(),
-		ArtifactService: s.artifactService,
-		MemoryService:   s.memoryService,
-		Logger:          log,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create executor: %w", err)
+	if len(cfg.Agents) > 0 {
+		log.Info("Created named agents",
+			logger.IntField("agents", len(cfg.Agents)),
+			logger.IntField("routes", len(s.agentRoutes)))
 	}
 
-	// Create connectors (but don't start yet)
+	// Create connectors (but don't start yet), each with its own executor
 	if cfg.Slack.Enabled() {
+		slackExecutor, err := s.createExecutor(agentFactories[slackPlatform])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Slack executor: %w", err)
+		}
+		s.executors[slackPlatform] = slackExecutor
 		s.slackConnector, err = slack.NewConnector(slack.Config{
-			BotToken: cfg.Slack.BotToken,
-			AppToken: cfg.Slack.AppToken,
-			Debug:    cfg.Slack.Debug,
-			Logger:   log,
-		}, s.executor, s.sessionManager)
+			BotToken:     cfg.Slack.BotToken,
+			AppToken:     cfg.Slack.AppToken,
+			Debug:        cfg.Slack.Debug,
+			Logger:       log,
+			SelfPrefixes: cfg.Slack.SelfPrefixes,
+
+			ResponsePrefix:        cfg.Slack.ResponsePrefix,
+			ResponseSuffix:        cfg.Slack.ResponseSuffix,
+			SuffixEveryChunk:      cfg.Slack.ResponseSuffixEveryChunk,
+			BotName:               cfg.Slack.AgentName,
+			DisplayName:           cfg.Slack.DisplayName,
+			Intro:                 cfg.Slack.Intro,
+			ErrorMessage:          cfg.ErrorMessage,
+			Feedback:              cfg.Analytics.Feedback,
+			Analytics:             s.analytics,
+			ToolStatus:            cfg.Tools.Disclosure,
+			AlwaysRespondChannels: cfg.Slack.AlwaysRespondChannels,
+			TriggerPrefixes: executor.TriggerPrefixes{
+				Default:  cfg.Slack.TriggerPrefixes,
+				Channels: cfg.Slack.ChannelTriggerPrefixes,
+			},
+			AdminUsers:        cfg.Slack.AdminUsers,
+			EphemeralCommands: cfg.Slack.EphemeralCommands,
+			EphemeralErrors:   cfg.Slack.EphemeralErrors,
+			Presence:          cfg.Slack.Presence,
+			Throttle:          s.throttleConfig(),
+			Audit:             s.audit,
+			Assistant: slack.AssistantConfig{
+				Enabled: cfg.Slack.Assistant,
+				Welcome: cfg.Slack.AssistantWelcome,
+				Prompts: cfg.Slack.AssistantPrompts,
+				AppHome: cfg.Slack.AppHome,
+			},
+			Reconnect: slack.ReconnectPolicy{
+				InitialBackoff: cfg.Slack.ReconnectInitialBackoff,
+				MaxBackoff:     cfg.Slack.ReconnectMaxBackoff,
+				MaxRetries:     cfg.Slack.ReconnectMaxRetries,
+			},
+			HTTPClient:    s.httpClient,
+			UserCacheSize: cfg.Slack.UserCacheSize,
+			UserCacheTTL:  cfg.Slack.UserCacheTTL,
+			Timezone:      cfg.Display.Location(),
+
+			ChannelContext:    cfg.Slack.ChannelContext,
+			ChannelContextTTL: cfg.Slack.ChannelContextTTL,
+		}, slackExecutor, s.sessionManager)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Slack connector: %w", err)
 		}
 	}
 
 	if cfg.Telegram.Enabled() {
+		telegramExecutor, err := s.createExecutor(agentFactories[telegramPlatform])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Telegram executor: %w", err)
+		}
+		s.executors[telegramPlatform] = telegramExecutor
+		var webhook telegram.WebhookConfig
+		if cfg.Telegram.WebhookEnabled() {
+			webhook = telegram.WebhookConfig{URL: cfg.Telegram.WebhookURL, Secret: cfg.Telegram.WebhookSecret}
+		}
 		s.telegramConnector, err = telegram.NewConnector(telegram.Config{
 			BotToken: cfg.Telegram.BotToken,
 			Debug:    cfg.Telegram.Debug,
 			Logger:   log,
-		}, s.executor, s.sessionManager)
+
+			ResponsePrefix:   cfg.Telegram.ResponsePrefix,
+			ResponseSuffix:   cfg.Telegram.ResponseSuffix,
+			SuffixEveryChunk: cfg.Telegram.ResponseSuffixEveryChunk,
+			BotName:          cfg.Telegram.AgentName,
+			DisplayName:      cfg.Telegram.DisplayName,
+			Intro:            cfg.Telegram.Intro,
+			ErrorMessage:     cfg.ErrorMessage,
+			Feedback:         cfg.Analytics.Feedback,
+			Analytics:        s.analytics,
+			AdminUsers:       cfg.Telegram.AdminUsers,
+			Audit:            s.audit,
+			Webhook:          webhook,
+			TriggerPrefixes: executor.TriggerPrefixes{
+				Default:  cfg.Telegram.TriggerPrefixes,
+				Channels: cfg.Telegram.ChatTriggerPrefixes,
+			},
+			Throttle:   s.throttleConfig(),
+			HTTPClient: s.httpClient,
+		}, telegramExecutor, s.sessionManager)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Telegram connector: %w", err)
 		}
 	}
 
+	// Reminders are posted through the connector of the conversation they were set in
+	if s.reminders != nil {
+		if s.slackConnector != nil {
+			s.reminders.RegisterSender(slackPlatform, s.slackConnector)
+		}
+		if s.telegramConnector != nil {
+			s.reminders.RegisterSender(telegramPlatform, s.telegramConnector)
+		}
+	}
+
 	return s, nil
 }
 
@@ -168,12 +329,18 @@ func New(ctx context.Context, cfg *appconfig.AppConfig, log logger.Logger) (*Ser
 //
 //nolint:revive // cognitive-complexity: Server orchestration requires managing multiple connectors
 func (s *Server) Run() error {
+	// Fail before starting anything if nothing can receive messages
+	if err := s.cfg.CheckConnectors(); err != nil {
+		return err
+	}
+
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	s.cancel = cancel
 	defer cancel()
 
 	s.setupGracefulShutdown()
+	s.setupMaintenanceToggle()
 
 	// Start pprof server for profiling (localhost only for security)
 	go func() {
@@ -192,6 +359,29 @@ func (s *Server) Run() error {
 	var wg sync.WaitGroup
 	enabledCount := 0
 
+	// A connector that fails (e.g. Slack giving up reconnecting) shuts the server down, and
+	// its error is returned so the process exits non-zero and can be restarted
+	var (
+		failedMu sync.Mutex
+		failed   []error
+	)
+	connectorFailed := func(err error) {
+		failedMu.Lock()
+		failed = append(failed, err)
+		failedMu.Unlock()
+		cancel()
+	}
+
+	// Track the connectors being started so readiness waits for them to connect
+	startingConnectors := make(map[string]monitoring.ConnectorHealthCheck)
+	if s.slackConnector != nil {
+		startingConnectors["slack"] = s.slackConnector
+	}
+	if s.telegramConnector != nil {
+		startingConnectors["telegram"] = s.telegramConnector
+	}
+	s.startup = monitoring.NewStartupBarrier(startingConnectors)
+
 	// Start health server
 	if s.cfg.Health.Enabled {
 		wg.Add(1)
@@ -203,6 +393,15 @@ func (s *Server) Run() error {
 		}()
 	}
 
+	// Send reminders as they fall due
+	if s.reminders != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.reminders.Run(ctx)
+		}()
+	}
+
 	// Start Slack connector if configured
 	if s.slackConnector != nil {
 		enabledCount++
@@ -212,7 +411,7 @@ func (s *Server) Run() error {
 			s.log.Info("Starting Slack connector")
 			if err := s.slackConnector.Start(ctx); err != nil {
 				s.log.Error("Slack connector error", logger.ErrorField(err))
-				cancel() // Trigger shutdown on error
+				connectorFailed(fmt.Errorf("slack connector: %w", err))
 			}
 		}()
 	} else {
@@ -225,39 +424,40 @@ func (s *Server) Run() error {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			s.log.Info("Starting Telegram bot polling")
-
-			// Get and log bot info
-			botInfo, err := s.telegramConnector.GetBotInfo(ctx)
-			if err != nil {
-				s.log.Warn("Failed to get Telegram bot info", logger.ErrorField(err))
-			} else {
-				s.log.Info("Telegram bot connected",
-					logger.StringField("bot_username", botInfo.Username),
-					logger.StringField("bot_first_name", botInfo.FirstName))
-			}
-
+			s.log.Info("Starting Telegram connector")
 			if err := s.telegramConnector.Start(ctx); err != nil {
 				s.log.Error("Telegram connector error", logger.ErrorField(err))
-				cancel() // Trigger shutdown on error
+				connectorFailed(fmt.Errorf("telegram connector: %w", err))
 			}
 		}()
 	} else {
 		s.log.Info("Telegram connector disabled (missing TELEGRAM_BOT_TOKEN)")
 	}
 
-	// Verify at least one connector is enabled
-	if enabledCount == 0 {
-		return fmt.Errorf("no connectors configured: please set environment variables for at least one platform (Slack or Telegram)")
+	// Connectors connect in the background; wait for them before reporting the server started
+	switch pending := s.startup.Wait(ctx, s.cfg.ConnectorStartupTimeout); {
+	case s.cfg.ConnectorStartupTimeout == 0:
+		s.log.Info("All enabled connectors starting", logger.IntField("count", enabledCount))
+	case len(pending) > 0:
+		s.log.Warn("Connectors not connected yet, readiness will fail until they connect",
+			logger.StringField("connectors", strings.Join(pending, ", ")),
+			logger.DurationField("waited", s.cfg.ConnectorStartupTimeout))
+	default:
+		s.log.Info("All enabled connectors started", logger.IntField("count", enabledCount))
 	}
 
-	s.log.Info("All enabled connectors started", logger.IntField("count", enabledCount))
-
 	// Wait for all connectors to finish
 	wg.Wait()
 	s.log.Info("All connectors stopped")
 
-	return nil
+	if err := s.audit.Close(); err != nil {
+		s.log.Warn("Failed to close audit log", logger.ErrorField(err))
+	}
+	if err := s.analytics.Close(); err != nil {
+		s.log.Warn("Failed to close analytics log", logger.ErrorField(err))
+	}
+
+	return errors.Join(failed...)
 }
 
 // startHealthServer initializes and starts the health check HTTP server
@@ -272,20 +472,40 @@ func (s *Server) startHealthServer(ctx context.Context) error {
 		logger.StringField("liveness_path", s.cfg.Health.LivenessPath),
 		logger.StringField("readiness_path", s.cfg.Health.ReadinessPath))
 
-	// Create health monitor with connector checks
-	healthMonitor := monitoring.NewHealthMonitor(monitoring.Config{
+	// Create health monitor with connector and storage checks
+	monitorCfg := monitoring.Config{
 		Logger:            s.log,
 		SlackConnector:    s.slackConnector,
 		TelegramConnector: s.telegramConnector,
+		Startup:           s.startup,
 		Timeout:           s.cfg.Health.Timeout,
 		FailureThreshold:  s.cfg.Health.FailureThreshold,
-	})
+		StorageMaxLatency: s.cfg.Health.StorageMaxLatency,
+		StorageInterval:   s.cfg.Health.StorageInterval,
+	}
+	if s.cfg.Health.StorageCheck && s.storageManager != nil {
+		monitorCfg.Storage = s.storageManager.GetProvider("sessions")
+	}
+	healthMonitor := monitoring.NewHealthMonitor(monitorCfg)
 
 	// Create HTTP server
 	mux := http.NewServeMux()
 	mux.HandleFunc(s.cfg.Health.LivenessPath, healthMonitor.LivenessHandler())
 	mux.HandleFunc(s.cfg.Health.ReadinessPath, healthMonitor.ReadinessHandler())
 	mux.HandleFunc(s.cfg.Health.CombinedPath, healthMonitor.HealthHandler())
+	if s.cfg.Health.VersionPath != "" {
+		mux.HandleFunc(s.cfg.Health.VersionPath, healthMonitor.VersionHandler())
+	}
+
+	// Let admins change the log level without a restart
+	if s.cfg.Admin.Token != "" {
+		mux.Handle(LogLevelPath, logLevelHandler(s.log, s.cfg.Admin.Token, s.audit))
+	}
+
+	// Receive Telegram updates on the same server in webhook mode
+	if s.telegramConnector != nil && s.cfg.Telegram.WebhookEnabled() {
+		mux.Handle(s.cfg.Telegram.WebhookPath, s.telegramConnector.WebhookHandler())
+	}
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf(":%d", s.cfg.Health.Port),
@@ -385,9 +605,41 @@ func (s *Server) createSessionManager() (session_manager.Manager, error) {
 	// Use storage manager with "sessions" namespace
 	provider := s.storageManager.GetProvider("sessions")
 
+	retention := session_manager.Retention{
+		MaxEvents: s.cfg.Storage.RetentionEvents,
+		MaxAge:    s.cfg.Storage.RetentionAge,
+	}
+	if s.cfg.Storage.RetentionArchive {
+		retention.Archive = s.storageManager.GetProvider("sessions_archive")
+	}
+
 	return session_manager.New(session_manager.Config{
-		MetadataFile: "sessions.json",
-		FileProvider: provider,
+		MetadataFile:    "sessions.json",
+		FileProvider:    provider,
+		Logger:          s.log,
+		CompactJSON:     s.cfg.Storage.CompactJSON,
+		ListConcurrency: s.cfg.Storage.ListConcurrency,
+		Retention:       retention,
+	})
+}
+
+// createReminderScheduler creates the reminder scheduler using the storage manager, holding
+// reminders back during the configured quiet hours
+func (s *Server) createReminderScheduler(ctx context.Context) (*reminders.Scheduler, error) {
+	var quiet *quiethours.Window
+	if s.cfg.QuietHours.Enabled() {
+		var err error
+		quiet, err = quiethours.New(s.cfg.QuietHours.Start, s.cfg.QuietHours.End, s.cfg.QuietHours.Timezone, s.cfg.QuietHours.Policy)
+		if err != nil {
+			return nil, err
+		}
+		s.log.Info("Quiet hours enabled for reminders", logger.StringField("window", quiet.String()))
+	}
+
+	return reminders.New(ctx, reminders.Config{
+		FileProvider: s.storageManager.GetProvider("reminders"),
+		QuietHours:   quiet,
+		Interval:     s.cfg.Reminders.CheckInterval,
 		Logger:       s.log,
 	})
 }
@@ -411,13 +663,234 @@ func (s *Server) createArtifactService() artifact.Service {
 }
 
 // createMemoryService creates a memory service using the storage manager
-func (s *Server) createMemoryService() memory.Service {
+func (s *Server) createMemoryService() *memory_service.Service {
 	// Use storage manager with "memory" namespace
 	provider := s.storageManager.GetProvider("memory")
 
 	return memory_service.New(memory_service.Config{
 		FileProvider: provider,
 		Logger:       s.log,
+		ChunkSize:    s.cfg.Documents.ChunkSize,
+		ChunkOverlap: &s.cfg.Documents.ChunkOverlap,
+	})
+}
+
+// Platform keys for per-connector agent factories
+const (
+	slackPlatform    = "slack"
+	telegramPlatform = "telegram"
+)
+
+// platformAgents are a connector's agents: its default agent and the named agents that
+// messages can be routed to
+type platformAgents struct {
+	defaultAgent agents.AgentFactory
+	named        map[string]agents.AgentFactory
+}
+
+// createAgentFactories creates the agent factories for each enabled connector, keyed by platform
+func (s *Server) createAgentFactories(ctx context.Context, llmModel model.LLM, tools []tool.Tool) (map[string]platformAgents, error) {
+	var platforms []string
+	var agentConfigs []agents.AgentConfig
+	toolTimeouts := agents.ToolTimeouts{
+		Default:   s.cfg.Tools.Timeout,
+		Overrides: maps.Clone(s.cfg.Tools.TimeoutOverrides),
+	}
+	// Delegation has its own limit rather than the default for a single tool call
+	if _, ok := toolTimeouts.Overrides[agents.DelegateToolName]; !ok {
+		if toolTimeouts.Overrides == nil {
+			toolTimeouts.Overrides = make(map[string]time.Duration)
+		}
+		toolTimeouts.Overrides[agents.DelegateToolName] = s.cfg.Tools.DelegationTimeout
+	}
+
+	if s.cfg.Slack.Enabled() {
+		platforms = append(platforms, slackPlatform)
+		agentConfigs = append(agentConfigs, agents.AgentConfig{
+			Name:            s.cfg.Slack.AgentName,
+			Platform:        "Slack",
+			Description:     s.cfg.Slack.AgentDescription,
+			Persona:         s.cfg.Slack.AgentPersona,
+			DisplayName:     s.cfg.Slack.DisplayName,
+			Intro:           s.cfg.Slack.Intro,
+			Logger:          s.log,
+			PromptProvider:  s.promptManager,
+			ToolTimeouts:    toolTimeouts,
+			FormattingGuide: s.cfg.FormattingGuide,
+		})
+	}
+
+	if s.cfg.Telegram.Enabled() {
+		platforms = append(platforms, telegramPlatform)
+		agentConfigs = append(agentConfigs, agents.AgentConfig{
+			Name:            s.cfg.Telegram.AgentName,
+			Platform:        "Telegram",
+			Description:     s.cfg.Telegram.AgentDescription,
+			Persona:         s.cfg.Telegram.AgentPersona,
+			DisplayName:     s.cfg.Telegram.DisplayName,
+			Intro:           s.cfg.Telegram.Intro,
+			Logger:          s.log,
+			PromptProvider:  s.promptManager,
+			ToolTimeouts:    toolTimeouts,
+			FormattingGuide: s.cfg.FormattingGuide,
+		})
+	}
+
+	factories := make(map[string]platformAgents, len(platforms))
+	if len(agentConfigs) == 0 {
+		return factories, nil
+	}
+
+	created, err := agents.NewChatAgentsWithToolsets(ctx, llmModel, agentConfigs, tools, s.mcpToolsets)
+	if err != nil {
+		return nil, err
+	}
+	namedModels, err := s.createNamedAgentModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, platform := range platforms {
+		named, err := s.createNamedAgentFactories(ctx, llmModel, agentConfigs[i], namedModels, tools)
+		if err != nil {
+			return nil, err
+		}
+		factories[platform] = platformAgents{defaultAgent: created[i], named: named}
+
+		// Delegated tasks run without platform guidance, so any platform's copy will do
+		if s.delegation != nil {
+			for name, factory := range named {
+				s.delegation.Register(name, factory)
+			}
+		}
+	}
+
+	return factories, nil
+}
+
+// createNamedAgentFactories creates a connector's copy of each named agent: its default
+// agent's config with the named agent's name, description, instructions, tools and model
+func (s *Server) createNamedAgentFactories(ctx context.Context, llmModel model.LLM, base agents.AgentConfig, namedModels map[string]model.LLM, tools []tool.Tool) (map[string]agents.AgentFactory, error) {
+	if len(s.cfg.Agents) == 0 {
+		return nil, nil
+	}
+
+	agentConfigs := make([]agents.AgentConfig, 0, len(s.cfg.Agents))
+	for _, definition := range s.cfg.Agents {
+		agentConfig := base
+		agentConfig.Name = definition.Name
+		agentConfig.Description = cmp.Or(definition.Description, base.Description)
+		agentConfig.AllowedTools = definition.Tools
+		agentConfig.Model = namedModels[definition.Model]
+		if definition.Instructions != "" {
+			agentConfig.PromptProvider = agents.StaticPrompt(definition.Instructions)
+		}
+		agentConfigs = append(agentConfigs, agentConfig)
+	}
+
+	created, err := agents.NewChatAgentsWithToolsets(ctx, llmModel, agentConfigs, tools, s.mcpToolsets)
+	if err != nil {
+		return nil, err
+	}
+	named := make(map[string]agents.AgentFactory, len(created))
+	for i, definition := range s.cfg.Agents {
+		named[definition.Name] = created[i]
+	}
+	return named, nil
+}
+
+// createNamedAgentModels creates the models named agents use instead of the default model,
+// keyed by model name; each is created once however many agents use it
+func (s *Server) createNamedAgentModels(ctx context.Context) (map[string]model.LLM, error) {
+	namedModels := make(map[string]model.LLM)
+	for _, definition := range s.cfg.Agents {
+		if definition.Model == "" || namedModels[definition.Model] != nil {
+			continue
+		}
+		cfg := *s.cfg
+		cfg.SetLLMModel(definition.Model)
+		modelServer := &Server{cfg: &cfg, log: s.log, httpClient: s.httpClient, analytics: s.analytics}
+		llm, err := modelServer.createLLMModel(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create model %q for agent %q: %w", definition.Model, definition.Name, err)
+		}
+		namedModels[definition.Model] = modelServer.wrapLLMModel(llm)
+	}
+	return namedModels, nil
+}
+
+// agentProfiles returns how each connector's agent presents itself, for agent_info
+func (s *Server) agentProfiles() map[string]agent_info.Profile {
+	profiles := make(map[string]agent_info.Profile)
+	if s.cfg.Slack.Enabled() {
+		profiles[s.cfg.Slack.AgentName] = agent_info.Profile{
+			DisplayName: s.cfg.Slack.DisplayName,
+			Platform:    "Slack",
+			Description: s.cfg.Slack.AgentDescription,
+		}
+	}
+	if s.cfg.Telegram.Enabled() {
+		profiles[s.cfg.Telegram.AgentName] = agent_info.Profile{
+			DisplayName: s.cfg.Telegram.DisplayName,
+			Platform:    "Telegram",
+			Description: s.cfg.Telegram.AgentDescription,
+		}
+	}
+	return profiles
+}
+
+// createExecutor creates an executor for a connector using its agents
+func (s *Server) createExecutor(platform platformAgents) (*executor.Executor, error) {
+	// Document ingestion from file uploads is opt-in
+	var documentIngester executor.DocumentIngester
+	if s.cfg.Documents.Enabled {
+		documentIngester = s.memoryService
+	}
+
+	// Messages are only routed when there are named agents to route them to
+	var router executor.Router
+	if len(platform.named) > 0 && len(s.agentRoutes) > 0 {
+		router = s.agentRoutes
+	}
+
+	return executor.NewExecutorWithConfig(executor.Config{
+		AgentFactory:     platform.defaultAgent,
+		AppName:          "chatbot",
+		SessionService:   s.sessionManager.GetADKSessionService(),
+		ArtifactService:  s.artifactService,
+		MemoryService:    s.memoryService,
+		DocumentIngester: documentIngester,
+		Citations:        s.cfg.Search.Enabled() && s.cfg.Search.Citations,
+		DetectLanguage:   s.cfg.Language.DetectionEnabled,
+		InboundLimit: executor.InboundLimit{
+			MaxChars:      s.cfg.Inbound.MaxChars,
+			MaxTokens:     s.cfg.Inbound.MaxTokens,
+			Model:         s.cfg.GetLLMModel(),
+			Reject:        strings.EqualFold(s.cfg.Inbound.Action, appconfig.InboundActionReject),
+			RejectMessage: s.cfg.Inbound.RejectMessage,
+		},
+		QuoteTrim: executor.QuoteTrim{
+			Enabled:   s.cfg.Inbound.TrimQuotes,
+			Threshold: s.cfg.Inbound.TrimThreshold,
+			KeepLines: s.cfg.Inbound.TrimKeepLines,
+		},
+		Maintenance:     s.maintenance,
+		CannedResponses: s.cannedResponses,
+		DegradedSessions: executor.DegradedSessions{
+			Enabled:      s.cfg.Storage.DegradedMode,
+			Retries:      s.cfg.Storage.DegradedRetries,
+			RetryBackoff: s.cfg.Storage.DegradedRetryBackoff,
+		},
+		Budget: executor.SessionBudget{
+			MaxTokens:            s.cfg.Budget.MaxTokens,
+			MaxCost:              s.cfg.Budget.MaxCost,
+			InputCostPerMillion:  s.cfg.Budget.InputCostPerMillion,
+			OutputCostPerMillion: s.cfg.Budget.OutputCostPerMillion,
+			Message:              s.cfg.Budget.Message,
+		},
+		DefaultLocale: s.cfg.Display.Locale,
+		Agents:        platform.named,
+		Router:        router,
+		Logger:        s.log,
 	})
 }
 
@@ -425,12 +898,23 @@ func (s *Server) createMemoryService() memory.Service {
 func (s *Server) createTools(llmModel model.LLM) ([]tool.Tool, error) {
 	var tools []tool.Tool
 
-	// Create agent info tool
+	// Create agent info tool; it reports the final tool list at call time
 	agentInfoTool, err := agent_info.New(agent_info.Config{
 		AgentName:   "chat_assistant",
 		Platform:    "Multi-Platform",
 		Description: "AI assistant with MCP capabilities",
 		Model:       llmModel,
+		Profiles:    s.agentProfiles(),
+		ToolNames: func() []string {
+			names := make([]string, 0, len(tools))
+			for _, t := range tools {
+				names = append(names, t.Name())
+			}
+			return names
+		},
+		Toolsets:       s.mcpToolsets,
+		SessionBackend: s.cfg.Storage.Backend,
+		StartTime:      s.startTime,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create agent info tool: %w", err)
@@ -438,7 +922,7 @@ func (s *Server) createTools(llmModel model.LLM) ([]tool.Tool, error) {
 	tools = append(tools, agentInfoTool)
 
 	// Create HTTP request tool
-	httpRequestTool, err := http_request.New()
+	httpRequestTool, err := http_request.NewWithClient(s.httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create http request tool: %w", err)
 	}
@@ -461,9 +945,16 @@ func (s *Server) createTools(llmModel model.LLM) ([]tool.Tool, error) {
 	// Add web search tool if API key is configured
 	if s.cfg.Search.Enabled() {
 		webSearchTool, err := web_search.New(web_search.Config{
-			APIKey:  s.cfg.Search.APIKey,
-			BaseURL: s.cfg.Search.BaseURL,
-			Timeout: s.cfg.Search.Timeout,
+			APIKey:       s.cfg.Search.APIKey,
+			BaseURL:      s.cfg.Search.BaseURL,
+			Timeout:      s.cfg.Search.Timeout,
+			MaxResults:   s.cfg.Search.MaxResults,
+			SnippetChars: s.cfg.Search.SnippetChars,
+			CacheTTL:     s.cfg.Search.CacheTTL,
+			CacheSize:    s.cfg.Search.CacheSize,
+			MaxRetries:   s.cfg.Search.MaxRetries,
+			RetryBackoff: s.cfg.Search.RetryBackoff,
+			HTTPClient:   s.httpClient,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to create web search tool: %w", err)
@@ -472,9 +963,69 @@ func (s *Server) createTools(llmModel model.LLM) ([]tool.Tool, error) {
 		s.log.Info("Web search tool enabled")
 	}
 
+	// Add document search tool if document ingestion is enabled
+	if s.cfg.Documents.Enabled {
+		documentTools, err := s.memoryService.DocumentTools()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create document tools: %w", err)
+		}
+		tools = append(tools, documentTools...)
+		s.log.Info("Document search tool enabled")
+	}
+
+	// Add command tool only when explicitly enabled
+	if s.cfg.Command.Enabled {
+		commandTool, err := command.New(command.Config{
+			Enabled:         s.cfg.Command.Enabled,
+			AllowedCommands: s.cfg.Command.AllowedCommands,
+			Timeout:         s.cfg.Command.Timeout,
+			MaxOutputBytes:  s.cfg.Command.MaxOutputBytes,
+			WorkingDir:      s.cfg.Command.WorkingDir,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create command tool: %w", err)
+		}
+		tools = append(tools, commandTool)
+	}
+
+	// Add the reminder tool when reminders are enabled
+	if s.reminders != nil {
+		reminderTool, err := s.reminders.Tool(s.sessionManager)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create reminder tool: %w", err)
+		}
+		tools = append(tools, reminderTool)
+		s.log.Info("Reminder tool enabled")
+	}
+
+	// Let agents hand tasks to the named agents; they're registered once created
+	if len(s.cfg.Agents) > 0 && s.cfg.Tools.DelegationMaxDepth > 0 {
+		descriptions := make(map[string]string, len(s.cfg.Agents))
+		for _, definition := range s.cfg.Agents {
+			descriptions[definition.Name] = definition.Description
+		}
+		s.delegation = agents.NewDelegation(s.cfg.Tools.DelegationMaxDepth, descriptions, s.log)
+		delegateTool, err := s.delegation.Tool()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create delegation tool: %w", err)
+		}
+		tools = append(tools, delegateTool)
+		s.log.Info("Delegation tool enabled", logger.IntField("max_depth", s.cfg.Tools.DelegationMaxDepth))
+	}
+
 	return tools, nil
 }
 
+// throttleConfig returns the outbound message pacing each connector uses
+func (s *Server) throttleConfig() executor.ThrottleConfig {
+	return executor.ThrottleConfig{
+		GlobalRate:   s.cfg.Throttle.GlobalRate,
+		GlobalBurst:  s.cfg.Throttle.GlobalBurst,
+		ChannelRate:  s.cfg.Throttle.ChannelRate,
+		ChannelBurst: s.cfg.Throttle.ChannelBurst,
+	}
+}
+
 // setupGracefulShutdown sets up signal handling for graceful shutdown
 func (s *Server) setupGracefulShutdown() {
 	sigChan := make(chan os.Signal, 1)
@@ -497,23 +1048,68 @@ func (s *Server) setupGracefulShutdown() {
 	}()
 }
 
+// setupMaintenanceToggle toggles maintenance mode each time the process receives SIGUSR1
+func (s *Server) setupMaintenanceToggle() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGUSR1)
+
+	go func() {
+		for sig := range sigChan {
+			action := audit.ActionMaintenanceOff
+			if s.maintenance.Toggle() {
+				action = audit.ActionMaintenanceOn
+				s.log.Warn("Maintenance mode enabled by signal; replies are paused")
+			} else {
+				s.log.Info("Maintenance mode disabled by signal; replies resumed")
+			}
+
+			err := s.audit.Record(audit.Record{
+				Platform: audit.PlatformSystem,
+				Actor:    sig.String(),
+				Action:   action,
+				Result:   audit.ResultSuccess,
+			})
+			if err != nil {
+				s.log.Error("Failed to write audit record", logger.ErrorField(err))
+			}
+		}
+	}()
+}
+
 // createLLMModel creates an LLM model instance based on the configured provider
 func (s *Server) createLLMModel(ctx context.Context) (model.LLM, error) {
 	provider := strings.ToLower(s.cfg.LLM.Provider)
+	params := s.modelParams()
 
 	switch provider {
 	case "claude":
 		s.log.Info("Initializing Claude model",
-			logger.StringField("model", s.cfg.Anthropic.Model))
-		return anthropic.NewClaudeModel(s.cfg.Anthropic.APIKey, s.cfg.Anthropic.Model)
+			logger.StringField("model", s.cfg.Anthropic.Model),
+			logger.StringField("base_url", s.cfg.Anthropic.APIBaseURL))
+		opts := []anthropic.Option{
+			anthropic.WithParams(params),
+			anthropic.WithMaxInputTokens(s.cfg.Anthropic.MaxInputTokens),
+			anthropic.WithHTTPClient(s.httpClient),
+			anthropic.WithBaseURL(s.cfg.Anthropic.APIBaseURL),
+			anthropic.WithTimeout(s.cfg.Anthropic.Timeout),
+			anthropic.WithRetry(models.RetryConfig(s.cfg.GetAnthropicRetryConfig())),
+			anthropic.WithPromptCaching(s.cfg.Anthropic.PromptCaching),
+		}
+		if s.cfg.Anthropic.ThinkingEnabled {
+			opts = append(opts, anthropic.WithThinking(s.cfg.Anthropic.ThinkingBudget))
+		}
+		return anthropic.NewClaudeModel(s.cfg.Anthropic.APIKey, s.cfg.Anthropic.Model, opts...)
 
 	case "gemini":
 		s.log.Info("Initializing Gemini model",
 			logger.StringField("model", s.cfg.Gemini.Model))
 
-		// Configure the Gemini client
+		// Configure the Gemini client. It gets its own copy of the shared client because
+		// Vertex AI adds its authentication to the client's transport.
+		httpClient := *s.httpClient
 		clientConfig := &genai.ClientConfig{
-			APIKey: s.cfg.Gemini.APIKey,
+			APIKey:     s.cfg.Gemini.APIKey,
+			HTTPClient: &httpClient,
 		}
 
 		// If Vertex AI credentials are provided, use Vertex AI backend
@@ -521,19 +1117,127 @@ func (s *Server) createLLMModel(ctx context.Context) (model.LLM, error) {
 			clientConfig.Backend = genai.BackendVertexAI
 			clientConfig.Project = s.cfg.Gemini.Project
 			clientConfig.Location = s.cfg.Gemini.Region
+			if clientConfig.APIKey == "" {
+				if err := clientConfig.UseDefaultCredentials(); err != nil {
+					return nil, err
+				}
+			}
 			s.log.Info("Using Vertex AI backend",
 				logger.StringField("project", s.cfg.Gemini.Project),
 				logger.StringField("region", s.cfg.Gemini.Region))
 		}
 
-		return gemini.NewModel(ctx, s.cfg.Gemini.Model, clientConfig)
+		llm, err := gemini.NewModel(ctx, s.cfg.Gemini.Model, clientConfig,
+			gemini.WithRetry(models.RetryConfig(s.cfg.GetGeminiRetryConfig())))
+		if err != nil {
+			return nil, err
+		}
+		return models.WrapWithParams(llm, params), nil
 
 	case "openai":
 		s.log.Info("Initializing OpenAI model",
-			logger.StringField("model", s.cfg.OpenAI.Model))
-		return openai.New(s.cfg.OpenAI.APIKey, s.cfg.OpenAI.Model)
+			logger.StringField("model", s.cfg.OpenAI.Model),
+			logger.StringField("base_url", s.cfg.OpenAI.APIBaseURL))
+		return openai.New(s.cfg.OpenAI.APIKey, s.cfg.OpenAI.Model,
+			openai.WithParams(params),
+			openai.WithHTTPClient(s.httpClient),
+			openai.WithBaseURL(s.cfg.OpenAI.APIBaseURL),
+			openai.WithOrganization(s.cfg.OpenAI.Organization, s.cfg.OpenAI.Project),
+			openai.WithTimeout(s.cfg.OpenAI.Timeout),
+			openai.WithMaxRetries(s.cfg.OpenAI.MaxRetries))
+
+	case "azure_openai":
+		azure := s.cfg.AzureOpenAI
+		s.log.Info("Initializing Azure OpenAI model",
+			logger.StringField("deployment", azure.Deployment),
+			logger.StringField("endpoint", azure.Endpoint),
+			logger.StringField("api_version", azure.APIVersion))
+		return openai.New(azure.APIKey, azure.Deployment,
+			openai.WithParams(params),
+			openai.WithHTTPClient(s.httpClient),
+			openai.WithAzure(azure.Endpoint, azure.Deployment, azure.APIVersion),
+			openai.WithTimeout(azure.Timeout),
+			openai.WithMaxRetries(azure.MaxRetries))
 
 	default:
 		return nil, fmt.Errorf("unsupported LLM provider: %s", provider)
 	}
 }
+
+// wrapLLMModel adds the configured call timeout, usage analytics, response cache and tool
+// history policy to a model. Usage is recorded inside the cache, so cached replies aren't.
+// The tool history policy is applied first, so the cache and usage see the history that's sent.
+func (s *Server) wrapLLMModel(llm model.LLM) model.LLM {
+	llm = models.WrapWithTimeout(llm, s.cfg.ModelCallTimeout)
+	if s.analytics != nil {
+		llm = models.WrapWithUsage(llm, strings.ToLower(s.cfg.LLM.Provider), s.recordModelUsage)
+	}
+	if s.cfg.ResponseCache.Enabled {
+		llm = s.withResponseCache(llm)
+	}
+	return models.WrapWithToolHistory(llm, models.ToolHistoryConfig{
+		Policy:    strings.ToLower(s.cfg.Tools.History),
+		KeepTurns: s.cfg.Tools.HistoryTurns,
+	})
+}
+
+// recordModelUsage records the size of a model call as an analytics event, with the user and
+// session the call was made for
+func (s *Server) recordModelUsage(ctx context.Context, usage models.Usage) {
+	event := analytics.Event{
+		Type:                  analytics.EventModelUsage,
+		Provider:              usage.Provider,
+		Model:                 usage.Model,
+		PromptTokens:          usage.PromptTokens,
+		CompletionTokens:      usage.CompletionTokens,
+		EstimatedPromptTokens: usage.EstimatedPromptTokens,
+		TruncatedMessages:     usage.TruncatedMessages,
+		MaxTokensReached:      usage.MaxTokensReached,
+	}
+	// The ADK calls models with the invocation's context
+	if invocation, ok := ctx.(agent.InvocationContext); ok && invocation.Session() != nil {
+		event.UserID = invocation.Session().UserID()
+		event.SessionID = invocation.Session().ID()
+	}
+	if err := s.analytics.Record(event); err != nil {
+		s.log.Warn("Failed to record model usage", logger.ErrorField(err))
+	}
+}
+
+// withResponseCache wraps llm so identical requests are answered from the configured cache
+func (s *Server) withResponseCache(llm model.LLM) model.LLM {
+	cfg := s.cfg.ResponseCache
+
+	var cache models.ResponseCache
+	if strings.EqualFold(cfg.Backend, appconfig.ResponseCacheRedis) {
+		cache = models.NewRedisResponseCache(models.RedisConfig{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		})
+	} else {
+		cache = models.NewMemoryResponseCache(cfg.MaxEntries)
+	}
+
+	s.log.Info("Response cache enabled",
+		logger.StringField("backend", cfg.Backend),
+		logger.DurationField("ttl", cfg.TTL))
+	return models.WrapWithCache(llm, models.CacheConfig{
+		Cache:         cache,
+		TTL:           cfg.TTL,
+		HistoryWindow: cfg.HistoryWindow,
+		Params:        s.modelParams(),
+	})
+}
+
+// modelParams converts the configured default model parameters for the LLM providers
+func (s *Server) modelParams() models.Params {
+	cfg := s.cfg.GetModelParams()
+	return models.Params{
+		Temperature:   cfg.Temperature,
+		MaxTokens:     cfg.MaxTokens,
+		TopP:          cfg.TopP,
+		StopSequences: cfg.StopSequences,
+		Seed:          cfg.Seed,
+	}
+}
@@ -0,0 +1,108 @@
+package session_manager //nolint:revive // var-naming: using underscores for domain clarity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/adk/session"
+)
+
+// EventBatch is a session.Service that defers event persistence. AppendEvent updates the
+// in-memory session immediately, so the runner sees each event as the turn progresses,
+// but events are only written when Flush is called, with one save per session.
+type EventBatch struct {
+	*SessionService
+
+	mutex   sync.Mutex
+	pending []*pendingEvents
+}
+
+// pendingEvents holds the events queued for one session, in order
+type pendingEvents struct {
+	sess   session.Session
+	events []*session.Event
+}
+
+// NewBatch returns an EventBatch that persists events through this service when flushed
+func (s *SessionService) NewBatch() *EventBatch {
+	return &EventBatch{SessionService: s}
+}
+
+// AppendEvent applies an event to the in-memory session and queues it for Flush
+func (b *EventBatch) AppendEvent(_ context.Context, sess session.Session, event *session.Event) error {
+	if sess == nil {
+		return fmt.Errorf("session cannot be nil")
+	}
+	if event == nil {
+		return fmt.Errorf("event cannot be nil")
+	}
+
+	// Skip partial events - they should not be persisted
+	if event.Partial {
+		return nil
+	}
+
+	prepareEvent(event)
+	applyToSession(sess, event)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	sessionKey := b.getSessionKey(sess.AppName(), sess.UserID(), sess.ID())
+	for _, p := range b.pending {
+		if b.getSessionKey(p.sess.AppName(), p.sess.UserID(), p.sess.ID()) == sessionKey {
+			p.events = append(p.events, event)
+			return nil
+		}
+	}
+	b.pending = append(b.pending, &pendingEvents{sess: sess, events: []*session.Event{event}})
+	return nil
+}
+
+// Flush persists the queued events, saving each session once. Events whose session fails to
+// save stay queued, ahead of any appended since, so calling Flush again retries them; the
+// batch is otherwise empty afterwards and can keep being used.
+func (b *EventBatch) Flush(ctx context.Context) error {
+	b.mutex.Lock()
+	pending := b.pending
+	b.pending = nil
+	b.mutex.Unlock()
+
+	var errs []error
+	var failed []*pendingEvents
+	for _, p := range pending {
+		if err := b.persistEvents(ctx, p.sess, p.events); err != nil {
+			errs = append(errs, err)
+			failed = append(failed, p)
+		}
+	}
+
+	if len(failed) > 0 {
+		b.mutex.Lock()
+		b.requeue(failed)
+		b.mutex.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// requeue puts events that failed to save back in front of those queued since. The caller
+// must hold the mutex.
+func (b *EventBatch) requeue(failed []*pendingEvents) {
+	for _, p := range b.pending {
+		sessionKey := b.getSessionKey(p.sess.AppName(), p.sess.UserID(), p.sess.ID())
+		merged := false
+		for _, f := range failed {
+			if b.getSessionKey(f.sess.AppName(), f.sess.UserID(), f.sess.ID()) == sessionKey {
+				f.events = append(f.events, p.events...)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			failed = append(failed, p)
+		}
+	}
+	b.pending = failed
+}
@@ -0,0 +1,168 @@
+package session_manager //nolint:revive // var-naming: using underscores for domain clarity
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/session"
+)
+
+// writeCountingProvider counts the writes made through a file provider
+type writeCountingProvider struct {
+	storage_manager.FileProvider
+	writes int
+}
+
+func (p *writeCountingProvider) Write(ctx context.Context, path string, data []byte) error {
+	p.writes++
+	return p.FileProvider.Write(ctx, path, data)
+}
+
+func newBatchTestService(t *testing.T) (*SessionService, *writeCountingProvider, session.Session) {
+	t.Helper()
+	provider := &writeCountingProvider{FileProvider: storage_manager.NewLocalFileProvider(t.TempDir())}
+	service := NewSessionService(provider, testLogger())
+
+	created, err := service.Create(context.Background(), &session.CreateRequest{
+		AppName:   "test-app",
+		UserID:    "user123",
+		SessionID: "batch-test",
+		State:     map[string]any{"existing": "kept"},
+	})
+	require.NoError(t, err)
+
+	provider.writes = 0
+	return service, provider, created.Session
+}
+
+// turnEvents returns the events of a typical turn: a user message, a tool call and a reply
+func turnEvents() []*session.Event {
+	user := session.NewEvent("inv-1")
+	user.Author = "user"
+	user.Actions.StateDelta = map[string]any{"step": "user", "temp:scratch": "dropped"}
+
+	tool := session.NewEvent("inv-1")
+	tool.Author = "agent"
+	tool.Actions.StateDelta = map[string]any{"step": "tool", "tool_used": "web_search"}
+
+	partial := session.NewEvent("inv-1")
+	partial.Author = "agent"
+	partial.Partial = true
+
+	reply := session.NewEvent("inv-1")
+	reply.Author = "agent"
+	reply.Actions.StateDelta = map[string]any{"step": "reply"}
+
+	return []*session.Event{user, tool, partial, reply}
+}
+
+func assertTurnPersisted(t *testing.T, service *SessionService, want []*session.Event) {
+	t.Helper()
+	resp, err := service.Get(context.Background(), &session.GetRequest{
+		AppName:   "test-app",
+		UserID:    "user123",
+		SessionID: "batch-test",
+	})
+	require.NoError(t, err)
+
+	events := resp.Session.Events()
+	require.Equal(t, 3, events.Len())
+	assert.Equal(t, want[0].ID, events.At(0).ID)
+	assert.Equal(t, want[1].ID, events.At(1).ID)
+	assert.Equal(t, want[3].ID, events.At(2).ID)
+
+	state := resp.Session.State()
+	for key, value := range map[string]any{"existing": "kept", "step": "reply", "tool_used": "web_search"} {
+		got, err := state.Get(key)
+		require.NoError(t, err, key)
+		assert.Equal(t, value, got, key)
+	}
+	_, err = state.Get("temp:scratch")
+	assert.Error(t, err, "temporary keys should not be persisted")
+}
+
+func TestSessionService_AppendEvents(t *testing.T) {
+	service, provider, sess := newBatchTestService(t)
+	events := turnEvents()
+
+	require.NoError(t, service.AppendEvents(context.Background(), sess, events))
+
+	assert.Equal(t, 1, provider.writes, "a batch should be saved once")
+	assertTurnPersisted(t, service, events)
+
+	// The in-memory session is updated too
+	assert.Equal(t, 3, sess.Events().Len())
+	step, err := sess.State().Get("step")
+	require.NoError(t, err)
+	assert.Equal(t, "reply", step)
+}
+
+func TestSessionService_AppendEvents_RejectsNilEvent(t *testing.T) {
+	service, provider, sess := newBatchTestService(t)
+
+	err := service.AppendEvents(context.Background(), sess, []*session.Event{session.NewEvent("inv-1"), nil})
+	require.Error(t, err)
+	assert.Equal(t, 0, provider.writes)
+	assert.Equal(t, 0, sess.Events().Len(), "no event should be applied when the batch is rejected")
+}
+
+func TestEventBatch_Flush(t *testing.T) {
+	service, provider, sess := newBatchTestService(t)
+	batch := service.NewBatch()
+	ctx := context.Background()
+	events := turnEvents()
+
+	for _, event := range events {
+		require.NoError(t, batch.AppendEvent(ctx, sess, event))
+	}
+
+	// Events are visible in memory straight away but not yet written
+	assert.Equal(t, 3, sess.Events().Len())
+	assert.Equal(t, 0, provider.writes)
+
+	require.NoError(t, batch.Flush(ctx))
+	assert.Equal(t, 1, provider.writes)
+	assertTurnPersisted(t, service, events)
+
+	// Flushing an empty batch writes nothing
+	require.NoError(t, batch.Flush(ctx))
+	assert.Equal(t, 1, provider.writes)
+}
+
+// failingWritesProvider fails the next n writes
+type failingWritesProvider struct {
+	*writeCountingProvider
+	failures int
+}
+
+func (p *failingWritesProvider) Write(ctx context.Context, path string, data []byte) error {
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("storage unavailable")
+	}
+	return p.writeCountingProvider.Write(ctx, path, data)
+}
+
+func TestEventBatch_FlushRetriesFailedEvents(t *testing.T) {
+	service, provider, sess := newBatchTestService(t)
+	failing := &failingWritesProvider{writeCountingProvider: provider, failures: 1}
+	service.fileProvider = failing
+	batch := service.NewBatch()
+	ctx := context.Background()
+	events := turnEvents()
+
+	require.NoError(t, batch.AppendEvent(ctx, sess, events[0]))
+	require.Error(t, batch.Flush(ctx))
+
+	// Events appended after the failure are saved after the ones that failed
+	for _, event := range events[1:] {
+		require.NoError(t, batch.AppendEvent(ctx, sess, event))
+	}
+	require.NoError(t, batch.Flush(ctx))
+	assert.Equal(t, 1, provider.writes)
+	assertTurnPersisted(t, service, events)
+}
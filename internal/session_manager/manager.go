@@ -4,6 +4,7 @@ package session_manager //nolint:revive // var-naming: using underscores for dom
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -28,9 +29,33 @@ type Manager interface {
 	// UpdateLastActive updates the last active timestamp for a session
 	UpdateLastActive(ctx context.Context, sessionID string) error
 
+	// Touch marks a stored conversation as active by bumping its UpdatedAt, without
+	// appending an event
+	Touch(ctx context.Context, appName, userID, sessionID string) error
+
+	// ClearState removes every key from a stored conversation's state, keeping its events
+	ClearState(ctx context.Context, appName, userID, sessionID string) error
+
+	// DeleteStateKeys removes keys from a stored conversation's state
+	DeleteStateKeys(ctx context.Context, appName, userID, sessionID string, keys ...string) error
+
 	// ListUserSessions returns all sessions for a user+connector
 	ListUserSessions(ctx context.Context, connector, userID string) ([]SessionInfo, error)
 
+	// GetSessionInfo returns the connector, user and channel a session belongs to
+	GetSessionInfo(ctx context.Context, sessionID string) (SessionInfo, bool)
+
+	// AddParticipant records that a platform user took part in a shared session, so their
+	// data can be found for export or deletion
+	AddParticipant(ctx context.Context, sessionID, userID string) error
+
+	// ListSessions returns every indexed session on every connector
+	ListSessions(ctx context.Context) []SessionInfo
+
+	// ForgetUser removes a user's sessions from the index on every connector, returning how
+	// many were removed. The stored conversations are left to be deleted separately.
+	ForgetUser(ctx context.Context, userID string) (int, error)
+
 	// GetADKSessionService returns the ADK-compatible session.Service for conversation data
 	GetADKSessionService() session.Service
 }
@@ -41,6 +66,8 @@ type sessionManager struct {
 	mutex          sync.RWMutex
 	index          map[string]map[string][]SessionInfo // connector -> userID -> []sessions
 	fileMutex      sync.Mutex
+	snapshots      uint64          // Number of the last index snapshot taken, guarded by mutex
+	savedSnapshot  uint64          // Number of the last snapshot written, guarded by fileMutex
 	sessionService *SessionService // ADK-compatible session service
 }
 
@@ -56,10 +83,21 @@ func New(config Config) (Manager, error) {
 		return nil, fmt.Errorf("logger is required")
 	}
 
+	var serviceOpts []SessionServiceOption
+	if config.CompactJSON {
+		serviceOpts = append(serviceOpts, WithCompactJSON())
+	}
+	if config.ListConcurrency > 0 {
+		serviceOpts = append(serviceOpts, WithListConcurrency(config.ListConcurrency))
+	}
+	if config.Retention.enabled() {
+		serviceOpts = append(serviceOpts, WithRetention(config.Retention))
+	}
+
 	sm := &sessionManager{
 		config:         config,
 		index:          make(map[string]map[string][]SessionInfo),
-		sessionService: NewSessionService(config.FileProvider, config.Logger),
+		sessionService: NewSessionService(config.FileProvider, config.Logger, serviceOpts...),
 	}
 
 	// Load existing metadata
@@ -80,39 +118,21 @@ func (sm *sessionManager) GetLatestSession(ctx context.Context, connector, userI
 	sm.mutex.RLock()
 	defer sm.mutex.RUnlock()
 
-	users, ok := sm.index[connector]
-	if !ok {
-		return "", nil
-	}
-
-	sessions, ok := users[userID]
-	if !ok || len(sessions) == 0 {
-		return "", nil
-	}
-
-	// Find session with most recent LastActive
-	latest := sessions[0]
-	for _, s := range sessions[1:] {
-		if s.LastActive.After(latest.LastActive) {
-			latest = s
-		}
-	}
-
-	return latest.SessionID, nil
+	return sm.latestSession(connector, userID), nil
 }
 
-// GetOrCreateSession returns existing latest session or creates new one
+// GetOrCreateSession returns existing latest session or creates new one. The lookup and the
+// creation happen under one lock, so concurrent first messages in a scope share a session;
+// the index is saved after the lock is released.
 func (sm *sessionManager) GetOrCreateSession(ctx context.Context, connector, userID, channelID string) (string, error) {
-	// Try to get existing session first
-	sessionID, err := sm.GetLatestSession(ctx, connector, userID)
-	if err != nil {
-		return "", fmt.Errorf("failed to get latest session: %w", err)
-	}
+	sm.mutex.Lock()
 
-	if sessionID != "" {
+	// Try to get existing session first
+	if sessionID := sm.latestSession(connector, userID); sessionID != "" {
 		// Update last active time
-		if err := sm.UpdateLastActive(ctx, sessionID); err != nil {
-			sm.config.Logger.Warn("Failed to update last active time",
+		sm.markActive(sessionID)
+		if err := sm.unlockAndSave(ctx); err != nil {
+			sm.config.Logger.Warn("Failed to save metadata after updating last active",
 				logger.StringField("session_id", sessionID),
 				logger.ErrorField(err))
 		}
@@ -120,14 +140,38 @@ func (sm *sessionManager) GetOrCreateSession(ctx context.Context, connector, use
 	}
 
 	// No existing session, create new one
-	return sm.CreateNewSession(ctx, connector, userID, channelID)
+	sessionID := sm.createSession(connector, userID, channelID)
+	sm.saveCreatedSession(ctx, sessionID)
+	return sessionID, nil
 }
 
 // CreateNewSession always creates a new session
 func (sm *sessionManager) CreateNewSession(ctx context.Context, connector, userID, channelID string) (string, error) {
 	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
+	sessionID := sm.createSession(connector, userID, channelID)
+	sm.saveCreatedSession(ctx, sessionID)
+	return sessionID, nil
+}
+
+// latestSession returns the user's session with the most recent LastActive, or an empty
+// string if they have none. The caller must hold the mutex.
+func (sm *sessionManager) latestSession(connector, userID string) string {
+	sessions := sm.index[connector][userID]
+	if len(sessions) == 0 {
+		return ""
+	}
 
+	latest := sessions[0]
+	for _, s := range sessions[1:] {
+		if s.LastActive.After(latest.LastActive) {
+			latest = s
+		}
+	}
+	return latest.SessionID
+}
+
+// createSession adds a new session to the index. The caller must hold the mutex for writing.
+func (sm *sessionManager) createSession(connector, userID, channelID string) string {
 	// Generate new session ID
 	sessionID := prefixed_uuid.New("session").String()
 
@@ -147,57 +191,101 @@ func (sm *sessionManager) CreateNewSession(ctx context.Context, connector, userI
 		sm.index[connector] = make(map[string][]SessionInfo)
 	}
 
-	// Add to index
+	// Add to index, and have the stored session record where it came from so the index
+	// can be rebuilt from storage if the metadata file is lost
 	sm.index[connector][userID] = append(sm.index[connector][userID], info)
+	sm.sessionService.setOrigin(sessionID, connector, channelID)
 
-	// Persist to file
-	if err := sm.saveMetadata(ctx); err != nil {
+	sm.config.Logger.Info("Created new session",
+		logger.StringField("session_id", sessionID),
+		logger.StringField("connector", connector),
+		logger.StringField("user_id", userID))
+
+	return sessionID
+}
+
+// saveCreatedSession releases the mutex and persists the index after a session was created.
+// The caller must hold the mutex for writing.
+func (sm *sessionManager) saveCreatedSession(ctx context.Context, sessionID string) {
+	if err := sm.unlockAndSave(ctx); err != nil {
 		sm.config.Logger.Error("Failed to save metadata after creating session",
 			logger.StringField("session_id", sessionID),
 			logger.ErrorField(err))
 		// Don't return error - session is created in memory
 	}
+}
 
-	sm.config.Logger.Info("Created new session",
-		logger.StringField("session_id", sessionID),
-		logger.StringField("connector", connector),
-		logger.StringField("user_id", userID))
+// GetSessionInfo returns the connector, user and channel a session belongs to
+func (sm *sessionManager) GetSessionInfo(ctx context.Context, sessionID string) (SessionInfo, bool) {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
 
-	return sessionID, nil
+	for _, users := range sm.index {
+		for _, sessions := range users {
+			for _, info := range sessions {
+				if info.SessionID == sessionID {
+					return info, true
+				}
+			}
+		}
+	}
+	return SessionInfo{}, false
 }
 
-// UpdateLastActive updates the last active timestamp for a session
-func (sm *sessionManager) UpdateLastActive(ctx context.Context, sessionID string) error {
+// AddParticipant records that a platform user took part in a shared session. The index is
+// only saved the first time a user is added.
+func (sm *sessionManager) AddParticipant(ctx context.Context, sessionID, userID string) error {
 	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
-
-	// Find session in index
-	found := false
-	for connector, users := range sm.index {
-		for userID, sessions := range users {
-			for i, s := range sessions {
-				if s.SessionID == sessionID {
-					// Update timestamp
-					sm.index[connector][userID][i].LastActive = time.Now()
-					found = true
-					break
+
+	for _, users := range sm.index {
+		for _, sessions := range users {
+			for i := range sessions {
+				if sessions[i].SessionID != sessionID {
+					continue
 				}
-			}
-			if found {
-				break
+				if slices.Contains(sessions[i].Participants, userID) {
+					sm.mutex.Unlock()
+					return nil
+				}
+				sessions[i].Participants = append(sessions[i].Participants, userID)
+				if err := sm.unlockAndSave(ctx); err != nil {
+					return fmt.Errorf("failed to save metadata after adding participant: %w", err)
+				}
+				return nil
 			}
 		}
-		if found {
-			break
+	}
+	sm.mutex.Unlock()
+	return fmt.Errorf("session not found: %s", sessionID)
+}
+
+// ListSessions returns every indexed session on every connector
+func (sm *sessionManager) ListSessions(ctx context.Context) []SessionInfo {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	var result []SessionInfo
+	for _, users := range sm.index {
+		for _, sessions := range users {
+			for _, info := range sessions {
+				info.Participants = slices.Clone(info.Participants)
+				result = append(result, info)
+			}
 		}
 	}
+	return result
+}
 
-	if !found {
+// UpdateLastActive updates the last active timestamp for a session
+func (sm *sessionManager) UpdateLastActive(ctx context.Context, sessionID string) error {
+	sm.mutex.Lock()
+	if !sm.markActive(sessionID) {
+		sm.mutex.Unlock()
 		return fmt.Errorf("session not found: %s", sessionID)
 	}
 
 	// Persist to file
-	if err := sm.saveMetadata(ctx); err != nil {
+	if err := sm.unlockAndSave(ctx); err != nil {
 		sm.config.Logger.Warn("Failed to save metadata after updating last active",
 			logger.StringField("session_id", sessionID),
 			logger.ErrorField(err))
@@ -207,6 +295,64 @@ func (sm *sessionManager) UpdateLastActive(ctx context.Context, sessionID string
 	return nil
 }
 
+// markActive updates a session's last active timestamp, reporting whether the session was
+// found. The caller must hold the mutex for writing.
+func (sm *sessionManager) markActive(sessionID string) bool {
+	for _, users := range sm.index {
+		for _, sessions := range users {
+			for i := range sessions {
+				if sessions[i].SessionID == sessionID {
+					sessions[i].LastActive = time.Now()
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Touch marks a stored conversation as active by bumping its UpdatedAt, without
+// appending an event
+func (sm *sessionManager) Touch(ctx context.Context, appName, userID, sessionID string) error {
+	return sm.sessionService.Touch(ctx, appName, userID, sessionID)
+}
+
+// ClearState removes every key from a stored conversation's state, keeping its events. A
+// conversation that hasn't been stored yet has nothing to clear.
+func (sm *sessionManager) ClearState(ctx context.Context, appName, userID, sessionID string) error {
+	sess, err := sm.storedSession(ctx, appName, userID, sessionID)
+	if err != nil || sess == nil {
+		return err
+	}
+	return sm.sessionService.ClearState(ctx, sess)
+}
+
+// DeleteStateKeys removes keys from a stored conversation's state. A conversation that hasn't
+// been stored yet has nothing to delete.
+func (sm *sessionManager) DeleteStateKeys(ctx context.Context, appName, userID, sessionID string, keys ...string) error {
+	sess, err := sm.storedSession(ctx, appName, userID, sessionID)
+	if err != nil || sess == nil {
+		return err
+	}
+	return sm.sessionService.DeleteStateKeys(ctx, sess, keys...)
+}
+
+// storedSession loads a conversation from storage, or returns nil if it hasn't been stored yet
+func (sm *sessionManager) storedSession(ctx context.Context, appName, userID, sessionID string) (session.Session, error) {
+	exists, err := sm.config.FileProvider.Exists(ctx, sm.sessionService.getSessionKey(appName, userID, sessionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check session existence: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+	resp, err := sm.sessionService.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Session, nil
+}
+
 // ListUserSessions returns all sessions for a user+connector, sorted by LastActive descending
 func (sm *sessionManager) ListUserSessions(ctx context.Context, connector, userID string) ([]SessionInfo, error) {
 	sm.mutex.RLock()
@@ -233,3 +379,24 @@ func (sm *sessionManager) ListUserSessions(ctx context.Context, connector, userI
 
 	return result, nil
 }
+
+// ForgetUser removes a user's sessions from the index on every connector, returning how
+// many were removed
+func (sm *sessionManager) ForgetUser(ctx context.Context, userID string) (int, error) {
+	sm.mutex.Lock()
+
+	removed := 0
+	for _, users := range sm.index {
+		removed += len(users[userID])
+		delete(users, userID)
+	}
+	if removed == 0 {
+		sm.mutex.Unlock()
+		return 0, nil
+	}
+
+	if err := sm.unlockAndSave(ctx); err != nil {
+		return 0, fmt.Errorf("failed to save metadata after forgetting user: %w", err)
+	}
+	return removed, nil
+}
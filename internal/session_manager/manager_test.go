@@ -2,6 +2,8 @@ package session_manager
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"path/filepath"
 	"sync"
 	"testing"
@@ -11,6 +13,7 @@ import (
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/session"
 )
 
 func setupTestManager(t *testing.T) (Manager, string) {
@@ -241,6 +244,29 @@ func TestListUserSessions(t *testing.T) {
 	assert.Equal(t, session1, sessions[2].SessionID)
 }
 
+func TestGetSessionInfo(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+	ctx := context.Background()
+
+	slackSession, err := mgr.CreateNewSession(ctx, "slack", "U12345", "C67890")
+	require.NoError(t, err)
+	telegramSession, err := mgr.CreateNewSession(ctx, "telegram", "111", "222")
+	require.NoError(t, err)
+
+	info, ok := mgr.GetSessionInfo(ctx, telegramSession)
+	require.True(t, ok)
+	assert.Equal(t, "telegram", info.Connector)
+	assert.Equal(t, "111", info.UserID)
+	assert.Equal(t, "222", info.ChannelID)
+
+	info, ok = mgr.GetSessionInfo(ctx, slackSession)
+	require.True(t, ok)
+	assert.Equal(t, "C67890", info.ChannelID)
+
+	_, ok = mgr.GetSessionInfo(ctx, "session_unknown")
+	assert.False(t, ok)
+}
+
 func TestMultipleConnectors(t *testing.T) {
 	mgr, _ := setupTestManager(t)
 	ctx := context.Background()
@@ -311,6 +337,57 @@ func TestPersistenceAcrossRestarts(t *testing.T) {
 	assert.Equal(t, session2, telegramSessions[0].SessionID)
 }
 
+func TestNewRecoversFromPartialWrite(t *testing.T) {
+	tmpDir := t.TempDir()
+	fileProvider := storage_manager.NewLocalFileProvider(tmpDir)
+	config := Config{
+		MetadataFile: "sessions_metadata.json",
+		FileProvider: fileProvider,
+		Logger:       logger.NewLogger(logger.Config{Level: logger.InfoLevel, Format: "text"}),
+	}
+	ctx := context.Background()
+
+	mgr1, err := New(config)
+	require.NoError(t, err)
+	sessionID, err := mgr1.CreateNewSession(ctx, "slack", "U12345", "C67890")
+	require.NoError(t, err)
+	_, err = mgr1.GetADKSessionService().Create(ctx, &session.CreateRequest{
+		AppName: "test_app", UserID: "U12345", SessionID: sessionID,
+	})
+	require.NoError(t, err)
+
+	// Simulate a crash partway through writing the file in place, twice
+	path := filepath.Join(tmpDir, "sessions_metadata.json")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	partial := data[:len(data)/2]
+	for range 2 {
+		require.NoError(t, os.WriteFile(path, partial, 0o600))
+		_, err = New(config)
+		require.NoError(t, err, "a corrupt metadata file shouldn't stop startup")
+	}
+
+	copies, err := filepath.Glob(path + corruptSuffix + "*")
+	require.NoError(t, err)
+	require.Len(t, copies, 2, "each corrupt file should be kept for inspection")
+	kept, err := os.ReadFile(copies[0])
+	require.NoError(t, err)
+	assert.Equal(t, partial, kept)
+
+	// The rebuilt index was saved, so it survives another restart
+	mgr2, err := New(config)
+	require.NoError(t, err)
+	sessions, err := mgr2.ListUserSessions(ctx, "slack", "U12345")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1, "the session should be rebuilt from storage")
+	assert.Equal(t, sessionID, sessions[0].SessionID)
+	assert.Equal(t, "C67890", sessions[0].ChannelID)
+
+	latest, err := mgr2.GetOrCreateSession(ctx, "slack", "U12345", "C67890")
+	require.NoError(t, err)
+	assert.Equal(t, sessionID, latest)
+}
+
 func TestConcurrentAccess(t *testing.T) {
 	mgr, _ := setupTestManager(t)
 	ctx := context.Background()
@@ -388,6 +465,123 @@ func TestConcurrentAccess(t *testing.T) {
 	}
 }
 
+func TestConcurrentFirstContact(t *testing.T) {
+	mgr, metadataFile := setupTestManager(t)
+	ctx := context.Background()
+
+	const numScopes = 50
+	const callsPerScope = 8
+
+	// Every scope is contacted by several goroutines at once, with no session beforehand
+	var wg sync.WaitGroup
+	results := make([][]string, numScopes)
+	for i := range results {
+		results[i] = make([]string, callsPerScope)
+	}
+	start := make(chan struct{})
+	for i := 0; i < numScopes; i++ {
+		for j := 0; j < callsPerScope; j++ {
+			wg.Add(1)
+			go func(scope, call int) {
+				defer wg.Done()
+				<-start
+				sessionID, err := mgr.GetOrCreateSession(ctx, "slack", fmt.Sprintf("U%d", scope), "C1")
+				assert.NoError(t, err)
+				results[scope][call] = sessionID
+			}(i, j)
+		}
+	}
+	close(start)
+	wg.Wait()
+
+	// Each scope gets exactly one session, shared by every call
+	for i, ids := range results {
+		for _, id := range ids {
+			assert.Equal(t, ids[0], id, "scope %d returned different sessions", i)
+		}
+		sessions, err := mgr.ListUserSessions(ctx, "slack", fmt.Sprintf("U%d", i))
+		require.NoError(t, err)
+		assert.Len(t, sessions, 1, "scope %d", i)
+	}
+
+	// The metadata file is valid and has every scope's session
+	reloaded, err := New(Config{
+		MetadataFile: metadataFile,
+		FileProvider: storage_manager.NewLocalFileProvider(filepath.Dir(metadataFile)),
+		Logger:       logger.NewLogger(logger.Config{Level: logger.InfoLevel, Format: "text"}),
+	})
+	require.NoError(t, err)
+	for i, ids := range results {
+		sessionID, err := reloaded.GetLatestSession(ctx, "slack", fmt.Sprintf("U%d", i))
+		require.NoError(t, err)
+		assert.Equal(t, ids[0], sessionID, "scope %d after reload", i)
+	}
+}
+
+// blockingMetadataProvider holds writes to the metadata file until release is closed,
+// signalling on writing when one starts
+type blockingMetadataProvider struct {
+	storage_manager.FileProvider
+	metadataFile string
+	writing      chan struct{}
+	release      chan struct{}
+}
+
+func (p *blockingMetadataProvider) Write(ctx context.Context, path string, data []byte) error {
+	if path == p.metadataFile {
+		select {
+		case p.writing <- struct{}{}:
+		default:
+		}
+		<-p.release
+	}
+	return p.FileProvider.Write(ctx, path, data)
+}
+
+func TestSlowMetadataWriteDoesNotBlockLookups(t *testing.T) {
+	tmpDir := t.TempDir()
+	metadataFile := filepath.Join(tmpDir, "sessions_metadata.json")
+	provider := &blockingMetadataProvider{
+		FileProvider: storage_manager.NewLocalFileProvider(tmpDir),
+		metadataFile: metadataFile,
+		writing:      make(chan struct{}, 1),
+		release:      make(chan struct{}),
+	}
+	mgr, err := New(Config{
+		MetadataFile: metadataFile,
+		FileProvider: provider,
+		Logger:       logger.NewLogger(logger.Config{Level: logger.InfoLevel, Format: "text"}),
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	created := make(chan string)
+	go func() {
+		sessionID, err := mgr.GetOrCreateSession(ctx, "slack", "U12345", "C67890")
+		assert.NoError(t, err)
+		created <- sessionID
+	}()
+	<-provider.writing
+
+	// While the save is stuck, the new session can already be looked up
+	lookup := make(chan string)
+	go func() {
+		sessionID, err := mgr.GetLatestSession(ctx, "slack", "U12345")
+		assert.NoError(t, err)
+		lookup <- sessionID
+	}()
+	var found string
+	select {
+	case found = <-lookup:
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetLatestSession blocked behind a metadata write")
+	}
+
+	close(provider.release)
+	sessionID := <-created
+	assert.Equal(t, sessionID, found)
+}
+
 func TestSessionIsolation(t *testing.T) {
 	mgr, _ := setupTestManager(t)
 	ctx := context.Background()
@@ -410,3 +604,51 @@ func TestSessionIsolation(t *testing.T) {
 	assert.Len(t, user2Sessions, 1)
 	assert.Equal(t, user2Session, user2Sessions[0].SessionID)
 }
+
+func TestManagerTouch(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+	ctx := context.Background()
+	sessions := mgr.GetADKSessionService()
+
+	created, err := sessions.Create(ctx, &session.CreateRequest{AppName: "chatbot", UserID: "U123", SessionID: "session-1"})
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, mgr.Touch(ctx, "chatbot", "U123", "session-1"))
+
+	got, err := sessions.Get(ctx, &session.GetRequest{AppName: "chatbot", UserID: "U123", SessionID: "session-1"})
+	require.NoError(t, err)
+	assert.True(t, got.Session.LastUpdateTime().After(created.Session.LastUpdateTime()))
+	assert.Equal(t, 0, got.Session.Events().Len())
+}
+
+func TestManagerClearState(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+	ctx := context.Background()
+	sessions := mgr.GetADKSessionService()
+
+	_, err := sessions.Create(ctx, &session.CreateRequest{
+		AppName: "chatbot", UserID: "U123", SessionID: "session-1",
+		State: map[string]any{"language": "fr", "language_override": "fr", "budget_tokens": 500},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, mgr.DeleteStateKeys(ctx, "chatbot", "U123", "session-1", "language_override"))
+	got, err := sessions.Get(ctx, &session.GetRequest{AppName: "chatbot", UserID: "U123", SessionID: "session-1"})
+	require.NoError(t, err)
+	_, err = got.Session.State().Get("language_override")
+	assert.Error(t, err, "language_override should be deleted")
+	language, err := got.Session.State().Get("language")
+	require.NoError(t, err)
+	assert.Equal(t, "fr", language)
+
+	require.NoError(t, mgr.ClearState(ctx, "chatbot", "U123", "session-1"))
+	got, err = sessions.Get(ctx, &session.GetRequest{AppName: "chatbot", UserID: "U123", SessionID: "session-1"})
+	require.NoError(t, err)
+	for key := range got.Session.State().All() {
+		t.Errorf("state has %q after ClearState, want it empty", key)
+	}
+
+	// A conversation that hasn't been stored yet has nothing to clear
+	assert.NoError(t, mgr.ClearState(ctx, "chatbot", "U123", "session-2"))
+}
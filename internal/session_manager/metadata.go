@@ -4,10 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
 )
 
+// corruptSuffix is added to the metadata file's name, followed by a timestamp, for the copy
+// kept when it can't be parsed
+const corruptSuffix = ".corrupt-"
+
 // loadMetadata loads session metadata from the JSON file
 func (sm *sessionManager) loadMetadata(ctx context.Context) error {
 	sm.fileMutex.Lock()
@@ -32,10 +38,20 @@ func (sm *sessionManager) loadMetadata(ctx context.Context) error {
 		return fmt.Errorf("failed to read metadata file: %w", err)
 	}
 
-	// Parse JSON
+	// Parse JSON. A file cut short by a crash shouldn't stop the bot starting, so it's kept
+	// aside for inspection and the index is rebuilt from the stored sessions.
 	var store metadataStore
 	if err := json.Unmarshal(data, &store); err != nil {
-		return fmt.Errorf("failed to parse metadata JSON: %w", err)
+		corruptFile := sm.config.MetadataFile + corruptSuffix +
+			strings.ReplaceAll(time.Now().UTC().Format("20060102T150405.000000000Z"), ".", "")
+		if writeErr := sm.config.FileProvider.Write(ctx, corruptFile, data); writeErr != nil {
+			return fmt.Errorf("failed to parse metadata JSON (%w) or keep a copy: %w", err, writeErr)
+		}
+		sm.config.Logger.Error("Metadata file is corrupt, rebuilding index from stored sessions",
+			logger.StringField("file", sm.config.MetadataFile),
+			logger.StringField("copy", corruptFile),
+			logger.ErrorField(err))
+		return sm.rebuildIndex(ctx)
 	}
 
 	// Load into index
@@ -48,26 +64,105 @@ func (sm *sessionManager) loadMetadata(ctx context.Context) error {
 	return nil
 }
 
-// saveMetadata persists session metadata to the JSON file
-func (sm *sessionManager) saveMetadata(ctx context.Context) error {
+// rebuildIndex replaces the index with one built from the headers of the stored sessions and
+// saves it. Sessions stored before their header recorded a connector can't be placed and are
+// skipped, as are the participants of shared sessions, which only the index records. The
+// caller must hold the file mutex.
+func (sm *sessionManager) rebuildIndex(ctx context.Context) error {
+	files, err := sm.config.FileProvider.List(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list stored sessions: %w", err)
+	}
+
+	sm.index = make(map[string]map[string][]SessionInfo)
+	recovered, skipped := 0, 0
+	for _, file := range files {
+		// Stored sessions are the files at <app>/<user>/<session>.json
+		if strings.Count(file, "/") != 2 || !strings.HasSuffix(file, ".json") {
+			continue
+		}
+		data, err := sm.config.FileProvider.Read(ctx, file)
+		var header sessionHeader
+		if err != nil || json.Unmarshal(data, &header) != nil || header.Connector == "" {
+			skipped++
+			continue
+		}
+
+		if sm.index[header.Connector] == nil {
+			sm.index[header.Connector] = make(map[string][]SessionInfo)
+		}
+		sm.index[header.Connector][header.UserID] = append(sm.index[header.Connector][header.UserID], SessionInfo{
+			SessionID:  header.SessionID,
+			Connector:  header.Connector,
+			UserID:     header.UserID,
+			ChannelID:  header.ChannelID,
+			CreatedAt:  header.CreatedAt,
+			LastActive: header.UpdatedAt,
+		})
+		recovered++
+	}
+
+	data, err := marshalIndex(sm.index)
+	if err != nil {
+		return err
+	}
+	if err := sm.writeMetadata(ctx, data); err != nil {
+		return err
+	}
+	sm.config.Logger.Info("Rebuilt session metadata from stored sessions",
+		logger.IntField("recovered", recovered),
+		logger.IntField("skipped", skipped))
+	return nil
+}
+
+// metadataSnapshot is the marshalled index at one point, numbered so an older snapshot is
+// never written over a newer one
+type metadataSnapshot struct {
+	number uint64
+	data   []byte
+}
+
+// unlockAndSave snapshots the index, releases the mutex and then saves the snapshot, so the
+// write (synced to disk for local storage) doesn't hold up lookups for other sessions. The
+// caller must hold the mutex for writing.
+func (sm *sessionManager) unlockAndSave(ctx context.Context) error {
+	data, err := marshalIndex(sm.index)
+	if err != nil {
+		sm.mutex.Unlock()
+		return err
+	}
+	sm.snapshots++
+	snapshot := metadataSnapshot{number: sm.snapshots, data: data}
+	sm.mutex.Unlock()
+
 	sm.fileMutex.Lock()
 	defer sm.fileMutex.Unlock()
 
-	// Create metadata store structure
-	store := metadataStore{
-		Sessions: sm.index,
+	// A later snapshot saved first already includes this one's changes
+	if snapshot.number <= sm.savedSnapshot {
+		return nil
+	}
+	if err := sm.writeMetadata(ctx, snapshot.data); err != nil {
+		return err
 	}
+	sm.savedSnapshot = snapshot.number
+	return nil
+}
 
-	// Marshal to JSON
-	data, err := json.MarshalIndent(store, "", "  ")
+// marshalIndex encodes the index in the metadata file's format
+func marshalIndex(index map[string]map[string][]SessionInfo) ([]byte, error) {
+	data, err := json.MarshalIndent(metadataStore{Sessions: index}, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
 	}
+	return data, nil
+}
 
+// writeMetadata writes data to the metadata file. The caller must hold the file mutex.
+func (sm *sessionManager) writeMetadata(ctx context.Context, data []byte) error {
 	// Write file (FileProvider handles directory creation for local storage)
 	if err := sm.config.FileProvider.Write(ctx, sm.config.MetadataFile, data); err != nil {
 		return fmt.Errorf("failed to write metadata file: %w", err)
 	}
-
 	return nil
 }
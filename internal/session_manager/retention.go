@@ -0,0 +1,137 @@
+package session_manager //nolint:revive // var-naming: using underscores for domain clarity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"google.golang.org/adk/session"
+)
+
+// Retention bounds how many events a stored session keeps. Events beyond the limits are
+// pruned when new events are appended, oldest first; session state is kept regardless.
+// The zero value keeps every event.
+type Retention struct {
+	MaxEvents int           // Most recent events to keep; 0 for no count limit
+	MaxAge    time.Duration // Drop events older than this; 0 for no age limit
+
+	// Archive receives pruned events instead of them being deleted. It should be a separate
+	// namespace from the sessions so archives aren't listed as sessions.
+	Archive storage_manager.FileProvider
+}
+
+// enabled reports whether any limit is set
+func (r Retention) enabled() bool {
+	return r.MaxEvents > 0 || r.MaxAge > 0
+}
+
+// archivedEvents is the structure of an archive file
+type archivedEvents struct {
+	AppName    string           `json:"app_name"`
+	UserID     string           `json:"user_id"`
+	SessionID  string           `json:"session_id"`
+	ArchivedAt time.Time        `json:"archived_at"`
+	Events     []*session.Event `json:"events"`
+}
+
+// WithRetention prunes stored events beyond the retention limits when events are appended
+func WithRetention(retention Retention) SessionServiceOption {
+	return func(s *SessionService) {
+		s.retention = retention
+	}
+}
+
+// pruneCount returns how many of the oldest events fall outside the retention limits. The
+// cut is moved past function responses so a kept response never lacks its call.
+func (r Retention) pruneCount(events []*session.Event, now time.Time) int {
+	cut := 0
+	if r.MaxEvents > 0 && len(events) > r.MaxEvents {
+		cut = len(events) - r.MaxEvents
+	}
+	if r.MaxAge > 0 {
+		oldest := now.Add(-r.MaxAge)
+		for cut < len(events) && events[cut] != nil && events[cut].Timestamp.Before(oldest) {
+			cut++
+		}
+	}
+	for cut > 0 && cut < len(events) && isFunctionResponse(events[cut]) {
+		cut++
+	}
+	return cut
+}
+
+// isFunctionResponse reports whether an event carries a tool result
+func isFunctionResponse(event *session.Event) bool {
+	if event == nil || event.Content == nil {
+		return false
+	}
+	for _, part := range event.Content.Parts {
+		if part.FunctionResponse != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// applyRetention prunes sessionData's events beyond the retention limits, archiving them
+// first when an archive is configured. If archiving fails the events are kept, to be
+// pruned on a later append.
+func (s *SessionService) applyRetention(ctx context.Context, sessionKey string, sessionData *SessionData) {
+	if !s.retention.enabled() {
+		return
+	}
+	cut := s.retention.pruneCount(sessionData.Events, time.Now())
+	if cut == 0 {
+		return
+	}
+	pruned := sessionData.Events[:cut]
+
+	if s.retention.Archive != nil {
+		if err := s.archiveEvents(ctx, sessionData, pruned); err != nil {
+			s.log.WarnCtx(ctx, "Failed to archive pruned session events, keeping them",
+				logger.StringField("session_key", sessionKey),
+				logger.ErrorField(err))
+			return
+		}
+	}
+
+	sessionData.Events = append([]*session.Event(nil), sessionData.Events[cut:]...)
+	s.log.DebugCtx(ctx, "Pruned session events",
+		logger.StringField("session_key", sessionKey),
+		logger.IntField("pruned", cut),
+		logger.IntField("kept", len(sessionData.Events)),
+		logger.BoolField("archived", s.retention.Archive != nil))
+}
+
+// archiveEvents writes pruned events to their own file in the archive, keyed by the
+// session and the time they were archived
+func (s *SessionService) archiveEvents(ctx context.Context, sessionData *SessionData, events []*session.Event) error {
+	now := time.Now()
+	data, err := json.Marshal(archivedEvents{
+		AppName:    sessionData.AppName,
+		UserID:     sessionData.UserID,
+		SessionID:  sessionData.SessionID,
+		ArchivedAt: now,
+		Events:     events,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal archived events: %w", err)
+	}
+
+	key := ArchiveKey(sessionData.AppName, sessionData.UserID, sessionData.SessionID, now)
+	if err := s.retention.Archive.Write(ctx, key, data); err != nil {
+		return fmt.Errorf("failed to write archived events: %w", err)
+	}
+	return nil
+}
+
+// ArchiveKey returns the archive file for events pruned from a session at the given time.
+// A session's archive files sort in the order they were written.
+func ArchiveKey(appName, userID, sessionID string, archivedAt time.Time) string {
+	stamp := strings.ReplaceAll(archivedAt.UTC().Format("20060102T150405.000000000Z"), ".", "")
+	return fmt.Sprintf("%s/%s/%s/%s.json", appName, userID, sessionID, stamp)
+}
@@ -0,0 +1,115 @@
+package session_manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// appendNumberedEvents appends events whose invocation IDs are inv-<first> to inv-<last>
+func appendNumberedEvents(t *testing.T, service *SessionService, sess session.Session, first, last int) {
+	t.Helper()
+	for i := first; i <= last; i++ {
+		event := session.NewEvent(fmt.Sprintf("inv-%d", i))
+		event.Author = "user"
+		require.NoError(t, service.AppendEvent(context.Background(), sess, event))
+	}
+}
+
+// storedInvocations returns the invocation IDs of a stored session's events, in order
+func storedInvocations(t *testing.T, service *SessionService) []string {
+	t.Helper()
+	resp, err := service.Get(context.Background(), &session.GetRequest{AppName: "app", UserID: "user", SessionID: "retained"})
+	require.NoError(t, err)
+	var ids []string
+	for event := range resp.Session.Events().All() {
+		ids = append(ids, event.InvocationID)
+	}
+	return ids
+}
+
+func newRetentionTestService(t *testing.T, retention Retention) (*SessionService, session.Session) {
+	t.Helper()
+	service := NewSessionService(storage_manager.NewLocalFileProvider(t.TempDir()), testLogger(), WithRetention(retention))
+	created, err := service.Create(context.Background(), &session.CreateRequest{
+		AppName:   "app",
+		UserID:    "user",
+		SessionID: "retained",
+		State:     map[string]any{"kept": "yes"},
+	})
+	require.NoError(t, err)
+	return service, created.Session
+}
+
+func TestRetention_KeepsLastEvents(t *testing.T) {
+	service, sess := newRetentionTestService(t, Retention{MaxEvents: 3})
+
+	appendNumberedEvents(t, service, sess, 1, 5)
+
+	assert.Equal(t, []string{"inv-3", "inv-4", "inv-5"}, storedInvocations(t, service))
+
+	resp, err := service.Get(context.Background(), &session.GetRequest{AppName: "app", UserID: "user", SessionID: "retained"})
+	require.NoError(t, err)
+	kept, err := resp.Session.State().Get("kept")
+	require.NoError(t, err)
+	assert.Equal(t, "yes", kept, "state should survive pruning")
+}
+
+func TestRetention_DropsOldEvents(t *testing.T) {
+	service, sess := newRetentionTestService(t, Retention{MaxAge: time.Hour})
+
+	old := session.NewEvent("inv-old")
+	old.Author = "user"
+	old.Timestamp = time.Now().Add(-2 * time.Hour)
+	require.NoError(t, service.AppendEvent(context.Background(), sess, old))
+	appendNumberedEvents(t, service, sess, 1, 2)
+
+	assert.Equal(t, []string{"inv-1", "inv-2"}, storedInvocations(t, service))
+}
+
+func TestRetention_ArchivesPrunedEvents(t *testing.T) {
+	archive := storage_manager.NewLocalFileProvider(t.TempDir())
+	service, sess := newRetentionTestService(t, Retention{MaxEvents: 2, Archive: archive})
+	ctx := context.Background()
+
+	appendNumberedEvents(t, service, sess, 1, 4)
+
+	assert.Equal(t, []string{"inv-3", "inv-4"}, storedInvocations(t, service))
+
+	files, err := archive.List(ctx, "app/user/retained/")
+	require.NoError(t, err)
+	var archived []string
+	for _, file := range files {
+		data, err := archive.Read(ctx, file)
+		require.NoError(t, err)
+		var contents archivedEvents
+		require.NoError(t, json.Unmarshal(data, &contents))
+		assert.Equal(t, "retained", contents.SessionID)
+		for _, event := range contents.Events {
+			archived = append(archived, event.InvocationID)
+		}
+	}
+	assert.ElementsMatch(t, []string{"inv-1", "inv-2"}, archived)
+}
+
+func TestRetention_KeepsToolResultsWithTheirCall(t *testing.T) {
+	events := make([]*session.Event, 4)
+	for i := range events {
+		events[i] = session.NewEvent(fmt.Sprintf("inv-%d", i))
+	}
+	events[1].Content = genai.NewContentFromFunctionCall("lookup", nil, genai.RoleModel)
+	events[2].Content = genai.NewContentFromFunctionResponse("lookup", map[string]any{"ok": true}, genai.RoleUser)
+
+	// Keeping two would start with the tool result, so it goes with its call
+	assert.Equal(t, 3, Retention{MaxEvents: 2}.pruneCount(events, time.Now()))
+	assert.Equal(t, 1, Retention{MaxEvents: 3}.pruneCount(events, time.Now()))
+	assert.Equal(t, 0, Retention{MaxEvents: 10}.pruneCount(events, time.Now()))
+}
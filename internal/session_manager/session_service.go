@@ -6,12 +6,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"iter"
+	"math"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
 	"github.com/lewisedginton/general_purpose_chatbot/pkg/logger"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/adk/session"
 )
 
@@ -22,9 +26,43 @@ var eventIDCounter atomic.Uint64
 type SessionService struct {
 	fileProvider   storage_manager.FileProvider
 	mutex          sync.RWMutex
-	sessionLocks   map[string]*sync.Mutex // Per-session locks to prevent concurrent modifications
-	sessionLockMux sync.Mutex             // Protects the sessionLocks map itself
-	log            logger.Logger          // Logger for debugging
+	sessionLocks   map[string]*sync.Mutex   // Per-session locks to prevent concurrent modifications
+	sessionLockMux sync.Mutex               // Protects the sessionLocks map itself
+	log            logger.Logger            // Logger for debugging
+	compactJSON    bool                     // Write sessions as compact rather than indented JSON
+	listWorkers    int                      // Session files List loads at once
+	sizeLogOnce    sync.Once                // Logs the compact vs indented size difference once
+	retention      Retention                // Limits on the events kept per session
+	origins        map[string]sessionOrigin // Where indexed sessions not yet stored came from
+	originMux      sync.Mutex               // Protects the origins map
+}
+
+// sessionOrigin is the connector and channel a session was started from
+type sessionOrigin struct {
+	connector string
+	channelID string
+}
+
+// DefaultListConcurrency is how many session files List loads at once by default
+const DefaultListConcurrency = 8
+
+// SessionServiceOption configures optional SessionService behaviour
+type SessionServiceOption func(*SessionService)
+
+// WithCompactJSON writes sessions as compact JSON instead of indented JSON, which is
+// considerably smaller for sessions with many events. Reads accept either format.
+func WithCompactJSON() SessionServiceOption {
+	return func(s *SessionService) {
+		s.compactJSON = true
+	}
+}
+
+// WithListConcurrency sets how many session files List loads at once, which speeds up
+// listing against remote backends such as S3. Values below 1 load one at a time.
+func WithListConcurrency(n int) SessionServiceOption {
+	return func(s *SessionService) {
+		s.listWorkers = max(n, 1)
+	}
 }
 
 // SessionData represents the structure of session data stored in JSON.
@@ -34,25 +72,33 @@ type SessionData struct {
 	SessionID string           `json:"session_id"`
 	CreatedAt time.Time        `json:"created_at"`
 	UpdatedAt time.Time        `json:"updated_at"`
-	State     map[string]any   `json:"state,omitempty"`  // Session state as key-value pairs
-	Events    []*session.Event `json:"events,omitempty"` // Session events
+	Connector string           `json:"connector,omitempty"`  // Platform the session was started from, if known
+	ChannelID string           `json:"channel_id,omitempty"` // Channel or chat the session was started in, if known
+	State     map[string]any   `json:"state,omitempty"`      // Session state as key-value pairs
+	Events    []*session.Event `json:"events,omitempty"`     // Session events
 }
 
 // NewSessionService creates a new session service with the given file provider.
 // The provider should be obtained from a StorageManager, typically with a
 // "sessions" namespace prefix.
-func NewSessionService(provider storage_manager.FileProvider, log logger.Logger) *SessionService {
+func NewSessionService(provider storage_manager.FileProvider, log logger.Logger, opts ...SessionServiceOption) *SessionService {
 	if provider == nil {
 		panic("file provider cannot be nil")
 	}
 	if log == nil {
 		panic("logger cannot be nil")
 	}
-	return &SessionService{
+	s := &SessionService{
 		fileProvider: provider,
 		sessionLocks: make(map[string]*sync.Mutex),
+		origins:      make(map[string]sessionOrigin),
 		log:          log,
+		listWorkers:  DefaultListConcurrency,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Create creates a new session.
@@ -94,20 +140,23 @@ func (s *SessionService) Create(ctx context.Context, req *session.CreateRequest)
 	// Create new session data
 	now := time.Now()
 
-	// Copy initial state from request
+	// Copy initial state from request; nil values mean the key is absent
 	initialState := make(map[string]any)
-	if req.State != nil {
-		for k, v := range req.State {
+	for k, v := range req.State {
+		if v != nil {
 			initialState[k] = v
 		}
 	}
 
+	origin := s.takeOrigin(sessionID)
 	sessionData := &SessionData{
 		AppName:   req.AppName,
 		UserID:    req.UserID,
 		SessionID: sessionID,
 		CreatedAt: now,
 		UpdatedAt: now,
+		Connector: origin.connector,
+		ChannelID: origin.channelID,
 		State:     initialState,
 		Events:    make([]*session.Event, 0),
 	}
@@ -135,7 +184,7 @@ func (s *SessionService) Get(ctx context.Context, req *session.GetRequest) (*ses
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	s.log.Debug("Loading session from storage", logger.StringField("session_key", sessionKey))
+	s.log.DebugCtx(ctx, "Loading session from storage", logger.StringField("session_key", sessionKey))
 
 	// Check if session exists before trying to load
 	exists, err := s.fileProvider.Exists(ctx, sessionKey)
@@ -229,13 +278,30 @@ func (s *SessionService) List(ctx context.Context, req *session.ListRequest) (*s
 		return nil, fmt.Errorf("failed to list session files: %w", err)
 	}
 
+	// Load the session files a few at a time, keeping them in listing order. A file that
+	// fails to load is skipped (loadSession logs it) rather than failing the whole list.
+	loaded := make([]*SessionData, len(files))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.listWorkers)
+	for i, file := range files {
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			if sessionData, err := s.loadSession(gctx, file); err == nil {
+				loaded[i] = sessionData
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
 	// Pre-allocate with estimated capacity
 	sessions := make([]session.Session, 0, len(files))
-	for _, file := range files {
-		// Load session data
-		sessionData, err := s.loadSession(ctx, file)
-		if err != nil {
-			// Log error but continue with other sessions
+	for _, sessionData := range loaded {
+		if sessionData == nil {
 			continue
 		}
 
@@ -272,58 +338,94 @@ func (s *SessionService) Delete(ctx context.Context, req *session.DeleteRequest)
 	return nil
 }
 
-// AppendEvent appends an event to a session.
-//
-//nolint:gocyclo,revive // Event handling requires multiple type assertions and state management
+// AppendEvent appends an event to a session
 func (s *SessionService) AppendEvent(ctx context.Context, sess session.Session, event *session.Event) error {
+	return s.AppendEvents(ctx, sess, []*session.Event{event})
+}
+
+// AppendEvents appends events to a session in order, applying their state deltas,
+// where a nil value deletes the key.
+// The session is loaded and saved once for the whole batch, so a turn's user message,
+// tool calls and reply can be persisted together in a single write.
+func (s *SessionService) AppendEvents(ctx context.Context, sess session.Session, events []*session.Event) error {
 	if sess == nil {
 		return fmt.Errorf("session cannot be nil")
 	}
 
-	if event == nil {
-		return fmt.Errorf("event cannot be nil")
+	for _, event := range events {
+		if event == nil {
+			return fmt.Errorf("event cannot be nil")
+		}
 	}
 
-	// Skip partial events - they should not be persisted
-	if event.Partial {
+	pending := make([]*session.Event, 0, len(events))
+	for _, event := range events {
+		// Skip partial events - they should not be persisted
+		if event.Partial {
+			continue
+		}
+
+		prepareEvent(event)
+		applyToSession(sess, event)
+		pending = append(pending, event)
+	}
+
+	if len(pending) == 0 {
 		return nil
 	}
+	return s.persistEvents(ctx, sess, pending)
+}
 
-	// Generate event ID if not set
+// prepareEvent assigns an ID and timestamp to an event that doesn't have them
+func prepareEvent(event *session.Event) {
 	// Use atomic counter combined with timestamp to ensure uniqueness even under high concurrency
 	if event.ID == "" {
 		counter := eventIDCounter.Add(1)
 		event.ID = fmt.Sprintf("event_%d_%d", time.Now().UnixNano(), counter)
 	}
 
-	// Set timestamp if not set
 	if event.Timestamp.IsZero() {
 		event.Timestamp = time.Now()
 	}
+}
 
-	// Update the in-memory session object - this is critical!
-	// The runner reuses the same session object and expects events to be available
-	// via sess.Events() on subsequent turns. Without this, the API call fails with
-	// "messages: Field required" because the events list appears empty.
-	if adkSess, ok := sess.(*adkSession); ok {
-		// Update events in-memory
-		if evts, ok := adkSess.events.(*sessionEvents); ok {
-			evts.mutex.Lock()
-			evts.events = append(evts.events, event)
-			evts.mutex.Unlock()
-		}
-		// Update state in-memory (excluding temporary keys)
-		if state, ok := adkSess.state.(*sessionState); ok {
-			for key, value := range event.Actions.StateDelta {
-				if !isTemporaryKey(key) {
-					_ = state.Set(key, value)
-				}
+// applyToSession adds an event and its state delta to the in-memory session object.
+// The runner reuses the same session object and expects events to be available
+// via sess.Events() on subsequent turns. Without this, the API call fails with
+// "messages: Field required" because the events list appears empty.
+func applyToSession(sess session.Session, event *session.Event) {
+	adkSess, ok := sess.(*adkSession)
+	if !ok {
+		return
+	}
+
+	// Update events in-memory
+	if evts, ok := adkSess.events.(*sessionEvents); ok {
+		evts.mutex.Lock()
+		evts.events = append(evts.events, event)
+		evts.mutex.Unlock()
+	}
+	// Update state in-memory (excluding temporary keys); nil values delete keys
+	if state, ok := adkSess.state.(*sessionState); ok {
+		for key, value := range event.Actions.StateDelta {
+			switch {
+			case isTemporaryKey(key):
+			case value == nil:
+				state.delete(key)
+			default:
+				_ = state.Set(key, value)
 			}
 		}
 	}
+}
 
+// persistEvents loads the stored session once, applies the events and their state deltas
+// in order, and saves it once
+func (s *SessionService) persistEvents(ctx context.Context, sess session.Session, events []*session.Event) error {
 	sessionKey := s.getSessionKey(sess.AppName(), sess.UserID(), sess.ID())
-	s.log.Debug("Appending event to session", logger.StringField("session_key", sessionKey))
+	s.log.DebugCtx(ctx, "Appending events to session",
+		logger.StringField("session_key", sessionKey),
+		logger.IntField("count", len(events)))
 
 	// Acquire session-specific lock to prevent concurrent modifications to the same session
 	sessionLock := s.getSessionLock(sessionKey)
@@ -338,30 +440,40 @@ func (s *SessionService) AppendEvent(ctx context.Context, sess session.Session,
 
 	// Initialize events slice if nil
 	if sessionData.Events == nil {
-		sessionData.Events = make([]*session.Event, 0)
+		sessionData.Events = make([]*session.Event, 0, len(events))
 	}
 
-	// Apply state delta from the event to the session state
-	// Exclude temporary keys (temp: prefix) from persistence
-	if len(event.Actions.StateDelta) > 0 {
-		if sessionData.State == nil {
-			sessionData.State = make(map[string]any)
-		}
+	for _, event := range events {
+		// Apply state delta from the event to the session state
+		// Exclude temporary keys (temp: prefix) from persistence
+		if len(event.Actions.StateDelta) > 0 {
+			if sessionData.State == nil {
+				sessionData.State = make(map[string]any)
+			}
 
-		// Filter out temporary keys from the event's StateDelta before persisting
-		filteredStateDelta := make(map[string]any)
-		for key, value := range event.Actions.StateDelta {
-			if !isTemporaryKey(key) {
-				sessionData.State[key] = value
+			// Filter out temporary keys from the event's StateDelta before persisting.
+			// A nil value deletes the key; it stays in the event as a record of the deletion.
+			filteredStateDelta := make(map[string]any)
+			for key, value := range event.Actions.StateDelta {
+				if isTemporaryKey(key) {
+					continue
+				}
+				if value == nil {
+					delete(sessionData.State, key)
+				} else {
+					sessionData.State[key] = value
+				}
 				filteredStateDelta[key] = value
 			}
+			// Update the event's StateDelta to only contain non-temporary keys
+			event.Actions.StateDelta = filteredStateDelta
 		}
-		// Update the event's StateDelta to only contain non-temporary keys
-		event.Actions.StateDelta = filteredStateDelta
+
+		sessionData.Events = append(sessionData.Events, event)
 	}
 
-	// Append the event
-	sessionData.Events = append(sessionData.Events, event)
+	// Drop the oldest events beyond the retention limits
+	s.applyRetention(ctx, sessionKey, sessionData)
 
 	// Save the updated session
 	if err := s.saveSession(ctx, sessionKey, sessionData); err != nil {
@@ -371,6 +483,98 @@ func (s *SessionService) AppendEvent(ctx context.Context, sess session.Session,
 	return nil
 }
 
+// ClearState removes every key from a session's state while keeping its events. The deletion
+// is recorded as a content-less event whose StateDelta sets each key to nil, which the agent
+// doesn't see as part of the conversation.
+func (s *SessionService) ClearState(ctx context.Context, sess session.Session) error {
+	if sess == nil {
+		return fmt.Errorf("session cannot be nil")
+	}
+
+	// Include keys only present in storage, in case the in-memory session is stale
+	stored, err := s.loadSession(ctx, s.getSessionKey(sess.AppName(), sess.UserID(), sess.ID()))
+	if err != nil {
+		return fmt.Errorf("failed to load session for state clear: %w", err)
+	}
+
+	keys := make([]string, 0, len(stored.State))
+	for key := range stored.State {
+		keys = append(keys, key)
+	}
+	for key := range sess.State().All() {
+		keys = append(keys, key)
+	}
+	return s.DeleteStateKeys(ctx, sess, keys...)
+}
+
+// DeleteStateKeys removes keys from a session's persisted and in-memory state. The deletion
+// is recorded as a content-less event whose StateDelta sets each key to nil. Deleting a key
+// that doesn't exist is not an error, and temporary keys are ignored as they're never persisted.
+func (s *SessionService) DeleteStateKeys(ctx context.Context, sess session.Session, keys ...string) error {
+	if sess == nil {
+		return fmt.Errorf("session cannot be nil")
+	}
+
+	delta := make(map[string]any, len(keys))
+	for _, key := range keys {
+		if !isTemporaryKey(key) {
+			delta[key] = nil
+		}
+	}
+	if len(delta) == 0 {
+		return nil
+	}
+
+	event := session.NewEvent("")
+	event.Author = "user"
+	event.Actions.StateDelta = delta
+	return s.AppendEvent(ctx, sess, event)
+}
+
+// sessionHeader is SessionData with its state and events left encoded, for updates that
+// only change the header fields. Its State and Events shadow the embedded ones.
+type sessionHeader struct {
+	SessionData
+	State  json.RawMessage `json:"state,omitempty"`
+	Events json.RawMessage `json:"events,omitempty"`
+}
+
+// Touch marks a session as active by setting its UpdatedAt to now without appending an
+// event, for interactions that leave nothing to persist. The state and events are copied
+// across without being decoded, so it stays cheap for long sessions.
+func (s *SessionService) Touch(ctx context.Context, appName, userID, sessionID string) error {
+	sessionKey := s.getSessionKey(appName, userID, sessionID)
+
+	sessionLock := s.getSessionLock(sessionKey)
+	sessionLock.Lock()
+	defer sessionLock.Unlock()
+
+	data, err := s.fileProvider.Read(ctx, sessionKey)
+	if err != nil {
+		return fmt.Errorf("failed to read session for touch: %w", err)
+	}
+	var header sessionHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return fmt.Errorf("failed to unmarshal session header: %w", err)
+	}
+	header.UpdatedAt = time.Now()
+
+	if s.compactJSON {
+		data, err = json.Marshal(header)
+	} else {
+		data, err = json.MarshalIndent(header, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal session header: %w", err)
+	}
+	if err := s.fileProvider.Write(ctx, sessionKey, data); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+
+	s.log.DebugCtx(ctx, "Touched session", logger.StringField("session_key", sessionKey))
+	return nil
+}
+
 // isTemporaryKey checks if a state key is temporary (should not be persisted).
 func isTemporaryKey(key string) bool {
 	return len(key) >= len(session.KeyPrefixTemp) && key[:len(session.KeyPrefixTemp)] == session.KeyPrefixTemp
@@ -393,6 +597,23 @@ func (s *SessionService) getSessionLock(sessionKey string) *sync.Mutex {
 	return lock
 }
 
+// setOrigin records the connector and channel an indexed session was started from, to be
+// written into the session's header when it's first stored
+func (s *SessionService) setOrigin(sessionID, connector, channelID string) {
+	s.originMux.Lock()
+	defer s.originMux.Unlock()
+	s.origins[sessionID] = sessionOrigin{connector: connector, channelID: channelID}
+}
+
+// takeOrigin returns and forgets the origin recorded for a session, if any
+func (s *SessionService) takeOrigin(sessionID string) sessionOrigin {
+	s.originMux.Lock()
+	defer s.originMux.Unlock()
+	origin := s.origins[sessionID]
+	delete(s.origins, sessionID)
+	return origin
+}
+
 // getSessionKey generates a consistent key for session storage.
 func (s *SessionService) getSessionKey(appName, userID, sessionID string) string {
 	if sessionID == "" {
@@ -406,7 +627,7 @@ func (s *SessionService) loadSession(ctx context.Context, sessionKey string) (*S
 	start := time.Now()
 	data, err := s.fileProvider.Read(ctx, sessionKey)
 	if err != nil {
-		s.log.Warn("Failed to read session from storage",
+		s.log.WarnCtx(ctx, "Failed to read session from storage",
 			logger.StringField("session_key", sessionKey),
 			logger.ErrorField(err))
 		return nil, err
@@ -418,18 +639,23 @@ func (s *SessionService) loadSession(ctx context.Context, sessionKey string) (*S
 	decoder.UseNumber()
 
 	if err := decoder.Decode(&sessionData); err != nil {
-		s.log.Error("Failed to unmarshal session data",
+		s.log.ErrorCtx(ctx, "Failed to unmarshal session data",
 			logger.StringField("session_key", sessionKey),
 			logger.ErrorField(err))
 		return nil, fmt.Errorf("failed to unmarshal session data: %w", err)
 	}
 
-	// Convert json.Number values back to appropriate types in State
+	// Convert json.Number values back to appropriate types in State and event state deltas
 	if sessionData.State != nil {
 		convertJSONNumbers(sessionData.State)
 	}
+	for _, event := range sessionData.Events {
+		if event != nil && event.Actions.StateDelta != nil {
+			convertJSONNumbers(event.Actions.StateDelta)
+		}
+	}
 
-	s.log.Info("Loaded session from storage",
+	s.log.InfoCtx(ctx, "Loaded session from storage",
 		logger.StringField("session_key", sessionKey),
 		logger.IntField("events_count", len(sessionData.Events)),
 		logger.DurationField("duration", time.Since(start)))
@@ -444,22 +670,22 @@ func (s *SessionService) saveSession(ctx context.Context, sessionKey string, ses
 	// Update timestamp
 	sessionData.UpdatedAt = time.Now()
 
-	data, err := json.MarshalIndent(sessionData, "", "  ")
+	data, err := s.marshalSession(sessionData)
 	if err != nil {
-		s.log.Error("Failed to marshal session data",
+		s.log.ErrorCtx(ctx, "Failed to marshal session data",
 			logger.StringField("session_key", sessionKey),
 			logger.ErrorField(err))
 		return fmt.Errorf("failed to marshal session data: %w", err)
 	}
 
 	if err := s.fileProvider.Write(ctx, sessionKey, data); err != nil {
-		s.log.Error("Failed to write session to storage",
+		s.log.ErrorCtx(ctx, "Failed to write session to storage",
 			logger.StringField("session_key", sessionKey),
 			logger.ErrorField(err))
 		return fmt.Errorf("failed to write session file: %w", err)
 	}
 
-	s.log.Info("Saved session to storage",
+	s.log.InfoCtx(ctx, "Saved session to storage",
 		logger.StringField("session_key", sessionKey),
 		logger.IntField("events_count", len(sessionData.Events)),
 		logger.IntField("size_bytes", len(data)),
@@ -468,6 +694,31 @@ func (s *SessionService) saveSession(ctx context.Context, sessionKey string, ses
 	return nil
 }
 
+// marshalSession serializes session data as compact or indented JSON depending on configuration
+func (s *SessionService) marshalSession(sessionData *SessionData) ([]byte, error) {
+	if !s.compactJSON {
+		return json.MarshalIndent(sessionData, "", "  ")
+	}
+
+	data, err := json.Marshal(sessionData)
+	if err != nil {
+		return nil, err
+	}
+
+	// Report the saving once, measured on the first session written
+	s.sizeLogOnce.Do(func() {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, data, "", "  "); err == nil && indented.Len() > 0 {
+			s.log.Info("Writing sessions as compact JSON",
+				logger.IntField("compact_bytes", len(data)),
+				logger.IntField("indented_bytes", indented.Len()),
+				logger.IntField("saved_percent", 100-len(data)*100/indented.Len()))
+		}
+	})
+
+	return data, nil
+}
+
 // sessionDataToADKSession converts internal session data to ADK session interface.
 // Creates defensive copies of state and events to prevent external modifications.
 func (s *SessionService) sessionDataToADKSession(data *SessionData) session.Session {
@@ -584,6 +835,14 @@ func (s *sessionState) Set(key string, value any) error {
 	return nil
 }
 
+// delete removes a key from the in-memory state
+func (s *sessionState) delete(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.data, key)
+}
+
 // All returns an iterator over all key-value pairs.
 func (s *sessionState) All() iter.Seq2[string, any] {
 	return func(yield func(string, any) bool) {
@@ -638,26 +897,48 @@ func (e *sessionEvents) At(i int) *session.Event {
 	return e.events[i]
 }
 
-// convertJSONNumbers converts json.Number values in a map back to their appropriate Go types.
+// convertJSONNumbers converts json.Number values in a map, including those nested in maps
+// and slices, back to Go numbers. The conversion is deterministic and picks the narrowest
+// exact type for the number as written:
+//   - integers (no fraction or exponent) become int, or int64 if they don't fit in int
+//   - everything else, and integers too large for int64, become float64
+//
+// JSON can't tell 3.0 from 3, so a whole-number float64 is stored as an integer and
+// comes back as int.
 func convertJSONNumbers(m map[string]any) {
 	for key, value := range m {
-		switch v := value.(type) {
-		case json.Number:
-			// Try to convert to int first, then float64
-			if intVal, err := v.Int64(); err == nil {
-				// Check if it fits in a regular int
-				if intVal >= int64(int(^uint(0)>>1)*-1) && intVal <= int64(int(^uint(0)>>1)) {
-					m[key] = int(intVal)
-				} else {
-					m[key] = intVal
-				}
-			} else if floatVal, err := v.Float64(); err == nil {
-				m[key] = floatVal
+		m[key] = convertJSONValue(value)
+	}
+}
+
+// convertJSONValue converts a decoded JSON value, recursing into maps and slices
+func convertJSONValue(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		return convertJSONNumber(v)
+	case map[string]any:
+		convertJSONNumbers(v)
+	case []any:
+		for i, item := range v {
+			v[i] = convertJSONValue(item)
+		}
+	}
+	return value
+}
+
+// convertJSONNumber converts a single json.Number following the rules of convertJSONNumbers
+func convertJSONNumber(n json.Number) any {
+	if !strings.ContainsAny(n.String(), ".eE") {
+		if intVal, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+			if intVal >= math.MinInt && intVal <= math.MaxInt {
+				return int(intVal)
 			}
-			// If both conversions fail, keep as json.Number
-		case map[string]any:
-			// Recursively handle nested maps
-			convertJSONNumbers(v)
+			return intVal
 		}
 	}
+	if floatVal, err := n.Float64(); err == nil {
+		return floatVal
+	}
+	// Not representable as a Go number; keep the original
+	return n
 }
@@ -2,8 +2,14 @@ package session_manager
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
+	"path"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager"
 	"github.com/lewisedginton/general_purpose_chatbot/internal/storage_manager/mocks"
@@ -437,6 +443,225 @@ func TestSessionService_StatePreservesNumbers(t *testing.T) {
 	assert.Equal(t, 3.14, floatVal)
 }
 
+func TestSessionService_StateNumberTypesRoundTrip(t *testing.T) {
+	service := emptySessionService(t)
+	ctx := context.Background()
+
+	_, err := service.Create(ctx, &session.CreateRequest{
+		AppName:   "test-app",
+		UserID:    "user123",
+		SessionID: "types-test",
+		State: map[string]any{
+			"nested": map[string]any{
+				"int":        7,
+				"int64":      int64(math.MaxInt64),
+				"float":      2.5,
+				"exponent":   1.5e300,
+				"huge":       json.Number("18446744073709551616"),
+				"wholeFloat": 3.0,
+			},
+			"list": []any{1, 2.5, int64(-9007199254740993), map[string]any{"deep": []any{4}}},
+		},
+	})
+	require.NoError(t, err)
+
+	resp, err := service.Get(ctx, &session.GetRequest{
+		AppName:   "test-app",
+		UserID:    "user123",
+		SessionID: "types-test",
+	})
+	require.NoError(t, err)
+
+	nested, err := resp.Session.State().Get("nested")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"int":      7,
+		"int64":    int(math.MaxInt64), // fits in int on 64-bit platforms
+		"float":    2.5,
+		"exponent": 1.5e300,
+		"huge":     1.8446744073709552e19,
+		// JSON can't tell 3.0 from 3, so whole-number floats come back as int
+		"wholeFloat": 3,
+	}, nested)
+
+	list, err := resp.Session.State().Get("list")
+	require.NoError(t, err)
+	assert.Equal(t, []any{1, 2.5, -9007199254740993, map[string]any{"deep": []any{4}}}, list)
+}
+
+func TestSessionService_StateArrayNumbers(t *testing.T) {
+	service := emptySessionService(t)
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, &session.CreateRequest{
+		AppName:   "test-app",
+		UserID:    "user123",
+		SessionID: "array-test",
+		State:     map[string]any{"nums": []any{1, 2, 3}},
+	})
+	require.NoError(t, err)
+
+	event := session.NewEvent("inv-1")
+	event.Author = "agent"
+	event.Actions.StateDelta = map[string]any{"scores": []any{[]any{10, 20}, map[string]any{"best": 30}}}
+	require.NoError(t, service.AppendEvent(ctx, created.Session, event))
+
+	resp, err := service.Get(ctx, &session.GetRequest{
+		AppName:   "test-app",
+		UserID:    "user123",
+		SessionID: "array-test",
+	})
+	require.NoError(t, err)
+
+	nums, err := resp.Session.State().Get("nums")
+	require.NoError(t, err)
+	assert.Equal(t, []any{1, 2, 3}, nums)
+
+	scores, err := resp.Session.State().Get("scores")
+	require.NoError(t, err)
+	assert.Equal(t, []any{[]any{10, 20}, map[string]any{"best": 30}}, scores)
+
+	// Numbers in the stored event's state delta are converted the same way
+	delta := resp.Session.Events().At(0).Actions.StateDelta
+	assert.Equal(t, []any{[]any{10, 20}, map[string]any{"best": 30}}, delta["scores"])
+}
+
+func TestSessionService_ClearState(t *testing.T) {
+	service := NewSessionService(storage_manager.NewLocalFileProvider(t.TempDir()), testLogger())
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, &session.CreateRequest{
+		AppName:   "test-app",
+		UserID:    "user123",
+		SessionID: "clear-test",
+		State:     map[string]any{"name": "Ada"},
+	})
+	require.NoError(t, err)
+
+	event := session.NewEvent("inv-1")
+	event.Author = "agent"
+	event.Actions.StateDelta = map[string]any{"language": "fr"}
+	require.NoError(t, service.AppendEvent(ctx, created.Session, event))
+
+	require.NoError(t, service.ClearState(ctx, created.Session))
+
+	// The in-memory session is cleared straight away
+	for key := range created.Session.State().All() {
+		t.Errorf("unexpected in-memory state key %q after clear", key)
+	}
+
+	resp, err := service.Get(ctx, &session.GetRequest{
+		AppName:   "test-app",
+		UserID:    "user123",
+		SessionID: "clear-test",
+	})
+	require.NoError(t, err)
+
+	for _, key := range []string{"name", "language"} {
+		_, err := resp.Session.State().Get(key)
+		assert.Error(t, err, "key %q should be absent after reload", key)
+	}
+
+	// Events are kept, with the clear recorded as a deletion event
+	require.Equal(t, 2, resp.Session.Events().Len())
+	assert.Equal(t, map[string]any{"name": nil, "language": nil}, resp.Session.Events().At(1).Actions.StateDelta)
+
+	// Clearing empty state appends nothing
+	require.NoError(t, service.ClearState(ctx, resp.Session))
+	assert.Equal(t, 2, resp.Session.Events().Len())
+}
+
+func TestSessionService_DeleteStateKeys(t *testing.T) {
+	service := NewSessionService(storage_manager.NewLocalFileProvider(t.TempDir()), testLogger())
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, &session.CreateRequest{
+		AppName:   "test-app",
+		UserID:    "user123",
+		SessionID: "delete-test",
+		State:     map[string]any{"name": "Ada", "team": "platform", "unset": nil},
+	})
+	require.NoError(t, err)
+
+	getSession := func() session.Session {
+		t.Helper()
+		resp, err := service.Get(ctx, &session.GetRequest{
+			AppName:   "test-app",
+			UserID:    "user123",
+			SessionID: "delete-test",
+		})
+		require.NoError(t, err)
+		return resp.Session
+	}
+
+	// Nil initial values are treated as absent
+	_, err = getSession().State().Get("unset")
+	assert.Error(t, err)
+
+	before := getSession()
+
+	// Deleting an existing key, a missing key and a temporary key succeeds
+	require.NoError(t, service.DeleteStateKeys(ctx, created.Session, "name", "missing", "temp:scratch"))
+
+	_, err = created.Session.State().Get("name")
+	assert.Error(t, err, "key should be removed from the in-memory session")
+
+	after := getSession()
+	_, err = after.State().Get("name")
+	assert.Error(t, err, "deletion should be persisted")
+	team, err := after.State().Get("team")
+	require.NoError(t, err)
+	assert.Equal(t, "platform", team)
+
+	// The intent is recorded in the event, without the temporary key
+	require.Equal(t, 1, after.Events().Len())
+	assert.Equal(t, map[string]any{"name": nil, "missing": nil}, after.Events().At(0).Actions.StateDelta)
+
+	// Sessions loaded before the deletion are unaffected
+	name, err := before.State().Get("name")
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", name)
+
+	// Deleting only temporary keys appends nothing
+	require.NoError(t, service.DeleteStateKeys(ctx, after, "temp:scratch"))
+	assert.Equal(t, 1, getSession().Events().Len())
+}
+
+func TestSessionService_AppendEvent_NilDeletesKey(t *testing.T) {
+	service := NewSessionService(storage_manager.NewLocalFileProvider(t.TempDir()), testLogger())
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, &session.CreateRequest{
+		AppName:   "test-app",
+		UserID:    "user123",
+		SessionID: "nil-delta-test",
+		State:     map[string]any{"name": "Ada"},
+	})
+	require.NoError(t, err)
+
+	// A tool setting a key to nil deletes it in the same event as other changes
+	delta := map[string]any{"name": nil, "nickname": "A"}
+	event := session.NewEvent("inv-1")
+	event.Author = "agent"
+	event.Actions.StateDelta = delta
+	require.NoError(t, service.AppendEvent(ctx, created.Session, event))
+
+	// The caller's delta map is not modified
+	assert.Equal(t, map[string]any{"name": nil, "nickname": "A"}, delta)
+
+	resp, err := service.Get(ctx, &session.GetRequest{
+		AppName:   "test-app",
+		UserID:    "user123",
+		SessionID: "nil-delta-test",
+	})
+	require.NoError(t, err)
+	_, err = resp.Session.State().Get("name")
+	assert.Error(t, err)
+	nickname, err := resp.Session.State().Get("nickname")
+	require.NoError(t, err)
+	assert.Equal(t, "A", nickname)
+}
+
 func TestSessionService_MockProvider(t *testing.T) {
 	// Create mock file provider with in-memory storage
 	mockProvider := mocks.NewFileProvider(t)
@@ -486,3 +711,199 @@ func TestSessionService_MockProvider(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "mock-test-session", getResp.Session.ID())
 }
+
+func TestSessionService_CompactJSON(t *testing.T) {
+	ctx := context.Background()
+
+	// writeSession saves a session with state and events, returning its serialized bytes
+	writeSession := func(t *testing.T, provider storage_manager.FileProvider, opts ...SessionServiceOption) []byte {
+		t.Helper()
+		service := NewSessionService(provider, testLogger(), opts...)
+		created, err := service.Create(ctx, &session.CreateRequest{
+			AppName:   "test-app",
+			UserID:    "user123",
+			SessionID: "format-test",
+			State:     map[string]any{"count": 7, "nested": map[string]any{"name": "value"}},
+		})
+		require.NoError(t, err)
+		for i := 0; i < 5; i++ {
+			event := session.NewEvent(fmt.Sprintf("inv-%d", i))
+			event.Author = "user"
+			require.NoError(t, service.AppendEvent(ctx, created.Session, event))
+		}
+
+		data, err := provider.Read(ctx, "test-app/user123/format-test.json")
+		require.NoError(t, err)
+		return data
+	}
+
+	indentedProvider := storage_manager.NewLocalFileProvider(t.TempDir())
+	compactProvider := storage_manager.NewLocalFileProvider(t.TempDir())
+	indented := writeSession(t, indentedProvider)
+	compact := writeSession(t, compactProvider, WithCompactJSON())
+
+	assert.Less(t, len(compact), len(indented), "compact JSON should be smaller")
+	assert.NotContains(t, string(compact), "\n  ")
+	assert.Contains(t, string(indented), "\n  ")
+
+	// Each format reads back identically, whichever mode the reading service uses
+	for name, provider := range map[string]storage_manager.FileProvider{"indented": indentedProvider, "compact": compactProvider} {
+		for _, opts := range [][]SessionServiceOption{nil, {WithCompactJSON()}} {
+			service := NewSessionService(provider, testLogger(), opts...)
+			resp, err := service.Get(ctx, &session.GetRequest{
+				AppName:   "test-app",
+				UserID:    "user123",
+				SessionID: "format-test",
+			})
+			require.NoError(t, err, name)
+
+			count, err := resp.Session.State().Get("count")
+			require.NoError(t, err, name)
+			assert.Equal(t, 7, count, name)
+			nested, err := resp.Session.State().Get("nested")
+			require.NoError(t, err, name)
+			assert.Equal(t, map[string]any{"name": "value"}, nested, name)
+			assert.Equal(t, 5, resp.Session.Events().Len(), name)
+			assert.Equal(t, "inv-4", resp.Session.Events().At(4).InvocationID, name)
+		}
+	}
+}
+
+// slowReadProvider delays reads, records the most reads seen in flight at once and fails
+// reads of the failing path
+type slowReadProvider struct {
+	storage_manager.FileProvider
+	failing string
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (p *slowReadProvider) Read(ctx context.Context, path string) ([]byte, error) {
+	p.mu.Lock()
+	p.inFlight++
+	p.maxInFlight = max(p.maxInFlight, p.inFlight)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.inFlight--
+		p.mu.Unlock()
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	if path == p.failing {
+		return nil, fmt.Errorf("read failed")
+	}
+	return p.FileProvider.Read(ctx, path)
+}
+
+func TestSessionService_ListConcurrency(t *testing.T) {
+	ctx := context.Background()
+	local := storage_manager.NewLocalFileProvider(t.TempDir())
+	writer := NewSessionService(local, testLogger())
+	for i := 0; i < 20; i++ {
+		_, err := writer.Create(ctx, &session.CreateRequest{
+			AppName:   "app",
+			UserID:    "user1",
+			SessionID: fmt.Sprintf("session%02d", i),
+		})
+		require.NoError(t, err)
+	}
+
+	files, err := local.List(ctx, "app/user1/")
+	require.NoError(t, err)
+	require.Len(t, files, 20)
+
+	for _, workers := range []int{1, 4, 50} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			provider := &slowReadProvider{FileProvider: local, failing: files[7]}
+			service := NewSessionService(provider, testLogger(), WithListConcurrency(workers))
+
+			resp, err := service.List(ctx, &session.ListRequest{AppName: "app", UserID: "user1"})
+			require.NoError(t, err, "a failed load shouldn't fail the list")
+
+			// Every other session is returned, in listing order
+			var want, got []string
+			for i, file := range files {
+				if i != 7 {
+					want = append(want, strings.TrimSuffix(path.Base(file), ".json"))
+				}
+			}
+			for _, sess := range resp.Sessions {
+				got = append(got, sess.ID())
+			}
+			assert.Equal(t, want, got)
+			assert.LessOrEqual(t, provider.maxInFlight, workers)
+			if workers > 1 {
+				assert.Greater(t, provider.maxInFlight, 1, "loads should overlap")
+			}
+		})
+	}
+}
+
+func TestSessionService_ListCancelled(t *testing.T) {
+	local := storage_manager.NewLocalFileProvider(t.TempDir())
+	service := NewSessionService(local, testLogger())
+	_, err := service.Create(context.Background(), &session.CreateRequest{AppName: "app", UserID: "user1", SessionID: "s1"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = service.List(ctx, &session.ListRequest{AppName: "app"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSessionService_LogsCorrelationIDFromContext(t *testing.T) {
+	var buf strings.Builder
+	log := logger.NewLogger(logger.Config{Level: logger.InfoLevel, Format: "json", Output: &buf})
+	s := NewSessionService(storage_manager.NewLocalFileProvider(t.TempDir()), log)
+	ctx := logger.WithCorrelationIDContext(context.Background(), "abc123")
+
+	_, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	require.NoError(t, err)
+	_, err = s.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.NotEmpty(t, lines)
+	for _, line := range lines {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		assert.Equal(t, "abc123", entry["correlation_id"], "log %q should carry the context's correlation ID", entry["msg"])
+	}
+	assert.Contains(t, buf.String(), "Loaded session from storage")
+}
+
+func TestSessionService_Touch(t *testing.T) {
+	service := NewSessionService(storage_manager.NewLocalFileProvider(t.TempDir()), testLogger())
+	ctx := context.Background()
+	getReq := &session.GetRequest{AppName: "test-app", UserID: "user123", SessionID: "touch-test"}
+
+	created, err := service.Create(ctx, &session.CreateRequest{
+		AppName:   "test-app",
+		UserID:    "user123",
+		SessionID: "touch-test",
+		State:     map[string]any{"count": 7},
+	})
+	require.NoError(t, err)
+	event := session.NewEvent("inv-1")
+	event.Author = "user"
+	require.NoError(t, service.AppendEvent(ctx, created.Session, event))
+
+	before, err := service.Get(ctx, getReq)
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, service.Touch(ctx, "test-app", "user123", "touch-test"))
+
+	after, err := service.Get(ctx, getReq)
+	require.NoError(t, err)
+	assert.True(t, after.Session.LastUpdateTime().After(before.Session.LastUpdateTime()), "Touch should advance UpdatedAt")
+	assert.Equal(t, 1, after.Session.Events().Len(), "Touch should not append an event")
+	count, err := after.Session.State().Get("count")
+	require.NoError(t, err)
+	assert.Equal(t, 7, count)
+
+	assert.Error(t, service.Touch(ctx, "test-app", "user123", "missing"))
+}
@@ -15,13 +15,18 @@ type SessionInfo struct {
 	ChannelID  string    `json:"channel_id"` // Channel/Chat ID
 	CreatedAt  time.Time `json:"created_at"`
 	LastActive time.Time `json:"last_active"`
+	// Platform user IDs of everyone who took part, for sessions shared by a channel or thread
+	Participants []string `json:"participants,omitempty"`
 }
 
 // Config holds configuration for the session manager
 type Config struct {
-	MetadataFile string                       // Path to metadata JSON file (relative to FileProvider root)
-	FileProvider storage_manager.FileProvider // File provider for persistence (used for both metadata and session data)
-	Logger       logger.Logger
+	MetadataFile    string                       // Path to metadata JSON file (relative to FileProvider root)
+	FileProvider    storage_manager.FileProvider // File provider for persistence (used for both metadata and session data)
+	Logger          logger.Logger
+	CompactJSON     bool      // Write session data as compact instead of indented JSON
+	ListConcurrency int       // Session files loaded at once when listing; 0 uses DefaultListConcurrency
+	Retention       Retention // Optional: limits on the events kept per session
 }
 
 // metadataStore represents the structure of the metadata JSON file
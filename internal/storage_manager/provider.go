@@ -9,6 +9,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 )
 
 // FileProvider defines the interface for file storage operations.
@@ -30,6 +33,81 @@ type FileProvider interface {
 	List(ctx context.Context, prefix string) ([]string, error)
 }
 
+// BatchWriter is implemented by providers that can write many files more efficiently than
+// one Write call per file (e.g. S3 uploads them in parallel). Use WriteBatch rather than
+// calling it directly so providers without batch support still work.
+type BatchWriter interface {
+	// WriteBatch writes every file in files, keyed by path
+	WriteBatch(ctx context.Context, files map[string][]byte) error
+}
+
+// s3BatchConcurrency caps how many objects an S3 batch write uploads at once
+const s3BatchConcurrency = 8
+
+// WriteBatch writes files through the provider's BatchWriter if it implements one,
+// otherwise it falls back to writing each file in path order, stopping at the first error.
+func WriteBatch(ctx context.Context, provider FileProvider, files map[string][]byte) error {
+	if batchWriter, ok := provider.(BatchWriter); ok {
+		return batchWriter.WriteBatch(ctx, files)
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if err := provider.Write(ctx, path, files[path]); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// ListPrefix returns every file under prefix, sorted. The prefix is treated as a directory
+// so "app/user1" doesn't also match "app/user10", and an empty prefix is rejected.
+func ListPrefix(ctx context.Context, provider FileProvider, prefix string) ([]string, error) {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return nil, fmt.Errorf("prefix cannot be empty")
+	}
+	prefix += "/"
+
+	files, err := provider.List(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+	}
+
+	// Local listings walk the directory; S3 listings match any key with the prefix
+	result := make([]string, 0, len(files))
+	for _, file := range files {
+		if file = filepath.ToSlash(file); strings.HasPrefix(file, prefix) {
+			result = append(result, file)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// DeletePrefix deletes every file under prefix, as listed by ListPrefix. It returns how
+// many files were deleted, stopping at the first error.
+func DeletePrefix(ctx context.Context, provider FileProvider, prefix string) (int, error) {
+	files, err := ListPrefix(ctx, provider, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, file := range files {
+		if err := provider.Delete(ctx, file); err != nil {
+			return deleted, fmt.Errorf("failed to delete %s: %w", file, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
 // LocalFileProvider implements FileProvider for local filesystem.
 type LocalFileProvider struct {
 	baseDir string
@@ -47,7 +125,7 @@ func (p *LocalFileProvider) Read(ctx context.Context, path string) ([]byte, erro
 	return os.ReadFile(filepath.Join(p.baseDir, path)) //nolint:gosec // G304: Path is constructed from trusted baseDir
 }
 
-// Write writes data to a local file.
+// Write writes data to a local file, replacing any existing file atomically.
 func (p *LocalFileProvider) Write(ctx context.Context, path string, data []byte) error {
 	fullPath := filepath.Join(p.baseDir, path)
 
@@ -57,7 +135,58 @@ func (p *LocalFileProvider) Write(ctx context.Context, path string, data []byte)
 		return fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
-	return os.WriteFile(fullPath, data, 0o600)
+	return writeFileAtomic(fullPath, data)
+}
+
+// tempFileInfix marks the temporary files Write creates next to the file being written
+const tempFileInfix = ".tmp-"
+
+// writeFileAtomic writes data to a temporary file in the same directory and renames it over
+// path, so readers see either the old contents or the new ones, never a partial write.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+tempFileInfix+"*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	// Flush the data before the rename, so a crash can't leave the new name on an empty file
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename temporary file: %w", err)
+	}
+	syncDir(filepath.Dir(path))
+	return nil
+}
+
+// syncDir flushes a directory so a rename in it survives a crash. It's best effort, as some
+// platforms can't sync directories.
+func syncDir(dir string) {
+	d, err := os.Open(dir) //nolint:gosec // G304: Path is constructed from trusted baseDir
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
+
+// isTempFile reports whether name is a temporary file left by Write
+func isTempFile(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.Contains(name, tempFileInfix)
 }
 
 // Exists checks if a file exists on the local filesystem.
@@ -96,7 +225,8 @@ func (p *LocalFileProvider) List(ctx context.Context, prefix string) ([]string,
 			return err
 		}
 
-		if !info.IsDir() {
+		// Skip temporary files from writes still in progress
+		if !info.IsDir() && !isTempFile(info.Name()) {
 			rel, err := filepath.Rel(p.baseDir, path)
 			if err == nil {
 				result = append(result, rel)
@@ -141,6 +271,34 @@ func (p *S3FileProvider) Write(ctx context.Context, path string, data []byte) er
 	return p.s3Client.PutObject(ctx, p.bucket, key, data)
 }
 
+// WriteBatch uploads files to S3 in parallel, returning the errors of any failed uploads.
+func (p *S3FileProvider) WriteBatch(ctx context.Context, files map[string][]byte) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	sem := make(chan struct{}, s3BatchConcurrency)
+
+	for path, data := range files {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(path string, data []byte) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := p.Write(ctx, path, data); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("failed to write %s: %w", path, err))
+				mu.Unlock()
+			}
+		}(path, data)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
 // Exists checks if a file exists in S3.
 // Returns (false, nil) only for "not found" errors.
 // Returns (false, error) for real errors (network, permissions, etc.).
@@ -218,6 +376,15 @@ func (p *PrefixedFileProvider) Write(ctx context.Context, path string, data []by
 	return p.provider.Write(ctx, p.prefixPath(path), data)
 }
 
+// WriteBatch writes files with the prefix applied, using the wrapped provider's batch support if any.
+func (p *PrefixedFileProvider) WriteBatch(ctx context.Context, files map[string][]byte) error {
+	prefixed := make(map[string][]byte, len(files))
+	for path, data := range files {
+		prefixed[p.prefixPath(path)] = data
+	}
+	return WriteBatch(ctx, p.provider, prefixed)
+}
+
 // Exists checks if a file exists with the prefix applied.
 func (p *PrefixedFileProvider) Exists(ctx context.Context, path string) (bool, error) {
 	return p.provider.Exists(ctx, p.prefixPath(path))
@@ -0,0 +1,222 @@
+package storage_manager
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3Client stores objects in memory and fails puts for keys in failKeys
+type fakeS3Client struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	puts     int
+	failKeys map[string]bool
+}
+
+func newFakeS3Client() *fakeS3Client {
+	return &fakeS3Client{objects: make(map[string][]byte), failKeys: make(map[string]bool)}
+}
+
+func (c *fakeS3Client) GetObject(_ context.Context, _, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, ok := c.objects[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+func (c *fakeS3Client) PutObject(_ context.Context, _, key string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.puts++
+	if c.failKeys[key] {
+		return errors.New("access denied")
+	}
+	c.objects[key] = data
+	return nil
+}
+
+func (c *fakeS3Client) HeadObject(_ context.Context, _, key string) error {
+	_, err := c.GetObject(context.Background(), "", key)
+	return err
+}
+
+func (c *fakeS3Client) DeleteObject(_ context.Context, _, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.objects, key)
+	return nil
+}
+
+func (c *fakeS3Client) ListObjects(_ context.Context, _, prefix string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var keys []string
+	for key := range c.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// batchRecorder is a FileProvider that records whether it was written in a batch
+type batchRecorder struct {
+	FileProvider
+	batches [][]string
+}
+
+func (r *batchRecorder) WriteBatch(_ context.Context, files map[string][]byte) error {
+	var paths []string
+	for path := range files {
+		paths = append(paths, path)
+	}
+	r.batches = append(r.batches, paths)
+	return nil
+}
+
+func testBatch() map[string][]byte {
+	return map[string][]byte{
+		"sessions/a.json":        []byte(`{"id":"a"}`),
+		"sessions/b.json":        []byte(`{"id":"b"}`),
+		"sessions/nested/c.json": []byte(`{"id":"c"}`),
+	}
+}
+
+func TestWriteBatch_FallsBackToWrite(t *testing.T) {
+	ctx := context.Background()
+	provider := NewLocalFileProvider(t.TempDir())
+
+	require.NoError(t, WriteBatch(ctx, provider, testBatch()))
+
+	for path, want := range testBatch() {
+		got, err := provider.Read(ctx, path)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestS3FileProvider_WriteBatch(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeS3Client()
+	provider := NewS3FileProvider("bucket", "prefix", client)
+
+	require.NoError(t, WriteBatch(ctx, provider, testBatch()))
+
+	assert.Equal(t, 3, client.puts)
+	for path, want := range testBatch() {
+		assert.Equal(t, want, client.objects["prefix/"+path])
+	}
+}
+
+func TestS3FileProvider_WriteBatchReportsFailures(t *testing.T) {
+	client := newFakeS3Client()
+	client.failKeys["prefix/sessions/b.json"] = true
+	provider := NewS3FileProvider("bucket", "prefix", client)
+
+	err := provider.WriteBatch(context.Background(), testBatch())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sessions/b.json")
+	assert.Len(t, client.objects, 2, "other files in the batch should still be written")
+}
+
+func TestPrefixedFileProvider_WriteBatchUsesWrappedBatchWriter(t *testing.T) {
+	recorder := &batchRecorder{}
+	provider := NewPrefixedFileProvider(recorder, "artifacts")
+
+	require.NoError(t, WriteBatch(context.Background(), provider, testBatch()))
+
+	require.Len(t, recorder.batches, 1, "the batch should reach the wrapped provider in one call")
+	assert.ElementsMatch(t, []string{
+		"artifacts/sessions/a.json", "artifacts/sessions/b.json", "artifacts/sessions/nested/c.json",
+	}, recorder.batches[0])
+}
+
+func TestDeletePrefix(t *testing.T) {
+	ctx := context.Background()
+	for name, provider := range map[string]FileProvider{
+		"local": NewLocalFileProvider(t.TempDir()),
+		"s3":    NewS3FileProvider("bucket", "root", newFakeS3Client()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, file := range []string{"app/user1/a.json", "app/user1/sub/b.json", "app/user10/c.json", "app/user2/d.json"} {
+				require.NoError(t, provider.Write(ctx, file, []byte("{}")))
+			}
+
+			deleted, err := DeletePrefix(ctx, provider, "app/user1")
+			require.NoError(t, err)
+			assert.Equal(t, 2, deleted)
+
+			for file, want := range map[string]bool{"app/user1/a.json": false, "app/user1/sub/b.json": false, "app/user10/c.json": true, "app/user2/d.json": true} {
+				exists, err := provider.Exists(ctx, file)
+				require.NoError(t, err)
+				assert.Equal(t, want, exists, file)
+			}
+
+			_, err = DeletePrefix(ctx, provider, "/")
+			assert.Error(t, err, "an empty prefix should be rejected")
+		})
+	}
+}
+
+func TestLocalFileProvider_WriteReplacesAtomically(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	provider := NewLocalFileProvider(dir)
+
+	require.NoError(t, provider.Write(ctx, "data/metadata.json", []byte(`{"version":1}`)))
+	require.NoError(t, provider.Write(ctx, "data/metadata.json", []byte(`{"version":2}`)))
+
+	got, err := provider.Read(ctx, "data/metadata.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"version":2}`, string(got))
+
+	entries, err := os.ReadDir(filepath.Join(dir, "data"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary files should be left behind")
+	assert.Equal(t, "metadata.json", entries[0].Name())
+}
+
+func TestLocalFileProvider_ListSkipsTemporaryFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	provider := NewLocalFileProvider(dir)
+
+	require.NoError(t, provider.Write(ctx, "data/a.json", []byte("{}")))
+	// A write in progress
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data", ".b.json"+tempFileInfix+"123"), []byte("{"), 0o600))
+
+	files, err := provider.List(ctx, "data")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("data", "a.json")}, files)
+}
+
+func TestLocalFileProvider_CrashedWriteKeepsPreviousContents(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	provider := NewLocalFileProvider(dir)
+	require.NoError(t, provider.Write(ctx, "metadata.json", []byte(`{"sessions":{}}`)))
+
+	// A writer that crashed partway leaves only its temporary file
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".metadata.json"+tempFileInfix+"123"), []byte(`{"sess`), 0o600))
+
+	got, err := provider.Read(ctx, "metadata.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"sessions":{}}`, string(got))
+
+	require.NoError(t, provider.Write(ctx, "metadata.json", []byte(`{"sessions":null}`)))
+	got, err = provider.Read(ctx, "metadata.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"sessions":null}`, string(got))
+}
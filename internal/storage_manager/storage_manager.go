@@ -1,6 +1,7 @@
 package storage_manager //nolint:revive // var-naming: using underscores for domain clarity
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -111,6 +112,12 @@ func (m *StorageManager) GetProvider(namespace string) FileProvider {
 	return NewPrefixedFileProvider(m.provider, namespace)
 }
 
+// DeletePrefix deletes every file under prefix in the root storage, where the first path
+// element is the namespace, e.g. "sessions/chatbot/U123". It returns how many files were deleted.
+func (m *StorageManager) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	return DeletePrefix(ctx, m.provider, prefix)
+}
+
 // GetRootProvider returns the root FileProvider without any prefix.
 // Use this with caution as it provides access to all storage.
 func (m *StorageManager) GetRootProvider() FileProvider {
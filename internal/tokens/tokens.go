@@ -0,0 +1,114 @@
+// Package tokens estimates how many tokens a model counts for a piece of text, for
+// features that work to a token budget (history truncation, inbound message limits).
+// Estimates are approximate and dependency-free: OpenAI models get a BPE-style estimate
+// modelled on tiktoken's pre-tokenizer, other models a characters-per-token heuristic.
+package tokens
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CharsPerToken is the approximate number of bytes per token used by the heuristic.
+// A low value over-estimates, which is the safe direction when staying under a limit.
+const CharsPerToken = 4
+
+// openAIPrefixes are the model name prefixes that get the BPE-style estimate
+var openAIPrefixes = []string{"gpt-", "o1", "o3", "o4", "chatgpt-", "text-embedding-"}
+
+// Count estimates the number of tokens model counts for text. Unknown or empty model
+// names use the heuristic.
+func Count(model, text string) int {
+	model = strings.ToLower(model)
+	for _, prefix := range openAIPrefixes {
+		if strings.HasPrefix(model, prefix) {
+			return estimateBPE(text)
+		}
+	}
+	return Heuristic(text)
+}
+
+// Heuristic estimates tokens as one per CharsPerToken bytes, rounded up
+func Heuristic(text string) int {
+	return (len(text) + CharsPerToken - 1) / CharsPerToken
+}
+
+// estimateBPE approximates a tiktoken-style byte-pair encoding. Text is split the way the
+// cl100k/o200k pre-tokenizers split it (words with their leading space, runs of up to three
+// digits, punctuation and whitespace runs) and each piece is costed by its length: common
+// short words are a single token and longer or non-Latin ones break into several.
+func estimateBPE(text string) int {
+	count := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case unicode.IsLetter(r):
+			end := scan(text, i, unicode.IsLetter)
+			count += wordTokens(text[i:end])
+			i = end
+
+		case unicode.IsNumber(r):
+			end := scan(text, i, unicode.IsNumber)
+			count += (utf8.RuneCountInString(text[i:end]) + 2) / 3
+			i = end
+
+		case r == ' ' && i+size < len(text) && startsWithLetter(text[i+size:]):
+			// A single space is merged into the word that follows it
+			i += size
+
+		case unicode.IsSpace(r):
+			count++
+			i = scan(text, i, unicode.IsSpace)
+
+		default:
+			end := scan(text, i, isPunct)
+			count += (utf8.RuneCountInString(text[i:end]) + 2) / 3
+			i = end
+		}
+	}
+	return count
+}
+
+// wordTokens costs a run of letters
+func wordTokens(word string) int {
+	runes := utf8.RuneCountInString(word)
+	switch {
+	case len(word) == runes:
+		// ASCII: most words up to six letters are one token
+		return (runes + 5) / 6
+	case isIdeographic(word):
+		// CJK text is roughly a token per character
+		return runes
+	default:
+		// Accented Latin, Cyrillic, Greek etc. split into more pieces than ASCII
+		return (runes + 2) / 3
+	}
+}
+
+// isIdeographic reports whether word starts with a Han, Hiragana, Katakana or Hangul character
+func isIdeographic(word string) bool {
+	r, _ := utf8.DecodeRuneInString(word)
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+func isPunct(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r) && !unicode.IsSpace(r)
+}
+
+func startsWithLetter(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsLetter(r)
+}
+
+// scan returns the index just past the run of runes matching fn that starts at i
+func scan(text string, i int, fn func(rune) bool) int {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !fn(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
@@ -0,0 +1,69 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+)
+
+// withinTolerance reports whether got is within 25% (and at least one token) of want
+func withinTolerance(got, want int) bool {
+	tolerance := max(want/4, 1)
+	return got >= want-tolerance && got <= want+tolerance
+}
+
+func TestCount_OpenAI(t *testing.T) {
+	// Token counts from tiktoken's cl100k_base encoding
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hello world", 2},
+		{"Hello, world!", 4},
+		{"The quick brown fox jumps over the lazy dog.", 10},
+		{"tiktoken is great!", 6},
+		{"1234567890", 4},
+		{strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20), 200},
+	}
+
+	for _, tt := range tests {
+		got := Count("gpt-4o", tt.text)
+		if !withinTolerance(got, tt.want) {
+			t.Errorf("Count(gpt-4o, %.40q) = %d, want about %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCount_Heuristic(t *testing.T) {
+	tests := []struct {
+		model string
+		text  string
+		want  int
+	}{
+		{"claude-sonnet-4-5-20250929", "", 0},
+		{"claude-sonnet-4-5-20250929", "hi", 1},
+		{"claude-sonnet-4-5-20250929", "abcd", 1},
+		{"gemini-2.5-flash", "abcde", 2},
+		{"", strings.Repeat("a", 100), 25},
+	}
+
+	for _, tt := range tests {
+		if got := Count(tt.model, tt.text); got != tt.want {
+			t.Errorf("Count(%q, %q) = %d, want %d", tt.model, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCount_NonLatinCostsMore(t *testing.T) {
+	// Non-Latin scripts take more tokens per character than English
+	english := Count("gpt-4o", "good morning")
+	russian := Count("gpt-4o", "доброе утро")
+	chinese := Count("gpt-4o", "早上好朋友们")
+
+	if russian <= english {
+		t.Errorf("Russian estimate %d should exceed English estimate %d", russian, english)
+	}
+	if chinese != 6 {
+		t.Errorf("Chinese estimate = %d, want about one token per character", chinese)
+	}
+}
@@ -2,37 +2,79 @@
 package agent_info //nolint:revive // var-naming: using underscores for domain clarity
 
 import (
+	"runtime/debug"
+	"sort"
+	"time"
+
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
 )
 
+// adkModulePath is the module path used to report the ADK version from build info
+const adkModulePath = "google.golang.org/adk"
+
 // Args represents the arguments for the agent info tool (no args needed)
 type Args struct{}
 
 // Result represents the result of the agent info tool
 type Result struct {
-	AgentName    string   `json:"agent_name"`
-	Model        string   `json:"model"`
-	Platform     string   `json:"platform"`
-	Description  string   `json:"description"`
-	Capabilities []string `json:"capabilities"`
-	Status       string   `json:"status"`
-	Framework    string   `json:"framework"`
+	AgentName      string          `json:"agent_name"`
+	DisplayName    string          `json:"display_name,omitempty"`
+	Model          string          `json:"model"`
+	Platform       string          `json:"platform"`
+	Description    string          `json:"description"`
+	Capabilities   []string        `json:"capabilities"`
+	Tools          []string        `json:"tools"`
+	MCPServers     []MCPServerInfo `json:"mcp_servers,omitempty"`
+	SessionBackend string          `json:"session_backend,omitempty"`
+	Uptime         string          `json:"uptime,omitempty"`
+	Status         string          `json:"status"`
+	Framework      string          `json:"framework"`
+}
+
+// MCPServerInfo describes a connected MCP server and the tools it exposes
+type MCPServerInfo struct {
+	Name      string `json:"name"`
+	ToolCount int    `json:"tool_count"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Profile describes how one agent presents itself, e.g. the agent serving a connector
+type Profile struct {
+	DisplayName string // Name shown to users; empty if none is configured
+	Platform    string
+	Description string
 }
 
-// Config holds configuration for creating the agent info tool
+// Config holds configuration for creating the agent info tool.
+// Only names are reported; no credentials are ever read from the components.
 type Config struct {
 	AgentName   string
 	Platform    string
 	Description string
 	Model       model.LLM
+
+	// Profiles override the name, platform and description above for the agent with the
+	// given name, so one tool reports each connector's agent as it's presented to users
+	Profiles map[string]Profile
+
+	// ToolNames returns the names of the registered tools at call time
+	ToolNames func() []string
+	// Toolsets are queried at call time for the tools each MCP server exposes
+	Toolsets []tool.Toolset
+	// SessionBackend names the storage backend used for sessions (e.g. "local", "s3")
+	SessionBackend string
+	// StartTime is when the server started, used to report uptime
+	StartTime time.Time
 }
 
 // createHandler creates a platform-specific agent info handler
 func createHandler(config Config) func(tool.Context, Args) (Result, error) {
+	framework := frameworkVersion()
+
 	return func(ctx tool.Context, args Args) (Result, error) {
-		return Result{
+		result := Result{
 			AgentName:   config.AgentName,
 			Platform:    config.Platform,
 			Model:       config.Model.Name(),
@@ -43,18 +85,63 @@ func createHandler(config Config) func(tool.Context, Args) (Result, error) {
 				"Technical discussions",
 				"Creative writing assistance",
 				"Problem solving and reasoning",
-				"HTTP requests to external APIs and services",
 			},
-			Status:    "operational",
-			Framework: "Google ADK Go v0.3.0",
-		}, nil
+			SessionBackend: config.SessionBackend,
+			Status:         "operational",
+			Framework:      framework,
+		}
+
+		// Prefer the name of the agent actually handling this call
+		if name := ctx.AgentName(); name != "" {
+			result.AgentName = name
+			if profile, ok := config.Profiles[name]; ok {
+				result.DisplayName = profile.DisplayName
+				result.Platform = profile.Platform
+				result.Description = profile.Description
+			}
+		}
+
+		if config.ToolNames != nil {
+			result.Tools = config.ToolNames()
+			sort.Strings(result.Tools)
+		}
+
+		for _, toolset := range config.Toolsets {
+			info := MCPServerInfo{Name: toolset.Name()}
+			tools, err := toolset.Tools(ctx)
+			if err != nil {
+				info.Error = "unavailable"
+			}
+			info.ToolCount = len(tools)
+			result.MCPServers = append(result.MCPServers, info)
+		}
+
+		if !config.StartTime.IsZero() {
+			result.Uptime = time.Since(config.StartTime).Truncate(time.Second).String()
+		}
+
+		return result, nil
+	}
+}
+
+// frameworkVersion reports the ADK version the binary was built with
+func frameworkVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == adkModulePath {
+				return "Google ADK Go " + dep.Version
+			}
+		}
 	}
+	return "Google ADK Go"
 }
 
 // New creates a new agent info tool
 func New(config Config) (tool.Tool, error) {
 	return functiontool.New(functiontool.Config{
-		Name:        "get_agent_info",
-		Description: "Get information about the current agent and its capabilities",
+		Name: "get_agent_info",
+		Description: "Get accurate runtime information about the current agent: its model, " +
+			"available tools, connected MCP servers, session backend and uptime. " +
+			"Use this to answer questions about what you can do.",
 	}, createHandler(config))
 }
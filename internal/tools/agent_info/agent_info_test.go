@@ -0,0 +1,150 @@
+package agent_info //nolint:revive // var-naming: using underscores for domain clarity
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// fakeModel is a model.LLM that only reports its name
+type fakeModel struct {
+	name string
+}
+
+func (m fakeModel) Name() string { return m.name }
+
+func (m fakeModel) GenerateContent(context.Context, *model.LLMRequest, bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(func(*model.LLMResponse, error) bool) {}
+}
+
+// fakeToolset reports a fixed number of tools or an error
+type fakeToolset struct {
+	name  string
+	tools []tool.Tool
+	err   error
+}
+
+func (f fakeToolset) Name() string { return f.name }
+
+func (f fakeToolset) Tools(agent.ReadonlyContext) ([]tool.Tool, error) { return f.tools, f.err }
+
+// fakeToolContext provides the agent name the handler reads
+type fakeToolContext struct {
+	tool.Context
+	agentName string
+}
+
+func (c fakeToolContext) AgentName() string { return c.agentName }
+
+func TestHandler_ReportsRuntimeInfo(t *testing.T) {
+	handler := createHandler(Config{
+		AgentName:   "chat_assistant",
+		Platform:    "Multi-Platform",
+		Description: "test agent",
+		Model:       fakeModel{name: "claude-test"},
+		ToolNames: func() []string {
+			return []string{"web_search", "get_agent_info", "http_request"}
+		},
+		Toolsets: []tool.Toolset{
+			fakeToolset{name: "mcp_github", tools: make([]tool.Tool, 3)},
+			fakeToolset{name: "mcp_broken", err: errors.New("connection refused: token=secret")},
+		},
+		SessionBackend: "s3",
+		StartTime:      time.Now().Add(-time.Hour),
+	})
+
+	result, err := handler(fakeToolContext{agentName: "slack_assistant"}, Args{})
+	if err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+
+	if result.Model != "claude-test" {
+		t.Errorf("Model = %q, want %q", result.Model, "claude-test")
+	}
+	if result.AgentName != "slack_assistant" {
+		t.Errorf("AgentName = %q, want the calling agent's name", result.AgentName)
+	}
+	wantTools := []string{"get_agent_info", "http_request", "web_search"}
+	if strings.Join(result.Tools, ",") != strings.Join(wantTools, ",") {
+		t.Errorf("Tools = %v, want %v", result.Tools, wantTools)
+	}
+	if result.SessionBackend != "s3" {
+		t.Errorf("SessionBackend = %q, want %q", result.SessionBackend, "s3")
+	}
+	if result.Uptime == "" {
+		t.Error("expected uptime to be reported")
+	}
+
+	if len(result.MCPServers) != 2 {
+		t.Fatalf("got %d MCP servers, want 2", len(result.MCPServers))
+	}
+	if got := result.MCPServers[0]; got.Name != "mcp_github" || got.ToolCount != 3 || got.Error != "" {
+		t.Errorf("MCPServers[0] = %+v, want mcp_github with 3 tools", got)
+	}
+	if got := result.MCPServers[1]; got.ToolCount != 0 || got.Error == "" || strings.Contains(got.Error, "secret") {
+		t.Errorf("MCPServers[1] = %+v, want unavailable without leaking error details", got)
+	}
+}
+
+func TestHandler_FallsBackToConfiguredAgentName(t *testing.T) {
+	handler := createHandler(Config{
+		AgentName: "chat_assistant",
+		Model:     fakeModel{name: "gpt-test"},
+	})
+
+	result, err := handler(fakeToolContext{}, Args{})
+	if err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+	if result.AgentName != "chat_assistant" {
+		t.Errorf("AgentName = %q, want %q", result.AgentName, "chat_assistant")
+	}
+	if result.Uptime != "" || len(result.MCPServers) != 0 {
+		t.Errorf("expected no uptime or MCP servers, got %+v", result)
+	}
+}
+
+func TestNew_CreatesTool(t *testing.T) {
+	agentInfoTool, err := New(Config{Model: fakeModel{name: "gemini-test"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if agentInfoTool.Name() != "get_agent_info" {
+		t.Errorf("Name() = %q, want %q", agentInfoTool.Name(), "get_agent_info")
+	}
+}
+
+func TestHandler_ReportsCallingAgentProfile(t *testing.T) {
+	handler := createHandler(Config{
+		AgentName: "chat_assistant",
+		Platform:  "Multi-Platform",
+		Model:     fakeModel{name: "gpt-test"},
+		Profiles: map[string]Profile{
+			"slack_assistant":    {DisplayName: "Support Bot", Platform: "Slack", Description: "Answers support questions"},
+			"telegram_assistant": {DisplayName: "Helper", Platform: "Telegram"},
+		},
+	})
+
+	result, err := handler(fakeToolContext{agentName: "slack_assistant"}, Args{})
+	if err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+	if result.DisplayName != "Support Bot" || result.Platform != "Slack" || result.Description != "Answers support questions" {
+		t.Errorf("result = %+v, want the Slack agent's profile", result)
+	}
+
+	result, err = handler(fakeToolContext{agentName: "other_agent"}, Args{})
+	if err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+	if result.DisplayName != "" || result.Platform != "Multi-Platform" {
+		t.Errorf("result = %+v, want the defaults for an agent without a profile", result)
+	}
+}
@@ -0,0 +1,228 @@
+// Package command provides an allowlisted command execution tool for the chatbot.
+//
+// Commands are executed directly without a shell, so arguments are never
+// subject to expansion, globbing, piping or redirection.
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// Default limits for command execution
+const (
+	DefaultTimeout        = 10 * time.Second
+	DefaultMaxOutputBytes = 64 * 1024
+	MaxArgs               = 64
+	MaxArgLength          = 4096
+)
+
+// Config holds configuration for creating the command tool
+type Config struct {
+	// Enabled must be explicitly set; the tool refuses to construct otherwise
+	Enabled bool
+	// AllowedCommands lists the command names (resolved via PATH) or absolute paths that may be run
+	AllowedCommands []string
+	// Timeout bounds each command's run time
+	Timeout time.Duration
+	// MaxOutputBytes caps the captured stdout and stderr, each
+	MaxOutputBytes int
+	// WorkingDir is the directory commands run in (defaults to the process working directory)
+	WorkingDir string
+}
+
+// Args represents the arguments for the command tool
+type Args struct {
+	Command string   `json:"command" jsonschema:"Name of an allowlisted command to run"`
+	Args    []string `json:"args,omitempty" jsonschema:"Arguments passed directly to the command (no shell expansion)"`
+}
+
+// Result represents the result of the command tool
+type Result struct {
+	ExitCode  int    `json:"exit_code"`
+	Stdout    string `json:"stdout"`
+	Stderr    string `json:"stderr,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Duration  string `json:"duration"`
+}
+
+// commandRunner executes allowlisted commands
+type commandRunner struct {
+	allowed        map[string]string // command name -> resolved binary path
+	names          []string          // allowlisted names in configured order
+	timeout        time.Duration
+	maxOutputBytes int
+	workingDir     string
+}
+
+// newRunner validates the config and resolves each allowlisted command to a binary path
+func newRunner(cfg Config) (*commandRunner, error) {
+	if !cfg.Enabled {
+		return nil, fmt.Errorf("command tool is disabled")
+	}
+	if len(cfg.AllowedCommands) == 0 {
+		return nil, fmt.Errorf("command tool requires a non-empty allowlist")
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.MaxOutputBytes <= 0 {
+		cfg.MaxOutputBytes = DefaultMaxOutputBytes
+	}
+
+	allowed := make(map[string]string, len(cfg.AllowedCommands))
+	var names []string
+	for _, name := range cfg.AllowedCommands {
+		name = strings.TrimSpace(name)
+		if _, seen := allowed[name]; name == "" || seen {
+			continue
+		}
+		if strings.ContainsRune(name, filepath.Separator) && !filepath.IsAbs(name) {
+			return nil, fmt.Errorf("allowlisted command %q must be a bare name or an absolute path", name)
+		}
+		path, err := exec.LookPath(name)
+		if err != nil {
+			return nil, fmt.Errorf("allowlisted command %q not found: %w", name, err)
+		}
+		allowed[name] = path
+		names = append(names, name)
+	}
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("command tool requires a non-empty allowlist")
+	}
+
+	return &commandRunner{
+		allowed:        allowed,
+		names:          names,
+		timeout:        cfg.Timeout,
+		maxOutputBytes: cfg.MaxOutputBytes,
+		workingDir:     cfg.WorkingDir,
+	}, nil
+}
+
+// validate checks the command is allowlisted and its arguments are within limits
+func (r *commandRunner) validate(args Args) (string, error) {
+	path, ok := r.allowed[args.Command]
+	if !ok {
+		return "", fmt.Errorf("command %q is not allowlisted", args.Command)
+	}
+	if len(args.Args) > MaxArgs {
+		return "", fmt.Errorf("too many arguments: %d (max %d)", len(args.Args), MaxArgs)
+	}
+	for i, arg := range args.Args {
+		if len(arg) > MaxArgLength {
+			return "", fmt.Errorf("argument %d exceeds %d bytes", i, MaxArgLength)
+		}
+		if strings.ContainsRune(arg, 0) {
+			return "", fmt.Errorf("argument %d contains a NUL byte", i)
+		}
+	}
+	return path, nil
+}
+
+// run executes the command directly (never via a shell) and captures capped output
+func (r *commandRunner) run(ctx context.Context, args Args) Result {
+	start := time.Now()
+
+	path, err := r.validate(args)
+	if err != nil {
+		return Result{ExitCode: -1, Error: err.Error(), Duration: time.Since(start).String()}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	stdout := &cappedBuffer{limit: r.maxOutputBytes}
+	stderr := &cappedBuffer{limit: r.maxOutputBytes}
+
+	cmd := exec.CommandContext(ctx, path, args.Args...) //nolint:gosec // G204: binary is allowlisted and args bypass any shell
+	cmd.Dir = r.workingDir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Only expose PATH so secrets in the bot's environment aren't leaked to commands
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH")}
+	cmd.WaitDelay = time.Second
+
+	runErr := cmd.Run()
+	exitCode := -1
+	if cmd.ProcessState != nil {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+	result := Result{
+		ExitCode:  exitCode,
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		Truncated: stdout.truncated || stderr.truncated,
+		Duration:  time.Since(start).String(),
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.Error = fmt.Sprintf("command timed out after %s", r.timeout)
+	case runErr != nil && !errors.As(runErr, &exitErr):
+		result.Error = fmt.Sprintf("failed to run command: %v", runErr)
+	}
+
+	return result
+}
+
+// cappedBuffer is an io.Writer that keeps at most limit bytes and discards the rest
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	remaining := b.limit - b.buf.Len()
+	if remaining <= 0 {
+		if len(p) > 0 {
+			b.truncated = true
+		}
+		return len(p), nil
+	}
+	if len(p) > remaining {
+		b.buf.Write(p[:remaining])
+		b.truncated = true
+		return len(p), nil
+	}
+	b.buf.Write(p)
+	return len(p), nil
+}
+
+func (b *cappedBuffer) String() string {
+	return b.buf.String()
+}
+
+// New creates a new command execution tool.
+// It returns an error unless cfg.Enabled is true and cfg.AllowedCommands is non-empty.
+func New(cfg Config) (tool.Tool, error) {
+	runner, err := newRunner(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := func(ctx tool.Context, args Args) (Result, error) {
+		return runner.run(ctx, args), nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name: "run_command",
+		Description: fmt.Sprintf("Run an allowlisted command on the host without a shell. "+
+			"Allowed commands: %s. Arguments are passed verbatim; pipes, redirects and globs are not supported.",
+			strings.Join(runner.names, ", ")),
+	}, handler)
+}
@@ -0,0 +1,128 @@
+package command
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew_RequiresEnabled(t *testing.T) {
+	_, err := New(Config{AllowedCommands: []string{"echo"}})
+	if err == nil {
+		t.Error("expected error when tool is not enabled")
+	}
+}
+
+func TestNew_RequiresAllowlist(t *testing.T) {
+	_, err := New(Config{Enabled: true})
+	if err == nil {
+		t.Error("expected error when allowlist is empty")
+	}
+}
+
+func TestNew_RejectsRelativePath(t *testing.T) {
+	_, err := New(Config{Enabled: true, AllowedCommands: []string{"./echo"}})
+	if err == nil {
+		t.Error("expected error for relative command path")
+	}
+}
+
+func TestNew_CreatesToolWithValidConfig(t *testing.T) {
+	tool, err := New(Config{Enabled: true, AllowedCommands: []string{"echo"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tool.Name() != "run_command" {
+		t.Errorf("expected tool name 'run_command', got %q", tool.Name())
+	}
+}
+
+func TestRunner_RunsAllowlistedCommand(t *testing.T) {
+	runner, err := newRunner(Config{Enabled: true, AllowedCommands: []string{"echo"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := runner.run(context.Background(), Args{Command: "echo", Args: []string{"hello", "$HOME", "; ls"}})
+	if result.Error != "" {
+		t.Fatalf("unexpected error: %s", result.Error)
+	}
+	if result.ExitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", result.ExitCode)
+	}
+	// Arguments are passed verbatim, with no shell expansion
+	if result.Stdout != "hello $HOME ; ls\n" {
+		t.Errorf("unexpected stdout: %q", result.Stdout)
+	}
+}
+
+func TestRunner_RejectsNonAllowlistedCommand(t *testing.T) {
+	runner, err := newRunner(Config{Enabled: true, AllowedCommands: []string{"echo"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, command := range []string{"ls", "sh", "/bin/echo", "echo hello"} {
+		result := runner.run(context.Background(), Args{Command: command})
+		if !strings.Contains(result.Error, "not allowlisted") {
+			t.Errorf("command %q: expected not allowlisted error, got %q", command, result.Error)
+		}
+		if result.Stdout != "" {
+			t.Errorf("command %q: expected no output, got %q", command, result.Stdout)
+		}
+	}
+}
+
+func TestRunner_ValidatesArgs(t *testing.T) {
+	runner, err := newRunner(Config{Enabled: true, AllowedCommands: []string{"echo"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tooMany := make([]string, MaxArgs+1)
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"too many args", tooMany},
+		{"arg too long", []string{strings.Repeat("a", MaxArgLength+1)}},
+		{"nul byte", []string{"a\x00b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runner.run(context.Background(), Args{Command: "echo", Args: tt.args})
+			if result.Error == "" {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestRunner_CapsOutput(t *testing.T) {
+	runner, err := newRunner(Config{Enabled: true, AllowedCommands: []string{"echo"}, MaxOutputBytes: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := runner.run(context.Background(), Args{Command: "echo", Args: []string{"hello world"}})
+	if result.Stdout != "hello" {
+		t.Errorf("expected capped stdout 'hello', got %q", result.Stdout)
+	}
+	if !result.Truncated {
+		t.Error("expected output to be marked truncated")
+	}
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	runner, err := newRunner(Config{Enabled: true, AllowedCommands: []string{"sleep"}, Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Skipf("sleep not available: %v", err)
+	}
+
+	result := runner.run(context.Background(), Args{Command: "sleep", Args: []string{"5"}})
+	if !strings.Contains(result.Error, "timed out") {
+		t.Errorf("expected timeout error, got %q", result.Error)
+	}
+}
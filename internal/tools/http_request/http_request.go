@@ -28,13 +28,18 @@ type Result struct {
 	Error      string            `json:"error,omitempty"`
 }
 
-// handler is the HTTP request tool handler
-func handler(ctx tool.Context, args Args) (Result, error) {
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+// requestTimeout bounds each request made by the tool
+const requestTimeout = 30 * time.Second
+
+// newHandler returns the HTTP request tool handler, sending requests with client
+func newHandler(client *http.Client) func(tool.Context, Args) (Result, error) {
+	return func(ctx tool.Context, args Args) (Result, error) {
+		return handle(ctx, client, args)
 	}
+}
 
+// handle performs the request described by args
+func handle(ctx tool.Context, client *http.Client, args Args) (Result, error) {
 	// Create request body if provided
 	var bodyReader io.Reader
 	if args.Body != "" {
@@ -91,8 +96,20 @@ func handler(ctx tool.Context, args Args) (Result, error) {
 
 // New creates a new HTTP request tool
 func New() (tool.Tool, error) {
+	return NewWithClient(nil)
+}
+
+// NewWithClient creates a new HTTP request tool sending requests with client (e.g. one
+// using a proxy); nil uses the default client. Requests time out after 30 seconds.
+func NewWithClient(client *http.Client) (tool.Tool, error) {
+	timed := http.Client{}
+	if client != nil {
+		timed = *client
+	}
+	timed.Timeout = requestTimeout
+
 	return functiontool.New(functiontool.Config{
 		Name:        "http_request",
 		Description: "Make arbitrary HTTP requests to external APIs and services",
-	}, handler)
+	}, newHandler(&timed))
 }
@@ -0,0 +1,37 @@
+package web_search //nolint:revive // var-naming: using underscores for domain clarity
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/cache"
+)
+
+// Cache defaults
+const (
+	DefaultCacheTTL  = 5 * time.Minute
+	DefaultCacheSize = 100
+)
+
+// newResultCache creates a bounded LRU cache of search results that expire after ttl
+func newResultCache(ttl time.Duration, maxSize int, opts ...cache.Option) *cache.TTLCache[string, Result] {
+	return cache.New[string, Result](maxSize, ttl, opts...)
+}
+
+// cacheKey builds a cache key from the normalised query and the options that change results
+func cacheKey(args Args, num int) string {
+	query := strings.Join(strings.Fields(strings.ToLower(args.Query)), " ")
+	engine := args.Engine
+	if engine == "" {
+		engine = EngineDefault
+	}
+	return strings.Join([]string{
+		engine,
+		query,
+		strconv.Itoa(num),
+		strconv.Itoa(args.Page),
+		strings.ToLower(args.Location),
+		args.SafeSearch,
+	}, "\x00")
+}
@@ -0,0 +1,140 @@
+package web_search //nolint:revive // var-naming: using underscores for domain clarity
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/cache"
+)
+
+// newCountingSearchServer responds with the given statuses in order (then 200s) and counts calls
+func newCountingSearchServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		if n <= len(statuses) && statuses[n-1] != http.StatusOK {
+			w.WriteHeader(statuses[n-1])
+			_, _ = w.Write([]byte(`{"error":"rate limited"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"organic_results":[{"title":"Go","link":"https://go.dev","snippet":"The Go language"}]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newTestSearchClient(baseURL string, maxRetries int) *searchClient {
+	return &searchClient{
+		apiKey:       "test-key",
+		baseURL:      baseURL,
+		timeout:      5 * time.Second,
+		cache:        newResultCache(time.Minute, 10),
+		maxRetries:   maxRetries,
+		retryBackoff: time.Millisecond,
+	}
+}
+
+func TestSearch_CachesIdenticalQueries(t *testing.T) {
+	server, calls := newCountingSearchServer(t)
+	client := newTestSearchClient(server.URL, 0)
+
+	first := client.search(context.Background(), Args{Query: "golang news"})
+	second := client.search(context.Background(), Args{Query: "  GoLang   NEWS "})
+
+	if first.Error != "" || second.Error != "" {
+		t.Fatalf("unexpected errors: %q, %q", first.Error, second.Error)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 backend call, got %d", got)
+	}
+	if len(second.Results) != 1 || second.Results[0].URL != "https://go.dev" {
+		t.Errorf("cached result = %+v, want the first result", second.Results)
+	}
+
+	// A different query is not served from cache
+	client.search(context.Background(), Args{Query: "golang news", Engine: EngineBing})
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 backend calls after a different query, got %d", got)
+	}
+}
+
+func TestSearch_CacheExpires(t *testing.T) {
+	server, calls := newCountingSearchServer(t)
+	client := newTestSearchClient(server.URL, 0)
+
+	now := time.Now()
+	client.cache = newResultCache(time.Minute, 10, cache.WithClock(func() time.Time { return now }))
+	client.search(context.Background(), Args{Query: "golang"})
+
+	now = now.Add(2 * time.Minute)
+	client.search(context.Background(), Args{Query: "golang"})
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected expired entry to be refetched (2 calls), got %d", got)
+	}
+}
+
+func TestSearch_ErrorsAreNotCached(t *testing.T) {
+	server, calls := newCountingSearchServer(t, http.StatusBadRequest)
+	client := newTestSearchClient(server.URL, 0)
+
+	if result := client.search(context.Background(), Args{Query: "golang"}); result.Error == "" {
+		t.Fatal("expected error from first search")
+	}
+	if result := client.search(context.Background(), Args{Query: "golang"}); result.Error != "" {
+		t.Fatalf("unexpected error from second search: %s", result.Error)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 backend calls, got %d", got)
+	}
+}
+
+func TestSearch_RetriesRateLimit(t *testing.T) {
+	server, calls := newCountingSearchServer(t, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+	client := newTestSearchClient(server.URL, 2)
+
+	result := client.search(context.Background(), Args{Query: "golang"})
+	if result.Error != "" {
+		t.Fatalf("unexpected error after retries: %s", result.Error)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("expected 3 backend calls (2 retries), got %d", got)
+	}
+}
+
+func TestSearch_GivesUpAfterMaxRetries(t *testing.T) {
+	server, calls := newCountingSearchServer(t, http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests)
+	client := newTestSearchClient(server.URL, 1)
+
+	result := client.search(context.Background(), Args{Query: "golang"})
+	if result.Error == "" {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 backend calls (1 retry), got %d", got)
+	}
+}
+
+func TestResultCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	results := newResultCache(time.Minute, 2)
+	results.Set("a", Result{Query: "a"})
+	results.Set("b", Result{Query: "b"})
+	results.Get("a") // a is now most recently used
+	results.Set("c", Result{Query: "c"})
+
+	if _, ok := results.Get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	if _, ok := results.Get("a"); !ok {
+		t.Error("expected a to remain cached")
+	}
+	if _, ok := results.Get("c"); !ok {
+		t.Error("expected c to be cached")
+	}
+}
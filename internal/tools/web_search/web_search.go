@@ -2,6 +2,7 @@
 package web_search //nolint:revive // var-naming: using underscores for domain clarity
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,7 +10,9 @@ import (
 	"net/url"
 	"strconv"
 	"time"
+	"unicode/utf8"
 
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/cache"
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
 )
@@ -60,20 +63,50 @@ const (
 	EngineDefault = EngineGoogle
 )
 
+// Result limits
+const (
+	DefaultMaxResults   = 10
+	MaxResultsLimit     = 100
+	DefaultSnippetChars = 500
+	MaxSnippetChars     = 10000
+)
+
 // Config holds configuration for the web search tool
 type Config struct {
 	APIKey  string
 	BaseURL string
 	Timeout time.Duration
+	// MaxResults is the default and maximum number of results per search (default 10, max 100)
+	MaxResults int
+	// SnippetChars truncates each result's snippet to this many characters (default 500)
+	SnippetChars int
+	// CacheTTL is how long identical queries are served from cache (default 5m)
+	CacheTTL time.Duration
+	// CacheSize bounds the number of cached queries (default 100)
+	CacheSize int
+	// MaxRetries is the number of retries for rate-limited or failed requests (default 2)
+	MaxRetries int
+	// RetryBackoff is the initial delay between retries, doubled each attempt (default 500ms)
+	RetryBackoff time.Duration
+	// HTTPClient sends search requests, e.g. through a proxy; nil uses the default client.
+	// Timeout overrides its timeout.
+	HTTPClient *http.Client
 }
 
+// Retry defaults
+const (
+	DefaultMaxRetries   = 2
+	DefaultRetryBackoff = 500 * time.Millisecond
+	maxRetryAfter       = 30 * time.Second
+)
+
 // Args represents the arguments for the web search tool
 //
 //nolint:lll // Engine description intentionally long to list all options for LLM
 type Args struct {
 	Query      string `json:"query" jsonschema:"The search query to execute"`
 	Engine     string `json:"engine,omitempty" jsonschema:"Search engine (default: google). Options: google, google_news, google_images, google_videos, google_maps, google_shopping, google_scholar, google_finance, google_jobs, google_patents, google_trends, google_flights, google_hotels, google_lens, google_autocomplete, google_play, google_events, bing, bing_images, bing_videos, baidu, duckduckgo, yahoo, yandex, naver, amazon, ebay, walmart, shein, airbnb, tripadvisor, youtube"`
-	NumResults int    `json:"num_results,omitempty" jsonschema:"Number of results (default: 10, max: 100)"`
+	NumResults int    `json:"num_results,omitempty" jsonschema:"Number of results (defaults to and is capped at the configured maximum)"`
 	Page       int    `json:"page,omitempty" jsonschema:"Page number for pagination (default: 1)"`
 	Location   string `json:"location,omitempty" jsonschema:"Location for localized results (e.g. 'New York')"`
 	SafeSearch string `json:"safe_search,omitempty" jsonschema:"Safe search filter: 'active' or 'off' (default: off)"`
@@ -107,18 +140,34 @@ type searchAPIResponse struct {
 
 // searchClient handles the HTTP communication with the search API
 type searchClient struct {
-	apiKey  string
-	baseURL string
-	timeout time.Duration
+	apiKey       string
+	baseURL      string
+	timeout      time.Duration
+	httpClient   *http.Client // nil uses the default client
+	maxResults   int          // 0 means no configured limit beyond MaxResultsLimit
+	snippetChars int          // 0 means snippets are not truncated
+	cache        *cache.TTLCache[string, Result]
+	maxRetries   int
+	retryBackoff time.Duration
 }
 
-func (c *searchClient) search(ctx tool.Context, args Args) Result {
+func (c *searchClient) search(ctx context.Context, args Args) Result {
+	num := c.resultCount(args.NumResults)
+
+	// Serve repeated queries from cache
+	key := cacheKey(args, num)
+	if c.cache != nil {
+		if cached, ok := c.cache.Get(key); ok {
+			return cached
+		}
+	}
+
 	reqURL, err := c.buildRequestURL(args)
 	if err != nil {
 		return Result{Query: args.Query, Results: []SearchResult{}, Error: fmt.Sprintf("failed to build request: %v", err)}
 	}
 
-	body, statusCode, err := c.doRequest(ctx, reqURL)
+	body, statusCode, err := c.doRequestWithRetry(ctx, reqURL)
 	if err != nil {
 		return Result{Query: args.Query, Results: []SearchResult{}, Error: err.Error()}
 	}
@@ -127,7 +176,45 @@ func (c *searchClient) search(ctx tool.Context, args Args) Result {
 		return Result{Query: args.Query, Results: []SearchResult{}, Error: fmt.Sprintf("API error (status %d): %s", statusCode, body)}
 	}
 
-	return c.parseResponse(args.Query, body)
+	// Providers don't always honour num, so cap the results here too
+	result := c.parseResponse(args.Query, body)
+	if num > 0 && len(result.Results) > num {
+		result.Results = result.Results[:num]
+	}
+
+	// Only successful results are cached
+	if c.cache != nil && result.Error == "" {
+		c.cache.Set(key, result)
+	}
+	return result
+}
+
+// doRequestWithRetry performs the request, retrying network errors, 429s and 5xx responses
+// with exponential backoff (or the server's Retry-After when given).
+func (c *searchClient) doRequestWithRetry(ctx context.Context, reqURL string) ([]byte, int, error) {
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		body, statusCode, retryAfter, err := c.doRequest(ctx, reqURL)
+		if attempt >= c.maxRetries || (err == nil && !isRetryableStatus(statusCode)) {
+			return body, statusCode, err
+		}
+
+		wait := backoff
+		if retryAfter > 0 {
+			wait = min(retryAfter, maxRetryAfter)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, 0, fmt.Errorf("request cancelled while retrying: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// isRetryableStatus reports whether a response status indicates a transient failure
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
 }
 
 func (c *searchClient) buildRequestURL(args Args) (string, error) {
@@ -146,11 +233,7 @@ func (c *searchClient) buildRequestURL(args Args) (string, error) {
 	q.Set("engine", engine)
 	q.Set("q", args.Query)
 
-	if args.NumResults > 0 {
-		num := args.NumResults
-		if num > 100 {
-			num = 100
-		}
+	if num := c.resultCount(args.NumResults); num > 0 {
 		q.Set("num", strconv.Itoa(num))
 	}
 
@@ -170,26 +253,55 @@ func (c *searchClient) buildRequestURL(args Args) (string, error) {
 	return u.String(), nil
 }
 
-func (c *searchClient) doRequest(ctx tool.Context, reqURL string) ([]byte, int, error) {
+// resultCount returns how many results to request: the requested count (or the configured
+// default when unset), capped at the configured maximum. It returns 0 when neither is set.
+func (c *searchClient) resultCount(requested int) int {
+	limit := c.maxResults
+	if limit <= 0 || limit > MaxResultsLimit {
+		limit = MaxResultsLimit
+	}
+
+	num := requested
+	if num <= 0 {
+		num = c.maxResults
+	}
+	if num > limit {
+		num = limit
+	}
+	return num
+}
+
+// doRequest performs a single request, also returning the Retry-After delay if the server sent one
+func (c *searchClient) doRequest(ctx context.Context, reqURL string) ([]byte, int, time.Duration, error) {
 	client := &http.Client{Timeout: c.timeout}
+	if c.httpClient != nil {
+		shared := *c.httpClient
+		shared.Timeout = c.timeout
+		client = &shared
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+		return nil, 0, 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, 0, fmt.Errorf("request failed: %w", err)
+		return nil, 0, 0, fmt.Errorf("request failed: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	var retryAfter time.Duration
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
+		return nil, resp.StatusCode, retryAfter, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	return body, resp.StatusCode, nil
+	return body, resp.StatusCode, retryAfter, nil
 }
 
 func (c *searchClient) parseResponse(query string, body []byte) Result {
@@ -203,7 +315,7 @@ func (c *searchClient) parseResponse(query string, body []byte) Result {
 		results[i] = SearchResult{
 			Title:   r.Title,
 			URL:     r.Link,
-			Snippet: r.Snippet,
+			Snippet: truncateSnippet(r.Snippet, c.snippetChars),
 		}
 	}
 
@@ -213,12 +325,35 @@ func (c *searchClient) parseResponse(query string, body []byte) Result {
 	}
 }
 
+// truncateSnippet shortens s to at most maxChars runes, marking the cut with an ellipsis
+func truncateSnippet(s string, maxChars int) string {
+	if maxChars <= 0 || utf8.RuneCountInString(s) <= maxChars {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:maxChars]) + "…"
+}
+
 // New creates a new web search tool
 func New(cfg Config) (tool.Tool, error) {
 	if cfg.APIKey == "" {
 		return nil, fmt.Errorf("search API key is required")
 	}
 
+	if cfg.MaxResults < 0 || cfg.MaxResults > MaxResultsLimit {
+		return nil, fmt.Errorf("max results must be between 1 and %d, got %d", MaxResultsLimit, cfg.MaxResults)
+	}
+	if cfg.MaxResults == 0 {
+		cfg.MaxResults = DefaultMaxResults
+	}
+
+	if cfg.SnippetChars < 0 || cfg.SnippetChars > MaxSnippetChars {
+		return nil, fmt.Errorf("snippet chars must be between 1 and %d, got %d", MaxSnippetChars, cfg.SnippetChars)
+	}
+	if cfg.SnippetChars == 0 {
+		cfg.SnippetChars = DefaultSnippetChars
+	}
+
 	if cfg.BaseURL == "" {
 		cfg.BaseURL = "https://www.searchapi.io"
 	}
@@ -227,10 +362,32 @@ func New(cfg Config) (tool.Tool, error) {
 		cfg.Timeout = 30 * time.Second
 	}
 
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = DefaultCacheTTL
+	}
+	if cfg.CacheSize <= 0 {
+		cfg.CacheSize = DefaultCacheSize
+	}
+	if cfg.MaxRetries < 0 {
+		return nil, fmt.Errorf("max retries cannot be negative, got %d", cfg.MaxRetries)
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultRetryBackoff
+	}
+
 	client := &searchClient{
-		apiKey:  cfg.APIKey,
-		baseURL: cfg.BaseURL,
-		timeout: cfg.Timeout,
+		apiKey:       cfg.APIKey,
+		baseURL:      cfg.BaseURL,
+		timeout:      cfg.Timeout,
+		httpClient:   cfg.HTTPClient,
+		maxResults:   cfg.MaxResults,
+		snippetChars: cfg.SnippetChars,
+		cache:        newResultCache(cfg.CacheTTL, cfg.CacheSize),
+		maxRetries:   cfg.MaxRetries,
+		retryBackoff: cfg.RetryBackoff,
 	}
 
 	handler := func(ctx tool.Context, args Args) (Result, error) {
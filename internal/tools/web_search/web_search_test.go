@@ -1,7 +1,13 @@
 package web_search
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNew_RequiresAPIKey(t *testing.T) {
@@ -171,3 +177,91 @@ func containsAt(s, substr string) bool {
 	}
 	return false
 }
+
+// newMockSearchServer serves count organic results and records the num parameter requested
+func newMockSearchServer(t *testing.T, count int, snippet string) (*httptest.Server, *string) {
+	t.Helper()
+	var requestedNum string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedNum = r.URL.Query().Get("num")
+		var results []string
+		for i := 0; i < count; i++ {
+			results = append(results, fmt.Sprintf(`{"title":"Result %d","link":"https://example.com/%d","snippet":%q}`, i, i, snippet))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"organic_results":[%s]}`, strings.Join(results, ","))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requestedNum
+}
+
+func TestSearchClient_Search_ConfiguredResultCount(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxResults  int
+		requested   int
+		wantNum     string
+		wantResults int
+	}{
+		{name: "uses configured default", maxResults: 3, requested: 0, wantNum: "3", wantResults: 3},
+		{name: "honours smaller request", maxResults: 5, requested: 2, wantNum: "2", wantResults: 2},
+		{name: "caps larger request", maxResults: 4, requested: 50, wantNum: "4", wantResults: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The backend ignores num and returns more than asked for
+			server, requestedNum := newMockSearchServer(t, 20, "snippet")
+			client := &searchClient{apiKey: "test-key", baseURL: server.URL, timeout: 5 * time.Second, maxResults: tt.maxResults}
+
+			result := client.search(context.Background(), Args{Query: "golang", NumResults: tt.requested})
+			if result.Error != "" {
+				t.Fatalf("unexpected error: %s", result.Error)
+			}
+			if *requestedNum != tt.wantNum {
+				t.Errorf("requested num=%q, want %q", *requestedNum, tt.wantNum)
+			}
+			if len(result.Results) != tt.wantResults {
+				t.Errorf("got %d results, want %d", len(result.Results), tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestSearchClient_Search_TruncatesSnippets(t *testing.T) {
+	server, _ := newMockSearchServer(t, 1, strings.Repeat("é", 50))
+	client := &searchClient{apiKey: "test-key", baseURL: server.URL, timeout: 5 * time.Second, maxResults: 1, snippetChars: 10}
+
+	result := client.search(context.Background(), Args{Query: "golang"})
+	if len(result.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(result.Results))
+	}
+	want := strings.Repeat("é", 10) + "…"
+	if result.Results[0].Snippet != want {
+		t.Errorf("snippet = %q, want %q", result.Results[0].Snippet, want)
+	}
+}
+
+func TestNew_ValidatesLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "defaults", cfg: Config{APIKey: "k"}},
+		{name: "valid limits", cfg: Config{APIKey: "k", MaxResults: 5, SnippetChars: 200}},
+		{name: "negative max results", cfg: Config{APIKey: "k", MaxResults: -1}, wantErr: true},
+		{name: "max results too large", cfg: Config{APIKey: "k", MaxResults: MaxResultsLimit + 1}, wantErr: true},
+		{name: "negative snippet chars", cfg: Config{APIKey: "k", SnippetChars: -1}, wantErr: true},
+		{name: "snippet chars too large", cfg: Config{APIKey: "k", SnippetChars: MaxSnippetChars + 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
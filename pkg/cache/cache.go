@@ -0,0 +1,138 @@
+// Package cache provides a bounded, concurrency-safe in-memory cache whose entries expire
+// after a time-to-live, for lookups such as user and channel names that are worth keeping
+// for a while but shouldn't grow without limit.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// TTLCache is a generic LRU cache with per-entry expiry. Once it holds maxSize entries,
+// adding another evicts the least recently used one. It's safe for concurrent use.
+type TTLCache[K comparable, V any] struct {
+	ttl     time.Duration
+	maxSize int
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[K]*list.Element
+	order   *list.List // Front is most recently used
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time // Zero if the entry never expires
+}
+
+// Option configures optional TTLCache behaviour
+type Option func(*options)
+
+type options struct {
+	now func() time.Time
+}
+
+// WithClock sets the clock used for expiry; time.Now by default
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// New creates a cache holding at most maxSize entries (unbounded if maxSize <= 0) that
+// expire ttl after they're set (never if ttl <= 0).
+func New[K comparable, V any](maxSize int, ttl time.Duration, opts ...Option) *TTLCache[K, V] {
+	o := options{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &TTLCache[K, V]{
+		ttl:     ttl,
+		maxSize: maxSize,
+		now:     o.now,
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the value cached for key, if present and not expired
+func (c *TTLCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	elem, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+	e := elem.Value.(*entry[K, V])
+	if !e.expires.IsZero() && !c.now().Before(e.expires) {
+		c.removeElement(elem)
+		return zero, false
+	}
+	c.order.MoveToFront(elem)
+	return e.value, true
+}
+
+// Set caches value under key with the cache's TTL
+func (c *TTLCache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL caches value under key, expiring after ttl (never if ttl <= 0)
+func (c *TTLCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.now().Add(ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*entry[K, V])
+		e.value = value
+		e.expires = expires
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expires: expires})
+	for c.maxSize > 0 && c.order.Len() > c.maxSize {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// Delete removes key from the cache
+func (c *TTLCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// Len returns the number of entries held, including expired ones not yet removed
+func (c *TTLCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Purge removes every entry
+func (c *TTLCache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[K]*list.Element)
+	c.order.Init()
+}
+
+// removeElement drops an entry; must be called with c.mu held
+func (c *TTLCache[K, V]) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable clock for tests
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestTTLCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[string, int](2, 0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// Reading a makes b the least recently used
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %d, %v; want 1, true", v, ok)
+	}
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if v, ok := c.Get(key); !ok || v != want {
+			t.Errorf("Get(%s) = %d, %v; want %d, true", key, v, ok, want)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+}
+
+func TestTTLCache_ExpiresEntries(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := New[string, string](10, time.Minute, WithClock(clock.Now))
+	c.Set("short", "x")
+	c.SetWithTTL("long", "y", time.Hour)
+	c.SetWithTTL("forever", "z", 0)
+
+	clock.Advance(59 * time.Second)
+	if _, ok := c.Get("short"); !ok {
+		t.Error("short expired early")
+	}
+
+	clock.Advance(time.Second)
+	if _, ok := c.Get("short"); ok {
+		t.Error("short should have expired after its TTL")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want the expired entry removed", c.Len())
+	}
+
+	clock.Advance(2 * time.Hour)
+	if _, ok := c.Get("long"); ok {
+		t.Error("long should have expired after its own TTL")
+	}
+	if v, ok := c.Get("forever"); !ok || v != "z" {
+		t.Errorf("Get(forever) = %q, %v; want an entry that never expires", v, ok)
+	}
+
+	// Setting a key again refreshes its expiry
+	c.Set("short", "x2")
+	clock.Advance(30 * time.Second)
+	c.Set("short", "x3")
+	clock.Advance(45 * time.Second)
+	if v, ok := c.Get("short"); !ok || v != "x3" {
+		t.Errorf("Get(short) = %q, %v; want the refreshed value", v, ok)
+	}
+}
+
+func TestTTLCache_DeleteAndPurge(t *testing.T) {
+	c := New[int, string](0, 0)
+	for i := 0; i < 100; i++ {
+		c.Set(i, fmt.Sprint(i))
+	}
+	if c.Len() != 100 {
+		t.Fatalf("Len() = %d, want an unbounded cache to keep everything", c.Len())
+	}
+
+	c.Delete(5)
+	if _, ok := c.Get(5); ok {
+		t.Error("5 should have been deleted")
+	}
+	c.Purge()
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d after Purge, want 0", c.Len())
+	}
+}
+
+func TestTTLCache_ConcurrentAccess(t *testing.T) {
+	c := New[int, int](50, time.Minute)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := (w*1000 + i) % 200
+				c.Set(key, key)
+				if v, ok := c.Get(key); ok && v != key {
+					t.Errorf("Get(%d) = %d", key, v)
+				}
+				if i%10 == 0 {
+					c.Delete(key)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if c.Len() > 50 {
+		t.Errorf("Len() = %d, want at most the max size", c.Len())
+	}
+}
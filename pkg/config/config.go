@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"reflect"
@@ -9,11 +10,15 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/secrets"
 	"gopkg.in/yaml.v3"
 )
 
 var durationType = reflect.TypeOf(time.Duration(0))
 
+// secretsTimeout bounds resolving all of a config's secret references
+const secretsTimeout = 30 * time.Second
+
 // Validator interface allows config structs to implement custom validation logic.
 // If a config struct implements this interface, validation will be automatically
 // called after loading configuration from files and environment variables.
@@ -21,6 +26,36 @@ type Validator interface {
 	Validate() error
 }
 
+// SecretSource lets config structs hold secret://name references (see package secrets) in
+// place of API keys and tokens. Once the configuration is loaded and defaults applied, and
+// before validation, SecretProvider is called and every reference in the struct is replaced
+// with the secret it names. A nil provider leaves references as they are.
+type SecretSource interface {
+	SecretProvider(ctx context.Context) (secrets.Provider, error)
+}
+
+// resolveSecrets resolves the secret references in dest if it's a SecretSource
+func resolveSecrets(dest any) error {
+	source, ok := dest.(SecretSource)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), secretsTimeout)
+	defer cancel()
+
+	provider, err := source.SecretProvider(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create secret provider: %w", err)
+	}
+	if provider == nil {
+		return nil
+	}
+	if err := secrets.ResolveAll(ctx, provider, dest); err != nil {
+		return fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+	return nil
+}
+
 //nolint:gocyclo,gocognit,revive // Reflection-based config processing requires complex type handling
 func processFields(val reflect.Value, typeOfT reflect.Type) (map[string]bool, error) {
 	setFields := make(map[string]bool)
@@ -55,58 +90,18 @@ func processFields(val reflect.Value, typeOfT reflect.Type) (map[string]bool, er
 				fieldKey := typeOfT.Name() + "." + fieldType.Name
 				setFields[fieldKey] = true
 
-				// Set the value to the field based on its type
-				// Check for time.Duration first (it's an int64 underneath)
-				if field.Type() == durationType {
-					duration, err := time.ParseDuration(envVal)
-					if err != nil {
-						return nil, fmt.Errorf("failed to convert %s to duration: %v", envVal, err)
+				// Pointer fields let callers tell an explicit zero value apart from unset
+				if field.Kind() == reflect.Ptr {
+					ptr := reflect.New(field.Type().Elem())
+					if err := setFieldFromString(ptr.Elem(), envVal); err != nil {
+						return nil, err
 					}
-					field.SetInt(int64(duration))
+					field.Set(ptr)
 					continue
 				}
 
-				switch field.Kind() {
-				case reflect.String:
-					field.SetString(envVal)
-				case reflect.Int, reflect.Int64:
-					intVal, err := strconv.ParseInt(envVal, 10, 64)
-					if err != nil {
-						return nil, fmt.Errorf("failed to convert %s to int: %v", envVal, err)
-					}
-					field.SetInt(intVal)
-				case reflect.Float64:
-					floatVal, err := strconv.ParseFloat(envVal, 64)
-					if err != nil {
-						return nil, fmt.Errorf("failed to convert %s to float64: %v", envVal, err)
-					}
-					field.SetFloat(floatVal)
-				case reflect.Float32:
-					floatVal, err := strconv.ParseFloat(envVal, 32)
-					if err != nil {
-						return nil, fmt.Errorf("failed to convert %s to float32: %v", envVal, err)
-					}
-					field.SetFloat(floatVal)
-				case reflect.Bool:
-					boolVal, err := strconv.ParseBool(envVal)
-					if err != nil {
-						return nil, fmt.Errorf("failed to convert %s to bool: %v", envVal, err)
-					}
-					field.SetBool(boolVal)
-				case reflect.Slice:
-					// Handle string slices (comma-separated values)
-					if field.Type().Elem().Kind() == reflect.String {
-						values := strings.Split(envVal, ",")
-						slice := reflect.MakeSlice(field.Type(), len(values), len(values))
-						for i, v := range values {
-							slice.Index(i).SetString(strings.TrimSpace(v))
-						}
-						field.Set(slice)
-					} else {
-						return nil, fmt.Errorf("unsupported slice type %s", field.Type())
-					}
-				default:
-					return nil, fmt.Errorf("unsupported kind %s", field.Kind())
+				if err := setFieldFromString(field, envVal); err != nil {
+					return nil, err
 				}
 			}
 		}
@@ -114,6 +109,62 @@ func processFields(val reflect.Value, typeOfT reflect.Type) (map[string]bool, er
 	return setFields, nil
 }
 
+// setFieldFromString parses raw according to the field's type and assigns it.
+func setFieldFromString(field reflect.Value, raw string) error {
+	// Check for time.Duration first (it's an int64 underneath)
+	if field.Type() == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s to duration: %v", raw, err)
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		intVal, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s to int: %v", raw, err)
+		}
+		field.SetInt(intVal)
+	case reflect.Float64:
+		floatVal, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s to float64: %v", raw, err)
+		}
+		field.SetFloat(floatVal)
+	case reflect.Float32:
+		floatVal, err := strconv.ParseFloat(raw, 32)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s to float32: %v", raw, err)
+		}
+		field.SetFloat(floatVal)
+	case reflect.Bool:
+		boolVal, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s to bool: %v", raw, err)
+		}
+		field.SetBool(boolVal)
+	case reflect.Slice:
+		// Handle string slices (comma-separated values)
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice type %s", field.Type())
+		}
+		values := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, v := range values {
+			slice.Index(i).SetString(strings.TrimSpace(v))
+		}
+		field.Set(slice)
+	default:
+		return fmt.Errorf("unsupported kind %s", field.Kind())
+	}
+	return nil
+}
+
 //nolint:gocyclo,gocognit,revive // Reflection-based validation requires complex type handling
 func checkRequiredAndDefaults(val reflect.Value, typeOfT reflect.Type, setFields map[string]bool) error {
 	var result error
@@ -229,6 +280,10 @@ func GetConfigFromEnvVars[T any](dest *T) error {
 		return err
 	}
 
+	if err := resolveSecrets(dest); err != nil {
+		return err
+	}
+
 	// Run custom validation if the type implements Validator
 	if validator, ok := any(*dest).(Validator); ok {
 		if err := validator.Validate(); err != nil {
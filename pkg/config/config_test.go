@@ -1,9 +1,12 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"os"
 	"testing"
 
+	"github.com/lewisedginton/general_purpose_chatbot/pkg/secrets"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -221,6 +224,88 @@ api_key: ${UNSET_VAR}
 	os.Clearenv()
 }
 
+func TestGetConfigFromEnvVarsPointerFields(t *testing.T) {
+	type pointerConfig struct {
+		Temperature *float64 `env:"TEST_TEMPERATURE" yaml:"temperature"`
+		MaxTokens   *int     `env:"TEST_MAX_TOKENS" yaml:"max_tokens"`
+	}
+
+	os.Clearenv()
+	os.Setenv("TEST_TEMPERATURE", "0")
+
+	var cfg pointerConfig
+	err := GetConfigFromEnvVars(&cfg)
+	assert.NoError(t, err)
+
+	// An explicit zero must be distinguishable from an unset field
+	if assert.NotNil(t, cfg.Temperature) {
+		assert.Equal(t, 0.0, *cfg.Temperature)
+	}
+	assert.Nil(t, cfg.MaxTokens)
+
+	os.Setenv("TEST_MAX_TOKENS", "not-a-number")
+	err = GetConfigFromEnvVars(&pointerConfig{})
+	assert.Error(t, err)
+
+	os.Clearenv()
+}
+
+// fakeSecrets serves secrets from a map
+type fakeSecrets map[string]string
+
+func (f fakeSecrets) GetSecret(_ context.Context, name, _ string) (string, error) {
+	if value, ok := f[name]; ok {
+		return value, nil
+	}
+	return "", secrets.ErrNotFound
+}
+
+// secretConfig resolves its references with a fakeSecrets provider and requires a real API key
+type secretConfig struct {
+	APIKey string `env:"TEST_API_KEY" yaml:"api_key" required:"true"`
+	Model  string `env:"TEST_MODEL" yaml:"model" default:"gpt-4o"`
+
+	provider fakeSecrets
+}
+
+func (c *secretConfig) SecretProvider(context.Context) (secrets.Provider, error) {
+	return c.provider, nil
+}
+
+func (c secretConfig) Validate() error {
+	if secrets.IsReference(c.APIKey) {
+		return errors.New("api_key is still a secret reference")
+	}
+	return nil
+}
+
+func TestGetConfigFromEnvVarsResolvesSecrets(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+	os.Setenv("TEST_API_KEY", "secret://prod/api-key")
+
+	cfg := secretConfig{provider: fakeSecrets{"prod/api-key": "sk-resolved"}}
+	err := GetConfigFromEnvVars(&cfg)
+
+	// References are resolved before validation; plain values and defaults are unchanged
+	assert.NoError(t, err)
+	assert.Equal(t, "sk-resolved", cfg.APIKey)
+	assert.Equal(t, "gpt-4o", cfg.Model)
+
+	os.Setenv("TEST_API_KEY", "sk-plain")
+	cfg = secretConfig{provider: fakeSecrets{}}
+	assert.NoError(t, GetConfigFromEnvVars(&cfg))
+	assert.Equal(t, "sk-plain", cfg.APIKey)
+
+	os.Setenv("TEST_API_KEY", "secret://missing")
+	cfg = secretConfig{provider: fakeSecrets{}}
+	err = GetConfigFromEnvVars(&cfg)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "failed to resolve secrets")
+		assert.ErrorIs(t, err, secrets.ErrNotFound)
+	}
+}
+
 func TestCommonConfigValidation(t *testing.T) {
 	testCases := []struct {
 		name     string
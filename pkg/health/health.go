@@ -58,14 +58,23 @@ type HealthStatus struct {
 	Checks  []CheckResult
 }
 
+// cachedResult is a check result and when it was taken
+type cachedResult struct {
+	result CheckResult
+	at     time.Time
+}
+
 // HealthChecker manages and executes health checks for liveness and readiness probes.
 // It supports concurrent check execution, failure thresholds, and configurable timeouts.
 type HealthChecker struct {
 	livenessChecks   []Check
 	readinessChecks  []Check
 	timeout          time.Duration
-	failureCount     map[string]int // Track consecutive failures per check
-	failureThreshold int            // Number of consecutive failures before reporting unhealthy
+	failureCount     map[string]int           // Track consecutive failures per check
+	failureThreshold int                      // Number of consecutive failures before reporting unhealthy
+	immediate        map[string]bool          // Checks reported unhealthy on their first failure
+	cacheTTL         map[string]time.Duration // How long results of cached checks are reused
+	lastResult       map[string]cachedResult  // Latest result of each cached check
 	logger           logger.Logger
 	mu               sync.RWMutex
 }
@@ -104,6 +113,9 @@ func New(opts ...Option) *HealthChecker {
 		timeout:          5 * time.Second,
 		failureThreshold: 3,
 		failureCount:     make(map[string]int),
+		immediate:        make(map[string]bool),
+		cacheTTL:         make(map[string]time.Duration),
+		lastResult:       make(map[string]cachedResult),
 	}
 
 	for _, opt := range opts {
@@ -129,6 +141,26 @@ func (h *HealthChecker) AddReadinessCheck(check Check) {
 	h.readinessChecks = append(h.readinessChecks, check)
 }
 
+// AddImmediateReadinessCheck adds a readiness check that is reported unhealthy on its first
+// failure rather than after the failure threshold, for conditions that shouldn't be smoothed
+// over, such as startup not having finished.
+func (h *HealthChecker) AddImmediateReadinessCheck(check Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.readinessChecks = append(h.readinessChecks, check)
+	h.immediate[check.Name()] = true
+}
+
+// AddCachedReadinessCheck adds a readiness check whose result is reused for ttl, for checks
+// too expensive to run on every probe (e.g. a storage round trip). Only real runs count
+// towards the failure threshold.
+func (h *HealthChecker) AddCachedReadinessCheck(check Check, ttl time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.readinessChecks = append(h.readinessChecks, check)
+	h.cacheTTL[check.Name()] = ttl
+}
+
 // CheckLiveness executes all liveness checks and returns an error if any fail.
 func (h *HealthChecker) CheckLiveness(ctx context.Context) (*HealthStatus, error) {
 	h.mu.RLock()
@@ -190,6 +222,14 @@ func (h *HealthChecker) executeChecks(ctx context.Context, checks []Check) (*Hea
 
 // executeCheck runs a single health check with timeout and failure threshold logic.
 func (h *HealthChecker) executeCheck(parentCtx context.Context, check Check) CheckResult {
+	h.mu.RLock()
+	ttl := h.cacheTTL[check.Name()]
+	cached, ok := h.lastResult[check.Name()]
+	h.mu.RUnlock()
+	if ttl > 0 && ok && time.Since(cached.at) < ttl {
+		return cached.result
+	}
+
 	ctx, cancel := context.WithTimeout(parentCtx, h.timeout)
 	defer cancel()
 
@@ -204,13 +244,18 @@ func (h *HealthChecker) executeCheck(parentCtx context.Context, check Check) Che
 
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	if ttl > 0 {
+		defer func() {
+			h.lastResult[check.Name()] = cachedResult{result: result, at: time.Now()}
+		}()
+	}
 
 	if err != nil {
 		// Increment failure count
 		h.failureCount[check.Name()]++
 
 		// Check if we've reached the threshold
-		if h.failureCount[check.Name()] >= h.failureThreshold {
+		if h.immediate[check.Name()] || h.failureCount[check.Name()] >= h.failureThreshold {
 			result.Healthy = false
 			result.Error = err.Error()
 
@@ -237,6 +237,49 @@ func TestHealthChecker_FailureThreshold(t *testing.T) {
 		assert.NoError(t, err) // Should still be healthy (count = 1)
 		assert.True(t, status.Healthy)
 	})
+
+	t.Run("immediate check ignores threshold", func(t *testing.T) {
+		h := New(WithFailureThreshold(3))
+		check := &mockCheck{name: "startup", err: errors.New("starting")}
+		h.AddImmediateReadinessCheck(check)
+
+		status, err := h.CheckReadiness(context.Background())
+		assert.Error(t, err)
+		assert.False(t, status.Healthy)
+
+		check.SetErr(nil)
+		status, err = h.CheckReadiness(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, status.Healthy)
+	})
+}
+
+func TestHealthChecker_CachedCheck(t *testing.T) {
+	h := New(WithFailureThreshold(2))
+	check := &mockCheck{name: "storage", err: errors.New("slow")}
+	h.AddCachedReadinessCheck(check, 50*time.Millisecond)
+
+	// Repeated probes within the TTL reuse the result and don't count as more failures
+	for i := 0; i < 3; i++ {
+		status, err := h.CheckReadiness(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, status.Healthy)
+	}
+
+	// The next real run reaches the threshold
+	time.Sleep(60 * time.Millisecond)
+	status, err := h.CheckReadiness(context.Background())
+	assert.Error(t, err)
+	assert.False(t, status.Healthy)
+
+	// Recovery shows up once the cached failure expires
+	check.SetErr(nil)
+	_, err = h.CheckReadiness(context.Background())
+	assert.Error(t, err)
+	time.Sleep(60 * time.Millisecond)
+	status, err = h.CheckReadiness(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, status.Healthy)
 }
 
 func TestHealthChecker_Timeout(t *testing.T) {
@@ -0,0 +1,151 @@
+// Package httpclient builds the HTTP clients used for outbound requests, so timeouts,
+// connection pooling and proxy settings are applied in one place for every component.
+// Components should share one client (or its transport) so connections are reused.
+package httpclient
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Defaults for zero Options fields
+const (
+	DefaultDialTimeout         = 30 * time.Second
+	DefaultTLSHandshakeTimeout = 10 * time.Second
+	DefaultIdleConnTimeout     = 90 * time.Second
+	DefaultMaxIdleConns        = 100
+	DefaultMaxIdleConnsPerHost = 10
+)
+
+// Options configures the clients built by New. Zero values use the defaults above.
+type Options struct {
+	// Timeout bounds each request, including reading the response body; 0 for no limit.
+	// Components needing a different limit copy the client and change it.
+	Timeout time.Duration
+	// DialTimeout bounds establishing a TCP connection
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout bounds the TLS handshake
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout bounds waiting for response headers once a request is sent;
+	// 0 for no limit. Non-streaming LLM calls only send headers once the reply is complete.
+	ResponseHeaderTimeout time.Duration
+	// IdleConnTimeout is how long an idle connection is kept for reuse
+	IdleConnTimeout time.Duration
+	// MaxIdleConns bounds the idle connections kept across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost bounds the idle connections kept per host
+	MaxIdleConnsPerHost int
+	// TLSConfig is used for TLS connections; nil for the defaults
+	TLSConfig *tls.Config
+
+	// ProxyURL is the proxy for all outbound requests, e.g. "http://proxy.corp:3128".
+	// When empty, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are honored.
+	ProxyURL string
+	// NoProxy lists hosts that bypass ProxyURL, in NO_PROXY syntax ("example.com,10.0.0.0/8")
+	NoProxy string
+}
+
+// New returns an HTTP client with the configured timeouts, connection pool and proxy
+func New(opts Options) (*http.Client, error) {
+	transport, err := NewTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport, Timeout: opts.Timeout}, nil
+}
+
+// NewTransport returns a pooling transport with the configured timeouts and proxy
+func NewTransport(opts Options) (*http.Transport, error) {
+	proxy, err := Proxy(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   orDefault(opts.DialTimeout, DefaultDialTimeout),
+		KeepAlive: 30 * time.Second,
+	}
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		TLSHandshakeTimeout:   orDefault(opts.TLSHandshakeTimeout, DefaultTLSHandshakeTimeout),
+		ResponseHeaderTimeout: opts.ResponseHeaderTimeout,
+		IdleConnTimeout:       orDefault(opts.IdleConnTimeout, DefaultIdleConnTimeout),
+		MaxIdleConns:          orDefault(opts.MaxIdleConns, DefaultMaxIdleConns),
+		MaxIdleConnsPerHost:   orDefault(opts.MaxIdleConnsPerHost, DefaultMaxIdleConnsPerHost),
+		ExpectContinueTimeout: time.Second,
+	}
+	if opts.TLSConfig != nil {
+		transport.TLSClientConfig = opts.TLSConfig.Clone()
+	}
+	return transport, nil
+}
+
+// orDefault returns value, or def if value isn't positive
+func orDefault[T time.Duration | int](value, def T) T {
+	if value > 0 {
+		return value
+	}
+	return def
+}
+
+// Proxy returns the function choosing the proxy for each request, for use as
+// http.Transport.Proxy. Requests to localhost are never proxied.
+func Proxy(opts Options) (func(*http.Request) (*url.URL, error), error) {
+	cfg := httpproxy.FromEnvironment()
+	if opts.ProxyURL != "" {
+		if _, err := ParseProxyURL(opts.ProxyURL); err != nil {
+			return nil, err
+		}
+		cfg = &httpproxy.Config{
+			HTTPProxy:  opts.ProxyURL,
+			HTTPSProxy: opts.ProxyURL,
+			NoProxy:    opts.NoProxy,
+		}
+	}
+
+	proxyFunc := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
+
+// ParseProxyURL parses and checks a proxy URL. http, https and socks5 proxies are supported.
+func ParseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", u.Redacted())
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: host is required", u.Redacted())
+	}
+	return u, nil
+}
+
+// WebSocketDialer returns a websocket dialer using the proxy, dialer and TLS settings of
+// client's transport, so websocket connections are configured like HTTP requests. Clients
+// without an *http.Transport (or nil) get the websocket defaults.
+func WebSocketDialer(client *http.Client) *websocket.Dialer {
+	dialer := *websocket.DefaultDialer
+	if client != nil {
+		if transport, ok := client.Transport.(*http.Transport); ok {
+			dialer.Proxy = transport.Proxy
+			dialer.NetDialContext = transport.DialContext
+			dialer.TLSClientConfig = transport.TLSClientConfig
+		}
+	}
+	return &dialer
+}
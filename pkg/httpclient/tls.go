@@ -0,0 +1,55 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSOptions configures certificate verification for outbound connections
+type TLSOptions struct {
+	// CAFile is a PEM bundle of extra CA certificates to trust, e.g. a private CA for
+	// self-hosted LLM or MCP endpoints. They're trusted in addition to the system roots.
+	CAFile string
+	// InsecureSkipVerify disables certificate verification. For development only.
+	InsecureSkipVerify bool
+}
+
+// NewTLSConfig returns the TLS config for opts, or nil if opts needs none
+func NewTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	if opts.CAFile == "" && !opts.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify, //nolint:gosec // Opt-in for development, warned about at startup
+	}
+	if opts.CAFile != "" {
+		pool, err := LoadCAPool(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+// LoadCAPool returns the system roots plus the certificates in the PEM file at path. It
+// fails if the file can't be read or contains no certificates.
+func LoadCAPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path) //nolint:gosec // Path comes from trusted config
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CA file %s contains no PEM certificates", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,105 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeServerCA writes the self-signed certificate of a TLS test server to a PEM file
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func newTLSServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "hello")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTLSClient(t *testing.T, opts TLSOptions) *http.Client {
+	t.Helper()
+	tlsConfig, err := NewTLSConfig(opts)
+	if err != nil {
+		t.Fatalf("NewTLSConfig() error = %v", err)
+	}
+	client, err := New(Options{TLSConfig: tlsConfig})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return client
+}
+
+func TestNewTLSConfig_TrustsCustomCA(t *testing.T) {
+	server := newTLSServer(t)
+
+	// Without the CA the self-signed certificate is rejected
+	if resp, err := newTLSClient(t, TLSOptions{}).Get(server.URL); err == nil {
+		_ = resp.Body.Close()
+		t.Fatal("Get() without the CA succeeded, want a certificate error")
+	}
+
+	client := newTLSClient(t, TLSOptions{CAFile: writeServerCA(t, server)})
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() with the CA error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if body, _ := io.ReadAll(resp.Body); string(body) != "hello" {
+		t.Errorf("body = %q, want hello", body)
+	}
+}
+
+func TestNewTLSConfig_InsecureSkipVerify(t *testing.T) {
+	server := newTLSServer(t)
+
+	resp, err := newTLSClient(t, TLSOptions{InsecureSkipVerify: true}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v, want verification skipped", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestNewTLSConfig_None(t *testing.T) {
+	cfg, err := NewTLSConfig(TLSOptions{})
+	if err != nil || cfg != nil {
+		t.Errorf("NewTLSConfig() = %v, %v, want nil for the defaults", cfg, err)
+	}
+}
+
+func TestLoadCAPool_Errors(t *testing.T) {
+	if _, err := LoadCAPool(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Error("LoadCAPool() of a missing file = nil error, want an error")
+	}
+
+	path := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(path, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := LoadCAPool(path); err == nil {
+		t.Error("LoadCAPool() of a file without certificates = nil error, want an error")
+	}
+}
+
+func TestWebSocketDialer_UsesClientTLS(t *testing.T) {
+	server := newTLSServer(t)
+	client := newTLSClient(t, TLSOptions{CAFile: writeServerCA(t, server)})
+
+	dialer := WebSocketDialer(client)
+	if dialer.TLSClientConfig == nil || dialer.TLSClientConfig.RootCAs == nil {
+		t.Error("dialer TLS config doesn't have the custom CA")
+	}
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Keys of the fields added alongside an error field when Config.ErrorDetail is set
+const (
+	ErrorTypeFieldKey   = "error_type"   // Go type of the error, e.g. *fmt.wrapError
+	ErrorChainFieldKey  = "error_chain"  // Every error in the chain, one per line
+	ErrorDetailFieldKey = "error_detail" // The error formatted with %+v, when that adds detail such as a stack trace
+)
+
+// maxChainLength bounds how many errors of a chain are rendered
+const maxChainLength = 32
+
+// WithError returns a new logger with an error field for err, plus its type and chain when
+// the logger was configured with ErrorDetail
+func (l *logger) WithError(err error) Logger {
+	return l.WithFields(ErrorField(err))
+}
+
+// errorDetailFields returns the type, chain and %+v detail fields for err
+func errorDetailFields(err error) []LogField {
+	fields := []LogField{{Key: ErrorTypeFieldKey, Value: fmt.Sprintf("%T", err)}}
+	if chain := errorChain(err); len(chain) > 1 {
+		fields = append(fields, LogField{Key: ErrorChainFieldKey, Value: strings.Join(chain, "\n")})
+	}
+	if detail := fmt.Sprintf("%+v", err); detail != err.Error() {
+		fields = append(fields, LogField{Key: ErrorDetailFieldKey, Value: detail})
+	}
+	return fields
+}
+
+// errorChain renders err and the errors it wraps, found with errors.Unwrap, Unwrap() []error
+// (errors.Join) or WrappedErrors (multierror), as "type: message" lines. The errors grouped
+// by a multi-error are indented below it.
+func errorChain(err error) []string {
+	var lines []string
+	var walk func(err error, depth int)
+	walk = func(err error, depth int) {
+		indent := strings.Repeat("  ", depth)
+		for err != nil && len(lines) < maxChainLength {
+			var children []error
+			switch e := err.(type) {
+			case interface{ WrappedErrors() []error }:
+				children = e.WrappedErrors()
+			case interface{ Unwrap() []error }:
+				children = e.Unwrap()
+			default:
+				lines = append(lines, fmt.Sprintf("%s%T: %s", indent, err, strings.Join(strings.Fields(err.Error()), " ")))
+				err = errors.Unwrap(err)
+				continue
+			}
+
+			// A multi-error's message lists its errors, which follow on their own lines
+			lines = append(lines, fmt.Sprintf("%s%T: %d errors", indent, err, len(children)))
+			for _, child := range children {
+				walk(child, depth+1)
+			}
+			return
+		}
+	}
+	walk(err, 0)
+	return lines
+}
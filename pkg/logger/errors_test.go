@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// logError logs err through a logger with the given ErrorDetail setting and returns the
+// decoded JSON entry
+func logError(t *testing.T, errorDetail bool, err error) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	NewLogger(Config{Level: InfoLevel, Format: "json", Output: &buf, ErrorDetail: errorDetail}).
+		WithError(err).
+		Error("operation failed")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse log output: %v", err)
+	}
+	return entry
+}
+
+func TestErrorDetail_RendersChain(t *testing.T) {
+	err := fmt.Errorf("failed to save session: %w", fmt.Errorf("write failed: %w", os.ErrPermission))
+
+	entry := logError(t, true, err)
+
+	if entry["error"] != err.Error() {
+		t.Errorf("error = %v, want the message", entry["error"])
+	}
+	if entry[ErrorTypeFieldKey] != "*fmt.wrapError" {
+		t.Errorf("error_type = %v, want *fmt.wrapError", entry[ErrorTypeFieldKey])
+	}
+	want := "*fmt.wrapError: failed to save session: write failed: permission denied\n" +
+		"*fmt.wrapError: write failed: permission denied\n" +
+		"*errors.errorString: permission denied"
+	if entry[ErrorChainFieldKey] != want {
+		t.Errorf("error_chain = %q, want %q", entry[ErrorChainFieldKey], want)
+	}
+	if _, ok := entry[ErrorDetailFieldKey]; ok {
+		t.Errorf("error_detail = %q, want it only when %%+v adds to the message", entry[ErrorDetailFieldKey])
+	}
+}
+
+func TestErrorDetail_MultiError(t *testing.T) {
+	var err error
+	err = multierror.Append(err, errors.New("anthropic_api_key is required"))
+	err = multierror.Append(err, fmt.Errorf("invalid port: %w", errors.New("out of range")))
+	err = fmt.Errorf("validation failed: %w", err)
+
+	chain, _ := logError(t, true, err)[ErrorChainFieldKey].(string)
+
+	for _, want := range []string{
+		"*fmt.wrapError: validation failed: 2 errors occurred: * anthropic_api_key is required * invalid port: out of range\n",
+		"\n*multierror.Error: 2 errors\n",
+		"\n  *errors.errorString: anthropic_api_key is required\n",
+		"\n  *fmt.wrapError: invalid port: out of range\n",
+		"\n  *errors.errorString: out of range",
+	} {
+		if !strings.Contains(chain, want) {
+			t.Errorf("error_chain = %q, want it to contain %q", chain, want)
+		}
+	}
+}
+
+func TestErrorDetail_Disabled(t *testing.T) {
+	err := fmt.Errorf("failed to save session: %w", os.ErrPermission)
+
+	entry := logError(t, false, err)
+
+	if entry["error"] != err.Error() {
+		t.Errorf("error = %v, want the plain message", entry["error"])
+	}
+	for _, key := range []string{ErrorTypeFieldKey, ErrorChainFieldKey, ErrorDetailFieldKey} {
+		if _, ok := entry[key]; ok {
+			t.Errorf("%s set, want only the message without ErrorDetail", key)
+		}
+	}
+}
+
+// stackError formats with a stack trace under %+v, like github.com/pkg/errors
+type stackError struct{}
+
+func (stackError) Error() string { return "boom" }
+
+func (e stackError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		_, _ = fmt.Fprint(s, "boom\nmain.run\n\tmain.go:12")
+		return
+	}
+	_, _ = fmt.Fprint(s, e.Error())
+}
+
+func TestErrorDetail_FormatsStack(t *testing.T) {
+	entry := logError(t, true, stackError{})
+
+	if entry[ErrorDetailFieldKey] != "boom\nmain.run\n\tmain.go:12" {
+		t.Errorf("error_detail = %v, want the %%+v output", entry[ErrorDetailFieldKey])
+	}
+	if _, ok := entry[ErrorChainFieldKey]; ok {
+		t.Error("error_chain set for an error that wraps nothing")
+	}
+}
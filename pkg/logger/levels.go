@@ -1,6 +1,8 @@
 // Package logger provides structured logging utilities.
 package logger
 
+import "strings"
+
 // Level string constants
 const (
 	levelDebug = "debug"
@@ -54,3 +56,28 @@ func ParseLevel(levelStr string) Level {
 		return InfoLevel
 	}
 }
+
+// LookupLevel parses a level name such as "debug", ignoring case, reporting false for
+// anything that isn't a level rather than defaulting to info
+func LookupLevel(name string) (Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case levelDebug:
+		return DebugLevel, true
+	case levelInfo:
+		return InfoLevel, true
+	case levelWarn:
+		return WarnLevel, true
+	case levelError:
+		return ErrorLevel, true
+	default:
+		return InfoLevel, false
+	}
+}
+
+// LevelController is implemented by loggers whose level can be changed while the program
+// runs, e.g. to turn on debug logging in production without a restart. The level is shared
+// with every logger derived from it with WithFields and the like.
+type LevelController interface {
+	Level() Level
+	SetLevel(level Level)
+}
@@ -1,6 +1,10 @@
 package logger
 
-import "testing"
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
 
 func TestLevelString(t *testing.T) {
 	tests := []struct {
@@ -44,3 +48,52 @@ func TestParseLevel(t *testing.T) {
 		})
 	}
 }
+
+func TestLookupLevel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Level
+		ok       bool
+	}{
+		{"debug", DebugLevel, true},
+		{" WARN ", WarnLevel, true},
+		{"Error", ErrorLevel, true},
+		{"verbose", InfoLevel, false},
+		{"", InfoLevel, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := LookupLevel(tt.input)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("LookupLevel(%q) = %v, %v, want %v, %v", tt.input, got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Config{Level: InfoLevel, Output: &buf})
+	derived := log.WithFields(StringField("component", "test"))
+
+	derived.Debug("before")
+	if strings.Contains(buf.String(), "before") {
+		t.Fatalf("debug line logged at info level: %s", buf.String())
+	}
+
+	// Lowering the level on the root logger applies to loggers derived from it
+	controller, ok := log.(LevelController)
+	if !ok {
+		t.Fatal("logger doesn't implement LevelController")
+	}
+	controller.SetLevel(DebugLevel)
+	if got := derived.(LevelController).Level(); got != DebugLevel {
+		t.Errorf("derived Level() = %v, want debug", got)
+	}
+
+	derived.Debug("after")
+	if !strings.Contains(buf.String(), "after") {
+		t.Errorf("debug line not logged after lowering the level: %s", buf.String())
+	}
+}
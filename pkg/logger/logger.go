@@ -6,7 +6,6 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,10 +28,14 @@ type contextKey string
 
 const correlationIDContextKey contextKey = "correlation_id"
 
-// LogField represents a structured log field with concrete types
+// LogField represents a structured log field. Value is a string, bool, integer, float or
+// time.Duration: numbers and bools are logged as native JSON types, and durations as
+// seconds in JSON and "1.5s" in text output.
 type LogField struct {
 	Key   string
-	Value string
+	Value any
+
+	err error // The logged error, set by ErrorField for loggers with ErrorDetail
 }
 
 // Logger interface with simplified, focused methods
@@ -41,8 +44,13 @@ type Logger interface {
 	Error(msg string, fields ...LogField)
 	Debug(msg string, fields ...LogField)
 	Warn(msg string, fields ...LogField)
+	InfoCtx(ctx context.Context, msg string, fields ...LogField)
+	ErrorCtx(ctx context.Context, msg string, fields ...LogField)
+	DebugCtx(ctx context.Context, msg string, fields ...LogField)
+	WarnCtx(ctx context.Context, msg string, fields ...LogField)
 	WithFields(fields ...LogField) Logger
 	WithCorrelationID(id string) Logger
+	WithError(err error) Logger
 	GrpcRequestsInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error)
 	HTTPMiddleware(next http.Handler) http.Handler
 }
@@ -53,13 +61,23 @@ type Config struct {
 	Format  string
 	Service string
 	Output  io.Writer // Optional: defaults to os.Stdout if nil
+
+	// Redaction masks sensitive field values (tokens, API keys, emails); on by default
+	Redaction RedactionConfig
+
+	// ErrorDetail adds the type and full chain of errors logged with ErrorField, for debugging
+	// wrapped errors; otherwise only err.Error() is logged
+	ErrorDetail bool
 }
 
 // logger implements the Logger interface
 type logger struct {
-	logrus  *logrus.Logger
-	fields  []LogField
-	service string
+	logrus      *logrus.Logger
+	fields      []LogField
+	service     string
+	redactor    *redactor
+	errorDetail bool
+	text        bool // Text output, where durations are logged as strings
 }
 
 // NewLogger creates a new logger instance with the given configuration
@@ -80,17 +98,7 @@ func NewLogger(config Config) Logger {
 		logrusLogger.SetOutput(os.Stdout)
 	}
 
-	// Set level
-	switch config.Level {
-	case DebugLevel:
-		logrusLogger.SetLevel(logrus.DebugLevel)
-	case WarnLevel:
-		logrusLogger.SetLevel(logrus.WarnLevel)
-	case ErrorLevel:
-		logrusLogger.SetLevel(logrus.ErrorLevel)
-	default:
-		logrusLogger.SetLevel(logrus.InfoLevel)
-	}
+	logrusLogger.SetLevel(toLogrusLevel(config.Level))
 
 	// Add service field if provided
 	var serviceFields []LogField
@@ -99,12 +107,49 @@ func NewLogger(config Config) Logger {
 	}
 
 	return &logger{
-		logrus:  logrusLogger,
-		fields:  serviceFields,
-		service: config.Service,
+		logrus:      logrusLogger,
+		fields:      serviceFields,
+		service:     config.Service,
+		redactor:    newRedactor(config.Redaction),
+		errorDetail: config.ErrorDetail,
+		text:        config.Format == "text",
+	}
+}
+
+// toLogrusLevel maps a Level to logrus, treating unknown levels as info
+func toLogrusLevel(level Level) logrus.Level {
+	switch level {
+	case DebugLevel:
+		return logrus.DebugLevel
+	case WarnLevel:
+		return logrus.WarnLevel
+	case ErrorLevel:
+		return logrus.ErrorLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
+
+// Level returns the level the logger currently logs at
+func (l *logger) Level() Level {
+	switch l.logrus.GetLevel() {
+	case logrus.DebugLevel, logrus.TraceLevel:
+		return DebugLevel
+	case logrus.WarnLevel:
+		return WarnLevel
+	case logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel:
+		return ErrorLevel
+	default:
+		return InfoLevel
 	}
 }
 
+// SetLevel changes the level this logger, the logger it was derived from and every logger
+// derived from them log at. It's safe to call while logging.
+func (l *logger) SetLevel(level Level) {
+	l.logrus.SetLevel(toLogrusLevel(level))
+}
+
 // WithFields returns a new logger with additional fields (immutable)
 func (l *logger) WithFields(fields ...LogField) Logger {
 	newFields := make([]LogField, 0, len(l.fields)+len(fields))
@@ -112,9 +157,12 @@ func (l *logger) WithFields(fields ...LogField) Logger {
 	newFields = append(newFields, fields...)
 
 	return &logger{
-		logrus:  l.logrus,
-		fields:  newFields,
-		service: l.service,
+		logrus:      l.logrus,
+		fields:      newFields,
+		service:     l.service,
+		redactor:    l.redactor,
+		errorDetail: l.errorDetail,
+		text:        l.text,
 	}
 }
 
@@ -143,6 +191,35 @@ func (l *logger) Warn(msg string, fields ...LogField) {
 	l.log(logrus.WarnLevel, msg, fields...)
 }
 
+// InfoCtx logs an info message with the correlation ID from ctx and optional fields
+func (l *logger) InfoCtx(ctx context.Context, msg string, fields ...LogField) {
+	l.log(logrus.InfoLevel, msg, withContextFields(ctx, fields)...)
+}
+
+// ErrorCtx logs an error message with the correlation ID from ctx and optional fields
+func (l *logger) ErrorCtx(ctx context.Context, msg string, fields ...LogField) {
+	l.log(logrus.ErrorLevel, msg, withContextFields(ctx, fields)...)
+}
+
+// DebugCtx logs a debug message with the correlation ID from ctx and optional fields
+func (l *logger) DebugCtx(ctx context.Context, msg string, fields ...LogField) {
+	l.log(logrus.DebugLevel, msg, withContextFields(ctx, fields)...)
+}
+
+// WarnCtx logs a warning message with the correlation ID from ctx and optional fields
+func (l *logger) WarnCtx(ctx context.Context, msg string, fields ...LogField) {
+	l.log(logrus.WarnLevel, msg, withContextFields(ctx, fields)...)
+}
+
+// withContextFields prepends the fields carried by ctx (its correlation ID) to fields
+func withContextFields(ctx context.Context, fields []LogField) []LogField {
+	correlationID := GetCorrelationIDFromContext(ctx)
+	if correlationID == "" {
+		return fields
+	}
+	return append([]LogField{CorrelationIDField(correlationID)}, fields...)
+}
+
 // log is the internal logging method
 func (l *logger) log(level logrus.Level, msg string, fields ...LogField) {
 	// Combine existing fields with new fields
@@ -167,15 +244,32 @@ func (l *logger) log(level logrus.Level, msg string, fields ...LogField) {
 	}
 }
 
-// convertToLogrusFields converts LogField slice to logrus.Fields
+// convertToLogrusFields converts LogField slice to logrus.Fields, masking sensitive values
 func (l *logger) convertToLogrusFields(fields []LogField) logrus.Fields {
 	logrusFields := make(logrus.Fields, len(fields))
 	for _, field := range fields {
-		logrusFields[field.Key] = field.Value
+		logrusFields[field.Key] = l.encodeValue(l.redactor.redact(field.Key, field.Value))
+		if l.errorDetail && field.err != nil {
+			for _, detail := range errorDetailFields(field.err) {
+				logrusFields[detail.Key] = l.redactor.redact(detail.Key, detail.Value)
+			}
+		}
 	}
 	return logrusFields
 }
 
+// encodeValue returns the value logrus formats for a field value. Durations are logged as
+// seconds in JSON, so they can be compared and aggregated, and as strings in text output.
+func (l *logger) encodeValue(value any) any {
+	if d, ok := value.(time.Duration); ok {
+		if l.text {
+			return d.String()
+		}
+		return d.Seconds()
+	}
+	return value
+}
+
 // Helper functions for common field types
 
 // StringField returns a LogField for a string value.
@@ -185,17 +279,22 @@ func StringField(key, value string) LogField {
 
 // IntField returns a LogField for an integer value.
 func IntField(key string, value int) LogField {
-	return LogField{Key: key, Value: strconv.Itoa(value)}
+	return LogField{Key: key, Value: value}
 }
 
 // Int64Field returns a LogField for an int64 value.
 func Int64Field(key string, value int64) LogField {
-	return LogField{Key: key, Value: strconv.FormatInt(value, 10)}
+	return LogField{Key: key, Value: value}
+}
+
+// Float64Field returns a LogField for a float64 value.
+func Float64Field(key string, value float64) LogField {
+	return LogField{Key: key, Value: value}
 }
 
 // BoolField returns a LogField for a boolean value.
 func BoolField(key string, value bool) LogField {
-	return LogField{Key: key, Value: strconv.FormatBool(value)}
+	return LogField{Key: key, Value: value}
 }
 
 // Field creates a log field with automatic type conversion for less common types
@@ -203,33 +302,17 @@ func Field[T any](key string, value T) LogField {
 	return LogField{Key: key, Value: convertValue(value)}
 }
 
-// convertValue converts various types to string representation
-func convertValue(value any) string {
+// convertValue keeps strings, numbers, bools and durations as they are, and converts
+// other types to their string representation
+func convertValue(value any) any {
 	switch v := value.(type) {
-	case string:
+	case string, bool, time.Duration,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
 		return v
-	case int:
-		return strconv.Itoa(v)
-	case int64:
-		return strconv.FormatInt(v, 10)
-	case int32:
-		return strconv.FormatInt(int64(v), 10)
-	case uint:
-		return strconv.FormatUint(uint64(v), 10)
-	case uint64:
-		return strconv.FormatUint(v, 10)
-	case uint32:
-		return strconv.FormatUint(uint64(v), 10)
-	case float64:
-		return strconv.FormatFloat(v, 'f', -1, 64)
-	case float32:
-		return strconv.FormatFloat(float64(v), 'f', -1, 32)
-	case bool:
-		return strconv.FormatBool(v)
 	case time.Time:
 		return v.Format(time.RFC3339)
-	case time.Duration:
-		return v.String()
 	case error:
 		if v == nil {
 			return "<nil>"
@@ -243,7 +326,7 @@ func convertValue(value any) string {
 
 // DurationField returns a LogField for a time.Duration value.
 func DurationField(key string, value time.Duration) LogField {
-	return LogField{Key: key, Value: value.String()}
+	return LogField{Key: key, Value: value}
 }
 
 // TimeField returns a LogField for a time.Time value formatted as RFC3339.
@@ -256,7 +339,7 @@ func ErrorField(err error) LogField {
 	if err == nil {
 		return LogField{Key: "error", Value: "<nil>"}
 	}
-	return LogField{Key: "error", Value: err.Error()}
+	return LogField{Key: "error", Value: err.Error(), err: err}
 }
 
 // Common field constructors
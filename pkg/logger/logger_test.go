@@ -165,12 +165,12 @@ func TestFieldHelpers(t *testing.T) {
 		{
 			name:     "IntField",
 			field:    IntField("count", 42),
-			expected: LogField{Key: "count", Value: "42"},
+			expected: LogField{Key: "count", Value: 42},
 		},
 		{
 			name:     "DurationField",
 			field:    DurationField("duration", 5*time.Second),
-			expected: LogField{Key: "duration", Value: "5s"},
+			expected: LogField{Key: "duration", Value: 5 * time.Second},
 		},
 		{
 			name:     "CorrelationIDField",
@@ -190,7 +190,7 @@ func TestFieldHelpers(t *testing.T) {
 		{
 			name:     "HTTPStatusField",
 			field:    HTTPStatusField(200),
-			expected: LogField{Key: "http_status", Value: "200"},
+			expected: LogField{Key: "http_status", Value: 200},
 		},
 		{
 			name:     "ClientIPField",
@@ -205,7 +205,7 @@ func TestFieldHelpers(t *testing.T) {
 				t.Errorf("Expected key=%s, got %s", tt.expected.Key, tt.field.Key)
 			}
 			if tt.field.Value != tt.expected.Value {
-				t.Errorf("Expected value=%s, got %s", tt.expected.Value, tt.field.Value)
+				t.Errorf("Expected value=%v, got %v", tt.expected.Value, tt.field.Value)
 			}
 		})
 	}
@@ -221,7 +221,89 @@ func TestTimeField(t *testing.T) {
 		t.Errorf("Expected key=%s, got %s", expected.Key, field.Key)
 	}
 	if field.Value != expected.Value {
-		t.Errorf("Expected value=%s, got %s", expected.Value, field.Value)
+		t.Errorf("Expected value=%v, got %v", expected.Value, field.Value)
+	}
+}
+
+func TestLoggerTypedFields(t *testing.T) {
+	fields := []LogField{
+		HTTPStatusField(404),
+		IntField("response_bytes", 1024),
+		Int64Field("input_tokens", 1500),
+		Float64Field("temperature", 0.7),
+		BoolField("cached", true),
+		DurationField("duration", 1500*time.Millisecond),
+		Field("retries", uint8(2)),
+		StringField("http_method", "GET"),
+	}
+
+	// JSON output uses native types; durations are in seconds
+	var buf bytes.Buffer
+	NewLogger(Config{Level: InfoLevel, Format: "json", Output: &buf}).Info("request", fields...)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse log output: %v", err)
+	}
+	want := map[string]any{
+		"http_status":    float64(404),
+		"response_bytes": float64(1024),
+		"input_tokens":   float64(1500),
+		"temperature":    0.7,
+		"cached":         true,
+		"duration":       1.5,
+		"retries":        float64(2),
+		"http_method":    "GET",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("JSON %s = %#v, want %#v", key, entry[key], value)
+		}
+	}
+	if !strings.Contains(buf.String(), `"http_status":404`) {
+		t.Errorf("JSON output %s should have http_status as a number", buf.String())
+	}
+
+	// Text output stays readable
+	buf.Reset()
+	NewLogger(Config{Level: InfoLevel, Format: "text", Output: &buf}).Info("request", fields...)
+	for _, want := range []string{"http_status=404", "cached=true", "duration=1.5s", "temperature=0.7", "http_method=GET"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("text output %q should contain %q", buf.String(), want)
+		}
+	}
+}
+
+func TestLoggerCtxMethods(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Config{Level: DebugLevel, Format: "json", Output: &buf})
+	ctx := WithCorrelationIDContext(context.Background(), "turn-123")
+
+	log.InfoCtx(ctx, "info", StringField("user_id", "U1"))
+	log.WarnCtx(ctx, "warn")
+	log.ErrorCtx(ctx, "error")
+	log.DebugCtx(ctx, "debug")
+	log.InfoCtx(context.Background(), "no correlation ID")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d log lines, want 5", len(lines))
+	}
+	for i, line := range lines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to parse log output: %v", err)
+		}
+		want := "turn-123"
+		if i == 4 {
+			want = ""
+		}
+		if got, _ := entry[CorrelationIDFieldKey].(string); got != want {
+			t.Errorf("%s: correlation_id = %q, want %q", entry["msg"], got, want)
+		}
+		if i == 0 && entry["user_id"] != "U1" {
+			t.Errorf("user_id = %v, want the explicit fields too", entry["user_id"])
+		}
 	}
 }
 
@@ -415,7 +497,7 @@ func TestGrpcFieldHelpers(t *testing.T) {
 				t.Errorf("Expected key=%s, got %s", tt.expected.Key, tt.field.Key)
 			}
 			if tt.field.Value != tt.expected.Value {
-				t.Errorf("Expected value=%s, got %s", tt.expected.Value, tt.field.Value)
+				t.Errorf("Expected value=%v, got %v", tt.expected.Value, tt.field.Value)
 			}
 		})
 	}
@@ -502,12 +584,13 @@ func TestHTTPMiddleware(t *testing.T) {
 			t.Errorf("Expected msg='HTTP response sent', got %v", responseEntry["msg"])
 		}
 
-		if responseEntry["http_status"] != "200" {
-			t.Errorf("Expected http_status='200', got %v", responseEntry["http_status"])
+		// Numeric fields are JSON numbers
+		if responseEntry["http_status"] != float64(200) {
+			t.Errorf("Expected http_status=200, got %#v", responseEntry["http_status"])
 		}
 
-		if responseEntry["response_bytes"] != "13" {
-			t.Errorf("Expected response_bytes='13', got %v", responseEntry["response_bytes"])
+		if responseEntry["response_bytes"] != float64(13) {
+			t.Errorf("Expected response_bytes=13, got %#v", responseEntry["response_bytes"])
 		}
 
 		if responseEntry["duration"] == nil {
@@ -558,12 +641,12 @@ func TestHTTPMiddleware(t *testing.T) {
 			t.Fatalf("Failed to parse response log entry: %v", err)
 		}
 
-		if responseEntry["http_status"] != "500" {
-			t.Errorf("Expected http_status='500', got %v", responseEntry["http_status"])
+		if responseEntry["http_status"] != float64(500) {
+			t.Errorf("Expected http_status=500, got %#v", responseEntry["http_status"])
 		}
 
-		if responseEntry["response_bytes"] != "5" {
-			t.Errorf("Expected response_bytes='5', got %v", responseEntry["response_bytes"])
+		if responseEntry["response_bytes"] != float64(5) {
+			t.Errorf("Expected response_bytes=5, got %#v", responseEntry["response_bytes"])
 		}
 	})
 }
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// RedactedValue replaces masked field values and secret-looking substrings
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactedKeys are the field keys whose values are masked unless redaction is
+// disabled. A key also matches field keys ending in "_" plus the key, so "token" covers
+// "slack_bot_token". Matching ignores case.
+var DefaultRedactedKeys = []string{
+	"api_key", "apikey", "token", "bot_token", "app_token", "access_token", "refresh_token",
+	"password", "secret", "client_secret", "authorization", "cookie", "email",
+}
+
+// secretPatterns match values that look like credentials or personal data wherever they
+// appear, for RedactionConfig.ScanValues
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\bbearer\s+[a-z0-9._~+/=-]{8,}`),                        // Authorization headers
+	regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{16,}`),                                   // OpenAI and Anthropic API keys
+	regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`),                            // Slack bot and user tokens
+	regexp.MustCompile(`\bxapp-[A-Za-z0-9-]{10,}`),                                  // Slack app tokens
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{20,}`),                              // GitHub tokens
+	regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`),                                   // Google API keys
+	regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),                             // AWS access key IDs
+	regexp.MustCompile(`\b\d{8,10}:[A-Za-z0-9_-]{35}\b`),                            // Telegram bot tokens
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]+`), // JWTs
+	regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),            // Email addresses
+}
+
+// RedactionConfig controls masking of sensitive field values. The zero value masks
+// DefaultRedactedKeys.
+type RedactionConfig struct {
+	Disabled   bool     // Log values as given, e.g. in local development
+	Keys       []string // Field keys to mask in addition to DefaultRedactedKeys
+	ScanValues bool     // Also mask secret-looking substrings (API keys, tokens, emails) in every field
+}
+
+// Redact returns value as it would be logged under key with this configuration, for masking
+// data outside log entries the same way
+func (c RedactionConfig) Redact(key string, value any) any {
+	return newRedactor(c).redact(key, value)
+}
+
+// redactor masks sensitive field values. A nil *redactor leaves values unchanged.
+type redactor struct {
+	keys []string
+	scan bool
+}
+
+// newRedactor compiles cfg, returning nil if redaction is disabled
+func newRedactor(cfg RedactionConfig) *redactor {
+	if cfg.Disabled {
+		return nil
+	}
+	keys := make([]string, 0, len(DefaultRedactedKeys)+len(cfg.Keys))
+	for _, key := range slices.Concat(DefaultRedactedKeys, cfg.Keys) {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return &redactor{keys: keys, scan: cfg.ScanValues}
+}
+
+// redact returns the value to log for the field key
+func (r *redactor) redact(key string, value any) any {
+	if r == nil || value == "" {
+		return value
+	}
+	if r.sensitiveKey(key) {
+		return RedactedValue
+	}
+	if s, ok := value.(string); ok && r.scan {
+		for _, pattern := range secretPatterns {
+			s = pattern.ReplaceAllString(s, RedactedValue)
+		}
+		return s
+	}
+	return value
+}
+
+// sensitiveKey reports whether key is, or ends in "_" plus, one of the redacted keys
+func (r *redactor) sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range r.keys {
+		if key == k || strings.HasSuffix(key, "_"+k) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// AWSProvider reads secrets from AWS Secrets Manager: secret://NAME resolves to the
+// SecretString of the secret with that name or ARN, and secret://NAME#KEY to one field of a
+// secret holding a JSON object (the format the console uses for key/value secrets).
+type AWSProvider struct {
+	cfg      aws.Config
+	endpoint string
+	client   *http.Client
+	signer   *v4.Signer
+}
+
+// AWSOption configures optional AWSProvider behaviour
+type AWSOption func(*AWSProvider)
+
+// WithAWSEndpoint overrides the Secrets Manager endpoint, e.g. for a VPC endpoint or tests
+func WithAWSEndpoint(endpoint string) AWSOption {
+	return func(p *AWSProvider) {
+		p.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithAWSHTTPClient sets the HTTP client used for requests; cfg.HTTPClient or
+// http.DefaultClient by default
+func WithAWSHTTPClient(client *http.Client) AWSOption {
+	return func(p *AWSProvider) {
+		p.client = client
+	}
+}
+
+// NewAWSProvider creates a provider using the region and credentials in cfg, as loaded by
+// config.LoadDefaultConfig
+func NewAWSProvider(cfg aws.Config, opts ...AWSOption) *AWSProvider {
+	p := &AWSProvider{
+		cfg:      cfg,
+		endpoint: fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", cfg.Region),
+		client:   http.DefaultClient,
+		signer:   v4.NewSigner(),
+	}
+	if client, ok := cfg.HTTPClient.(*http.Client); ok {
+		p.client = client
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// GetSecret fetches the secret name with the GetSecretValue API
+func (p *AWSProvider) GetSecret(ctx context.Context, name, key string) (string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": name})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	if p.cfg.Credentials == nil {
+		return "", fmt.Errorf("no AWS credentials configured")
+	}
+	creds, err := p.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256(body)
+	if err := p.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "secretsmanager", p.cfg.Region, time.Now()); err != nil {
+		return "", fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("secrets manager request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read secrets manager response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		if strings.HasSuffix(apiErr.Type, "ResourceNotFoundException") {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, apiErr.Message)
+		}
+		return "", fmt.Errorf("secrets manager returned %d: %s %s", resp.StatusCode, apiErr.Type, apiErr.Message)
+	}
+
+	var out struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return "", fmt.Errorf("failed to decode secrets manager response")
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("secret has no string value (binary secrets aren't supported)")
+	}
+	if key != "" {
+		return jsonField(*out.SecretString, key)
+	}
+	return *out.SecretString, nil
+}
@@ -0,0 +1,23 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// EnvProvider reads secrets from environment variables: secret://NAME resolves to the
+// value of $NAME. It's the default provider and needs no setup.
+type EnvProvider struct{}
+
+// GetSecret returns the value of the environment variable name
+func (EnvProvider) GetSecret(_ context.Context, name, key string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("%w: environment variable %s is not set", ErrNotFound, name)
+	}
+	if key != "" {
+		return jsonField(value, key)
+	}
+	return value, nil
+}
@@ -0,0 +1,162 @@
+// Package secrets resolves secret://name references in configuration values to secrets
+// held in a secrets manager, so API keys and tokens needn't be set as plain environment
+// variables. Providers read them from the environment, AWS Secrets Manager or HashiCorp Vault.
+//
+// A reference is "secret://<name>" or "secret://<name>#<key>", where key selects one field
+// of a secret holding a JSON object (or one key of a Vault secret).
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Prefix marks a configuration value as a reference to a secret
+const Prefix = "secret://"
+
+// ErrNotFound is returned when a referenced secret (or key within it) doesn't exist
+var ErrNotFound = errors.New("secret not found")
+
+// Provider looks up secrets by name. key selects one field of the secret, and is empty when
+// the reference doesn't name one. Implementations must not include secret values in errors.
+type Provider interface {
+	GetSecret(ctx context.Context, name, key string) (string, error)
+}
+
+// IsReference reports whether value is a secret reference
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// parseReference splits a reference into the secret name and optional key
+func parseReference(value string) (name, key string, err error) {
+	name, key, _ = strings.Cut(strings.TrimPrefix(value, Prefix), "#")
+	if name == "" {
+		return "", "", fmt.Errorf("invalid secret reference %q: missing secret name", value)
+	}
+	return name, key, nil
+}
+
+// Resolve returns the secret value references, or value unchanged if it isn't a reference
+func Resolve(ctx context.Context, p Provider, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	name, key, err := parseReference(value)
+	if err != nil {
+		return "", err
+	}
+	secret, err := p.GetSecret(ctx, name, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", value, err)
+	}
+	return secret, nil
+}
+
+// ResolveAll replaces every secret reference in the string fields of the struct v points
+// to, including nested structs, string slices and maps, with the secret value. Other values
+// are left unchanged. It returns an error naming each field that couldn't be resolved.
+func ResolveAll(ctx context.Context, p Provider, v any) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("secrets: ResolveAll needs a pointer to a struct, got %T", v)
+	}
+	r := resolver{ctx: ctx, provider: p}
+	r.walk(val.Elem(), val.Elem().Type().Name())
+	return r.errs
+}
+
+// resolver walks a value, resolving the references it holds and collecting failures
+type resolver struct {
+	ctx      context.Context
+	provider Provider
+	errs     error
+}
+
+// walk resolves the references in val, which is addressable unless it's a map element.
+// It returns the resolved value for callers that have to store it back (map elements).
+func (r *resolver) walk(val reflect.Value, path string) reflect.Value {
+	switch val.Kind() {
+	case reflect.String:
+		if !IsReference(val.String()) {
+			return val
+		}
+		secret, err := Resolve(r.ctx, r.provider, val.String())
+		if err != nil {
+			r.errs = multierror.Append(r.errs, fmt.Errorf("%s: %w", path, err))
+			return val
+		}
+		out := reflect.New(val.Type()).Elem()
+		out.SetString(secret)
+		if val.CanSet() {
+			val.Set(out)
+		}
+		return out
+
+	case reflect.Struct:
+		if !val.CanSet() {
+			// Copy map elements so their fields can be set
+			cp := reflect.New(val.Type()).Elem()
+			cp.Set(val)
+			val = cp
+		}
+		for i := 0; i < val.NumField(); i++ {
+			if val.Type().Field(i).IsExported() {
+				r.walk(val.Field(i), path+"."+val.Type().Field(i).Name)
+			}
+		}
+		return val
+
+	case reflect.Ptr:
+		if !val.IsNil() {
+			r.walk(val.Elem(), path)
+		}
+		return val
+
+	case reflect.Slice:
+		for i := 0; i < val.Len(); i++ {
+			r.walk(val.Index(i), fmt.Sprintf("%s[%d]", path, i))
+		}
+		return val
+
+	case reflect.Map:
+		iter := val.MapRange()
+		for iter.Next() {
+			resolved := r.walk(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key()))
+			val.SetMapIndex(iter.Key(), resolved)
+		}
+		return val
+
+	default:
+		return val
+	}
+}
+
+// jsonField returns field key of a secret holding a JSON object
+func jsonField(secret, key string) (string, error) {
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		// Don't wrap the JSON error, which can quote part of the secret
+		return "", fmt.Errorf("secret isn't a JSON object, so key %q can't be read", key)
+	}
+	return stringField(fields, key)
+}
+
+// stringField returns fields[key] as a string
+func stringField(fields map[string]any, key string) (string, error) {
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("%w: no key %q", ErrNotFound, key)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("key %q isn't a string", key)
+	}
+	return s, nil
+}
@@ -0,0 +1,233 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// fakeProvider serves secrets from a map, keyed by name or "name#key"
+type fakeProvider map[string]string
+
+func (f fakeProvider) GetSecret(_ context.Context, name, key string) (string, error) {
+	if key != "" {
+		name += "#" + key
+	}
+	value, ok := f[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+type nestedConfig struct {
+	Token string
+}
+
+type testConfig struct {
+	APIKey    string
+	Model     string
+	Port      int
+	Nested    nestedConfig
+	Optional  *nestedConfig
+	Keys      []string
+	Headers   map[string]string
+	Servers   map[string]nestedConfig
+	unexposed string
+}
+
+func TestResolveAll(t *testing.T) {
+	provider := fakeProvider{
+		"anthropic-key":   "sk-ant-123",
+		"slack#bot_token": "xoxb-456",
+		"header-secret":   "Bearer abc",
+		"server-token":    "mcp-token",
+		"second-key":      "key-2",
+		"optional-token":  "opt-token",
+	}
+	cfg := testConfig{
+		APIKey:    "secret://anthropic-key",
+		Model:     "claude-sonnet-4-5",
+		Port:      8080,
+		Nested:    nestedConfig{Token: "secret://slack#bot_token"},
+		Optional:  &nestedConfig{Token: "secret://optional-token"},
+		Keys:      []string{"plain-key", "secret://second-key"},
+		Headers:   map[string]string{"Authorization": "secret://header-secret", "Accept": "application/json"},
+		Servers:   map[string]nestedConfig{"github": {Token: "secret://server-token"}},
+		unexposed: "secret://anthropic-key",
+	}
+
+	if err := ResolveAll(context.Background(), provider, &cfg); err != nil {
+		t.Fatalf("ResolveAll() error = %v", err)
+	}
+
+	want := testConfig{
+		APIKey:    "sk-ant-123",
+		Model:     "claude-sonnet-4-5",
+		Port:      8080,
+		Nested:    nestedConfig{Token: "xoxb-456"},
+		Optional:  &nestedConfig{Token: "opt-token"},
+		Keys:      []string{"plain-key", "key-2"},
+		Headers:   map[string]string{"Authorization": "Bearer abc", "Accept": "application/json"},
+		Servers:   map[string]nestedConfig{"github": {Token: "mcp-token"}},
+		unexposed: "secret://anthropic-key",
+	}
+	got, _ := json.Marshal(cfg)
+	wantJSON, _ := json.Marshal(want)
+	if string(got) != string(wantJSON) || *cfg.Optional != *want.Optional || cfg.unexposed != want.unexposed {
+		t.Errorf("ResolveAll() = %s\nwant %s", got, wantJSON)
+	}
+}
+
+func TestResolveAll_PlainValuesUnchanged(t *testing.T) {
+	cfg := testConfig{APIKey: "sk-plain", Model: "gpt-4o", Keys: []string{"a", "b"}}
+	want := cfg
+
+	if err := ResolveAll(context.Background(), fakeProvider{}, &cfg); err != nil {
+		t.Fatalf("ResolveAll() error = %v", err)
+	}
+	if cfg.APIKey != want.APIKey || cfg.Model != want.Model || strings.Join(cfg.Keys, ",") != "a,b" {
+		t.Errorf("ResolveAll() changed plain values: %+v", cfg)
+	}
+}
+
+func TestResolveAll_ReportsFailuresWithoutValues(t *testing.T) {
+	cfg := testConfig{
+		APIKey: "secret://missing",
+		Nested: nestedConfig{Token: "secret://"},
+		Model:  "secret://present",
+	}
+
+	err := ResolveAll(context.Background(), fakeProvider{"present": "hunter2"}, &cfg)
+
+	if err == nil {
+		t.Fatal("ResolveAll() error = nil, want errors for the unresolved references")
+	}
+	for _, want := range []string{"testConfig.APIKey", "secret://missing", "testConfig.Nested.Token"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("error %q leaks a secret value", err)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("error = %v, want ErrNotFound", err)
+	}
+	if cfg.Model != "hunter2" {
+		t.Errorf("Model = %q, want references that resolve to be resolved", cfg.Model)
+	}
+}
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("TEST_SECRET_TOKEN", "tok-123")
+	t.Setenv("TEST_SECRET_JSON", `{"user":"bot","password":"pw"}`)
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{"secret://TEST_SECRET_TOKEN", "tok-123", false},
+		{"secret://TEST_SECRET_JSON#password", "pw", false},
+		{"secret://TEST_SECRET_JSON#missing", "", true},
+		{"secret://TEST_SECRET_TOKEN#key", "", true},
+		{"secret://TEST_SECRET_UNSET", "", true},
+		{"plain value", "plain value", false},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(context.Background(), EnvProvider{}, tt.ref)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v; want %q, error %v", tt.ref, got, err, tt.want, tt.wantErr)
+		}
+		if err != nil && strings.Contains(err.Error(), "tok-123") {
+			t.Errorf("Resolve(%q) error %q leaks a secret value", tt.ref, err)
+		}
+	}
+}
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		if r.URL.Path != "/v1/kv/data/chatbot/slack" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"data":{"value":"default","bot_token":"xoxb-1"},"metadata":{"version":3}}}`))
+	}))
+	defer server.Close()
+
+	p, err := NewVaultProvider(VaultConfig{Address: server.URL + "/", Token: "vault-token", Mount: "kv"})
+	if err != nil {
+		t.Fatalf("NewVaultProvider() error = %v", err)
+	}
+
+	if got, err := Resolve(context.Background(), p, "secret://chatbot/slack#bot_token"); err != nil || got != "xoxb-1" {
+		t.Errorf("Resolve(#bot_token) = %q, %v; want xoxb-1", got, err)
+	}
+	if got, err := Resolve(context.Background(), p, "secret://chatbot/slack"); err != nil || got != "default" {
+		t.Errorf("Resolve() = %q, %v; want the value key", got, err)
+	}
+	if _, err := Resolve(context.Background(), p, "secret://chatbot/missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Resolve(missing) error = %v, want ErrNotFound", err)
+	}
+
+	denied, _ := NewVaultProvider(VaultConfig{Address: server.URL, Token: "wrong"})
+	if _, err := Resolve(context.Background(), denied, "secret://chatbot/slack"); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("Resolve() with a bad token error = %v, want the vault error", err)
+	}
+
+	if _, err := NewVaultProvider(VaultConfig{Token: "t"}); err == nil {
+		t.Error("NewVaultProvider() without an address should fail")
+	}
+}
+
+func TestAWSProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var req struct{ SecretId string }
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch req.SecretId {
+		case "prod/anthropic":
+			_, _ = w.Write([]byte(`{"Name":"prod/anthropic","SecretString":"sk-ant-aws"}`))
+		case "prod/slack":
+			_, _ = w.Write([]byte(`{"Name":"prod/slack","SecretString":"{\"bot_token\":\"xoxb-aws\"}"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`))
+		}
+	}))
+	defer server.Close()
+
+	cfg := aws.Config{
+		Region: "eu-west-2",
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+		}),
+	}
+	p := NewAWSProvider(cfg, WithAWSEndpoint(server.URL))
+
+	if got, err := Resolve(context.Background(), p, "secret://prod/anthropic"); err != nil || got != "sk-ant-aws" {
+		t.Errorf("Resolve() = %q, %v; want sk-ant-aws", got, err)
+	}
+	if got, err := Resolve(context.Background(), p, "secret://prod/slack#bot_token"); err != nil || got != "xoxb-aws" {
+		t.Errorf("Resolve(#bot_token) = %q, %v; want xoxb-aws", got, err)
+	}
+	if _, err := Resolve(context.Background(), p, "secret://prod/missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Resolve(missing) error = %v, want ErrNotFound", err)
+	}
+}
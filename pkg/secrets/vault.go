@@ -0,0 +1,96 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultVaultMount is the mount path of Vault's default KV secrets engine
+const DefaultVaultMount = "secret"
+
+// DefaultVaultKey is the key read from a Vault secret when the reference doesn't name one
+const DefaultVaultKey = "value"
+
+// VaultConfig configures a VaultProvider
+type VaultConfig struct {
+	Address    string       // Vault server address, e.g. "https://vault.example.com:8200"
+	Token      string       // Token used to authenticate
+	Mount      string       // Mount path of the KV version 2 engine; DefaultVaultMount if empty
+	HTTPClient *http.Client // Client used for requests; http.DefaultClient if nil
+}
+
+// VaultProvider reads secrets from a HashiCorp Vault KV version 2 engine:
+// secret://PATH#KEY resolves to KEY of the secret at PATH, and secret://PATH to its
+// DefaultVaultKey key.
+type VaultProvider struct {
+	cfg VaultConfig
+}
+
+// NewVaultProvider creates a provider for the Vault server in cfg
+func NewVaultProvider(cfg VaultConfig) (*VaultProvider, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("vault address is required")
+	}
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("vault token is required")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = DefaultVaultMount
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	cfg.Address = strings.TrimSuffix(cfg.Address, "/")
+	cfg.Mount = strings.Trim(cfg.Mount, "/")
+	return &VaultProvider{cfg: cfg}, nil
+}
+
+// GetSecret reads the latest version of the secret at path name
+func (p *VaultProvider) GetSecret(ctx context.Context, name, key string) (string, error) {
+	if key == "" {
+		key = DefaultVaultKey
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/%s/data/%s", p.cfg.Address, url.PathEscape(p.cfg.Mount), strings.TrimPrefix(name, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.cfg.Token)
+
+	resp, err := p.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", fmt.Errorf("%w: no secret at %s", ErrNotFound, name)
+	case resp.StatusCode != http.StatusOK:
+		var apiErr struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		return "", fmt.Errorf("vault returned %d: %s", resp.StatusCode, strings.Join(apiErr.Errors, "; "))
+	}
+
+	var out struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return "", fmt.Errorf("failed to decode vault response")
+	}
+	return stringField(out.Data.Data, key)
+}